@@ -4,13 +4,23 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/neoclaw-ai/neoclaw/internal/cli"
+	"github.com/neoclaw-ai/neoclaw/internal/crash"
 	"github.com/neoclaw-ai/neoclaw/internal/logging"
 )
 
 func main() {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if path := crash.Recovered("claw", recovered); path != "" {
+				fmt.Fprintf(os.Stderr, "claw crashed. Crash report: %s\n", path)
+			}
+			os.Exit(2)
+		}
+	}()
 	if err := cli.NewRootCmd().ExecuteContext(context.Background()); err != nil {
 		logging.Logger().Error("fatal error", "err", err)
 		os.Exit(1)
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/neoclaw-ai/neoclaw/internal/runtime"
@@ -13,14 +14,21 @@ import (
 	"github.com/neoclaw-ai/neoclaw/internal/approval"
 	"github.com/neoclaw-ai/neoclaw/internal/config"
 	"github.com/neoclaw-ai/neoclaw/internal/costs"
+	"github.com/neoclaw-ai/neoclaw/internal/jsonschema"
+	"github.com/neoclaw-ai/neoclaw/internal/language"
 	"github.com/neoclaw-ai/neoclaw/internal/logging"
 	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
 	"github.com/neoclaw-ai/neoclaw/internal/provider"
 	"github.com/neoclaw-ai/neoclaw/internal/session"
 	"github.com/neoclaw-ai/neoclaw/internal/tools"
 )
 
-const defaultRequestTimeout = 30 * time.Second
+const (
+	defaultRequestTimeout = 30 * time.Second
+	// quickAnswerMaxTokens keeps QuickAnswer replies short and fast.
+	quickAnswerMaxTokens = 512
+)
 
 // Agent implements the runtime Handler for one conversation.
 type Agent struct {
@@ -31,6 +39,9 @@ type Agent struct {
 	contextCfg        config.ContextConfig
 	maxIter           int
 	toolOutputLength  int
+	fullOutputs       *outputs.Store
+	workspaceDir      string
+	projects          map[string]config.ProjectConfig
 	maxContextTokens  int
 	recentMessages    int
 	history           []provider.ChatMessage
@@ -38,11 +49,34 @@ type Agent struct {
 	memoryStore       *memory.Store
 	requestTimeout    time.Duration
 	historyLoadedOnce bool
+	titleRequested    bool
 	costTracker       *costs.Tracker
 	costProvider      string
 	costModel         string
 	dailySpendLimit   float64
 	monthlySpendLimit float64
+	jsonResponse      bool
+	responseSchema    jsonschema.Schema
+	incognito         bool
+	incognitoHistory  []provider.ChatMessage
+	incognitoLastTurn *session.TurnReport
+	review            codeReview
+	postProcess       func(context.Context, string) string
+	forkParent        *session.Store
+	forkStart         int
+	// bridgeChannel, bridgeHome, and bridgeSessions let Attach move the
+	// conversation between channels' sessions; bridgeOwner names the
+	// channel whose session is current.
+	bridgeChannel  string
+	bridgeHome     *session.Store
+	bridgeSessions map[string]*session.Store
+	bridgeOwner    string
+	queueSummaries bool
+	languages      *language.Store
+	deletionLog    string
+	loadedTools    map[string]bool
+	usageMu        sync.Mutex
+	lastUsage      PromptUsage
 }
 
 // New creates a conversation-scoped Agent.
@@ -106,8 +140,22 @@ func (a *Agent) ConfigureCosts(
 	a.monthlySpendLimit = monthlyLimit
 }
 
+// ConfigureOutputs saves tool results longer than the inline limit to
+// store before they are truncated.
+func (a *Agent) ConfigureOutputs(store *outputs.Store) {
+	a.fullOutputs = store
+}
+
+// ConfigureMemoryWrites applies the privacy memory_writes mode to writes the
+// agent makes on its own. Outside auto mode, end-of-session daily log summaries
+// are queued for review instead of written, since nobody is there to approve
+// them one by one.
+func (a *Agent) ConfigureMemoryWrites(mode string) {
+	a.queueSummaries = mode == config.MemoryWritesApprove || mode == config.MemoryWritesQueue
+}
+
 // HandleMessage processes one inbound message and writes the assistant response.
-func (a *Agent) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+func (a *Agent) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) (err error) {
 	if w == nil {
 		return errors.New("response writer is required")
 	}
@@ -125,6 +173,8 @@ func (a *Agent) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg
 	if blocked {
 		return nil
 	}
+	ctx, recorder := withTurnRecorder(ctx, time.Now())
+	defer func() { a.saveTurnReport(recorder.finish(time.Now(), err)) }()
 
 	if err := a.ensureHistoryLoaded(ctx); err != nil {
 		return err
@@ -132,12 +182,46 @@ func (a *Agent) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg
 
 	// Rebuild system prompt on every request so memory, daily logs, and
 	// current time are always fresh.
-	systemPrompt, err := BuildSystemPrompt(a.agentDir, a.memoryStore, a.contextCfg)
+	disabledBlocks, err := a.DisabledPromptBlocks()
+	if err != nil {
+		return err
+	}
+	systemPrompt, blocks, err := buildSystemPromptBlocks(a.agentDir, a.memoryStore, time.Now(), a.contextCfg, disabledBlocks...)
+	if err != nil {
+		return err
+	}
+	withContacts := a.contactsPrompt(systemPrompt, msg.Text, disabledBlocks)
+	blocks = addBlockTokens(blocks, PromptBlockContacts, estimateTokens(withContacts[len(systemPrompt):], nil))
+	projectName, project, inProject, err := a.activeProjectConfig()
 	if err != nil {
 		return err
 	}
+	withProject, root := withContacts, a.workspaceDir
+	if inProject {
+		root = a.projectDir(project)
+		withProject = projectPrompt(withContacts, projectName, root, project)
+		blocks = addBlockTokens(blocks, BlockProject, estimateTokens(withProject[len(withContacts):], nil))
+		// Commands run in the project, and its allowed commands and
+		// trusted policy overlay apply for this turn only.
+		overlay, err := approval.LoadProjectOverlay(ctx, a.approver, root)
+		if err != nil {
+			logging.Logger().Warn("failed to load project policy overlay", "project", projectName, "err", err)
+		}
+		overlay = overlay.Merge(approval.Overlay{Commands: approval.Rules{Allow: project.AllowedCommands}})
+		ctx = tools.WithWorkdir(ctx, root)
+		ctx = approval.WithOverlay(ctx, overlay)
+	}
+	ctx = a.withCodeReview(ctx)
+	withWorkspace, err := a.workspacePrompt(ctx, withProject, root)
+	if err != nil {
+		return err
+	}
+	blocks = addBlockTokens(blocks, BlockWorkspace, estimateTokens(withWorkspace[len(withProject):], nil))
+	systemPrompt = a.languagePrompt(withWorkspace, msg)
+	format := a.turnResponseFormat(msg)
+	systemPrompt = a.incognitoPrompt(format.prompt(systemPrompt))
 
-	baseHistory := append([]provider.ChatMessage{}, a.history...)
+	baseHistory := a.turnHistory()
 	baseHistory, _ = sanitizeToolTurns(baseHistory)
 	messages := appendUserMessage(baseHistory, msg.Text)
 	uncompactedMessages := append([]provider.ChatMessage{}, messages...)
@@ -149,21 +233,41 @@ func (a *Agent) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg
 	// following tool_result messages). Re-sanitize after compaction so provider
 	// payloads never contain orphan tool_result blocks.
 	messages, _ = sanitizeToolTurns(messages)
+	registry := a.turnRegistry()
+	a.recordPromptUsage(blocks, systemPrompt, registry, messages)
+	onLLMResponse := func(usage provider.TokenUsage) error {
+		if err := a.recordUsage(ctx, usage); err != nil {
+			logging.Logger().Warn("failed to record llm usage", "err", err)
+		}
+		return nil
+	}
+	progress := progressReporter(a.contextCfg.ProgressUpdateAfter, w)
+	if format.json || a.postProcess != nil {
+		// The reply is rewritten before it is sent, so the raw text must not
+		// be shown first.
+		progress.Stream = nil
+	}
+	if format.json {
+		// Clients parse every message of a JSON turn, so plain-text
+		// "still working" updates must not reach them either.
+		progress.Send = nil
+	}
 	resp, history, err := Run(
 		ctx,
 		a.provider,
-		a.registry,
+		registry,
 		a.approver,
 		systemPrompt,
 		messages,
 		a.maxIter,
 		a.toolOutputLength,
-		func(usage provider.TokenUsage) error {
-			if err := a.recordUsage(ctx, usage); err != nil {
-				logging.Logger().Warn("failed to record llm usage", "err", err)
-			}
-			return nil
+		a.turnOutputs(),
+		TurnBudget{
+			MaxTokens:   a.contextCfg.MaxTurnTokens,
+			MaxDuration: a.contextCfg.MaxTurnDuration,
 		},
+		progress,
+		onLLMResponse,
 	)
 	if err != nil {
 		// Option 2 policy: return runtime/infrastructure errors so transports
@@ -173,6 +277,20 @@ func (a *Agent) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg
 	if resp == nil {
 		return fmt.Errorf("agent run returned nil response")
 	}
+	reply := resp.Content
+	if format.json {
+		reply, history, err = a.structuredReply(ctx, format.schema, systemPrompt, history, reply, onLLMResponse)
+		if err != nil {
+			return err
+		}
+	} else if a.postProcess != nil {
+		reply = a.postProcess(ctx, reply)
+	}
+
+	if a.incognito {
+		a.incognitoHistory = history
+		return w.WriteMessage(ctx, reply)
+	}
 
 	a.history = history
 	if sameMessageSlice(messages, uncompactedMessages) {
@@ -183,61 +301,172 @@ func (a *Agent) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg
 	if err != nil {
 		return err
 	}
-	if err := w.WriteMessage(ctx, resp.Content); err != nil {
+	a.titleSessionAsync(ctx, history)
+	if err := w.WriteMessage(ctx, reply); err != nil {
 		return err
 	}
 	return nil
 }
 
+// Complete runs one standalone turn with the agent's tools and cost tracking
+// but without reading or writing session history. Workflows use it so their
+// steps do not clutter the conversation.
+func (a *Agent) Complete(ctx context.Context, text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", errors.New("prompt is required")
+	}
+	limit, err := a.spendLimitMessage(ctx, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if limit != "" {
+		return "", errors.New(limit)
+	}
+
+	systemPrompt, err := BuildSystemPrompt(a.agentDir, a.memoryStore, a.contextCfg)
+	if err != nil {
+		return "", err
+	}
+	resp, _, err := Run(
+		a.withCodeReview(ctx),
+		a.provider,
+		a.registry,
+		a.approver,
+		systemPrompt,
+		appendUserMessage(nil, text),
+		a.maxIter,
+		a.toolOutputLength,
+		a.turnOutputs(),
+		TurnBudget{
+			MaxTokens:   a.contextCfg.MaxTurnTokens,
+			MaxDuration: a.contextCfg.MaxTurnDuration,
+		},
+		ProgressReporter{},
+		func(usage provider.TokenUsage) error {
+			if err := a.recordUsage(ctx, usage); err != nil {
+				logging.Logger().Warn("failed to record llm usage", "err", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	if resp == nil {
+		return "", fmt.Errorf("agent run returned nil response")
+	}
+	return resp.Content, nil
+}
+
+// QuickAnswer answers text in one model call, without tools or session
+// history, for replies that must come back within seconds such as Telegram
+// inline queries. The system prompt still carries the profile and memory.
+func (a *Agent) QuickAnswer(ctx context.Context, text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", errors.New("prompt is required")
+	}
+	limit, err := a.spendLimitMessage(ctx, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if limit != "" {
+		return "", errors.New(limit)
+	}
+
+	systemPrompt, err := BuildSystemPrompt(a.agentDir, a.memoryStore, a.contextCfg)
+	if err != nil {
+		return "", err
+	}
+	timeout := a.requestTimeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := a.provider.Chat(reqCtx, provider.ChatRequest{
+		SystemPrompt: systemPrompt + "\n\n" + quickAnswerInstruction,
+		Messages:     appendUserMessage(nil, text),
+		MaxTokens:    quickAnswerMaxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp == nil {
+		return "", errors.New("quick answer response is nil")
+	}
+	if err := a.recordUsage(reqCtx, resp.Usage); err != nil {
+		logging.Logger().Warn("failed to record quick answer usage", "err", err)
+	}
+	answer := strings.TrimSpace(resp.Content)
+	if answer == "" {
+		return "", errors.New("quick answer is empty")
+	}
+	return answer, nil
+}
+
 func (a *Agent) enforceSpendLimits(ctx context.Context, w runtime.ResponseWriter, now time.Time) (bool, error) {
+	message, err := a.spendLimitMessage(ctx, now)
+	if err != nil || message == "" {
+		return false, err
+	}
+	if err := w.WriteMessage(ctx, message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// spendLimitMessage returns a user-facing message when a spend limit has been
+// reached, or "" when the turn may proceed.
+func (a *Agent) spendLimitMessage(ctx context.Context, now time.Time) (string, error) {
 	if a.costTracker == nil {
-		return false, nil
+		return "", nil
 	}
 	if a.dailySpendLimit <= 0 && a.monthlySpendLimit <= 0 {
-		return false, nil
+		return "", nil
 	}
 
 	spend, err := a.costTracker.Spend(ctx, now)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
 	if a.dailySpendLimit > 0 && spend.TodayUSD >= a.dailySpendLimit {
-		if err := w.WriteMessage(ctx, fmt.Sprintf("Daily spend limit reached: $%.4f / $%.4f", spend.TodayUSD, a.dailySpendLimit)); err != nil {
-			return false, err
-		}
-		return true, nil
+		return fmt.Sprintf("Daily spend limit reached: $%.4f / $%.4f", spend.TodayUSD, a.dailySpendLimit), nil
 	}
 	if a.monthlySpendLimit > 0 && spend.MonthUSD >= a.monthlySpendLimit {
-		if err := w.WriteMessage(ctx, fmt.Sprintf("Monthly spend limit reached: $%.4f / $%.4f", spend.MonthUSD, a.monthlySpendLimit)); err != nil {
-			return false, err
-		}
-		return true, nil
+		return fmt.Sprintf("Monthly spend limit reached: $%.4f / $%.4f", spend.MonthUSD, a.monthlySpendLimit), nil
 	}
-	return false, nil
+	return "", nil
 }
 
 func (a *Agent) recordUsage(ctx context.Context, usage provider.TokenUsage) error {
-	if a.costTracker == nil {
-		return nil
-	}
+	return a.recordModelUsage(ctx, a.costProvider, a.costModel, usage)
+}
 
+// recordModelUsage records usage of a model other than the conversation's
+// own, such as the code review model.
+func (a *Agent) recordModelUsage(ctx context.Context, providerName, model string, usage provider.TokenUsage) error {
 	costUSD := 0.0
 	if usage.CostUSD != nil {
 		costUSD = *usage.CostUSD
 	} else if estimated, ok := costs.EstimateUSD(
-		a.costProvider,
-		a.costModel,
+		providerName,
+		model,
 		usage.InputTokens,
 		usage.OutputTokens,
 	); ok {
 		costUSD = estimated
 	}
+	turnRecorderFrom(ctx).modelCall(usage, costUSD)
+	if a.costTracker == nil {
+		return nil
+	}
 
 	return a.costTracker.Append(ctx, costs.Record{
 		Timestamp:    time.Now(),
-		Provider:     a.costProvider,
-		Model:        a.costModel,
+		Provider:     providerName,
+		Model:        model,
 		InputTokens:  usage.InputTokens,
 		OutputTokens: usage.OutputTokens,
 		TotalTokens:  usage.TotalTokens,
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -139,6 +140,150 @@ func TestAgentWithSessionLoadsHistoryAndAppendsTurn(t *testing.T) {
 	}
 }
 
+func TestAgentCompleteLeavesSessionUntouched(t *testing.T) {
+	modelProvider := &recordingProvider{
+		responses: []*provider.ChatResponse{{Content: "weekly summary"}},
+	}
+	sessionPath := filepath.Join(t.TempDir(), "sessions", "cli", "default.jsonl")
+	sessionStore := session.New(sessionPath)
+	ag := NewWithSession(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), sessionStore, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+
+	out, err := ag.Complete(context.Background(), "summarize my week")
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if out != "weekly summary" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if len(modelProvider.requests) != 1 || len(modelProvider.requests[0].Messages) != 1 {
+		t.Fatalf("expected one request with only the prompt, got %#v", modelProvider.requests)
+	}
+	loaded, err := sessionStore.Load(context.Background())
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if len(loaded) != 0 {
+		t.Fatalf("expected session untouched, got %#v", loaded)
+	}
+}
+
+func TestAgentQuickAnswerSendsNoTools(t *testing.T) {
+	modelProvider := &recordingProvider{
+		responses: []*provider.ChatResponse{{Content: "  Oslo is 1h ahead of London.  "}},
+	}
+	registry := tools.NewRegistry()
+	if err := registry.Register(fakeTool{name: "read_file", out: "unused"}); err != nil {
+		t.Fatalf("register tool: %v", err)
+	}
+	sessionStore := session.New(filepath.Join(t.TempDir(), "sessions", "telegram", "telegram-1.jsonl"))
+	ag := NewWithSession(modelProvider, registry, noopApprover{}, makeAgentDir(t), sessionStore, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+
+	out, err := ag.QuickAnswer(context.Background(), "time difference oslo london")
+	if err != nil {
+		t.Fatalf("quick answer: %v", err)
+	}
+	if out != "Oslo is 1h ahead of London." {
+		t.Fatalf("unexpected answer %q", out)
+	}
+	req := modelProvider.requests[0]
+	if len(req.Tools) != 0 || len(req.Messages) != 1 || req.MaxTokens != quickAnswerMaxTokens {
+		t.Fatalf("expected a single tool-free request, got %#v", req)
+	}
+	if !strings.Contains(req.SystemPrompt, quickAnswerInstruction) {
+		t.Fatalf("expected the quick answer instruction in the system prompt")
+	}
+	if loaded, err := sessionStore.Load(context.Background()); err != nil || len(loaded) != 0 {
+		t.Fatalf("expected session untouched, got %#v (%v)", loaded, err)
+	}
+}
+
+func TestAgentGeneratesSessionTitleAfterThreeTurns(t *testing.T) {
+	registry := tools.NewRegistry()
+	modelProvider := &recordingProvider{
+		responses: []*provider.ChatResponse{
+			{Content: "third answer"},
+			{Content: "\"Weekend hiking plans.\"\nextra"},
+		},
+	}
+	sessionStore := session.New(filepath.Join(t.TempDir(), "sessions", "cli", "default.jsonl"))
+	if err := sessionStore.Append(context.Background(), []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "first"},
+		{Role: provider.RoleAssistant, Content: "first answer"},
+		{Role: provider.RoleUser, Content: "second"},
+		{Role: provider.RoleAssistant, Content: "second answer"},
+	}); err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+
+	ag := NewWithSession(modelProvider, registry, noopApprover{}, makeAgentDir(t), sessionStore, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+	if err := ag.HandleMessage(context.Background(), &captureWriter{}, &runtime.Message{Text: "third"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+
+	waitFor(t, time.Second, func() bool {
+		title, err := sessionStore.Title()
+		return err == nil && title != ""
+	})
+	title, err := sessionStore.Title()
+	if err != nil {
+		t.Fatalf("read title: %v", err)
+	}
+	if title != "Weekend hiking plans" {
+		t.Fatalf("unexpected session title %q", title)
+	}
+}
+
+// titleGateProvider holds session title requests until release is closed.
+type titleGateProvider struct {
+	*recordingProvider
+	release chan struct{}
+}
+
+func (p titleGateProvider) Chat(ctx context.Context, req provider.ChatRequest) (*provider.ChatResponse, error) {
+	if req.SystemPrompt == sessionTitlePrompt {
+		<-p.release
+	}
+	return p.recordingProvider.Chat(ctx, req)
+}
+
+func TestAgentSessionTitleStaysWithItsSessionAfterFork(t *testing.T) {
+	modelProvider := titleGateProvider{
+		recordingProvider: &recordingProvider{responses: []*provider.ChatResponse{
+			{Content: "third answer"},
+			{Content: "Weekend hiking plans"},
+		}},
+		release: make(chan struct{}),
+	}
+	dir := filepath.Join(t.TempDir(), "sessions", "cli")
+	sessionStore := session.New(filepath.Join(dir, "default.jsonl"))
+	if err := sessionStore.Append(context.Background(), []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "first"},
+		{Role: provider.RoleAssistant, Content: "first answer"},
+		{Role: provider.RoleUser, Content: "second"},
+		{Role: provider.RoleAssistant, Content: "second answer"},
+	}); err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+
+	ag := NewWithSession(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), sessionStore, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+	if err := ag.HandleMessage(context.Background(), &captureWriter{}, &runtime.Message{Text: "third"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	// Fork while the title is still being generated.
+	if _, err := ag.Fork(context.Background(), "side"); err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	close(modelProvider.release)
+
+	waitFor(t, time.Second, func() bool {
+		title, err := sessionStore.Title()
+		return err == nil && title == "Weekend hiking plans"
+	})
+	if title, err := session.New(filepath.Join(dir, "side.jsonl")).Title(); err != nil || title == "Weekend hiking plans" {
+		t.Fatalf("expected the fork to keep its own title, got %q (%v)", title, err)
+	}
+}
+
 func TestAgentResetResetsSession(t *testing.T) {
 	registry := tools.NewRegistry()
 	modelProvider := &recordingProvider{
@@ -633,7 +778,11 @@ func (w *captureWriter) WriteMessage(_ context.Context, text string) error {
 	return nil
 }
 
+// recordingProvider records requests and replays responses. Background
+// calls such as session titles reach it from other goroutines, so tests that
+// race them read requests through request.
 type recordingProvider struct {
+	mu                 sync.Mutex
 	requests           []provider.ChatRequest
 	responses          []*provider.ChatResponse
 	err                error
@@ -642,6 +791,8 @@ type recordingProvider struct {
 }
 
 func (p *recordingProvider) Chat(ctx context.Context, req provider.ChatRequest) (*provider.ChatResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.requests = append(p.requests, req)
 	if p.requireLiveContext && ctx.Err() != nil {
 		return nil, ctx.Err()
@@ -664,6 +815,13 @@ func (p *recordingProvider) Chat(ctx context.Context, req provider.ChatRequest)
 	return resp, nil
 }
 
+// request returns the i-th recorded request.
+func (p *recordingProvider) request(i int) provider.ChatRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests[i]
+}
+
 type truncatingTool struct{}
 
 func (truncatingTool) Name() string        { return "truncating_tool" }
@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+)
+
+// ConfigureBridge names the channel this agent talks on and the sessions of
+// other channels that Attach can continue. The agent is recorded as
+// attached to its own session until it attaches elsewhere or Detach is
+// called.
+func (a *Agent) ConfigureBridge(channel string, sessions map[string]*session.Store) {
+	a.bridgeChannel = channel
+	a.bridgeSessions = sessions
+	a.bridgeOwner = channel
+	a.bridgeHome = a.sessionStore
+	if a.sessionStore != nil {
+		if err := a.sessionStore.Attach(channel); err != nil {
+			logging.Logger().Warn("failed to record session attachment", "channel", channel, "err", err)
+		}
+	}
+}
+
+// Attach continues the conversation of another channel's session here, so
+// both channels share one conversation. Attaching to this agent's own
+// channel returns to its own session.
+func (a *Agent) Attach(ctx context.Context, channel string) error {
+	if a.bridgeHome == nil {
+		return errors.New("bridging is unavailable")
+	}
+	if a.incognito {
+		return errors.New("cannot attach in incognito mode")
+	}
+	if a.forkParent != nil {
+		return fmt.Errorf("in fork %s; merge it back first", a.sessionStore.Name())
+	}
+	target := a.bridgeHome
+	if channel != a.bridgeChannel {
+		var ok bool
+		if target, ok = a.bridgeSessions[channel]; !ok {
+			return fmt.Errorf("unknown channel %q (available: %s)", channel, strings.Join(a.BridgeChannels(), ", "))
+		}
+	}
+	if target == a.sessionStore {
+		return nil
+	}
+
+	a.Detach()
+	if err := target.Attach(a.bridgeChannel); err != nil {
+		return err
+	}
+	a.sessionStore = target
+	a.bridgeOwner = channel
+	a.history = nil
+	a.historyLoadedOnce = false
+	a.titleRequested = false
+	a.loadedTools = nil
+	return a.ensureHistoryLoaded(ctx)
+}
+
+// Detach removes this agent from the current session's attachments. Call
+// it when the channel shuts down.
+func (a *Agent) Detach() {
+	if a.bridgeHome == nil || a.sessionStore == nil {
+		return
+	}
+	if err := a.sessionStore.Detach(a.bridgeChannel); err != nil {
+		logging.Logger().Warn("failed to remove session attachment", "channel", a.bridgeChannel, "err", err)
+	}
+}
+
+// BridgeChannels lists the channels Attach accepts, sorted.
+func (a *Agent) BridgeChannels() []string {
+	channels := []string{a.bridgeChannel}
+	for channel := range a.bridgeSessions {
+		channels = append(channels, channel)
+	}
+	slices.Sort(channels)
+	return channels
+}
+
+// Where returns the channel whose session this conversation is, and the
+// channels attached to it, oldest first. This agent's entry is marked Here.
+func (a *Agent) Where() (owner string, attached []session.Attachment, err error) {
+	if a.bridgeHome == nil {
+		return "", nil, errors.New("bridging is unavailable")
+	}
+	if a.forkParent != nil {
+		// Forks are private to the channel that made them.
+		return a.bridgeOwner + " (fork " + a.sessionStore.Name() + ")", nil, nil
+	}
+	attached, err = a.sessionStore.Attached()
+	if err != nil {
+		return "", nil, err
+	}
+	for i := range attached {
+		attached[i].Here = attached[i].Channel == a.bridgeChannel && attached[i].PID == os.Getpid()
+	}
+	return a.bridgeOwner, attached, nil
+}
@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestAttachContinuesAnotherChannelsSession(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cliStore := session.New(filepath.Join(dir, "cli", "default.jsonl"))
+	telegramStore := session.New(filepath.Join(dir, "telegram", "default.jsonl"))
+	if err := telegramStore.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "draft the release notes"},
+		{Role: provider.RoleAssistant, Content: "here is a draft"},
+	}); err != nil {
+		t.Fatalf("seed telegram session: %v", err)
+	}
+	modelProvider := &recordingProvider{
+		responses: []*provider.ChatResponse{{Content: "shortened"}, {Content: "done"}},
+	}
+	ag := NewWithSession(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), cliStore, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+	ag.ConfigureBridge("cli", map[string]*session.Store{"telegram": telegramStore})
+	defer ag.Detach()
+
+	if err := ag.Attach(ctx, "slack"); err == nil {
+		t.Fatalf("expected unknown channel to be refused")
+	}
+	if err := ag.Attach(ctx, "telegram"); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	owner, attached, err := ag.Where()
+	if err != nil || owner != "telegram" || len(attached) != 1 || attached[0].Channel != "cli" || !attached[0].Here {
+		t.Fatalf("unexpected where %q %#v (%v)", owner, attached, err)
+	}
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "make it shorter"}); err != nil {
+		t.Fatalf("handle attached turn: %v", err)
+	}
+	if got := len(modelProvider.request(0).Messages); got != 3 {
+		t.Fatalf("expected the telegram history to be continued, got %d messages", got)
+	}
+
+	// The telegram channel adds a turn from another process.
+	other := session.New(filepath.Join(dir, "telegram", "default.jsonl"))
+	if err := other.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "add a thank-you line"},
+		{Role: provider.RoleAssistant, Content: "added"},
+	}); err != nil {
+		t.Fatalf("append from the other channel: %v", err)
+	}
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "send it"}); err != nil {
+		t.Fatalf("handle second turn: %v", err)
+	}
+	if got := len(modelProvider.request(1).Messages); got != 7 {
+		t.Fatalf("expected the other channel's turn to be picked up, got %d messages", got)
+	}
+
+	if err := ag.Attach(ctx, "cli"); err != nil {
+		t.Fatalf("attach back: %v", err)
+	}
+	if attached, err := telegramStore.Attached(); err != nil || len(attached) != 0 {
+		t.Fatalf("expected the telegram session to be left, got %#v (%v)", attached, err)
+	}
+}
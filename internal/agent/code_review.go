@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+const (
+	// maxCodeReviewInputChars caps the code sent for review; larger writes
+	// are reviewed from their start and the prompt says so.
+	maxCodeReviewInputChars = 24000
+	// maxCodeReviewFindingsChars keeps findings short enough for an
+	// approval prompt on chat channels.
+	maxCodeReviewFindingsChars = 1200
+	// codeReviewClean is the reply the review model gives when it finds
+	// nothing.
+	codeReviewClean = "NO_ISSUES"
+)
+
+// codeReview is the model that checks generated code before write approvals.
+type codeReview struct {
+	provider     provider.Provider
+	providerName string
+	model        string
+}
+
+// ConfigureCodeReview makes write_file and apply_patch ask for approval
+// after reviewer has checked the code against a security checklist.
+// providerName and model attribute the review calls in the cost log.
+func (a *Agent) ConfigureCodeReview(reviewer provider.Provider, providerName, model string) {
+	a.review = codeReview{provider: reviewer, providerName: providerName, model: model}
+}
+
+// withCodeReview adds the configured reviewer to ctx, if there is one.
+func (a *Agent) withCodeReview(ctx context.Context) context.Context {
+	if a.review.provider == nil {
+		return ctx
+	}
+	return approval.WithCodeReview(ctx, a.reviewCode)
+}
+
+// reviewCode implements approval.CodeReviewer with one call to the review
+// model.
+func (a *Agent) reviewCode(ctx context.Context, toolName string, args map[string]any) (string, error) {
+	code, err := codeForReview(toolName, args)
+	if err != nil {
+		return "", err
+	}
+	timeout := a.requestTimeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := a.review.provider.Chat(reqCtx, provider.ChatRequest{
+		SystemPrompt: codeReviewPrompt,
+		Messages: []provider.ChatMessage{
+			{
+				Role:    provider.RoleUser,
+				Content: code,
+			},
+		},
+		MaxTokens: 400,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp == nil {
+		return "", errors.New("review response is nil")
+	}
+	if err := a.recordModelUsage(reqCtx, a.review.providerName, a.review.model, resp.Usage); err != nil {
+		logging.Logger().Warn("failed to record code review usage", "err", err)
+	}
+	return cleanCodeReview(resp.Content), nil
+}
+
+// codeForReview formats the code a write_file or apply_patch call carries.
+func codeForReview(toolName string, args map[string]any) (string, error) {
+	var header, code string
+	switch toolName {
+	case "write_file":
+		path, _ := args["path"].(string)
+		code, _ = args["content"].(string)
+		header = "New contents of " + path
+	case "apply_patch":
+		code, _ = args["patch"].(string)
+		header = "Unified diff"
+	default:
+		return "", fmt.Errorf("no code to review for %s", toolName)
+	}
+	code, truncated := truncateStringByChars(code, maxCodeReviewInputChars)
+	if truncated {
+		header += " (truncated; only the start is shown)"
+	}
+	return header + ":\n\n" + code, nil
+}
+
+// cleanCodeReview returns the findings in raw, or "" when the model found
+// nothing.
+func cleanCodeReview(raw string) string {
+	findings := strings.TrimSpace(raw)
+	if findings == "" || strings.EqualFold(strings.Trim(findings, " .`*"), codeReviewClean) {
+		return ""
+	}
+	if trimmed, truncated := truncateStringByChars(findings, maxCodeReviewFindingsChars); truncated {
+		findings = strings.TrimSpace(trimmed) + "…"
+	}
+	return findings
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+func TestReviewCodeSendsCodeToReviewModel(t *testing.T) {
+	reviewer := &recordingProvider{responses: []*provider.ChatResponse{
+		{Content: "- writes the API key to config.py\n"},
+		{Content: "NO_ISSUES."},
+	}}
+	a := New(&recordingProvider{}, nil, noopApprover{}, t.TempDir(), nil, config.ContextConfig{})
+	a.ConfigureCodeReview(reviewer, "openrouter", "cheap-model")
+
+	findings, err := a.reviewCode(context.Background(), "write_file", map[string]any{"path": "config.py", "content": "KEY = 'sk-1'"})
+	if err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if findings != "- writes the API key to config.py" {
+		t.Fatalf("unexpected findings %q", findings)
+	}
+	req := reviewer.requests[0]
+	if req.SystemPrompt != codeReviewPrompt || req.Messages[0].Content != "New contents of config.py:\n\nKEY = 'sk-1'" {
+		t.Fatalf("unexpected review request %#v", req)
+	}
+
+	findings, err = a.reviewCode(context.Background(), "apply_patch", map[string]any{"patch": "--- a\n+++ b\n"})
+	if err != nil || findings != "" {
+		t.Fatalf("expected a clean review, got %q, %v", findings, err)
+	}
+	if !strings.HasPrefix(reviewer.requests[1].Messages[0].Content, "Unified diff:") {
+		t.Fatalf("unexpected patch request %q", reviewer.requests[1].Messages[0].Content)
+	}
+}
+
+func TestCodeForReviewTruncatesLargeWrites(t *testing.T) {
+	code, err := codeForReview("write_file", map[string]any{"path": "big.txt", "content": strings.Repeat("x", maxCodeReviewInputChars+10)})
+	if err != nil {
+		t.Fatalf("code for review: %v", err)
+	}
+	if !strings.Contains(code, "(truncated; only the start is shown)") {
+		t.Fatalf("expected the truncation note, got %q", code[:80])
+	}
+	if _, err := codeForReview("read_file", nil); err == nil {
+		t.Fatal("expected an error for a tool without code")
+	}
+}
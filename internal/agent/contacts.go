@@ -0,0 +1,36 @@
+package agent
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+)
+
+// maxPromptContacts caps how many mentioned contacts are added to one turn.
+const maxPromptContacts = 5
+
+// contactsPrompt appends what memory holds about the people mentioned in the
+// user's message, so contact details only cost tokens when they are relevant.
+func (a *Agent) contactsPrompt(systemPrompt, text string, disabled []string) string {
+	if a.memoryStore == nil || slices.Contains(disabled, PromptBlockContacts) {
+		return systemPrompt
+	}
+	mentioned := memory.MentionedContacts(a.memoryStore.Contacts(time.Now()), text)
+	if len(mentioned) == 0 {
+		return systemPrompt
+	}
+	if len(mentioned) > maxPromptContacts {
+		mentioned = mentioned[:maxPromptContacts]
+	}
+	var b strings.Builder
+	b.WriteString(systemPrompt)
+	b.WriteString("\n\n[Contacts mentioned in this message]\n")
+	for _, contact := range mentioned {
+		b.WriteString("- ")
+		b.WriteString(contact.Format())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestContactsPromptAddsMentionedContactsOnly(t *testing.T) {
+	memoryStore := mustNewMemoryStore(t, t.TempDir())
+	for _, entry := range []memory.LogEntry{
+		{Tags: []string{"contact"}, Text: "Allergic to peanuts", KV: "name=Anna relationship=sister"},
+		{Tags: []string{"contact"}, Text: "Plays chess", KV: "name=Marco relationship=friend"},
+	} {
+		if err := memoryStore.AppendMemory(entry); err != nil {
+			t.Fatalf("append memory: %v", err)
+		}
+	}
+	ag := New(&recordingProvider{}, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), memoryStore, config.ContextConfig{})
+
+	prompt := ag.contactsPrompt("base", "Dinner ideas for Anna?", nil)
+	if !strings.Contains(prompt, "- Anna (sister): Allergic to peanuts") {
+		t.Fatalf("expected Anna in prompt, got %q", prompt)
+	}
+	if strings.Contains(prompt, "Marco") {
+		t.Fatalf("expected unmentioned contact to be left out, got %q", prompt)
+	}
+	if got := ag.contactsPrompt("base", "Dinner ideas for Anna?", []string{PromptBlockContacts}); got != "base" {
+		t.Fatalf("expected disabled contacts block to leave prompt unchanged, got %q", got)
+	}
+	if got := ag.contactsPrompt("base", "what's the weather", nil); got != "base" {
+		t.Fatalf("expected no contacts block, got %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+const (
+	correctionKind = "correction"
+	correctionTag  = "correction"
+	// correctionQuoteLength bounds how much of the corrected answer is quoted
+	// in the memory fact.
+	correctionQuoteLength = 120
+)
+
+// Correct records a user correction of the previous assistant answer. The
+// correction is saved as a memory fact tagged "correction" that quotes the
+// answer it corrects, and a note is added to the conversation so the rest of
+// the session sees it too. Text may start with "<topic>:" to file the fact
+// under a topic; a later correction with the same topic replaces it. It
+// returns the topic the fact was saved under.
+func (a *Agent) Correct(ctx context.Context, text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("correction text is required")
+	}
+	if a.incognito {
+		return "", errors.New("corrections are not saved in incognito mode")
+	}
+	if a.memoryStore == nil {
+		return "", errors.New("memory is unavailable")
+	}
+	if err := a.ensureHistoryLoaded(ctx); err != nil {
+		return "", err
+	}
+	answer, ok := lastAssistantAnswer(a.history)
+	if !ok {
+		return "", errors.New("there is no previous answer to correct")
+	}
+
+	topic, text := correctionTopic(text, time.Now())
+	quote, truncated := truncateStringByChars(strings.Join(strings.Fields(answer), " "), correctionQuoteLength)
+	if truncated {
+		quote += "..."
+	}
+	entry := memory.LogEntry{
+		Tags: []string{topic, correctionTag},
+		Text: fmt.Sprintf("%s (corrects earlier answer: %q)", text, quote),
+	}
+	if err := a.memoryStore.AppendMemory(entry); err != nil {
+		return "", err
+	}
+
+	base := append([]provider.ChatMessage{}, a.history...)
+	history := append(append([]provider.ChatMessage{}, a.history...),
+		provider.ChatMessage{Role: provider.RoleUser, Kind: correctionKind, Content: "Correction to your previous answer: " + text},
+		provider.ChatMessage{Role: provider.RoleAssistant, Kind: correctionKind, Content: "Noted. I saved this correction to memory and will not repeat the earlier claim."},
+	)
+	if err := a.appendSessionDelta(ctx, base, history); err != nil {
+		return "", err
+	}
+	a.history = history
+	return memory.NormalizeTags([]string{topic})[0], nil
+}
+
+func lastAssistantAnswer(history []provider.ChatMessage) (string, bool) {
+	for i := len(history) - 1; i >= 0; i-- {
+		msg := history[i]
+		if msg.Role == provider.RoleAssistant && msg.Kind == "" && strings.TrimSpace(msg.Content) != "" {
+			return strings.TrimSpace(msg.Content), true
+		}
+	}
+	return "", false
+}
+
+// correctionTopic splits an optional "<topic>:" prefix off text. Without one
+// the topic is unique so corrections never supersede each other.
+func correctionTopic(text string, now time.Time) (string, string) {
+	if prefix, rest, ok := strings.Cut(text, ":"); ok {
+		prefix = strings.TrimSpace(prefix)
+		rest = strings.TrimSpace(rest)
+		if prefix != "" && rest != "" && !strings.ContainsAny(prefix, " \t/") {
+			return prefix, rest
+		}
+	}
+	return correctionTag + "_" + strconv.FormatInt(now.Unix(), 10), text
+}
@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestCorrectSavesFactLinkedToPreviousAnswer(t *testing.T) {
+	ctx := context.Background()
+	memoryStore := mustNewMemoryStore(t, t.TempDir())
+	sessionStore := session.New(filepath.Join(t.TempDir(), "sessions", "cli", "default.jsonl"))
+	if err := sessionStore.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "what does my NAS run?"},
+		{Role: provider.RoleAssistant, Content: "Your NAS runs Unraid."},
+	}); err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+	ag := NewWithSession(&recordingProvider{}, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), sessionStore, memoryStore, 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+
+	topic, err := ag.Correct(ctx, "NAS: it runs TrueNAS, not Unraid")
+	if err != nil {
+		t.Fatalf("correct: %v", err)
+	}
+	if topic != "nas" {
+		t.Fatalf("expected topic nas, got %q", topic)
+	}
+	facts := memoryStore.ActiveFacts(time.Now())
+	if len(facts) != 1 {
+		t.Fatalf("expected one fact, got %#v", facts)
+	}
+	fact := facts[0]
+	if strings.Join(fact.Tags, ",") != "nas,correction" {
+		t.Fatalf("unexpected tags: %#v", fact.Tags)
+	}
+	if !strings.HasPrefix(fact.Text, "it runs TrueNAS, not Unraid") || !strings.Contains(fact.Text, `"Your NAS runs Unraid."`) {
+		t.Fatalf("expected fact to quote the corrected answer, got %q", fact.Text)
+	}
+
+	loaded, err := sessionStore.Load(ctx)
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if len(loaded) != 4 || loaded[2].Kind != correctionKind || !strings.Contains(loaded[2].Content, "TrueNAS") {
+		t.Fatalf("expected correction persisted to the session, got %#v", loaded)
+	}
+}
+
+func TestCorrectWithoutTopicDoesNotSupersedeEarlierCorrections(t *testing.T) {
+	topic, text := correctionTopic("the backup runs at 2am", time.Unix(1700000000, 0))
+	if topic != "correction_1700000000" || text != "the backup runs at 2am" {
+		t.Fatalf("unexpected split: %q %q", topic, text)
+	}
+	topic, text = correctionTopic("the router is at 10.0.0.1: not .254", time.Unix(1700000000, 0))
+	if topic != "correction_1700000000" || text != "the router is at 10.0.0.1: not .254" {
+		t.Fatalf("expected a prefix with spaces not to be a topic, got %q %q", topic, text)
+	}
+}
+
+func TestCorrectRequiresPreviousAnswer(t *testing.T) {
+	ag := New(&recordingProvider{}, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), mustNewMemoryStore(t, t.TempDir()), config.ContextConfig{})
+	if _, err := ag.Correct(context.Background(), "wrong"); err == nil || !strings.Contains(err.Error(), "no previous answer") {
+		t.Fatalf("expected missing answer error, got %v", err)
+	}
+	ag.history = []provider.ChatMessage{{Role: provider.RoleAssistant, Content: "answer"}}
+	ag.SetIncognito(true)
+	if _, err := ag.Correct(context.Background(), "wrong"); err == nil {
+		t.Fatalf("expected incognito corrections to be refused")
+	}
+}
@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"context"
+	"errors"
+
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+)
+
+// ConfigureDeletionLog sets the audit log that DeleteLast records removals in.
+func (a *Agent) ConfigureDeletionLog(path string) {
+	a.deletionLog = path
+}
+
+// DeleteLast permanently removes the last user message and everything that
+// followed it from the session, for when that message should never have been
+// saved. Unlike Rewind nothing is archived; only the removal itself is
+// recorded in the deletion log. It returns the number of messages removed.
+func (a *Agent) DeleteLast(ctx context.Context) (int, error) {
+	if a.sessionStore == nil {
+		return 0, errors.New("sessions are unavailable")
+	}
+	if a.incognito {
+		return 0, errors.New("nothing is saved in incognito mode")
+	}
+	if a.deletionLog == "" {
+		return 0, errors.New("deletion log is not configured")
+	}
+	// Deletion indexes refer to the session file, which can differ from the
+	// sanitized history held in memory.
+	messages, err := a.sessionStore.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	indexes := session.LastExchange(messages)
+	if len(indexes) == 0 {
+		return 0, errors.New("nothing to delete")
+	}
+	removed, err := a.sessionStore.Delete(ctx, indexes, a.deletionLog, "/delete-last")
+	if err != nil {
+		return 0, err
+	}
+	history, _ := sanitizeToolTurns(messages[:indexes[0]])
+	a.history = history
+	a.historyLoadedOnce = true
+	if a.forkStart > len(history) {
+		a.forkStart = len(history)
+	}
+	return len(removed), nil
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestDeleteLastRemovesExchangeFromSessionAndPrompt(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := session.New(filepath.Join(dir, "cli", "default.jsonl"))
+	if err := store.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "log in to the router"},
+		{Role: provider.RoleAssistant, Content: "what is the password?"},
+		{Role: provider.RoleUser, Content: "hunter2"},
+		{Role: provider.RoleAssistant, Content: "logged in"},
+	}); err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{{Content: "ok"}}}
+	ag := NewWithSession(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), store, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+	if _, err := ag.DeleteLast(ctx); err == nil {
+		t.Fatalf("expected delete without a deletion log to fail")
+	}
+	ag.ConfigureDeletionLog(filepath.Join(dir, "logs", "session_deletions.jsonl"))
+
+	removed, err := ag.DeleteLast(ctx)
+	if err != nil {
+		t.Fatalf("delete last: %v", err)
+	}
+	if removed != 2 {
+		t.Fatalf("expected 2 messages removed, got %d", removed)
+	}
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "thanks"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	for _, msg := range modelProvider.requests[0].Messages {
+		if strings.Contains(msg.Content, "hunter2") {
+			t.Fatalf("deleted message was sent to the model: %#v", modelProvider.requests[0].Messages)
+		}
+	}
+}
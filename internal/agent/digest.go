@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+// RefreshDigests writes a weekly digest for the last completed week (Monday
+// to Sunday) and a monthly digest for the last completed month when they are
+// missing. Periods without daily log entries are skipped, so the model is only
+// called once per period. It returns the kinds written.
+func RefreshDigests(ctx context.Context, modelProvider provider.Provider, memoryStore *memory.Store, now time.Time) ([]string, error) {
+	if modelProvider == nil {
+		return nil, errors.New("provider is required")
+	}
+	if memoryStore == nil {
+		return nil, errors.New("memory store is required")
+	}
+
+	var written []string
+	for _, kind := range []string{memory.DigestWeekly, memory.DigestMonthly} {
+		from, to := digestPeriod(kind, now)
+		latest, ok, err := memoryStore.LatestDigest(kind)
+		if err != nil {
+			return written, err
+		}
+		if ok && !latest.To.Before(to) {
+			continue
+		}
+
+		var dates []time.Time
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			dates = append(dates, day)
+		}
+		entries := memoryStore.DailyLogsForPrompt(dates)
+		if len(entries) == 0 {
+			continue
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "[Daily log — %s to %s]\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		b.WriteString("date\ttags\ttext\tkv\n")
+		for _, entry := range entries {
+			b.WriteString(entry.Timestamp.In(time.Local).Format("2006-01-02"))
+			b.WriteByte('\t')
+			b.WriteString(entry.FormatLLM())
+			b.WriteByte('\n')
+		}
+		resp, err := modelProvider.Chat(ctx, provider.ChatRequest{
+			SystemPrompt: digestPrompt,
+			Messages: []provider.ChatMessage{
+				{Role: provider.RoleUser, Content: b.String()},
+			},
+		})
+		if err != nil {
+			return written, fmt.Errorf("%s digest: %w", kind, err)
+		}
+		if resp == nil || strings.TrimSpace(resp.Content) == "" {
+			return written, fmt.Errorf("%s digest: response is empty", kind)
+		}
+		if err := memoryStore.SaveDigest(memory.Digest{Kind: kind, From: from, To: to, Text: resp.Content}); err != nil {
+			return written, err
+		}
+		written = append(written, kind)
+	}
+	return written, nil
+}
+
+// digestPeriod returns the first and last local days of the last completed
+// week or month before now.
+func digestPeriod(kind string, now time.Time) (from, to time.Time) {
+	local := now.In(time.Local)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+	if kind == memory.DigestMonthly {
+		firstOfMonth := today.AddDate(0, 0, 1-today.Day())
+		return firstOfMonth.AddDate(0, -1, 0), firstOfMonth.AddDate(0, 0, -1)
+	}
+	// Days since Monday, with Sunday as the last day of the week.
+	sinceMonday := (int(today.Weekday()) + 6) % 7
+	monday := today.AddDate(0, 0, -sinceMonday)
+	return monday.AddDate(0, 0, -7), monday.AddDate(0, 0, -1)
+}
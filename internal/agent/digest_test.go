@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+func TestRefreshDigestsWritesEachPeriodOnceAndReplacesOldLogsInPrompt(t *testing.T) {
+	agentDir := makeAgentDir(t)
+	store := mustNewMemoryStore(t, t.TempDir())
+	// Wednesday: the last full week is Feb 23 – Mar 1, the last full month February.
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.Local)
+	for _, entry := range []memory.LogEntry{
+		{Timestamp: time.Date(2026, 2, 10, 9, 0, 0, 0, time.Local), Tags: []string{"work"}, Text: "Started the migration"},
+		{Timestamp: time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local), Tags: []string{"work"}, Text: "Finished the migration"},
+		{Timestamp: time.Date(2026, 3, 3, 9, 0, 0, 0, time.Local), Tags: []string{"health"}, Text: "Went running"},
+	} {
+		if err := store.AppendDailyLog(entry); err != nil {
+			t.Fatalf("append daily log: %v", err)
+		}
+	}
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{
+		{Content: "Finished the database migration."},
+		{Content: "Ran the database migration from start to finish."},
+	}}
+
+	written, err := RefreshDigests(context.Background(), modelProvider, store, now)
+	if err != nil {
+		t.Fatalf("refresh digests: %v", err)
+	}
+	if strings.Join(written, ",") != "weekly,monthly" {
+		t.Fatalf("expected weekly and monthly digests, got %v", written)
+	}
+	weeklyInput := modelProvider.requests[0].Messages[0].Content
+	if !strings.Contains(weeklyInput, "Finished the migration") || strings.Contains(weeklyInput, "Started the migration") || strings.Contains(weeklyInput, "Went running") {
+		t.Fatalf("expected only last week's entries in the weekly request, got %q", weeklyInput)
+	}
+
+	written, err = RefreshDigests(context.Background(), modelProvider, store, now)
+	if err != nil || len(written) != 0 || len(modelProvider.requests) != 2 {
+		t.Fatalf("expected up-to-date digests to skip the model, got %v err=%v calls=%d", written, err, len(modelProvider.requests))
+	}
+
+	got, err := buildSystemPromptAt(agentDir, store, now, config.ContextConfig{DailyLogLookbackDays: 4})
+	if err != nil {
+		t.Fatalf("build system prompt: %v", err)
+	}
+	if !strings.Contains(got, "[Weekly digest — 2026-02-23 to 2026-03-01]\nFinished the database migration.") {
+		t.Fatalf("expected weekly digest block, got %q", got)
+	}
+	if !strings.Contains(got, "[Monthly digest — 2026-02-01 to 2026-02-28]") {
+		t.Fatalf("expected monthly digest block, got %q", got)
+	}
+	if strings.Contains(got, "[Daily log — 2026-03-01]") || !strings.Contains(got, "[Daily log — 2026-03-03]") {
+		t.Fatalf("expected digested days to replace raw logs, got %q", got)
+	}
+	if strings.Contains(got, "[Persistent facts]") {
+		t.Fatalf("expected digest facts to stay out of the facts block, got %q", got)
+	}
+}
@@ -0,0 +1,144 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+const forkKind = "fork"
+
+// Fork copies the current session into a new session called name and
+// continues the conversation there, leaving the original untouched. An empty
+// name picks a timestamped one. Forks do not nest; MergeSummary returns to
+// the original session. It returns the fork's session name.
+func (a *Agent) Fork(ctx context.Context, name string) (string, error) {
+	if a.sessionStore == nil {
+		return "", errors.New("sessions are unavailable")
+	}
+	if a.incognito {
+		return "", errors.New("cannot fork in incognito mode")
+	}
+	if a.forkParent != nil {
+		return "", fmt.Errorf("already in fork %s; merge it back first", a.sessionStore.Name())
+	}
+	if err := a.ensureHistoryLoaded(ctx); err != nil {
+		return "", err
+	}
+	if name == "" {
+		name = "fork-" + time.Now().Format("20060102-150405")
+	}
+	fork, err := a.sessionStore.Fork(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	a.forkParent = a.sessionStore
+	a.forkStart = len(a.history)
+	a.sessionStore = fork
+	return fork.Name(), nil
+}
+
+// ForkName returns the active fork's session name, or "" when not in a fork.
+func (a *Agent) ForkName() string {
+	if a.forkParent == nil {
+		return ""
+	}
+	return a.sessionStore.Name()
+}
+
+// MergeSummary summarizes what happened in the active fork, switches back to
+// the session it was forked from, and adds the summary there. The fork's own
+// session file is kept. It returns the fork name and the summary, which is
+// empty when nothing was discussed in the fork.
+func (a *Agent) MergeSummary(ctx context.Context) (string, string, error) {
+	if a.forkParent == nil {
+		return "", "", errors.New("not in a fork; use /fork first")
+	}
+	if err := a.ensureHistoryLoaded(ctx); err != nil {
+		return "", "", err
+	}
+	explored := a.history
+	if a.forkStart <= len(explored) {
+		explored = explored[a.forkStart:]
+	}
+
+	var summary string
+	if countUserTurns(explored) > 0 {
+		var err error
+		summary, err = a.summarizeFork(ctx, explored)
+		if err != nil {
+			return "", "", fmt.Errorf("summarize fork: %w", err)
+		}
+	}
+
+	name := a.sessionStore.Name()
+	a.sessionStore = a.forkParent
+	a.forkParent = nil
+	a.forkStart = 0
+	a.history = nil
+	a.historyLoadedOnce = false
+	if err := a.ensureHistoryLoaded(ctx); err != nil {
+		return "", "", err
+	}
+	if summary == "" {
+		return name, "", nil
+	}
+
+	history := append(append([]provider.ChatMessage{}, a.history...),
+		provider.ChatMessage{Role: provider.RoleUser, Kind: forkKind, Content: fmt.Sprintf("Summary of what we explored in fork %s:\n%s", name, summary)},
+		provider.ChatMessage{Role: provider.RoleAssistant, Kind: forkKind, Content: "Noted. I'll take the fork's findings into account."},
+	)
+	if err := a.appendSessionDelta(ctx, a.history, history); err != nil {
+		return "", "", err
+	}
+	a.history = history
+	return name, summary, nil
+}
+
+func (a *Agent) summarizeFork(ctx context.Context, messages []provider.ChatMessage) (string, error) {
+	timeout := a.requestTimeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := a.provider.Chat(reqCtx, provider.ChatRequest{
+		SystemPrompt: forkSummaryPrompt,
+		Messages: []provider.ChatMessage{
+			{
+				Role:    provider.RoleUser,
+				Content: buildSummaryTranscript(messages),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp == nil {
+		return "", errors.New("summary response is nil")
+	}
+	if err := a.recordUsage(reqCtx, resp.Usage); err != nil {
+		logging.Logger().Warn("failed to record fork summary usage", "err", err)
+	}
+	summary := strings.TrimSpace(resp.Content)
+	if summary == "" {
+		return "", errors.New("summary is empty")
+	}
+	return summary, nil
+}
+
+func countUserTurns(messages []provider.ChatMessage) int {
+	turns := 0
+	for _, msg := range messages {
+		if msg.Role == provider.RoleUser && msg.Kind == "" {
+			turns++
+		}
+	}
+	return turns
+}
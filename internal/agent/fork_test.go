@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestForkKeepsMainSessionAndMergesSummary(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	mainStore := session.New(filepath.Join(dir, "cli", "default.jsonl"))
+	if err := mainStore.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "plan the migration"},
+		{Role: provider.RoleAssistant, Content: "use postgres"},
+	}); err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+	modelProvider := &recordingProvider{
+		responses: []*provider.ChatResponse{{Content: "sqlite would work"}, {Content: "SQLite is enough for one user."}},
+	}
+	ag := NewWithSession(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), mainStore, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+
+	name, err := ag.Fork(ctx, "try-sqlite")
+	if err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	if name != "try-sqlite" || ag.ForkName() != "try-sqlite" {
+		t.Fatalf("unexpected fork name %q / %q", name, ag.ForkName())
+	}
+	if _, err := ag.Fork(ctx, "nested"); err == nil {
+		t.Fatalf("expected nested fork to be refused")
+	}
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "what about sqlite?"}); err != nil {
+		t.Fatalf("handle fork turn: %v", err)
+	}
+	if got := len(modelProvider.requests[0].Messages); got != 3 {
+		t.Fatalf("expected fork turn to see the main history, got %d messages", got)
+	}
+
+	forkName, summary, err := ag.MergeSummary(ctx)
+	if err != nil {
+		t.Fatalf("merge summary: %v", err)
+	}
+	if forkName != "try-sqlite" || summary != "SQLite is enough for one user." || ag.ForkName() != "" {
+		t.Fatalf("unexpected merge result %q %q", forkName, summary)
+	}
+	if transcript := modelProvider.requests[1].Messages[0].Content; strings.Contains(transcript, "plan the migration") || !strings.Contains(transcript, "what about sqlite?") {
+		t.Fatalf("expected only fork turns to be summarized, got %q", transcript)
+	}
+
+	loaded, err := mainStore.Load(ctx)
+	if err != nil {
+		t.Fatalf("load main session: %v", err)
+	}
+	if len(loaded) != 4 || loaded[2].Kind != forkKind || !strings.Contains(loaded[2].Content, "SQLite is enough") {
+		t.Fatalf("expected main session to gain only the summary, got %#v", loaded)
+	}
+	forked, err := session.New(filepath.Join(dir, "cli", "try-sqlite.jsonl")).Load(ctx)
+	if err != nil {
+		t.Fatalf("load fork session: %v", err)
+	}
+	if len(forked) != 4 {
+		t.Fatalf("expected fork session to be kept, got %#v", forked)
+	}
+}
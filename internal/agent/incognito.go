@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+const incognitoInstruction = "Incognito mode is on: nothing from this exchange will be saved. Memory and daily log tools are unavailable; do not offer to remember anything."
+
+// SetIncognito switches incognito mode. Incognito turns continue from the
+// saved conversation but are kept only in memory: they are not written to
+// the session file, titled, summarized into the daily log, given tools
+// that write memory, or have long tool results saved for /outputs. Turning incognito off discards those turns.
+func (a *Agent) SetIncognito(on bool) {
+	a.incognito = on
+	a.incognitoHistory = nil
+	a.incognitoLastTurn = nil
+}
+
+// Incognito reports whether incognito mode is on.
+func (a *Agent) Incognito() bool {
+	return a.incognito
+}
+
+// turnHistory returns the history the next turn builds on.
+func (a *Agent) turnHistory() []provider.ChatMessage {
+	if a.incognito && a.incognitoHistory != nil {
+		return append([]provider.ChatMessage{}, a.incognitoHistory...)
+	}
+	return append([]provider.ChatMessage{}, a.history...)
+}
+
+// turnRegistry returns the tools available to the next turn.
+func (a *Agent) turnRegistry() *tools.Registry {
+	registry := a.registry
+	if registry == nil {
+		return nil
+	}
+	if a.incognito {
+		registry = registry.Filter(func(tool tools.Tool) bool {
+			_, writes := tool.(tools.MemoryWriter)
+			return !writes
+		})
+	}
+	if a.contextCfg.LazyTools {
+		registry = a.lazyRegistry(registry)
+	}
+	return registry
+}
+
+// turnOutputs returns where the next turn saves full tool results, or nil
+// when it must not save them.
+func (a *Agent) turnOutputs() *outputs.Store {
+	if a.incognito {
+		return nil
+	}
+	return a.fullOutputs
+}
+
+func (a *Agent) incognitoPrompt(systemPrompt string) string {
+	if !a.incognito {
+		return systemPrompt
+	}
+	return systemPrompt + "\n\n" + incognitoInstruction
+}
@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestAgentIncognitoTurnsAreNotPersisted(t *testing.T) {
+	ctx := context.Background()
+	memoryStore := mustNewMemoryStore(t, t.TempDir())
+	registry := tools.NewRegistry()
+	for _, tool := range []tools.Tool{tools.MemoryAppendTool{Store: memoryStore}, tools.SearchLogsTool{Store: memoryStore}} {
+		if err := registry.Register(tool); err != nil {
+			t.Fatalf("register %s: %v", tool.Name(), err)
+		}
+	}
+	modelProvider := &recordingProvider{
+		responses: []*provider.ChatResponse{{Content: "secret reply"}, {Content: "follow-up"}, {Content: "normal reply"}},
+	}
+	sessionStore := session.New(filepath.Join(t.TempDir(), "sessions", "cli", "default.jsonl"))
+	if err := sessionStore.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "saved question"},
+		{Role: provider.RoleAssistant, Content: "saved answer"},
+	}); err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+	ag := NewWithSession(modelProvider, registry, noopApprover{}, makeAgentDir(t), sessionStore, memoryStore, 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+	writer := &captureWriter{}
+
+	ag.SetIncognito(true)
+	for _, text := range []string{"private question", "and another"} {
+		if err := ag.HandleMessage(ctx, writer, &runtime.Message{Text: text}); err != nil {
+			t.Fatalf("handle %q: %v", text, err)
+		}
+	}
+
+	first := modelProvider.requests[0]
+	if len(first.Messages) != 3 {
+		t.Fatalf("expected incognito turn to see saved history, got %d messages", len(first.Messages))
+	}
+	if len(first.Tools) != 1 || first.Tools[0].Name != "search_logs" {
+		t.Fatalf("expected memory-writing tools to be hidden, got %#v", first.Tools)
+	}
+	if got := len(modelProvider.requests[1].Messages); got != 5 {
+		t.Fatalf("expected second incognito turn to include the first, got %d messages", got)
+	}
+	loaded, err := sessionStore.Load(ctx)
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected session file untouched, got %#v", loaded)
+	}
+
+	ag.SetIncognito(false)
+	if err := ag.HandleMessage(ctx, writer, &runtime.Message{Text: "back to normal"}); err != nil {
+		t.Fatalf("handle normal turn: %v", err)
+	}
+	last := modelProvider.requests[2]
+	if len(last.Messages) != 3 {
+		t.Fatalf("expected incognito turns to be discarded, got %d messages", len(last.Messages))
+	}
+	if len(last.Tools) != 2 {
+		t.Fatalf("expected all tools after incognito, got %#v", last.Tools)
+	}
+	loaded, err = sessionStore.Load(ctx)
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if len(loaded) != 4 || loaded[2].Content != "back to normal" {
+		t.Fatalf("expected only the normal turn to be persisted, got %#v", loaded)
+	}
+}
+
+func TestAgentIncognitoDoesNotSaveFullToolOutputs(t *testing.T) {
+	ctx := context.Background()
+	registry := tools.NewRegistry()
+	if err := registry.Register(fakeTool{name: "read_file", out: strings.Repeat("private ", 50)}); err != nil {
+		t.Fatalf("register tool: %v", err)
+	}
+	toolTurn := func(id string) *provider.ChatResponse {
+		return &provider.ChatResponse{ToolCalls: []provider.ToolCall{{ID: id, Name: "read_file", Arguments: "{}"}}}
+	}
+	modelProvider := &recordingProvider{
+		responses: []*provider.ChatResponse{toolTurn("call_1"), {Content: "done"}, toolTurn("call_2"), {Content: "done"}},
+	}
+	sessionStore := session.New(filepath.Join(t.TempDir(), "sessions", "cli", "default.jsonl"))
+	ag := NewWithSession(modelProvider, registry, noopApprover{}, makeAgentDir(t), sessionStore, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 20, time.Second, config.ContextConfig{})
+	fullOutputs := outputs.New(filepath.Join(t.TempDir(), "tool_outputs"))
+	ag.ConfigureOutputs(fullOutputs)
+
+	ag.SetIncognito(true)
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "read my diary"}); err != nil {
+		t.Fatalf("handle incognito turn: %v", err)
+	}
+	if saved, err := fullOutputs.List(0); err != nil || len(saved) != 0 {
+		t.Fatalf("expected no full outputs saved while incognito, got %#v (%v)", saved, err)
+	}
+
+	ag.SetIncognito(false)
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "read it again"}); err != nil {
+		t.Fatalf("handle normal turn: %v", err)
+	}
+	if saved, err := fullOutputs.List(0); err != nil || len(saved) != 1 {
+		t.Fatalf("expected the normal turn's output saved, got %#v (%v)", saved, err)
+	}
+}
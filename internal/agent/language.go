@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/language"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+)
+
+// ConfigureLanguages enables per-user reply languages kept in store.
+func (a *Agent) ConfigureLanguages(store *language.Store) {
+	a.languages = store
+}
+
+// languagePrompt learns the sender's language from their message and asks
+// the model to reply in it, whatever language the system prompt is in.
+// Incognito turns read the stored preference but do not update it.
+func (a *Agent) languagePrompt(systemPrompt string, msg *runtime.Message) string {
+	if a.languages == nil {
+		return systemPrompt
+	}
+	var (
+		pref language.Preference
+		err  error
+	)
+	if a.incognito {
+		pref, err = a.languages.Get(msg.UserID)
+	} else {
+		pref, err = a.languages.Observe(msg.UserID, msg.Text, time.Now())
+	}
+	if err != nil {
+		logging.Logger().Warn("failed to load language preference", "user_id", msg.UserID, "err", err)
+		return systemPrompt
+	}
+	if pref.Mode == language.ModeOff || pref.Language == "" {
+		return systemPrompt
+	}
+	name := language.Name(pref.Language)
+	return systemPrompt + fmt.Sprintf("\n\n[Reply language]\nThe person writing this message prefers %s. Reply in %s, even though these instructions are in another language, unless they ask for a different one. Keep code, commands, and quoted text as they are.", name, name)
+}
@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/language"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestLanguagePromptFollowsEachSender(t *testing.T) {
+	ag := New(&recordingProvider{}, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), nil, config.ContextConfig{})
+	if got := ag.languagePrompt("base", &runtime.Message{Text: "Kannst du mir bitte helfen, wie das geht?"}); got != "base" {
+		t.Fatalf("expected no block without a store, got %q", got)
+	}
+	languages := language.New(filepath.Join(t.TempDir(), "languages.json"))
+	ag.ConfigureLanguages(languages)
+
+	prompt := ag.languagePrompt("base", &runtime.Message{Text: "Kannst du mir bitte helfen, wie das geht?", UserID: "1"})
+	if !strings.Contains(prompt, "[Reply language]\nThe person writing this message prefers German. Reply in German") {
+		t.Fatalf("expected German reply block, got %q", prompt)
+	}
+	// A short follow-up keeps the detected language.
+	if prompt = ag.languagePrompt("base", &runtime.Message{Text: "ok", UserID: "1"}); !strings.Contains(prompt, "prefers German") {
+		t.Fatalf("expected German to stick, got %q", prompt)
+	}
+	// Another household member gets their own language.
+	if prompt = ag.languagePrompt("base", &runtime.Message{Text: "Você pode me lembrar de comprar pão hoje?", UserID: "2"}); !strings.Contains(prompt, "prefers Portuguese") {
+		t.Fatalf("expected Portuguese for user 2, got %q", prompt)
+	}
+
+	if err := languages.Set("1", language.Preference{Language: "de", Mode: language.ModeOff}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got := ag.languagePrompt("base", &runtime.Message{Text: "Kannst du mir bitte helfen?", UserID: "1"}); got != "base" {
+		t.Fatalf("expected no block when off, got %q", got)
+	}
+}
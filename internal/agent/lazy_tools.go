@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"slices"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+// lazyRegistry offers the model only the core tools, the tools it loaded
+// earlier in the session, and load_tools to add more. Every tool stays
+// callable, so history that uses a tool loaded before a restart still works.
+func (a *Agent) lazyRegistry(all *tools.Registry) *tools.Registry {
+	advertised := func(tool tools.Tool) bool {
+		name := tool.Name()
+		return name == tools.LoadToolName || a.loadedTools[name] || slices.Contains(a.contextCfg.CoreTools, name)
+	}
+	registry := all.Advertise(advertised)
+	loader := tools.LoadToolsTool{
+		Available: func() []tools.Tool {
+			var available []tools.Tool
+			for _, tool := range all.Tools() {
+				if !advertised(tool) {
+					available = append(available, tool)
+				}
+			}
+			return available
+		},
+		Load: func(names []string) {
+			if a.loadedTools == nil {
+				a.loadedTools = map[string]bool{}
+			}
+			for _, name := range names {
+				a.loadedTools[name] = true
+			}
+			logging.Logger().Info("loaded tools", "tools", names)
+		},
+	}
+	if err := registry.Register(loader); err != nil {
+		logging.Logger().Warn("lazy tool loading disabled", "err", err)
+		return all
+	}
+	return registry
+}
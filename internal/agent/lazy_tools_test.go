@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestLazyToolsAdvertisesCoreAndLoadsOnDemand(t *testing.T) {
+	registry := tools.NewRegistry()
+	for _, name := range []string{"read_file", "todo_add", "todo_list"} {
+		if err := registry.Register(fakeTool{name: name, out: name + " ok"}); err != nil {
+			t.Fatalf("register %s: %v", name, err)
+		}
+	}
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{
+		{ToolCalls: []provider.ToolCall{{ID: "1", Name: "load_tools", Arguments: `{"names":"todo_list"}`}}},
+		{ToolCalls: []provider.ToolCall{{ID: "2", Name: "todo_list", Arguments: `{}`}}},
+		{Content: "Nothing due today."},
+		{Content: "Still nothing."},
+	}}
+	contextCfg := config.ContextConfig{LazyTools: true, CoreTools: []string{"read_file"}}
+	ag := New(modelProvider, registry, noopApprover{}, makeAgentDir(t), mustNewMemoryStore(t, t.TempDir()), contextCfg)
+
+	if err := ag.HandleMessage(context.Background(), &captureWriter{}, &runtime.Message{Text: "what's on my todo list?"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	offered := func(req provider.ChatRequest) string {
+		names := make([]string, 0, len(req.Tools))
+		for _, def := range req.Tools {
+			names = append(names, def.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	if got := offered(modelProvider.requests[0]); got != "load_tools,read_file" {
+		t.Fatalf("first request offered %s", got)
+	}
+	if desc := modelProvider.requests[0].Tools[0].Description; !strings.Contains(desc, "- todo_add: todo_add") || !strings.Contains(desc, "- todo_list: todo_list") {
+		t.Fatalf("load_tools description does not list unloaded tools: %q", desc)
+	}
+	if got := offered(modelProvider.requests[1]); got != "load_tools,read_file,todo_list" {
+		t.Fatalf("second request offered %s", got)
+	}
+	if result := modelProvider.requests[2].Messages[len(modelProvider.requests[2].Messages)-1]; result.Content != "todo_list ok" {
+		t.Fatalf("expected loaded tool to run, got %q", result.Content)
+	}
+
+	// Loaded tools stay offered for the rest of the session.
+	if err := ag.HandleMessage(context.Background(), &captureWriter{}, &runtime.Message{Text: "and now?"}); err != nil {
+		t.Fatalf("handle second message: %v", err)
+	}
+	if got := offered(modelProvider.requests[3]); got != "load_tools,read_file,todo_list" {
+		t.Fatalf("next turn offered %s", got)
+	}
+}
@@ -10,13 +10,33 @@ import (
 
 	"github.com/neoclaw-ai/neoclaw/internal/approval"
 	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
 	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/telemetry"
 	"github.com/neoclaw-ai/neoclaw/internal/tools"
 )
 
 const defaultMaxIterations = 15
 const defaultToolOutputLength = 12000
 
+// TurnBudget bounds how many tokens and how much wall-clock time one turn may
+// consume. Zero values disable the corresponding limit.
+type TurnBudget struct {
+	MaxTokens   int
+	MaxDuration time.Duration
+}
+
+// exceeded reports which limit, if any, the turn has crossed.
+func (b TurnBudget) exceeded(usedTokens int, elapsed time.Duration) string {
+	if b.MaxTokens > 0 && usedTokens >= b.MaxTokens {
+		return fmt.Sprintf("%d of %d tokens used", usedTokens, b.MaxTokens)
+	}
+	if b.MaxDuration > 0 && elapsed >= b.MaxDuration {
+		return fmt.Sprintf("%s of %s elapsed", elapsed.Round(time.Second), b.MaxDuration)
+	}
+	return ""
+}
+
 // Run executes the agent loop until the model returns a final text response.
 func Run(
 	ctx context.Context,
@@ -27,6 +47,9 @@ func Run(
 	messages []provider.ChatMessage,
 	maxIterations int,
 	toolOutputLength int,
+	fullOutputs *outputs.Store,
+	budget TurnBudget,
+	progress ProgressReporter,
 	onLLMResponse func(usage provider.TokenUsage) error,
 ) (*provider.ChatResponse, []provider.ChatMessage, error) {
 	if modelProvider == nil {
@@ -42,17 +65,45 @@ func Run(
 		toolOutputLength = defaultToolOutputLength
 	}
 
+	// Decisions are reused for identical requests within this call only.
+	approver = approval.NewTurnApprover(recordApprovals(approver))
 	history := append([]provider.ChatMessage(nil), messages...)
-	toolDefs := registry.ToolDefinitions()
-	availableTools := toolNames(toolDefs)
 	totalUsage := provider.TokenUsage{}
+	turnStartedAt := time.Now()
+	partialAnswer := ""
+	recorder := turnRecorderFrom(ctx)
+	tracker := newProgressTracker(turnStartedAt)
+	stopProgress := tracker.start(ctx, progress)
+	defer stopProgress()
 
 	for i := 0; i < maxIterations; i++ {
 		if err := ctx.Err(); err != nil {
 			return nil, history, err
 		}
+		// Definitions are rebuilt every iteration because load_tools can
+		// offer more tools partway through a turn.
+		toolDefs := registry.ToolDefinitions()
+		availableTools := toolNames(toolDefs)
+		if reason := budget.exceeded(totalUsage.TotalTokens, time.Since(turnStartedAt)); reason != "" {
+			// Wrap up locally instead of spending another LLM call. The history
+			// ends on tool results, so closing with an assistant message keeps it
+			// valid and lets "continue" pick up where the turn stopped.
+			logging.Logger().Warn(
+				"turn budget exceeded",
+				"reason", reason,
+				"iteration", i+1,
+				"total_tokens", totalUsage.TotalTokens,
+			)
+			content := budgetExceededMessage(partialAnswer, reason)
+			history = append(history, provider.ChatMessage{
+				Role:    provider.RoleAssistant,
+				Content: content,
+			})
+			return &provider.ChatResponse{Content: content, Usage: totalUsage}, history, nil
+		}
 		// Each iteration sends the full conversation state and available tools.
 		// The model either returns final text or a set of tool calls.
+		tracker.thinking()
 		logging.Logger().Info(
 			"llm request",
 			"iteration", i+1,
@@ -61,12 +112,24 @@ func Run(
 			"latest_user_message", summarizeTextForLog(latestUserMessage(history), 300),
 		)
 
-		resp, err := modelProvider.Chat(ctx, provider.ChatRequest{
+		chatCtx := ctx
+		if progress.Stream != nil {
+			chatCtx = provider.WithStream(ctx, func(text string) {
+				tracker.streaming()
+				progress.Stream(ctx, text)
+			})
+		}
+		resp, err := modelProvider.Chat(chatCtx, provider.ChatRequest{
 			SystemPrompt: systemPrompt,
 			Messages:     history,
 			Tools:        toolDefs,
 		})
 		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				telemetry.Error("provider.timeout")
+			} else if !errors.Is(err, context.Canceled) {
+				telemetry.Error("provider.error")
+			}
 			return nil, history, err
 		}
 		logging.Logger().Info(
@@ -104,6 +167,9 @@ func Run(
 			Content:   resp.Content,
 			ToolCalls: resp.ToolCalls,
 		})
+		if strings.TrimSpace(resp.Content) != "" {
+			partialAnswer = resp.Content
+		}
 
 		for _, call := range resp.ToolCalls {
 			if err := ctx.Err(); err != nil {
@@ -161,7 +227,14 @@ func Run(
 
 			// Approval and execution are coupled here so both policy errors and
 			// runtime execution errors are returned to the model uniformly.
-			result, err := approval.ExecuteTool(ctx, approver, tool, args, toolDescription(tool, args, call.Name))
+			description := toolDescription(tool, args, call.Name)
+			tracker.toolStarted(call.Name, description)
+			if progress.ToolStarted != nil {
+				progress.ToolStarted(ctx, description)
+			}
+			telemetry.Feature("tool." + call.Name)
+			result, err := approval.ExecuteTool(ctx, approver, tool, args, description)
+			trust := registry.Trust(call.Name)
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
 					logging.Logger().Info(
@@ -172,10 +245,13 @@ func Run(
 					)
 					return nil, history, err
 				}
+				telemetry.Error("tool." + call.Name)
+				recorder.toolCall(call.Name, time.Since(startedAt), err)
 				logging.Logger().Warn(
 					"tool call failed",
 					"tool", call.Name,
 					"tool_call_id", call.ID,
+					"trust", trust,
 					"duration_ms", time.Since(startedAt).Milliseconds(),
 					"err", err,
 				)
@@ -183,25 +259,37 @@ func Run(
 					Role:       provider.RoleTool,
 					ToolCallID: call.ID,
 					Content:    fmt.Sprintf("tool execution error: %v", err),
+					Trust:      trust,
 				})
 				continue
 			}
 
+			content := result.Output
+			if len(content) > toolOutputLength {
+				// Keep what the model doesn't see so /outputs can show it.
+				if fullOutputs != nil {
+					path, err := fullOutputs.Save(call.Name, content, time.Now())
+					if err != nil {
+						logging.Logger().Warn("failed to save full tool output", "tool", call.Name, "err", err)
+					}
+					result.FullOutputPath = path
+				}
+				content = content[:toolOutputLength]
+			}
+			recorder.toolCall(call.Name, time.Since(startedAt), nil)
 			logging.Logger().Info(
 				"tool call complete",
 				"tool", call.Name,
 				"tool_call_id", call.ID,
+				"trust", trust,
 				"duration_ms", time.Since(startedAt).Milliseconds(),
+				"full_output", result.FullOutputPath,
 			)
-
-			content := result.Output
-			if len(content) > toolOutputLength {
-				content = content[:toolOutputLength]
-			}
 			history = append(history, provider.ChatMessage{
 				Role:       provider.RoleTool,
 				ToolCallID: call.ID,
 				Content:    content,
+				Trust:      trust,
 			})
 		}
 	}
@@ -209,6 +297,17 @@ func Run(
 	return nil, history, fmt.Errorf("max iterations exceeded (%d)", maxIterations)
 }
 
+// budgetExceededMessage builds the user-facing wrap-up for a turn that ran out of budget.
+func budgetExceededMessage(partialAnswer, reason string) string {
+	var b strings.Builder
+	if partial := strings.TrimSpace(partialAnswer); partial != "" {
+		b.WriteString(partial)
+		b.WriteString("\n\n")
+	}
+	fmt.Fprintf(&b, "I stopped before finishing because this turn hit its budget (%s). Reply \"continue\" if you want me to keep going.", reason)
+	return b.String()
+}
+
 func toolNames(defs []provider.ToolDefinition) string {
 	if len(defs) == 0 {
 		return "<none>"
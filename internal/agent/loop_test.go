@@ -3,9 +3,12 @@ package agent
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
 	"github.com/neoclaw-ai/neoclaw/internal/provider"
 	"github.com/neoclaw-ai/neoclaw/internal/tools"
 )
@@ -37,6 +40,9 @@ func TestRun_DispatchesToolAndReturnsFinalResponse(t *testing.T) {
 		10,
 		0,
 		nil,
+		TurnBudget{},
+		ProgressReporter{},
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("run loop: %v", err)
@@ -51,11 +57,51 @@ func TestRun_DispatchesToolAndReturnsFinalResponse(t *testing.T) {
 	var foundToolResult bool
 	for _, msg := range history {
 		if msg.Role == provider.RoleTool && msg.ToolCallID == "call_1" && msg.Content == "hello from file" {
-			foundToolResult = true
+			foundToolResult = msg.Trust == config.TrustWorkspace
 		}
 	}
 	if !foundToolResult {
-		t.Fatalf("expected tool result to be appended to history")
+		t.Fatalf("expected tool result tagged as workspace content to be appended to history")
+	}
+}
+
+func TestRun_SavesFullOutputWhenTruncating(t *testing.T) {
+	registry := tools.NewRegistry()
+	if err := registry.Register(fakeTool{name: "run_command", out: "0123456789abcdef"}); err != nil {
+		t.Fatalf("register tool: %v", err)
+	}
+	modelProvider := &scriptProvider{responses: []*provider.ChatResponse{
+		{ToolCalls: []provider.ToolCall{{ID: "call_1", Name: "run_command", Arguments: `{"command":"ls"}`}}},
+		{Content: "done"},
+	}}
+	store := outputs.New(t.TempDir())
+
+	_, history, err := Run(
+		context.Background(),
+		modelProvider,
+		registry,
+		nil,
+		"system",
+		[]provider.ChatMessage{{Role: provider.RoleUser, Content: "list"}},
+		10,
+		10,
+		store,
+		TurnBudget{},
+		ProgressReporter{},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("run loop: %v", err)
+	}
+	if history[2].Content != "0123456789" {
+		t.Fatalf("expected the model to get the truncated output, got %q", history[2].Content)
+	}
+	saved, err := store.List(0)
+	if err != nil || len(saved) != 1 || saved[0].Tool != "run_command" {
+		t.Fatalf("expected one saved output, got %#v err=%v", saved, err)
+	}
+	if raw, _ := os.ReadFile(saved[0].Path); string(raw) != "0123456789abcdef" {
+		t.Fatalf("expected the full output on disk, got %q", raw)
 	}
 }
 
@@ -92,6 +138,9 @@ func TestRun_MaxIterationsGuard(t *testing.T) {
 		1,
 		0,
 		nil,
+		TurnBudget{},
+		ProgressReporter{},
+		nil,
 	)
 	if err == nil || !strings.Contains(err.Error(), "max iterations exceeded") {
 		t.Fatalf("expected max iterations error, got %v", err)
@@ -124,6 +173,9 @@ func TestRun_UnknownToolAppendsErrorAndContinues(t *testing.T) {
 		2,
 		0,
 		nil,
+		TurnBudget{},
+		ProgressReporter{},
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("expected loop to continue after unknown tool, got %v", err)
@@ -143,6 +195,60 @@ func TestRun_UnknownToolAppendsErrorAndContinues(t *testing.T) {
 	}
 }
 
+func TestRun_TurnBudgetWrapsUpWithPartialAnswer(t *testing.T) {
+	registry := tools.NewRegistry()
+	if err := registry.Register(fakeTool{name: "read_file", out: "hello from file"}); err != nil {
+		t.Fatalf("register tool: %v", err)
+	}
+
+	modelProvider := &scriptProvider{responses: []*provider.ChatResponse{
+		{
+			Content: "Reading the README first.",
+			ToolCalls: []provider.ToolCall{{
+				ID:        "call_1",
+				Name:      "read_file",
+				Arguments: `{"path":"README.md"}`,
+			}},
+			Usage: provider.TokenUsage{TotalTokens: 500},
+		},
+		{Content: "should not be reached"},
+	}}
+
+	resp, history, err := Run(
+		context.Background(),
+		modelProvider,
+		registry,
+		nil,
+		"system",
+		[]provider.ChatMessage{{Role: provider.RoleUser, Content: "read it"}},
+		10,
+		0,
+		nil,
+		TurnBudget{MaxTokens: 100},
+		ProgressReporter{},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("run loop: %v", err)
+	}
+	if modelProvider.calls != 1 {
+		t.Fatalf("expected 1 provider call before budget stop, got %d", modelProvider.calls)
+	}
+	if !strings.Contains(resp.Content, "Reading the README first.") {
+		t.Fatalf("expected partial answer in response, got %q", resp.Content)
+	}
+	if !strings.Contains(resp.Content, "500 of 100 tokens used") || !strings.Contains(resp.Content, "continue") {
+		t.Fatalf("expected budget reason and offer to continue, got %q", resp.Content)
+	}
+	if resp.Usage.TotalTokens != 500 {
+		t.Fatalf("expected accumulated usage 500, got %d", resp.Usage.TotalTokens)
+	}
+	last := history[len(history)-1]
+	if last.Role != provider.RoleAssistant || last.Content != resp.Content {
+		t.Fatalf("expected wrap-up appended to history, got %+v", last)
+	}
+}
+
 func TestToolDescriptionUsesSummarizer(t *testing.T) {
 	tool := summarizedTool{summary: `write_file: path="notes.md" (12 bytes)`}
 	got := toolDescription(tool, map[string]any{"path": "notes.md", "content": "hello world!"}, "write_file")
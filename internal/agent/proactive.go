@@ -0,0 +1,316 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/expenses"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"github.com/neoclaw-ai/neoclaw/internal/todo"
+)
+
+const (
+	checkInLookbackDays  = 2
+	checkInNothingMarker = "NOTHING"
+	checkInStateDays     = 7
+	// checkInJobHorizon is how far ahead scheduled jobs are worth mentioning.
+	checkInJobHorizon = 24 * time.Hour
+)
+
+// checkInTags are the daily log entry types worth following up on. A check-in
+// makes no LLM call unless at least one recent entry carries one of them.
+var checkInTags = map[string]bool{
+	"task":     true,
+	"followup": true,
+	"plan":     true,
+	"event":    true,
+}
+
+// CheckIn decides whether to send an unprompted message based on recent
+// daily log entries, and enforces the rate limit.
+type CheckIn struct {
+	Provider provider.Provider
+	Memory   *memory.Store
+	// Tasks, when set, adds todo items due today or overdue.
+	Tasks *todo.Store
+	// Jobs, when set, adds scheduled reminders and jobs that run within the
+	// next day or are overdue.
+	Jobs *scheduler.Service
+	// Expenses, when set, sends last month's spending summary on the first
+	// check-in of a new month. The summary needs no LLM call.
+	Expenses  *expenses.Log
+	StatePath string
+	MaxPerDay int
+	// Silenced reports whether unprompted messages are currently being held
+	// (quiet hours or do-not-disturb). Check-ins are skipped rather than
+	// queued, since they would be stale by the time they are delivered.
+	Silenced func() bool
+	// UpdateNotice, when set, returns the latest release version and a
+	// notice about it, or an empty notice when NeoClaw is up to date. Each
+	// version is announced once.
+	UpdateNotice func(ctx context.Context) (version, notice string)
+}
+
+type checkInRecord struct {
+	SentAt time.Time `json:"sent_at"`
+	Text   string    `json:"text"`
+}
+
+type checkInState struct {
+	Sent []checkInRecord `json:"sent"`
+	// ExpenseMonth is the last month (YYYY-MM) whose expense summary was sent.
+	ExpenseMonth string `json:"expense_month,omitempty"`
+	// UpdateVersion is the last release version announced.
+	UpdateVersion string `json:"update_version,omitempty"`
+}
+
+// Run evaluates one check-in and writes the message to w when there is
+// something worth raising. It returns a short status for the scheduler log.
+func (c CheckIn) Run(ctx context.Context, w io.Writer, now time.Time) (string, error) {
+	if c.Provider == nil {
+		return "", errors.New("provider is required")
+	}
+	if c.Memory == nil {
+		return "", errors.New("memory store is required")
+	}
+	if c.Silenced != nil && c.Silenced() {
+		return "skipped: notifications silenced", nil
+	}
+
+	state, err := loadCheckInState(c.StatePath)
+	if err != nil {
+		return "", err
+	}
+	if c.MaxPerDay > 0 && sentOnDay(state, now) >= c.MaxPerDay {
+		return "skipped: daily limit reached", nil
+	}
+	if summary := c.expenseSummary(&state, now); summary != "" {
+		if _, err := fmt.Fprintln(w, summary); err != nil {
+			return "", fmt.Errorf("send expense summary: %w", err)
+		}
+		state.Sent = append(state.Sent, checkInRecord{SentAt: now, Text: summary})
+		if err := saveCheckInState(c.StatePath, state, now); err != nil {
+			logging.Logger().Warn("failed to record expense summary", "err", err)
+		}
+		return "sent expense summary", nil
+	}
+	if notice := c.updateNotice(ctx, &state); notice != "" {
+		if _, err := fmt.Fprintln(w, notice); err != nil {
+			return "", fmt.Errorf("send update notice: %w", err)
+		}
+		state.Sent = append(state.Sent, checkInRecord{SentAt: now, Text: notice})
+		if err := saveCheckInState(c.StatePath, state, now); err != nil {
+			logging.Logger().Warn("failed to record update notice", "err", err)
+		}
+		return "sent update notice", nil
+	}
+
+	var candidates []memory.LogEntry
+	for _, entry := range c.Memory.DailyLogsForPrompt(lookbackDates(now, checkInLookbackDays)) {
+		if len(entry.Tags) > 0 && checkInTags[entry.Tags[0]] {
+			candidates = append(candidates, entry)
+		}
+	}
+	var dueTasks []todo.Task
+	if c.Tasks != nil {
+		dueTasks, err = c.Tasks.Due(now)
+		if err != nil {
+			logging.Logger().Warn("skipping unreadable todo list", "err", err)
+		}
+	}
+	var dueJobs []scheduler.Job
+	if c.Jobs != nil {
+		dueJobs, err = upcomingJobs(ctx, c.Jobs, now)
+		if err != nil {
+			logging.Logger().Warn("skipping unreadable scheduled jobs", "err", err)
+		}
+	}
+	if len(candidates) == 0 && len(dueTasks) == 0 && len(dueJobs) == 0 {
+		return "skipped: nothing pending", nil
+	}
+
+	var b strings.Builder
+	b.WriteString(currentTimeContextLine(now))
+	if len(candidates) > 0 {
+		b.WriteString("\n\n[Recent daily log]\n")
+		for _, entry := range candidates {
+			b.WriteString(entry.Timestamp.In(time.Local).Format("2006-01-02 15:04"))
+			b.WriteByte('\t')
+			b.WriteString(entry.FormatLLM())
+			b.WriteByte('\n')
+		}
+	}
+	if len(dueTasks) > 0 {
+		b.WriteString("\n\n[Todo items due]\n")
+		for _, task := range dueTasks {
+			b.WriteString(task.Format(now))
+			b.WriteByte('\n')
+		}
+	}
+	if len(dueJobs) > 0 {
+		b.WriteString("\n\n[Scheduled reminders and jobs]\n")
+		for _, job := range dueJobs {
+			next := job.NextRun(now)
+			status := "runs"
+			if next.Before(now) {
+				status = "overdue since"
+			}
+			fmt.Fprintf(&b, "%s %s\t%s (%s)\n", status, next.In(time.Local).Format("2006-01-02 15:04"), job.Description, job.Action)
+		}
+	}
+	b.WriteString("\n[Persistent facts]\n")
+	for _, entry := range c.Memory.ActiveFacts(now) {
+		b.WriteString(entry.FormatLLM())
+		b.WriteByte('\n')
+	}
+	if len(state.Sent) > 0 {
+		b.WriteString("\n[Check-ins already sent]\n")
+		for _, sent := range state.Sent {
+			b.WriteString(sent.SentAt.In(time.Local).Format("2006-01-02 15:04"))
+			b.WriteByte('\t')
+			b.WriteString(sent.Text)
+			b.WriteByte('\n')
+		}
+	}
+
+	resp, err := c.Provider.Chat(ctx, provider.ChatRequest{
+		SystemPrompt: proactiveCheckInPrompt,
+		Messages: []provider.ChatMessage{
+			{Role: provider.RoleUser, Content: b.String()},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("proactive check-in: %w", err)
+	}
+	if resp == nil {
+		return "", errors.New("proactive check-in: response is nil")
+	}
+	message := strings.TrimSpace(resp.Content)
+	if message == "" || message == checkInNothingMarker {
+		return "skipped: nothing worth raising", nil
+	}
+
+	if _, err := fmt.Fprintln(w, message); err != nil {
+		return "", fmt.Errorf("send check-in: %w", err)
+	}
+	state.Sent = append(state.Sent, checkInRecord{SentAt: now, Text: message})
+	if err := saveCheckInState(c.StatePath, state, now); err != nil {
+		logging.Logger().Warn("failed to record proactive check-in", "err", err)
+	}
+	return "sent", nil
+}
+
+// upcomingJobs returns the enabled jobs that are overdue or run within
+// checkInJobHorizon of now, soonest first.
+func upcomingJobs(ctx context.Context, service *scheduler.Service, now time.Time) ([]scheduler.Job, error) {
+	jobs, err := service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var due []scheduler.Job
+	for _, job := range jobs {
+		if next := job.NextRun(now); !next.IsZero() && next.Before(now.Add(checkInJobHorizon)) {
+			due = append(due, job)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].NextRun(now).Before(due[j].NextRun(now)) })
+	return due, nil
+}
+
+// expenseSummary returns last month's expense summary when it has not been
+// sent yet and there is something to report, and marks it sent in state.
+func (c CheckIn) expenseSummary(state *checkInState, now time.Time) string {
+	if c.Expenses == nil {
+		return ""
+	}
+	year, month, _ := now.In(time.Local).Date()
+	lastMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.Local).AddDate(0, -1, 0).Format(expenses.MonthLayout)
+	if state.ExpenseMonth == lastMonth {
+		return ""
+	}
+	entries, err := c.Expenses.Month(lastMonth)
+	if err != nil {
+		logging.Logger().Warn("skipping unreadable expense log", "err", err)
+		return ""
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	state.ExpenseMonth = lastMonth
+	return expenses.Summarize(entries).Format(expenses.MonthTitle(lastMonth))
+}
+
+// updateNotice returns a notice about a release not announced yet, and
+// marks it announced in state.
+func (c CheckIn) updateNotice(ctx context.Context, state *checkInState) string {
+	if c.UpdateNotice == nil {
+		return ""
+	}
+	version, notice := c.UpdateNotice(ctx)
+	if notice == "" || version == state.UpdateVersion {
+		return ""
+	}
+	state.UpdateVersion = version
+	return notice
+}
+
+func sentOnDay(state checkInState, now time.Time) int {
+	year, month, day := now.In(time.Local).Date()
+	count := 0
+	for _, sent := range state.Sent {
+		y, m, d := sent.SentAt.In(time.Local).Date()
+		if y == year && m == month && d == day {
+			count++
+		}
+	}
+	return count
+}
+
+func loadCheckInState(path string) (checkInState, error) {
+	var state checkInState
+	if strings.TrimSpace(path) == "" {
+		return state, nil
+	}
+	raw, err := store.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("read check-in state: %w", err)
+	}
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return state, fmt.Errorf("decode check-in state: %w", err)
+	}
+	return state, nil
+}
+
+// saveCheckInState persists recent check-ins, dropping entries older than a week.
+func saveCheckInState(path string, state checkInState, now time.Time) error {
+	if strings.TrimSpace(path) == "" {
+		return nil
+	}
+	cutoff := now.AddDate(0, 0, -checkInStateDays)
+	kept := state.Sent[:0]
+	for _, sent := range state.Sent {
+		if sent.SentAt.After(cutoff) {
+			kept = append(kept, sent)
+		}
+	}
+	state.Sent = kept
+	encoded, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode check-in state: %w", err)
+	}
+	return store.WriteFile(path, append(encoded, '\n'))
+}
@@ -0,0 +1,248 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/expenses"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
+	"github.com/neoclaw-ai/neoclaw/internal/todo"
+)
+
+func TestCheckInSkipsLLMWhenNothingPending(t *testing.T) {
+	memoryStore := mustNewMemoryStore(t, t.TempDir())
+	now := time.Now()
+	if err := memoryStore.AppendDailyLog(memory.LogEntry{Timestamp: now, Tags: []string{"note"}, Text: "Had coffee", KV: "-"}); err != nil {
+		t.Fatalf("append daily log: %v", err)
+	}
+	modelProvider := &recordingProvider{}
+	out := &bytes.Buffer{}
+
+	status, err := CheckIn{Provider: modelProvider, Memory: memoryStore, MaxPerDay: 2}.Run(context.Background(), out, now)
+	if err != nil {
+		t.Fatalf("run check-in: %v", err)
+	}
+	if status != "skipped: nothing pending" {
+		t.Fatalf("unexpected status %q", status)
+	}
+	if len(modelProvider.requests) != 0 || out.Len() != 0 {
+		t.Fatalf("expected no provider call or output, got %d calls and %q", len(modelProvider.requests), out.String())
+	}
+}
+
+func TestCheckInSendsMessageAndEnforcesDailyLimit(t *testing.T) {
+	memoryStore := mustNewMemoryStore(t, t.TempDir())
+	now := time.Now()
+	if err := memoryStore.AppendDailyLog(memory.LogEntry{Timestamp: now, Tags: []string{"followup", "sarah"}, Text: "Follow up with Sarah about the contract", KV: "-"}); err != nil {
+		t.Fatalf("append daily log: %v", err)
+	}
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{
+		{Content: "You said you'd follow up with Sarah today — want me to draft it?"},
+	}}
+	checkIn := CheckIn{
+		Provider:  modelProvider,
+		Memory:    memoryStore,
+		StatePath: filepath.Join(t.TempDir(), "checkins.json"),
+		MaxPerDay: 1,
+	}
+	out := &bytes.Buffer{}
+
+	status, err := checkIn.Run(context.Background(), out, now)
+	if err != nil {
+		t.Fatalf("run check-in: %v", err)
+	}
+	if status != "sent" || !strings.Contains(out.String(), "follow up with Sarah") {
+		t.Fatalf("expected check-in sent, got status %q output %q", status, out.String())
+	}
+	if !strings.Contains(modelProvider.requests[0].Messages[0].Content, "Follow up with Sarah about the contract") {
+		t.Fatalf("expected pending follow-up in request")
+	}
+
+	status, err = checkIn.Run(context.Background(), out, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("run second check-in: %v", err)
+	}
+	if status != "skipped: daily limit reached" {
+		t.Fatalf("expected daily limit, got %q", status)
+	}
+	if len(modelProvider.requests) != 1 {
+		t.Fatalf("expected no second provider call, got %d", len(modelProvider.requests))
+	}
+}
+
+func TestCheckInIncludesUpcomingScheduledJobs(t *testing.T) {
+	now := time.Now()
+	jobs := scheduler.NewService(filepath.Join(t.TempDir(), "jobs.json"), nil)
+	for _, in := range []scheduler.CreateInput{
+		{Description: "Remind me to bring the passport to the visa appointment", RunAt: now.Add(3 * time.Hour)},
+		{Description: "Renew the car insurance", RunAt: now.Add(72 * time.Hour)},
+	} {
+		in.Action = scheduler.ActionSendMessage
+		in.Args = map[string]any{"message": in.Description}
+		in.ChannelID = "cli"
+		if _, err := jobs.Create(context.Background(), in); err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+	}
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{{Content: "NOTHING"}}}
+
+	status, err := CheckIn{Provider: modelProvider, Memory: mustNewMemoryStore(t, t.TempDir()), Jobs: jobs}.Run(context.Background(), &bytes.Buffer{}, now)
+	if err != nil {
+		t.Fatalf("run check-in: %v", err)
+	}
+	if status != "skipped: nothing worth raising" || len(modelProvider.requests) != 1 {
+		t.Fatalf("expected the upcoming job to prompt an llm call, got %q with %d calls", status, len(modelProvider.requests))
+	}
+	content := modelProvider.requests[0].Messages[0].Content
+	if !strings.Contains(content, "[Scheduled reminders and jobs]") || !strings.Contains(content, "bring the passport") {
+		t.Fatalf("expected the upcoming job in the request, got %q", content)
+	}
+	if strings.Contains(content, "car insurance") {
+		t.Fatalf("expected jobs beyond a day left out, got %q", content)
+	}
+}
+
+func TestCheckInSkipsWhenSilenced(t *testing.T) {
+	modelProvider := &recordingProvider{}
+
+	status, err := CheckIn{
+		Provider: modelProvider,
+		Memory:   mustNewMemoryStore(t, t.TempDir()),
+		Silenced: func() bool { return true },
+	}.Run(context.Background(), &bytes.Buffer{}, time.Now())
+	if err != nil {
+		t.Fatalf("run check-in: %v", err)
+	}
+	if status != "skipped: notifications silenced" || len(modelProvider.requests) != 0 {
+		t.Fatalf("expected silenced skip, got %q with %d calls", status, len(modelProvider.requests))
+	}
+}
+
+func TestCheckInNothingResponseSendsNothing(t *testing.T) {
+	memoryStore := mustNewMemoryStore(t, t.TempDir())
+	now := time.Now()
+	if err := memoryStore.AppendDailyLog(memory.LogEntry{Timestamp: now, Tags: []string{"task"}, Text: "Renew passport", KV: "status=done"}); err != nil {
+		t.Fatalf("append daily log: %v", err)
+	}
+	out := &bytes.Buffer{}
+	status, err := CheckIn{
+		Provider: &recordingProvider{responses: []*provider.ChatResponse{{Content: "NOTHING"}}},
+		Memory:   memoryStore,
+	}.Run(context.Background(), out, now)
+	if err != nil {
+		t.Fatalf("run check-in: %v", err)
+	}
+	if status != "skipped: nothing worth raising" || out.Len() != 0 {
+		t.Fatalf("expected nothing sent, got %q / %q", status, out.String())
+	}
+}
+
+func TestCheckInRaisesDueTodoItems(t *testing.T) {
+	memoryStore := mustNewMemoryStore(t, t.TempDir())
+	tasks := todo.New(filepath.Join(t.TempDir(), "tasks.json"))
+	now := time.Now()
+	if _, err := tasks.Add("renew passport", now.Format(todo.DateLayout), now); err != nil {
+		t.Fatalf("add task: %v", err)
+	}
+	if _, err := tasks.Add("plan trip", now.AddDate(0, 0, 5).Format(todo.DateLayout), now); err != nil {
+		t.Fatalf("add task: %v", err)
+	}
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{
+		{Content: "Your passport renewal is due today — want the form link?"},
+	}}
+	out := &bytes.Buffer{}
+
+	status, err := CheckIn{Provider: modelProvider, Memory: memoryStore, Tasks: tasks}.Run(context.Background(), out, now)
+	if err != nil {
+		t.Fatalf("run check-in: %v", err)
+	}
+	if status != "sent" {
+		t.Fatalf("expected check-in sent, got %q", status)
+	}
+	request := modelProvider.requests[0].Messages[0].Content
+	if !strings.Contains(request, "[Todo items due]\n1. renew passport (due today)") || strings.Contains(request, "plan trip") {
+		t.Fatalf("expected only the due task in request, got %q", request)
+	}
+}
+
+func TestCheckInSendsLastMonthExpenseSummaryOnce(t *testing.T) {
+	dir := t.TempDir()
+	memoryStore := mustNewMemoryStore(t, dir)
+	spending := expenses.New(filepath.Join(dir, "expenses.tsv"))
+	now := time.Date(2026, 4, 1, 9, 0, 0, 0, time.Local)
+	for _, entry := range []expenses.Entry{
+		{Date: "2026-03-05", Amount: 40, Currency: "USD", Category: "groceries"},
+		{Date: "2026-04-01", Amount: 5, Currency: "USD", Category: "coffee"},
+	} {
+		if _, err := spending.Append(entry); err != nil {
+			t.Fatalf("append expense: %v", err)
+		}
+	}
+	modelProvider := &recordingProvider{}
+	checkIn := CheckIn{Provider: modelProvider, Memory: memoryStore, Expenses: spending, StatePath: filepath.Join(dir, "checkins.json")}
+
+	out := &bytes.Buffer{}
+	status, err := checkIn.Run(context.Background(), out, now)
+	if err != nil {
+		t.Fatalf("run check-in: %v", err)
+	}
+	if status != "sent expense summary" || out.String() != "Expenses for March 2026 (1 entry)\nTotal: 40.00 USD\n- groceries: 40.00 USD (1)\n" {
+		t.Fatalf("unexpected summary %q / %q", status, out.String())
+	}
+	if len(modelProvider.requests) != 0 {
+		t.Fatalf("expected no provider call, got %d", len(modelProvider.requests))
+	}
+
+	out.Reset()
+	status, err = checkIn.Run(context.Background(), out, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("run second check-in: %v", err)
+	}
+	if status != "skipped: nothing pending" || out.Len() != 0 {
+		t.Fatalf("expected summary sent only once, got %q / %q", status, out.String())
+	}
+}
+
+func TestCheckInAnnouncesEachUpdateOnce(t *testing.T) {
+	dir := t.TempDir()
+	memoryStore := mustNewMemoryStore(t, dir)
+	modelProvider := &recordingProvider{}
+	latest := "1.5.0"
+	checkIn := CheckIn{
+		Provider:  modelProvider,
+		Memory:    memoryStore,
+		StatePath: filepath.Join(dir, "checkins.json"),
+		UpdateNotice: func(context.Context) (string, string) {
+			return latest, "NeoClaw " + latest + " is available"
+		},
+	}
+	now := time.Date(2026, 4, 2, 9, 0, 0, 0, time.Local)
+
+	out := &bytes.Buffer{}
+	status, err := checkIn.Run(context.Background(), out, now)
+	if err != nil {
+		t.Fatalf("run check-in: %v", err)
+	}
+	if status != "sent update notice" || out.String() != "NeoClaw 1.5.0 is available\n" {
+		t.Fatalf("unexpected notice %q / %q", status, out.String())
+	}
+
+	out.Reset()
+	if status, _ := checkIn.Run(context.Background(), out, now.Add(time.Hour)); status != "skipped: nothing pending" || out.Len() != 0 {
+		t.Fatalf("expected notice sent only once, got %q / %q", status, out.String())
+	}
+
+	latest = "1.6.0"
+	if status, _ := checkIn.Run(context.Background(), out, now.Add(2*time.Hour)); status != "sent update notice" {
+		t.Fatalf("expected newer release announced, got %q", status)
+	}
+	if len(modelProvider.requests) != 0 {
+		t.Fatalf("expected no provider call, got %d", len(modelProvider.requests))
+	}
+}
@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// DefaultProfileRefreshLookbackDays is how many days of daily logs a profile
+// refresh reviews when the job does not set lookback_days.
+const DefaultProfileRefreshLookbackDays = 7
+
+const profileNoChangesMarker = "NO_CHANGES"
+
+// ProposeUserProfile asks the model to review recent memory against USER.md.
+// When it suggests changes, the full proposed profile is saved next to USER.md
+// for the user to apply or discard; USER.md itself is never written here.
+// It returns the proposed profile, or "" when no update is needed.
+func ProposeUserProfile(
+	ctx context.Context,
+	modelProvider provider.Provider,
+	agentDir string,
+	memoryStore *memory.Store,
+	lookbackDays int,
+	now time.Time,
+) (string, error) {
+	if modelProvider == nil {
+		return "", errors.New("provider is required")
+	}
+	if memoryStore == nil {
+		return "", errors.New("memory store is required")
+	}
+	if lookbackDays <= 0 {
+		lookbackDays = DefaultProfileRefreshLookbackDays
+	}
+
+	userText, _, err := readOptionalFile(filepath.Join(agentDir, config.UserFilePath))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("[Current USER.md]\n")
+	if strings.TrimSpace(userText) == "" {
+		b.WriteString("(empty)\n")
+	} else {
+		b.WriteString(userText)
+		if !strings.HasSuffix(userText, "\n") {
+			b.WriteByte('\n')
+		}
+	}
+	b.WriteString("\n[Persistent facts]\n")
+	for _, entry := range memoryStore.ActiveFacts(now) {
+		b.WriteString(entry.FormatLLM())
+		b.WriteByte('\n')
+	}
+	b.WriteString("\n[Recent daily log]\n")
+	for _, entry := range memoryStore.DailyLogsForPrompt(lookbackDates(now, lookbackDays)) {
+		b.WriteString(entry.Timestamp.In(time.Local).Format("2006-01-02"))
+		b.WriteByte('\t')
+		b.WriteString(entry.FormatLLM())
+		b.WriteByte('\n')
+	}
+
+	resp, err := modelProvider.Chat(ctx, provider.ChatRequest{
+		SystemPrompt: profileRefreshPrompt,
+		Messages: []provider.ChatMessage{
+			{Role: provider.RoleUser, Content: b.String()},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("profile refresh: %w", err)
+	}
+	if resp == nil {
+		return "", errors.New("profile refresh: response is nil")
+	}
+
+	proposal := strings.TrimSpace(resp.Content)
+	if proposal == "" || proposal == profileNoChangesMarker || proposal == strings.TrimSpace(userText) {
+		return "", nil
+	}
+	if err := store.WriteFile(filepath.Join(agentDir, config.ProposedUserFilePath), []byte(proposal+"\n")); err != nil {
+		return "", fmt.Errorf("write profile proposal: %w", err)
+	}
+	return proposal, nil
+}
+
+// PendingUserProfile returns the saved profile proposal, or "" when none is pending.
+func PendingUserProfile(agentDir string) (string, error) {
+	text, _, err := readOptionalFile(filepath.Join(agentDir, config.ProposedUserFilePath))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// ApplyUserProfile replaces USER.md with the pending proposal.
+func ApplyUserProfile(agentDir string) error {
+	proposal, err := PendingUserProfile(agentDir)
+	if err != nil {
+		return err
+	}
+	if proposal == "" {
+		return errors.New("no profile update is pending")
+	}
+	if err := store.WriteFile(filepath.Join(agentDir, config.UserFilePath), []byte(proposal+"\n")); err != nil {
+		return fmt.Errorf("write %s: %w", config.UserFilePath, err)
+	}
+	return DiscardUserProfile(agentDir)
+}
+
+// DiscardUserProfile removes the pending proposal without touching USER.md.
+func DiscardUserProfile(agentDir string) error {
+	if err := store.RemoveFile(filepath.Join(agentDir, config.ProposedUserFilePath)); err != nil {
+		return fmt.Errorf("remove profile proposal: %w", err)
+	}
+	return nil
+}
+
+// FormatProfileProposal renders a proposal as a message asking for approval.
+func FormatProfileProposal(proposal string) string {
+	return fmt.Sprintf(
+		"I drafted an update to your profile (%s) from recent memory:\n\n%s\n\nSend /profile apply to save it or /profile discard to drop it.",
+		config.UserFilePath,
+		proposal,
+	)
+}
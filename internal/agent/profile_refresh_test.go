@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+func TestProposeUserProfileSavesProposalWithoutTouchingUserFile(t *testing.T) {
+	agentDir := t.TempDir()
+	userPath := filepath.Join(agentDir, config.UserFilePath)
+	if err := os.WriteFile(userPath, []byte("Name: Sam\nCity: Boston\n"), 0o644); err != nil {
+		t.Fatalf("write USER.md: %v", err)
+	}
+	memoryStore := mustNewMemoryStore(t, t.TempDir())
+	now := time.Now()
+	if err := memoryStore.AppendMemory(memory.LogEntry{Timestamp: now, Tags: []string{"location"}, Text: "Moved to Denver", KV: "-"}); err != nil {
+		t.Fatalf("append memory: %v", err)
+	}
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{
+		{Content: "Name: Sam\nCity: Denver\n"},
+	}}
+
+	proposal, err := ProposeUserProfile(context.Background(), modelProvider, agentDir, memoryStore, 0, now)
+	if err != nil {
+		t.Fatalf("propose profile: %v", err)
+	}
+	if proposal != "Name: Sam\nCity: Denver" {
+		t.Fatalf("unexpected proposal %q", proposal)
+	}
+	if len(modelProvider.requests) != 1 {
+		t.Fatalf("expected one provider call, got %d", len(modelProvider.requests))
+	}
+	input := modelProvider.requests[0].Messages[0].Content
+	if !strings.Contains(input, "City: Boston") || !strings.Contains(input, "Moved to Denver") {
+		t.Fatalf("expected profile and memory in request, got %q", input)
+	}
+
+	current, err := os.ReadFile(userPath)
+	if err != nil {
+		t.Fatalf("read USER.md: %v", err)
+	}
+	if string(current) != "Name: Sam\nCity: Boston\n" {
+		t.Fatalf("USER.md changed before approval: %q", current)
+	}
+	pending, err := PendingUserProfile(agentDir)
+	if err != nil || pending != proposal {
+		t.Fatalf("expected pending proposal, got %q err=%v", pending, err)
+	}
+
+	if err := ApplyUserProfile(agentDir); err != nil {
+		t.Fatalf("apply profile: %v", err)
+	}
+	current, err = os.ReadFile(userPath)
+	if err != nil {
+		t.Fatalf("read USER.md: %v", err)
+	}
+	if string(current) != "Name: Sam\nCity: Denver\n" {
+		t.Fatalf("unexpected USER.md after apply: %q", current)
+	}
+	if pending, _ := PendingUserProfile(agentDir); pending != "" {
+		t.Fatalf("expected proposal cleared after apply, got %q", pending)
+	}
+}
+
+func TestProposeUserProfileNoChanges(t *testing.T) {
+	agentDir := t.TempDir()
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{{Content: "NO_CHANGES"}}}
+
+	proposal, err := ProposeUserProfile(context.Background(), modelProvider, agentDir, mustNewMemoryStore(t, t.TempDir()), 3, time.Now())
+	if err != nil {
+		t.Fatalf("propose profile: %v", err)
+	}
+	if proposal != "" {
+		t.Fatalf("expected no proposal, got %q", proposal)
+	}
+	if _, err := os.Stat(filepath.Join(agentDir, config.ProposedUserFilePath)); !os.IsNotExist(err) {
+		t.Fatalf("expected no proposal file, got err=%v", err)
+	}
+}
+
+func TestApplyUserProfileWithoutProposalFails(t *testing.T) {
+	if err := ApplyUserProfile(t.TempDir()); err == nil {
+		t.Fatal("expected error without pending proposal")
+	}
+}
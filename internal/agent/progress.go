@@ -0,0 +1,214 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+)
+
+// ProgressReporter sends interim "still working" updates while a turn runs.
+// Updates start once the turn has run for After and repeat every After until
+// the turn finishes. A zero After or nil Send disables updates.
+type ProgressReporter struct {
+	After time.Duration
+	Send  func(ctx context.Context, text string) error
+	// ToolStarted, when set, is called with a one-line summary as each tool
+	// call begins.
+	ToolStarted func(ctx context.Context, description string)
+	// Stream, when set, is called with the model's reply so far as it is
+	// written.
+	Stream func(ctx context.Context, text string)
+}
+
+// progressReporter builds the reporter for a turn replying through w. Tool
+// starts are forwarded when w also surfaces activity.
+func progressReporter(after time.Duration, w runtime.ResponseWriter) ProgressReporter {
+	reporter := ProgressReporter{After: after, Send: w.WriteMessage}
+	if stream, ok := w.(runtime.StreamWriter); ok {
+		// Updates replace the streamed preview instead of piling up as
+		// separate messages.
+		reporter.Send = stream.WriteStream
+		reporter.Stream = func(ctx context.Context, text string) {
+			if err := stream.WriteStream(ctx, text); err != nil {
+				logging.Logger().Warn("failed to stream reply", "err", err)
+			}
+		}
+	}
+	if activity, ok := w.(runtime.ActivityWriter); ok {
+		reporter.ToolStarted = func(ctx context.Context, description string) {
+			if err := activity.WriteActivity(ctx, "Running "+description); err != nil {
+				logging.Logger().Warn("failed to write tool activity", "err", err)
+			}
+		}
+	}
+	return reporter
+}
+
+// progressTracker records tool activity for the current turn so interim
+// updates can be built from the tool-call history without an LLM call.
+type progressTracker struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	counts    map[string]int
+	order     []string
+	current   string
+	// writing is set while the model streams text, which is progress
+	// enough.
+	writing bool
+}
+
+func newProgressTracker(startedAt time.Time) *progressTracker {
+	return &progressTracker{startedAt: startedAt, counts: map[string]int{}}
+}
+
+// toolStarted marks a tool call as in flight.
+func (p *progressTracker) toolStarted(name, description string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.counts[name]; !ok {
+		p.order = append(p.order, name)
+	}
+	p.counts[name]++
+	p.current = description
+	p.writing = false
+}
+
+// thinking marks the turn as waiting on the model.
+func (p *progressTracker) thinking() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = ""
+	p.writing = false
+}
+
+// streaming marks the model as writing text the user can already see.
+func (p *progressTracker) streaming() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.writing = true
+}
+
+// quiet reports whether an update would interrupt streamed text.
+func (p *progressTracker) quiet() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.writing
+}
+
+// summary renders the current progress as one user-facing line.
+func (p *progressTracker) summary(now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Still working (%s so far)", now.Sub(p.startedAt).Round(time.Second))
+	var done []string
+	for _, name := range p.order {
+		done = append(done, describeToolCount(name, p.counts[name]))
+	}
+	if len(done) > 0 {
+		b.WriteString(": ")
+		b.WriteString(strings.Join(done, ", "))
+	}
+	if p.current != "" {
+		b.WriteString("; now running ")
+		b.WriteString(p.current)
+	} else {
+		b.WriteString("; now deciding the next step")
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// start launches the update loop and returns a function that stops it and
+// waits for any in-flight send to finish.
+func (p *progressTracker) start(ctx context.Context, reporter ProgressReporter) func() {
+	if reporter.After <= 0 || reporter.Send == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(reporter.After)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if p.quiet() {
+					continue
+				}
+				if err := reporter.Send(ctx, p.summary(now)); err != nil {
+					logging.Logger().Warn("failed to send progress update", "err", err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+func describeToolCount(name string, count int) string {
+	switch name {
+	case "run_command":
+		return fmt.Sprintf("ran %d %s", count, plural(count, "command", "commands"))
+	case "read_file", "read_files":
+		return fmt.Sprintf("read %d %s", count, plural(count, "file", "files"))
+	case "write_file":
+		return fmt.Sprintf("wrote %d %s", count, plural(count, "file", "files"))
+	case "apply_patch":
+		return fmt.Sprintf("applied %d %s", count, plural(count, "patch", "patches"))
+	case "render_template":
+		return fmt.Sprintf("rendered %d %s", count, plural(count, "template", "templates"))
+	case "move_file":
+		return fmt.Sprintf("moved %d %s", count, plural(count, "item", "items"))
+	case "copy_file":
+		return fmt.Sprintf("copied %d %s", count, plural(count, "file", "files"))
+	case "delete_file", "delete_dir":
+		return fmt.Sprintf("deleted %d %s", count, plural(count, "item", "items"))
+	case "outline_file":
+		return fmt.Sprintf("outlined %d %s", count, plural(count, "file", "files"))
+	case "query_table":
+		return fmt.Sprintf("ran %d table %s", count, plural(count, "query", "queries"))
+	case "query_data":
+		return fmt.Sprintf("ran %d data %s", count, plural(count, "query", "queries"))
+	case "transcribe_audio":
+		return fmt.Sprintf("transcribed %d %s", count, plural(count, "recording", "recordings"))
+	case "list_dir", "tree":
+		return fmt.Sprintf("listed %d %s", count, plural(count, "directory", "directories"))
+	case "web_search":
+		return fmt.Sprintf("ran %d web %s", count, plural(count, "search", "searches"))
+	case "translate":
+		return fmt.Sprintf("translated %d %s", count, plural(count, "text", "texts"))
+	case "get_transcript":
+		return fmt.Sprintf("fetched %d video %s", count, plural(count, "transcript", "transcripts"))
+	case "http_request":
+		return fmt.Sprintf("made %d HTTP %s", count, plural(count, "request", "requests"))
+	default:
+		return fmt.Sprintf("used %s %d %s", name, count, plural(count, "time", "times"))
+	}
+}
+
+func plural(count int, singular, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestProgressTrackerSummary(t *testing.T) {
+	startedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tracker := newProgressTracker(startedAt)
+	for i := 0; i < 6; i++ {
+		tracker.toolStarted("run_command", "run_command: ls")
+	}
+	tracker.toolStarted("read_file", `read_file: path="logs/app.log"`)
+
+	got := tracker.summary(startedAt.Add(90 * time.Second))
+	want := `Still working (1m30s so far): ran 6 commands, read 1 file; now running read_file: path="logs/app.log".`
+	if got != want {
+		t.Fatalf("unexpected summary:\n got: %q\nwant: %q", got, want)
+	}
+
+	tracker.thinking()
+	if got := tracker.summary(startedAt.Add(time.Minute)); !strings.HasSuffix(got, "now deciding the next step.") {
+		t.Fatalf("expected thinking summary, got %q", got)
+	}
+}
+
+func TestProgressTrackerSendsUpdatesUntilStopped(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	tracker := newProgressTracker(time.Now())
+	tracker.toolStarted("run_command", "run_command: sleep 10")
+
+	stop := tracker.start(context.Background(), ProgressReporter{
+		After: 10 * time.Millisecond,
+		Send: func(_ context.Context, text string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, text)
+			return nil
+		},
+	})
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		n := len(sent)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected a progress update")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	stop()
+
+	mu.Lock()
+	count := len(sent)
+	first := sent[0]
+	mu.Unlock()
+	if !strings.Contains(first, "ran 1 command") {
+		t.Fatalf("unexpected progress update %q", first)
+	}
+	time.Sleep(30 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != count {
+		t.Fatalf("expected no updates after stop, got %d more", len(sent)-count)
+	}
+}
+
+func TestProgressTrackerDisabled(t *testing.T) {
+	tracker := newProgressTracker(time.Now())
+	stop := tracker.start(context.Background(), ProgressReporter{After: 0, Send: func(context.Context, string) error {
+		t.Fatal("send should not be called")
+		return nil
+	}})
+	stop()
+}
+
+type activityCaptureWriter struct {
+	captureWriter
+	activity []string
+}
+
+func (w *activityCaptureWriter) WriteActivity(_ context.Context, text string) error {
+	w.activity = append(w.activity, text)
+	return nil
+}
+
+func TestProgressReporterForwardsToolActivity(t *testing.T) {
+	writer := &activityCaptureWriter{}
+	reporter := progressReporter(0, writer)
+	if reporter.ToolStarted == nil {
+		t.Fatal("expected tool activity to be forwarded for an ActivityWriter")
+	}
+	reporter.ToolStarted(context.Background(), "run_command: ls")
+	if len(writer.activity) != 1 || writer.activity[0] != "Running run_command: ls" {
+		t.Fatalf("unexpected activity: %#v", writer.activity)
+	}
+
+	if plain := progressReporter(0, &captureWriter{}); plain.ToolStarted != nil {
+		t.Fatal("expected no tool activity hook for a plain writer")
+	}
+}
+
+type streamCaptureWriter struct {
+	captureWriter
+	streamed []string
+}
+
+func (w *streamCaptureWriter) WriteStream(_ context.Context, text string) error {
+	w.streamed = append(w.streamed, text)
+	return nil
+}
+
+// streamingProvider writes its reply through the stream callback before
+// returning it.
+type streamingProvider struct{}
+
+func (streamingProvider) Chat(ctx context.Context, _ provider.ChatRequest) (*provider.ChatResponse, error) {
+	if onText := provider.StreamFrom(ctx); onText != nil {
+		onText("Hel")
+		onText("Hello")
+	}
+	return &provider.ChatResponse{Content: "Hello"}, nil
+}
+
+func TestRunStreamsToStreamWriter(t *testing.T) {
+	writer := &streamCaptureWriter{}
+	reporter := progressReporter(0, writer)
+	if reporter.Stream == nil {
+		t.Fatal("expected streaming for a StreamWriter")
+	}
+	resp, _, err := Run(context.Background(), streamingProvider{}, tools.NewRegistry(), nil, "", appendUserMessage(nil, "hi"), 1, 0, nil, TurnBudget{}, reporter, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if resp.Content != "Hello" || len(writer.streamed) != 2 || writer.streamed[1] != "Hello" {
+		t.Fatalf("unexpected response %q and stream %q", resp.Content, writer.streamed)
+	}
+
+	if plain := progressReporter(0, &captureWriter{}); plain.Stream != nil {
+		t.Fatal("expected no streaming for a plain writer")
+	}
+}
@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+// maxPinnedFileBytes caps how much of each pinned file goes into the system
+// prompt.
+const maxPinnedFileBytes = 16 * 1024
+
+// ConfigureProjects sets the project profiles /project switches between.
+func (a *Agent) ConfigureProjects(projects map[string]config.ProjectConfig) {
+	a.projects = projects
+}
+
+// Projects returns the configured project names, sorted.
+func (a *Agent) Projects() []string {
+	names := make([]string, 0, len(a.projects))
+	for name := range a.projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveProject returns the project selected for the current session, or ""
+// if there is none. A project removed from config is no longer active.
+func (a *Agent) ActiveProject() (string, error) {
+	if a.sessionStore == nil {
+		return "", nil
+	}
+	meta, err := a.sessionStore.Meta()
+	if err != nil {
+		return "", err
+	}
+	if _, ok := a.projects[meta.Project]; !ok {
+		return "", nil
+	}
+	return meta.Project, nil
+}
+
+// SetProject selects a project for the current session; "" clears it. Like
+// prompt block toggles, the choice is stored with the session.
+func (a *Agent) SetProject(name string) error {
+	if a.sessionStore == nil {
+		return errors.New("sessions are unavailable")
+	}
+	if name != "" {
+		project, ok := a.projects[name]
+		if !ok {
+			return fmt.Errorf("unknown project %q", name)
+		}
+		dir := a.projectDir(project)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("project directory %s does not exist", dir)
+		}
+	}
+	meta, err := a.sessionStore.Meta()
+	if err != nil {
+		return err
+	}
+	meta.Project = name
+	return a.sessionStore.SetMeta(meta)
+}
+
+func (a *Agent) projectDir(project config.ProjectConfig) string {
+	return filepath.Join(a.workspaceDir, project.Path)
+}
+
+// activeProjectConfig returns the active project's name and settings.
+func (a *Agent) activeProjectConfig() (string, config.ProjectConfig, bool, error) {
+	name, err := a.ActiveProject()
+	if err != nil || name == "" {
+		return "", config.ProjectConfig{}, false, err
+	}
+	return name, a.projects[name], true, nil
+}
+
+// projectPrompt appends the active project's root, instructions, and pinned
+// files to systemPrompt. Pins are read fresh every turn.
+func projectPrompt(systemPrompt, name, dir string, project config.ProjectConfig) string {
+	var b strings.Builder
+	b.WriteString(systemPrompt)
+	fmt.Fprintf(&b, "\n\n[Active project: %s]\n", name)
+	fmt.Fprintf(&b, "Project root: %s. Work there: run_command starts in it, and relative file paths resolve against it.\n", dir)
+	if instructions := strings.TrimSpace(project.Instructions); instructions != "" {
+		b.WriteString(instructions)
+		b.WriteByte('\n')
+	}
+	for _, pin := range project.Pins {
+		fmt.Fprintf(&b, "\n[Pinned file: %s]\n", pin)
+		content, err := os.ReadFile(filepath.Join(dir, pin))
+		if err != nil {
+			fmt.Fprintf(&b, "(could not be read: %v)\n", err)
+			continue
+		}
+		if len(content) > maxPinnedFileBytes {
+			content = []byte(strings.ToValidUTF8(string(content[:maxPinnedFileBytes]), "") + "\n[... truncated]")
+		}
+		b.Write(content)
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
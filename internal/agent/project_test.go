@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestSetProjectAddsInstructionsAndPins(t *testing.T) {
+	ctx := context.Background()
+	workspace := t.TempDir()
+	blog := filepath.Join(workspace, "blog")
+	if err := os.MkdirAll(blog, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(blog, "STYLE.md"), []byte("Short sentences."), 0o644); err != nil {
+		t.Fatalf("write pin: %v", err)
+	}
+	sessionPath := filepath.Join(t.TempDir(), "sessions", "cli", "default.jsonl")
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{{Content: "one"}, {Content: "two"}}}
+	ag := NewWithSession(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), session.New(sessionPath), mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+	ag.ConfigureWorkspace(workspace)
+	ag.ConfigureProjects(map[string]config.ProjectConfig{
+		"blog":     {Path: "blog", Pins: []string{"STYLE.md"}, Instructions: "Posts are Hugo markdown."},
+		"dotfiles": {Path: "dotfiles"},
+	})
+
+	if err := ag.SetProject("wiki"); err == nil {
+		t.Fatal("expected an unknown project to be refused")
+	}
+	if err := ag.SetProject("dotfiles"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected a missing project directory to be refused, got %v", err)
+	}
+	if err := ag.SetProject("blog"); err != nil {
+		t.Fatalf("set project: %v", err)
+	}
+	if active, err := ag.ActiveProject(); err != nil || active != "blog" {
+		t.Fatalf("expected blog to be active, got %q err=%v", active, err)
+	}
+
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "hi"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	prompt := modelProvider.requests[0].SystemPrompt
+	for _, want := range []string{"[Active project: blog]", "Project root: " + blog, "Posts are Hugo markdown.", "[Pinned file: STYLE.md]\nShort sentences."} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("expected %q in the system prompt, got %q", want, prompt)
+		}
+	}
+
+	if err := ag.SetProject(""); err != nil {
+		t.Fatalf("clear project: %v", err)
+	}
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "again"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if prompt := modelProvider.requests[1].SystemPrompt; strings.Contains(prompt, "Active project") {
+		t.Fatalf("expected no project after /project off, got %q", prompt)
+	}
+}
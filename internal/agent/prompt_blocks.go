@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DisabledPromptBlocks returns the system-prompt blocks turned off for the
+// current session.
+func (a *Agent) DisabledPromptBlocks() ([]string, error) {
+	if a.sessionStore == nil {
+		return nil, nil
+	}
+	meta, err := a.sessionStore.Meta()
+	if err != nil {
+		return nil, err
+	}
+	return meta.DisabledPromptBlocks, nil
+}
+
+// TogglePromptBlock turns one system-prompt block on or off for the current
+// session and reports whether it is now enabled. The setting is stored with
+// the session, so it survives restarts and is cleared by a reset.
+func (a *Agent) TogglePromptBlock(block string) (bool, error) {
+	if a.sessionStore == nil {
+		return false, errors.New("sessions are unavailable")
+	}
+	if !slices.Contains(PromptBlocks, block) {
+		return false, fmt.Errorf("unknown block %q (use %s)", block, strings.Join(PromptBlocks, ", "))
+	}
+	meta, err := a.sessionStore.Meta()
+	if err != nil {
+		return false, err
+	}
+	enabled := slices.Contains(meta.DisabledPromptBlocks, block)
+	if enabled {
+		meta.DisabledPromptBlocks = slices.DeleteFunc(meta.DisabledPromptBlocks, func(name string) bool { return name == block })
+	} else {
+		meta.DisabledPromptBlocks = append(meta.DisabledPromptBlocks, block)
+	}
+	if err := a.sessionStore.SetMeta(meta); err != nil {
+		return false, err
+	}
+	return enabled, nil
+}
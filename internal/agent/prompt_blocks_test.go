@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestTogglePromptBlockLeavesBlockOutOfSession(t *testing.T) {
+	ctx := context.Background()
+	agentDir := makeAgentDir(t)
+	if err := os.WriteFile(filepath.Join(agentDir, config.UserFilePath), []byte("Name: Sam\n"), 0o644); err != nil {
+		t.Fatalf("write USER.md: %v", err)
+	}
+	memoryStore := mustNewMemoryStore(t, t.TempDir())
+	if err := memoryStore.AppendMemory(memory.LogEntry{Tags: []string{"location"}, Text: "Lives in Lisbon"}); err != nil {
+		t.Fatalf("append fact: %v", err)
+	}
+	sessionPath := filepath.Join(t.TempDir(), "sessions", "cli", "default.jsonl")
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{{Content: "one"}, {Content: "two"}}}
+	ag := NewWithSession(modelProvider, tools.NewRegistry(), noopApprover{}, agentDir, session.New(sessionPath), memoryStore, 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+
+	enabled, err := ag.TogglePromptBlock(PromptBlockFacts)
+	if err != nil || enabled {
+		t.Fatalf("expected facts to be turned off, got enabled=%v err=%v", enabled, err)
+	}
+	if _, err := ag.TogglePromptBlock("soul"); err == nil {
+		t.Fatalf("expected unknown block to be rejected")
+	}
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "hi"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	prompt := modelProvider.requests[0].SystemPrompt
+	if strings.Contains(prompt, "Lives in Lisbon") || !strings.Contains(prompt, "Name: Sam") {
+		t.Fatalf("expected facts left out and profile kept, got %q", prompt)
+	}
+
+	reloaded := NewWithSession(modelProvider, tools.NewRegistry(), noopApprover{}, agentDir, session.New(sessionPath), memoryStore, 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+	disabled, err := reloaded.DisabledPromptBlocks()
+	if err != nil || len(disabled) != 1 || disabled[0] != PromptBlockFacts {
+		t.Fatalf("expected toggle to be persisted with the session, got %#v err=%v", disabled, err)
+	}
+	if enabled, err := reloaded.TogglePromptBlock(PromptBlockFacts); err != nil || !enabled {
+		t.Fatalf("expected facts back on, got enabled=%v err=%v", enabled, err)
+	}
+	if err := reloaded.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "again"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if prompt := modelProvider.requests[1].SystemPrompt; !strings.Contains(prompt, "Lives in Lisbon") {
+		t.Fatalf("expected facts after toggling back on, got %q", prompt)
+	}
+}
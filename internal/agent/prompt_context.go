@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -15,18 +16,39 @@ import (
 	"github.com/neoclaw-ai/neoclaw/internal/store"
 )
 
+// Prompt blocks that can be left out of the system prompt per session.
+const (
+	PromptBlockProfile   = "profile"
+	PromptBlockFacts     = "facts"
+	PromptBlockDailyLogs = "daily_logs"
+	PromptBlockDigests   = "digests"
+	PromptBlockContacts  = "contacts"
+)
+
+// PromptBlocks lists the blocks accepted by BuildSystemPrompt's disabled list.
+var PromptBlocks = []string{PromptBlockProfile, PromptBlockFacts, PromptBlockDailyLogs, PromptBlockDigests, PromptBlockContacts}
+
 // BuildSystemPrompt assembles the runtime system prompt from base instructions,
-// SOUL.md, USER.md, long-term memory, and recent daily log entries.
-func BuildSystemPrompt(agentDir string, store *memory.Store, contextCfg config.ContextConfig) (string, error) {
-	return buildSystemPromptAt(agentDir, store, time.Now(), contextCfg)
+// SOUL.md, USER.md, long-term memory, the latest memory digests, and recent
+// daily log entries. Days covered by an included digest are not repeated as
+// raw daily log entries. Blocks named in disabled are left out.
+func BuildSystemPrompt(agentDir string, store *memory.Store, contextCfg config.ContextConfig, disabled ...string) (string, error) {
+	return buildSystemPromptAt(agentDir, store, time.Now(), contextCfg, disabled...)
 }
 
-func buildSystemPromptAt(agentDir string, store *memory.Store, now time.Time, contextCfg config.ContextConfig) (string, error) {
+func buildSystemPromptAt(agentDir string, store *memory.Store, now time.Time, contextCfg config.ContextConfig, disabled ...string) (string, error) {
+	prompt, _, err := buildSystemPromptBlocks(agentDir, store, now, contextCfg, disabled...)
+	return prompt, err
+}
+
+// buildSystemPromptBlocks builds the system prompt and estimates the tokens
+// each of its blocks takes, in prompt order.
+func buildSystemPromptBlocks(agentDir string, store *memory.Store, now time.Time, contextCfg config.ContextConfig, disabled ...string) (string, []BlockTokens, error) {
 	if strings.TrimSpace(agentDir) == "" {
-		return "", errors.New("agent directory is required")
+		return "", nil, errors.New("agent directory is required")
 	}
 	if store == nil {
-		return "", errors.New("memory store is required")
+		return "", nil, errors.New("memory store is required")
 	}
 
 	var promptBuilder strings.Builder
@@ -34,6 +56,8 @@ func buildSystemPromptAt(agentDir string, store *memory.Store, now time.Time, co
 	promptBuilder.WriteString("\n\n")
 	promptBuilder.WriteString(toolGuidance)
 	promptBuilder.WriteString("\n\n")
+	promptBuilder.WriteString(trustGuidance)
+	promptBuilder.WriteString("\n\n")
 	promptBuilder.WriteString(autoRememberInstruction)
 	if timeLine := currentTimeContextLine(now); timeLine != "" {
 		promptBuilder.WriteString("\n\n")
@@ -46,27 +70,59 @@ func buildSystemPromptAt(agentDir string, store *memory.Store, now time.Time, co
 	soulPath := filepath.Join(agentDir, config.SoulFilePath)
 	soulText, soulExists, err := readOptionalFile(soulPath)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	if !soulExists {
 		logging.Logger().Warn("missing SOUL.md; continuing without soul context", "path", soulPath)
 	}
-	userPath := filepath.Join(agentDir, config.UserFilePath)
-	userText, userExists, err := readOptionalFile(userPath)
-	if err != nil {
-		return "", err
-	}
-	if !userExists {
-		logging.Logger().Warn("missing USER.md; continuing without user context", "path", userPath)
+	var userText string
+	if !slices.Contains(disabled, PromptBlockProfile) {
+		userPath := filepath.Join(agentDir, config.UserFilePath)
+		var userExists bool
+		userText, userExists, err = readOptionalFile(userPath)
+		if err != nil {
+			return "", nil, err
+		}
+		if !userExists {
+			logging.Logger().Warn("missing USER.md; continuing without user context", "path", userPath)
+		}
 	}
 
-	activeFacts := store.ActiveFacts(now)
-	dates := lookbackDates(now, contextCfg.DailyLogLookbackDays)
+	var activeFacts []memory.LogEntry
+	if !slices.Contains(disabled, PromptBlockFacts) {
+		// Digest facts are shown in their own block below, and contact facts
+		// only when the person is mentioned.
+		for _, entry := range store.ActiveFacts(now) {
+			if !memory.IsDigestFact(entry) && !memory.IsContactFact(entry) {
+				activeFacts = append(activeFacts, entry)
+			}
+		}
+	}
+	var digests []memory.Digest
+	if !slices.Contains(disabled, PromptBlockDigests) {
+		for _, kind := range []string{memory.DigestMonthly, memory.DigestWeekly} {
+			digest, ok, err := store.LatestDigest(kind)
+			if err != nil {
+				logging.Logger().Warn("skipping unreadable memory digest", "kind", kind, "err", err)
+				continue
+			}
+			if ok && digest.Text != "" {
+				digests = append(digests, digest)
+			}
+		}
+	}
+	var dates []time.Time
+	if !slices.Contains(disabled, PromptBlockDailyLogs) {
+		dates = lookbackDates(now, contextCfg.DailyLogLookbackDays)
+	}
 	dailyLogsByDate := make(map[string][]memory.LogEntry, len(dates))
 	hasDailyLogs := false
 	for _, date := range dates {
+		if slices.ContainsFunc(digests, func(d memory.Digest) bool { return d.Covers(date) }) {
+			continue
+		}
 		key := date.In(time.Local).Format("2006-01-02")
-		entries := store.DailyLogsByDate([]time.Time{date})
+		entries := store.DailyLogsForPrompt([]time.Time{date})
 		dailyLogsByDate[key] = entries
 		if len(entries) > 0 {
 			hasDailyLogs = true
@@ -80,31 +136,39 @@ func buildSystemPromptAt(agentDir string, store *memory.Store, now time.Time, co
 	if userText != "" {
 		includedFiles[config.UserFilePath] = estimateTokens(userText, nil)
 	}
-	if soulText == "" && userText == "" && len(activeFacts) == 0 && !hasDailyLogs {
+	if soulText == "" && userText == "" && len(activeFacts) == 0 && len(digests) == 0 && !hasDailyLogs {
 		logging.Logger().Debug(
 			"built system prompt",
 			"included_files", includedFiles,
 			"total_tokens", estimateTokens(prompt, nil),
 		)
-		return prompt, nil
+		return prompt, []BlockTokens{{Block: BlockInstructions, Tokens: estimateTokens(prompt, nil)}}, nil
 	}
 
-	var b strings.Builder
+	var (
+		b      strings.Builder
+		blocks []BlockTokens
+	)
 	b.WriteString(prompt)
 	b.WriteString("\n\nContext:\n")
+	blocks = addBlockTokens(blocks, BlockInstructions, estimateTokens(b.String(), nil))
 	if soulText != "" {
+		start := b.Len()
 		b.WriteString("\n[SOUL.md]\n")
 		b.WriteString(soulText)
 		if !strings.HasSuffix(soulText, "\n") {
 			b.WriteByte('\n')
 		}
+		blocks = addBlockTokens(blocks, BlockSoul, estimateTokens(b.String()[start:], nil))
 	}
 	if userText != "" {
+		start := b.Len()
 		b.WriteString("\n[User profile]\n")
 		b.WriteString(userText)
 		if !strings.HasSuffix(userText, "\n") {
 			b.WriteByte('\n')
 		}
+		blocks = addBlockTokens(blocks, PromptBlockProfile, estimateTokens(b.String()[start:], nil))
 	}
 	if len(activeFacts) > 0 {
 		var factsBlock strings.Builder
@@ -119,6 +183,14 @@ func buildSystemPromptAt(agentDir string, store *memory.Store, now time.Time, co
 		block := factsBlock.String()
 		b.WriteString(block)
 		includedFiles[config.MemoryFilePath] = estimateTokens(block, nil)
+		blocks = addBlockTokens(blocks, PromptBlockFacts, estimateTokens(block, nil))
+	}
+	for _, digest := range digests {
+		title := strings.ToUpper(digest.Kind[:1]) + digest.Kind[1:]
+		block := fmt.Sprintf("\n[%s digest — %s]\n%s\n", title, digest.Period(), digest.Text)
+		b.WriteString(block)
+		includedFiles[digest.Kind+".md"] = estimateTokens(block, nil)
+		blocks = addBlockTokens(blocks, PromptBlockDigests, estimateTokens(block, nil))
 	}
 	for _, date := range dates {
 		dayKey := date.In(time.Local).Format("2006-01-02")
@@ -140,6 +212,7 @@ func buildSystemPromptAt(agentDir string, store *memory.Store, now time.Time, co
 		block := dayBlock.String()
 		b.WriteString(block)
 		includedFiles[dayKey+".tsv"] = estimateTokens(block, nil)
+		blocks = addBlockTokens(blocks, PromptBlockDailyLogs, estimateTokens(block, nil))
 	}
 	systemPrompt := b.String()
 	logging.Logger().Debug(
@@ -147,7 +220,7 @@ func buildSystemPromptAt(agentDir string, store *memory.Store, now time.Time, co
 		"included_files", includedFiles,
 		"total_tokens", estimateTokens(systemPrompt, nil),
 	)
-	return systemPrompt, nil
+	return systemPrompt, blocks, nil
 }
 
 // lookbackDates returns local calendar dates from most recent to oldest.
@@ -37,6 +37,31 @@ func TestBuildSystemPromptIncludesPersistentFactsBlock(t *testing.T) {
 	}
 }
 
+func TestBuildSystemPromptOmitsDisabledBlocks(t *testing.T) {
+	agentDir := makeAgentDir(t)
+	if err := os.WriteFile(filepath.Join(agentDir, config.UserFilePath), []byte("Name: Sam\n"), 0o644); err != nil {
+		t.Fatalf("write USER.md: %v", err)
+	}
+	store := mustNewMemoryStore(t, t.TempDir())
+	now := time.Date(2026, 2, 17, 12, 0, 0, 0, time.Local)
+	if err := store.AppendMemory(memory.LogEntry{Timestamp: now.Add(-time.Hour), Tags: []string{"location"}, Text: "In SF"}); err != nil {
+		t.Fatalf("append memory fact: %v", err)
+	}
+	if err := store.AppendDailyLog(memory.LogEntry{Timestamp: now.Add(-time.Hour), Tags: []string{"work"}, Text: "Reviewed PR"}); err != nil {
+		t.Fatalf("append daily log: %v", err)
+	}
+
+	got, err := buildSystemPromptAt(agentDir, store, now, config.ContextConfig{DailyLogLookbackDays: 1}, PromptBlocks...)
+	if err != nil {
+		t.Fatalf("build system prompt: %v", err)
+	}
+	for _, unwanted := range []string{"Name: Sam", "In SF", "Reviewed PR", "Context:"} {
+		if strings.Contains(got, unwanted) {
+			t.Fatalf("expected %q to be left out, got %q", unwanted, got)
+		}
+	}
+}
+
 func TestBuildSystemPromptIncludesDailyLogBlockWithTimeColumn(t *testing.T) {
 	agentDir := t.TempDir()
 	memoryDir := filepath.Join(agentDir, "memory")
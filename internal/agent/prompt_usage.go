@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+// Request parts reported alongside the PromptBlocks in a PromptUsage.
+const (
+	// BlockInstructions is NeoClaw's built-in instructions and current time.
+	BlockInstructions = "instructions"
+	// BlockSoul is SOUL.md.
+	BlockSoul = "soul"
+	// BlockProject is the active project's instructions and pinned files.
+	BlockProject = "project"
+	// BlockWorkspace is the workspace snapshot of coding sessions.
+	BlockWorkspace = "workspace"
+	// BlockOther is the per-turn additions: reply language, response format,
+	// and incognito notes.
+	BlockOther = "other"
+	// BlockToolSchemas is the tool names, descriptions, and schemas.
+	BlockToolSchemas = "tool_schemas"
+	// BlockHistory is the conversation sent with the request.
+	BlockHistory = "history"
+)
+
+// BlockTokens is the estimated size of one part of a request.
+type BlockTokens struct {
+	Block  string
+	Tokens int
+}
+
+// PromptUsage breaks down the estimated input tokens of the first request of
+// a turn. Estimates use the same four characters per token as compaction.
+type PromptUsage struct {
+	At     time.Time
+	Blocks []BlockTokens
+	Total  int
+}
+
+// LastPromptUsage returns the token breakdown of the most recent turn, and
+// false before the first one.
+func (a *Agent) LastPromptUsage() (PromptUsage, bool) {
+	a.usageMu.Lock()
+	defer a.usageMu.Unlock()
+	return a.lastUsage, !a.lastUsage.At.IsZero()
+}
+
+// recordPromptUsage completes the system prompt's block sizes with the parts
+// added after it was built, the tool schemas, and the history, then keeps and
+// logs the breakdown.
+func (a *Agent) recordPromptUsage(blocks []BlockTokens, systemPrompt string, registry *tools.Registry, messages []provider.ChatMessage) {
+	measured := 0
+	for _, block := range blocks {
+		measured += block.Tokens
+	}
+	blocks = addBlockTokens(blocks, BlockOther, estimateTokens(systemPrompt, nil)-measured)
+	blocks = addBlockTokens(blocks, BlockToolSchemas, toolSchemaTokens(registry))
+	blocks = addBlockTokens(blocks, BlockHistory, estimateTokens("", messages))
+
+	usage := PromptUsage{At: time.Now(), Blocks: blocks}
+	attrs := make([]any, 0, len(blocks)+1)
+	for _, block := range blocks {
+		usage.Total += block.Tokens
+		attrs = append(attrs, slog.Int(block.Block, block.Tokens))
+	}
+	attrs = append(attrs, slog.Int("total", usage.Total))
+	logging.Logger().Info("prompt tokens", attrs...)
+
+	a.usageMu.Lock()
+	a.lastUsage = usage
+	a.usageMu.Unlock()
+}
+
+// addBlockTokens adds tokens to block, appending it if it is new. Empty
+// blocks are left out.
+func addBlockTokens(blocks []BlockTokens, block string, tokens int) []BlockTokens {
+	if tokens <= 0 {
+		return blocks
+	}
+	for i := range blocks {
+		if blocks[i].Block == block {
+			blocks[i].Tokens += tokens
+			return blocks
+		}
+	}
+	return append(blocks, BlockTokens{Block: block, Tokens: tokens})
+}
+
+func toolSchemaTokens(registry *tools.Registry) int {
+	if registry == nil {
+		return 0
+	}
+	encoded, err := json.Marshal(registry.ToolDefinitions())
+	if err != nil {
+		return 0
+	}
+	return len(encoded) / 4
+}
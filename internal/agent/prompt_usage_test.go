@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestHandleMessageRecordsPromptUsage(t *testing.T) {
+	agentDir := makeAgentDir(t)
+	if err := os.WriteFile(filepath.Join(agentDir, config.SoulFilePath), []byte(strings.Repeat("Be kind. ", 100)), 0o644); err != nil {
+		t.Fatalf("write SOUL.md: %v", err)
+	}
+	registry := tools.NewRegistry()
+	if err := registry.Register(fakeTool{name: "read_file", out: "hello"}); err != nil {
+		t.Fatalf("register tool: %v", err)
+	}
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{{Content: "hi"}}}
+	ag := New(modelProvider, registry, noopApprover{}, agentDir, mustNewMemoryStore(t, t.TempDir()), config.ContextConfig{})
+	if _, ok := ag.LastPromptUsage(); ok {
+		t.Fatalf("expected no usage before the first turn")
+	}
+
+	if err := ag.HandleMessage(context.Background(), &captureWriter{}, &runtime.Message{Text: "hello there"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	usage, ok := ag.LastPromptUsage()
+	if !ok {
+		t.Fatalf("expected usage after a turn")
+	}
+	tokens := map[string]int{}
+	sum := 0
+	for _, block := range usage.Blocks {
+		tokens[block.Block] = block.Tokens
+		sum += block.Tokens
+	}
+	if tokens[BlockSoul] < 220 || tokens[BlockInstructions] == 0 || tokens[BlockToolSchemas] == 0 || tokens[BlockHistory] == 0 {
+		t.Fatalf("unexpected blocks %+v", usage.Blocks)
+	}
+	if sum != usage.Total {
+		t.Fatalf("total %d does not match blocks %d", usage.Total, sum)
+	}
+	// Everything but the tool schemas is in the system prompt or messages.
+	request := modelProvider.requests[0]
+	if got, want := usage.Total-tokens[BlockToolSchemas], estimateTokens(request.SystemPrompt, request.Messages); got < want-len(usage.Blocks) || got > want {
+		t.Fatalf("blocks add up to %d tokens, request has %d", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/jsonschema"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+)
+
+const jsonResponseInstruction = "Response format: reply with a single JSON value and nothing else — no prose, no markdown code fences. Tools may still be used before the final reply."
+
+// ConfigureJSONResponse constrains final replies to JSON for integrations.
+// When schema is non-nil the reply must also validate against it. A reply
+// that fails validation gets one correction attempt; if that also fails, an
+// {"error": ...} object is delivered instead of the invalid text.
+func (a *Agent) ConfigureJSONResponse(schema jsonschema.Schema) {
+	a.jsonResponse = true
+	a.responseSchema = schema
+}
+
+// ConfigurePostProcess rewrites text replies just before delivery. History
+// keeps the original reply. JSON replies are never post-processed.
+func (a *Agent) ConfigurePostProcess(postProcess func(context.Context, string) string) {
+	a.postProcess = postProcess
+}
+
+// responseFormat is the reply format of one turn.
+type responseFormat struct {
+	json   bool
+	schema jsonschema.Schema
+}
+
+// turnResponseFormat returns the configured reply format, unless msg asks
+// for its own. A message's format replaces the configured one entirely, so a
+// json message without a schema is not held to the configured schema.
+func (a *Agent) turnResponseFormat(msg *runtime.Message) responseFormat {
+	switch {
+	case msg.ResponseSchema != nil:
+		return responseFormat{json: true, schema: msg.ResponseSchema}
+	case msg.ResponseFormat == config.ResponseFormatJSON:
+		return responseFormat{json: true}
+	case msg.ResponseFormat == config.ResponseFormatText:
+		return responseFormat{}
+	}
+	return responseFormat{json: a.jsonResponse, schema: a.responseSchema}
+}
+
+func (f responseFormat) prompt(systemPrompt string) string {
+	if !f.json {
+		return systemPrompt
+	}
+	instruction := jsonResponseInstruction
+	if f.schema != nil {
+		instruction += "\nThe JSON must validate against this JSON Schema:\n" + f.schema.String()
+	}
+	return systemPrompt + "\n\n" + instruction
+}
+
+// structuredReply validates a final reply in JSON mode against schema, asking
+// the model to correct it once when invalid. It returns the reply to deliver and the
+// history including any correction turn.
+func (a *Agent) structuredReply(
+	ctx context.Context,
+	schema jsonschema.Schema,
+	systemPrompt string,
+	history []provider.ChatMessage,
+	content string,
+	onLLMResponse func(provider.TokenUsage) error,
+) (string, []provider.ChatMessage, error) {
+	reply, err := parseJSONReply(content, schema)
+	if err == nil {
+		return reply, history, nil
+	}
+	logging.Logger().Warn("json reply failed validation; requesting correction", "err", err)
+
+	correction := fmt.Sprintf("Your reply was not valid for the required response format: %v. Reply again with only the corrected JSON.", err)
+	resp, corrected, runErr := Run(
+		ctx,
+		a.provider,
+		a.turnRegistry(),
+		a.approver,
+		systemPrompt,
+		appendUserMessage(history, correction),
+		a.maxIter,
+		a.toolOutputLength,
+		a.turnOutputs(),
+		TurnBudget{
+			MaxTokens:   a.contextCfg.MaxTurnTokens,
+			MaxDuration: a.contextCfg.MaxTurnDuration,
+		},
+		ProgressReporter{},
+		onLLMResponse,
+	)
+	if runErr != nil {
+		return "", nil, runErr
+	}
+	if resp == nil {
+		return "", nil, fmt.Errorf("agent run returned nil response")
+	}
+	reply, err = parseJSONReply(resp.Content, schema)
+	if err != nil {
+		logging.Logger().Warn("json reply failed validation after correction", "err", err)
+		return jsonErrorReply(err), corrected, nil
+	}
+	return reply, corrected, nil
+}
+
+// parseJSONReply strips an optional markdown code fence, then checks that the
+// remainder is one JSON value matching schema.
+func parseJSONReply(content string, schema jsonschema.Schema) (string, error) {
+	text := strings.TrimSpace(content)
+	if rest, ok := strings.CutPrefix(text, "```"); ok {
+		if newline := strings.IndexByte(rest, '\n'); newline >= 0 {
+			rest = rest[newline+1:]
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), "```"))
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(text))
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", err)
+	}
+	if strings.TrimSpace(text[decoder.InputOffset():]) != "" {
+		return "", fmt.Errorf("invalid JSON: unexpected content after the first value")
+	}
+	if err := schema.Validate(value); err != nil {
+		return "", fmt.Errorf("schema mismatch: %w", err)
+	}
+	return text, nil
+}
+
+func jsonErrorReply(err error) string {
+	raw, _ := json.Marshal(map[string]string{"error": "response did not match the required format: " + err.Error()})
+	return string(raw)
+}
@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/jsonschema"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func newJSONAgent(t *testing.T, responses ...string) (*Agent, *recordingProvider) {
+	t.Helper()
+	modelProvider := &recordingProvider{}
+	for _, content := range responses {
+		modelProvider.responses = append(modelProvider.responses, &provider.ChatResponse{Content: content})
+	}
+	schema, err := jsonschema.Parse([]byte(`{"type":"object","required":["answer"],"properties":{"answer":{"type":"string"}}}`))
+	if err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	ag := New(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), mustNewMemoryStore(t, t.TempDir()), config.ContextConfig{})
+	ag.ConfigureJSONResponse(schema)
+	return ag, modelProvider
+}
+
+func TestJSONResponseDeliversValidReply(t *testing.T) {
+	ag, modelProvider := newJSONAgent(t, "```json\n{\"answer\": \"42\"}\n```")
+	writer := &captureWriter{}
+
+	if err := ag.HandleMessage(context.Background(), writer, &runtime.Message{Text: "question"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if len(writer.messages) != 1 || writer.messages[0] != `{"answer": "42"}` {
+		t.Fatalf("expected unfenced JSON reply, got %#v", writer.messages)
+	}
+	if len(modelProvider.requests) != 1 {
+		t.Fatalf("expected one llm call, got %d", len(modelProvider.requests))
+	}
+	if !strings.Contains(modelProvider.requests[0].SystemPrompt, `"required":["answer"]`) {
+		t.Fatalf("expected schema in system prompt, got %q", modelProvider.requests[0].SystemPrompt)
+	}
+}
+
+func TestJSONResponseRequestsOneCorrection(t *testing.T) {
+	ag, modelProvider := newJSONAgent(t, "The answer is 42.", `{"answer":"42"}`)
+	writer := &captureWriter{}
+
+	if err := ag.HandleMessage(context.Background(), writer, &runtime.Message{Text: "question"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if len(writer.messages) != 1 || writer.messages[0] != `{"answer":"42"}` {
+		t.Fatalf("expected corrected reply, got %#v", writer.messages)
+	}
+	if len(modelProvider.requests) != 2 {
+		t.Fatalf("expected a correction call, got %d calls", len(modelProvider.requests))
+	}
+	last := modelProvider.requests[1].Messages
+	if !strings.Contains(last[len(last)-1].Content, "invalid JSON") {
+		t.Fatalf("expected correction message to explain the failure, got %q", last[len(last)-1].Content)
+	}
+}
+
+func TestJSONResponseReportsErrorAfterFailedCorrection(t *testing.T) {
+	ag, modelProvider := newJSONAgent(t, `{"result":1}`, `{"answer":1}`)
+	writer := &captureWriter{}
+
+	if err := ag.HandleMessage(context.Background(), writer, &runtime.Message{Text: "question"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if len(modelProvider.requests) != 2 {
+		t.Fatalf("expected exactly one correction attempt, got %d calls", len(modelProvider.requests))
+	}
+	if len(writer.messages) != 1 || !strings.HasPrefix(writer.messages[0], `{"error":"response did not match the required format: schema mismatch: $.answer: expected string`) {
+		t.Fatalf("expected error object, got %#v", writer.messages)
+	}
+}
+
+func TestMessageResponseFormatOverridesTheConfiguredOne(t *testing.T) {
+	ag, modelProvider := newJSONAgent(t, "Plain words.", `{"ok":true}`)
+	writer := &captureWriter{}
+
+	if err := ag.HandleMessage(context.Background(), writer, &runtime.Message{Text: "chat", ResponseFormat: config.ResponseFormatText}); err != nil {
+		t.Fatalf("handle text message: %v", err)
+	}
+	// json without a schema is not held to the configured schema.
+	if err := ag.HandleMessage(context.Background(), writer, &runtime.Message{Text: "status", ResponseFormat: config.ResponseFormatJSON}); err != nil {
+		t.Fatalf("handle json message: %v", err)
+	}
+	if len(writer.messages) != 2 || writer.messages[0] != "Plain words." || writer.messages[1] != `{"ok":true}` {
+		t.Fatalf("expected both replies as given, got %#v", writer.messages)
+	}
+	if prompt := modelProvider.request(0).SystemPrompt; strings.Contains(prompt, "Response format:") {
+		t.Fatalf("expected no JSON instruction for the text message, got %q", prompt)
+	}
+	if prompt := modelProvider.request(1).SystemPrompt; !strings.Contains(prompt, "Response format:") || strings.Contains(prompt, `"required"`) {
+		t.Fatalf("expected the JSON instruction without the schema, got %q", prompt)
+	}
+}
+
+// slowProvider answers after a delay, long enough for progress updates.
+type slowProvider struct {
+	delay time.Duration
+	reply string
+}
+
+func (p slowProvider) Chat(ctx context.Context, _ provider.ChatRequest) (*provider.ChatResponse, error) {
+	select {
+	case <-time.After(p.delay):
+		return &provider.ChatResponse{Content: p.reply}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// syncWriter records messages written from several goroutines.
+type syncWriter struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (w *syncWriter) WriteMessage(_ context.Context, text string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, text)
+	return nil
+}
+
+func TestJSONResponseSendsNoProgressUpdates(t *testing.T) {
+	for _, msg := range []runtime.Message{
+		{Text: "slow question", ResponseFormat: config.ResponseFormatJSON},
+		{Text: "slow question"},
+	} {
+		modelProvider := slowProvider{delay: 100 * time.Millisecond, reply: `{"answer":"42"}`}
+		ag := New(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), mustNewMemoryStore(t, t.TempDir()), config.ContextConfig{ProgressUpdateAfter: 10 * time.Millisecond})
+		writer := &syncWriter{}
+
+		if err := ag.HandleMessage(context.Background(), writer, &msg); err != nil {
+			t.Fatalf("handle message: %v", err)
+		}
+		writer.mu.Lock()
+		messages := writer.messages
+		writer.mu.Unlock()
+		if msg.ResponseFormat == "" {
+			// The same slow turn in text mode does get updates.
+			if len(messages) < 2 || !strings.HasPrefix(messages[0], "Still working") {
+				t.Fatalf("expected progress updates in text mode, got %#v", messages)
+			}
+			continue
+		}
+		if len(messages) != 1 || messages[0] != `{"answer":"42"}` {
+			t.Fatalf("expected only the JSON reply, got %#v", messages)
+		}
+	}
+}
+
+func TestPostProcessRewritesDeliveredReplyOnly(t *testing.T) {
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{{Content: "raw reply"}}}
+	ag := New(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), mustNewMemoryStore(t, t.TempDir()), config.ContextConfig{})
+	ag.ConfigurePostProcess(func(_ context.Context, text string) string {
+		return strings.ToUpper(text) + "\n-- sig"
+	})
+	writer := &captureWriter{}
+
+	if err := ag.HandleMessage(context.Background(), writer, &runtime.Message{Text: "question"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if len(writer.messages) != 1 || writer.messages[0] != "RAW REPLY\n-- sig" {
+		t.Fatalf("expected post-processed reply, got %#v", writer.messages)
+	}
+	if last := ag.history[len(ag.history)-1]; last.Content != "raw reply" {
+		t.Fatalf("expected history to keep the original reply, got %q", last.Content)
+	}
+}
+
+func TestPostProcessSkippedForJSONReplies(t *testing.T) {
+	ag, _ := newJSONAgent(t, `{"answer":"42"}`)
+	ag.ConfigurePostProcess(func(_ context.Context, text string) string { return text + " -- sig" })
+	writer := &captureWriter{}
+
+	if err := ag.HandleMessage(context.Background(), writer, &runtime.Message{Text: "question"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if len(writer.messages) != 1 || writer.messages[0] != `{"answer":"42"}` {
+		t.Fatalf("expected JSON reply untouched, got %#v", writer.messages)
+	}
+}
+
+func TestParseJSONReplyRejectsTrailingText(t *testing.T) {
+	if _, err := parseJSONReply(`{"answer":"a"} thanks!`, nil); err == nil {
+		t.Fatalf("expected trailing text to be rejected")
+	}
+	if got, err := parseJSONReply(" [1, 2] ", nil); err != nil || got != "[1, 2]" {
+		t.Fatalf("expected array reply to be accepted, got %q err=%v", got, err)
+	}
+}
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+// Rewind removes the last n exchanges from the conversation and archives
+// them next to the session file. An exchange is a user message and every
+// tool call and reply that followed it. It returns how many exchanges were
+// removed and the last user message that is still in the session, which is
+// empty when nothing is left.
+func (a *Agent) Rewind(ctx context.Context, n int) (int, string, error) {
+	if n < 1 {
+		return 0, "", errors.New("number of exchanges must be at least 1")
+	}
+	if a.incognito {
+		return 0, "", errors.New("cannot rewind in incognito mode")
+	}
+	if err := a.ensureHistoryLoaded(ctx); err != nil {
+		return 0, "", err
+	}
+
+	cut, removed := rewindPoint(a.history, n)
+	if removed == 0 {
+		return 0, "", errors.New("nothing to rewind")
+	}
+	if a.forkParent != nil && cut < a.forkStart {
+		return 0, "", fmt.Errorf("cannot rewind past the start of fork %s; use /merge-summary first", a.sessionStore.Name())
+	}
+
+	kept := append([]provider.ChatMessage{}, a.history[:cut]...)
+	if a.sessionStore != nil {
+		if err := a.sessionStore.Archive(ctx, a.history[cut:]); err != nil {
+			return 0, "", err
+		}
+		if err := a.sessionStore.Rewrite(ctx, kept); err != nil {
+			return 0, "", err
+		}
+	}
+	a.history = kept
+	return removed, lastUserMessage(kept), nil
+}
+
+// rewindPoint returns the index where the last n exchanges of history start
+// and how many exchanges that is, which is fewer than n when the history is
+// shorter.
+func rewindPoint(history []provider.ChatMessage, n int) (int, int) {
+	cut, removed := len(history), 0
+	for i := len(history) - 1; i >= 0 && removed < n; i-- {
+		if history[i].Role == provider.RoleUser && history[i].Kind == "" {
+			cut = i
+			removed++
+		}
+	}
+	return cut, removed
+}
+
+func lastUserMessage(history []provider.ChatMessage) string {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == provider.RoleUser && history[i].Kind == "" {
+			return history[i].Content
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestRewindRemovesAndArchivesLastExchanges(t *testing.T) {
+	ctx := context.Background()
+	store := session.New(filepath.Join(t.TempDir(), "cli", "default.jsonl"))
+	if err := store.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "name the project"},
+		{Role: provider.RoleAssistant, Content: "how about Atlas?"},
+		{Role: provider.RoleUser, Content: "check the domain"},
+		{Role: provider.RoleAssistant, ToolCalls: []provider.ToolCall{{ID: "call-1", Name: "http_request", Arguments: `{"url":"https://atlas.dev"}`}}},
+		{Role: provider.RoleTool, ToolCallID: "call-1", Content: "taken"},
+		{Role: provider.RoleAssistant, Content: "atlas.dev is taken"},
+		{Role: provider.RoleUser, Content: "then buy atlas.io"},
+		{Role: provider.RoleAssistant, Content: "done"},
+	}); err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+	ag := NewWithSession(&recordingProvider{}, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), store, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+
+	removed, lastKept, err := ag.Rewind(ctx, 2)
+	if err != nil {
+		t.Fatalf("rewind: %v", err)
+	}
+	if removed != 2 || lastKept != "name the project" {
+		t.Fatalf("unexpected rewind result %d %q", removed, lastKept)
+	}
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if len(loaded) != 2 || loaded[1].Content != "how about Atlas?" {
+		t.Fatalf("unexpected session after rewind: %#v", loaded)
+	}
+	archived, err := store.Archived(ctx)
+	if err != nil {
+		t.Fatalf("load archive: %v", err)
+	}
+	if len(archived) != 6 || archived[0].Content != "check the domain" || archived[2].ToolCallID != "call-1" {
+		t.Fatalf("unexpected archive: %#v", archived)
+	}
+
+	// Asking for more than is left removes what there is.
+	if removed, lastKept, err = ag.Rewind(ctx, 5); err != nil || removed != 1 || lastKept != "" {
+		t.Fatalf("unexpected second rewind %d %q %v", removed, lastKept, err)
+	}
+	if _, _, err := ag.Rewind(ctx, 1); err == nil {
+		t.Fatalf("expected empty session to have nothing to rewind")
+	}
+}
+
+func TestRewindStopsAtForkStart(t *testing.T) {
+	ctx := context.Background()
+	store := session.New(filepath.Join(t.TempDir(), "cli", "default.jsonl"))
+	if err := store.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "plan the migration"},
+		{Role: provider.RoleAssistant, Content: "use postgres"},
+	}); err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+	ag := NewWithSession(&recordingProvider{}, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), store, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+	if _, err := ag.Fork(ctx, "try-sqlite"); err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	if _, _, err := ag.Rewind(ctx, 1); err == nil {
+		t.Fatalf("expected rewind past the fork start to be refused")
+	}
+
+	ag.SetIncognito(true)
+	if _, _, err := ag.Rewind(ctx, 1); err == nil {
+		t.Fatalf("expected rewind to be refused in incognito mode")
+	}
+}
@@ -13,7 +13,11 @@ import (
 )
 
 func (a *Agent) ensureHistoryLoaded(ctx context.Context) error {
-	if a.historyLoadedOnce || a.sessionStore == nil {
+	if a.sessionStore == nil {
+		return nil
+	}
+	// Reload when another channel attached to the session added turns.
+	if a.historyLoadedOnce && !a.sessionStore.ChangedElsewhere() {
 		return nil
 	}
 	history, err := a.sessionStore.Load(ctx)
@@ -38,7 +42,11 @@ func (a *Agent) Reset(ctx context.Context) error {
 
 func (a *Agent) resetSession(ctx context.Context) error {
 	a.history = nil
+	a.incognitoHistory = nil
+	a.incognitoLastTurn = nil
 	a.historyLoadedOnce = true
+	a.titleRequested = false
+	a.loadedTools = nil
 	if a.sessionStore == nil {
 		return nil
 	}
@@ -150,7 +158,12 @@ func (a *Agent) runSessionSummary(ctx context.Context, timeout time.Duration, sn
 			Text:      fields[1],
 			KV:        fields[2],
 		}
-		if err := a.memoryStore.AppendDailyLog(entry); err != nil {
+		if a.queueSummaries {
+			err = a.memoryStore.QueuePending(memory.PendingDailyLog, entry)
+		} else {
+			err = a.memoryStore.AppendDailyLog(entry)
+		}
+		if err != nil {
 			logging.Logger().Warn("append session summary to daily log failed", "err", err)
 			return
 		}
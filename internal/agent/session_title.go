@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+)
+
+// sessionTitleAfterTurns is how many user messages a session needs before a
+// title is generated. Earlier turns rarely say what the conversation is about.
+const sessionTitleAfterTurns = 3
+
+const maxSessionTitleChars = 60
+
+// titleSessionAsync generates and stores a session title once the session has
+// enough turns. It runs at most once per session until the session is reset.
+func (a *Agent) titleSessionAsync(ctx context.Context, history []provider.ChatMessage) {
+	if a == nil || a.sessionStore == nil || a.titleRequested {
+		return
+	}
+	if countUserTurns(history) < sessionTitleAfterTurns {
+		return
+	}
+	a.titleRequested = true
+
+	title, err := a.sessionStore.Title()
+	if err != nil {
+		logging.Logger().Warn("read session title failed", "err", err)
+		return
+	}
+	if title != "" {
+		return
+	}
+
+	timeout := a.requestTimeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	snapshot := append([]provider.ChatMessage{}, history...)
+	// The title belongs to the session that had these turns, even if /fork
+	// or /attach switches sessions before it is ready.
+	go a.runSessionTitle(context.WithoutCancel(ctx), timeout, a.sessionStore, snapshot)
+}
+
+func (a *Agent) runSessionTitle(ctx context.Context, timeout time.Duration, store *session.Store, snapshot []provider.ChatMessage) {
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := a.provider.Chat(reqCtx, provider.ChatRequest{
+		SystemPrompt: sessionTitlePrompt,
+		Messages: []provider.ChatMessage{
+			{
+				Role:    provider.RoleUser,
+				Content: buildSummaryTranscript(snapshot),
+			},
+		},
+		MaxTokens: 32,
+	})
+	if err != nil {
+		logging.Logger().Warn("session title generation failed", "err", err)
+		return
+	}
+	if resp == nil {
+		logging.Logger().Warn("session title generation failed", "err", "title response is nil")
+		return
+	}
+	if err := a.recordUsage(reqCtx, resp.Usage); err != nil {
+		logging.Logger().Warn("failed to record title usage", "err", err)
+	}
+
+	title := cleanSessionTitle(resp.Content)
+	if title == "" {
+		logging.Logger().Warn("session title is empty; skipping")
+		return
+	}
+	if err := store.SetTitle(title); err != nil {
+		logging.Logger().Warn("store session title failed", "err", err)
+	}
+}
+
+func cleanSessionTitle(raw string) string {
+	title := strings.TrimSpace(raw)
+	if idx := strings.IndexByte(title, '\n'); idx >= 0 {
+		title = title[:idx]
+	}
+	title = strings.Trim(title, " \t\"'`*#.")
+	title, _ = truncateStringByChars(title, maxSessionTitleChars)
+	return strings.TrimSpace(title)
+}
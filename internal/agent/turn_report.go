@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+)
+
+// turnRecorder collects the report of the turn it travels with in ctx.
+// Model calls made for the turn, such as compaction and code review, are
+// counted along with the main loop's. A nil recorder records nothing.
+type turnRecorder struct {
+	mu     sync.Mutex
+	report session.TurnReport
+}
+
+type turnRecorderKey struct{}
+
+// withTurnRecorder starts a report for a turn beginning at now.
+func withTurnRecorder(ctx context.Context, now time.Time) (context.Context, *turnRecorder) {
+	recorder := &turnRecorder{report: session.TurnReport{Started: now}}
+	return context.WithValue(ctx, turnRecorderKey{}, recorder), recorder
+}
+
+func turnRecorderFrom(ctx context.Context) *turnRecorder {
+	recorder, _ := ctx.Value(turnRecorderKey{}).(*turnRecorder)
+	return recorder
+}
+
+func (r *turnRecorder) modelCall(usage provider.TokenUsage, costUSD float64) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.ModelCalls++
+	r.report.InputTokens += usage.InputTokens
+	r.report.OutputTokens += usage.OutputTokens
+	r.report.CostUSD += costUSD
+}
+
+func (r *turnRecorder) toolCall(name string, duration time.Duration, err error) {
+	if r == nil {
+		return
+	}
+	call := session.ToolReport{Name: name, DurationMS: duration.Milliseconds()}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Tools = append(r.report.Tools, call)
+}
+
+func (r *turnRecorder) approval(tool string, decision approval.ApprovalDecision, err error, wait time.Duration) {
+	if r == nil {
+		return
+	}
+	answer := "approved"
+	switch {
+	case err != nil:
+		answer = "failed"
+	case decision == approval.Denied:
+		answer = "denied"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Approvals = append(r.report.Approvals, session.ApprovalReport{Tool: tool, Decision: answer, WaitMS: wait.Milliseconds()})
+}
+
+// finish returns the report of a turn ending at now with err.
+func (r *turnRecorder) finish(now time.Time, err error) session.TurnReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report := r.report
+	report.DurationMS = now.Sub(report.Started).Milliseconds()
+	report.Tools = append([]session.ToolReport(nil), report.Tools...)
+	report.Approvals = append([]session.ApprovalReport(nil), report.Approvals...)
+	if err != nil {
+		report.Error = err.Error()
+	}
+	return report
+}
+
+// recordingApprover adds every prompt its approver answers to the turn's
+// report.
+type recordingApprover struct {
+	approver approval.Approver
+}
+
+// recordApprovals wraps approver so its prompts are reported. A nil
+// approver stays nil so "no approver configured" still surfaces.
+func recordApprovals(approver approval.Approver) approval.Approver {
+	if approver == nil {
+		return nil
+	}
+	return recordingApprover{approver: approver}
+}
+
+func (a recordingApprover) RequestApproval(ctx context.Context, req approval.ApprovalRequest) (approval.ApprovalDecision, error) {
+	startedAt := time.Now()
+	decision, err := a.approver.RequestApproval(ctx, req)
+	turnRecorderFrom(ctx).approval(req.Tool, decision, err, time.Since(startedAt))
+	return decision, err
+}
+
+// ApproverName names the wrapped approver for the policy journal.
+func (a recordingApprover) ApproverName() string {
+	if namer, ok := a.approver.(approval.ApproverNamer); ok {
+		return namer.ApproverName()
+	}
+	return ""
+}
+
+// LastTurn returns the report of the latest turn in the current session,
+// or nil before the first one.
+func (a *Agent) LastTurn() (*session.TurnReport, error) {
+	if a.incognito && a.incognitoLastTurn != nil {
+		return a.incognitoLastTurn, nil
+	}
+	if a.sessionStore == nil {
+		return nil, nil
+	}
+	meta, err := a.sessionStore.Meta()
+	if err != nil {
+		return nil, err
+	}
+	return meta.LastTurn, nil
+}
+
+// saveTurnReport stores report with the session. Incognito turns keep it
+// in memory only.
+func (a *Agent) saveTurnReport(report session.TurnReport) {
+	if a.incognito {
+		a.incognitoLastTurn = &report
+		return
+	}
+	if a.sessionStore == nil {
+		return
+	}
+	meta, err := a.sessionStore.Meta()
+	if err == nil {
+		meta.LastTurn = &report
+		err = a.sessionStore.SetMeta(meta)
+	}
+	if err != nil {
+		logging.Logger().Warn("failed to save turn report", "err", err)
+	}
+}
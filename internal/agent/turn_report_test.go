@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+type approvalTool struct{ fakeTool }
+
+func (approvalTool) Permission() tools.Permission { return tools.RequiresApproval }
+
+func TestHandleMessageSavesTurnReport(t *testing.T) {
+	registry := tools.NewRegistry()
+	if err := registry.Register(fakeTool{name: "read_file", out: "hello"}); err != nil {
+		t.Fatalf("register tool: %v", err)
+	}
+	if err := registry.Register(approvalTool{fakeTool{name: "write_file", out: "ok"}}); err != nil {
+		t.Fatalf("register tool: %v", err)
+	}
+	cost := 0.25
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{
+		{
+			ToolCalls: []provider.ToolCall{{ID: "1", Name: "read_file", Arguments: `{}`}, {ID: "2", Name: "write_file", Arguments: `{}`}},
+			Usage:     provider.TokenUsage{InputTokens: 100, OutputTokens: 10, TotalTokens: 110, CostUSD: &cost},
+		},
+		{Content: "done", Usage: provider.TokenUsage{InputTokens: 200, OutputTokens: 20, TotalTokens: 220, CostUSD: &cost}},
+	}}
+	sessionStore := session.New(filepath.Join(t.TempDir(), "default.jsonl"))
+	ag := NewWithSession(modelProvider, registry, noopApprover{}, makeAgentDir(t), sessionStore, mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, config.ContextConfig{})
+
+	if report, err := ag.LastTurn(); err != nil || report != nil {
+		t.Fatalf("expected no report before the first turn, got %+v, %v", report, err)
+	}
+	if err := ag.HandleMessage(context.Background(), &captureWriter{}, &runtime.Message{Text: "go"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+
+	meta, err := sessionStore.Meta()
+	if err != nil {
+		t.Fatalf("meta: %v", err)
+	}
+	report := meta.LastTurn
+	if report == nil {
+		t.Fatal("expected the turn report in the session metadata")
+	}
+	if report.ModelCalls != 2 || report.InputTokens != 300 || report.OutputTokens != 30 || report.CostUSD != 0.5 {
+		t.Fatalf("unexpected totals %+v", report)
+	}
+	if len(report.Tools) != 2 || report.Tools[0].Name != "read_file" || report.Tools[1].Name != "write_file" {
+		t.Fatalf("unexpected tools %+v", report.Tools)
+	}
+	if len(report.Approvals) != 1 || report.Approvals[0].Tool != "write_file" || report.Approvals[0].Decision != "approved" {
+		t.Fatalf("unexpected approvals %+v", report.Approvals)
+	}
+	if report.Started.IsZero() || report.Error != "" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
@@ -0,0 +1,167 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// workspaceGitTimeout bounds the git calls made for one snapshot, which
+	// is rebuilt every turn.
+	workspaceGitTimeout = 2 * time.Second
+	// maxWorkspaceRepos caps how many top-level repositories get a status.
+	maxWorkspaceRepos = 5
+	// maxWorkspaceStatusLines caps the changed files listed per repository.
+	maxWorkspaceStatusLines = 20
+)
+
+// ConfigureWorkspace sets the directory summarized in coding sessions.
+func (a *Agent) ConfigureWorkspace(dir string) {
+	a.workspaceDir = dir
+}
+
+// CodingSession reports whether the current session is flagged as a coding
+// session.
+func (a *Agent) CodingSession() (bool, error) {
+	if a.sessionStore == nil {
+		return false, nil
+	}
+	meta, err := a.sessionStore.Meta()
+	if err != nil {
+		return false, err
+	}
+	return meta.Coding, nil
+}
+
+// SetCodingSession flags or unflags the current session as a coding
+// session. Like prompt block toggles, the flag is stored with the session.
+func (a *Agent) SetCodingSession(coding bool) error {
+	if a.sessionStore == nil {
+		return errors.New("sessions are unavailable")
+	}
+	meta, err := a.sessionStore.Meta()
+	if err != nil {
+		return err
+	}
+	meta.Coding = coding
+	return a.sessionStore.SetMeta(meta)
+}
+
+// workspacePrompt appends a fresh snapshot of root to systemPrompt when
+// summaries are enabled and the session is a coding session.
+func (a *Agent) workspacePrompt(ctx context.Context, systemPrompt, root string) (string, error) {
+	if !a.contextCfg.WorkspaceSummary.Enabled || strings.TrimSpace(root) == "" {
+		return systemPrompt, nil
+	}
+	coding, err := a.CodingSession()
+	if err != nil || !coding {
+		return systemPrompt, err
+	}
+	summary := WorkspaceSummary(ctx, root, a.contextCfg.WorkspaceSummary.MaxEntries)
+	if summary == "" {
+		return systemPrompt, nil
+	}
+	return systemPrompt + "\n\n" + summary, nil
+}
+
+// WorkspaceSummary describes dir for the system prompt: its top-level
+// entries, at most maxEntries of them, and the git branch and changed files
+// of dir and of the repositories directly under it. Git details are left out
+// when git is not installed. It returns "" if dir cannot be read.
+func WorkspaceSummary(ctx context.Context, dir string, maxEntries int) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	names := make([]string, 0, len(entries))
+	var repos []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			if name == ".git" {
+				continue
+			}
+			if isGitRepo(filepath.Join(dir, name)) {
+				repos = append(repos, name)
+			}
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Workspace snapshot: %s]\n", dir)
+	if len(names) == 0 {
+		b.WriteString("The directory is empty.\n")
+	}
+	for i, name := range names {
+		if maxEntries > 0 && i == maxEntries {
+			fmt.Fprintf(&b, "... and %d more\n", len(names)-maxEntries)
+			break
+		}
+		b.WriteString("- ")
+		b.WriteString(name)
+		b.WriteByte('\n')
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return strings.TrimRight(b.String(), "\n")
+	}
+	gitCtx, cancel := context.WithTimeout(ctx, workspaceGitTimeout)
+	defer cancel()
+	if isGitRepo(dir) {
+		writeGitStatus(gitCtx, &b, dir, ".")
+	}
+	for i, repo := range repos {
+		if i == maxWorkspaceRepos {
+			fmt.Fprintf(&b, "(%d more repositories not shown)\n", len(repos)-maxWorkspaceRepos)
+			break
+		}
+		writeGitStatus(gitCtx, &b, filepath.Join(dir, repo), repo+"/")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func isGitRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// writeGitStatus adds the branch and changed files of the repository at dir.
+// Failures are left out rather than reported, since the snapshot is only a
+// hint.
+func writeGitStatus(ctx context.Context, b *strings.Builder, dir, label string) {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "status", "--short", "--branch")
+	// Do not take the index lock, which could fail a git command the agent
+	// or the user runs at the same time.
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	output, err := cmd.Output()
+	if err != nil {
+		return
+	}
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	branch, changes := strings.TrimPrefix(lines[0], "## "), lines[1:]
+	fmt.Fprintf(b, "Git %s: branch %s", label, branch)
+	if len(changes) == 0 {
+		b.WriteString(", clean\n")
+		return
+	}
+	fmt.Fprintf(b, ", %d changed:\n", len(changes))
+	for i, line := range changes {
+		if i == maxWorkspaceStatusLines {
+			fmt.Fprintf(b, "  ... and %d more\n", len(changes)-maxWorkspaceStatusLines)
+			break
+		}
+		b.WriteString("  ")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestWorkspaceSummaryListsEntriesAndGitStatus(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"notes.md", "todo.txt", "zeta.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	repo := filepath.Join(dir, "app")
+	if err := os.MkdirAll(repo, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	summary := WorkspaceSummary(context.Background(), dir, 2)
+	if !strings.Contains(summary, "- app/\n- notes.md\n... and 2 more") {
+		t.Fatalf("expected capped top-level entries, got:\n%s", summary)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	git := exec.Command("git", "-C", repo, "init", "-q", "-b", "main")
+	if output, err := git.CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v %s", err, output)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("write main.go: %v", err)
+	}
+	summary = WorkspaceSummary(context.Background(), dir, 10)
+	if !strings.Contains(summary, "Git app/: branch ") || !strings.Contains(summary, ", 1 changed:\n  ?? main.go") {
+		t.Fatalf("expected git status of the repository, got:\n%s", summary)
+	}
+}
+
+func TestCodingSessionAddsWorkspaceSnapshot(t *testing.T) {
+	ctx := context.Background()
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "plan.md"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write plan.md: %v", err)
+	}
+	sessionPath := filepath.Join(t.TempDir(), "sessions", "cli", "default.jsonl")
+	modelProvider := &recordingProvider{responses: []*provider.ChatResponse{{Content: "one"}, {Content: "two"}}}
+	contextCfg := config.ContextConfig{WorkspaceSummary: config.WorkspaceSummaryConfig{Enabled: true, MaxEntries: 10}}
+	ag := NewWithSession(modelProvider, tools.NewRegistry(), noopApprover{}, makeAgentDir(t), session.New(sessionPath), mustNewMemoryStore(t, t.TempDir()), 4000, 10, 0, 0, time.Second, contextCfg)
+	ag.ConfigureWorkspace(workspace)
+
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "hi"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if prompt := modelProvider.requests[0].SystemPrompt; strings.Contains(prompt, "Workspace snapshot") {
+		t.Fatalf("expected no snapshot outside a coding session, got %q", prompt)
+	}
+
+	if err := ag.SetCodingSession(true); err != nil {
+		t.Fatalf("set coding session: %v", err)
+	}
+	if err := ag.HandleMessage(ctx, &captureWriter{}, &runtime.Message{Text: "again"}); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if prompt := modelProvider.requests[1].SystemPrompt; !strings.Contains(prompt, "[Workspace snapshot: "+workspace+"]\n- plan.md") {
+		t.Fatalf("expected the workspace snapshot, got %q", prompt)
+	}
+	if usage, _ := ag.LastPromptUsage(); !hasBlock(usage, BlockWorkspace) {
+		t.Fatalf("expected workspace tokens in the usage, got %#v", usage.Blocks)
+	}
+}
+
+func hasBlock(usage PromptUsage, block string) bool {
+	for _, b := range usage.Blocks {
+		if b.Block == block {
+			return true
+		}
+	}
+	return false
+}
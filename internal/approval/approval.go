@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 
@@ -47,6 +48,8 @@ type policyPaths struct {
 	commands string
 	domains  string
 	users    string
+	bins     string
+	overlays string
 }
 
 var (
@@ -54,10 +57,25 @@ var (
 	commandPolicyCache = map[string]commandPolicy{}
 	domainPolicyCache  = map[string]domainPolicy{}
 	usersPolicyCache   = map[string]UsersFile{}
+	binPolicyCache     = map[string]binPolicy{}
+	overlayTrustCache  = map[string]overlayTrust{}
 )
 
 // ExecuteTool enforces permission checks and executes the tool when allowed.
+// The canary tripwire, when armed, applies in every security mode.
 func ExecuteTool(ctx context.Context, approver Approver, tool tools.Tool, args map[string]any, description string) (*tools.ToolResult, error) {
+	trip := tripwire.Load()
+	if err := trip.CheckCall(ctx, tool.Name(), args); err != nil {
+		return nil, err
+	}
+	result, err := executeTool(ctx, approver, tool, args, description)
+	if result != nil {
+		result.Output = trip.CheckOutput(ctx, tool.Name(), result.Output)
+	}
+	return result, err
+}
+
+func executeTool(ctx context.Context, approver Approver, tool tools.Tool, args map[string]any, description string) (*tools.ToolResult, error) {
 	// In danger mode we bypass all approval and policy checks for tool execution.
 	if isDangerMode() {
 		return tool.Execute(ctx, args)
@@ -72,6 +90,10 @@ func ExecuteTool(ctx context.Context, approver Approver, tool tools.Tool, args m
 		}
 		permission = permissionForRunCommand
 	}
+	if reviewer, ok := ctx.Value(codeReviewKey{}).(CodeReviewer); ok && reviewer != nil && reviewsCode(tool.Name()) {
+		description = reviewedDescription(ctx, reviewer, tool.Name(), args, description)
+		permission = tools.RequiresApproval
+	}
 
 	if permission == tools.RequiresApproval {
 		if approver == nil {
@@ -131,41 +153,94 @@ func resolveRunCommandPermission(
 		return tools.RequiresApproval, err
 	}
 
-	switch evaluateCommandPatterns(command, policy.Allow, policy.Deny) {
+	var unlisted []string
+	if mode := unlistedBinsMode(); mode != config.UnlistedBinsAllow {
+		bins, err := loadCachedBinPolicy(paths.bins)
+		if err != nil {
+			return tools.RequiresApproval, err
+		}
+		unlisted = unlistedPrograms(command, bins.Allow)
+		if len(unlisted) > 0 && mode == config.UnlistedBinsDeny {
+			return tools.RequiresApproval, unlistedBinsError(unlisted)
+		}
+	}
+
+	// An allow rule for python or bash says nothing about the script passed
+	// with -c, so inline scripts are asked about every time.
+	var scripts []inlineScript
+	if inlineScriptsMode() != config.InlineScriptsPolicy {
+		scripts = findInlineScripts(command)
+	}
+
+	overlay := overlayFrom(ctx)
+	allow := append(slices.Clone(policy.Allow), overlay.Commands.Allow...)
+	deny := append(slices.Clone(policy.Deny), overlay.Commands.Deny...)
+	switch evaluateCommandPatterns(command, allow, deny) {
 	case commandAllowed:
-		return tools.AutoApprove, nil
+		if len(unlisted) == 0 && len(scripts) == 0 {
+			return tools.AutoApprove, nil
+		}
+		return promptForRunCommandPolicy(ctx, approver, tool.Name(), args, description, paths, policy, command, unlisted, scripts)
 	case commandDenied:
 		return tools.RequiresApproval, toolDeniedError(tool.Name())
 	case commandNoMatch:
-		return promptForRunCommandPolicy(ctx, approver, tool.Name(), args, description, paths.commands, policy, command)
+		return promptForRunCommandPolicy(ctx, approver, tool.Name(), args, description, paths, policy, command, unlisted, scripts)
 	default:
 		return tools.RequiresApproval, nil
 	}
 }
 
 // Prompt for run_command policy decision and persist allow/deny pattern.
+// Commands carrying inline scripts are approved once and never persisted.
 func promptForRunCommandPolicy(
 	ctx context.Context,
 	approver Approver,
 	toolName string,
 	args map[string]any,
 	description string,
-	path string,
+	paths policyPaths,
 	policy commandPolicy,
 	command string,
+	unlisted []string,
+	scripts []inlineScript,
 ) (tools.Permission, error) {
 	if approver == nil {
 		return tools.RequiresApproval, fmt.Errorf("tool %s requires approval but no approver is configured", toolName)
 	}
 
-	pattern, ok := generateCommandPattern(command)
-	if !ok {
-		pattern = strings.TrimSpace(command)
+	// One pattern per segment the allow list does not cover yet, so approving
+	// `git status && rm -rf build` shows and persists both parts.
+	patterns := generateSegmentPatterns(command, policy.Allow)
+	if len(patterns) == 0 {
+		pattern, ok := generateCommandPattern(command)
+		if !ok {
+			pattern = strings.TrimSpace(command)
+		}
+		if pattern != "" {
+			patterns = []string{pattern}
+		}
 	}
 
 	prompt := description
-	if pattern != "" {
-		prompt = fmt.Sprintf("Allow Command: %s", pattern)
+	switch {
+	case len(scripts) > 0:
+		// A pattern like "python3 *" would approve every future script.
+		patterns = nil
+		prompt = fmt.Sprintf("Run Once: %s", strings.TrimSpace(command))
+	case len(patterns) > 0:
+		prompt = fmt.Sprintf("Allow Command: %s", strings.Join(patterns, ", "))
+	}
+	if risks := classifyCommandRisk(command); len(risks) > 0 {
+		prompt = fmt.Sprintf("%s (%s)", prompt, strings.Join(risks, ", "))
+	}
+	if len(unlisted) > 0 {
+		prompt = fmt.Sprintf("%s; new program: %s", prompt, strings.Join(unlisted, ", "))
+	}
+	if len(scripts) > 0 {
+		prompt = fmt.Sprintf("%s\n%s", prompt, formatInlineScripts(scripts))
+		if inlineScriptsMode() == config.InlineScriptsSandbox {
+			prompt += "\n(runs sandboxed to the workspace)"
+		}
 	}
 
 	decision, err := approver.RequestApproval(ctx, ApprovalRequest{
@@ -177,23 +252,40 @@ func promptForRunCommandPolicy(
 		return tools.RequiresApproval, err
 	}
 
+	note := policyNote{actor: approverName(approver), reason: "run_command: " + command}
 	switch decision {
 	case Approved:
-		if pattern != "" {
-			policy.Allow = appendUnique(policy.Allow, pattern)
-			if err := saveCachedCommandPolicy(path, policy); err != nil {
+		if len(patterns) > 0 {
+			for _, pattern := range patterns {
+				policy.Allow = appendUnique(policy.Allow, pattern)
+			}
+			note.summary = "allow command " + strings.Join(patterns, ", ")
+			if err := saveCachedCommandPolicy(paths.commands, policy, note); err != nil {
 				logging.Logger().Warn(
 					"failed to persist command allow pattern",
-					"pattern", pattern,
+					"pattern", strings.Join(patterns, ", "),
+					"err", err,
+				)
+			}
+		}
+		if len(unlisted) > 0 {
+			if err := addAllowedBinaries(paths.bins, unlisted, note); err != nil {
+				logging.Logger().Warn(
+					"failed to persist allowed programs",
+					"programs", strings.Join(unlisted, ", "),
 					"err", err,
 				)
 			}
 		}
 		return tools.AutoApprove, nil
 	case Denied:
-		if pattern != "" {
+		// Denying a compound command says nothing about which part was
+		// unwanted, so only a single pattern is remembered.
+		if len(patterns) == 1 {
+			pattern := patterns[0]
 			policy.Deny = appendUnique(policy.Deny, pattern)
-			if err := saveCachedCommandPolicy(path, policy); err != nil {
+			note.summary = "deny command " + pattern
+			if err := saveCachedCommandPolicy(paths.commands, policy, note); err != nil {
 				logging.Logger().Warn(
 					"failed to persist command deny pattern",
 					"pattern", pattern,
@@ -229,16 +321,56 @@ func FlushPolicies() error {
 	if err != nil {
 		return err
 	}
+	binPolicy, err := loadCachedBinPolicy(paths.bins)
+	if err != nil {
+		return err
+	}
+	overlayTrust, err := loadCachedOverlayTrust(paths.overlays)
+	if err != nil {
+		return err
+	}
+
+	// Anything on disk that differs from the cache was written by someone
+	// else, most likely the command that just ran. Journal what the flush
+	// reverts so the edit stays visible.
+	journalErr := journalFlush(paths.commands, loadCommandPolicy, commandPolicy)
+	journalErr = errors.Join(journalErr, journalFlush(paths.domains, loadDomainPolicy, domainPolicy))
+	journalErr = errors.Join(journalErr, journalFlush(paths.users, LoadUsers, usersPolicy))
+	journalErr = errors.Join(journalErr, journalFlush(paths.bins, loadBinPolicy, binPolicy))
+	journalErr = errors.Join(journalErr, journalFlush(paths.overlays, loadOverlayTrust, overlayTrust))
+	if journalErr != nil {
+		logging.Logger().Warn("failed to journal policy flush", "err", journalErr)
+	}
 
 	flushErr := saveCommandPolicy(paths.commands, commandPolicy)
 	flushErr = errors.Join(flushErr, saveDomainPolicy(paths.domains, domainPolicy))
 	flushErr = errors.Join(flushErr, saveUsers(paths.users, usersPolicy))
+	flushErr = errors.Join(flushErr, saveBinPolicy(paths.bins, binPolicy))
+	flushErr = errors.Join(flushErr, saveOverlayTrust(paths.overlays, overlayTrust))
 	if flushErr != nil {
 		return fmt.Errorf("flush policies: %w", flushErr)
 	}
 	return nil
 }
 
+// journalFlush journals the difference between the policy file at path and
+// the cached policy a flush is about to write over it. A missing file counts
+// as empty; an unreadable one is not journaled.
+func journalFlush[T any](path string, load func(string) (T, error), cached T) error {
+	onDisk, err := load(path)
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		logging.Logger().Warn("policy file unreadable before flush; not journaling it", "path", path, "err", err)
+		return nil
+	}
+	return journalPolicyChange(path, onDisk, cached, policyNote{
+		actor:   "flush",
+		summary: "restore after an outside edit",
+	})
+}
+
 // Resolve policy file paths from config.
 func currentPolicyPaths() (policyPaths, error) {
 	cfg, err := config.Load()
@@ -249,6 +381,8 @@ func currentPolicyPaths() (policyPaths, error) {
 		commands: cfg.AllowedCommandsPath(),
 		domains:  cfg.AllowedDomainsPath(),
 		users:    cfg.AllowedUsersPath(),
+		bins:     cfg.AllowedBinsPath(),
+		overlays: cfg.TrustedOverlaysPath(),
 	}, nil
 }
 
@@ -267,7 +401,7 @@ func isDangerMode() bool {
 	return strings.EqualFold(strings.TrimSpace(cfg.Security.Mode), config.SecurityModeDanger)
 }
 
-// Ensure both command and domain policy are loaded into in-memory cache.
+// Ensure every policy file is loaded into in-memory cache.
 func ensurePolicyCacheLoaded(paths policyPaths) error {
 	if _, err := loadCachedCommandPolicy(paths.commands); err != nil {
 		return err
@@ -278,6 +412,12 @@ func ensurePolicyCacheLoaded(paths policyPaths) error {
 	if _, err := loadCachedUsersFile(paths.users); err != nil {
 		return err
 	}
+	if _, err := loadCachedBinPolicy(paths.bins); err != nil {
+		return err
+	}
+	if _, err := loadCachedOverlayTrust(paths.overlays); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -302,9 +442,16 @@ func loadCachedCommandPolicy(path string) (commandPolicy, error) {
 	return cloneCommandPolicy(policy), nil
 }
 
-// Persist command policy and update in-memory cache.
-func saveCachedCommandPolicy(path string, policy commandPolicy) error {
+// Journal the change, then persist command policy and update in-memory cache.
+func saveCachedCommandPolicy(path string, policy commandPolicy, note policyNote) error {
+	before, err := loadCachedCommandPolicy(path)
+	if err != nil {
+		return err
+	}
 	copied := cloneCommandPolicy(policy)
+	if err := journalPolicyChange(path, before, copied, note); err != nil {
+		return err
+	}
 
 	policyCacheMu.Lock()
 	commandPolicyCache[path] = copied
@@ -337,9 +484,16 @@ func loadCachedDomainPolicy(path string) (domainPolicy, error) {
 	return cloneDomainPolicy(policy), nil
 }
 
-// Persist domain policy and update in-memory cache.
-func saveCachedDomainPolicy(path string, policy domainPolicy) error {
+// Journal the change, then persist domain policy and update in-memory cache.
+func saveCachedDomainPolicy(path string, policy domainPolicy, note policyNote) error {
+	before, err := loadCachedDomainPolicy(path)
+	if err != nil {
+		return err
+	}
 	copied := cloneDomainPolicy(policy)
+	if err := journalPolicyChange(path, before, copied, note); err != nil {
+		return err
+	}
 
 	policyCacheMu.Lock()
 	domainPolicyCache[path] = copied
@@ -368,9 +522,16 @@ func loadCachedUsersFile(path string) (UsersFile, error) {
 	return cloneUsersFile(usersFile), nil
 }
 
-// Persist allowed users and update in-memory cache.
-func saveCachedUsersFile(path string, usersFile UsersFile) error {
+// Journal the change, then persist allowed users and update in-memory cache.
+func saveCachedUsersFile(path string, usersFile UsersFile, note policyNote) error {
+	before, err := loadCachedUsersFile(path)
+	if err != nil {
+		return err
+	}
 	copied := cloneUsersFile(usersFile)
+	if err := journalPolicyChange(path, before, copied, note); err != nil {
+		return err
+	}
 
 	policyCacheMu.Lock()
 	usersPolicyCache[path] = copied
@@ -412,6 +573,8 @@ func resetPolicyCache() {
 	commandPolicyCache = map[string]commandPolicy{}
 	domainPolicyCache = map[string]domainPolicy{}
 	usersPolicyCache = map[string]UsersFile{}
+	binPolicyCache = map[string]binPolicy{}
+	overlayTrustCache = map[string]overlayTrust{}
 }
 
 // Load command policy from disk.
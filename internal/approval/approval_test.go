@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -200,6 +201,41 @@ func TestExecuteTool_RunCommandDenyPatternAutoDenies(t *testing.T) {
 	}
 }
 
+func TestExecuteTool_RunCommandScopedAllowPatternsAreNotPersisted(t *testing.T) {
+	useIsolatedPolicyCache(t)
+
+	dataDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", dataDir)
+	writeCommandPolicyFile(t, dataDir, commandPolicy{
+		Allow: []string{"git status"},
+		Deny:  []string{"npm publish *"},
+	})
+
+	appr := &fakeApprover{decision: Denied}
+	tool := fakeTool{name: "run_command", permission: tools.RequiresApproval, output: "done"}
+	ctx := WithOverlay(context.Background(), Overlay{Commands: Rules{Allow: []string{"npm *"}}})
+	if _, err := ExecuteTool(ctx, appr, tool, map[string]any{"command": "git status && npm test"}, "Run: npm test"); err != nil {
+		t.Fatalf("execute tool: %v", err)
+	}
+	if appr.calls != 0 {
+		t.Fatalf("expected no prompt for a scoped pattern, got %d prompts", appr.calls)
+	}
+	if _, err := ExecuteTool(ctx, appr, tool, map[string]any{"command": "npm publish --access public"}, "Run: npm publish"); err == nil {
+		t.Fatal("expected deny rules to win over scoped patterns")
+	}
+	if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": "npm test"}, "Run: npm test"); err == nil || appr.calls != 1 {
+		t.Fatalf("expected a prompt without the scope, got err=%v prompts=%d", err, appr.calls)
+	}
+	cfg := &config.Config{HomeDir: dataDir, Agent: "default"}
+	policy, err := loadCommandPolicy(cfg.AllowedCommandsPath())
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	if slices.Contains(policy.Allow, "npm *") {
+		t.Fatalf("expected scoped patterns to stay out of the policy file, got %#v", policy.Allow)
+	}
+}
+
 func TestExecuteTool_RunCommandNoMatchPromptsWithPatternAndPersistsAllow(t *testing.T) {
 	useIsolatedPolicyCache(t)
 
@@ -225,7 +261,7 @@ func TestExecuteTool_RunCommandNoMatchPromptsWithPatternAndPersistsAllow(t *test
 	if appr.calls != 1 {
 		t.Fatalf("expected one prompt, got %d", appr.calls)
 	}
-	if appr.lastReq.Description != "Allow Command: git commit *" {
+	if appr.lastReq.Description != "Allow Command: git commit * (writes files)" {
 		t.Fatalf("expected generated pattern prompt, got %q", appr.lastReq.Description)
 	}
 
@@ -235,6 +271,46 @@ func TestExecuteTool_RunCommandNoMatchPromptsWithPatternAndPersistsAllow(t *test
 	}
 }
 
+func TestExecuteTool_RunCommandCompoundPromptsForEachUncoveredSegment(t *testing.T) {
+	useIsolatedPolicyCache(t)
+
+	dataDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", dataDir)
+	writeCommandPolicyFile(t, dataDir, commandPolicy{Allow: []string{"git *"}})
+
+	tool := fakeTool{name: "run_command", permission: tools.RequiresApproval, output: "done"}
+	denier := &fakeApprover{decision: Denied}
+	if _, err := ExecuteTool(context.Background(), denier, tool, map[string]any{"command": "git status && rm -rf build"}, "Run"); err == nil {
+		t.Fatal("expected denial")
+	}
+	if denier.lastReq.Description != "Allow Command: rm * (reads files, deletes)" {
+		t.Fatalf("expected prompt for the uncovered segment, got %q", denier.lastReq.Description)
+	}
+	if policy := readCommandPolicyFile(t, dataDir); !containsString(policy.Deny, "rm *") {
+		t.Fatalf("expected the single uncovered pattern denied, got %#v", policy.Deny)
+	}
+
+	appr := &fakeApprover{decision: Approved}
+	if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": "git status; ls build | wc -l"}, "Run"); err != nil {
+		t.Fatalf("execute tool: %v", err)
+	}
+	if appr.lastReq.Description != "Allow Command: ls build, wc * (reads files)" {
+		t.Fatalf("expected both uncovered patterns in prompt, got %q", appr.lastReq.Description)
+	}
+	policy := readCommandPolicyFile(t, dataDir)
+	if !containsString(policy.Allow, "ls build") || !containsString(policy.Allow, "wc *") {
+		t.Fatalf("expected both patterns persisted, got %#v", policy.Allow)
+	}
+
+	denier = &fakeApprover{decision: Denied}
+	if _, err := ExecuteTool(context.Background(), denier, tool, map[string]any{"command": "make && make install"}, "Run"); err == nil {
+		t.Fatal("expected denial")
+	}
+	if policy := readCommandPolicyFile(t, dataDir); len(policy.Deny) != 1 {
+		t.Fatalf("expected compound denial not persisted, got %#v", policy.Deny)
+	}
+}
+
 func TestExecuteTool_RunCommandNoMatchPromptsAndPersistsDeny(t *testing.T) {
 	useIsolatedPolicyCache(t)
 
@@ -0,0 +1,184 @@
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// binPolicy is the on-disk shape of allowed_bins.json.
+type binPolicy struct {
+	Allow []string `json:"allow"`
+}
+
+// shellBuiltins run inside the shell, so they are never checked against
+// allowed_bins.json. Builtins that run other programs or files (exec,
+// eval, source, .) are not listed.
+var shellBuiltins = map[string]bool{
+	"cd": true, "echo": true, "printf": true, "pwd": true, "test": true,
+	"[": true, "true": true, "false": true, ":": true, "export": true,
+	"set": true, "unset": true, "read": true, "exit": true, "return": true,
+	"shift": true, "local": true, "declare": true, "readonly": true,
+	"typeset": true, "umask": true, "wait": true, "type": true,
+}
+
+// unlistedBinsMode returns the configured security.unlisted_bins value.
+func unlistedBinsMode() string {
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Logger().Warn("failed to load config for unlisted bins check", "err", err)
+		return config.UnlistedBinsAllow
+	}
+	mode := strings.ToLower(strings.TrimSpace(cfg.Security.UnlistedBins))
+	if mode == "" {
+		return config.UnlistedBinsAllow
+	}
+	return mode
+}
+
+// unlistedPrograms returns the programs a command line runs that are not in
+// allowed, in order and without duplicates. Wrappers such as sudo or xargs
+// count as programs, and so does the program they run. Programs are
+// compared by base name. A command that cannot be parsed returns itself so
+// it is never treated as fully listed.
+func unlistedPrograms(command string, allowed []string) []string {
+	segments, _, err := parseShellCommand(command)
+	if err != nil {
+		return []string{strings.TrimSpace(command)}
+	}
+	var unlisted []string
+	for _, tokens := range expandShellScripts(segments, 0) {
+		for _, program := range segmentPrograms(tokens) {
+			if shellBuiltins[program] || slices.ContainsFunc(allowed, func(bin string) bool {
+				return filepath.Base(strings.TrimSpace(bin)) == program
+			}) {
+				continue
+			}
+			unlisted = appendUnique(unlisted, program)
+		}
+	}
+	return unlisted
+}
+
+// segmentPrograms lists the program a simple command runs, preceded by any
+// wrappers around it.
+func segmentPrograms(tokens []string) []string {
+	var programs []string
+	for len(tokens) > 0 {
+		program := filepath.Base(tokens[0])
+		programs = append(programs, program)
+		if !commandWrappers[program] {
+			break
+		}
+		tokens = stripCommandWrapper(tokens)
+	}
+	return programs
+}
+
+// addAllowedBinaries adds programs to allowed_bins.json after the user
+// approves a command that runs them.
+func addAllowedBinaries(path string, programs []string, note policyNote) error {
+	policy, err := loadCachedBinPolicy(path)
+	if err != nil {
+		return err
+	}
+	for _, program := range programs {
+		policy.Allow = appendUnique(policy.Allow, program)
+	}
+	note.summary = "allow program " + strings.Join(programs, ", ")
+	return saveCachedBinPolicy(path, policy, note)
+}
+
+// unlistedBinsError tells the model why a command was refused.
+func unlistedBinsError(programs []string) error {
+	return fmt.Errorf(
+		"run_command refused: %s not in %s and security.unlisted_bins is deny. Try a different approach or ask the user to allow the program",
+		strings.Join(programs, ", "),
+		config.AllowedBinsFileName,
+	)
+}
+
+// Load allowed bins from in-memory cache, lazy-loading from disk once.
+func loadCachedBinPolicy(path string) (binPolicy, error) {
+	policyCacheMu.Lock()
+	defer policyCacheMu.Unlock()
+
+	if policy, ok := binPolicyCache[path]; ok {
+		return cloneBinPolicy(policy), nil
+	}
+
+	policy, err := loadBinPolicy(path)
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		policy = binPolicy{}
+	default:
+		return binPolicy{}, err
+	}
+	binPolicyCache[path] = cloneBinPolicy(policy)
+	return cloneBinPolicy(policy), nil
+}
+
+// Journal the change, then persist allowed bins and update in-memory cache.
+func saveCachedBinPolicy(path string, policy binPolicy, note policyNote) error {
+	before, err := loadCachedBinPolicy(path)
+	if err != nil {
+		return err
+	}
+	copied := cloneBinPolicy(policy)
+	if err := journalPolicyChange(path, before, copied, note); err != nil {
+		return err
+	}
+
+	policyCacheMu.Lock()
+	binPolicyCache[path] = copied
+	policyCacheMu.Unlock()
+
+	return saveBinPolicy(path, copied)
+}
+
+// Copy bin policy slices before returning/storing.
+func cloneBinPolicy(policy binPolicy) binPolicy {
+	return binPolicy{Allow: append([]string(nil), policy.Allow...)}
+}
+
+// Load allowed bins from disk.
+func loadBinPolicy(path string) (binPolicy, error) {
+	raw, err := store.ReadFile(path)
+	if err != nil {
+		return binPolicy{}, err
+	}
+	if strings.TrimSpace(raw) == "" {
+		return binPolicy{}, nil
+	}
+
+	var policy binPolicy
+	if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+		return binPolicy{}, fmt.Errorf("decode bin policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// Save allowed bins to disk.
+func saveBinPolicy(path string, policy binPolicy) error {
+	if policy.Allow == nil {
+		policy.Allow = []string{}
+	}
+	encoded, err := json.MarshalIndent(policy, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode bin policy: %w", err)
+	}
+	encoded = append(encoded, '\n')
+	if err := store.WriteFile(path, encoded); err != nil {
+		return fmt.Errorf("write bin policy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package approval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestUnlistedPrograms(t *testing.T) {
+	allowed := []string{"git", "/usr/bin/grep", "sudo"}
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{command: "git status", want: ""},
+		{command: "cd repo && git status | grep main", want: ""},
+		{command: "git status && rm -rf build", want: "rm"},
+		{command: "sudo env FOO=1 python3 x.py", want: "env, python3"},
+		{command: "echo $(whoami) > out.txt", want: "whoami"},
+		{command: "./build.sh; ./build.sh", want: "build.sh"},
+		{command: `git commit -m "unterminated`, want: `git commit -m "unterminated`},
+	}
+	for _, test := range tests {
+		if got := strings.Join(unlistedPrograms(test.command, allowed), ", "); got != test.want {
+			t.Errorf("unlistedPrograms(%q) = %q, want %q", test.command, got, test.want)
+		}
+	}
+}
+
+func TestExecuteTool_UnlistedBinsDenyRefusesUnknownProgram(t *testing.T) {
+	useIsolatedPolicyCache(t)
+
+	homeDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", homeDir)
+	writeUnlistedBinsConfig(t, homeDir, config.UnlistedBinsDeny)
+	writeCommandPolicyFile(t, homeDir, commandPolicy{Allow: []string{"git *", "rm *"}})
+	writeBinPolicyFile(t, homeDir, `{"allow": ["git"]}`)
+
+	appr := &fakeApprover{decision: Approved}
+	tool := fakeTool{name: "run_command", permission: tools.RequiresApproval, output: "done"}
+	if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": "git status"}, "Run"); err != nil {
+		t.Fatalf("expected listed program to run, got %v", err)
+	}
+	_, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": "git status && rm -rf /"}, "Run")
+	if err == nil || !strings.Contains(err.Error(), "rm not in allowed_bins.json") {
+		t.Fatalf("expected unlisted program refused, got %v", err)
+	}
+	if appr.calls != 0 {
+		t.Fatalf("expected no prompt in deny mode, got %d", appr.calls)
+	}
+}
+
+func TestExecuteTool_UnlistedBinsPromptAsksAndRemembersProgram(t *testing.T) {
+	useIsolatedPolicyCache(t)
+
+	homeDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", homeDir)
+	writeUnlistedBinsConfig(t, homeDir, config.UnlistedBinsPrompt)
+	writeCommandPolicyFile(t, homeDir, commandPolicy{Allow: []string{"jq *"}})
+	writeBinPolicyFile(t, homeDir, `{"allow": []}`)
+
+	appr := &fakeApprover{decision: Approved}
+	tool := fakeTool{name: "run_command", permission: tools.RequiresApproval, output: "done"}
+	if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": "jq . data.json"}, "Run"); err != nil {
+		t.Fatalf("execute tool: %v", err)
+	}
+	if appr.calls != 1 {
+		t.Fatalf("expected a prompt despite the allow pattern, got %d", appr.calls)
+	}
+	if !strings.HasSuffix(appr.lastReq.Description, "; new program: jq") {
+		t.Fatalf("expected new program in prompt, got %q", appr.lastReq.Description)
+	}
+
+	if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": "jq .name data.json"}, "Run"); err != nil {
+		t.Fatalf("execute tool: %v", err)
+	}
+	if appr.calls != 1 {
+		t.Fatalf("expected approved program remembered, got %d prompts", appr.calls)
+	}
+
+	cfg := &config.Config{HomeDir: homeDir, Agent: "default"}
+	policy, err := loadBinPolicy(cfg.AllowedBinsPath())
+	if err != nil || !containsString(policy.Allow, "jq") {
+		t.Fatalf("expected jq persisted, got %#v %v", policy, err)
+	}
+}
+
+func writeUnlistedBinsConfig(t *testing.T, homeDir, mode string) {
+	t.Helper()
+	content := "[security]\nmode = \"standard\"\nunlisted_bins = \"" + mode + "\"\n"
+	if err := os.WriteFile(filepath.Join(homeDir, config.ConfigFilePath), []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func writeBinPolicyFile(t *testing.T, homeDir, content string) {
+	t.Helper()
+	cfg := &config.Config{HomeDir: homeDir, Agent: "default"}
+	path := cfg.AllowedBinsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir policy dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write bin policy: %v", err)
+	}
+}
@@ -33,6 +33,11 @@ func NewCLIApproverFromReader(in *bufio.Reader, out io.Writer) *CLIApprover {
 	}
 }
 
+// ApproverName names the CLI user who answers approval prompts.
+func (a *CLIApprover) ApproverName() string {
+	return "cli"
+}
+
 // RequestApproval prompts once and returns Approved or Denied.
 func (a *CLIApprover) RequestApproval(_ context.Context, req ApprovalRequest) (ApprovalDecision, error) {
 	fmt.Fprint(a.out, FormatApprovalPrompt(req))
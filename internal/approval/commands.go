@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/google/shlex"
+	"mvdan.cc/sh/v3/syntax"
 )
 
 type commandMatchDecision int
@@ -14,20 +15,103 @@ const (
 	commandDenied
 )
 
-// Apply deny-first, then allow, over tokenized command patterns.
+// Apply deny-first, then allow, to every simple command in a shell command
+// line. Pipelines, lists, subshells, command substitutions, and sh -c
+// payloads are split apart, so one segment matching an allow rule cannot
+// carry the others: any segment matching deny denies, and all segments must
+// match allow.
 func evaluateCommandPatterns(command string, allowPatterns, denyPatterns []string) commandMatchDecision {
-	commandTokens, err := tokenizeCommand(command)
-	if err != nil || len(commandTokens) == 0 {
+	segments, _, err := parseShellCommand(command)
+	if err != nil || len(segments) == 0 {
 		return commandNoMatch
 	}
+	segments = expandShellScripts(segments, 0)
 
-	if matchCommandPatterns(denyPatterns, commandTokens) {
-		return commandDenied
+	for _, segment := range segments {
+		if matchCommandPatterns(denyPatterns, segment) {
+			return commandDenied
+		}
+	}
+	for _, segment := range segments {
+		if !matchCommandPatterns(allowPatterns, segment) {
+			return commandNoMatch
+		}
+	}
+	return commandAllowed
+}
+
+// parseShellCommand parses a command line into the argument tokens of each
+// simple command it runs, in source order, and reports whether any
+// redirection writes to a file.
+func parseShellCommand(command string) ([][]string, bool, error) {
+	file, err := syntax.NewParser().Parse(strings.NewReader(command), "")
+	if err != nil {
+		return nil, false, err
+	}
+
+	var segments [][]string
+	writesFile := false
+	syntax.Walk(file, func(node syntax.Node) bool {
+		switch node := node.(type) {
+		case *syntax.CallExpr:
+			// Leading KEY=value assignments are in Assigns, not Args.
+			if len(node.Args) > 0 {
+				tokens := make([]string, 0, len(node.Args))
+				for _, word := range node.Args {
+					tokens = append(tokens, shellWordString(word))
+				}
+				segments = append(segments, tokens)
+			}
+		case *syntax.DeclClause:
+			tokens := []string{node.Variant.Value}
+			for _, assign := range node.Args {
+				tokens = append(tokens, shellNodeString(assign))
+			}
+			segments = append(segments, tokens)
+		case *syntax.Redirect:
+			switch node.Op {
+			case syntax.RdrOut, syntax.AppOut, syntax.ClbOut, syntax.RdrAll, syntax.AppAll:
+				if node.Word == nil || node.Word.Lit() != "/dev/null" {
+					writesFile = true
+				}
+			}
+		}
+		return true
+	})
+	return segments, writesFile, nil
+}
+
+// shellWordString renders a word as the shell would pass it when it is
+// literal, removing quotes, and as source text otherwise.
+func shellWordString(word *syntax.Word) string {
+	var b strings.Builder
+	for _, part := range word.Parts {
+		switch part := part.(type) {
+		case *syntax.Lit:
+			b.WriteString(part.Value)
+		case *syntax.SglQuoted:
+			b.WriteString(part.Value)
+		case *syntax.DblQuoted:
+			for _, inner := range part.Parts {
+				if lit, ok := inner.(*syntax.Lit); ok {
+					b.WriteString(lit.Value)
+				} else {
+					b.WriteString(shellNodeString(inner))
+				}
+			}
+		default:
+			b.WriteString(shellNodeString(part))
+		}
 	}
-	if matchCommandPatterns(allowPatterns, commandTokens) {
-		return commandAllowed
+	return b.String()
+}
+
+func shellNodeString(node syntax.Node) string {
+	var b strings.Builder
+	if err := syntax.NewPrinter().Print(&b, node); err != nil {
+		return ""
 	}
-	return commandNoMatch
+	return b.String()
 }
 
 // Check whether any pattern matches the tokenized command.
@@ -69,7 +153,35 @@ func matchPatternTokens(patternTokens, commandTokens []string) bool {
 // Derive a persistent approval pattern from a raw command string.
 func generateCommandPattern(command string) (string, bool) {
 	tokens, err := tokenizeCommand(command)
-	if err != nil || len(tokens) == 0 {
+	if err != nil {
+		return "", false
+	}
+	return patternFromTokens(tokens)
+}
+
+// Derive approval patterns for the segments of a command line that allow
+// does not already cover, without duplicates.
+func generateSegmentPatterns(command string, allowPatterns []string) []string {
+	segments, _, err := parseShellCommand(command)
+	if err != nil {
+		return nil
+	}
+	segments = expandShellScripts(segments, 0)
+	var patterns []string
+	for _, segment := range segments {
+		if matchCommandPatterns(allowPatterns, segment) {
+			continue
+		}
+		if pattern, ok := patternFromTokens(segment); ok {
+			patterns = appendUnique(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// Keep tokens up to the first flag, then end with a wildcard if any followed.
+func patternFromTokens(tokens []string) (string, bool) {
+	if len(tokens) == 0 {
 		return "", false
 	}
 
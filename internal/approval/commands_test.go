@@ -1,6 +1,9 @@
 package approval
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestEvaluateCommandPatterns_ExactMatch(t *testing.T) {
 	tests := []struct {
@@ -113,16 +116,16 @@ func TestEvaluateCommandPatterns_Wildcards(t *testing.T) {
 			expected: commandNoMatch,
 		},
 		{
-			name:     "wildcard matches operators",
+			name:     "wildcard does not span operators",
 			command:  `git commit -m "x" && echo done`,
 			pattern:  "git commit *",
-			expected: commandAllowed,
+			expected: commandNoMatch,
 		},
 		{
-			name:     "wildcard matches subshell token",
+			name:     "wildcard does not cover command substitution",
 			command:  `git commit -m "$(cat msg.txt)"`,
 			pattern:  "git commit *",
-			expected: commandAllowed,
+			expected: commandNoMatch,
 		},
 		{
 			name:     "quoted args preserved",
@@ -207,6 +210,83 @@ func TestEvaluateCommandPatterns_EnvPrefixAndPrecedence(t *testing.T) {
 	}
 }
 
+func TestEvaluateCommandPatterns_ShellSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		allow    []string
+		deny     []string
+		expected commandMatchDecision
+	}{
+		{
+			name:     "allow does not carry a chained command",
+			command:  "git status && rm -rf /",
+			allow:    []string{"git *"},
+			expected: commandNoMatch,
+		},
+		{
+			name:     "deny matches any segment",
+			command:  "git status && rm -rf /",
+			allow:    []string{"git *", "rm *"},
+			deny:     []string{"rm -rf *"},
+			expected: commandDenied,
+		},
+		{
+			name:     "all segments allowed",
+			command:  `git commit -m "x" && echo done`,
+			allow:    []string{"git commit *", "echo *"},
+			expected: commandAllowed,
+		},
+		{
+			name:     "pipeline segments",
+			command:  "cat notes.md | grep todo | wc -l",
+			allow:    []string{"cat *", "grep *"},
+			expected: commandNoMatch,
+		},
+		{
+			name:     "command substitution evaluated",
+			command:  `git commit -m "$(cat msg.txt)"`,
+			allow:    []string{"git commit *", "cat *"},
+			expected: commandAllowed,
+		},
+		{
+			name:     "denied command inside substitution",
+			command:  "echo $(curl https://example.com)",
+			allow:    []string{"echo *", "curl *"},
+			deny:     []string{"curl *"},
+			expected: commandDenied,
+		},
+		{
+			name:     "subshell and semicolons",
+			command:  "(cd build; make) ; ls",
+			allow:    []string{"cd *", "make", "ls"},
+			expected: commandAllowed,
+		},
+		{
+			name:     "operator inside quotes is one argument",
+			command:  `grep "a && b" notes.md`,
+			allow:    []string{"grep *"},
+			expected: commandAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateCommandPatterns(tt.command, tt.allow, tt.deny)
+			if got != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateSegmentPatterns(t *testing.T) {
+	got := generateSegmentPatterns("git status && rm -rf build | tee log && git status", []string{"tee *"})
+	if strings.Join(got, ", ") != "git status, rm *" {
+		t.Fatalf("unexpected patterns %q", got)
+	}
+}
+
 func TestGenerateCommandPattern(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/neoclaw-ai/neoclaw/internal/store"
@@ -48,7 +49,12 @@ func (c Checker) Allow(ctx context.Context, host string) error {
 		return err
 	}
 
-	switch evaluateDomainPolicy(target, policy) {
+	overlay := overlayFrom(ctx)
+	effective := domainPolicy{
+		Allow: append(slices.Clone(policy.Allow), overlay.Domains.Allow...),
+		Deny:  append(slices.Clone(policy.Deny), overlay.Domains.Deny...),
+	}
+	switch evaluateDomainPolicy(target, effective) {
 	case domainAllowed:
 		return nil
 	case domainDenied:
@@ -72,13 +78,16 @@ func (c Checker) Allow(ctx context.Context, host string) error {
 		return err
 	}
 
+	note := policyNote{actor: approverName(c.Approver), reason: "network request to " + target}
 	switch decision {
 	case Approved:
 		policy.Allow = appendUnique(policy.Allow, target)
-		return saveCachedDomainPolicy(c.AllowedDomainsPath, policy)
+		note.summary = "allow domain " + target
+		return saveCachedDomainPolicy(c.AllowedDomainsPath, policy, note)
 	case Denied:
 		policy.Deny = appendUnique(policy.Deny, target)
-		if err := saveCachedDomainPolicy(c.AllowedDomainsPath, policy); err != nil {
+		note.summary = "deny domain " + target
+		if err := saveCachedDomainPolicy(c.AllowedDomainsPath, policy, note); err != nil {
 			return err
 		}
 		return toolDeniedError("network_domain")
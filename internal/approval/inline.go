@@ -0,0 +1,196 @@
+package approval
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+// maxInlineScriptDepth bounds how far sh -c payloads nested inside other
+// sh -c payloads are unpacked.
+const maxInlineScriptDepth = 4
+
+// inlineScript is a program passed on an interpreter's command line, such
+// as the payload of python -c.
+type inlineScript struct {
+	interpreter string
+	script      string
+}
+
+// inlineScriptFlags maps interpreters to the single-letter flags that take
+// an inline script. Shells read the script from the first operand after -c.
+var inlineScriptFlags = map[string]string{
+	"python": "c", "node": "ep", "bun": "e", "perl": "eE", "ruby": "e",
+	"php": "r", "bash": "c", "sh": "c", "zsh": "c", "dash": "c", "ksh": "c",
+}
+
+// inlineScriptLongFlags are the long flags that take an inline script.
+var inlineScriptLongFlags = map[string][]string{
+	"node": {"--eval", "--print"},
+	"bun":  {"--eval", "--print"},
+}
+
+// shellInterpreters run their inline script as shell source, so its commands
+// are checked like the rest of the command line.
+var shellInterpreters = map[string]bool{
+	"bash": true, "sh": true, "zsh": true, "dash": true, "ksh": true,
+}
+
+// inlineScriptsMode returns the configured security.inline_scripts value.
+func inlineScriptsMode() string {
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Logger().Warn("failed to load config for inline script check", "err", err)
+		return config.InlineScriptsPrompt
+	}
+	mode := strings.ToLower(strings.TrimSpace(cfg.Security.InlineScripts))
+	if mode == "" {
+		return config.InlineScriptsPrompt
+	}
+	return mode
+}
+
+// SandboxesCommand reports whether run_command must run command in the
+// per-command sandbox, which security.inline_scripts = "sandbox" asks for
+// every command carrying an inline script. Danger mode never sandboxes.
+func SandboxesCommand(command string) bool {
+	return !isDangerMode() && inlineScriptsMode() == config.InlineScriptsSandbox && len(findInlineScripts(command)) > 0
+}
+
+// findInlineScripts returns the inline scripts a command line passes to
+// interpreters, in source order, including scripts nested in sh -c
+// payloads. A command that cannot be parsed has none.
+func findInlineScripts(command string) []inlineScript {
+	return inlineScriptsIn(command, 0)
+}
+
+func inlineScriptsIn(command string, depth int) []inlineScript {
+	segments, _, err := parseShellCommand(command)
+	if err != nil {
+		return nil
+	}
+	var scripts []inlineScript
+	for _, tokens := range segments {
+		script, ok := segmentInlineScript(stripCommandWrappers(tokens))
+		if !ok {
+			continue
+		}
+		scripts = append(scripts, script)
+		if shellInterpreters[script.interpreter] && depth+1 < maxInlineScriptDepth {
+			scripts = append(scripts, inlineScriptsIn(script.script, depth+1)...)
+		}
+	}
+	return scripts
+}
+
+// segmentInlineScript extracts the inline script from one simple command.
+// Options are scanned up to the first operand, which for every supported
+// interpreter names a script file instead.
+func segmentInlineScript(tokens []string) (inlineScript, bool) {
+	if len(tokens) == 0 {
+		return inlineScript{}, false
+	}
+	interpreter := interpreterName(tokens[0])
+	flags, ok := inlineScriptFlags[interpreter]
+	if !ok {
+		return inlineScript{}, false
+	}
+	args := tokens[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") || arg == "-" {
+			return inlineScript{}, false
+		}
+		for _, long := range inlineScriptLongFlags[interpreter] {
+			if arg == long && i+1 < len(args) {
+				return inlineScript{interpreter: interpreter, script: args[i+1]}, true
+			}
+			if value, ok := strings.CutPrefix(arg, long+"="); ok {
+				return inlineScript{interpreter: interpreter, script: value}, true
+			}
+		}
+		if strings.HasPrefix(arg, "--") {
+			continue
+		}
+		cluster := arg[1:]
+		at := strings.IndexAny(cluster, flags)
+		if at < 0 {
+			continue
+		}
+		if shellInterpreters[interpreter] {
+			// -c is a mode, not an option with a value: the script is the
+			// first operand, even when more options follow.
+			for j := i + 1; j < len(args); j++ {
+				if !strings.HasPrefix(args[j], "-") {
+					return inlineScript{interpreter: interpreter, script: args[j]}, true
+				}
+			}
+			return inlineScript{}, false
+		}
+		if rest := cluster[at+1:]; rest != "" {
+			// python -c'print(1)' and perl -e'print 1' attach the script.
+			return inlineScript{interpreter: interpreter, script: rest}, true
+		}
+		if i+1 < len(args) {
+			return inlineScript{interpreter: interpreter, script: args[i+1]}, true
+		}
+		return inlineScript{}, false
+	}
+	return inlineScript{}, false
+}
+
+// interpreterName folds versioned names such as python3.12 and nodejs into
+// the keys of inlineScriptFlags.
+func interpreterName(program string) string {
+	name := filepath.Base(program)
+	switch {
+	case strings.HasPrefix(name, "python"):
+		return "python"
+	case name == "nodejs":
+		return "node"
+	case strings.HasPrefix(name, "perl"):
+		return "perl"
+	case strings.HasPrefix(name, "ruby"):
+		return "ruby"
+	case strings.HasPrefix(name, "php"):
+		return "php"
+	}
+	return name
+}
+
+// expandShellScripts appends the simple commands inside sh -c payloads to
+// segments, so policy rules reach commands hidden in a shell string.
+func expandShellScripts(segments [][]string, depth int) [][]string {
+	if depth >= maxInlineScriptDepth {
+		return segments
+	}
+	expanded := segments
+	for _, tokens := range segments {
+		script, ok := segmentInlineScript(stripCommandWrappers(tokens))
+		if !ok || !shellInterpreters[script.interpreter] {
+			continue
+		}
+		inner, _, err := parseShellCommand(script.script)
+		if err != nil {
+			continue
+		}
+		expanded = append(expanded, expandShellScripts(inner, depth+1)...)
+	}
+	return expanded
+}
+
+// formatInlineScripts renders scripts for an approval prompt in full, since
+// the outer command says nothing about what they do.
+func formatInlineScripts(scripts []inlineScript) string {
+	var b strings.Builder
+	for i, script := range scripts {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s script:\n%s", script.interpreter, strings.TrimSpace(script.script))
+	}
+	return b.String()
+}
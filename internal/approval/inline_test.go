@@ -0,0 +1,108 @@
+package approval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestFindInlineScripts(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{command: `python3 -c 'print(1)'`, want: []string{"python: print(1)"}},
+		{command: `python3.12 -Bc "import os"`, want: []string{"python: import os"}},
+		{command: `python -c'print(2)'`, want: []string{"python: print(2)"}},
+		{command: `node -e "console.log(1)"`, want: []string{"node: console.log(1)"}},
+		{command: `node --eval=1+1`, want: []string{"node: 1+1"}},
+		{command: `perl -ne 'print if /x/' file`, want: []string{"perl: print if /x/"}},
+		{command: `sudo bash -lc 'rm -rf build && python3 -c "print(3)"'`, want: []string{
+			`bash: rm -rf build && python3 -c "print(3)"`,
+			"python: print(3)",
+		}},
+		{command: `python3 script.py -c config.toml`, want: nil},
+		{command: `python3 -m http.server`, want: nil},
+		{command: `git commit -m "python -c"`, want: nil},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, script := range findInlineScripts(test.command) {
+			got = append(got, script.interpreter+": "+script.script)
+		}
+		if strings.Join(got, "|") != strings.Join(test.want, "|") {
+			t.Errorf("findInlineScripts(%q) = %q, want %q", test.command, got, test.want)
+		}
+	}
+}
+
+func TestEvaluateCommandPatterns_ShellPayloads(t *testing.T) {
+	if got := evaluateCommandPatterns(`bash -c "rm -rf /"`, []string{"bash *"}, []string{"rm -rf *"}); got != commandDenied {
+		t.Fatalf("expected deny rule to reach sh -c payload, got %v", got)
+	}
+	if got := evaluateCommandPatterns(`sh -c "git status"`, []string{"sh *"}, nil); got != commandNoMatch {
+		t.Fatalf("expected uncovered payload to need approval, got %v", got)
+	}
+	if got := evaluateCommandPatterns(`sh -c "git status"`, []string{"sh *", "git *"}, nil); got != commandAllowed {
+		t.Fatalf("expected covered payload to be allowed, got %v", got)
+	}
+}
+
+func TestExecuteTool_InlineScriptPromptsDespiteAllowPattern(t *testing.T) {
+	useIsolatedPolicyCache(t)
+
+	homeDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", homeDir)
+	writeCommandPolicyFile(t, homeDir, commandPolicy{Allow: []string{"python3 *"}})
+
+	appr := &fakeApprover{decision: Approved}
+	tool := fakeTool{name: "run_command", permission: tools.RequiresApproval, output: "done"}
+	command := `python3 -c 'import shutil; shutil.rmtree("/tmp/x")'`
+	for i := 1; i <= 2; i++ {
+		if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": command}, "Run"); err != nil {
+			t.Fatalf("execute tool: %v", err)
+		}
+		if appr.calls != i {
+			t.Fatalf("expected a prompt on every run, got %d after %d runs", appr.calls, i)
+		}
+	}
+	if !strings.Contains(appr.lastReq.Description, "python script:\nimport shutil; shutil.rmtree(\"/tmp/x\")") {
+		t.Fatalf("expected full script in prompt, got %q", appr.lastReq.Description)
+	}
+
+	if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": "python3 app.py"}, "Run"); err != nil {
+		t.Fatalf("execute tool: %v", err)
+	}
+	if appr.calls != 2 {
+		t.Fatalf("expected script file to follow the allow pattern, got %d prompts", appr.calls)
+	}
+}
+
+func TestExecuteTool_InlineScriptPolicyModeUsesPatterns(t *testing.T) {
+	useIsolatedPolicyCache(t)
+
+	homeDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", homeDir)
+	content := "[security]\nmode = \"standard\"\ninline_scripts = \"policy\"\n"
+	if err := os.WriteFile(filepath.Join(homeDir, config.ConfigFilePath), []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	writeCommandPolicyFile(t, homeDir, commandPolicy{Allow: []string{"python3 *"}})
+
+	appr := &fakeApprover{decision: Approved}
+	tool := fakeTool{name: "run_command", permission: tools.RequiresApproval, output: "done"}
+	if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": `python3 -c 'print(1)'`}, "Run"); err != nil {
+		t.Fatalf("execute tool: %v", err)
+	}
+	if appr.calls != 0 {
+		t.Fatalf("expected allow pattern to apply in policy mode, got %d prompts", appr.calls)
+	}
+	if SandboxesCommand(`python3 -c 'print(1)'`) {
+		t.Fatal("expected no sandbox in policy mode")
+	}
+}
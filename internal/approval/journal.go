@@ -0,0 +1,436 @@
+package approval
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// PolicyChange is one journaled mutation of a policy file. The whole policy
+// is recorded before and after the change so any entry can be reverted.
+type PolicyChange struct {
+	ID   int       `json:"id"`
+	Time time.Time `json:"time"`
+	// Actor is who made the change, e.g. the approving user or a claw command.
+	Actor string `json:"actor"`
+	// Policy is the policy file name, e.g. allowed_commands.json.
+	Policy  string `json:"policy"`
+	Summary string `json:"summary"`
+	// Reason is the request that triggered the change, if any.
+	Reason string          `json:"reason,omitempty"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+	// Diff lists the entries the change added and removed, per rule list.
+	Diff []PolicyDiff `json:"diff,omitempty"`
+	// Reverts is the ID of the change this one rolled back.
+	Reverts int `json:"reverts,omitempty"`
+}
+
+// PolicyDiff is what one change did to one rule list of a policy file.
+// Entries that are not strings, such as users, are compact JSON.
+type PolicyDiff struct {
+	// Field is the list's JSON key, e.g. allow or users.
+	Field   string   `json:"field"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// PolicyNotifier is told about every journaled policy change.
+type PolicyNotifier func(change PolicyChange)
+
+var policyNotifier atomic.Pointer[PolicyNotifier]
+
+// SetPolicyNotifier makes notify hear about every policy change this
+// process journals; nil stops the notifications.
+func SetPolicyNotifier(notify PolicyNotifier) {
+	if notify == nil {
+		policyNotifier.Store(nil)
+		return
+	}
+	policyNotifier.Store(&notify)
+}
+
+// ApproverNamer is implemented by approvers that can say who answers their
+// prompts, for the policy journal.
+type ApproverNamer interface {
+	ApproverName() string
+}
+
+// policyNote describes a mutation for the journal.
+type policyNote struct {
+	actor   string
+	summary string
+	reason  string
+	reverts int
+}
+
+var journalMu sync.Mutex
+
+// approverName names who answered approver's prompts.
+func approverName(approver Approver) string {
+	if namer, ok := approver.(ApproverNamer); ok {
+		if name := strings.TrimSpace(namer.ApproverName()); name != "" {
+			return name
+		}
+	}
+	return "approver"
+}
+
+// policyJournalPath returns the journal next to a policy file.
+func policyJournalPath(policyPath string) string {
+	return filepath.Join(filepath.Dir(policyPath), config.PolicyJournalFileName)
+}
+
+// journalPolicyChange appends a change to the journal before the policy file
+// is written, so the journal never misses a mutation that reached disk. A
+// change that adds or removes nothing is not journaled.
+func journalPolicyChange(policyPath string, before, after any, note policyNote) error {
+	beforeRaw, err := json.Marshal(before)
+	if err != nil {
+		return fmt.Errorf("encode policy before change: %w", err)
+	}
+	afterRaw, err := json.Marshal(after)
+	if err != nil {
+		return fmt.Errorf("encode policy after change: %w", err)
+	}
+	if string(beforeRaw) == string(afterRaw) {
+		return nil
+	}
+	diff, err := diffPolicy(beforeRaw, afterRaw)
+	if err != nil {
+		return err
+	}
+	if len(diff) == 0 {
+		return nil
+	}
+
+	change, err := appendPolicyChange(policyPath, PolicyChange{
+		Actor:   note.actor,
+		Policy:  filepath.Base(policyPath),
+		Summary: note.summary,
+		Reason:  note.reason,
+		Before:  beforeRaw,
+		After:   afterRaw,
+		Diff:    diff,
+		Reverts: note.reverts,
+	})
+	if err != nil {
+		return err
+	}
+	if notify := policyNotifier.Load(); notify != nil {
+		(*notify)(change)
+	}
+	return nil
+}
+
+// appendPolicyChange numbers and timestamps change and appends it to the
+// journal next to policyPath.
+func appendPolicyChange(policyPath string, change PolicyChange) (PolicyChange, error) {
+	journalMu.Lock()
+	defer journalMu.Unlock()
+
+	path := policyJournalPath(policyPath)
+	existing, err := LoadPolicyJournal(path)
+	if err != nil {
+		return PolicyChange{}, err
+	}
+	change.ID = 1
+	if len(existing) > 0 {
+		change.ID = existing[len(existing)-1].ID + 1
+	}
+	change.Time = time.Now().UTC()
+	encoded, err := json.Marshal(change)
+	if err != nil {
+		return PolicyChange{}, fmt.Errorf("encode policy change: %w", err)
+	}
+	if err := store.AppendFile(path, append(encoded, '\n')); err != nil {
+		return PolicyChange{}, fmt.Errorf("write policy journal: %w", err)
+	}
+	return change, nil
+}
+
+// diffPolicy compares two encoded policies list by list. A missing or null
+// list counts as empty, and a field that is not a list is one entry.
+func diffPolicy(beforeRaw, afterRaw json.RawMessage) ([]PolicyDiff, error) {
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(beforeRaw, &before); err != nil {
+		return nil, fmt.Errorf("decode policy before change: %w", err)
+	}
+	if err := json.Unmarshal(afterRaw, &after); err != nil {
+		return nil, fmt.Errorf("decode policy after change: %w", err)
+	}
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var diff []PolicyDiff
+	for _, field := range fields {
+		old, err := policyEntries(before[field])
+		if err != nil {
+			return nil, fmt.Errorf("decode policy field %s: %w", field, err)
+		}
+		updated, err := policyEntries(after[field])
+		if err != nil {
+			return nil, fmt.Errorf("decode policy field %s: %w", field, err)
+		}
+		change := PolicyDiff{Field: field}
+		for _, entry := range updated {
+			if !slices.Contains(old, entry) {
+				change.Added = append(change.Added, entry)
+			}
+		}
+		for _, entry := range old {
+			if !slices.Contains(updated, entry) {
+				change.Removed = append(change.Removed, entry)
+			}
+		}
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			diff = append(diff, change)
+		}
+	}
+	return diff, nil
+}
+
+// policyEntries renders the entries of one encoded policy field.
+func policyEntries(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var items []json.RawMessage
+	if raw[0] != '[' {
+		items = []json.RawMessage{raw}
+	} else if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+	entries := make([]string, 0, len(items))
+	for _, item := range items {
+		var text string
+		if err := json.Unmarshal(item, &text); err == nil {
+			entries = append(entries, text)
+			continue
+		}
+		entries = append(entries, string(item))
+	}
+	return entries, nil
+}
+
+// LoadPolicyJournal reads every change in the journal at path, oldest first.
+// A missing journal returns no changes.
+func LoadPolicyJournal(path string) ([]PolicyChange, error) {
+	raw, err := store.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read policy journal %s: %w", path, err)
+	}
+	var changes []PolicyChange
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var change PolicyChange
+		if err := json.Unmarshal([]byte(text), &change); err != nil {
+			return nil, fmt.Errorf("decode policy journal %s line %d: %w", path, line, err)
+		}
+		changes = append(changes, change)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan policy journal %s: %w", path, err)
+	}
+	return changes, nil
+}
+
+// RollbackPolicyChange reverts the single change id in the journal at
+// journalPath and journals the rollback as a new change. Later changes to
+// other entries are kept: rules the change added are removed and rules it
+// removed or replaced are restored.
+func RollbackPolicyChange(journalPath string, id int, actor string) (PolicyChange, error) {
+	changes, err := LoadPolicyJournal(journalPath)
+	if err != nil {
+		return PolicyChange{}, err
+	}
+	index := slices.IndexFunc(changes, func(change PolicyChange) bool { return change.ID == id })
+	if index < 0 {
+		return PolicyChange{}, fmt.Errorf("policy change #%d not found", id)
+	}
+	target := changes[index]
+	note := policyNote{
+		actor:   actor,
+		summary: fmt.Sprintf("rollback #%d: %s", target.ID, target.Summary),
+		reverts: target.ID,
+	}
+	policyPath := filepath.Join(filepath.Dir(journalPath), target.Policy)
+
+	switch target.Policy {
+	case config.AllowedCommandsFileName, config.AllowedDomainsFileName:
+		var before, after commandPolicy
+		if err := json.Unmarshal(target.Before, &before); err != nil {
+			return PolicyChange{}, fmt.Errorf("decode policy change #%d: %w", id, err)
+		}
+		if err := json.Unmarshal(target.After, &after); err != nil {
+			return PolicyChange{}, fmt.Errorf("decode policy change #%d: %w", id, err)
+		}
+		if target.Policy == config.AllowedCommandsFileName {
+			current, err := loadCachedCommandPolicy(policyPath)
+			if err != nil {
+				return PolicyChange{}, err
+			}
+			current.Allow = revertList(current.Allow, before.Allow, after.Allow)
+			current.Deny = revertList(current.Deny, before.Deny, after.Deny)
+			err = saveCachedCommandPolicy(policyPath, current, note)
+			return lastPolicyChange(journalPath, err)
+		}
+		current, err := loadCachedDomainPolicy(policyPath)
+		if err != nil {
+			return PolicyChange{}, err
+		}
+		current.Allow = revertList(current.Allow, before.Allow, after.Allow)
+		current.Deny = revertList(current.Deny, before.Deny, after.Deny)
+		err = saveCachedDomainPolicy(policyPath, current, note)
+		return lastPolicyChange(journalPath, err)
+	case config.AllowedBinsFileName:
+		var before, after binPolicy
+		if err := json.Unmarshal(target.Before, &before); err != nil {
+			return PolicyChange{}, fmt.Errorf("decode policy change #%d: %w", id, err)
+		}
+		if err := json.Unmarshal(target.After, &after); err != nil {
+			return PolicyChange{}, fmt.Errorf("decode policy change #%d: %w", id, err)
+		}
+		current, err := loadCachedBinPolicy(policyPath)
+		if err != nil {
+			return PolicyChange{}, err
+		}
+		current.Allow = revertList(current.Allow, before.Allow, after.Allow)
+		err = saveCachedBinPolicy(policyPath, current, note)
+		return lastPolicyChange(journalPath, err)
+	case config.TrustedOverlaysFileName:
+		var before, after overlayTrust
+		if err := json.Unmarshal(target.Before, &before); err != nil {
+			return PolicyChange{}, fmt.Errorf("decode policy change #%d: %w", id, err)
+		}
+		if err := json.Unmarshal(target.After, &after); err != nil {
+			return PolicyChange{}, fmt.Errorf("decode policy change #%d: %w", id, err)
+		}
+		current, err := loadCachedOverlayTrust(policyPath)
+		if err != nil {
+			return PolicyChange{}, err
+		}
+		current.Trusted = revertList(current.Trusted, before.Trusted, after.Trusted)
+		current.Refused = revertList(current.Refused, before.Refused, after.Refused)
+		err = saveCachedOverlayTrust(policyPath, current, note)
+		return lastPolicyChange(journalPath, err)
+	case config.AllowedUsersFileName:
+		var before, after UsersFile
+		if err := json.Unmarshal(target.Before, &before); err != nil {
+			return PolicyChange{}, fmt.Errorf("decode policy change #%d: %w", id, err)
+		}
+		if err := json.Unmarshal(target.After, &after); err != nil {
+			return PolicyChange{}, fmt.Errorf("decode policy change #%d: %w", id, err)
+		}
+		current, err := loadCachedUsersFile(policyPath)
+		if err != nil {
+			return PolicyChange{}, err
+		}
+		current.Users = revertUsers(current.Users, before.Users, after.Users)
+		err = saveCachedUsersFile(policyPath, current, note)
+		return lastPolicyChange(journalPath, err)
+	default:
+		return PolicyChange{}, fmt.Errorf("policy change #%d targets unknown policy %s", id, target.Policy)
+	}
+}
+
+// lastPolicyChange returns the change a rollback just journaled.
+func lastPolicyChange(journalPath string, saveErr error) (PolicyChange, error) {
+	if saveErr != nil {
+		return PolicyChange{}, saveErr
+	}
+	changes, err := LoadPolicyJournal(journalPath)
+	if err != nil {
+		return PolicyChange{}, err
+	}
+	if len(changes) == 0 {
+		return PolicyChange{}, errors.New("policy rollback was not journaled")
+	}
+	return changes[len(changes)-1], nil
+}
+
+// revertList undoes the before->after change to one rule list in current.
+func revertList(current, before, after []string) []string {
+	var reverted []string
+	for _, value := range current {
+		if slices.Contains(after, value) && !slices.Contains(before, value) {
+			continue
+		}
+		reverted = append(reverted, value)
+	}
+	for _, value := range before {
+		if !slices.Contains(after, value) {
+			reverted = appendUnique(reverted, value)
+		}
+	}
+	return reverted
+}
+
+// revertUsers undoes the before->after change to the user list in current.
+func revertUsers(current, before, after []User) []User {
+	key := func(user User) string {
+		return strings.TrimSpace(user.ID) + "\x00" + strings.ToLower(strings.TrimSpace(user.Channel))
+	}
+	find := func(users []User, k string) (User, bool) {
+		for _, user := range users {
+			if key(user) == k {
+				return user, true
+			}
+		}
+		return User{}, false
+	}
+
+	var reverted []User
+	for _, user := range current {
+		if _, added := find(after, key(user)); added {
+			if _, existed := find(before, key(user)); !existed {
+				continue
+			}
+		}
+		reverted = append(reverted, user)
+	}
+	for _, old := range before {
+		if updated, ok := find(after, key(old)); ok && updated == old {
+			continue
+		}
+		replaced := false
+		for i := range reverted {
+			if key(reverted[i]) == key(old) {
+				reverted[i] = old
+				replaced = true
+			}
+		}
+		if !replaced {
+			reverted = append(reverted, old)
+		}
+	}
+	return reverted
+}
@@ -0,0 +1,153 @@
+package approval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+type namedApprover struct {
+	fakeApprover
+	name string
+}
+
+func (a *namedApprover) ApproverName() string { return a.name }
+
+func TestPolicyJournal_RecordsAndRollsBackCommandPattern(t *testing.T) {
+	useIsolatedPolicyCache(t)
+
+	homeDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", homeDir)
+	cfg := &config.Config{HomeDir: homeDir, Agent: "default"}
+
+	appr := &namedApprover{fakeApprover: fakeApprover{decision: Approved}, name: "telegram user 42"}
+	tool := fakeTool{name: "run_command", permission: tools.RequiresApproval, output: "done"}
+	for _, command := range []string{"rm -rf build", "git status"} {
+		if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": command}, "Run"); err != nil {
+			t.Fatalf("execute %q: %v", command, err)
+		}
+	}
+
+	changes, err := LoadPolicyJournal(cfg.PolicyJournalPath())
+	if err != nil {
+		t.Fatalf("load journal: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected two journaled changes, got %#v", changes)
+	}
+	first := changes[0]
+	if first.ID != 1 || first.Actor != "telegram user 42" || first.Policy != config.AllowedCommandsFileName {
+		t.Fatalf("unexpected first change %#v", first)
+	}
+	if first.Summary != "allow command rm *" || first.Reason != "run_command: rm -rf build" {
+		t.Fatalf("unexpected first change summary %q reason %q", first.Summary, first.Reason)
+	}
+
+	rollback, err := RollbackPolicyChange(cfg.PolicyJournalPath(), 1, "claw policy rollback")
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if rollback.ID != 3 || rollback.Reverts != 1 || !strings.HasPrefix(rollback.Summary, "rollback #1") {
+		t.Fatalf("unexpected rollback change %#v", rollback)
+	}
+	policy := readCommandPolicyFile(t, homeDir)
+	if containsString(policy.Allow, "rm *") || !containsString(policy.Allow, "git status") {
+		t.Fatalf("expected only rm pattern reverted, got %#v", policy.Allow)
+	}
+
+	if _, err := RollbackPolicyChange(cfg.PolicyJournalPath(), 99, "claw policy rollback"); err == nil {
+		t.Fatal("expected error for unknown change")
+	}
+}
+
+func TestPolicyJournal_RollbackRestoresUpdatedUser(t *testing.T) {
+	useIsolatedPolicyCache(t)
+
+	path := filepath.Join(t.TempDir(), config.AllowedUsersFileName)
+	if err := AddUser(path, User{ID: "123", Channel: "telegram", Username: "alice"}); err != nil {
+		t.Fatalf("add user: %v", err)
+	}
+	if err := AddUser(path, User{ID: "123", Channel: "telegram", Username: "alice", Role: RoleObserver}); err != nil {
+		t.Fatalf("update user: %v", err)
+	}
+	if err := AddUser(path, User{ID: "456", Channel: "telegram", Username: "bob"}); err != nil {
+		t.Fatalf("add second user: %v", err)
+	}
+
+	journalPath := policyJournalPath(path)
+	changes, err := LoadPolicyJournal(journalPath)
+	if err != nil || len(changes) != 3 {
+		t.Fatalf("expected three changes, got %#v %v", changes, err)
+	}
+	if changes[1].Actor != "claw pair" || changes[1].Summary != "add telegram user 123 (@alice) as observer" {
+		t.Fatalf("unexpected change %#v", changes[1])
+	}
+
+	if _, err := RollbackPolicyChange(journalPath, 2, "claw policy rollback"); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	loaded, err := LoadUsers(path)
+	if err != nil {
+		t.Fatalf("load users: %v", err)
+	}
+	if len(loaded.Users) != 2 || loaded.Users[0].IsObserver() || loaded.Users[1].ID != "456" {
+		t.Fatalf("expected observer role reverted and bob kept, got %#v", loaded.Users)
+	}
+}
+
+func TestPolicyJournal_RecordsDiffAndFlushedEdits(t *testing.T) {
+	useIsolatedPolicyCache(t)
+
+	homeDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", homeDir)
+	cfg := &config.Config{HomeDir: homeDir, Agent: "default"}
+	writeDomainPolicyFile(t, homeDir, domainPolicy{Allow: []string{"api.anthropic.com"}})
+
+	var notified []PolicyChange
+	SetPolicyNotifier(func(change PolicyChange) { notified = append(notified, change) })
+	t.Cleanup(func() { SetPolicyNotifier(nil) })
+
+	appr := &namedApprover{fakeApprover: fakeApprover{decision: Approved}, name: "cli"}
+	tool := fakeTool{
+		name:       "run_command",
+		permission: tools.RequiresApproval,
+		execute: func(context.Context, map[string]any) (*tools.ToolResult, error) {
+			if err := os.WriteFile(cfg.AllowedDomainsPath(), []byte("{\"allow\":[\"*\"],\"deny\":[]}\n"), 0o644); err != nil {
+				return nil, err
+			}
+			return &tools.ToolResult{Output: "done"}, nil
+		},
+	}
+	if _, err := ExecuteTool(context.Background(), appr, tool, map[string]any{"command": "git status"}, "Run"); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	changes, err := LoadPolicyJournal(cfg.PolicyJournalPath())
+	if err != nil {
+		t.Fatalf("load journal: %v", err)
+	}
+	if len(changes) != 2 || len(notified) != 2 {
+		t.Fatalf("expected an approval and a flush journaled and notified, got %#v and %d notifications", changes, len(notified))
+	}
+	approved := changes[0]
+	if len(approved.Diff) != 1 || approved.Diff[0].Field != "allow" || !slices.Equal(approved.Diff[0].Added, []string{"git status"}) || approved.Diff[0].Removed != nil {
+		t.Fatalf("unexpected approval diff %#v", approved.Diff)
+	}
+	flushed := changes[1]
+	if flushed.Actor != "flush" || flushed.Policy != config.AllowedDomainsFileName {
+		t.Fatalf("unexpected flush change %#v", flushed)
+	}
+	// deny went from [] on disk to empty in memory, which is no change.
+	if len(flushed.Diff) != 1 || !slices.Equal(flushed.Diff[0].Added, []string{"api.anthropic.com"}) || !slices.Equal(flushed.Diff[0].Removed, []string{"*"}) {
+		t.Fatalf("unexpected flush diff %#v", flushed.Diff)
+	}
+	if notified[1].ID != flushed.ID {
+		t.Fatalf("expected the notifier to get the journaled change, got %#v", notified[1])
+	}
+}
@@ -0,0 +1,249 @@
+package approval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// OverlayPath is where a project keeps its policy overlay, relative to the
+// project directory.
+var OverlayPath = filepath.Join(".neoclaw", "policy.json")
+
+// Rules are allow and deny patterns in the format of the global policy files.
+type Rules struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// Overlay is policy applied on top of allowed_commands.json and
+// allowed_domains.json for calls made with a context from WithOverlay. It is
+// never written to the global files.
+type Overlay struct {
+	Commands Rules `json:"commands"`
+	Domains  Rules `json:"domains"`
+}
+
+// Merge returns the rules of o and other together.
+func (o Overlay) Merge(other Overlay) Overlay {
+	return Overlay{
+		Commands: Rules{
+			Allow: append(slices.Clone(o.Commands.Allow), other.Commands.Allow...),
+			Deny:  append(slices.Clone(o.Commands.Deny), other.Commands.Deny...),
+		},
+		Domains: Rules{
+			Allow: append(slices.Clone(o.Domains.Allow), other.Domains.Allow...),
+			Deny:  append(slices.Clone(o.Domains.Deny), other.Domains.Deny...),
+		},
+	}
+}
+
+func (o Overlay) empty() bool {
+	return len(o.Commands.Allow)+len(o.Commands.Deny)+len(o.Domains.Allow)+len(o.Domains.Deny) == 0
+}
+
+type overlayKey struct{}
+
+// WithOverlay applies overlay to run_command and network checks made with
+// ctx. Deny rules from either side win, and the program allowlist still
+// applies.
+func WithOverlay(ctx context.Context, overlay Overlay) context.Context {
+	return context.WithValue(ctx, overlayKey{}, overlay)
+}
+
+func overlayFrom(ctx context.Context) Overlay {
+	overlay, _ := ctx.Value(overlayKey{}).(Overlay)
+	return overlay
+}
+
+// overlayTrust is the on-disk shape of trusted_overlays.json. Each entry is
+// an overlay's SHA-256 and path, so any change to a file asks again.
+type overlayTrust struct {
+	Trusted []string `json:"trusted"`
+	Refused []string `json:"refused"`
+}
+
+// LoadProjectOverlay reads the policy overlay of the project at dir. A new
+// or changed overlay is shown to approver once: approved content is trusted
+// until the file changes, and refused content is ignored until then too. A
+// missing overlay, or one without an approver to ask, applies nothing.
+func LoadProjectOverlay(ctx context.Context, approver Approver, dir string) (Overlay, error) {
+	path, err := filepath.Abs(filepath.Join(dir, OverlayPath))
+	if err != nil {
+		return Overlay{}, fmt.Errorf("resolve policy overlay: %w", err)
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Overlay{}, nil
+	}
+	if err != nil {
+		return Overlay{}, fmt.Errorf("read policy overlay %s: %w", path, err)
+	}
+	var overlay Overlay
+	if err := json.Unmarshal(raw, &overlay); err != nil {
+		return Overlay{}, fmt.Errorf("decode policy overlay %s: %w", path, err)
+	}
+	if overlay.empty() {
+		return Overlay{}, nil
+	}
+
+	sum := sha256.Sum256(raw)
+	entry := hex.EncodeToString(sum[:]) + " " + path
+	paths, err := currentPolicyPaths()
+	if err != nil {
+		return Overlay{}, err
+	}
+	trust, err := loadCachedOverlayTrust(paths.overlays)
+	if err != nil {
+		return Overlay{}, err
+	}
+	switch {
+	case slices.Contains(trust.Trusted, entry):
+		return overlay, nil
+	case slices.Contains(trust.Refused, entry):
+		return Overlay{}, nil
+	case approver == nil:
+		logging.Logger().Warn("ignoring untrusted policy overlay: no approver to ask", "path", path)
+		return Overlay{}, nil
+	}
+
+	decision, err := approver.RequestApproval(ctx, ApprovalRequest{
+		Tool:        "policy_overlay",
+		Description: describeOverlay(path, overlay),
+		Args:        map[string]any{"path": path},
+	})
+	if err != nil {
+		return Overlay{}, err
+	}
+	// Forget earlier decisions about the file; only its current content
+	// counts.
+	samePath := func(existing string) bool { return strings.HasSuffix(existing, " "+path) }
+	trust.Trusted = slices.DeleteFunc(trust.Trusted, samePath)
+	trust.Refused = slices.DeleteFunc(trust.Refused, samePath)
+	note := policyNote{actor: approverName(approver), reason: "project policy overlay " + path}
+	if decision == Approved {
+		trust.Trusted = append(trust.Trusted, entry)
+		note.summary = "trust overlay " + path
+	} else {
+		trust.Refused = append(trust.Refused, entry)
+		note.summary = "refuse overlay " + path
+		overlay = Overlay{}
+	}
+	if err := saveCachedOverlayTrust(paths.overlays, trust, note); err != nil {
+		return Overlay{}, err
+	}
+	return overlay, nil
+}
+
+// describeOverlay is the approval prompt for trusting an overlay.
+func describeOverlay(path string, overlay Overlay) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Trust project policy %s?", path)
+	for _, rules := range []struct {
+		label string
+		items []string
+	}{
+		{"Allow commands", overlay.Commands.Allow},
+		{"Deny commands", overlay.Commands.Deny},
+		{"Allow domains", overlay.Domains.Allow},
+		{"Deny domains", overlay.Domains.Deny},
+	} {
+		if len(rules.items) > 0 {
+			fmt.Fprintf(&b, "\n%s: %s", rules.label, strings.Join(rules.items, ", "))
+		}
+	}
+	return b.String()
+}
+
+// Load overlay trust from in-memory cache, lazy-loading from disk once.
+func loadCachedOverlayTrust(path string) (overlayTrust, error) {
+	policyCacheMu.Lock()
+	defer policyCacheMu.Unlock()
+
+	if trust, ok := overlayTrustCache[path]; ok {
+		return cloneOverlayTrust(trust), nil
+	}
+
+	trust, err := loadOverlayTrust(path)
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		trust = overlayTrust{}
+	default:
+		return overlayTrust{}, err
+	}
+	overlayTrustCache[path] = cloneOverlayTrust(trust)
+	return cloneOverlayTrust(trust), nil
+}
+
+// Journal the change, then persist overlay trust and update in-memory cache.
+func saveCachedOverlayTrust(path string, trust overlayTrust, note policyNote) error {
+	before, err := loadCachedOverlayTrust(path)
+	if err != nil {
+		return err
+	}
+	copied := cloneOverlayTrust(trust)
+	if err := journalPolicyChange(path, before, copied, note); err != nil {
+		return err
+	}
+
+	policyCacheMu.Lock()
+	overlayTrustCache[path] = copied
+	policyCacheMu.Unlock()
+
+	return saveOverlayTrust(path, copied)
+}
+
+// Copy overlay trust slices before returning/storing.
+func cloneOverlayTrust(trust overlayTrust) overlayTrust {
+	return overlayTrust{
+		Trusted: append([]string(nil), trust.Trusted...),
+		Refused: append([]string(nil), trust.Refused...),
+	}
+}
+
+// Load overlay trust from disk.
+func loadOverlayTrust(path string) (overlayTrust, error) {
+	raw, err := store.ReadFile(path)
+	if err != nil {
+		return overlayTrust{}, err
+	}
+	if strings.TrimSpace(raw) == "" {
+		return overlayTrust{}, nil
+	}
+
+	var trust overlayTrust
+	if err := json.Unmarshal([]byte(raw), &trust); err != nil {
+		return overlayTrust{}, fmt.Errorf("decode overlay trust %s: %w", path, err)
+	}
+	return trust, nil
+}
+
+// Save overlay trust to disk.
+func saveOverlayTrust(path string, trust overlayTrust) error {
+	if trust.Trusted == nil {
+		trust.Trusted = []string{}
+	}
+	if trust.Refused == nil {
+		trust.Refused = []string{}
+	}
+	encoded, err := json.MarshalIndent(trust, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode overlay trust: %w", err)
+	}
+	encoded = append(encoded, '\n')
+	if err := store.WriteFile(path, encoded); err != nil {
+		return fmt.Errorf("write overlay trust: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package approval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestLoadProjectOverlay_AsksOnceUntilTheFileChanges(t *testing.T) {
+	useIsolatedPolicyCache(t)
+	dataDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", dataDir)
+	project := t.TempDir()
+	writeOverlay(t, project, `{"commands": {"allow": ["npm *", "node *"]}}`)
+
+	appr := &fakeApprover{decision: Approved}
+	overlay, err := LoadProjectOverlay(context.Background(), appr, project)
+	if err != nil {
+		t.Fatalf("load overlay: %v", err)
+	}
+	if !slices.Equal(overlay.Commands.Allow, []string{"npm *", "node *"}) {
+		t.Fatalf("unexpected overlay: %#v", overlay)
+	}
+	if appr.lastReq.Tool != "policy_overlay" || !strings.Contains(appr.lastReq.Description, "Allow commands: npm *, node *") {
+		t.Fatalf("unexpected prompt: %#v", appr.lastReq)
+	}
+	if _, err := LoadProjectOverlay(context.Background(), appr, project); err != nil || appr.calls != 1 {
+		t.Fatalf("expected a trusted overlay to load without asking, got err=%v prompts=%d", err, appr.calls)
+	}
+
+	writeOverlay(t, project, `{"commands": {"allow": ["curl *"]}}`)
+	appr.decision = Denied
+	overlay, err = LoadProjectOverlay(context.Background(), appr, project)
+	if err != nil {
+		t.Fatalf("load overlay: %v", err)
+	}
+	if appr.calls != 2 || len(overlay.Commands.Allow) != 0 {
+		t.Fatalf("expected a changed overlay to be asked about and refused, got %#v after %d prompts", overlay, appr.calls)
+	}
+	if _, err := LoadProjectOverlay(context.Background(), appr, project); err != nil || appr.calls != 2 {
+		t.Fatalf("expected a refused overlay not to ask again, got err=%v prompts=%d", err, appr.calls)
+	}
+
+	cfg := &config.Config{HomeDir: dataDir, Agent: "default"}
+	trust, err := loadOverlayTrust(cfg.TrustedOverlaysPath())
+	if err != nil {
+		t.Fatalf("load trust: %v", err)
+	}
+	if len(trust.Trusted) != 0 || len(trust.Refused) != 1 {
+		t.Fatalf("expected only the current content to be remembered, got %#v", trust)
+	}
+}
+
+func TestLoadProjectOverlay_MissingFileOrApproverAppliesNothing(t *testing.T) {
+	useIsolatedPolicyCache(t)
+	t.Setenv("NEOCLAW_HOME", t.TempDir())
+	project := t.TempDir()
+
+	overlay, err := LoadProjectOverlay(context.Background(), &fakeApprover{}, project)
+	if err != nil || !overlay.empty() {
+		t.Fatalf("expected no overlay, got %#v err=%v", overlay, err)
+	}
+	writeOverlay(t, project, `{"commands": {"allow": ["cargo *"]}}`)
+	overlay, err = LoadProjectOverlay(context.Background(), nil, project)
+	if err != nil || !overlay.empty() {
+		t.Fatalf("expected an untrusted overlay to be ignored, got %#v err=%v", overlay, err)
+	}
+}
+
+func TestExecuteTool_OverlayDenyRulesApply(t *testing.T) {
+	useIsolatedPolicyCache(t)
+	dataDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", dataDir)
+	writeCommandPolicyFile(t, dataDir, commandPolicy{Allow: []string{"cargo *"}})
+
+	appr := &fakeApprover{decision: Approved}
+	tool := fakeTool{name: "run_command", permission: tools.RequiresApproval, output: "done"}
+	ctx := WithOverlay(context.Background(), Overlay{Commands: Rules{Deny: []string{"cargo publish *"}}})
+	if _, err := ExecuteTool(ctx, appr, tool, map[string]any{"command": "cargo publish --dry-run"}, "Run: cargo publish"); err == nil {
+		t.Fatal("expected the overlay deny rule to refuse the command")
+	}
+	if appr.calls != 0 {
+		t.Fatalf("expected no prompt, got %d", appr.calls)
+	}
+}
+
+func TestCheckerAllow_OverlayDomainsAreNotPersisted(t *testing.T) {
+	allowedPath := filepath.Join(t.TempDir(), "allowed_domains.json")
+	writeDomainPolicy(t, allowedPath, domainPolicy{Allow: []string{"github.com"}})
+
+	appr := &mockDomainApprover{decision: Denied}
+	checker := Checker{AllowedDomainsPath: allowedPath, Approver: appr}
+	ctx := WithOverlay(context.Background(), Overlay{Domains: Rules{Allow: []string{"registry.npmjs.org"}, Deny: []string{"gist.github.com"}}})
+	if err := checker.Allow(ctx, "registry.npmjs.org"); err != nil {
+		t.Fatalf("expected the overlay to allow the domain, got %v", err)
+	}
+	if err := checker.Allow(ctx, "gist.github.com"); err == nil {
+		t.Fatal("expected the overlay to deny the domain")
+	}
+	if appr.calls != 0 {
+		t.Fatalf("expected no prompt, got %d", appr.calls)
+	}
+	if policy := readDomainPolicy(t, allowedPath); slices.Contains(policy.Allow, "registry.npmjs.org") {
+		t.Fatalf("expected overlay domains to stay out of the policy file, got %#v", policy)
+	}
+}
+
+func writeOverlay(t *testing.T, dir, content string) {
+	t.Helper()
+	path := filepath.Join(dir, OverlayPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir overlay dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write overlay: %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package approval
+
+import "strings"
+
+// ReplyDecision reads one answer word in any letter case: yes, y, and
+// approve mean approve; no, n, and deny mean deny.
+func ReplyDecision(word string) (ApprovalDecision, bool) {
+	switch strings.ToLower(strings.TrimSpace(word)) {
+	case "y", "yes", "approve":
+		return Approved, true
+	case "n", "no", "deny":
+		return Denied, true
+	}
+	return Denied, false
+}
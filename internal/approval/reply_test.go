@@ -0,0 +1,14 @@
+package approval
+
+import "testing"
+
+func TestReplyDecision(t *testing.T) {
+	for word, want := range map[string]ApprovalDecision{"y": Approved, "YES": Approved, "approve": Approved, "n": Denied, "No": Denied, "deny": Denied} {
+		if got, ok := ReplyDecision(word); !ok || got != want {
+			t.Fatalf("ReplyDecision(%q) = %v, %v", word, got, ok)
+		}
+	}
+	if _, ok := ReplyDecision("sure"); ok {
+		t.Fatal("expected an unknown word not to be a decision")
+	}
+}
@@ -0,0 +1,38 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CodeReviewer checks the code a write_file or apply_patch call is about to
+// write. It returns its findings, or "" when it found nothing to report.
+type CodeReviewer func(ctx context.Context, toolName string, args map[string]any) (string, error)
+
+type codeReviewKey struct{}
+
+// WithCodeReview makes write_file and apply_patch calls made with ctx ask
+// for approval, with reviewer's findings added to the prompt.
+func WithCodeReview(ctx context.Context, reviewer CodeReviewer) context.Context {
+	return context.WithValue(ctx, codeReviewKey{}, reviewer)
+}
+
+// reviewsCode reports whether toolName writes code that a reviewer checks.
+func reviewsCode(toolName string) bool {
+	return toolName == "write_file" || toolName == "apply_patch"
+}
+
+// reviewedDescription runs reviewer on the call and returns description
+// with its findings. A failed review is reported in the prompt rather than
+// refusing the call, since the user still decides.
+func reviewedDescription(ctx context.Context, reviewer CodeReviewer, toolName string, args map[string]any, description string) string {
+	findings, err := reviewer(ctx, toolName, args)
+	switch {
+	case err != nil:
+		findings = fmt.Sprintf("failed (%v); check the change yourself.", err)
+	case strings.TrimSpace(findings) == "":
+		findings = "no issues found."
+	}
+	return description + "\n\nSecurity review:\n" + strings.TrimSpace(findings)
+}
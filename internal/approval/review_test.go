@@ -0,0 +1,55 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestExecuteTool_CodeReviewPromptsWithFindings(t *testing.T) {
+	var reviewed map[string]any
+	ctx := WithCodeReview(context.Background(), func(_ context.Context, toolName string, args map[string]any) (string, error) {
+		reviewed = args
+		return "- hardcoded API key on line 3", nil
+	})
+	appr := &fakeApprover{decision: Approved}
+	tool := fakeTool{name: "write_file", permission: tools.AutoApprove, output: "done"}
+	args := map[string]any{"path": "main.go", "content": "package main"}
+	if _, err := ExecuteTool(ctx, appr, tool, args, "write_file: main.go"); err != nil {
+		t.Fatalf("execute tool: %v", err)
+	}
+	if appr.calls != 1 {
+		t.Fatalf("expected reviewed writes to prompt, got %d prompts", appr.calls)
+	}
+	if reviewed["content"] != "package main" {
+		t.Fatalf("expected the reviewer to see the tool args, got %v", reviewed)
+	}
+	want := "write_file: main.go\n\nSecurity review:\n- hardcoded API key on line 3"
+	if appr.lastReq.Description != want {
+		t.Fatalf("unexpected description %q", appr.lastReq.Description)
+	}
+}
+
+func TestExecuteTool_CodeReviewFailureStillPrompts(t *testing.T) {
+	ctx := WithCodeReview(context.Background(), func(context.Context, string, map[string]any) (string, error) {
+		return "", errors.New("rate limited")
+	})
+	appr := &fakeApprover{decision: Denied}
+	tool := fakeTool{name: "apply_patch", permission: tools.AutoApprove, output: "done"}
+	if _, err := ExecuteTool(ctx, appr, tool, map[string]any{"patch": "diff"}, "apply_patch"); err == nil {
+		t.Fatal("expected the denied patch to fail")
+	}
+	if !strings.Contains(appr.lastReq.Description, "Security review:\nfailed (rate limited)") {
+		t.Fatalf("expected the failure in the prompt, got %q", appr.lastReq.Description)
+	}
+
+	// Other tools are not reviewed.
+	appr = &fakeApprover{decision: Denied}
+	tool = fakeTool{name: "read_file", permission: tools.AutoApprove, output: "done"}
+	if _, err := ExecuteTool(ctx, appr, tool, nil, "read_file"); err != nil || appr.calls != 0 {
+		t.Fatalf("expected read_file to run without review, got err %v and %d prompts", err, appr.calls)
+	}
+}
@@ -0,0 +1,163 @@
+package approval
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Risk classes shown next to run_command approval prompts. They are
+// heuristics from the command text, not a guarantee of what it does.
+const (
+	riskReads   = "reads files"
+	riskWrites  = "writes files"
+	riskDeletes = "deletes"
+	riskNetwork = "network"
+	riskInstall = "package install"
+)
+
+// riskOrder is the order classes are listed in.
+var riskOrder = []string{riskReads, riskWrites, riskDeletes, riskNetwork, riskInstall}
+
+var programRisks = map[string]string{
+	"cat": riskReads, "less": riskReads, "more": riskReads, "head": riskReads,
+	"tail": riskReads, "grep": riskReads, "rg": riskReads, "ag": riskReads,
+	"find": riskReads, "ls": riskReads, "tree": riskReads, "wc": riskReads,
+	"diff": riskReads, "stat": riskReads, "file": riskReads, "du": riskReads,
+	"jq": riskReads, "yq": riskReads, "awk": riskReads, "sed": riskReads,
+	"sort": riskReads, "uniq": riskReads, "cut": riskReads, "strings": riskReads,
+
+	"cp": riskWrites, "mv": riskWrites, "touch": riskWrites, "mkdir": riskWrites,
+	"tee": riskWrites, "dd": riskWrites, "chmod": riskWrites, "chown": riskWrites,
+	"ln": riskWrites, "truncate": riskWrites, "install": riskWrites, "patch": riskWrites,
+	"tar": riskWrites, "unzip": riskWrites, "zip": riskWrites, "gzip": riskWrites,
+
+	"rm": riskDeletes, "rmdir": riskDeletes, "shred": riskDeletes, "unlink": riskDeletes,
+	"trash": riskDeletes,
+
+	"curl": riskNetwork, "wget": riskNetwork, "ssh": riskNetwork, "scp": riskNetwork,
+	"sftp": riskNetwork, "rsync": riskNetwork, "nc": riskNetwork, "ncat": riskNetwork,
+	"telnet": riskNetwork, "ftp": riskNetwork, "ping": riskNetwork, "dig": riskNetwork,
+	"nslookup": riskNetwork, "http": riskNetwork,
+}
+
+// packageManagers maps package managers to the subcommands that install or
+// change packages.
+var packageManagers = map[string][]string{
+	"apt": {"install", "upgrade", "remove", "purge"}, "apt-get": {"install", "upgrade", "remove", "purge"},
+	"brew": {"install", "upgrade", "reinstall", "uninstall"}, "dnf": {"install", "upgrade", "remove"},
+	"yum": {"install", "update", "remove"}, "apk": {"add", "upgrade", "del"},
+	"pacman": {"-S", "-Syu", "-R"}, "snap": {"install", "remove"},
+	"pip": {"install", "uninstall"}, "pip3": {"install", "uninstall"}, "pipx": {"install"},
+	"uv": {"add", "pip", "tool"}, "npm": {"install", "i", "ci", "add", "update", "uninstall"},
+	"pnpm": {"install", "i", "add", "update", "remove"}, "yarn": {"install", "add", "upgrade", "remove"},
+	"gem": {"install", "update", "uninstall"}, "cargo": {"install", "add"},
+	"go": {"install", "get"},
+}
+
+// gitRisks classifies git subcommands.
+var gitRisks = map[string]string{
+	"status": riskReads, "log": riskReads, "diff": riskReads, "show": riskReads,
+	"blame": riskReads, "grep": riskReads, "branch": riskReads,
+
+	"add": riskWrites, "commit": riskWrites, "checkout": riskWrites, "switch": riskWrites,
+	"restore": riskWrites, "merge": riskWrites, "rebase": riskWrites, "reset": riskWrites,
+	"stash": riskWrites, "apply": riskWrites, "cherry-pick": riskWrites, "tag": riskWrites,
+	"init": riskWrites, "mv": riskWrites,
+
+	"rm": riskDeletes, "clean": riskDeletes,
+
+	"push": riskNetwork, "pull": riskNetwork, "fetch": riskNetwork, "clone": riskNetwork,
+}
+
+// commandWrappers run the command that follows them.
+var commandWrappers = map[string]bool{
+	"sudo": true, "doas": true, "env": true, "nohup": true, "time": true,
+	"nice": true, "xargs": true, "command": true, "exec": true, "timeout": true,
+}
+
+// classifyCommandRisk returns the risk classes a command likely falls into,
+// in a fixed order. Unknown programs add nothing.
+func classifyCommandRisk(command string) []string {
+	segments, writesFile, err := parseShellCommand(command)
+	if err != nil {
+		return nil
+	}
+	found := map[string]bool{}
+	if writesFile {
+		found[riskWrites] = true
+	}
+	for _, tokens := range expandShellScripts(segments, 0) {
+		tokens = stripCommandWrappers(tokens)
+		if len(tokens) == 0 {
+			continue
+		}
+		program := filepath.Base(tokens[0])
+		args := tokens[1:]
+		switch {
+		case program == "git":
+			if sub := gitSubcommand(args); sub != "" {
+				if risk, ok := gitRisks[sub]; ok {
+					found[risk] = true
+				}
+			}
+		case packageManagers[program] != nil:
+			if slices.ContainsFunc(args, func(arg string) bool { return slices.Contains(packageManagers[program], arg) }) {
+				found[riskInstall] = true
+			}
+		case program == "sed" && slices.ContainsFunc(args, func(arg string) bool { return arg == "-i" || strings.HasPrefix(arg, "-i") || arg == "--in-place" }):
+			found[riskWrites] = true
+		case program == "find" && slices.Contains(args, "-delete"):
+			found[riskDeletes] = true
+		default:
+			if risk, ok := programRisks[program]; ok {
+				found[risk] = true
+			}
+		}
+	}
+
+	var risks []string
+	for _, risk := range riskOrder {
+		if found[risk] {
+			risks = append(risks, risk)
+		}
+	}
+	return risks
+}
+
+// stripCommandWrappers drops sudo, env, and similar prefixes along with their
+// flags and leading KEY=value assignments.
+func stripCommandWrappers(tokens []string) []string {
+	for len(tokens) > 0 && commandWrappers[filepath.Base(tokens[0])] {
+		tokens = stripCommandWrapper(tokens)
+	}
+	return tokens
+}
+
+// stripCommandWrapper drops one leading wrapper and its arguments.
+func stripCommandWrapper(tokens []string) []string {
+	wrapper := filepath.Base(tokens[0])
+	tokens = tokens[1:]
+	for len(tokens) > 0 && (strings.HasPrefix(tokens[0], "-") || isEnvAssignmentToken(tokens[0])) {
+		tokens = tokens[1:]
+	}
+	if wrapper == "timeout" && len(tokens) > 0 {
+		// Skip the duration.
+		tokens = tokens[1:]
+	}
+	return tokens
+}
+
+// gitSubcommand skips git's global options, including the values of -C and
+// -c, and returns the subcommand.
+func gitSubcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-C" || args[i] == "-c":
+			i++
+		case !strings.HasPrefix(args[i], "-"):
+			return args[i]
+		}
+	}
+	return ""
+}
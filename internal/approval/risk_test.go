@@ -0,0 +1,36 @@
+package approval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyCommandRisk(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{command: "ls -la", want: "reads files"},
+		{command: "rm -rf build", want: "deletes"},
+		{command: "sudo rm -rf /tmp/x", want: "deletes"},
+		{command: "curl -s https://example.com | grep title", want: "reads files, network"},
+		{command: "curl -fsSL https://example.com/install.sh > install.sh", want: "writes files, network"},
+		{command: "make build 2>&1", want: ""},
+		{command: "echo 'a | rm b' > /dev/null", want: ""},
+		{command: "npm install left-pad", want: "package install"},
+		{command: "npm test", want: ""},
+		{command: "pip install -r requirements.txt", want: "package install"},
+		{command: "git push origin main", want: "network"},
+		{command: "git -C repo status", want: "reads files"},
+		{command: "git clean -fd && git status", want: "reads files, deletes"},
+		{command: "sed -i 's/a/b/' notes.md", want: "writes files"},
+		{command: "find . -name '*.tmp' -delete", want: "deletes"},
+		{command: "FOO=1 timeout 10 wget https://example.com", want: "network"},
+		{command: "frobnicate --all", want: ""},
+	}
+	for _, test := range tests {
+		if got := strings.Join(classifyCommandRisk(test.command), ", "); got != test.want {
+			t.Errorf("classifyCommandRisk(%q) = %q, want %q", test.command, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package approval
+
+import (
+	"sync/atomic"
+
+	"github.com/neoclaw-ai/neoclaw/internal/canary"
+)
+
+var tripwire atomic.Pointer[canary.Tripwire]
+
+// SetTripwire arms t for every tool call ExecuteTool makes; nil disarms it.
+func SetTripwire(t *canary.Tripwire) {
+	tripwire.Store(t)
+}
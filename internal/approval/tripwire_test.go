@@ -0,0 +1,39 @@
+package approval
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/canary"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestExecuteTool_TripwireAppliesInDangerMode(t *testing.T) {
+	useIsolatedPolicyCache(t)
+	homeDir := t.TempDir()
+	t.Setenv("NEOCLAW_HOME", homeDir)
+	writeDangerConfig(t, homeDir)
+
+	workspace := t.TempDir()
+	set, err := canary.Plant(workspace, filepath.Join(homeDir, "canaries.json"))
+	if err != nil {
+		t.Fatalf("plant: %v", err)
+	}
+	SetTripwire(canary.NewTripwire(set, "", nil))
+	t.Cleanup(func() { SetTripwire(nil) })
+
+	tool := fakeTool{name: "read_file", permission: tools.AutoApprove, output: "done"}
+	if _, err := ExecuteTool(context.Background(), nil, tool, map[string]any{"path": filepath.Join(workspace, ".env.production")}, ""); err == nil {
+		t.Fatal("expected the canary read to be refused")
+	}
+
+	leaky := fakeTool{name: "run_command", permission: tools.AutoApprove, output: "KEY=" + set.Canaries()[1].Tokens[0]}
+	res, err := ExecuteTool(context.Background(), nil, leaky, map[string]any{"command": "grep -r KEY ."}, "")
+	if err != nil {
+		t.Fatalf("execute tool: %v", err)
+	}
+	if res.Output == leaky.output {
+		t.Fatal("expected output with a canary token to be withheld")
+	}
+}
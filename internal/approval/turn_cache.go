@@ -0,0 +1,70 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// TurnApprover remembers decisions for the length of one agent turn, so a
+// model retrying the identical action is not asked about twice. Only an
+// exact repeat (same tool, prompt, and args) reuses a decision, and nothing
+// outlives the turn: create a new one per turn and drop it afterwards.
+type TurnApprover struct {
+	approver Approver
+
+	mu        sync.Mutex
+	decisions map[string]ApprovalDecision
+}
+
+// NewTurnApprover wraps approver with a cache scoped to one turn. A nil
+// approver is returned as nil so "no approver configured" still surfaces.
+func NewTurnApprover(approver Approver) Approver {
+	if approver == nil {
+		return nil
+	}
+	return &TurnApprover{approver: approver, decisions: map[string]ApprovalDecision{}}
+}
+
+// RequestApproval returns the earlier decision for an identical request in
+// this turn, or asks the wrapped approver. Errors such as timeouts are not
+// remembered, so the next attempt asks again.
+func (a *TurnApprover) RequestApproval(ctx context.Context, req ApprovalRequest) (ApprovalDecision, error) {
+	key, ok := RequestKey(req)
+	if ok {
+		a.mu.Lock()
+		decision, seen := a.decisions[key]
+		a.mu.Unlock()
+		if seen {
+			return decision, nil
+		}
+	}
+	decision, err := a.approver.RequestApproval(ctx, req)
+	if err != nil || !ok {
+		return decision, err
+	}
+	a.mu.Lock()
+	a.decisions[key] = decision
+	a.mu.Unlock()
+	return decision, nil
+}
+
+// ApproverName names the wrapped approver for the policy journal.
+func (a *TurnApprover) ApproverName() string {
+	return approverName(a.approver)
+}
+
+// RequestKey identifies a request by its tool, prompt, and args, so an
+// identical request can be matched to an earlier answer. ok is false when
+// the args cannot be encoded.
+func RequestKey(req ApprovalRequest) (string, bool) {
+	encoded, err := json.Marshal(struct {
+		Tool        string         `json:"tool"`
+		Description string         `json:"description"`
+		Args        map[string]any `json:"args"`
+	}{req.Tool, req.Description, req.Args})
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
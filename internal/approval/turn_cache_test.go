@@ -0,0 +1,75 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type countingApprover struct {
+	calls    int
+	decision ApprovalDecision
+	err      error
+}
+
+func (a *countingApprover) RequestApproval(context.Context, ApprovalRequest) (ApprovalDecision, error) {
+	a.calls++
+	return a.decision, a.err
+}
+
+func (a *countingApprover) ApproverName() string {
+	return "telegram:42"
+}
+
+func TestTurnApproverReusesIdenticalDecisions(t *testing.T) {
+	inner := &countingApprover{decision: Denied}
+	approver := NewTurnApprover(inner)
+	req := ApprovalRequest{Tool: "run_command", Description: "Run Once: python3 -c 'print(1)'", Args: map[string]any{"command": "python3 -c 'print(1)'"}}
+
+	for i := 0; i < 2; i++ {
+		decision, err := approver.RequestApproval(context.Background(), req)
+		if err != nil || decision != Denied {
+			t.Fatalf("attempt %d: expected Denied, got %v (%v)", i, decision, err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected one prompt for a repeated request, got %d", inner.calls)
+	}
+
+	other := req
+	other.Args = map[string]any{"command": "python3 -c 'print(2)'"}
+	if _, err := approver.RequestApproval(context.Background(), other); err != nil {
+		t.Fatalf("request approval: %v", err)
+	}
+	if inner.calls != 2 {
+		t.Fatalf("expected different args to prompt again, got %d prompts", inner.calls)
+	}
+
+	// A new turn starts with no remembered decisions.
+	if _, err := NewTurnApprover(inner).RequestApproval(context.Background(), req); err != nil {
+		t.Fatalf("request approval: %v", err)
+	}
+	if inner.calls != 3 {
+		t.Fatalf("expected a new turn to prompt again, got %d prompts", inner.calls)
+	}
+	if got := approverName(approver); got != "telegram:42" {
+		t.Fatalf("expected wrapped approver name, got %q", got)
+	}
+}
+
+func TestTurnApproverDoesNotRememberErrors(t *testing.T) {
+	inner := &countingApprover{err: errors.New("approval timed out")}
+	approver := NewTurnApprover(inner)
+	req := ApprovalRequest{Tool: "write_file", Description: "write notes.txt"}
+	for i := 0; i < 2; i++ {
+		if _, err := approver.RequestApproval(context.Background(), req); err == nil {
+			t.Fatalf("attempt %d: expected error", i)
+		}
+	}
+	if inner.calls != 2 {
+		t.Fatalf("expected errors to be retried, got %d prompts", inner.calls)
+	}
+	if NewTurnApprover(nil) != nil {
+		t.Fatalf("expected nil approver to stay nil")
+	}
+}
@@ -11,15 +11,26 @@ import (
 	"github.com/neoclaw-ai/neoclaw/internal/store"
 )
 
+// RoleObserver marks a user who receives a read-only mirror of the
+// conversation but cannot send messages or answer approvals. Users without
+// a role have full access.
+const RoleObserver = "observer"
+
 // User is one authorized user record in allowed_users.json.
 type User struct {
 	ID       string    `json:"id"`
 	Channel  string    `json:"channel"`
 	Username string    `json:"username"`
 	Name     string    `json:"name"`
+	Role     string    `json:"role,omitempty"`
 	AddedAt  time.Time `json:"added_at"`
 }
 
+// IsObserver reports whether the user has the read-only observer role.
+func (u User) IsObserver() bool {
+	return strings.EqualFold(strings.TrimSpace(u.Role), RoleObserver)
+}
+
 // UsersFile is the on-disk shape for the allowed users store.
 type UsersFile struct {
 	Users []User `json:"users"`
@@ -77,6 +88,7 @@ func saveUsers(path string, usersFile UsersFile) error {
 }
 
 // IsAllowedUser reports whether a user ID is authorized for one channel.
+// Observers are included; callers that accept commands must check IsObserver.
 func IsAllowedUser(usersFile UsersFile, id, channel string) bool {
 	targetID := strings.TrimSpace(id)
 	targetChannel := strings.ToLower(strings.TrimSpace(channel))
@@ -92,7 +104,8 @@ func IsAllowedUser(usersFile UsersFile, id, channel string) bool {
 	return false
 }
 
-// AddUser adds or updates one id+channel user entry and atomically writes the file.
+// AddUser adds or updates one id+channel user entry and atomically writes the
+// file. The change is journaled as made by claw pair, the only caller.
 func AddUser(path string, user User) error {
 	targetID := strings.TrimSpace(user.ID)
 	targetChannel := strings.ToLower(strings.TrimSpace(user.Channel))
@@ -104,6 +117,10 @@ func AddUser(path string, user User) error {
 	}
 	username := strings.TrimSpace(user.Username)
 	name := strings.TrimSpace(user.Name)
+	role := strings.ToLower(strings.TrimSpace(user.Role))
+	if role != "" && role != RoleObserver {
+		return fmt.Errorf("unsupported user role %s", user.Role)
+	}
 
 	usersFile, err := loadCachedUsersFile(path)
 	if err != nil {
@@ -115,7 +132,8 @@ func AddUser(path string, user User) error {
 			strings.ToLower(strings.TrimSpace(usersFile.Users[i].Channel)) == targetChannel {
 			usersFile.Users[i].Username = username
 			usersFile.Users[i].Name = name
-			return saveCachedUsersFile(path, usersFile)
+			usersFile.Users[i].Role = role
+			return saveCachedUsersFile(path, usersFile, addUserNote(targetChannel, targetID, username, role))
 		}
 	}
 
@@ -129,7 +147,19 @@ func AddUser(path string, user User) error {
 		Channel:  targetChannel,
 		Username: username,
 		Name:     name,
+		Role:     role,
 		AddedAt:  addedAt,
 	})
-	return saveCachedUsersFile(path, usersFile)
+	return saveCachedUsersFile(path, usersFile, addUserNote(targetChannel, targetID, username, role))
+}
+
+func addUserNote(channel, id, username, role string) policyNote {
+	summary := fmt.Sprintf("add %s user %s", channel, id)
+	if username != "" {
+		summary += " (@" + username + ")"
+	}
+	if role != "" {
+		summary += " as " + role
+	}
+	return policyNote{actor: "claw pair", summary: summary}
 }
@@ -138,3 +138,33 @@ func TestAddUser_ExistingUserUpdatesMetadata(t *testing.T) {
 		t.Fatalf("unexpected updated name: %q", loaded.Users[0].Name)
 	}
 }
+
+func TestAddUser_ObserverRole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allowed_users.json")
+	if err := AddUser(path, User{ID: "456", Channel: "telegram", Name: "Sam", Role: "Observer"}); err != nil {
+		t.Fatalf("add observer: %v", err)
+	}
+	loaded, err := LoadUsers(path)
+	if err != nil {
+		t.Fatalf("reload users: %v", err)
+	}
+	if len(loaded.Users) != 1 || !loaded.Users[0].IsObserver() || loaded.Users[0].Role != RoleObserver {
+		t.Fatalf("expected observer role to round-trip, got %#v", loaded.Users)
+	}
+
+	// Re-pairing without a role restores full access.
+	if err := AddUser(path, User{ID: "456", Channel: "telegram", Name: "Sam"}); err != nil {
+		t.Fatalf("re-add user: %v", err)
+	}
+	loaded, err = LoadUsers(path)
+	if err != nil {
+		t.Fatalf("reload users: %v", err)
+	}
+	if loaded.Users[0].IsObserver() {
+		t.Fatalf("expected role to be cleared, got %#v", loaded.Users[0])
+	}
+
+	if err := AddUser(path, User{ID: "789", Channel: "telegram", Role: "admin"}); err == nil {
+		t.Fatalf("expected unsupported role error")
+	}
+}
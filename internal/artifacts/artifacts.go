@@ -0,0 +1,164 @@
+// Package artifacts records files the agent produced for the user so they can be listed and sent later.
+package artifacts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// Artifact is one registered output file.
+type Artifact struct {
+	ID          int       `json:"id"`
+	Path        string    `json:"path"`
+	Description string    `json:"description"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Channel is the chat the artifact was made in; empty for ones every
+	// channel can see.
+	Channel string `json:"channel,omitempty"`
+}
+
+// Name returns the artifact's file name.
+func (a Artifact) Name() string {
+	return filepath.Base(a.Path)
+}
+
+// Store persists artifacts as a JSON array.
+type Store struct {
+	mu      *sync.Mutex
+	path    string
+	channel string
+}
+
+// New creates an artifact store backed by path.
+func New(path string) *Store {
+	return &Store{mu: &sync.Mutex{}, path: path}
+}
+
+// ForChannel returns a view of the store for one chat: it registers
+// artifacts under channel and only lists and gets that chat's artifacts. An
+// empty channel returns the whole store.
+func (s *Store) ForChannel(channel string) *Store {
+	return &Store{mu: s.mu, path: s.path, channel: channel}
+}
+
+// Add registers an existing regular file. Registering the same path again
+// from the same channel refreshes its description, size, and timestamp
+// instead of adding a duplicate.
+func (s *Store) Add(path, description string, now time.Time) (Artifact, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("stat artifact: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return Artifact{}, fmt.Errorf("artifact %s is not a regular file", path)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.load()
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	artifact := Artifact{
+		Path:        path,
+		Description: strings.Join(strings.Fields(description), " "),
+		Size:        info.Size(),
+		CreatedAt:   now.UTC(),
+		Channel:     s.channel,
+	}
+	nextID := 1
+	replaced := false
+	for i, existing := range items {
+		if existing.ID >= nextID {
+			nextID = existing.ID + 1
+		}
+		if existing.Path == path && existing.Channel == s.channel {
+			artifact.ID = existing.ID
+			items[i] = artifact
+			replaced = true
+		}
+	}
+	if !replaced {
+		artifact.ID = nextID
+		items = append(items, artifact)
+	}
+	if err := s.save(items); err != nil {
+		return Artifact{}, err
+	}
+	return artifact, nil
+}
+
+// List returns artifacts newest first.
+func (s *Store) List() ([]Artifact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]Artifact, 0, len(items))
+	for i := len(items) - 1; i >= 0; i-- {
+		if s.visible(items[i]) {
+			visible = append(visible, items[i])
+		}
+	}
+	return visible, nil
+}
+
+// Get returns one artifact by ID.
+func (s *Store) Get(id int) (Artifact, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.load()
+	if err != nil {
+		return Artifact{}, false, err
+	}
+	for _, item := range items {
+		if item.ID == id && s.visible(item) {
+			return item, true, nil
+		}
+	}
+	return Artifact{}, false, nil
+}
+
+// visible reports whether item belongs to this view of the store.
+func (s *Store) visible(item Artifact) bool {
+	return s.channel == "" || item.Channel == s.channel
+}
+
+func (s *Store) load() ([]Artifact, error) {
+	raw, err := store.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read artifacts: %w", err)
+	}
+	var items []Artifact
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, fmt.Errorf("decode artifacts: %w", err)
+	}
+	return items, nil
+}
+
+func (s *Store) save(items []Artifact) error {
+	raw, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode artifacts: %w", err)
+	}
+	if err := store.WriteFile(s.path, append(raw, '\n')); err != nil {
+		return fmt.Errorf("write artifacts: %w", err)
+	}
+	return nil
+}
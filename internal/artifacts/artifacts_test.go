@@ -0,0 +1,112 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddListGet(t *testing.T) {
+	dir := t.TempDir()
+	report := writeFile(t, dir, "report.md", "# Q1\n")
+	chart := writeFile(t, dir, "chart.png", "png")
+	s := New(filepath.Join(dir, "artifacts.json"))
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	first, err := s.Add(report, "Q1 sales report", now)
+	if err != nil {
+		t.Fatalf("add report: %v", err)
+	}
+	if first.ID != 1 || first.Size != 5 || first.Name() != "report.md" {
+		t.Fatalf("unexpected artifact: %#v", first)
+	}
+	if _, err := s.Add(chart, "Sales chart", now.Add(time.Minute)); err != nil {
+		t.Fatalf("add chart: %v", err)
+	}
+	// Re-registering keeps the ID and refreshes the metadata.
+	again, err := s.Add(report, "Q1 sales report (final)", now.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("re-add report: %v", err)
+	}
+	if again.ID != 1 {
+		t.Fatalf("expected re-registered artifact to keep id 1, got %d", again.ID)
+	}
+
+	items, err := s.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 2 || items[0].ID != 2 || items[1].Description != "Q1 sales report (final)" {
+		t.Fatalf("unexpected list: %#v", items)
+	}
+
+	got, ok, err := s.Get(2)
+	if err != nil || !ok || got.Path != chart {
+		t.Fatalf("get 2: %#v ok=%v err=%v", got, ok, err)
+	}
+	if _, ok, err := s.Get(9); err != nil || ok {
+		t.Fatalf("expected missing artifact, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestAddRejectsMissingAndDirectories(t *testing.T) {
+	dir := t.TempDir()
+	s := New(filepath.Join(dir, "artifacts.json"))
+	if _, err := s.Add(filepath.Join(dir, "missing.txt"), "missing", time.Now()); err == nil {
+		t.Fatalf("expected error for missing file")
+	}
+	if _, err := s.Add(dir, "directory", time.Now()); err == nil {
+		t.Fatalf("expected error for directory")
+	}
+}
+
+func TestForChannelKeepsChatsApart(t *testing.T) {
+	dir := t.TempDir()
+	report := writeFile(t, dir, "report.md", "# Q1\n")
+	s := New(filepath.Join(dir, "artifacts.json"))
+	alice := s.ForChannel("telegram-111")
+	bob := s.ForChannel("telegram-222")
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mine, err := alice.Add(report, "Alice's report", now)
+	if err != nil {
+		t.Fatalf("add for alice: %v", err)
+	}
+	// The same file registered from another chat is a separate artifact.
+	theirs, err := bob.Add(report, "Bob's report", now)
+	if err != nil {
+		t.Fatalf("add for bob: %v", err)
+	}
+	if mine.ID == theirs.ID || mine.Channel != "telegram-111" {
+		t.Fatalf("expected separate artifacts, got %#v and %#v", mine, theirs)
+	}
+
+	items, err := bob.List()
+	if err != nil {
+		t.Fatalf("list bob: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != theirs.ID {
+		t.Fatalf("expected only bob's artifact, got %#v", items)
+	}
+	if _, ok, err := bob.Get(mine.ID); err != nil || ok {
+		t.Fatalf("expected bob not to get alice's artifact, ok=%v err=%v", ok, err)
+	}
+	// The unscoped store sees every chat's artifacts.
+	all, err := s.List()
+	if err != nil {
+		t.Fatalf("list all: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected both artifacts in the whole store, got %#v", all)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
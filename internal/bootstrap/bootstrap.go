@@ -42,6 +42,31 @@ var defaultAllowedCommands = []string{
 	"whoami *",
 }
 
+// defaultAllowedBins are the programs behind defaultAllowedCommands, for
+// security.unlisted_bins. cd is a shell builtin and needs no entry.
+var defaultAllowedBins = []string{
+	"cat",
+	"curl",
+	"cut",
+	"expr",
+	"find",
+	"grep",
+	"head",
+	"id",
+	"ls",
+	"paste",
+	"rev",
+	"seq",
+	"stat",
+	"tail",
+	"tr",
+	"uname",
+	"uniq",
+	"wc",
+	"which",
+	"whoami",
+}
+
 // Initialize creates the expected NeoClaw data tree if missing.
 func Initialize(cfg *config.Config) error {
 	agentDir := cfg.AgentDir()
@@ -80,12 +105,14 @@ func Initialize(cfg *config.Config) error {
 		{path: cfg.AllowedDomainsPath(), content: defaultAllowedDomainsJSON()},
 		{path: cfg.AllowedCommandsPath(), content: defaultAllowedCommandsJSON()},
 		{path: cfg.AllowedUsersPath(), content: defaultAllowedUsersJSON()},
+		{path: cfg.AllowedBinsPath(), content: defaultAllowedBinsJSON()},
 		{path: cfg.CostsPath(), content: "ts\tprovider\tmodel\tinput_tokens\toutput_tokens\ttotal_tokens\tcost_usd\n"},
 
 		{path: cfg.SoulPath(), content: defaultSoulMarkdown()},
 		{path: cfg.UserPath(), content: defaultUserMarkdown()},
 		{path: cfg.JobsPath(), content: "[]\n"},
 		{path: cfg.MemoryPath(), content: "ts\ttags\ttext\tkv\n"},
+		{path: cfg.ExpensesPath(), content: "ts\tdate\tamount\tcurrency\tcategory\tmerchant\tnote\n"},
 		{path: cfg.CLIContextPath(), content: ""},
 	}
 
@@ -135,6 +162,17 @@ func defaultAllowedCommandsJSON() string {
 	return string(b) + "\n"
 }
 
+func defaultAllowedBinsJSON() string {
+	payload := map[string][]string{
+		"allow": defaultAllowedBins,
+	}
+	b, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return "{\n  \"allow\": []\n}\n"
+	}
+	return string(b) + "\n"
+}
+
 func defaultAllowedUsersJSON() string {
 	return "{\n  \"users\": []\n}\n"
 }
@@ -26,12 +26,14 @@ func TestInitializeCreatesRequiredFilesAndDirs(t *testing.T) {
 		cfg.AllowedDomainsPath(),
 		cfg.AllowedCommandsPath(),
 		cfg.AllowedUsersPath(),
+		cfg.AllowedBinsPath(),
 		cfg.LogsDir(),
 		cfg.CostsPath(),
 		cfg.SoulPath(),
 		cfg.UserPath(),
 		cfg.JobsPath(),
 		cfg.MemoryPath(),
+		cfg.ExpensesPath(),
 		cfg.CLIContextPath(),
 		cfg.WorkspaceDir(),
 	}
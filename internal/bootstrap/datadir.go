@@ -0,0 +1,107 @@
+package bootstrap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// containerMarkers are files container runtimes create in every container.
+var containerMarkers = []string{"/.dockerenv", "/run/.containerenv"}
+
+// mountInfoPath lists the mounts visible to this process on Linux.
+var mountInfoPath = "/proc/self/mountinfo"
+
+// CheckWritable makes sure dir exists and files can be created in it, and
+// explains what to do when they cannot.
+func CheckWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("NeoClaw home %s cannot be created: %w. Set NEOCLAW_HOME to a writable directory%s", dir, err, containerHint())
+	}
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("NeoClaw home %s is not writable: %w. Fix its permissions or set NEOCLAW_HOME to a writable directory%s", dir, err, containerHint())
+	}
+	name := probe.Name()
+	probe.Close()
+	os.Remove(name)
+	return nil
+}
+
+// InContainer reports whether this process runs inside a container.
+func InContainer() bool {
+	for _, marker := range containerMarkers {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// OnVolume reports whether dir lives on a mount other than the root
+// filesystem, i.e. on a volume or bind mount when run in a container. ok is
+// false when this cannot be told, e.g. outside Linux.
+func OnVolume(dir string) (onVolume, ok bool) {
+	mounts, err := mountPoints()
+	if err != nil {
+		return false, false
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return false, false
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	for _, mount := range mounts {
+		if mount == "/" {
+			continue
+		}
+		if dir == mount || strings.HasPrefix(dir, mount+string(filepath.Separator)) {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+func containerHint() string {
+	if !InContainer() {
+		return ""
+	}
+	return ". In a container, mount a volume there (for example -v neoclaw-data:/data with NEOCLAW_HOME=/data) and make sure the container user can write to it"
+}
+
+// mountPoints returns the mount points in /proc/self/mountinfo; the fifth
+// field of each line is the mount point.
+func mountPoints() ([]string, error) {
+	file, err := os.Open(mountInfoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var mounts []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		mounts = append(mounts, unescapeMountPath(fields[4]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(mounts) == 0 {
+		return nil, errors.New("no mounts listed")
+	}
+	return mounts, nil
+}
+
+// unescapeMountPath undoes the octal escapes mountinfo uses for spaces,
+// tabs, newlines, and backslashes.
+func unescapeMountPath(path string) string {
+	return strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`).Replace(path)
+}
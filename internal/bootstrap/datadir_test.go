@@ -0,0 +1,56 @@
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckWritable(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "home")
+	if err := CheckWritable(dir); err != nil {
+		t.Fatalf("expected writable dir, got %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected probe file removed, got %v (%v)", entries, err)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	readOnly := filepath.Join(t.TempDir(), "ro")
+	if err := os.Mkdir(readOnly, 0o555); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := CheckWritable(readOnly); err == nil || !strings.Contains(err.Error(), "not writable") {
+		t.Fatalf("expected not writable error, got %v", err)
+	}
+}
+
+func TestOnVolumeReadsMountInfo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mountinfo")
+	content := "22 1 0:21 / / rw - overlay overlay rw\n" +
+		"35 22 8:1 /var/lib/docker/volumes/data /data rw - ext4 /dev/sda1 rw\n" +
+		"36 22 8:1 /home/me/notes /srv/my\\040notes rw - ext4 /dev/sda1 rw\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write mountinfo: %v", err)
+	}
+	old := mountInfoPath
+	mountInfoPath = path
+	t.Cleanup(func() { mountInfoPath = old })
+
+	cases := map[string]bool{
+		"/data":              true,
+		"/data/neoclaw":      true,
+		"/srv/my notes/claw": true,
+		"/database":          false,
+		"/root/.neoclaw":     false,
+	}
+	for dir, want := range cases {
+		if got, ok := OnVolume(dir); !ok || got != want {
+			t.Errorf("OnVolume(%q) = %v, %v; want %v", dir, got, ok, want)
+		}
+	}
+}
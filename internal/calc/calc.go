@@ -0,0 +1,321 @@
+// Package calc evaluates arithmetic expressions and converts units and currencies deterministically, so numbers never depend on the model.
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const maxExpressionLength = 1000
+
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// functions maps names to implementations and their accepted argument counts.
+var functions = map[string]struct {
+	minArgs, maxArgs int
+	fn               func(args []float64) (float64, error)
+}{
+	"sqrt":  {1, 1, func(a []float64) (float64, error) { return math.Sqrt(a[0]), nil }},
+	"abs":   {1, 1, func(a []float64) (float64, error) { return math.Abs(a[0]), nil }},
+	"floor": {1, 1, func(a []float64) (float64, error) { return math.Floor(a[0]), nil }},
+	"ceil":  {1, 1, func(a []float64) (float64, error) { return math.Ceil(a[0]), nil }},
+	"ln":    {1, 1, func(a []float64) (float64, error) { return math.Log(a[0]), nil }},
+	"log":   {1, 1, func(a []float64) (float64, error) { return math.Log10(a[0]), nil }},
+	"exp":   {1, 1, func(a []float64) (float64, error) { return math.Exp(a[0]), nil }},
+	"sin":   {1, 1, func(a []float64) (float64, error) { return math.Sin(a[0]), nil }},
+	"cos":   {1, 1, func(a []float64) (float64, error) { return math.Cos(a[0]), nil }},
+	"tan":   {1, 1, func(a []float64) (float64, error) { return math.Tan(a[0]), nil }},
+	"pow":   {2, 2, func(a []float64) (float64, error) { return math.Pow(a[0], a[1]), nil }},
+	"min":   {1, 64, func(a []float64) (float64, error) { return reduce(a, math.Min), nil }},
+	"max":   {1, 64, func(a []float64) (float64, error) { return reduce(a, math.Max), nil }},
+	"round": {1, 2, func(a []float64) (float64, error) {
+		if len(a) == 1 {
+			return math.Round(a[0]), nil
+		}
+		scale := math.Pow(10, math.Trunc(a[1]))
+		return math.Round(a[0]*scale) / scale, nil
+	}},
+}
+
+// Eval evaluates an arithmetic expression with + - * / % ^, parentheses,
+// the constants pi and e, and the functions sqrt, abs, floor, ceil, round,
+// ln, log, exp, sin, cos, tan, pow, min and max. ^ is exponentiation and
+// binds tighter than unary minus, so -2^2 is -4. Thousands separators are
+// not accepted; use _ instead (1_000).
+func Eval(expression string) (float64, error) {
+	if strings.TrimSpace(expression) == "" {
+		return 0, errors.New("expression is required")
+	}
+	if len(expression) > maxExpressionLength {
+		return 0, fmt.Errorf("expression is longer than %d characters", maxExpressionLength)
+	}
+	p := &parser{input: expression}
+	p.next()
+	value, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	if p.tok.kind != tokEOF {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.tok.text, p.tok.pos+1)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, errors.New("result is not a finite number")
+	}
+	return value, nil
+}
+
+// Format renders a result without float noise such as 0.30000000000000004.
+func Format(value float64) string {
+	abs := math.Abs(value)
+	if abs != 0 && (abs >= 1e15 || abs < 1e-9) {
+		return strconv.FormatFloat(value, 'g', 12, 64)
+	}
+	text := strconv.FormatFloat(value, 'f', 10, 64)
+	text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	if text == "-0" {
+		return "0"
+	}
+	return text
+}
+
+func reduce(values []float64, fn func(a, b float64) float64) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		result = fn(result, v)
+	}
+	return result
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+type parser struct {
+	input string
+	pos   int
+	tok   token
+	err   error
+}
+
+// next advances to the following token. Invalid numbers are recorded in
+// p.err and reported by parsePrimary.
+func (p *parser) next() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.input) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	c := p.input[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		p.pos++
+		for p.pos < len(p.input) && isNumberByte(p.input[p.pos], p.input[p.pos-1]) {
+			p.pos++
+		}
+		text := p.input[start:p.pos]
+		value, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64)
+		if err != nil {
+			p.tok = token{kind: tokOp, text: text, pos: start}
+			p.err = fmt.Errorf("invalid number %q", text)
+			return
+		}
+		p.tok = token{kind: tokNumber, text: text, value: value, pos: start}
+	case unicode.IsLetter(rune(c)):
+		for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+		p.tok = token{kind: tokIdent, text: strings.ToLower(p.input[start:p.pos]), pos: start}
+	default:
+		p.pos++
+		if c == '*' && p.pos < len(p.input) && p.input[p.pos] == '*' {
+			// Accept ** as exponentiation.
+			p.pos++
+			p.tok = token{kind: tokOp, text: "^", pos: start}
+			return
+		}
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+	}
+}
+
+func isNumberByte(c, prev byte) bool {
+	switch {
+	case c >= '0' && c <= '9', c == '.', c == '_':
+		return true
+	case c == 'e' || c == 'E':
+		return true
+	case (c == '+' || c == '-') && (prev == 'e' || prev == 'E'):
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseSum() (float64, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for p.tok.kind == tokOp && (p.tok.text == "+" || p.tok.text == "-") {
+		op := p.tok.text
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+		if op == "+" {
+			left += right
+		} else {
+			left -= right
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseProduct() (float64, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for p.tok.kind == tokOp && (p.tok.text == "*" || p.tok.text == "/" || p.tok.text == "%") {
+		op := p.tok.text
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "*":
+			left *= right
+		case "/":
+			if right == 0 {
+				return 0, errors.New("division by zero")
+			}
+			left /= right
+		case "%":
+			if right == 0 {
+				return 0, errors.New("modulo by zero")
+			}
+			left = math.Mod(left, right)
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (float64, error) {
+	if p.tok.kind == tokOp && (p.tok.text == "-" || p.tok.text == "+") {
+		negate := p.tok.text == "-"
+		p.next()
+		value, err := p.parseUnary()
+		if negate {
+			value = -value
+		}
+		return value, err
+	}
+	return p.parsePower()
+}
+
+func (p *parser) parsePower() (float64, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return 0, err
+	}
+	if p.tok.kind == tokOp && p.tok.text == "^" {
+		p.next()
+		// Right-associative: 2^3^2 is 2^9.
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		return math.Pow(base, exponent), nil
+	}
+	return base, nil
+}
+
+func (p *parser) parsePrimary() (float64, error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		p.next()
+		return tok.value, nil
+	case tokIdent:
+		p.next()
+		if p.tok.kind == tokOp && p.tok.text == "(" {
+			return p.parseCall(tok)
+		}
+		if value, ok := constants[tok.text]; ok {
+			return value, nil
+		}
+		return 0, fmt.Errorf("unknown name %q", tok.text)
+	case tokOp:
+		if tok.text == "(" {
+			p.next()
+			value, err := p.parseSum()
+			if err != nil {
+				return 0, err
+			}
+			if p.tok.kind != tokOp || p.tok.text != ")" {
+				return 0, fmt.Errorf("missing ) at position %d", p.tok.pos+1)
+			}
+			p.next()
+			return value, nil
+		}
+		return 0, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	default:
+		return 0, errors.New("unexpected end of expression")
+	}
+}
+
+func (p *parser) parseCall(name token) (float64, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return 0, fmt.Errorf("unknown function %q", name.text)
+	}
+	p.next() // (
+	var args []float64
+	if p.tok.kind != tokOp || p.tok.text != ")" {
+		for {
+			value, err := p.parseSum()
+			if err != nil {
+				return 0, err
+			}
+			args = append(args, value)
+			if p.tok.kind == tokOp && p.tok.text == "," {
+				p.next()
+				continue
+			}
+			break
+		}
+	}
+	if p.tok.kind != tokOp || p.tok.text != ")" {
+		return 0, fmt.Errorf("missing ) after %s arguments", name.text)
+	}
+	p.next()
+	if len(args) < fn.minArgs || len(args) > fn.maxArgs {
+		return 0, fmt.Errorf("%s takes %d to %d arguments, got %d", name.text, fn.minArgs, fn.maxArgs, len(args))
+	}
+	return fn.fn(args)
+}
@@ -0,0 +1,96 @@
+package calc
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"2 ^ 3 ^ 2", 512},
+		{"2 ** 10", 1024},
+		{"-2^2", -4},
+		{"10 % 4", 2},
+		{"1_000 / 8", 125},
+		{"1.5e3 + .5", 1500.5},
+		{"round(pi, 2)", 3.14},
+		{"max(1, sqrt(16), 3)", 4},
+		{"250000*0.005/(1-(1+0.005)^-360)", 1498.876313},
+	}
+	for _, tt := range tests {
+		got, err := Eval(tt.expression)
+		if err != nil {
+			t.Fatalf("eval %q: %v", tt.expression, err)
+		}
+		if math.Abs(got-tt.want) > 1e-6 {
+			t.Fatalf("eval %q = %v, want %v", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	for expression, want := range map[string]string{
+		"":          "expression is required",
+		"1 / 0":     "division by zero",
+		"1,000 + 1": `unexpected ","`,
+		"(1 + 2":    "missing )",
+		"foo(1)":    "unknown function",
+		"x + 1":     "unknown name",
+		"sqrt(-1)":  "not a finite number",
+		"pow(2)":    "takes 2 to 2 arguments",
+		"1..2":      "invalid number",
+	} {
+		if _, err := Eval(expression); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("eval %q: expected error containing %q, got %v", expression, want, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for value, want := range map[float64]string{
+		0.1 + 0.2: "0.3",
+		1260:      "1260",
+		-0.5:      "-0.5",
+		2e20:      "2e+20",
+	} {
+		if got := Format(value); got != want {
+			t.Fatalf("format %v = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestConvertUnit(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{5, "mi", "km", 8.04672},
+		{212, "F", "C", 100},
+		{0, "celsius", "K", 273.15},
+		{1, "GiB", "MB", 1073.741824},
+		{2, "cups", "ml", 473.176473},
+		{60, "mph", "km/h", 96.56064},
+	}
+	for _, tt := range tests {
+		got, err := ConvertUnit(tt.value, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("convert %v %s to %s: %v", tt.value, tt.from, tt.to, err)
+		}
+		if math.Abs(got-tt.want) > 1e-6 {
+			t.Fatalf("convert %v %s to %s = %v, want %v", tt.value, tt.from, tt.to, got, tt.want)
+		}
+	}
+	if _, err := ConvertUnit(1, "kg", "km"); err == nil || !strings.Contains(err.Error(), "cannot convert") {
+		t.Fatalf("expected dimension mismatch error, got %v", err)
+	}
+	if _, err := ConvertUnit(1, "parsec", "km"); err == nil {
+		t.Fatalf("expected unknown unit error")
+	}
+}
@@ -0,0 +1,170 @@
+package calc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// DefaultFXEndpoint serves European Central Bank reference rates without an
+// API key.
+const DefaultFXEndpoint = "https://api.frankfurter.app/latest"
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+var fxMu sync.Mutex
+
+// IsCurrency reports whether code looks like an ISO 4217 currency code. Codes
+// must be upper case so they never shadow units such as cup or min.
+func IsCurrency(code string) bool {
+	return currencyPattern.MatchString(strings.TrimSpace(code))
+}
+
+// FXRates converts between currencies using reference rates fetched at most
+// once a day and cached in a JSON file.
+type FXRates struct {
+	Path     string
+	Client   *http.Client
+	Endpoint string
+
+	now func() time.Time
+}
+
+type fxCache struct {
+	// Fetched is the local date the rates were downloaded.
+	Fetched string `json:"fetched"`
+	// Date is the publication date reported by the source.
+	Date  string             `json:"date"`
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Convert converts amount from one currency to another. It also returns the
+// publication date of the rates used. When today's rates cannot be fetched,
+// the last cached rates are used instead.
+func (r *FXRates) Convert(ctx context.Context, amount float64, from, to string) (float64, string, error) {
+	from = strings.TrimSpace(from)
+	to = strings.TrimSpace(to)
+	if !IsCurrency(from) || !IsCurrency(to) {
+		return 0, "", fmt.Errorf("currency codes must be three upper-case letters, got %q and %q", from, to)
+	}
+	rates, err := r.load(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	fromRate, ok := rates.rate(from)
+	if !ok {
+		return 0, "", fmt.Errorf("no exchange rate for %s", from)
+	}
+	toRate, ok := rates.rate(to)
+	if !ok {
+		return 0, "", fmt.Errorf("no exchange rate for %s", to)
+	}
+	return amount / fromRate * toRate, rates.Date, nil
+}
+
+func (c fxCache) rate(code string) (float64, bool) {
+	if code == c.Base {
+		return 1, true
+	}
+	rate, ok := c.Rates[code]
+	return rate, ok && rate > 0
+}
+
+func (r *FXRates) load(ctx context.Context) (fxCache, error) {
+	fxMu.Lock()
+	defer fxMu.Unlock()
+
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
+	today := now().Format("2006-01-02")
+
+	cached, cacheErr := r.readCache()
+	if cacheErr == nil && cached.Fetched == today {
+		return cached, nil
+	}
+	fetched, err := r.fetch(ctx)
+	if err != nil {
+		if cacheErr == nil {
+			logging.Logger().Warn("using cached exchange rates", "date", cached.Date, "err", err)
+			return cached, nil
+		}
+		return fxCache{}, err
+	}
+	fetched.Fetched = today
+	encoded, err := json.Marshal(fetched)
+	if err != nil {
+		return fxCache{}, fmt.Errorf("marshal exchange rates: %w", err)
+	}
+	if err := store.WriteFile(r.Path, append(encoded, '\n')); err != nil {
+		return fxCache{}, fmt.Errorf("write exchange rates: %w", err)
+	}
+	return fetched, nil
+}
+
+func (r *FXRates) readCache() (fxCache, error) {
+	if strings.TrimSpace(r.Path) == "" {
+		return fxCache{}, errors.New("exchange rate cache path is required")
+	}
+	raw, err := store.ReadFile(r.Path)
+	if err != nil {
+		return fxCache{}, err
+	}
+	var cached fxCache
+	if err := json.Unmarshal([]byte(raw), &cached); err != nil {
+		return fxCache{}, fmt.Errorf("parse exchange rates: %w", err)
+	}
+	if cached.Base == "" || len(cached.Rates) == 0 {
+		return fxCache{}, os.ErrNotExist
+	}
+	return cached, nil
+}
+
+func (r *FXRates) fetch(ctx context.Context) (fxCache, error) {
+	if r.Client == nil {
+		return fxCache{}, errors.New("http client is required")
+	}
+	endpoint := r.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultFXEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fxCache{}, fmt.Errorf("create exchange rate request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "NeoClaw")
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return fxCache{}, fmt.Errorf("fetch exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fxCache{}, fmt.Errorf("fetch exchange rates: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fxCache{}, fmt.Errorf("read exchange rates: %w", err)
+	}
+	var rates fxCache
+	if err := json.Unmarshal(body, &rates); err != nil {
+		return fxCache{}, fmt.Errorf("decode exchange rates: %w", err)
+	}
+	if rates.Base == "" || len(rates.Rates) == 0 {
+		return fxCache{}, errors.New("exchange rate response has no rates")
+	}
+	return rates, nil
+}
@@ -0,0 +1,64 @@
+package calc
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFXRatesFetchesOncePerDayAndFallsBackToCache(t *testing.T) {
+	requests := 0
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		if failing {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"amount":1.0,"base":"EUR","date":"2026-10-15","rates":{"USD":1.1,"GBP":0.85}}`))
+	}))
+	defer server.Close()
+
+	day := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	rates := &FXRates{
+		Path:     filepath.Join(t.TempDir(), "fx_rates.json"),
+		Client:   server.Client(),
+		Endpoint: server.URL,
+		now:      func() time.Time { return day },
+	}
+	ctx := context.Background()
+
+	got, date, err := rates.Convert(ctx, 110, "USD", "GBP")
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	if math.Abs(got-85) > 1e-9 || date != "2026-10-15" {
+		t.Fatalf("unexpected conversion %v on %s", got, date)
+	}
+	if _, _, err := rates.Convert(ctx, 10, "EUR", "USD"); err != nil {
+		t.Fatalf("convert from base: %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected one fetch per day, got %d", requests)
+	}
+
+	day = day.AddDate(0, 0, 1)
+	failing = true
+	if _, _, err := rates.Convert(ctx, 10, "EUR", "USD"); err != nil {
+		t.Fatalf("expected cached rates when the source is down, got %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected a refresh attempt on the next day, got %d", requests)
+	}
+
+	if _, _, err := rates.Convert(ctx, 1, "USD", "XYZ"); err == nil {
+		t.Fatalf("expected unknown currency error")
+	}
+	if _, _, err := rates.Convert(ctx, 1, "usd", "EUR"); err == nil {
+		t.Fatalf("expected lower-case code to be rejected")
+	}
+}
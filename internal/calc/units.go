@@ -0,0 +1,129 @@
+package calc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unit is a measurement unit expressed as a factor of its dimension's base
+// unit. Temperatures also carry an offset.
+type unit struct {
+	dimension string
+	factor    float64
+	offset    float64
+}
+
+var units = map[string]unit{}
+
+func init() {
+	add := func(dimension string, factor float64, names ...string) {
+		for _, name := range names {
+			units[strings.ToLower(name)] = unit{dimension: dimension, factor: factor}
+		}
+	}
+	add("length", 1, "m", "meter", "meters", "metre", "metres")
+	add("length", 1000, "km", "kilometer", "kilometers", "kilometre", "kilometres")
+	add("length", 0.01, "cm", "centimeter", "centimeters")
+	add("length", 0.001, "mm", "millimeter", "millimeters")
+	add("length", 1609.344, "mi", "mile", "miles")
+	add("length", 0.9144, "yd", "yard", "yards")
+	add("length", 0.3048, "ft", "foot", "feet")
+	add("length", 0.0254, "in", "inch", "inches")
+	add("length", 1852, "nmi", "nautical_mile", "nautical_miles")
+
+	add("mass", 1, "kg", "kilogram", "kilograms")
+	add("mass", 0.001, "g", "gram", "grams")
+	add("mass", 1e-6, "mg", "milligram", "milligrams")
+	add("mass", 1000, "t", "tonne", "tonnes")
+	add("mass", 0.45359237, "lb", "lbs", "pound", "pounds")
+	add("mass", 0.028349523125, "oz", "ounce", "ounces")
+	add("mass", 6.35029318, "st", "stone", "stones")
+
+	add("volume", 1, "l", "liter", "liters", "litre", "litres")
+	add("volume", 0.001, "ml", "milliliter", "milliliters", "millilitre", "millilitres")
+	add("volume", 1000, "m3", "cubic_meter", "cubic_meters")
+	add("volume", 3.785411784, "gal", "gallon", "gallons")
+	add("volume", 0.946352946, "qt", "quart", "quarts")
+	add("volume", 0.473176473, "pt", "pint", "pints")
+	add("volume", 0.2365882365, "cup", "cups")
+	add("volume", 0.0295735295625, "floz", "fl_oz", "fluid_ounce", "fluid_ounces")
+	add("volume", 0.01478676478125, "tbsp", "tablespoon", "tablespoons")
+	add("volume", 0.00492892159375, "tsp", "teaspoon", "teaspoons")
+
+	add("area", 1, "m2", "sqm", "square_meter", "square_meters")
+	add("area", 1e6, "km2", "square_kilometer", "square_kilometers")
+	add("area", 0.09290304, "ft2", "sqft", "square_foot", "square_feet")
+	add("area", 2589988.110336, "mi2", "square_mile", "square_miles")
+	add("area", 4046.8564224, "acre", "acres")
+	add("area", 10000, "ha", "hectare", "hectares")
+
+	add("time", 1, "s", "sec", "second", "seconds")
+	add("time", 0.001, "ms", "millisecond", "milliseconds")
+	add("time", 60, "min", "minute", "minutes")
+	add("time", 3600, "h", "hr", "hour", "hours")
+	add("time", 86400, "d", "day", "days")
+	add("time", 604800, "wk", "week", "weeks")
+	add("time", 31557600, "yr", "year", "years")
+
+	add("speed", 1, "m/s", "mps")
+	add("speed", 1000.0/3600, "km/h", "kmh", "kph")
+	add("speed", 1609.344/3600, "mph")
+	add("speed", 1852.0/3600, "kn", "knot", "knots")
+
+	add("data", 1, "b", "byte", "bytes")
+	add("data", 1e3, "kb", "kilobyte", "kilobytes")
+	add("data", 1e6, "mb", "megabyte", "megabytes")
+	add("data", 1e9, "gb", "gigabyte", "gigabytes")
+	add("data", 1e12, "tb", "terabyte", "terabytes")
+	add("data", 1<<10, "kib")
+	add("data", 1<<20, "mib")
+	add("data", 1<<30, "gib")
+	add("data", 1<<40, "tib")
+
+	add("energy", 1, "j", "joule", "joules")
+	add("energy", 1000, "kj", "kilojoule", "kilojoules")
+	add("energy", 4.184, "cal", "calorie", "calories")
+	add("energy", 4184, "kcal", "kilocalorie", "kilocalories")
+	add("energy", 3600, "wh")
+	add("energy", 3.6e6, "kwh")
+
+	// Temperatures convert through kelvin: base = value*factor + offset.
+	for _, name := range []string{"k", "kelvin"} {
+		units[name] = unit{dimension: "temperature", factor: 1}
+	}
+	for _, name := range []string{"c", "celsius", "°c"} {
+		units[name] = unit{dimension: "temperature", factor: 1, offset: 273.15}
+	}
+	for _, name := range []string{"f", "fahrenheit", "°f"} {
+		units[name] = unit{dimension: "temperature", factor: 5.0 / 9, offset: 273.15 - 32*5.0/9}
+	}
+}
+
+// IsUnit reports whether name is a known measurement unit.
+func IsUnit(name string) bool {
+	_, ok := units[normalizeUnit(name)]
+	return ok
+}
+
+// ConvertUnit converts value between two units of the same dimension, such
+// as mi to km or F to C.
+func ConvertUnit(value float64, from, to string) (float64, error) {
+	fromUnit, ok := units[normalizeUnit(from)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	toUnit, ok := units[normalizeUnit(to)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if fromUnit.dimension != toUnit.dimension {
+		return 0, fmt.Errorf("cannot convert %s (%s) to %s (%s)", from, fromUnit.dimension, to, toUnit.dimension)
+	}
+	base := value*fromUnit.factor + fromUnit.offset
+	return (base - toUnit.offset) / toUnit.factor, nil
+}
+
+func normalizeUnit(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.ReplaceAll(name, " ", "_")
+}
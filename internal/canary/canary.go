@@ -0,0 +1,187 @@
+// Package canary plants fake credentials in the workspace and raises an alarm when a tool call touches them, a tripwire for prompt-injected data theft.
+package canary
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// Canary is one planted file. Path is relative to the workspace; Tokens
+// are the fake secrets written into it.
+type Canary struct {
+	Path   string   `json:"path"`
+	Tokens []string `json:"tokens"`
+}
+
+// Set is the canaries planted in one workspace.
+type Set struct {
+	canaries []Canary
+}
+
+// bait is a canary file and the template its fake secrets are written into.
+type bait struct {
+	path   string
+	render func(tokens []string) string
+	// newTokens generates the file's fake secrets.
+	newTokens func() []string
+}
+
+var baits = []bait{
+	{
+		path: ".env.production",
+		newTokens: func() []string {
+			return []string{"AKIA" + randomString(base32Upper, 16), randomString(base64ish, 40), "sk_live_" + randomString(alnum, 24)}
+		},
+		render: func(tokens []string) string {
+			return "# Production credentials. Do not commit.\n" +
+				"AWS_ACCESS_KEY_ID=" + tokens[0] + "\n" +
+				"AWS_SECRET_ACCESS_KEY=" + tokens[1] + "\n" +
+				"STRIPE_SECRET_KEY=" + tokens[2] + "\n"
+		},
+	},
+	{
+		path: filepath.Join("backups", "db_credentials.txt"),
+		newTokens: func() []string {
+			return []string{randomString(alnum, 20)}
+		},
+		render: func(tokens []string) string {
+			return "host: db-prod-1.internal\nuser: admin\npassword: " + tokens[0] + "\n"
+		},
+	},
+}
+
+const (
+	alnum       = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	base32Upper = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+	base64ish   = alnum + "/+"
+)
+
+// Plant writes the canary files into workspaceDir and returns the set.
+// Tokens are kept in statePath so they stay the same across restarts, and
+// a deleted canary is written again. A file at a canary's path that
+// NeoClaw did not plant is left alone and that canary is skipped.
+func Plant(workspaceDir, statePath string) (*Set, error) {
+	planted, err := loadState(statePath)
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]Canary, len(planted))
+	for _, c := range planted {
+		byPath[c.Path] = c
+	}
+
+	set := &Set{}
+	changed := false
+	for _, b := range baits {
+		c, known := byPath[b.path]
+		path := filepath.Join(workspaceDir, b.path)
+		_, statErr := os.Stat(path)
+		switch {
+		case statErr == nil && !known:
+			logging.Logger().Warn("canary path already exists; skipping it", "path", path)
+			continue
+		case statErr == nil:
+			set.canaries = append(set.canaries, c)
+			continue
+		case !errors.Is(statErr, fs.ErrNotExist):
+			return nil, fmt.Errorf("check canary %s: %w", path, statErr)
+		}
+		if !known {
+			c = Canary{Path: b.path, Tokens: b.newTokens()}
+			changed = true
+		}
+		if err := store.WriteFile(path, []byte(b.render(c.Tokens))); err != nil {
+			return nil, fmt.Errorf("plant canary %s: %w", path, err)
+		}
+		set.canaries = append(set.canaries, c)
+	}
+	if changed {
+		if err := saveState(statePath, set.canaries); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// Canaries returns the planted canaries.
+func (s *Set) Canaries() []Canary {
+	if s == nil {
+		return nil
+	}
+	return append([]Canary(nil), s.canaries...)
+}
+
+// matchToken returns the canary whose token appears in text.
+func (s *Set) matchToken(text string) (Canary, bool) {
+	if s == nil {
+		return Canary{}, false
+	}
+	for _, c := range s.canaries {
+		for _, token := range c.Tokens {
+			if strings.Contains(text, token) {
+				return c, true
+			}
+		}
+	}
+	return Canary{}, false
+}
+
+// matchPath returns the canary whose file text names. Both the path
+// relative to the workspace and the absolute path count.
+func (s *Set) matchPath(text string) (Canary, bool) {
+	if s == nil {
+		return Canary{}, false
+	}
+	normalized := filepath.ToSlash(text)
+	for _, c := range s.canaries {
+		if strings.Contains(normalized, filepath.ToSlash(c.Path)) {
+			return c, true
+		}
+	}
+	return Canary{}, false
+}
+
+func loadState(path string) ([]Canary, error) {
+	raw, err := store.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read canaries: %w", err)
+	}
+	var canaries []Canary
+	if err := json.Unmarshal([]byte(raw), &canaries); err != nil {
+		return nil, fmt.Errorf("decode canaries %s: %w", path, err)
+	}
+	return canaries, nil
+}
+
+func saveState(path string, canaries []Canary) error {
+	encoded, err := json.MarshalIndent(canaries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode canaries: %w", err)
+	}
+	if err := store.WriteFile(path, append(encoded, '\n')); err != nil {
+		return fmt.Errorf("write canaries: %w", err)
+	}
+	return nil
+}
+
+func randomString(alphabet string, n int) string {
+	raw := make([]byte, n)
+	rand.Read(raw)
+	out := make([]byte, n)
+	for i, b := range raw {
+		out[i] = alphabet[int(b)%len(alphabet)]
+	}
+	return string(out)
+}
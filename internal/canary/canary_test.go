@@ -0,0 +1,69 @@
+package canary
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlantKeepsTokensAcrossRestarts(t *testing.T) {
+	workspace := t.TempDir()
+	statePath := filepath.Join(t.TempDir(), "canaries.json")
+
+	first, err := Plant(workspace, statePath)
+	if err != nil {
+		t.Fatalf("plant: %v", err)
+	}
+	if len(first.Canaries()) != len(baits) {
+		t.Fatalf("expected %d canaries, got %d", len(baits), len(first.Canaries()))
+	}
+	env := first.Canaries()[0]
+	raw, err := os.ReadFile(filepath.Join(workspace, env.Path))
+	if err != nil {
+		t.Fatalf("read canary: %v", err)
+	}
+	for _, token := range env.Tokens {
+		if !strings.Contains(string(raw), token) {
+			t.Fatalf("expected token %q in %s", token, raw)
+		}
+	}
+
+	// A deleted canary comes back with the same tokens.
+	if err := os.Remove(filepath.Join(workspace, env.Path)); err != nil {
+		t.Fatalf("remove canary: %v", err)
+	}
+	second, err := Plant(workspace, statePath)
+	if err != nil {
+		t.Fatalf("plant again: %v", err)
+	}
+	again, err := os.ReadFile(filepath.Join(workspace, env.Path))
+	if err != nil {
+		t.Fatalf("read replanted canary: %v", err)
+	}
+	if string(again) != string(raw) || second.Canaries()[0].Tokens[0] != env.Tokens[0] {
+		t.Fatalf("expected the same canary after a restart, got %q", again)
+	}
+}
+
+func TestPlantLeavesExistingFilesAlone(t *testing.T) {
+	workspace := t.TempDir()
+	userEnv := filepath.Join(workspace, ".env.production")
+	if err := os.WriteFile(userEnv, []byte("REAL=1\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+
+	set, err := Plant(workspace, filepath.Join(t.TempDir(), "canaries.json"))
+	if err != nil {
+		t.Fatalf("plant: %v", err)
+	}
+	raw, _ := os.ReadFile(userEnv)
+	if string(raw) != "REAL=1\n" {
+		t.Fatalf("expected the user's file untouched, got %q", raw)
+	}
+	for _, c := range set.Canaries() {
+		if c.Path == ".env.production" {
+			t.Fatal("expected the existing file not to be treated as a canary")
+		}
+	}
+}
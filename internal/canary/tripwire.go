@@ -0,0 +1,149 @@
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// Alert kinds.
+const (
+	// AlertAccess is a tool call that names a canary file.
+	AlertAccess = "access"
+	// AlertExfiltration is a tool call whose arguments carry a canary token,
+	// such as a request body or a command that sends it somewhere.
+	AlertExfiltration = "exfiltration"
+	// AlertRead is tool output that contains a canary token.
+	AlertRead = "read"
+)
+
+// withheldOutput replaces tool output that contains a canary token, so the
+// token never reaches the model.
+const withheldOutput = "Output withheld: it contained a canary credential planted to detect data theft. The user has been alerted."
+
+// Alert is one record in the security alert log.
+type Alert struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Tool   string    `json:"tool"`
+	Canary string    `json:"canary"`
+}
+
+// Tripwire checks tool calls against a canary set. Every hit is written to
+// the alert log and sounded through alarm.
+type Tripwire struct {
+	set     *Set
+	logPath string
+	alarm   func(ctx context.Context, text string)
+	now     func() time.Time
+	logMu   sync.Mutex
+}
+
+// NewTripwire creates a tripwire for set that records alerts at logPath.
+// alarm, if non-nil, tells the user.
+func NewTripwire(set *Set, logPath string, alarm func(ctx context.Context, text string)) *Tripwire {
+	return &Tripwire{set: set, logPath: logPath, alarm: alarm, now: time.Now}
+}
+
+// CheckCall refuses a tool call whose arguments name a canary file or
+// carry a canary token. A nil Tripwire allows everything.
+func (t *Tripwire) CheckCall(ctx context.Context, toolName string, args map[string]any) error {
+	if t == nil {
+		return nil
+	}
+	for _, value := range argStrings(args) {
+		if c, ok := t.set.matchToken(value); ok {
+			t.trip(ctx, AlertExfiltration, toolName, c)
+			return fmt.Errorf("refused: the arguments contain a canary credential from %s, planted to detect data theft. The user has been alerted", c.Path)
+		}
+		if c, ok := t.set.matchPath(value); ok {
+			t.trip(ctx, AlertAccess, toolName, c)
+			return fmt.Errorf("refused: %s is a canary file planted to detect data theft. The user has been alerted", c.Path)
+		}
+	}
+	return nil
+}
+
+// CheckOutput returns output, or a notice in its place when it contains a
+// canary token.
+func (t *Tripwire) CheckOutput(ctx context.Context, toolName, output string) string {
+	if t == nil {
+		return output
+	}
+	c, ok := t.set.matchToken(output)
+	if !ok {
+		return output
+	}
+	t.trip(ctx, AlertRead, toolName, c)
+	return withheldOutput
+}
+
+func (t *Tripwire) trip(ctx context.Context, kind, toolName string, c Canary) {
+	alert := Alert{Time: t.now(), Kind: kind, Tool: toolName, Canary: c.Path}
+	logging.Logger().Error("canary tripped", "kind", kind, "tool", toolName, "canary", c.Path)
+	if err := t.record(alert); err != nil {
+		logging.Logger().Error("failed to record canary alert", "err", err)
+	}
+	if t.alarm != nil {
+		t.alarm(ctx, alertMessage(alert))
+	}
+}
+
+func (t *Tripwire) record(alert Alert) error {
+	if t.logPath == "" {
+		return nil
+	}
+	encoded, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("encode canary alert: %w", err)
+	}
+	t.logMu.Lock()
+	defer t.logMu.Unlock()
+	return store.AppendFile(t.logPath, append(encoded, '\n'))
+}
+
+func alertMessage(alert Alert) string {
+	what, outcome := fmt.Sprintf("%s tried to access %s", alert.Tool, alert.Canary), "The call was blocked."
+	switch alert.Kind {
+	case AlertExfiltration:
+		what = fmt.Sprintf("%s tried to send a fake credential from %s", alert.Tool, alert.Canary)
+	case AlertRead:
+		what, outcome = fmt.Sprintf("%s read a fake credential from %s", alert.Tool, alert.Canary), "Its output was withheld from the bot."
+	}
+	return "🚨 Canary tripped: " + what + ". This file is bait, so a prompt injection may be trying to steal credentials. " + outcome + " Check what the bot was reading before this."
+}
+
+// argStrings returns every string in args, including inside nested maps
+// and lists, in a stable order.
+func argStrings(args map[string]any) []string {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var out []string
+	for _, key := range keys {
+		out = appendStrings(out, args[key])
+	}
+	return out
+}
+
+func appendStrings(out []string, value any) []string {
+	switch v := value.(type) {
+	case string:
+		return append(out, v)
+	case []any:
+		for _, item := range v {
+			out = appendStrings(out, item)
+		}
+	case map[string]any:
+		out = append(out, argStrings(v)...)
+	}
+	return out
+}
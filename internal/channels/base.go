@@ -0,0 +1,79 @@
+package channels
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+)
+
+// Base holds the settings every chat listener shares. Listeners embed it,
+// so the Configure methods below are theirs.
+type Base struct {
+	// QueueSize is how many messages may wait while one is handled.
+	QueueSize int
+	// OutboundSecrets is the redact.Secrets* mode applied to outgoing replies.
+	OutboundSecrets string
+	// ApprovalTimeout expires unanswered prompts; zero waits indefinitely.
+	ApprovalTimeout time.Duration
+}
+
+// NewBase returns the settings a listener starts with.
+func NewBase() Base {
+	return Base{QueueSize: defaultDispatchQueue}
+}
+
+// ConfigureQueueSize sets how many messages may wait while one is handled.
+// Messages beyond that are held back until the queue has room.
+func (b *Base) ConfigureQueueSize(size int) {
+	b.QueueSize = size
+}
+
+// ConfigureOutboundFilter sets how replies containing credentials are
+// handled before they are sent: redact.SecretsRedact, redact.SecretsBlock,
+// or redact.SecretsOff.
+func (b *Base) ConfigureOutboundFilter(mode string) {
+	b.OutboundSecrets = mode
+}
+
+// ConfigureApprovalTimeout sets how long a prompt waits for an answer before
+// it expires and the action is refused. Zero waits indefinitely.
+func (b *Base) ConfigureApprovalTimeout(timeout time.Duration) {
+	b.ApprovalTimeout = timeout
+}
+
+// allowlist is the users of one channel in the shared allowed users file.
+// Observers are only supported on Telegram; elsewhere they get no access.
+type allowlist struct {
+	// allowedChannel is the channel name users are listed under.
+	allowedChannel   string
+	allowedUsersPath string
+	allowedUsers     map[string]struct{}
+}
+
+func newAllowlist(channel, path string) allowlist {
+	return allowlist{allowedChannel: channel, allowedUsersPath: path}
+}
+
+func (a *allowlist) loadAllowedUsers() error {
+	usersFile, err := approval.LoadUsers(a.allowedUsersPath)
+	if err != nil {
+		return fmt.Errorf("load allowed users %s: %w", a.allowedUsersPath, err)
+	}
+	allowed := make(map[string]struct{})
+	for _, user := range usersFile.Users {
+		id := strings.TrimSpace(user.ID)
+		if !strings.EqualFold(strings.TrimSpace(user.Channel), a.allowedChannel) || id == "" || user.IsObserver() {
+			continue
+		}
+		allowed[id] = struct{}{}
+	}
+	a.allowedUsers = allowed
+	return nil
+}
+
+func (a *allowlist) isAllowedUser(userID string) bool {
+	_, ok := a.allowedUsers[strings.TrimSpace(userID)]
+	return ok
+}
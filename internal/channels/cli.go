@@ -11,6 +11,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/chzyer/readline"
 	"github.com/neoclaw-ai/neoclaw/internal/approval"
@@ -42,6 +43,16 @@ func (w *CLIWriter) WriteMessage(_ context.Context, text string) error {
 	return nil
 }
 
+// SendFile points the user at a local file; the terminal shares its filesystem.
+func (w *CLIWriter) SendFile(_ context.Context, path, caption string) error {
+	if caption != "" {
+		fmt.Fprintf(w.out, "assistant> %s\n  %s\n\n", caption, path)
+		return nil
+	}
+	fmt.Fprintf(w.out, "assistant> %s\n\n", path)
+	return nil
+}
+
 // CLIListener listens for interactive terminal input and dispatches messages.
 type CLIListener struct {
 	in  io.Reader
@@ -49,6 +60,11 @@ type CLIListener struct {
 
 	rl       *readline.Instance
 	fallback *bufio.Reader
+
+	// plain reads lines without readline, so nothing is redrawn and no
+	// escape codes are written.
+	plain  bool
+	alerts CLIAlerts
 }
 
 // NewCLI creates a new CLI listener over stdin/stdout style streams.
@@ -56,6 +72,12 @@ func NewCLI(in io.Reader, out io.Writer) *CLIListener {
 	return &CLIListener{in: in, out: out}
 }
 
+// ConfigurePlain reads input as plain lines instead of through readline,
+// for dumb terminals, editor shells, and logs.
+func (c *CLIListener) ConfigurePlain(plain bool) {
+	c.plain = plain
+}
+
 // Listen runs the interactive loop until EOF, /quit, /exit, or fatal handler error.
 func (c *CLIListener) Listen(ctx context.Context, handler runtime.Handler) error {
 	if handler == nil {
@@ -102,6 +124,7 @@ func (c *CLIListener) Listen(ctx context.Context, handler runtime.Handler) error
 		reqCtx, cancelReq := context.WithCancel(ctx)
 		drainInterruptSignals(interruptCh)
 		interruptCanceled := watchRequestInterrupt(reqCtx, interruptCh, cancelReq)
+		started := time.Now()
 		err = handler.HandleMessage(reqCtx, writer, &runtime.Message{Text: input})
 		cancelReq()
 
@@ -111,6 +134,9 @@ func (c *CLIListener) Listen(ctx context.Context, handler runtime.Handler) error
 			canceledByInterrupt = true
 		default:
 		}
+		if !canceledByInterrupt {
+			c.alertTurnDone(time.Since(started))
+		}
 		if canceledByInterrupt && errors.Is(err, context.Canceled) {
 			if writeErr := writer.WriteMessage(ctx, "Canceled request"); writeErr != nil {
 				return writeErr
@@ -138,9 +164,15 @@ func (c *CLIListener) RequestApproval(ctx context.Context, req approval.Approval
 	}
 
 	prompt := approval.FormatApprovalPrompt(req)
+	c.alert("Approval needed")
 	return c.requestApprovalDirect(prompt)
 }
 
+// ApproverName names the CLI user who answers approval prompts.
+func (c *CLIListener) ApproverName() string {
+	return "cli"
+}
+
 func (c *CLIListener) requestApprovalDirect(prompt string) (approval.ApprovalDecision, error) {
 	var answer string
 	if c.rl != nil {
@@ -162,12 +194,9 @@ func (c *CLIListener) requestApprovalDirect(prompt string) (approval.ApprovalDec
 }
 
 func parseApprovalAnswer(answer string) approval.ApprovalDecision {
-	switch strings.ToLower(strings.TrimSpace(answer)) {
-	case "y", "yes":
-		return approval.Approved
-	default:
-		return approval.Denied
-	}
+	// Anything that is not a yes denies, as the [y/N] prompt says.
+	decision, _ := approval.ReplyDecision(answer)
+	return decision
 }
 
 func (c *CLIListener) ensureInputReady() error {
@@ -175,10 +204,11 @@ func (c *CLIListener) ensureInputReady() error {
 		return nil
 	}
 
-	rl, err := newReadline(c.in, c.out)
-	if err == nil {
-		c.rl = rl
-		return nil
+	if !c.plain {
+		if rl, err := newReadline(c.in, c.out); err == nil {
+			c.rl = rl
+			return nil
+		}
 	}
 
 	c.fallback = bufio.NewReader(c.in)
@@ -0,0 +1,79 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+const desktopNotifyTimeout = 5 * time.Second
+
+// CLIAlerts tells the user at the terminal that a turn finished or needs
+// an answer, for when they have switched to another window.
+type CLIAlerts struct {
+	// Bell writes the terminal bell character.
+	Bell bool
+	// Desktop shows a desktop notification as well.
+	Desktop bool
+	// After skips the finished-turn alert for shorter turns.
+	After time.Duration
+}
+
+// desktopNotify is replaced in tests.
+var desktopNotify = sendDesktopNotification
+
+// ConfigureAlerts enables alerts. Only set them when output is a terminal.
+func (c *CLIListener) ConfigureAlerts(alerts CLIAlerts) {
+	c.alerts = alerts
+}
+
+// alertTurnDone alerts once a turn that took at least alerts.After ends.
+func (c *CLIListener) alertTurnDone(elapsed time.Duration) {
+	if elapsed < c.alerts.After {
+		return
+	}
+	c.alert("Turn finished")
+}
+
+// alert never includes message text: desktop notifications are shown on
+// the lock screen and kept in notification history.
+func (c *CLIListener) alert(body string) {
+	if c.alerts.Bell {
+		fmt.Fprint(c.out, "\a")
+	}
+	if c.alerts.Desktop {
+		go func() {
+			if err := desktopNotify("NeoClaw", body); err != nil {
+				logging.Logger().Debug("desktop notification failed", "err", err)
+			}
+		}()
+	}
+}
+
+// sendDesktopNotification passes title and body as arguments, never as
+// script text, so neither can inject commands.
+func sendDesktopNotification(title, body string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), desktopNotifyTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.CommandContext(ctx, "notify-send", "--", title, body)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Path, err, output)
+	}
+	return nil
+}
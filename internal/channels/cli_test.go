@@ -6,6 +6,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/neoclaw-ai/neoclaw/internal/approval"
 	"github.com/neoclaw-ai/neoclaw/internal/runtime"
@@ -111,3 +112,46 @@ func (h *testHandler) HandleMessage(ctx context.Context, w runtime.ResponseWrite
 	}
 	return w.WriteMessage(ctx, h.response)
 }
+
+func TestCLIListenerAlertsOnLongTurnsAndApprovals(t *testing.T) {
+	notified := make(chan string, 2)
+	orig := desktopNotify
+	desktopNotify = func(_, body string) error {
+		notified <- body
+		return nil
+	}
+	defer func() { desktopNotify = orig }()
+
+	out := &bytes.Buffer{}
+	listener := NewCLI(strings.NewReader("hello\n"), out)
+	listener.ConfigureAlerts(CLIAlerts{Bell: true, Desktop: true, After: time.Hour})
+	if err := listener.Listen(context.Background(), &testHandler{response: "ok"}); err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	if strings.Contains(out.String(), "\a") {
+		t.Fatalf("expected no bell for a short turn, got %q", out.String())
+	}
+
+	out.Reset()
+	listener = NewCLI(strings.NewReader("y\n"), out)
+	listener.ConfigureAlerts(CLIAlerts{Bell: true, Desktop: true})
+	listener.alertTurnDone(time.Second)
+	if _, err := listener.RequestApproval(context.Background(), approval.ApprovalRequest{Tool: "run_command"}); err != nil {
+		t.Fatalf("request approval: %v", err)
+	}
+	if got := strings.Count(out.String(), "\a"); got != 2 {
+		t.Fatalf("expected two bells, got %d in %q", got, out.String())
+	}
+	got := map[string]bool{}
+	for range 2 {
+		select {
+		case body := <-notified:
+			got[body] = true
+		case <-time.After(time.Second):
+			t.Fatalf("expected two desktop notifications, got %v", got)
+		}
+	}
+	if !got["Turn finished"] || !got["Approval needed"] {
+		t.Fatalf("unexpected desktop notifications %v", got)
+	}
+}
@@ -0,0 +1,624 @@
+// Package httpapi serves the agent over HTTP for scripts and custom UIs:
+// messages are posted as JSON and replies are streamed back as server-sent
+// events.
+package httpapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/channels"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/jsonschema"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/redact"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/workflow"
+)
+
+const (
+	// Channel names the HTTP API in scheduler channel keys.
+	Channel = "http"
+	// DefaultListen keeps the API on the local machine unless configured
+	// otherwise.
+	DefaultListen = "127.0.0.1:8787"
+
+	// maxRequestBody bounds one posted message or approval answer.
+	maxRequestBody = 1 << 20
+	// keepAliveInterval sends an SSE comment so proxies keep idle streams open.
+	keepAliveInterval = 15 * time.Second
+	// streamBuffer is how many events a slow stream may fall behind by before
+	// events to it are dropped.
+	streamBuffer = 64
+)
+
+var _ runtime.Listener = (*Listener)(nil)
+var _ approval.Approver = (*Listener)(nil)
+
+// Event is one server-sent event. MessageID ties replies, approval prompts,
+// and the final done event to the posted message they answer; scheduled
+// messages have none.
+type Event struct {
+	Type       string `json:"-"`
+	MessageID  string `json:"message_id,omitempty"`
+	Text       string `json:"text,omitempty"`
+	ApprovalID string `json:"approval_id,omitempty"`
+	Tool       string `json:"tool,omitempty"`
+}
+
+// Listener serves POST /v1/messages, GET /v1/stream,
+// POST /v1/approvals/{id}, and POST /v1/workflows/{name}/run. Every request
+// needs one of the configured API keys as a bearer token; each key is its
+// own client with its own streams.
+type Listener struct {
+	channels.Base
+
+	listen string
+	// clients maps each API key to the client ID derived from it.
+	clients map[string]string
+	// workflowsDir holds the workflows POST /v1/workflows/{name}/run may
+	// start; empty disables the endpoint.
+	workflowsDir string
+
+	mu      sync.Mutex
+	streams map[*stream]struct{}
+	active  *request
+	pending map[string]pendingApproval
+}
+
+// request is the posted message being handled.
+type request struct {
+	client    string
+	messageID string
+}
+
+type pendingApproval struct {
+	client   string
+	response chan approval.ApprovalDecision
+}
+
+// stream is one open event stream. A stream opened by POST /v1/messages
+// only receives events for its message and ends after done.
+type stream struct {
+	client    string
+	messageID string
+	events    chan Event
+}
+
+// New creates an HTTP API listener on listen (DefaultListen if empty) that
+// accepts the given API keys.
+func New(listen string, apiKeys []string) *Listener {
+	if strings.TrimSpace(listen) == "" {
+		listen = DefaultListen
+	}
+	clients := make(map[string]string, len(apiKeys))
+	for _, key := range apiKeys {
+		if key = strings.TrimSpace(key); key != "" {
+			clients[key] = ClientID(key)
+		}
+	}
+	return &Listener{
+		Base:    channels.NewBase(),
+		listen:  listen,
+		clients: clients,
+		streams: make(map[*stream]struct{}),
+		pending: make(map[string]pendingApproval),
+	}
+}
+
+// ClientID names the client using apiKey without revealing it: "key-"
+// and the start of the key's SHA-256.
+func ClientID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key-" + hex.EncodeToString(sum[:4])
+}
+
+// ConfigureWorkflows lets clients start the workflows in dir with
+// POST /v1/workflows/{name}/run, for webhooks and other automations.
+func (l *Listener) ConfigureWorkflows(dir string) {
+	l.workflowsDir = dir
+}
+
+// ChannelKey returns the scheduler channel key for one client.
+func (l *Listener) ChannelKey(clientID string) string {
+	return Channel + "-" + clientID
+}
+
+// ClientIDs returns the IDs of the configured API keys.
+func (l *Listener) ClientIDs() []string {
+	ids := make([]string, 0, len(l.clients))
+	for _, id := range l.clients {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Listen serves the API until ctx is cancelled.
+func (l *Listener) Listen(ctx context.Context, handler runtime.Handler) error {
+	if handler == nil {
+		return errors.New("handler is required")
+	}
+	if len(l.clients) == 0 {
+		return errors.New("http api_keys are required")
+	}
+	ln, err := net.Listen("tcp", l.listen)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", l.listen, err)
+	}
+
+	dispatchCtx, cancelDispatch := context.WithCancel(ctx)
+	defer cancelDispatch()
+	dispatcher := runtime.NewDispatcher(&requestHandler{listener: l, handler: handler}, l.QueueSize)
+	if err := dispatcher.Start(dispatchCtx); err != nil {
+		ln.Close()
+		return err
+	}
+	defer dispatcher.Wait()
+	defer dispatcher.Stop()
+
+	server := &http.Server{
+		Handler:           l.routes(dispatchCtx, dispatcher),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	logging.Logger().Info(fmt.Sprintf("HTTP API listening on http://%s", ln.Addr()))
+	if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve http api: %w", err)
+	}
+	return ctx.Err()
+}
+
+func (l *Listener) routes(ctx context.Context, dispatcher *runtime.Dispatcher) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/messages", l.authorized(func(w http.ResponseWriter, r *http.Request, client string) {
+		l.handlePostMessage(ctx, dispatcher, w, r, client)
+	}))
+	mux.HandleFunc("GET /v1/stream", l.authorized(l.handleStream))
+	mux.HandleFunc("POST /v1/approvals/{id}", l.authorized(l.handleApproval))
+	mux.HandleFunc("POST /v1/workflows/{name}/run", l.authorized(func(w http.ResponseWriter, r *http.Request, client string) {
+		l.handleRunWorkflow(ctx, dispatcher, w, r, client)
+	}))
+	return mux
+}
+
+// authorized checks the bearer token against the API keys in constant time.
+func (l *Listener) authorized(next func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		client := ""
+		if ok {
+			for key, id := range l.clients {
+				if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(key)) == 1 {
+					client = id
+				}
+			}
+		}
+		if client == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="neoclaw"`)
+			writeError(w, http.StatusUnauthorized, "a valid API key is required")
+			return
+		}
+		next(w, r, client)
+	}
+}
+
+func (l *Listener) handlePostMessage(ctx context.Context, dispatcher *runtime.Dispatcher, w http.ResponseWriter, r *http.Request, client string) {
+	var body struct {
+		Text           string          `json:"text"`
+		ResponseFormat string          `json:"response_format"`
+		ResponseSchema json.RawMessage `json:"response_schema"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBody)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "body must be JSON like {\"text\": \"...\"}")
+		return
+	}
+	text := strings.TrimSpace(body.Text)
+	if text == "" {
+		writeError(w, http.StatusBadRequest, "text is required")
+		return
+	}
+	msg := &runtime.Message{Text: text, UserID: client}
+	switch format := strings.ToLower(strings.TrimSpace(body.ResponseFormat)); format {
+	case "", config.ResponseFormatText, config.ResponseFormatJSON:
+		msg.ResponseFormat = format
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid response_format %s (allowed: %s, %s)", body.ResponseFormat, config.ResponseFormatText, config.ResponseFormatJSON))
+		return
+	}
+	if len(body.ResponseSchema) > 0 && string(body.ResponseSchema) != "null" {
+		if msg.ResponseFormat == config.ResponseFormatText {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("response_schema requires response_format %q", config.ResponseFormatJSON))
+			return
+		}
+		schema, err := jsonschema.Parse(body.ResponseSchema)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "response_schema: "+err.Error())
+			return
+		}
+		msg.ResponseSchema = schema
+	}
+	messageID, err := newID("msg_")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not create a message id")
+		return
+	}
+	logging.Logger().Info("http inbound message", "client", client, "message_id", messageID, "text", preview(text, 100), "response_format", msg.ResponseFormat)
+	l.enqueue(ctx, dispatcher, w, r, messageID, msg)
+}
+
+// handleRunWorkflow starts a workflow as if the client had sent
+// /run <name>, so it queues behind other messages and its approval steps
+// go to the client's streams. The body may be empty or {"resume": true}.
+func (l *Listener) handleRunWorkflow(ctx context.Context, dispatcher *runtime.Dispatcher, w http.ResponseWriter, r *http.Request, client string) {
+	var body struct {
+		Resume bool `json:"resume"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBody)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "body must be empty or JSON like {\"resume\": true}")
+		return
+	}
+	name := r.PathValue("name")
+	if l.workflowsDir == "" {
+		writeError(w, http.StatusNotFound, "workflows are unavailable")
+		return
+	}
+	if _, err := workflow.Load(l.workflowsDir, name); err != nil {
+		if errors.Is(err, fs.ErrNotExist) || !workflow.ValidName(name) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("no workflow named %q", name))
+			return
+		}
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	messageID, err := newID("msg_")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not create a message id")
+		return
+	}
+	text := "/run " + name
+	if body.Resume {
+		text += " resume"
+	}
+	logging.Logger().Info("http workflow trigger", "client", client, "message_id", messageID, "workflow", name, "resume", body.Resume)
+	l.enqueue(ctx, dispatcher, w, r, messageID, &runtime.Message{Text: text, UserID: client})
+}
+
+// enqueue queues msg, whose UserID is the client, as messageID. It answers
+// with the message ID, or streams the replies when the client accepts
+// text/event-stream.
+func (l *Listener) enqueue(ctx context.Context, dispatcher *runtime.Dispatcher, w http.ResponseWriter, r *http.Request, messageID string, msg *runtime.Message) {
+	client := msg.UserID
+	// Subscribe before enqueueing so no reply can be missed.
+	var replies *stream
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		replies = l.subscribe(client, messageID)
+		defer l.unsubscribe(replies)
+	}
+	writer := &responseWriter{listener: l, client: client, messageID: messageID}
+	if err := dispatcher.Enqueue(r.Context(), msg, writer); err != nil {
+		writeError(w, http.StatusServiceUnavailable, "the agent is not accepting messages")
+		return
+	}
+	if replies == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(map[string]string{"message_id": messageID})
+		return
+	}
+	l.serveEvents(ctx, w, r, replies)
+}
+
+func (l *Listener) handleStream(w http.ResponseWriter, r *http.Request, client string) {
+	events := l.subscribe(client, "")
+	defer l.unsubscribe(events)
+	l.serveEvents(r.Context(), w, r, events)
+}
+
+func (l *Listener) handleApproval(w http.ResponseWriter, r *http.Request, client string) {
+	var body struct {
+		Approve *bool `json:"approve"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBody)).Decode(&body); err != nil || body.Approve == nil {
+		writeError(w, http.StatusBadRequest, "body must be JSON like {\"approve\": true}")
+		return
+	}
+	id := r.PathValue("id")
+	l.mu.Lock()
+	pending, ok := l.pending[id]
+	// Only the client that was asked can answer.
+	if ok && pending.client == client {
+		delete(l.pending, id)
+	} else {
+		ok = false
+	}
+	l.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "no such approval is waiting")
+		return
+	}
+	decision := approval.Denied
+	if *body.Approve {
+		decision = approval.Approved
+	}
+	pending.response <- decision
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveEvents writes events as text/event-stream until the client leaves,
+// ctx ends, or a message stream receives done.
+func (l *Listener) serveEvents(ctx context.Context, w http.ResponseWriter, r *http.Request, s *stream) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is unsupported")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event := <-s.events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+			if event.Type == "done" && s.messageID != "" {
+				return
+			}
+		}
+	}
+}
+
+func (l *Listener) subscribe(client, messageID string) *stream {
+	s := &stream{client: client, messageID: messageID, events: make(chan Event, streamBuffer)}
+	l.mu.Lock()
+	l.streams[s] = struct{}{}
+	l.mu.Unlock()
+	return s
+}
+
+func (l *Listener) unsubscribe(s *stream) {
+	l.mu.Lock()
+	delete(l.streams, s)
+	l.mu.Unlock()
+}
+
+// publish sends event to the client's streams and reports how many got it.
+func (l *Listener) publish(client string, event Event) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delivered := 0
+	for s := range l.streams {
+		if s.client != client || (s.messageID != "" && s.messageID != event.MessageID) {
+			continue
+		}
+		select {
+		case s.events <- event:
+			delivered++
+		default:
+			logging.Logger().Warn("http event stream is full; dropping event", "client", client, "event", event.Type)
+		}
+	}
+	return delivered
+}
+
+// publishText streams a reply after applying the outbound secrets filter.
+func (l *Listener) publishText(client, messageID, text string) int {
+	text, found := redact.FilterSecrets(text, l.OutboundSecrets)
+	if len(found) > 0 {
+		logging.Logger().Warn("outbound http message contained credentials", "client", client, "kinds", strings.Join(found, ", "), "mode", l.OutboundSecrets)
+	}
+	return l.publish(client, Event{Type: "message", MessageID: messageID, Text: text})
+}
+
+// RequestApproval streams an approval event and waits for the client to
+// answer it with POST /v1/approvals/{id}.
+func (l *Listener) RequestApproval(ctx context.Context, req approval.ApprovalRequest) (approval.ApprovalDecision, error) {
+	if ctx.Err() != nil {
+		return approval.Denied, nil
+	}
+	active, ok := l.activeRequest()
+	if !ok {
+		return approval.Denied, errors.New("http approval target is unavailable")
+	}
+	id, err := newID("apr_")
+	if err != nil {
+		return approval.Denied, fmt.Errorf("generate approval id: %w", err)
+	}
+	prompt := strings.TrimSpace(req.Description)
+	if prompt == "" {
+		prompt = fmt.Sprintf("Approve %s?", strings.TrimSpace(req.Tool))
+	}
+
+	pending := pendingApproval{client: active.client, response: make(chan approval.ApprovalDecision, 1)}
+	l.mu.Lock()
+	l.pending[id] = pending
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.pending, id)
+		l.mu.Unlock()
+	}()
+	event := Event{Type: "approval", MessageID: active.messageID, ApprovalID: id, Tool: req.Tool, Text: prompt}
+	if l.publish(active.client, event) == 0 {
+		return approval.Denied, fmt.Errorf("approval for %s needs an open event stream to answer it", req.Tool)
+	}
+
+	var expired <-chan time.Time
+	if l.ApprovalTimeout > 0 {
+		timer := time.NewTimer(l.ApprovalTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case decision := <-pending.response:
+		return decision, nil
+	case <-expired:
+		return approval.Denied, fmt.Errorf("approval for %s timed out: the user did not answer within %s", req.Tool, l.ApprovalTimeout)
+	case <-ctx.Done():
+		return approval.Denied, nil
+	}
+}
+
+// ApproverName names the client that answers approval prompts.
+func (l *Listener) ApproverName() string {
+	active, ok := l.activeRequest()
+	if !ok {
+		return "http api"
+	}
+	return "http client " + active.client
+}
+
+// Send delivers a channel message to the streams of the current request.
+func (l *Listener) Send(ctx context.Context, message string) error {
+	active, ok := l.activeRequest()
+	if !ok {
+		return errors.New("http request is unavailable")
+	}
+	l.publishText(active.client, active.messageID, message)
+	return nil
+}
+
+// CurrentChannelID returns the scheduler channel key of the client whose
+// request is being handled.
+func (l *Listener) CurrentChannelID() string {
+	active, ok := l.activeRequest()
+	if !ok {
+		return ""
+	}
+	return l.ChannelKey(active.client)
+}
+
+// ChannelWriter returns an io.Writer that delivers scheduler messages to
+// the client's open GET /v1/stream connections.
+func (l *Listener) ChannelWriter(clientID string) io.Writer {
+	return channelWriter{listener: l, client: clientID}
+}
+
+type channelWriter struct {
+	listener *Listener
+	client   string
+}
+
+func (w channelWriter) Write(p []byte) (int, error) {
+	text := strings.TrimSpace(string(p))
+	if text == "" {
+		return len(p), nil
+	}
+	if w.listener.publishText(w.client, "", text) == 0 {
+		return 0, fmt.Errorf("http client %s has no open event stream", w.client)
+	}
+	return len(p), nil
+}
+
+func (l *Listener) activeRequest() (request, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active == nil {
+		return request{}, false
+	}
+	return *l.active, true
+}
+
+func (l *Listener) setActive(r *request) {
+	l.mu.Lock()
+	l.active = r
+	l.mu.Unlock()
+}
+
+// responseWriter streams replies to one posted message.
+type responseWriter struct {
+	listener  *Listener
+	client    string
+	messageID string
+}
+
+func (w *responseWriter) WriteMessage(_ context.Context, text string) error {
+	w.listener.publishText(w.client, w.messageID, text)
+	return nil
+}
+
+// requestHandler tracks the request being handled for approvals and Send,
+// and ends its streams with done.
+type requestHandler struct {
+	listener *Listener
+	handler  runtime.Handler
+}
+
+func (h *requestHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	writer, ok := w.(*responseWriter)
+	if !ok {
+		return h.handler.HandleMessage(ctx, w, msg)
+	}
+	h.listener.setActive(&request{client: writer.client, messageID: writer.messageID})
+	defer h.listener.setActive(nil)
+	err := h.handler.HandleMessage(ctx, w, msg)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		// The dispatcher reports errors after the handler returns, which
+		// would be after done; report it here instead.
+		logging.Logger().Error("message handling failed", "err", err)
+		h.listener.publish(writer.client, Event{Type: "error", MessageID: writer.messageID, Text: "There was an error with your request. Check server logs for details"})
+		err = nil
+	}
+	h.listener.publish(writer.client, Event{Type: "done", MessageID: writer.messageID})
+	return err
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+func newID(prefix string) (string, error) {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(raw), nil
+}
+
+func preview(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,306 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/agent"
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+const (
+	aliceKey = "alice-0123456789abcdef0123456789"
+	bobKey   = "bob-0123456789abcdef0123456789ab"
+)
+
+// approvingHandler asks for approval and replies with the decision.
+type approvingHandler struct {
+	approver approval.Approver
+}
+
+func (h approvingHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	decision, err := h.approver.RequestApproval(ctx, approval.ApprovalRequest{Tool: "run_command", Description: "Run: make test"})
+	if err != nil {
+		return err
+	}
+	if decision == approval.Approved {
+		return w.WriteMessage(ctx, "tests passed for "+msg.UserID)
+	}
+	return w.WriteMessage(ctx, "skipped")
+}
+
+// echoHandler replies with the text it was sent.
+type echoHandler struct{}
+
+func (echoHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	return w.WriteMessage(ctx, "got "+msg.Text)
+}
+
+func startServer(t *testing.T) (*Listener, *httptest.Server) {
+	t.Helper()
+	listener := New("", []string{aliceKey, bobKey})
+	return listener, serve(t, listener, approvingHandler{approver: listener})
+}
+
+func serve(t *testing.T, listener *Listener, handler runtime.Handler) *httptest.Server {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher := runtime.NewDispatcher(&requestHandler{listener: listener, handler: handler}, 4)
+	if err := dispatcher.Start(ctx); err != nil {
+		t.Fatalf("start dispatcher: %v", err)
+	}
+	server := httptest.NewServer(listener.routes(ctx, dispatcher))
+	t.Cleanup(func() {
+		cancel()
+		server.Close()
+		dispatcher.Wait()
+	})
+	return server
+}
+
+func post(t *testing.T, url, key, body string, header ...string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post %s: %v", url, err)
+	}
+	return resp
+}
+
+// readEvent returns the next event type and data from an SSE stream.
+func readEvent(t *testing.T, events *bufio.Reader) (string, Event) {
+	t.Helper()
+	var eventType string
+	var event Event
+	for {
+		line, err := events.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				t.Fatalf("decode event: %v", err)
+			}
+		case line == "" && eventType != "":
+			return eventType, event
+		}
+	}
+}
+
+func TestPostMessageStreamsApprovalReplyAndDone(t *testing.T) {
+	_, server := startServer(t)
+
+	if resp := post(t, server.URL+"/v1/messages", "wrong", `{"text":"hi"}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a valid key, got %d", resp.StatusCode)
+	}
+
+	resp := post(t, server.URL+"/v1/messages", aliceKey, `{"text":"run the tests"}`, "Accept", "text/event-stream")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	events := bufio.NewReader(resp.Body)
+
+	eventType, prompt := readEvent(t, events)
+	if eventType != "approval" || prompt.Text != "Run: make test" || prompt.Tool != "run_command" || prompt.ApprovalID == "" {
+		t.Fatalf("expected an approval prompt, got %s %#v", eventType, prompt)
+	}
+
+	// Only the client that was asked can answer.
+	answer := server.URL + "/v1/approvals/" + prompt.ApprovalID
+	if resp := post(t, answer, bobKey, `{"approve":true}`); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected another client's answer to be refused, got %d", resp.StatusCode)
+	}
+	if resp := post(t, answer, aliceKey, `{"approve":true}`); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected the answer to be accepted, got %d", resp.StatusCode)
+	}
+
+	eventType, reply := readEvent(t, events)
+	if eventType != "message" || reply.Text != "tests passed for "+ClientID(aliceKey) || reply.MessageID != prompt.MessageID {
+		t.Fatalf("unexpected reply %s %#v", eventType, reply)
+	}
+	if eventType, done := readEvent(t, events); eventType != "done" || done.MessageID != prompt.MessageID {
+		t.Fatalf("expected done, got %s %#v", eventType, done)
+	}
+}
+
+func TestStreamReceivesScheduledMessages(t *testing.T) {
+	listener, server := startServer(t)
+	writer := listener.ChannelWriter(ClientID(aliceKey))
+	if _, err := writer.Write([]byte("Daily briefing")); err == nil {
+		t.Fatal("expected an error without an open stream")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/stream", nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+aliceKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+
+	// The stream is registered once its headers are sent.
+	for deadline := time.Now().Add(2 * time.Second); ; {
+		if _, err := writer.Write([]byte("Daily briefing\n")); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stream was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if eventType, event := readEvent(t, events); eventType != "message" || event.Text != "Daily briefing" || event.MessageID != "" {
+		t.Fatalf("unexpected scheduled event %s %#v", eventType, event)
+	}
+
+	// Bob's messages never reach Alice's stream.
+	if _, err := listener.ChannelWriter(ClientID(bobKey)).Write([]byte("hi")); err == nil {
+		t.Fatal("expected an error without an open stream for bob")
+	}
+}
+
+func TestRunWorkflowQueuesTheRunCommand(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "deploy.yaml"), []byte("steps:\n  - type: prompt\n    prompt: ship it\n"), 0o644); err != nil {
+		t.Fatalf("write workflow: %v", err)
+	}
+	listener := New("", []string{aliceKey})
+	server := serve(t, listener, echoHandler{})
+
+	// Without ConfigureWorkflows the endpoint is off.
+	if resp := post(t, server.URL+"/v1/workflows/deploy/run", aliceKey, ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 before workflows are configured, got %d", resp.StatusCode)
+	}
+	listener.ConfigureWorkflows(dir)
+
+	if resp := post(t, server.URL+"/v1/workflows/deploy/run", "wrong", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a valid key, got %d", resp.StatusCode)
+	}
+	for _, name := range []string{"missing", "Deploy"} {
+		if resp := post(t, server.URL+"/v1/workflows/"+name+"/run", aliceKey, ""); resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected 404 for workflow %q, got %d", name, resp.StatusCode)
+		}
+	}
+
+	// A webhook without a body starts the workflow and gets the message ID.
+	resp := post(t, server.URL+"/v1/workflows/deploy/run", aliceKey, "")
+	var accepted struct {
+		MessageID string `json:"message_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&accepted); err != nil || resp.StatusCode != http.StatusAccepted || accepted.MessageID == "" {
+		t.Fatalf("expected 202 with a message id, got %d %#v (%v)", resp.StatusCode, accepted, err)
+	}
+	resp.Body.Close()
+
+	resp = post(t, server.URL+"/v1/workflows/deploy/run", aliceKey, `{"resume":true}`, "Accept", "text/event-stream")
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+	if eventType, reply := readEvent(t, events); eventType != "message" || reply.Text != "got /run deploy resume" {
+		t.Fatalf("unexpected reply %s %#v", eventType, reply)
+	}
+	if eventType, _ := readEvent(t, events); eventType != "done" {
+		t.Fatalf("expected done, got %s", eventType)
+	}
+}
+
+// fixedProvider answers every chat with the same reply and keeps the system
+// prompts it was sent.
+type fixedProvider struct {
+	reply string
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+func (p *fixedProvider) Chat(_ context.Context, req provider.ChatRequest) (*provider.ChatResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prompts = append(p.prompts, req.SystemPrompt)
+	return &provider.ChatResponse{Content: p.reply}, nil
+}
+
+func TestPostMessageAppliesTheRequestedResponseSchema(t *testing.T) {
+	agentDir := t.TempDir()
+	for _, name := range []string{config.SoulFilePath, config.UserFilePath} {
+		if err := os.WriteFile(filepath.Join(agentDir, name), nil, 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	memoryStore, err := memory.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("new memory store: %v", err)
+	}
+	model := &fixedProvider{reply: `{"answer": 42}`}
+	listener := New("", []string{aliceKey})
+	server := serve(t, listener, agent.New(model, tools.NewRegistry(), listener, agentDir, memoryStore, config.ContextConfig{}))
+
+	for _, body := range []string{
+		`{"text":"hi","response_format":"xml"}`,
+		`{"text":"hi","response_format":"text","response_schema":{"type":"object"}}`,
+		`{"text":"hi","response_schema":[1]}`,
+	} {
+		if resp := post(t, server.URL+"/v1/messages", aliceKey, body); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, resp.StatusCode)
+		}
+	}
+
+	// The model keeps answering with a number where the schema wants a
+	// string, so after one correction the client gets the error object.
+	resp := post(t, server.URL+"/v1/messages", aliceKey,
+		`{"text":"What is the answer?","response_schema":{"type":"object","required":["answer"],"properties":{"answer":{"type":"string"}}}}`,
+		"Accept", "text/event-stream")
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+	eventType, reply := readEvent(t, events)
+	if eventType != "message" || !strings.HasPrefix(reply.Text, `{"error":"response did not match the required format: schema mismatch: $.answer: expected string`) {
+		t.Fatalf("expected the schema error object, got %s %#v", eventType, reply)
+	}
+	if eventType, _ := readEvent(t, events); eventType != "done" {
+		t.Fatalf("expected done, got %s", eventType)
+	}
+
+	// The schema applied to that message only.
+	resp = post(t, server.URL+"/v1/messages", aliceKey, `{"text":"And now?"}`, "Accept", "text/event-stream")
+	defer resp.Body.Close()
+	if eventType, reply := readEvent(t, bufio.NewReader(resp.Body)); eventType != "message" || reply.Text != `{"answer": 42}` {
+		t.Fatalf("expected the plain reply, got %s %#v", eventType, reply)
+	}
+	model.mu.Lock()
+	defer model.mu.Unlock()
+	if len(model.prompts) != 3 {
+		t.Fatalf("expected a correction call and one plain call, got %d calls", len(model.prompts))
+	}
+	if !strings.Contains(model.prompts[0], `"required":["answer"]`) || strings.Contains(model.prompts[2], "Response format:") {
+		t.Fatalf("expected the schema in the first prompt only, got %q and %q", model.prompts[0], model.prompts[2])
+	}
+}
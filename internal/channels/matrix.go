@@ -0,0 +1,631 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/redact"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+)
+
+const (
+	// MatrixChannel is the allowlist channel of Matrix users.
+	MatrixChannel = "matrix"
+
+	matrixApproveKey = "✅"
+	matrixDenyKey    = "❌"
+)
+
+var _ runtime.Listener = (*MatrixListener)(nil)
+var _ approval.Approver = (*MatrixListener)(nil)
+
+// MatrixListener receives Matrix messages by long polling /sync. It joins
+// rooms allowlisted users invite it to, answers every message in a direct
+// chat and mentions in larger rooms, and asks for approvals with ✅/❌
+// reactions or a "yes <code>" reply.
+//
+// End-to-end encryption is handled by an E2EE-aware proxy such as
+// pantalaimon between NeoClaw and the homeserver. Encrypted events that
+// reach the listener undecrypted are never answered, so nothing is sent in
+// plain text to an encrypted room.
+type MatrixListener struct {
+	Base
+	allowlist
+
+	api matrixAPI
+
+	// userID is the bot account, whose own events are ignored.
+	userID string
+
+	roomsMu sync.Mutex
+	// members holds the joined members of each room the bot is in.
+	members map[string]map[string]struct{}
+	// lastRoom is the direct chat each user last wrote from, where
+	// scheduled messages for them are delivered.
+	lastRoom map[string]string
+	// warnedEncrypted records rooms already reported as undecryptable.
+	warnedEncrypted map[string]struct{}
+
+	approvalMu       sync.Mutex
+	activeTarget     *matrixTarget
+	pendingApprovals map[string]matrixPendingApproval
+	// replies lets users without reactions answer by "yes <code>".
+	replies *matrixReplyCodes
+}
+
+// matrixTarget is where the message being handled came from.
+type matrixTarget struct {
+	userID string
+	roomID string
+	// threadRoot is the thread replies go to; empty replies in the room.
+	threadRoot string
+}
+
+type matrixPendingApproval struct {
+	tool     string
+	prompt   string
+	userID   string
+	roomID   string
+	code     string
+	response chan approval.ApprovalDecision
+}
+
+// matrixInbound is a room message waiting to be dispatched.
+type matrixInbound struct {
+	roomID string
+	event  matrixEvent
+}
+
+// NewMatrix creates a Matrix listener for the account that token belongs
+// to on homeserver.
+func NewMatrix(homeserver, token, allowedUsersPath string) *MatrixListener {
+	return &MatrixListener{
+		Base:             NewBase(),
+		allowlist:        newAllowlist(MatrixChannel, allowedUsersPath),
+		api:              matrixAPI{homeserver: homeserver, token: token},
+		members:          make(map[string]map[string]struct{}),
+		lastRoom:         make(map[string]string),
+		warnedEncrypted:  make(map[string]struct{}),
+		pendingApprovals: make(map[string]matrixPendingApproval),
+		replies:          newMatrixReplyCodes(),
+	}
+}
+
+// ChannelKey returns the scheduler channel key for one Matrix user.
+// Messages to it are sent in the direct chat the user last wrote from.
+func (m *MatrixListener) ChannelKey(userID string) string {
+	return MatrixChannel + "-" + userID
+}
+
+// Listen syncs with the homeserver and dispatches authorized messages.
+// Messages sent while NeoClaw was stopped are skipped.
+func (m *MatrixListener) Listen(ctx context.Context, handler runtime.Handler) error {
+	if handler == nil {
+		return errors.New("handler is required")
+	}
+	if strings.TrimSpace(m.api.homeserver) == "" || strings.TrimSpace(m.api.token) == "" {
+		return errors.New("matrix homeserver and token are required")
+	}
+	if err := m.loadAllowedUsers(); err != nil {
+		return err
+	}
+	if len(m.allowedUsers) == 0 {
+		logging.Logger().Warn("No authorized Matrix users. Run claw pair --bot matrix to authorize your account.")
+	}
+
+	userID, err := m.api.whoami(ctx)
+	if err != nil {
+		return fmt.Errorf("check matrix access token: %w", err)
+	}
+	m.userID = userID
+	logging.Logger().Info(fmt.Sprintf("Connected to Matrix as %s", userID))
+
+	dispatchCtx, cancelDispatch := context.WithCancel(ctx)
+	defer cancelDispatch()
+	dispatcher := runtime.NewDispatcher(&matrixApprovalHandler{listener: m, handler: handler}, m.QueueSize)
+	if err := dispatcher.Start(dispatchCtx); err != nil {
+		return err
+	}
+	defer dispatcher.Wait()
+	defer dispatcher.Stop()
+
+	// Messages are queued apart from the sync loop so a full dispatch queue
+	// never holds up reactions, which the running turn may wait on.
+	inbound := make(chan matrixInbound, m.QueueSize)
+	go func() {
+		for message := range inbound {
+			m.handleInboundMessage(dispatchCtx, dispatcher, message)
+		}
+	}()
+	defer close(inbound)
+
+	return runMatrixSync(ctx, m.api, func(ctx context.Context, batch matrixSync, initial bool) {
+		m.handleSync(ctx, batch, initial, inbound)
+	})
+}
+
+// runMatrixSync long polls /sync until ctx is done. The first batch is the
+// current state, passed with initial set; failures are retried with backoff.
+func runMatrixSync(ctx context.Context, api matrixAPI, handle func(context.Context, matrixSync, bool)) error {
+	since := ""
+	wait := time.Second
+	for {
+		batch, err := api.sync(ctx, since)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			logging.Logger().Warn("matrix sync failed; retrying", "err", err, "wait", wait.String())
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(wait):
+			}
+			wait = min(wait*2, matrixReconnectMax)
+			continue
+		}
+		wait = time.Second
+		handle(ctx, batch, since == "")
+		since = batch.NextBatch
+	}
+}
+
+// handleSync applies one sync batch: invites from allowlisted users are
+// accepted, membership is tracked, and new messages and reactions are
+// handled. The initial batch only updates membership.
+func (m *MatrixListener) handleSync(ctx context.Context, batch matrixSync, initial bool, inbound chan<- matrixInbound) {
+	for roomID, invite := range batch.Rooms.Invite {
+		inviter := matrixInviter(invite.InviteState.Events, m.userID)
+		if !m.isAllowedUser(inviter) {
+			logging.Logger().Info("ignoring matrix invite from unauthorized user", "room", roomID, "inviter", inviter)
+			continue
+		}
+		if err := m.api.join(ctx, roomID); err != nil {
+			logging.Logger().Warn("failed to join matrix room", "room", roomID, "inviter", inviter, "err", err)
+			continue
+		}
+		logging.Logger().Info("joined matrix room", "room", roomID, "inviter", inviter)
+	}
+	for roomID := range batch.Rooms.Leave {
+		m.roomsMu.Lock()
+		delete(m.members, roomID)
+		m.roomsMu.Unlock()
+	}
+
+	for roomID, room := range batch.Rooms.Join {
+		for _, event := range room.State.Events {
+			m.trackMember(roomID, event)
+		}
+		for _, event := range room.Timeline.Events {
+			m.trackMember(roomID, event)
+			if initial || event.Sender == m.userID {
+				continue
+			}
+			switch event.Type {
+			case "m.room.message":
+				// Answers are taken here, since the queue is held up by
+				// the turn waiting for them.
+				if m.handleReplyAnswer(ctx, event) {
+					continue
+				}
+				select {
+				case inbound <- matrixInbound{roomID: roomID, event: event}:
+				case <-ctx.Done():
+					return
+				}
+			case "m.reaction":
+				m.handleReaction(ctx, event)
+			case "m.room.encrypted":
+				m.warnEncrypted(roomID, event.Sender)
+			}
+		}
+	}
+}
+
+// trackMember keeps the joined members of roomID current.
+func (m *MatrixListener) trackMember(roomID string, event matrixEvent) {
+	if event.Type != "m.room.member" || event.StateKey == nil {
+		return
+	}
+	m.roomsMu.Lock()
+	defer m.roomsMu.Unlock()
+	members := m.members[roomID]
+	if members == nil {
+		members = make(map[string]struct{})
+		m.members[roomID] = members
+	}
+	if event.content().Membership == "join" {
+		members[*event.StateKey] = struct{}{}
+	} else {
+		delete(members, *event.StateKey)
+	}
+}
+
+// isDirect reports whether roomID is a chat between the bot and one person.
+func (m *MatrixListener) isDirect(roomID string) bool {
+	m.roomsMu.Lock()
+	defer m.roomsMu.Unlock()
+	return len(m.members[roomID]) <= 2
+}
+
+// warnEncrypted logs once per room that an encrypted message could not be
+// read, which means no E2EE proxy sits in front of the homeserver.
+func (m *MatrixListener) warnEncrypted(roomID, sender string) {
+	if !m.isAllowedUser(sender) {
+		return
+	}
+	m.roomsMu.Lock()
+	_, warned := m.warnedEncrypted[roomID]
+	m.warnedEncrypted[roomID] = struct{}{}
+	m.roomsMu.Unlock()
+	if !warned {
+		logging.Logger().Warn("ignoring encrypted matrix messages; point homeserver at an E2EE proxy such as pantalaimon to read them", "room", roomID, "sender", sender)
+	}
+}
+
+// handleInboundMessage dispatches text messages from allowlisted users:
+// every message in a direct chat, and mentions of the bot elsewhere.
+func (m *MatrixListener) handleInboundMessage(ctx context.Context, dispatcher *runtime.Dispatcher, message matrixInbound) {
+	event := message.event
+	content := event.content()
+	if content.MsgType != "m.text" {
+		return
+	}
+	if content.RelatesTo != nil && content.RelatesTo.RelType == "m.replace" {
+		return
+	}
+	logging.Logger().Info(
+		"matrix inbound message",
+		"user_id", event.Sender,
+		"room", message.roomID,
+		"text", messagePreview(content.Body, 100),
+	)
+	if !m.isAllowedUser(event.Sender) {
+		return
+	}
+
+	direct := m.isDirect(message.roomID)
+	text := stripMatrixReplyFallback(content.Body)
+	if !direct {
+		mentioned := strings.Contains(text, m.userID)
+		if content.Mentions != nil && slices.Contains(content.Mentions.UserIDs, m.userID) {
+			mentioned = true
+		}
+		if !mentioned {
+			return
+		}
+		text = strings.TrimSpace(strings.ReplaceAll(text, m.userID, ""))
+		text = strings.TrimSpace(strings.TrimPrefix(text, ":"))
+	}
+	if text == "" {
+		return
+	}
+
+	// Mentions in a room are answered in a thread to keep the room
+	// readable; direct chats stay flat unless the user started a thread.
+	threadRoot := ""
+	if content.RelatesTo != nil && content.RelatesTo.RelType == "m.thread" {
+		threadRoot = content.RelatesTo.EventID
+	} else if !direct {
+		threadRoot = event.EventID
+	}
+	if direct {
+		m.roomsMu.Lock()
+		m.lastRoom[event.Sender] = message.roomID
+		m.roomsMu.Unlock()
+	}
+
+	writer := &matrixWriter{listener: m, userID: event.Sender, roomID: message.roomID, threadRoot: threadRoot}
+	if err := dispatcher.Enqueue(ctx, &runtime.Message{Text: text, UserID: event.Sender}, writer); err != nil {
+		logging.Logger().Warn("matrix enqueue failed", "user_id", event.Sender, "err", err)
+	}
+}
+
+// RequestApproval sends the prompt where the message being handled came
+// from, reacts to it with ✅ and ❌ so the user can tap one, and waits for
+// the same user's reaction.
+func (m *MatrixListener) RequestApproval(ctx context.Context, req approval.ApprovalRequest) (approval.ApprovalDecision, error) {
+	if ctx.Err() != nil {
+		return approval.Denied, nil
+	}
+	target, ok := m.activeTargetSnapshot()
+	if !ok {
+		return approval.Denied, errors.New("matrix approval target is unavailable")
+	}
+
+	code, err := m.replies.Open(target.userID)
+	if err != nil {
+		return approval.Denied, err
+	}
+	defer m.replies.Close(code)
+	prompt := approvalPrompt(req)
+	instructions := fmt.Sprintf("React with %s to approve or %s to deny, or reply \"yes %s\" or \"no %s\".", matrixApproveKey, matrixDenyKey, code, code)
+	eventID, err := m.sendText(ctx, target.roomID, target.threadRoot, prompt+"\n\n"+instructions)
+	if err != nil {
+		return approval.Denied, fmt.Errorf("send approval prompt: %w", err)
+	}
+	pending := matrixPendingApproval{
+		tool:     req.Tool,
+		prompt:   prompt,
+		userID:   target.userID,
+		roomID:   target.roomID,
+		code:     code,
+		response: make(chan approval.ApprovalDecision, 1),
+	}
+	m.approvalMu.Lock()
+	m.pendingApprovals[eventID] = pending
+	m.approvalMu.Unlock()
+	defer m.takePendingApproval(eventID)
+
+	for _, key := range []string{matrixApproveKey, matrixDenyKey} {
+		if _, err := m.api.send(ctx, target.roomID, "m.reaction", map[string]any{
+			"m.relates_to": map[string]any{"rel_type": "m.annotation", "event_id": eventID, "key": key},
+		}); err != nil {
+			logging.Logger().Debug("failed to add matrix approval reaction", "key", key, "err", err)
+		}
+	}
+
+	var expired <-chan time.Time
+	if m.ApprovalTimeout > 0 {
+		timer := time.NewTimer(m.ApprovalTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case decision := <-pending.response:
+		return decision, nil
+	case <-expired:
+		if _, ok := m.takePendingApproval(eventID); ok {
+			m.closeApprovalPrompt(context.Background(), eventID, pending, fmt.Sprintf("⌛ Expired: no answer within %s", m.ApprovalTimeout))
+		}
+		return approval.Denied, fmt.Errorf("approval for %s timed out: the user did not answer within %s", req.Tool, m.ApprovalTimeout)
+	case <-ctx.Done():
+		if _, ok := m.takePendingApproval(eventID); ok {
+			m.closeApprovalPrompt(context.Background(), eventID, pending, "⌛ Expired: the request was cancelled")
+		}
+		return approval.Denied, nil
+	}
+}
+
+// handleReaction answers an approval prompt. Reactions by anyone other
+// than the user who was asked are ignored.
+func (m *MatrixListener) handleReaction(ctx context.Context, event matrixEvent) {
+	relation := event.content().RelatesTo
+	if relation == nil || relation.RelType != "m.annotation" {
+		return
+	}
+	decision := approval.Denied
+	// Some clients add a variation selector to emoji.
+	switch strings.TrimSuffix(relation.Key, "\ufe0f") {
+	case matrixApproveKey:
+		decision = approval.Approved
+	case matrixDenyKey:
+	default:
+		return
+	}
+
+	m.approvalMu.Lock()
+	pending, ok := m.pendingApprovals[relation.EventID]
+	if !ok || pending.userID != event.Sender {
+		m.approvalMu.Unlock()
+		return
+	}
+	delete(m.pendingApprovals, relation.EventID)
+	m.approvalMu.Unlock()
+	m.answerApproval(ctx, relation.EventID, pending, decision)
+}
+
+// handleReplyAnswer answers an approval prompt from a "yes <code>" or
+// "no <code>" message, for clients that cannot react. It reports whether
+// the message was such an answer.
+func (m *MatrixListener) handleReplyAnswer(ctx context.Context, event matrixEvent) bool {
+	content := event.content()
+	if content.MsgType != "m.text" {
+		return false
+	}
+	text := strings.TrimSpace(strings.ReplaceAll(stripMatrixReplyFallback(content.Body), m.userID, ""))
+	code, decision, ok := m.replies.Match(event.Sender, strings.TrimSpace(strings.TrimPrefix(text, ":")))
+	if !ok {
+		return false
+	}
+	m.approvalMu.Lock()
+	var eventID string
+	var pending matrixPendingApproval
+	for id, candidate := range m.pendingApprovals {
+		if candidate.code == code {
+			eventID, pending = id, candidate
+		}
+	}
+	if eventID == "" {
+		m.approvalMu.Unlock()
+		return false
+	}
+	delete(m.pendingApprovals, eventID)
+	m.approvalMu.Unlock()
+	m.answerApproval(ctx, eventID, pending, decision)
+	return true
+}
+
+// answerApproval closes the prompt and hands decision to the waiting turn.
+func (m *MatrixListener) answerApproval(ctx context.Context, eventID string, pending matrixPendingApproval, decision approval.ApprovalDecision) {
+	status := "❌ Denied"
+	if decision == approval.Approved {
+		status = "✅ Approved"
+	}
+	m.closeApprovalPrompt(ctx, eventID, pending, status)
+	select {
+	case pending.response <- decision:
+	default:
+	}
+}
+
+// closeApprovalPrompt edits the prompt to show status, so a stale prompt
+// does not look answerable.
+func (m *MatrixListener) closeApprovalPrompt(ctx context.Context, eventID string, pending matrixPendingApproval, status string) {
+	text := pending.prompt + "\n\n" + status
+	if _, err := m.api.send(ctx, pending.roomID, "m.room.message", map[string]any{
+		"msgtype":       "m.text",
+		"body":          "* " + text,
+		"m.new_content": map[string]any{"msgtype": "m.text", "body": text},
+		"m.relates_to":  map[string]any{"rel_type": "m.replace", "event_id": eventID},
+	}); err != nil {
+		logging.Logger().Warn("failed to close matrix approval prompt", "tool", pending.tool, "err", err)
+	}
+}
+
+// ApproverName names the Matrix user who answers approval prompts.
+func (m *MatrixListener) ApproverName() string {
+	target, ok := m.activeTargetSnapshot()
+	if !ok {
+		return MatrixChannel
+	}
+	return "matrix user " + target.userID
+}
+
+func (m *MatrixListener) takePendingApproval(eventID string) (matrixPendingApproval, bool) {
+	m.approvalMu.Lock()
+	defer m.approvalMu.Unlock()
+	pending, ok := m.pendingApprovals[eventID]
+	delete(m.pendingApprovals, eventID)
+	return pending, ok
+}
+
+func (m *MatrixListener) setActiveTarget(target matrixTarget) {
+	m.approvalMu.Lock()
+	defer m.approvalMu.Unlock()
+	m.activeTarget = &target
+}
+
+func (m *MatrixListener) clearActiveTarget() {
+	m.approvalMu.Lock()
+	defer m.approvalMu.Unlock()
+	m.activeTarget = nil
+}
+
+func (m *MatrixListener) activeTargetSnapshot() (matrixTarget, bool) {
+	m.approvalMu.Lock()
+	defer m.approvalMu.Unlock()
+	if m.activeTarget == nil {
+		return matrixTarget{}, false
+	}
+	return *m.activeTarget, true
+}
+
+// sendText sends text, with credentials filtered, to roomID and returns the
+// event ID. A threadRoot sends it in that thread.
+func (m *MatrixListener) sendText(ctx context.Context, roomID, threadRoot, text string) (string, error) {
+	text, found := redact.FilterSecrets(text, m.OutboundSecrets)
+	if len(found) > 0 {
+		logging.Logger().Warn("outbound matrix message contained credentials", "room", roomID, "kinds", strings.Join(found, ", "), "mode", m.OutboundSecrets)
+	}
+	content := map[string]any{"msgtype": "m.text", "body": text}
+	if threadRoot != "" {
+		content["m.relates_to"] = map[string]any{
+			"rel_type":        "m.thread",
+			"event_id":        threadRoot,
+			"is_falling_back": true,
+			"m.in_reply_to":   map[string]any{"event_id": threadRoot},
+		}
+	}
+	return m.api.send(ctx, roomID, "m.room.message", content)
+}
+
+// Send delivers a channel message to where the current request came from.
+func (m *MatrixListener) Send(ctx context.Context, message string) error {
+	target, ok := m.activeTargetSnapshot()
+	if !ok {
+		return errors.New("matrix chat target is unavailable")
+	}
+	_, err := m.sendText(ctx, target.roomID, target.threadRoot, message)
+	return err
+}
+
+// CurrentChannelID returns the scheduler channel key of the user whose
+// request is being handled.
+func (m *MatrixListener) CurrentChannelID() string {
+	target, ok := m.activeTargetSnapshot()
+	if !ok {
+		return ""
+	}
+	return m.ChannelKey(target.userID)
+}
+
+// directRoom returns the direct chat to deliver to userID in: the one the
+// user last wrote from, or else any room with just the bot and the user.
+func (m *MatrixListener) directRoom(userID string) (string, bool) {
+	m.roomsMu.Lock()
+	defer m.roomsMu.Unlock()
+	if roomID, ok := m.lastRoom[userID]; ok {
+		if _, joined := m.members[roomID]; joined {
+			return roomID, true
+		}
+	}
+	for roomID, members := range m.members {
+		if _, ok := members[userID]; ok && len(members) == 2 {
+			return roomID, true
+		}
+	}
+	return "", false
+}
+
+type matrixWriter struct {
+	listener   *MatrixListener
+	userID     string
+	roomID     string
+	threadRoot string
+}
+
+func (w *matrixWriter) WriteMessage(ctx context.Context, text string) error {
+	if w == nil || w.listener == nil {
+		return errors.New("matrix sender is not configured")
+	}
+	_, err := w.listener.sendText(ctx, w.roomID, w.threadRoot, text)
+	return err
+}
+
+type matrixChannelWriter struct {
+	listener *MatrixListener
+	userID   string
+}
+
+func (w matrixChannelWriter) Write(p []byte) (int, error) {
+	text := strings.TrimSpace(string(p))
+	if text == "" {
+		return len(p), nil
+	}
+	roomID, ok := w.listener.directRoom(w.userID)
+	if !ok {
+		return 0, fmt.Errorf("no direct matrix chat with %s; invite the bot to one", w.userID)
+	}
+	if _, err := w.listener.sendText(context.Background(), roomID, "", text); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ChannelWriter returns an io.Writer that delivers scheduler messages to a
+// Matrix user's direct chat with the bot.
+func (m *MatrixListener) ChannelWriter(userID string) io.Writer {
+	return matrixChannelWriter{listener: m, userID: userID}
+}
+
+type matrixApprovalHandler struct {
+	listener *MatrixListener
+	handler  runtime.Handler
+}
+
+func (h *matrixApprovalHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	if writer, ok := w.(*matrixWriter); ok {
+		h.listener.setActiveTarget(matrixTarget{userID: writer.userID, roomID: writer.roomID, threadRoot: writer.threadRoot})
+		defer h.listener.clearActiveTarget()
+	}
+	return h.handler.HandleMessage(ctx, w, msg)
+}
@@ -0,0 +1,237 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	matrixRequestTimeout = 30 * time.Second
+	// matrixSyncTimeout is how long the homeserver holds a /sync request
+	// open when nothing happens.
+	matrixSyncTimeout = 30 * time.Second
+	// maxMatrixResponse bounds one response; sync batches are the largest.
+	maxMatrixResponse = 16 << 20
+	// matrixReconnectMax caps the wait between failed sync attempts.
+	matrixReconnectMax = 30 * time.Second
+)
+
+// matrixAPI calls the Matrix client-server API of one homeserver with the
+// bot account's access token.
+type matrixAPI struct {
+	homeserver string
+	token      string
+	client     *http.Client
+}
+
+// matrixError is the error body the client-server API returns.
+type matrixError struct {
+	ErrCode string `json:"errcode"`
+	Error   string `json:"error"`
+}
+
+// matrixTxn makes transaction IDs unique within this process; the
+// homeserver deduplicates retried sends by them.
+var matrixTxn atomic.Uint64
+
+// call sends body as JSON to path under /_matrix/client/v3 and decodes the
+// reply into out. timeout bounds the whole request.
+func (a matrixAPI) call(ctx context.Context, method, path string, query url.Values, body, out any, timeout time.Duration) error {
+	var payload io.Reader = http.NoBody
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode matrix %s: %w", path, err)
+		}
+		payload = bytes.NewReader(encoded)
+	}
+	endpoint := strings.TrimRight(a.homeserver, "/") + "/_matrix/client/v3" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, payload)
+	if err != nil {
+		return fmt.Errorf("build matrix %s request: %w", path, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := a.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("matrix %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxMatrixResponse))
+	if err != nil {
+		return fmt.Errorf("read matrix %s response: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure matrixError
+		if json.Unmarshal(raw, &failure) == nil && failure.ErrCode != "" {
+			return fmt.Errorf("matrix %s: %s: %s", path, failure.ErrCode, failure.Error)
+		}
+		return fmt.Errorf("matrix %s: HTTP %d", path, resp.StatusCode)
+	}
+	if out != nil {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return fmt.Errorf("decode matrix %s response: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// whoami returns the user ID the access token belongs to.
+func (a matrixAPI) whoami(ctx context.Context) (string, error) {
+	var identity struct {
+		UserID string `json:"user_id"`
+	}
+	if err := a.call(ctx, http.MethodGet, "/account/whoami", nil, nil, &identity, matrixRequestTimeout); err != nil {
+		return "", err
+	}
+	return identity.UserID, nil
+}
+
+// sync returns the events after since. An empty since returns the current
+// state of every room with a short timeline, which is used to skip the
+// backlog on start.
+func (a matrixAPI) sync(ctx context.Context, since string) (matrixSync, error) {
+	query := url.Values{}
+	wait := time.Duration(0)
+	if since != "" {
+		query.Set("since", since)
+		wait = matrixSyncTimeout
+	}
+	query.Set("timeout", fmt.Sprint(wait.Milliseconds()))
+	var batch matrixSync
+	err := a.call(ctx, http.MethodGet, "/sync", query, nil, &batch, wait+matrixRequestTimeout)
+	return batch, err
+}
+
+// join accepts an invite to roomID.
+func (a matrixAPI) join(ctx context.Context, roomID string) error {
+	return a.call(ctx, http.MethodPost, "/join/"+url.PathEscape(roomID), nil, map[string]any{}, nil, matrixRequestTimeout)
+}
+
+// send posts one event to roomID and returns its event ID.
+func (a matrixAPI) send(ctx context.Context, roomID, eventType string, content any) (string, error) {
+	txnID := fmt.Sprintf("neoclaw-%d-%d", time.Now().UnixNano(), matrixTxn.Add(1))
+	var sent struct {
+		EventID string `json:"event_id"`
+	}
+	path := "/rooms/" + url.PathEscape(roomID) + "/send/" + url.PathEscape(eventType) + "/" + txnID
+	if err := a.call(ctx, http.MethodPut, path, nil, content, &sent, matrixRequestTimeout); err != nil {
+		return "", err
+	}
+	return sent.EventID, nil
+}
+
+// displayName returns userID's profile name, or "" if it has none.
+func (a matrixAPI) displayName(ctx context.Context, userID string) (string, error) {
+	var profile struct {
+		DisplayName string `json:"displayname"`
+	}
+	if err := a.call(ctx, http.MethodGet, "/profile/"+url.PathEscape(userID)+"/displayname", nil, nil, &profile, matrixRequestTimeout); err != nil {
+		return "", err
+	}
+	return profile.DisplayName, nil
+}
+
+// matrixSync is the part of a /sync response NeoClaw uses.
+type matrixSync struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join map[string]struct {
+			State struct {
+				Events []matrixEvent `json:"events"`
+			} `json:"state"`
+			Timeline struct {
+				Events []matrixEvent `json:"events"`
+			} `json:"timeline"`
+		} `json:"join"`
+		Invite map[string]struct {
+			InviteState struct {
+				Events []matrixEvent `json:"events"`
+			} `json:"invite_state"`
+		} `json:"invite"`
+		Leave map[string]json.RawMessage `json:"leave"`
+	} `json:"rooms"`
+}
+
+// matrixEvent is one room event.
+type matrixEvent struct {
+	Type     string          `json:"type"`
+	EventID  string          `json:"event_id"`
+	Sender   string          `json:"sender"`
+	StateKey *string         `json:"state_key"`
+	Content  json.RawMessage `json:"content"`
+}
+
+// matrixContent is the part of message, member, and reaction content
+// NeoClaw reads.
+type matrixContent struct {
+	MsgType    string `json:"msgtype"`
+	Body       string `json:"body"`
+	Membership string `json:"membership"`
+	RelatesTo  *struct {
+		RelType string `json:"rel_type"`
+		EventID string `json:"event_id"`
+		Key     string `json:"key"`
+	} `json:"m.relates_to"`
+	Mentions *struct {
+		UserIDs []string `json:"user_ids"`
+	} `json:"m.mentions"`
+}
+
+func (e matrixEvent) content() matrixContent {
+	var content matrixContent
+	_ = json.Unmarshal(e.Content, &content)
+	return content
+}
+
+// matrixInviter returns who invited userID, from the stripped state of an
+// invited room.
+func matrixInviter(events []matrixEvent, userID string) string {
+	for _, event := range events {
+		if event.Type == "m.room.member" && event.StateKey != nil && *event.StateKey == userID && event.content().Membership == "invite" {
+			return event.Sender
+		}
+	}
+	return ""
+}
+
+// stripMatrixReplyFallback removes the quoted "> " lines clients put at
+// the start of a reply's body.
+func stripMatrixReplyFallback(body string) string {
+	if !strings.HasPrefix(body, "> ") {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, ">") {
+			return strings.TrimSpace(strings.Join(lines[i:], "\n"))
+		}
+	}
+	return ""
+}
+
+// matrixLocalpart returns "alice" for @alice:example.org.
+func matrixLocalpart(userID string) string {
+	localpart, _, _ := strings.Cut(strings.TrimPrefix(userID, "@"), ":")
+	return localpart
+}
@@ -0,0 +1,156 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+// MatrixPairSession represents one active Matrix pairing session.
+type MatrixPairSession struct {
+	api              matrixAPI
+	botUserID        string
+	roomID           string
+	expectedCode     string
+	userID           string
+	name             string
+	allowedUsersPath string
+}
+
+// BeginMatrixPairing syncs with the homeserver, accepts invites, and waits
+// for the first text message to the bot. The sender gets a code to enter in
+// the terminal and is added to the allowlist at allowedUsersPath once it
+// matches.
+func BeginMatrixPairing(ctx context.Context, homeserver, token, allowedUsersPath string) (*MatrixPairSession, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(homeserver) == "" || strings.TrimSpace(token) == "" {
+		return nil, errors.New("matrix homeserver and token are required")
+	}
+	return beginMatrixPairing(ctx, matrixAPI{homeserver: homeserver, token: token}, allowedUsersPath)
+}
+
+func beginMatrixPairing(ctx context.Context, api matrixAPI, allowedUsersPath string) (*MatrixPairSession, error) {
+	botUserID, err := api.whoami(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("check matrix access token: %w", err)
+	}
+	logging.Logger().Info(fmt.Sprintf("Connected to Matrix as %s", botUserID))
+
+	type firstMessage struct {
+		roomID string
+		sender string
+	}
+	syncCtx, stopSync := context.WithCancel(ctx)
+	defer stopSync()
+	firstInbound := make(chan firstMessage, 1)
+	go runMatrixSync(syncCtx, api, func(ctx context.Context, batch matrixSync, initial bool) {
+		// Pairing is started by the operator, so any invite is accepted;
+		// only whoever then enters the code is authorized.
+		for roomID := range batch.Rooms.Invite {
+			if err := api.join(ctx, roomID); err != nil {
+				logging.Logger().Warn("failed to join matrix room", "room", roomID, "err", err)
+			}
+		}
+		if initial {
+			return
+		}
+		for roomID, room := range batch.Rooms.Join {
+			for _, event := range room.Timeline.Events {
+				if event.Sender == botUserID {
+					continue
+				}
+				if event.Type == "m.room.encrypted" {
+					logging.Logger().Warn("ignoring encrypted matrix message; pair through an E2EE proxy such as pantalaimon", "room", roomID)
+					continue
+				}
+				if event.Type != "m.room.message" || event.content().MsgType != "m.text" {
+					continue
+				}
+				select {
+				case firstInbound <- firstMessage{roomID: roomID, sender: event.Sender}:
+				default:
+				}
+			}
+		}
+	})
+
+	var inbound firstMessage
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case inbound = <-firstInbound:
+	}
+
+	code, err := generatePairCode()
+	if err != nil {
+		return nil, fmt.Errorf("generate pairing code: %w", err)
+	}
+	session := &MatrixPairSession{
+		api:              api,
+		botUserID:        botUserID,
+		roomID:           inbound.roomID,
+		expectedCode:     code,
+		userID:           inbound.sender,
+		allowedUsersPath: allowedUsersPath,
+	}
+	if err := session.send(ctx, fmt.Sprintf("Pairing mode active. Your code is: %s - enter this in your terminal.", code)); err != nil {
+		return nil, fmt.Errorf("send pairing code: %w", err)
+	}
+	if name, err := api.displayName(ctx, inbound.sender); err != nil {
+		logging.Logger().Debug("could not look up matrix display name", "user_id", inbound.sender, "err", err)
+	} else {
+		session.name = name
+	}
+	return session, nil
+}
+
+func (s *MatrixPairSession) send(ctx context.Context, text string) error {
+	_, err := s.api.send(ctx, s.roomID, "m.room.message", map[string]any{"msgtype": "m.text", "body": text})
+	return err
+}
+
+// BotUsername returns the localpart of the bot account's user ID.
+func (s *MatrixPairSession) BotUsername() string {
+	return matrixLocalpart(s.botUserID)
+}
+
+// UserID returns the paired user's Matrix ID, such as @alice:example.org.
+func (s *MatrixPairSession) UserID() string {
+	return s.userID
+}
+
+// Username returns the localpart of the paired user's Matrix ID.
+func (s *MatrixPairSession) Username() string {
+	return matrixLocalpart(s.userID)
+}
+
+// Name returns the paired user's display name, if they have one.
+func (s *MatrixPairSession) Name() string {
+	return s.name
+}
+
+// SubmitCode validates an entered code and persists the paired Matrix user on success.
+func (s *MatrixPairSession) SubmitCode(ctx context.Context, entered string) error {
+	if strings.TrimSpace(entered) != s.expectedCode {
+		return ErrWrongCode
+	}
+	if err := s.send(ctx, "You are now authorized. Restart the bot server to activate."); err != nil {
+		return fmt.Errorf("send pairing confirmation: %w", err)
+	}
+	if err := approval.AddUser(s.allowedUsersPath, approval.User{
+		ID:       s.userID,
+		Channel:  MatrixChannel,
+		Username: matrixLocalpart(s.userID),
+		Name:     s.name,
+	}); err != nil {
+		return fmt.Errorf("persist paired user: %w", err)
+	}
+	logging.Logger().Info("matrix user paired", "user_id", s.userID, "channel", MatrixChannel)
+	return nil
+}
@@ -0,0 +1,91 @@
+package channels
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+)
+
+// replyCodeDigits is the length of a reply code: short enough to type on a
+// phone, and only ever matched against the few prompts open for one user.
+const replyCodeDigits = 4
+
+// matrixReplyCodes lets Matrix users answer approval prompts by reply when
+// reactions are awkward: each prompt shows a short code, and the asked user
+// answers with "yes <code>" or "no <code>". A code identifies the prompt, so
+// a stray "yes" in conversation never approves anything.
+type matrixReplyCodes struct {
+	mu sync.Mutex
+	// owners maps each open code to the user it was asked of.
+	owners map[string]string
+}
+
+func newMatrixReplyCodes() *matrixReplyCodes {
+	return &matrixReplyCodes{owners: make(map[string]string)}
+}
+
+// Open returns a new code for a prompt answered by userID. Close it when the
+// prompt is answered or expires.
+func (r *matrixReplyCodes) Open(userID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limit := big.NewInt(1)
+	for range replyCodeDigits {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	for range 100 {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", fmt.Errorf("generate reply code: %w", err)
+		}
+		code := fmt.Sprintf("%0*d", replyCodeDigits, n)
+		if _, taken := r.owners[code]; !taken {
+			r.owners[code] = userID
+			return code, nil
+		}
+	}
+	return "", errors.New("too many approval prompts are open")
+}
+
+// Close forgets code.
+func (r *matrixReplyCodes) Close(code string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.owners, code)
+}
+
+// Match reports whether text from userID answers one of their open prompts,
+// and with which code and decision. The code stays open; the caller closes
+// it once the prompt is resolved. Text that does not match is an ordinary
+// message.
+func (r *matrixReplyCodes) Match(userID, text string) (string, approval.ApprovalDecision, bool) {
+	decision, code, ok := parseReply(text)
+	if !ok {
+		return "", approval.Denied, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if owner, open := r.owners[code]; !open || owner != userID {
+		return "", approval.Denied, false
+	}
+	return code, decision, true
+}
+
+// parseReply splits a reply such as "yes 4821" or "No 4821." into its
+// decision and code.
+func parseReply(text string) (approval.ApprovalDecision, string, bool) {
+	fields := strings.Fields(strings.TrimRight(strings.TrimSpace(text), ".!"))
+	if len(fields) != 2 {
+		return approval.Denied, "", false
+	}
+	decision, ok := approval.ReplyDecision(fields[0])
+	if !ok {
+		return approval.Denied, "", false
+	}
+	return decision, fields[1], true
+}
@@ -0,0 +1,38 @@
+package channels
+
+import (
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+)
+
+func TestMatrixReplyCodesMatchOnlyTheAskedUsersOpenCodes(t *testing.T) {
+	replies := newMatrixReplyCodes()
+	code, err := replies.Open("alice")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if len(code) != replyCodeDigits {
+		t.Fatalf("expected a %d digit code, got %q", replyCodeDigits, code)
+	}
+
+	if got, decision, ok := replies.Match("alice", "Yes "+code+"."); !ok || got != code || decision != approval.Approved {
+		t.Fatalf("expected alice's yes to match, got %q %v %v", got, decision, ok)
+	}
+	if _, decision, ok := replies.Match("alice", "deny "+code); !ok || decision != approval.Denied {
+		t.Fatalf("expected alice's deny to match, got %v %v", decision, ok)
+	}
+	for _, text := range []string{"yes", "yes please " + code, "maybe " + code, "yes 0000x"} {
+		if _, _, ok := replies.Match("alice", text); ok {
+			t.Fatalf("expected %q not to be an answer", text)
+		}
+	}
+	if _, _, ok := replies.Match("bob", "yes "+code); ok {
+		t.Fatal("expected another user's reply not to match")
+	}
+
+	replies.Close(code)
+	if _, _, ok := replies.Match("alice", "yes "+code); ok {
+		t.Fatal("expected a closed code not to match")
+	}
+}
@@ -0,0 +1,268 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeHomeserver serves the client-server API calls the listener makes.
+// The first /sync returns initial; later ones return batches pushed on
+// syncs.
+type fakeHomeserver struct {
+	server  *httptest.Server
+	initial string
+	syncs   chan string
+	// calls receives every join and send as "METHOD path" plus JSON body.
+	calls chan matrixCall
+	sent  atomic.Int64
+}
+
+type matrixCall struct {
+	path string
+	body map[string]any
+}
+
+func newFakeHomeserver(t *testing.T, initial string) *fakeHomeserver {
+	f := &fakeHomeserver{initial: initial, syncs: make(chan string, 10), calls: make(chan matrixCall, 20)}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer syt-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"errcode":"M_UNKNOWN_TOKEN","error":"Invalid access token"}`)
+			return
+		}
+		path := strings.TrimPrefix(r.URL.EscapedPath(), "/_matrix/client/v3")
+		switch {
+		case path == "/account/whoami":
+			io.WriteString(w, `{"user_id":"@claw:example.org"}`)
+		case path == "/sync" && r.URL.Query().Get("since") == "":
+			io.WriteString(w, f.initial)
+		case path == "/sync":
+			select {
+			case batch := <-f.syncs:
+				io.WriteString(w, batch)
+			case <-r.Context().Done():
+			}
+		default:
+			var body map[string]any
+			raw, _ := io.ReadAll(r.Body)
+			json.Unmarshal(raw, &body)
+			n := f.sent.Add(1)
+			f.calls <- matrixCall{path: r.Method + " " + path, body: body}
+			fmt.Fprintf(w, `{"event_id":"$sent%d","room_id":"!room"}`, n)
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeHomeserver) waitForCall(t *testing.T, prefix string) matrixCall {
+	t.Helper()
+	for {
+		select {
+		case call := <-f.calls:
+			if strings.HasPrefix(call.path, prefix) {
+				return call
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected a %s call", prefix)
+		}
+	}
+}
+
+// matrixBatch builds a /sync response with timeline events in roomID.
+func matrixBatch(next, roomID string, events ...string) string {
+	return `{"next_batch":"` + next + `","rooms":{"join":{"` + roomID + `":{"timeline":{"events":[` + strings.Join(events, ",") + `]}}}}}`
+}
+
+const matrixTestInitial = `{
+  "next_batch": "s1",
+  "rooms": {
+    "join": {
+      "!dm:example.org": {
+        "state": {"events": [
+          {"type":"m.room.member","state_key":"@claw:example.org","sender":"@claw:example.org","content":{"membership":"join"}},
+          {"type":"m.room.member","state_key":"@alice:example.org","sender":"@alice:example.org","content":{"membership":"join"}}
+        ]},
+        "timeline": {"events": [
+          {"type":"m.room.message","event_id":"$old","sender":"@alice:example.org","content":{"msgtype":"m.text","body":"sent while stopped"}}
+        ]}
+      },
+      "!team:example.org": {
+        "state": {"events": [
+          {"type":"m.room.member","state_key":"@claw:example.org","sender":"@claw:example.org","content":{"membership":"join"}},
+          {"type":"m.room.member","state_key":"@alice:example.org","sender":"@alice:example.org","content":{"membership":"join"}},
+          {"type":"m.room.member","state_key":"@bob:example.org","sender":"@bob:example.org","content":{"membership":"join"}}
+        ]}
+      }
+    },
+    "invite": {
+      "!spam:example.org": {"invite_state": {"events": [
+        {"type":"m.room.member","state_key":"@claw:example.org","sender":"@mallory:example.org","content":{"membership":"invite"}}
+      ]}},
+      "!new:example.org": {"invite_state": {"events": [
+        {"type":"m.room.member","state_key":"@claw:example.org","sender":"@alice:example.org","content":{"membership":"invite"}}
+      ]}}
+    }
+  }
+}`
+
+const matrixTestUsers = `{
+  "users": [
+    {"id":"@alice:example.org","channel":"matrix","username":"alice","name":"Alice","added_at":"2026-02-19T14:30:00Z"},
+    {"id":"111","channel":"telegram","username":"alice","name":"Alice","added_at":"2026-02-19T14:30:00Z"}
+  ]
+}
+`
+
+func startMatrixListener(t *testing.T, homeserver *fakeHomeserver) (*MatrixListener, *approvingHandler) {
+	t.Helper()
+	listener := NewMatrix(homeserver.server.URL, "syt-test", writeAllowedUsersFile(t, matrixTestUsers))
+	handler := &approvingHandler{approver: listener, texts: make(chan string, 2)}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- listener.Listen(ctx, handler) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("listen: %v", err)
+		}
+	})
+	return listener, handler
+}
+
+func TestMatrixListener_ApprovesByReaction(t *testing.T) {
+	homeserver := newFakeHomeserver(t, matrixTestInitial)
+	listener, handler := startMatrixListener(t, homeserver)
+
+	// Only the invite from an allowlisted user is accepted; the backlog in
+	// the initial sync is skipped.
+	if join := homeserver.waitForCall(t, "POST /join/"); join.path != "POST /join/%21new:example.org" {
+		t.Fatalf("expected to join the invited room, got %s", join.path)
+	}
+
+	homeserver.syncs <- matrixBatch("s2", "!dm:example.org",
+		`{"type":"m.room.message","event_id":"$m0","sender":"@mallory:example.org","content":{"msgtype":"m.text","body":"hi"}}`,
+		`{"type":"m.room.message","event_id":"$m1","sender":"@alice:example.org","content":{"msgtype":"m.text","body":"run the tests"}}`,
+	)
+	select {
+	case text := <-handler.texts:
+		if text != "run the tests" {
+			t.Fatalf("expected the allowlisted message, got %q", text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not dispatched")
+	}
+
+	prompt := homeserver.waitForCall(t, "PUT /rooms/%21dm:example.org/send/m.room.message/")
+	if body, _ := prompt.body["body"].(string); !strings.Contains(body, "React with ✅ to approve") || prompt.body["m.relates_to"] != nil {
+		t.Fatalf("unexpected prompt %#v", prompt.body)
+	}
+	for range 2 {
+		homeserver.waitForCall(t, "PUT /rooms/%21dm:example.org/send/m.reaction/")
+	}
+
+	// Reactions from others are ignored; the variation selector some
+	// clients add is accepted.
+	homeserver.syncs <- matrixBatch("s3", "!dm:example.org",
+		`{"type":"m.reaction","event_id":"$r0","sender":"@mallory:example.org","content":{"m.relates_to":{"rel_type":"m.annotation","event_id":"$sent2","key":"✅"}}}`,
+		`{"type":"m.reaction","event_id":"$r1","sender":"@alice:example.org","content":{"m.relates_to":{"rel_type":"m.annotation","event_id":"$sent2","key":"✅\ufe0f"}}}`,
+	)
+
+	edit := homeserver.waitForCall(t, "PUT /rooms/%21dm:example.org/send/m.room.message/")
+	newContent, _ := edit.body["m.new_content"].(map[string]any)
+	if body, _ := newContent["body"].(string); !strings.HasSuffix(body, "✅ Approved") {
+		t.Fatalf("expected the prompt to be closed, got %#v", edit.body)
+	}
+	reply := homeserver.waitForCall(t, "PUT /rooms/%21dm:example.org/send/m.room.message/")
+	if reply.body["body"] != "tests passed" {
+		t.Fatalf("unexpected reply %#v", reply.body)
+	}
+
+	// Scheduled messages go to the direct chat the user wrote from.
+	if _, err := listener.ChannelWriter("@alice:example.org").Write([]byte("Daily briefing\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if call := homeserver.waitForCall(t, "PUT /rooms/"); !strings.HasPrefix(call.path, "PUT /rooms/%21dm:example.org/") || call.body["body"] != "Daily briefing" {
+		t.Fatalf("unexpected scheduled message %s %#v", call.path, call.body)
+	}
+	if _, err := listener.ChannelWriter("@bob:example.org").Write([]byte("hi")); err == nil {
+		t.Fatal("expected an error without a direct chat")
+	}
+}
+
+func TestMatrixListener_AnswersMentionsInRoomsInAThread(t *testing.T) {
+	homeserver := newFakeHomeserver(t, matrixTestInitial)
+	_, handler := startMatrixListener(t, homeserver)
+	homeserver.waitForCall(t, "POST /join/")
+
+	homeserver.syncs <- matrixBatch("s2", "!team:example.org",
+		`{"type":"m.room.message","event_id":"$m1","sender":"@alice:example.org","content":{"msgtype":"m.text","body":"lunch anyone?"}}`,
+		`{"type":"m.room.encrypted","event_id":"$m2","sender":"@alice:example.org","content":{"algorithm":"m.megolm.v1.aes-sha2","ciphertext":"..."}}`,
+		`{"type":"m.room.message","event_id":"$m3","sender":"@alice:example.org","content":{"msgtype":"m.text","body":"claw: check the build","m.mentions":{"user_ids":["@claw:example.org"]}}}`,
+	)
+	select {
+	case text := <-handler.texts:
+		if text != "claw: check the build" {
+			t.Fatalf("expected the mention to be handled, got %q", text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("mention was not dispatched")
+	}
+
+	prompt := homeserver.waitForCall(t, "PUT /rooms/%21team:example.org/send/m.room.message/")
+	relation, _ := prompt.body["m.relates_to"].(map[string]any)
+	if relation["rel_type"] != "m.thread" || relation["event_id"] != "$m3" {
+		t.Fatalf("expected the prompt in a thread under the mention, got %#v", prompt.body)
+	}
+}
+
+func TestMatrixListener_ApprovesByReplyCode(t *testing.T) {
+	homeserver := newFakeHomeserver(t, matrixTestInitial)
+	_, handler := startMatrixListener(t, homeserver)
+	homeserver.waitForCall(t, "POST /join/")
+
+	homeserver.syncs <- matrixBatch("s2", "!dm:example.org",
+		`{"type":"m.room.message","event_id":"$m1","sender":"@alice:example.org","content":{"msgtype":"m.text","body":"run the tests"}}`,
+	)
+	<-handler.texts
+	prompt := homeserver.waitForCall(t, "PUT /rooms/%21dm:example.org/send/m.room.message/")
+	body, _ := prompt.body["body"].(string)
+	code := regexp.MustCompile(`"yes (\d+)"`).FindStringSubmatch(body)
+	if code == nil {
+		t.Fatalf("expected a reply code in the prompt, got %q", body)
+	}
+	// The reactions are added once the prompt is registered.
+	for range 2 {
+		homeserver.waitForCall(t, "PUT /rooms/%21dm:example.org/send/m.reaction/")
+	}
+
+	// Another user's reply with the code is an ordinary message; Alice's
+	// answers the prompt instead of starting a turn.
+	homeserver.syncs <- matrixBatch("s3", "!dm:example.org",
+		`{"type":"m.room.message","event_id":"$m2","sender":"@mallory:example.org","content":{"msgtype":"m.text","body":"yes `+code[1]+`"}}`,
+		`{"type":"m.room.message","event_id":"$m3","sender":"@alice:example.org","content":{"msgtype":"m.text","body":"Yes `+code[1]+`"}}`,
+	)
+	edit := homeserver.waitForCall(t, "PUT /rooms/%21dm:example.org/send/m.room.message/")
+	newContent, _ := edit.body["m.new_content"].(map[string]any)
+	if body, _ := newContent["body"].(string); !strings.HasSuffix(body, "✅ Approved") {
+		t.Fatalf("expected the prompt to be closed, got %#v", edit.body)
+	}
+	reply := homeserver.waitForCall(t, "PUT /rooms/%21dm:example.org/send/m.room.message/")
+	if reply.body["body"] != "tests passed" {
+		t.Fatalf("unexpected reply %#v", reply.body)
+	}
+	select {
+	case text := <-handler.texts:
+		t.Fatalf("expected the answer not to be dispatched, got %q", text)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
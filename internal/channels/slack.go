@@ -0,0 +1,466 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/redact"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+)
+
+const (
+	// SlackChannel is the allowlist channel of Slack users.
+	SlackChannel = "slack"
+
+	slackApproveAction = "approval_approve"
+	slackDenyAction    = "approval_deny"
+)
+
+// slackMention matches a user mention such as <@U0123ABCD>.
+var slackMention = regexp.MustCompile(`<@[A-Z0-9]+>`)
+
+var _ runtime.Listener = (*SlackListener)(nil)
+var _ approval.Approver = (*SlackListener)(nil)
+
+// SlackListener receives Slack messages over Socket Mode, so no public URL
+// is needed. Direct messages and mentions of the bot from allowlisted users
+// are handled; replies go to the thread the message came from.
+type SlackListener struct {
+	Base
+	allowlist
+
+	appToken string
+	botToken string
+	api      slackAPI
+
+	// botUserID is the bot's own user, whose mentions are removed from
+	// incoming text.
+	botUserID string
+
+	approvalMu       sync.Mutex
+	activeTarget     *slackTarget
+	pendingApprovals map[string]slackPendingApproval
+}
+
+// slackTarget is where the message being handled came from.
+type slackTarget struct {
+	userID   string
+	channel  string
+	threadTS string
+}
+
+type slackPendingApproval struct {
+	tool     string
+	prompt   string
+	userID   string
+	channel  string
+	ts       string
+	response chan approval.ApprovalDecision
+}
+
+// NewSlack creates a Slack listener. appToken (xapp-) opens the Socket
+// Mode connection; botToken (xoxb-) posts messages.
+func NewSlack(appToken, botToken, allowedUsersPath string) *SlackListener {
+	return &SlackListener{
+		Base:             NewBase(),
+		allowlist:        newAllowlist(SlackChannel, allowedUsersPath),
+		appToken:         appToken,
+		botToken:         botToken,
+		pendingApprovals: make(map[string]slackPendingApproval),
+	}
+}
+
+// ChannelKey returns the scheduler channel key for one Slack user. Messages
+// to it are posted in the user's direct messages with the bot.
+func (s *SlackListener) ChannelKey(userID string) string {
+	return SlackChannel + "-" + userID
+}
+
+// Listen connects over Socket Mode and dispatches authorized messages.
+func (s *SlackListener) Listen(ctx context.Context, handler runtime.Handler) error {
+	if handler == nil {
+		return errors.New("handler is required")
+	}
+	if strings.TrimSpace(s.appToken) == "" || strings.TrimSpace(s.botToken) == "" {
+		return errors.New("slack app_token and token are required")
+	}
+	if err := s.loadAllowedUsers(); err != nil {
+		return err
+	}
+	if len(s.allowedUsers) == 0 {
+		logging.Logger().Warn("No authorized Slack users. Run claw pair --bot slack to authorize your account.")
+	}
+
+	var identity struct {
+		UserID string `json:"user_id"`
+		User   string `json:"user"`
+		Team   string `json:"team"`
+	}
+	if err := s.api.call(ctx, s.botToken, "auth.test", nil, &identity); err != nil {
+		return fmt.Errorf("check slack bot token: %w", err)
+	}
+	s.botUserID = identity.UserID
+	logging.Logger().Info(fmt.Sprintf("Connected to Slack as @%s in %s", identity.User, identity.Team))
+
+	dispatchCtx, cancelDispatch := context.WithCancel(ctx)
+	defer cancelDispatch()
+	dispatcher := runtime.NewDispatcher(&slackApprovalHandler{listener: s, handler: handler}, s.QueueSize)
+	if err := dispatcher.Start(dispatchCtx); err != nil {
+		return err
+	}
+	defer dispatcher.Wait()
+	defer dispatcher.Stop()
+
+	// Messages are queued apart from the socket so a full dispatch queue
+	// never holds up button presses, which the running turn may wait on.
+	events := make(chan slackEvent, s.QueueSize)
+	go func() {
+		for event := range events {
+			s.handleInboundMessage(dispatchCtx, dispatcher, event)
+		}
+	}()
+	defer close(events)
+
+	return runSocketMode(ctx, s.api, s.appToken, func(ctx context.Context, envelope slackEnvelope) {
+		switch envelope.Type {
+		case "events_api":
+			event, err := decodeSlackEvent(envelope)
+			if err != nil {
+				logging.Logger().Warn("ignoring malformed slack event", "err", err)
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		case "interactive":
+			s.handleInteraction(ctx, envelope.Payload)
+		}
+	})
+}
+
+// handleInboundMessage dispatches direct messages and mentions. Channel
+// messages without a mention arrive as plain message events too and are
+// ignored, so each request is handled once.
+func (s *SlackListener) handleInboundMessage(ctx context.Context, dispatcher *runtime.Dispatcher, event slackEvent) {
+	switch {
+	case event.Type == "app_mention":
+	case event.Type == "message" && event.ChannelType == "im":
+	default:
+		return
+	}
+	if !event.fromPerson() {
+		return
+	}
+	logging.Logger().Info(
+		"slack inbound message",
+		"user_id", event.User,
+		"channel", event.Channel,
+		"text", messagePreview(event.Text, 100),
+	)
+	if !s.isAllowedUser(event.User) {
+		return
+	}
+
+	text := strings.TrimSpace(slackMention.ReplaceAllStringFunc(event.Text, func(mention string) string {
+		if mention == "<@"+s.botUserID+">" {
+			return ""
+		}
+		return mention
+	}))
+	if text == "" {
+		return
+	}
+	// Mentions in a channel are answered in a thread to keep the channel
+	// readable; direct messages stay flat unless the user started a thread.
+	threadTS := event.ThreadTS
+	if threadTS == "" && event.ChannelType != "im" {
+		threadTS = event.TS
+	}
+	writer := &slackWriter{listener: s, userID: event.User, channel: event.Channel, threadTS: threadTS}
+	if err := dispatcher.Enqueue(ctx, &runtime.Message{Text: text, UserID: event.User}, writer); err != nil {
+		logging.Logger().Warn("slack enqueue failed", "user_id", event.User, "err", err)
+	}
+}
+
+// RequestApproval posts Approve/Deny buttons in the thread of the message
+// being handled and waits for the same user to press one.
+func (s *SlackListener) RequestApproval(ctx context.Context, req approval.ApprovalRequest) (approval.ApprovalDecision, error) {
+	if ctx.Err() != nil {
+		return approval.Denied, nil
+	}
+	target, ok := s.activeTargetSnapshot()
+	if !ok {
+		return approval.Denied, errors.New("slack approval target is unavailable")
+	}
+	token, err := generateApprovalToken()
+	if err != nil {
+		return approval.Denied, fmt.Errorf("generate approval token: %w", err)
+	}
+
+	prompt := approvalPrompt(req)
+	body := map[string]any{
+		"channel": target.channel,
+		"text":    prompt,
+		"blocks":  slackApprovalBlocks(prompt, token),
+	}
+	if target.threadTS != "" {
+		body["thread_ts"] = target.threadTS
+	}
+	var posted struct {
+		TS string `json:"ts"`
+	}
+	if err := s.api.call(ctx, s.botToken, "chat.postMessage", body, &posted); err != nil {
+		return approval.Denied, fmt.Errorf("send approval prompt: %w", err)
+	}
+
+	pending := slackPendingApproval{
+		tool:     req.Tool,
+		prompt:   prompt,
+		userID:   target.userID,
+		channel:  target.channel,
+		ts:       posted.TS,
+		response: make(chan approval.ApprovalDecision, 1),
+	}
+	s.approvalMu.Lock()
+	s.pendingApprovals[token] = pending
+	s.approvalMu.Unlock()
+	defer s.takePendingApproval(token)
+
+	var expired <-chan time.Time
+	if s.ApprovalTimeout > 0 {
+		timer := time.NewTimer(s.ApprovalTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case decision := <-pending.response:
+		return decision, nil
+	case <-expired:
+		if _, ok := s.takePendingApproval(token); ok {
+			s.closeApprovalPrompt(context.Background(), pending, fmt.Sprintf("⌛ Expired: no answer within %s", s.ApprovalTimeout))
+		}
+		return approval.Denied, fmt.Errorf("approval for %s timed out: the user did not answer within %s", req.Tool, s.ApprovalTimeout)
+	case <-ctx.Done():
+		if _, ok := s.takePendingApproval(token); ok {
+			s.closeApprovalPrompt(context.Background(), pending, "⌛ Expired: the request was cancelled")
+		}
+		return approval.Denied, nil
+	}
+}
+
+// ApproverName names the Slack user who answers approval prompts.
+func (s *SlackListener) ApproverName() string {
+	target, ok := s.activeTargetSnapshot()
+	if !ok {
+		return SlackChannel
+	}
+	return "slack user " + target.userID
+}
+
+func slackApprovalBlocks(prompt, token string) []any {
+	button := func(text, actionID, style string) map[string]any {
+		return map[string]any{
+			"type":      "button",
+			"text":      map[string]any{"type": "plain_text", "text": text},
+			"action_id": actionID,
+			"value":     token,
+			"style":     style,
+		}
+	}
+	return []any{
+		map[string]any{"type": "section", "text": map[string]any{"type": "plain_text", "text": prompt}},
+		map[string]any{"type": "actions", "elements": []any{
+			button("✅ Approve", slackApproveAction, "primary"),
+			button("❌ Deny", slackDenyAction, "danger"),
+		}},
+	}
+}
+
+// slackBlockActions is the part of a block_actions payload NeoClaw uses.
+type slackBlockActions struct {
+	Type string `json:"type"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// handleInteraction answers an approval prompt. Presses by anyone other
+// than the user who was asked are ignored.
+func (s *SlackListener) handleInteraction(ctx context.Context, payload json.RawMessage) {
+	var interaction slackBlockActions
+	if err := json.Unmarshal(payload, &interaction); err != nil || interaction.Type != "block_actions" || len(interaction.Actions) == 0 {
+		return
+	}
+	action := interaction.Actions[0]
+	decision := approval.Denied
+	status := "❌ Denied"
+	switch action.ActionID {
+	case slackApproveAction:
+		decision = approval.Approved
+		status = "✅ Approved"
+	case slackDenyAction:
+	default:
+		return
+	}
+
+	s.approvalMu.Lock()
+	pending, ok := s.pendingApprovals[action.Value]
+	if !ok || pending.userID != interaction.User.ID {
+		s.approvalMu.Unlock()
+		return
+	}
+	delete(s.pendingApprovals, action.Value)
+	s.approvalMu.Unlock()
+
+	s.closeApprovalPrompt(ctx, pending, status)
+	select {
+	case pending.response <- decision:
+	default:
+	}
+}
+
+// closeApprovalPrompt replaces the buttons with status so no stale prompt
+// is left in the thread.
+func (s *SlackListener) closeApprovalPrompt(ctx context.Context, pending slackPendingApproval, status string) {
+	if pending.ts == "" {
+		return
+	}
+	text := pending.prompt + "\n\n" + status
+	if err := s.api.call(ctx, s.botToken, "chat.update", map[string]any{
+		"channel": pending.channel,
+		"ts":      pending.ts,
+		"text":    text,
+		"blocks": []any{
+			map[string]any{"type": "section", "text": map[string]any{"type": "plain_text", "text": text}},
+		},
+	}, nil); err != nil {
+		logging.Logger().Warn("failed to close slack approval prompt", "tool", pending.tool, "err", err)
+	}
+}
+
+func (s *SlackListener) takePendingApproval(token string) (slackPendingApproval, bool) {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	pending, ok := s.pendingApprovals[token]
+	delete(s.pendingApprovals, token)
+	return pending, ok
+}
+
+func (s *SlackListener) setActiveTarget(target slackTarget) {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	s.activeTarget = &target
+}
+
+func (s *SlackListener) clearActiveTarget() {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	s.activeTarget = nil
+}
+
+func (s *SlackListener) activeTargetSnapshot() (slackTarget, bool) {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	if s.activeTarget == nil {
+		return slackTarget{}, false
+	}
+	return *s.activeTarget, true
+}
+
+// postMessage posts text, with credentials filtered, to channel. An empty
+// threadTS posts at the top level.
+func (s *SlackListener) postMessage(ctx context.Context, channel, threadTS, text string) error {
+	text, found := redact.FilterSecrets(text, s.OutboundSecrets)
+	if len(found) > 0 {
+		logging.Logger().Warn("outbound slack message contained credentials", "channel", channel, "kinds", strings.Join(found, ", "), "mode", s.OutboundSecrets)
+	}
+	body := map[string]any{"channel": channel, "text": text}
+	if threadTS != "" {
+		body["thread_ts"] = threadTS
+	}
+	return s.api.call(ctx, s.botToken, "chat.postMessage", body, nil)
+}
+
+// Send delivers a channel message to the thread of the current request.
+func (s *SlackListener) Send(ctx context.Context, message string) error {
+	target, ok := s.activeTargetSnapshot()
+	if !ok {
+		return errors.New("slack chat target is unavailable")
+	}
+	return s.postMessage(ctx, target.channel, target.threadTS, message)
+}
+
+// CurrentChannelID returns the scheduler channel key of the user whose
+// request is being handled.
+func (s *SlackListener) CurrentChannelID() string {
+	target, ok := s.activeTargetSnapshot()
+	if !ok {
+		return ""
+	}
+	return s.ChannelKey(target.userID)
+}
+
+type slackWriter struct {
+	listener *SlackListener
+	userID   string
+	channel  string
+	threadTS string
+}
+
+func (w *slackWriter) WriteMessage(ctx context.Context, text string) error {
+	if w == nil || w.listener == nil {
+		return errors.New("slack sender is not configured")
+	}
+	return w.listener.postMessage(ctx, w.channel, w.threadTS, text)
+}
+
+type slackChannelWriter struct {
+	listener *SlackListener
+	userID   string
+}
+
+func (w slackChannelWriter) Write(p []byte) (int, error) {
+	text := strings.TrimSpace(string(p))
+	if text == "" {
+		return len(p), nil
+	}
+	// A user ID as the channel posts in the user's direct messages with
+	// the bot.
+	if err := w.listener.postMessage(context.Background(), w.userID, "", text); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ChannelWriter returns an io.Writer that delivers scheduler messages to a
+// Slack user's direct messages.
+func (s *SlackListener) ChannelWriter(userID string) io.Writer {
+	return slackChannelWriter{listener: s, userID: userID}
+}
+
+type slackApprovalHandler struct {
+	listener *SlackListener
+	handler  runtime.Handler
+}
+
+func (h *slackApprovalHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	if writer, ok := w.(*slackWriter); ok {
+		h.listener.setActiveTarget(slackTarget{userID: writer.userID, channel: writer.channel, threadTS: writer.threadTS})
+		defer h.listener.clearActiveTarget()
+	}
+	return h.handler.HandleMessage(ctx, w, msg)
+}
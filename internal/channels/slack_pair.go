@@ -0,0 +1,145 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+// SlackPairSession represents one active Slack pairing session.
+type SlackPairSession struct {
+	api              slackAPI
+	botToken         string
+	botUsername      string
+	channel          string
+	expectedCode     string
+	userID           string
+	username         string
+	name             string
+	allowedUsersPath string
+}
+
+// BeginSlackPairing connects over Socket Mode and waits for the first direct
+// message to the bot. The sender gets a code to enter in the terminal and
+// is added to the allowlist at allowedUsersPath once it matches.
+func BeginSlackPairing(ctx context.Context, appToken, botToken, allowedUsersPath string) (*SlackPairSession, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(appToken) == "" || strings.TrimSpace(botToken) == "" {
+		return nil, errors.New("slack app_token and token are required")
+	}
+	return beginSlackPairing(ctx, slackAPI{}, appToken, botToken, allowedUsersPath)
+}
+
+func beginSlackPairing(ctx context.Context, api slackAPI, appToken, botToken, allowedUsersPath string) (*SlackPairSession, error) {
+	var identity struct {
+		User string `json:"user"`
+	}
+	if err := api.call(ctx, botToken, "auth.test", nil, &identity); err != nil {
+		return nil, fmt.Errorf("check slack bot token: %w", err)
+	}
+	logging.Logger().Info(fmt.Sprintf("Connected to Slack as @%s", identity.User))
+
+	firstInbound := make(chan slackEvent, 1)
+	go runSocketMode(ctx, api, appToken, func(_ context.Context, envelope slackEnvelope) {
+		if envelope.Type != "events_api" {
+			return
+		}
+		event, err := decodeSlackEvent(envelope)
+		if err != nil || event.Type != "message" || event.ChannelType != "im" || !event.fromPerson() {
+			return
+		}
+		select {
+		case firstInbound <- event:
+		default:
+		}
+	})
+
+	var inbound slackEvent
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case inbound = <-firstInbound:
+	}
+
+	code, err := generatePairCode()
+	if err != nil {
+		return nil, fmt.Errorf("generate pairing code: %w", err)
+	}
+	session := &SlackPairSession{
+		api:              api,
+		botToken:         botToken,
+		botUsername:      identity.User,
+		channel:          inbound.Channel,
+		expectedCode:     code,
+		userID:           inbound.User,
+		allowedUsersPath: allowedUsersPath,
+	}
+	if err := session.post(ctx, fmt.Sprintf("Pairing mode active. Your code is: %s - enter this in your terminal.", code)); err != nil {
+		return nil, fmt.Errorf("send pairing code: %w", err)
+	}
+
+	// The name is a convenience for the allowlist; without the users:read
+	// scope it stays empty.
+	var info struct {
+		User struct {
+			Name     string `json:"name"`
+			RealName string `json:"real_name"`
+		} `json:"user"`
+	}
+	if err := api.call(ctx, botToken, "users.info", map[string]any{"user": inbound.User}, &info); err != nil {
+		logging.Logger().Debug("could not look up slack user name", "user_id", inbound.User, "err", err)
+	}
+	session.username = info.User.Name
+	session.name = info.User.RealName
+	return session, nil
+}
+
+func (s *SlackPairSession) post(ctx context.Context, text string) error {
+	return s.api.call(ctx, s.botToken, "chat.postMessage", map[string]any{"channel": s.channel, "text": text}, nil)
+}
+
+// BotUsername returns the connected bot's user name.
+func (s *SlackPairSession) BotUsername() string {
+	return s.botUsername
+}
+
+// UserID returns the paired user's Slack member ID.
+func (s *SlackPairSession) UserID() string {
+	return s.userID
+}
+
+// Username returns the paired user's Slack handle, if it could be read.
+func (s *SlackPairSession) Username() string {
+	return s.username
+}
+
+// Name returns the paired user's display name, if it could be read.
+func (s *SlackPairSession) Name() string {
+	return s.name
+}
+
+// SubmitCode validates an entered code and persists the paired Slack user on success.
+func (s *SlackPairSession) SubmitCode(ctx context.Context, entered string) error {
+	if strings.TrimSpace(entered) != s.expectedCode {
+		return ErrWrongCode
+	}
+	if err := s.post(ctx, "You are now authorized. Restart the bot server to activate."); err != nil {
+		return fmt.Errorf("send pairing confirmation: %w", err)
+	}
+	if err := approval.AddUser(s.allowedUsersPath, approval.User{
+		ID:       s.userID,
+		Channel:  SlackChannel,
+		Username: s.username,
+		Name:     s.name,
+	}); err != nil {
+		return fmt.Errorf("persist paired user: %w", err)
+	}
+	logging.Logger().Info("slack user paired", "user_id", s.userID, "username", s.username, "channel", SlackChannel)
+	return nil
+}
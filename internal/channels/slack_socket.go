@@ -0,0 +1,206 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+const (
+	defaultSlackAPIURL  = "https://slack.com/api/"
+	slackRequestTimeout = 30 * time.Second
+	// maxSlackEnvelope is far above any real event; Socket Mode messages
+	// carry one event each.
+	maxSlackEnvelope = 1 << 20
+	// slackReconnectMax caps the wait between reconnect attempts.
+	slackReconnectMax = 30 * time.Second
+)
+
+// slackAPI calls Slack Web API methods with JSON bodies.
+type slackAPI struct {
+	baseURL string
+	client  *http.Client
+}
+
+// slackResponse is the envelope every Web API response shares.
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// call posts body to method with token and decodes the reply into out.
+func (a slackAPI) call(ctx context.Context, token, method string, body, out any) error {
+	var payload io.Reader = http.NoBody
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode slack %s: %w", method, err)
+		}
+		payload = bytes.NewReader(encoded)
+	}
+	baseURL := a.baseURL
+	if baseURL == "" {
+		baseURL = defaultSlackAPIURL
+	}
+	ctx, cancel := context.WithTimeout(ctx, slackRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+method, payload)
+	if err != nil {
+		return fmt.Errorf("build slack %s request: %w", method, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	client := a.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxSlackEnvelope))
+	if err != nil {
+		return fmt.Errorf("read slack %s response: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack %s: HTTP %d", method, resp.StatusCode)
+	}
+	var status slackResponse
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return fmt.Errorf("decode slack %s response: %w", method, err)
+	}
+	if !status.OK {
+		return fmt.Errorf("slack %s: %s", method, status.Error)
+	}
+	if out != nil {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return fmt.Errorf("decode slack %s response: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// slackEnvelope is one Socket Mode message. Envelopes with an ID must be
+// acknowledged within three seconds or Slack retries them.
+type slackEnvelope struct {
+	EnvelopeID string          `json:"envelope_id"`
+	Type       string          `json:"type"`
+	Reason     string          `json:"reason"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// runSocketMode keeps a Socket Mode connection open until ctx is done and
+// passes every event to handle after acknowledging it. Slack asks clients
+// to reconnect regularly, and dropped connections are retried with
+// backoff.
+func runSocketMode(ctx context.Context, api slackAPI, appToken string, handle func(context.Context, slackEnvelope)) error {
+	wait := time.Second
+	for {
+		connected, err := serveSocketConnection(ctx, api, appToken, handle)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			wait = time.Second
+		}
+		if err != nil {
+			logging.Logger().Warn("slack socket mode connection lost; reconnecting", "err", err, "wait", wait.String())
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(wait):
+			}
+			wait = min(wait*2, slackReconnectMax)
+		}
+	}
+}
+
+// serveSocketConnection handles one connection until Slack asks for a
+// reconnect (nil error) or it fails. connected reports whether Slack said
+// hello, so a working connection resets the backoff.
+func serveSocketConnection(ctx context.Context, api slackAPI, appToken string, handle func(context.Context, slackEnvelope)) (connected bool, err error) {
+	var opened struct {
+		URL string `json:"url"`
+	}
+	if err := api.call(ctx, appToken, "apps.connections.open", nil, &opened); err != nil {
+		return false, err
+	}
+	conn, _, err := websocket.Dial(ctx, opened.URL, &websocket.DialOptions{HTTPClient: api.client})
+	if err != nil {
+		return false, fmt.Errorf("dial slack socket: %w", err)
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(maxSlackEnvelope)
+
+	for {
+		_, raw, err := conn.Read(ctx)
+		if err != nil {
+			return connected, err
+		}
+		var envelope slackEnvelope
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			logging.Logger().Warn("ignoring malformed slack socket message", "err", err)
+			continue
+		}
+		switch envelope.Type {
+		case "hello":
+			connected = true
+			continue
+		case "disconnect":
+			logging.Logger().Debug("slack asked to reconnect", "reason", envelope.Reason)
+			conn.Close(websocket.StatusNormalClosure, "")
+			return connected, nil
+		}
+		if envelope.EnvelopeID != "" {
+			ack, _ := json.Marshal(map[string]string{"envelope_id": envelope.EnvelopeID})
+			if err := conn.Write(ctx, websocket.MessageText, ack); err != nil {
+				return connected, fmt.Errorf("acknowledge slack event: %w", err)
+			}
+		}
+		handle(ctx, envelope)
+	}
+}
+
+// slackEvent is the part of an Events API message event NeoClaw uses.
+type slackEvent struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype"`
+	User        string `json:"user"`
+	BotID       string `json:"bot_id"`
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channel_type"`
+	Text        string `json:"text"`
+	TS          string `json:"ts"`
+	ThreadTS    string `json:"thread_ts"`
+}
+
+// fromPerson reports whether the event is a message a person wrote, as
+// opposed to an edit, a join notice, or a bot post such as NeoClaw's own.
+func (e slackEvent) fromPerson() bool {
+	return e.Subtype == "" && e.BotID == "" && strings.TrimSpace(e.User) != ""
+}
+
+// decodeSlackEvent returns the event inside an events_api envelope.
+func decodeSlackEvent(envelope slackEnvelope) (slackEvent, error) {
+	var callback struct {
+		Event slackEvent `json:"event"`
+	}
+	if err := json.Unmarshal(envelope.Payload, &callback); err != nil {
+		return slackEvent{}, err
+	}
+	if callback.Event.Type == "" {
+		return slackEvent{}, errors.New("event is missing")
+	}
+	return callback.Event, nil
+}
@@ -0,0 +1,232 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+)
+
+// fakeSlack serves the Web API methods the listener uses and one Socket
+// Mode connection driven by the test.
+type fakeSlack struct {
+	server *httptest.Server
+	// toClient is sent over the socket; acks come back on acks.
+	toClient chan string
+	acks     chan string
+	// calls receives every Web API call as method plus JSON body.
+	calls chan slackCall
+}
+
+type slackCall struct {
+	method string
+	body   map[string]any
+}
+
+func newFakeSlack(t *testing.T) *fakeSlack {
+	f := &fakeSlack{
+		toClient: make(chan string, 10),
+		acks:     make(chan string, 10),
+		calls:    make(chan slackCall, 20),
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/socket" {
+			f.serveSocket(w, r)
+			return
+		}
+		method := strings.TrimPrefix(r.URL.Path, "/api/")
+		var body map[string]any
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+		if method != "apps.connections.open" {
+			f.calls <- slackCall{method: method, body: body}
+		}
+		switch method {
+		case "auth.test":
+			io.WriteString(w, `{"ok":true,"user_id":"UBOT","user":"neoclaw","team":"Acme"}`)
+		case "apps.connections.open":
+			if r.Header.Get("Authorization") != "Bearer xapp-test" {
+				io.WriteString(w, `{"ok":false,"error":"invalid_auth"}`)
+				return
+			}
+			io.WriteString(w, `{"ok":true,"url":"ws`+strings.TrimPrefix(f.server.URL, "http")+`/socket"}`)
+		case "chat.postMessage":
+			io.WriteString(w, `{"ok":true,"ts":"2.0"}`)
+		default:
+			io.WriteString(w, `{"ok":true}`)
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeSlack) serveSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+	ctx := r.Context()
+	go func() {
+		for {
+			_, raw, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			f.acks <- string(raw)
+		}
+	}()
+	conn.Write(ctx, websocket.MessageText, []byte(`{"type":"hello"}`))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-f.toClient:
+			conn.Write(ctx, websocket.MessageText, []byte(msg))
+		}
+	}
+}
+
+func (f *fakeSlack) waitForCall(t *testing.T, method string) slackCall {
+	t.Helper()
+	for {
+		select {
+		case call := <-f.calls:
+			if call.method == method {
+				return call
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected a %s call", method)
+		}
+	}
+}
+
+// approvingHandler asks for approval and replies with the decision.
+type approvingHandler struct {
+	approver approval.Approver
+	texts    chan string
+}
+
+func (h *approvingHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	h.texts <- msg.Text
+	decision, err := h.approver.RequestApproval(ctx, approval.ApprovalRequest{Tool: "run_command", Description: "Run: make test"})
+	if err != nil {
+		return err
+	}
+	if decision == approval.Approved {
+		return w.WriteMessage(ctx, "tests passed")
+	}
+	return w.WriteMessage(ctx, "skipped")
+}
+
+func TestSlackListener_RepliesInThreadAfterButtonApproval(t *testing.T) {
+	slack := newFakeSlack(t)
+	listener := NewSlack("xapp-test", "xoxb-test", writeAllowedUsersFile(t, `{
+  "users": [
+    {"id":"U111","channel":"slack","username":"alice","name":"Alice","added_at":"2026-02-19T14:30:00Z"}
+  ]
+}
+`))
+	listener.api = slackAPI{baseURL: slack.server.URL + "/api/", client: slack.server.Client()}
+	handler := &approvingHandler{approver: listener, texts: make(chan string, 2)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- listener.Listen(ctx, handler) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	slack.waitForCall(t, "auth.test")
+
+	// Someone else's mention is ignored; Alice's is handled once.
+	slack.toClient <- `{"envelope_id":"e1","type":"events_api","payload":{"event":{"type":"app_mention","user":"U999","channel":"C1","text":"<@UBOT> hi","ts":"1.0"}}}`
+	slack.toClient <- `{"envelope_id":"e2","type":"events_api","payload":{"event":{"type":"app_mention","user":"U111","channel":"C1","text":"<@UBOT> run the tests","ts":"1.5"}}}`
+	select {
+	case text := <-handler.texts:
+		if text != "run the tests" {
+			t.Fatalf("expected the mention to be removed, got %q", text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not dispatched")
+	}
+	for _, id := range []string{"e1", "e2"} {
+		select {
+		case ack := <-slack.acks:
+			if !strings.Contains(ack, id) {
+				t.Fatalf("expected ack for %s, got %s", id, ack)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected ack for %s", id)
+		}
+	}
+
+	prompt := slack.waitForCall(t, "chat.postMessage")
+	if prompt.body["thread_ts"] != "1.5" || prompt.body["channel"] != "C1" {
+		t.Fatalf("expected the prompt in the message thread, got %#v", prompt.body)
+	}
+	blocks, _ := json.Marshal(prompt.body["blocks"])
+	var parsed []struct {
+		Elements []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"elements"`
+	}
+	json.Unmarshal(blocks, &parsed)
+	if len(parsed) != 2 || len(parsed[1].Elements) != 2 || parsed[1].Elements[0].ActionID != slackApproveAction {
+		t.Fatalf("expected approve/deny buttons, got %s", blocks)
+	}
+	token := parsed[1].Elements[0].Value
+	// The fake sees the post before the listener has the reply, so wait
+	// until the prompt is registered before pressing.
+	for deadline := time.Now().Add(2 * time.Second); ; {
+		listener.approvalMu.Lock()
+		_, registered := listener.pendingApprovals[token]
+		listener.approvalMu.Unlock()
+		if registered {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("approval prompt was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Only the user who was asked can answer.
+	slack.toClient <- `{"envelope_id":"e3","type":"interactive","payload":{"type":"block_actions","user":{"id":"U999"},"actions":[{"action_id":"approval_approve","value":"` + token + `"}]}}`
+	slack.toClient <- `{"envelope_id":"e4","type":"interactive","payload":{"type":"block_actions","user":{"id":"U111"},"actions":[{"action_id":"approval_approve","value":"` + token + `"}]}}`
+
+	update := slack.waitForCall(t, "chat.update")
+	if text, _ := update.body["text"].(string); !strings.HasSuffix(text, "✅ Approved") || update.body["ts"] != "2.0" {
+		t.Fatalf("expected the prompt to be closed, got %#v", update.body)
+	}
+	reply := slack.waitForCall(t, "chat.postMessage")
+	if reply.body["text"] != "tests passed" || reply.body["thread_ts"] != "1.5" {
+		t.Fatalf("expected the reply in the thread, got %#v", reply.body)
+	}
+}
+
+func TestSlackChannelWriterPostsToUserDirectMessages(t *testing.T) {
+	slack := newFakeSlack(t)
+	listener := NewSlack("xapp-test", "xoxb-test", "")
+	listener.api = slackAPI{baseURL: slack.server.URL + "/api/", client: slack.server.Client()}
+
+	if _, err := listener.ChannelWriter("U111").Write([]byte("Daily briefing\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	call := slack.waitForCall(t, "chat.postMessage")
+	if call.body["channel"] != "U111" || call.body["text"] != "Daily briefing" || call.body["thread_ts"] != nil {
+		t.Fatalf("unexpected post %#v", call.body)
+	}
+	if key := listener.ChannelKey("U111"); key != "slack-U111" {
+		t.Fatalf("unexpected channel key %q", key)
+	}
+}
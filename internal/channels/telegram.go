@@ -1,6 +1,7 @@
 package channels
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
@@ -9,16 +10,21 @@ import (
 	"html"
 	"io"
 	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"github.com/neoclaw-ai/neoclaw/internal/approval"
-	"github.com/neoclaw-ai/neoclaw/internal/config"
 	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/presence"
+	"github.com/neoclaw-ai/neoclaw/internal/redact"
 	"github.com/neoclaw-ai/neoclaw/internal/runtime"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
@@ -35,6 +41,14 @@ const (
 	telegramApprovalDenyPrefix    = "approval:no:"
 )
 
+// Bot API upload limits.
+const (
+	maxTelegramPhotoBytes    = 10 << 20
+	maxTelegramDocumentBytes = 50 << 20
+	maxTelegramCaptionChars  = 1024
+	maxTelegramMessageChars  = 4096
+)
+
 var telegramMarkdown = goldmark.New(
 	goldmark.WithExtensions(
 		extension.Strikethrough,
@@ -54,6 +68,7 @@ type TelegramPairSession struct {
 	chatID           int64
 	expectedCode     string
 	user             telegramPairUser
+	role             string
 	allowedUsersPath string
 }
 
@@ -72,38 +87,78 @@ type telegramApprovalTarget struct {
 	userID   string
 	username string
 	chatID   int64
+	// text is the message being handled, saved with its approval prompts.
+	text string
+	// resumeKey is an approval.RequestKey already approved after a restart.
+	resumeKey string
 }
 
 type telegramPendingApproval struct {
-	userID   string
-	chatID   int64
+	pendingApprovalRecord
+	// response receives the decision. It is nil for prompts recovered after
+	// a restart, whose turn no longer exists.
 	response chan approval.ApprovalDecision
 }
 
 type telegramSendMessageFunc func(context.Context, *bot.SendMessageParams) (*models.Message, error)
 type telegramAnswerCallbackQueryFunc func(context.Context, *bot.AnswerCallbackQueryParams) (bool, error)
 type telegramEditMessageReplyMarkupFunc func(context.Context, *bot.EditMessageReplyMarkupParams) (*models.Message, error)
+type telegramEditMessageTextFunc func(context.Context, *bot.EditMessageTextParams) (*models.Message, error)
 type telegramSendChatActionFunc func(context.Context, *bot.SendChatActionParams) (bool, error)
+type telegramSendDocumentFunc func(context.Context, *bot.SendDocumentParams) (*models.Message, error)
+type telegramSendPhotoFunc func(context.Context, *bot.SendPhotoParams) (*models.Message, error)
+type telegramDeleteMessageFunc func(context.Context, *bot.DeleteMessageParams) (bool, error)
 
 // TelegramListener receives Telegram updates and dispatches authorized messages.
 type TelegramListener struct {
+	Base
+
 	token            string
 	allowedUsersPath string
+	// channelName is the config entry of this bot, e.g. "telegram_work";
+	// empty means "telegram". It prefixes scheduler channel keys.
+	channelName string
 
 	allowedTelegramUsers map[string]struct{}
+	// observerTelegramUsers maps read-only observers to their private chat IDs.
+	observerTelegramUsers map[string]int64
 
 	sendMessage            telegramSendMessageFunc
 	answerCallbackQuery    telegramAnswerCallbackQueryFunc
 	editMessageReplyMarkup telegramEditMessageReplyMarkupFunc
+	editMessageText        telegramEditMessageTextFunc
 	sendChatAction         telegramSendChatActionFunc
+	sendDocument           telegramSendDocumentFunc
+	sendPhoto              telegramSendPhotoFunc
+	deleteMessage          telegramDeleteMessageFunc
+	answerInlineQuery      telegramAnswerInlineQueryFunc
 
 	approvalMu           sync.Mutex
 	activeApprovalTarget *telegramApprovalTarget
 	pendingApprovals     map[string]telegramPendingApproval
+	pendingApprovalsPath string
+	// webhook replaces long polling when its URL is set.
+	webhook TelegramWebhook
+	// apiURL overrides the Bot API address, for tests.
+	apiURL string
+	// presence, when set, records which bot each user last wrote to.
+	presence *presence.Store
+
+	// inlineAnswerer, when set, answers inline queries; inlineQueries holds
+	// each user's query still waiting for its answer.
+	inlineAnswerer TelegramInlineAnswerer
+	inlineDebounce time.Duration
+	inlineMu       sync.Mutex
+	inlineQueries  map[string]*telegramInlineQuery
+
+	// listenCtx and dispatcher are set while Listen runs.
+	listenCtx  context.Context
+	dispatcher *runtime.Dispatcher
 }
 
-// BeginTelegramPairing starts Telegram pairing and waits for the first inbound user message.
-func BeginTelegramPairing(ctx context.Context, token string) (*TelegramPairSession, error) {
+// BeginTelegramPairing starts Telegram pairing and waits for the first inbound
+// user message. The user is added to the allowlist at allowedUsersPath.
+func BeginTelegramPairing(ctx context.Context, token, allowedUsersPath string) (*TelegramPairSession, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -126,6 +181,7 @@ func BeginTelegramPairing(ctx context.Context, token string) (*TelegramPairSessi
 	}
 	logging.Logger().Info(fmt.Sprintf("Connected to Telegram Bot @%s", strings.TrimSpace(me.Username)))
 
+	deleteStaleWebhook(ctx, b)
 	go b.Start(ctx)
 
 	var inbound telegramInboundMessage
@@ -135,7 +191,7 @@ func BeginTelegramPairing(ctx context.Context, token string) (*TelegramPairSessi
 	case inbound = <-firstInbound:
 	}
 
-	code, err := generateTelegramPairCode()
+	code, err := generatePairCode()
 	if err != nil {
 		return nil, fmt.Errorf("generate pairing code: %w", err)
 	}
@@ -148,11 +204,6 @@ func BeginTelegramPairing(ctx context.Context, token string) (*TelegramPairSessi
 		return nil, fmt.Errorf("send pairing code: %w", err)
 	}
 
-	cfg, err := config.Load()
-	if err != nil {
-		return nil, fmt.Errorf("load config for users store: %w", err)
-	}
-
 	return &TelegramPairSession{
 		bot:          b,
 		botUsername:  strings.TrimSpace(me.Username),
@@ -163,7 +214,7 @@ func BeginTelegramPairing(ctx context.Context, token string) (*TelegramPairSessi
 			username: inbound.username,
 			name:     inbound.name,
 		},
-		allowedUsersPath: cfg.AllowedUsersPath(),
+		allowedUsersPath: allowedUsersPath,
 	}, nil
 }
 
@@ -204,15 +255,25 @@ func (s *TelegramPairSession) Name() string {
 	return s.user.name
 }
 
+// SetRole sets the role stored for the user on a successful pairing, such as
+// approval.RoleObserver. The default empty role grants full access.
+func (s *TelegramPairSession) SetRole(role string) {
+	s.role = role
+}
+
 // SubmitCode validates an entered code and persists the paired Telegram user on success.
 func (s *TelegramPairSession) SubmitCode(ctx context.Context, entered string) error {
 	if strings.TrimSpace(entered) != s.expectedCode {
 		return ErrWrongCode
 	}
 
+	confirmation := "You are now authorized. Restart the bot server to activate."
+	if s.role == approval.RoleObserver {
+		confirmation = "You are now paired as an observer. Restart the bot server to start following the conversation."
+	}
 	if _, err := s.bot.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID: s.chatID,
-		Text:   "You are now authorized. Restart the bot server to activate.",
+		Text:   confirmation,
 	}); err != nil {
 		return fmt.Errorf("send pairing confirmation: %w", err)
 	}
@@ -222,6 +283,7 @@ func (s *TelegramPairSession) SubmitCode(ctx context.Context, entered string) er
 		Channel:  "telegram",
 		Username: s.user.username,
 		Name:     s.user.name,
+		Role:     s.role,
 	}); err != nil {
 		return fmt.Errorf("persist paired user: %w", err)
 	}
@@ -230,12 +292,13 @@ func (s *TelegramPairSession) SubmitCode(ctx context.Context, entered string) er
 		"user_id", s.user.id,
 		"username", s.user.username,
 		"channel", "telegram",
+		"role", s.role,
 	)
 
 	return nil
 }
 
-func generateTelegramPairCode() (string, error) {
+func generatePairCode() (string, error) {
 	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
 	if err != nil {
 		return "", err
@@ -249,10 +312,34 @@ var _ approval.Approver = (*TelegramListener)(nil)
 // NewTelegram creates a Telegram listener over one bot token and allowlist path.
 func NewTelegram(token, allowedUsersPath string) *TelegramListener {
 	return &TelegramListener{
+		Base:             NewBase(),
 		token:            token,
 		allowedUsersPath: allowedUsersPath,
 		pendingApprovals: make(map[string]telegramPendingApproval),
+		inlineDebounce:   defaultTelegramInlineDebounce,
+		inlineQueries:    make(map[string]*telegramInlineQuery),
+	}
+}
+
+// ConfigureChannelName names the config entry this bot comes from, so
+// several bots in one process get distinct scheduler channel keys.
+func (t *TelegramListener) ConfigureChannelName(name string) {
+	t.channelName = name
+}
+
+// ChannelKey returns the scheduler channel key for one chat of this bot.
+func (t *TelegramListener) ChannelKey(chatID int64) string {
+	name := t.channelName
+	if name == "" {
+		name = "telegram"
 	}
+	return fmt.Sprintf("%s-%d", name, chatID)
+}
+
+// ConfigurePresence records each user's private chat with this bot as
+// their latest channel whenever they send a message.
+func (t *TelegramListener) ConfigurePresence(store *presence.Store) {
+	t.presence = store
 }
 
 // Listen starts long-polling Telegram and dispatches authorized messages.
@@ -271,15 +358,28 @@ func (t *TelegramListener) Listen(ctx context.Context, handler runtime.Handler)
 	}
 
 	dispatchCtx, cancelDispatch := context.WithCancel(ctx)
-	dispatcher := runtime.NewDispatcher(&telegramApprovalHandler{listener: t, handler: handler}, defaultDispatchQueue)
+	dispatcher := runtime.NewDispatcher(&telegramApprovalHandler{listener: t, handler: handler}, t.QueueSize)
 	defaultHandler := func(updateCtx context.Context, _ *bot.Bot, update *models.Update) {
+		if update != nil && update.InlineQuery != nil {
+			t.handleInlineQuery(updateCtx, update.InlineQuery)
+			return
+		}
 		if update == nil || update.Message == nil || update.Message.From == nil {
 			return
 		}
 		t.handleInboundMessage(updateCtx, dispatcher, update.Message)
 	}
 
-	b, err := t.createTelegramBot(defaultHandler)
+	var webhookSecret string
+	if t.webhook.URL != "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			cancelDispatch()
+			return fmt.Errorf("generate webhook secret: %w", err)
+		}
+		webhookSecret = secret
+	}
+	b, err := t.createTelegramBot(defaultHandler, webhookSecret)
 	if err != nil {
 		cancelDispatch()
 		return fmt.Errorf("create telegram bot: %w", err)
@@ -295,7 +395,12 @@ func (t *TelegramListener) Listen(ctx context.Context, handler runtime.Handler)
 	t.sendMessage = b.SendMessage
 	t.answerCallbackQuery = b.AnswerCallbackQuery
 	t.editMessageReplyMarkup = b.EditMessageReplyMarkup
+	t.editMessageText = b.EditMessageText
 	t.sendChatAction = b.SendChatAction
+	t.sendDocument = b.SendDocument
+	t.sendPhoto = b.SendPhoto
+	t.deleteMessage = b.DeleteMessage
+	t.answerInlineQuery = b.AnswerInlineQuery
 
 	if err := dispatcher.Start(dispatchCtx); err != nil {
 		cancelDispatch()
@@ -305,14 +410,28 @@ func (t *TelegramListener) Listen(ctx context.Context, handler runtime.Handler)
 		cancelDispatch()
 		dispatcher.Wait()
 	}()
-
+	t.listenCtx = ctx
+	t.dispatcher = dispatcher
+	t.recoverPendingApprovals(ctx)
+
+	if t.webhook.URL != "" {
+		err := t.runWebhook(ctx, b, webhookSecret)
+		if err == nil || ctx.Err() != nil {
+			dispatcher.Stop()
+			return err
+		}
+		// A bot that cannot receive updates is worse than a slower one.
+		logging.Logger().Warn("telegram webhook unavailable; falling back to long polling", "err", err)
+	}
+	deleteStaleWebhook(ctx, b)
 	go b.Start(ctx)
 	<-ctx.Done()
 	dispatcher.Stop()
 	return nil
 }
 
-// RequestApproval prompts the active Telegram user with an inline Approve/Deny keyboard.
+// RequestApproval prompts the Telegram user whose message started the turn
+// in ctx with an inline Approve/Deny keyboard.
 func (t *TelegramListener) RequestApproval(ctx context.Context, req approval.ApprovalRequest) (approval.ApprovalDecision, error) {
 	if ctx == nil {
 		ctx = context.Background()
@@ -321,68 +440,85 @@ func (t *TelegramListener) RequestApproval(ctx context.Context, req approval.App
 		return approval.Denied, nil
 	}
 
-	target, ok := t.activeApprovalTargetSnapshot()
+	// Only the user whose message started the turn may answer for it.
+	target, ok := t.approvalTargetFrom(ctx)
 	if !ok {
 		return approval.Denied, errors.New("telegram approval target is unavailable")
 	}
 
-	token, err := generateTelegramApprovalToken()
+	// The user already approved this exact request before a restart.
+	if key, ok := approval.RequestKey(req); ok && t.consumeResumedApproval(ctx, key) {
+		return approval.Approved, nil
+	}
+
+	token, err := generateApprovalToken()
 	if err != nil {
 		return approval.Denied, fmt.Errorf("generate approval token: %w", err)
 	}
 
-	prompt := strings.TrimSpace(req.Description)
-	if prompt == "" {
-		prompt = fmt.Sprintf("Approve %s?", strings.TrimSpace(req.Tool))
-	}
-
-	message, err := t.sendTelegramMessage(ctx, &bot.SendMessageParams{
-		ChatID: target.chatID,
-		Text:   prompt,
-		ReplyMarkup: &models.InlineKeyboardMarkup{
-			InlineKeyboard: [][]models.InlineKeyboardButton{
-				{
-					{
-						Text:         "✅ Approve",
-						CallbackData: telegramApprovalApprovePrefix + token,
-					},
-					{
-						Text:         "❌ Deny",
-						CallbackData: telegramApprovalDenyPrefix + token,
-					},
-				},
-			},
-		},
-	})
+	prompt := approvalPrompt(req)
+	message, err := t.sendApprovalPrompt(ctx, target.chatID, prompt, token)
 	if err != nil {
 		return approval.Denied, fmt.Errorf("send approval prompt: %w", err)
 	}
+	t.mirrorToObservers(ctx, "Approval requested: "+prompt)
 
 	pending := telegramPendingApproval{
-		userID:   target.userID,
-		chatID:   target.chatID,
+		pendingApprovalRecord: pendingApprovalRecord{
+			Token:       token,
+			Tool:        req.Tool,
+			Description: req.Description,
+			Args:        req.Args,
+			UserID:      target.userID,
+			Username:    target.username,
+			ChatID:      target.chatID,
+			Text:        target.text,
+			RequestedAt: time.Now().UTC(),
+		},
 		response: make(chan approval.ApprovalDecision, 1),
 	}
+	if message != nil {
+		pending.MessageIDs = []int{message.ID}
+	}
 	t.storePendingApproval(token, pending)
-	defer t.deletePendingApproval(token)
+	// On shutdown the prompt stays saved so the next start asks again.
+	defer func() { t.deletePendingApproval(token, !t.stopping()) }()
+
+	var expired <-chan time.Time
+	if t.ApprovalTimeout > 0 {
+		timer := time.NewTimer(t.ApprovalTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
 
 	select {
 	case decision := <-pending.response:
 		return decision, nil
+	case <-expired:
+		if current, ok := t.pendingApproval(token); ok {
+			t.closeApprovalPrompts(context.Background(), current.pendingApprovalRecord, fmt.Sprintf("⌛ Expired: no answer within %s", t.ApprovalTimeout))
+		}
+		return approval.Denied, fmt.Errorf("approval for %s timed out: the user did not answer within %s", req.Tool, t.ApprovalTimeout)
 	case <-ctx.Done():
-		if message != nil {
-			if _, err := t.editTelegramReplyMarkup(context.Background(), &bot.EditMessageReplyMarkupParams{
-				ChatID:      target.chatID,
-				MessageID:   message.ID,
-				ReplyMarkup: nil,
-			}); err != nil {
-				logging.Logger().Warn("failed to clear approval keyboard", "chat_id", target.chatID, "message_id", message.ID, "err", err)
-			}
+		if current, ok := t.pendingApproval(token); ok && !t.stopping() {
+			t.closeApprovalPrompts(context.Background(), current.pendingApprovalRecord, "⌛ Expired: the request was cancelled")
 		}
 		return approval.Denied, nil
 	}
 }
 
+// ApproverName names the Telegram user who answers approval prompts.
+func (t *TelegramListener) ApproverName() string {
+	target, ok := t.activeApprovalTargetSnapshot()
+	if !ok {
+		return "telegram"
+	}
+	if target.username != "" {
+		return fmt.Sprintf("telegram user %s (@%s)", target.userID, target.username)
+	}
+	return "telegram user " + target.userID
+}
+
 func (t *TelegramListener) loadAllowedUsers() error {
 	usersFile, err := approval.LoadUsers(t.allowedUsersPath)
 	if err != nil {
@@ -390,16 +526,29 @@ func (t *TelegramListener) loadAllowedUsers() error {
 	}
 
 	allowed := make(map[string]struct{}, len(usersFile.Users))
+	observers := make(map[string]int64)
 	for _, user := range usersFile.Users {
 		if strings.EqualFold(strings.TrimSpace(user.Channel), "telegram") {
 			id := strings.TrimSpace(user.ID)
 			if id == "" {
 				continue
 			}
+			if user.IsObserver() {
+				// Private chat IDs equal user IDs, so observers can be
+				// messaged without having written first.
+				chatID, err := strconv.ParseInt(id, 10, 64)
+				if err != nil {
+					logging.Logger().Warn("skipping telegram observer with invalid user id", "user_id", id, "err", err)
+					continue
+				}
+				observers[id] = chatID
+				continue
+			}
 			allowed[id] = struct{}{}
 		}
 	}
 	t.allowedTelegramUsers = allowed
+	t.observerTelegramUsers = observers
 	return nil
 }
 
@@ -430,24 +579,29 @@ func (t *TelegramListener) handleApprovalCallback(
 	}
 
 	userID := strconv.FormatInt(callback.From.ID, 10)
-	if userID != pending.userID {
+	if userID != pending.UserID {
 		return
 	}
 
 	chatID, messageID, ok := callbackMessageLocation(callback)
-	if !ok || chatID != pending.chatID {
+	if !ok || chatID != pending.ChatID {
 		return
 	}
 
-	t.deletePendingApproval(token)
-	if _, err := t.editTelegramReplyMarkup(ctx, &bot.EditMessageReplyMarkupParams{
-		ChatID:      chatID,
-		MessageID:   messageID,
-		ReplyMarkup: nil,
-	}); err != nil {
-		logging.Logger().Warn("failed to clear approval keyboard", "chat_id", chatID, "message_id", messageID, "err", err)
+	t.deletePendingApproval(token, true)
+	if !slices.Contains(pending.MessageIDs, messageID) {
+		pending.MessageIDs = append(pending.MessageIDs, messageID)
+	}
+	status := "✅ Approved"
+	if decision == approval.Denied {
+		status = "❌ Denied"
 	}
+	t.closeApprovalPrompts(ctx, pending.pendingApprovalRecord, status)
 
+	if pending.response == nil {
+		t.resumeApproval(ctx, pending, decision)
+		return
+	}
 	select {
 	case pending.response <- decision:
 	default:
@@ -473,9 +627,30 @@ func (t *TelegramListener) handleInboundMessage(
 		"text", messagePreview(text, 100),
 	)
 
+	if _, ok := t.observerTelegramUsers[userID]; ok {
+		if err := t.sendChatMessage(ctx, msg.Chat.ID, "You are paired as an observer: you can follow the conversation here but cannot send messages or approve actions."); err != nil {
+			logging.Logger().Warn("failed to notify telegram observer", "user_id", userID, "err", err)
+		}
+		return
+	}
 	if !t.isAllowedUser(userID) {
 		return
 	}
+	if t.presence != nil && msg.Chat.ID == msg.From.ID {
+		if err := t.presence.Record(userID, t.ChannelKey(msg.Chat.ID), time.Now()); err != nil {
+			logging.Logger().Warn("failed to record telegram presence", "user_id", userID, "err", err)
+		}
+	}
+
+	trimmedText := strings.TrimSpace(text)
+	// Answered here rather than queued: the queue is blocked while a turn
+	// waits for approval, which is when the list is needed.
+	if strings.EqualFold(trimmedText, telegramApprovalsCommand) {
+		if err := t.sendPendingApprovalList(ctx, msg.Chat.ID, userID, time.Now()); err != nil {
+			logging.Logger().Warn("failed to list pending approvals", "user_id", userID, "err", err)
+		}
+		return
+	}
 
 	writer := &telegramWriter{
 		listener: t,
@@ -483,8 +658,7 @@ func (t *TelegramListener) handleInboundMessage(
 		userID:   userID,
 		username: username,
 	}
-	trimmedText := strings.TrimSpace(text)
-	if err := dispatcher.Enqueue(ctx, &runtime.Message{Text: trimmedText}, writer); err != nil {
+	if err := dispatcher.Enqueue(ctx, &runtime.Message{Text: trimmedText, UserID: userID}, writer); err != nil {
 		logging.Logger().Warn("telegram enqueue failed", "user_id", userID, "username", username, "err", err)
 	}
 }
@@ -502,13 +676,53 @@ type telegramWriter struct {
 	chatID   int64
 	userID   string
 	username string
+	// mirror copies replies and tool activity to observers. It is set for
+	// conversation turns but not for slash command output.
+	mirror bool
+	// resumeKey marks a message handled again after its approval was given
+	// across a restart; see telegramApprovalTarget.
+	resumeKey string
+	// stream is the preview the reply is being streamed into, if any.
+	stream telegramStream
 }
 
 func (w *telegramWriter) WriteMessage(ctx context.Context, text string) error {
 	if w == nil || w.listener == nil {
 		return errors.New("telegram sender is not configured")
 	}
-	return w.listener.sendFormattedChatMessage(ctx, w.chatID, text)
+	streamed, err := w.finishStream(ctx, text)
+	if err != nil {
+		return err
+	}
+	if !streamed {
+		if err := w.listener.sendFormattedChatMessage(ctx, w.chatID, text); err != nil {
+			return err
+		}
+	}
+	if w.mirror {
+		w.listener.mirrorToObservers(ctx, text)
+	}
+	return nil
+}
+
+// WriteActivity shows tool activity to observers only; the sender already
+// sees progress updates and approval prompts.
+func (w *telegramWriter) WriteActivity(ctx context.Context, text string) error {
+	if w == nil || w.listener == nil {
+		return errors.New("telegram sender is not configured")
+	}
+	if w.mirror {
+		w.listener.mirrorToObservers(ctx, "🔧 "+text)
+	}
+	return nil
+}
+
+// SendFile uploads a file to the chat.
+func (w *telegramWriter) SendFile(ctx context.Context, path, caption string) error {
+	if w == nil || w.listener == nil {
+		return errors.New("telegram sender is not configured")
+	}
+	return w.listener.sendChatFile(ctx, w.chatID, path, caption)
 }
 
 type telegramChannelWriter struct {
@@ -543,24 +757,70 @@ type telegramApprovalHandler struct {
 func (h *telegramApprovalHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
 	if h.listener != nil {
 		if writer, ok := w.(*telegramWriter); ok {
-			h.listener.setActiveApprovalTarget(writer.userID, writer.username, writer.chatID)
+			target := telegramApprovalTarget{
+				userID:    writer.userID,
+				username:  writer.username,
+				chatID:    writer.chatID,
+				resumeKey: writer.resumeKey,
+			}
+			if msg != nil {
+				target.text = msg.Text
+			}
+			ctx = withApprovalTarget(ctx, h.listener.setActiveApprovalTarget(target))
 			defer h.listener.clearActiveApprovalTarget()
 			if msg != nil && !strings.HasPrefix(strings.TrimSpace(msg.Text), "/") {
 				go h.listener.runTypingIndicator(ctx, writer.chatID)
+				writer.mirror = true
+				h.listener.mirrorToObservers(ctx, fmt.Sprintf("%s: %s", writer.senderLabel(), msg.Text))
 			}
 		}
 	}
 	return h.handler.HandleMessage(ctx, w, msg)
 }
 
-func (t *TelegramListener) setActiveApprovalTarget(userID, username string, chatID int64) {
+func (w *telegramWriter) senderLabel() string {
+	if w.username != "" {
+		return "@" + w.username
+	}
+	return "User " + w.userID
+}
+
+// mirrorToObservers forwards conversation text to every observer. Failures
+// are logged so an unreachable observer never breaks the owner's turn.
+func (t *TelegramListener) mirrorToObservers(ctx context.Context, text string) {
+	for userID, chatID := range t.observerTelegramUsers {
+		if err := t.sendFormattedChatMessage(ctx, chatID, text); err != nil {
+			logging.Logger().Warn("failed to mirror message to telegram observer", "user_id", userID, "err", err)
+		}
+	}
+}
+
+func (t *TelegramListener) setActiveApprovalTarget(target telegramApprovalTarget) *telegramApprovalTarget {
 	t.approvalMu.Lock()
 	defer t.approvalMu.Unlock()
-	t.activeApprovalTarget = &telegramApprovalTarget{
-		userID:   strings.TrimSpace(userID),
-		username: strings.TrimSpace(username),
-		chatID:   chatID,
+	target.userID = strings.TrimSpace(target.userID)
+	target.username = strings.TrimSpace(target.username)
+	t.activeApprovalTarget = &target
+	return t.activeApprovalTarget
+}
+
+type approvalTargetKey struct{}
+
+// withApprovalTarget marks ctx as the turn of target, so its approval
+// prompts go to that user and no one else.
+func withApprovalTarget(ctx context.Context, target *telegramApprovalTarget) context.Context {
+	return context.WithValue(ctx, approvalTargetKey{}, target)
+}
+
+// approvalTargetFrom returns the user whose turn ctx belongs to.
+func (t *TelegramListener) approvalTargetFrom(ctx context.Context) (telegramApprovalTarget, bool) {
+	target, _ := ctx.Value(approvalTargetKey{}).(*telegramApprovalTarget)
+	if target == nil {
+		return telegramApprovalTarget{}, false
 	}
+	t.approvalMu.Lock()
+	defer t.approvalMu.Unlock()
+	return *target, true
 }
 
 func (t *TelegramListener) clearActiveApprovalTarget() {
@@ -582,6 +842,7 @@ func (t *TelegramListener) storePendingApproval(token string, pending telegramPe
 	t.approvalMu.Lock()
 	defer t.approvalMu.Unlock()
 	t.pendingApprovals[token] = pending
+	t.savePendingApprovalsLocked()
 }
 
 func (t *TelegramListener) pendingApproval(token string) (telegramPendingApproval, bool) {
@@ -591,10 +852,17 @@ func (t *TelegramListener) pendingApproval(token string) (telegramPendingApprova
 	return pending, ok
 }
 
-func (t *TelegramListener) deletePendingApproval(token string) {
+// deletePendingApproval forgets token; save=false keeps it on disk.
+func (t *TelegramListener) deletePendingApproval(token string, save bool) {
 	t.approvalMu.Lock()
 	defer t.approvalMu.Unlock()
+	if _, ok := t.pendingApprovals[token]; !ok {
+		return
+	}
 	delete(t.pendingApprovals, token)
+	if save {
+		t.savePendingApprovalsLocked()
+	}
 }
 
 func (t *TelegramListener) sendTelegramMessage(ctx context.Context, params *bot.SendMessageParams) (*models.Message, error) {
@@ -613,20 +881,146 @@ func (t *TelegramListener) sendChatMessage(ctx context.Context, chatID int64, te
 	return err
 }
 
+// sendFormattedChatMessage sends text to chatID as one message, or as
+// several when it is too long for one.
 func (t *TelegramListener) sendFormattedChatMessage(ctx context.Context, chatID int64, text string) error {
-	formattedText, ok := formatTelegram(text)
-	params := &bot.SendMessageParams{
-		ChatID: chatID,
-		Text:   formattedText,
+	for _, chunk := range t.formatOutbound(chatID, text) {
+		if _, err := t.sendTelegramMessage(ctx, &bot.SendMessageParams{
+			ChatID:    chatID,
+			Text:      chunk.text,
+			ParseMode: chunk.parseMode,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// telegramChunk is one message of a formatted reply.
+type telegramChunk struct {
+	text      string
+	parseMode models.ParseMode
+}
+
+// formatOutbound filters credentials from a reply to chatID, splits it into
+// messages Telegram accepts, and renders each as Telegram HTML, falling
+// back to plain text.
+func (t *TelegramListener) formatOutbound(chatID int64, text string) []telegramChunk {
+	text = t.filterOutbound(chatID, text)
+	var chunks []telegramChunk
+	for _, part := range splitTelegramMarkdown(text, telegramChunkChars) {
+		formattedText, ok := formatTelegram(part)
+		chunk := telegramChunk{text: formattedText}
+		if ok {
+			chunk.parseMode = models.ParseModeHTML
+		}
+		chunks = append(chunks, chunk)
 	}
-	if ok {
-		params.ParseMode = models.ParseModeHTML
+	return chunks
+}
+
+// filterOutbound applies the outbound credential filter to text for chatID.
+func (t *TelegramListener) filterOutbound(chatID int64, text string) string {
+	text, found := redact.FilterSecrets(text, t.OutboundSecrets)
+	if len(found) > 0 {
+		logging.Logger().Warn("outbound telegram message contained credentials", "chat_id", chatID, "kinds", strings.Join(found, ", "), "mode", t.OutboundSecrets)
 	}
+	return text
+}
 
-	_, err := t.sendTelegramMessage(ctx, params)
+// filterOutboundFile applies the outbound credential filter to a text file
+// about to be uploaded to chatID. Binary files pass unchanged.
+func (t *TelegramListener) filterOutboundFile(chatID int64, file io.Reader) (io.Reader, error) {
+	if t.OutboundSecrets != redact.SecretsRedact && t.OutboundSecrets != redact.SecretsBlock {
+		return file, nil
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	if !utf8.Valid(content) {
+		return bytes.NewReader(content), nil
+	}
+	filtered, found := redact.FilterSecrets(string(content), t.OutboundSecrets)
+	if len(found) == 0 {
+		return bytes.NewReader(content), nil
+	}
+	logging.Logger().Warn("outbound telegram file contained credentials", "chat_id", chatID, "kinds", strings.Join(found, ", "), "mode", t.OutboundSecrets)
+	if t.OutboundSecrets == redact.SecretsBlock {
+		return nil, fmt.Errorf("it contains what looks like a credential (%s)", strings.Join(found, ", "))
+	}
+	return strings.NewReader(filtered), nil
+}
+
+// sendChatFile uploads the file at path to the chat: images up to
+// maxTelegramPhotoBytes as a photo, anything else as a document.
+func (t *TelegramListener) sendChatFile(ctx context.Context, chatID int64, path, caption string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+	if info.Size() > maxTelegramDocumentBytes {
+		return fmt.Errorf("%s is %d MB; Telegram bots can send files up to %d MB", filepath.Base(path), info.Size()>>20, maxTelegramDocumentBytes>>20)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+	defer file.Close()
+	upload := &models.InputFileUpload{Filename: filepath.Base(path), Data: file}
+	caption = truncateTelegramCaption(t.filterOutbound(chatID, caption))
+	photo := isTelegramPhoto(path) && info.Size() <= maxTelegramPhotoBytes
+	if !photo {
+		data, err := t.filterOutboundFile(chatID, file)
+		if err != nil {
+			return fmt.Errorf("%s was not sent: %w", filepath.Base(path), err)
+		}
+		upload.Data = data
+	}
+
+	if photo {
+		send := t.sendPhoto
+		if send == nil {
+			return errors.New("telegram bot is not connected")
+		}
+		_, err = send(ctx, &bot.SendPhotoParams{ChatID: chatID, Photo: upload, Caption: caption})
+		return err
+	}
+	send := t.sendDocument
+	if send == nil {
+		return errors.New("telegram bot is not connected")
+	}
+	_, err = send(ctx, &bot.SendDocumentParams{ChatID: chatID, Document: upload, Caption: caption})
 	return err
 }
 
+// SendFile uploads a file to the active Telegram chat for the current request.
+func (t *TelegramListener) SendFile(ctx context.Context, path, caption string) error {
+	target, ok := t.activeApprovalTargetSnapshot()
+	if !ok {
+		return errors.New("telegram chat target is unavailable")
+	}
+	return t.sendChatFile(ctx, target.chatID, path, caption)
+}
+
+// isTelegramPhoto reports whether path is an image Telegram shows inline.
+func isTelegramPhoto(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg", ".png", ".webp":
+		return true
+	}
+	return false
+}
+
+// truncateTelegramCaption cuts caption to Telegram's caption limit.
+func truncateTelegramCaption(caption string) string {
+	runes := []rune(caption)
+	if len(runes) <= maxTelegramCaptionChars {
+		return caption
+	}
+	return string(runes[:maxTelegramCaptionChars-1]) + "…"
+}
+
 // Send delivers a channel message to the active Telegram chat for the current request.
 func (t *TelegramListener) Send(ctx context.Context, message string) error {
 	target, ok := t.activeApprovalTargetSnapshot()
@@ -642,7 +1036,7 @@ func (t *TelegramListener) CurrentChannelID() string {
 	if !ok {
 		return ""
 	}
-	return fmt.Sprintf("telegram-%d", target.chatID)
+	return t.ChannelKey(target.chatID)
 }
 
 func (t *TelegramListener) answerTelegramCallback(ctx context.Context, params *bot.AnswerCallbackQueryParams) (bool, error) {
@@ -653,6 +1047,23 @@ func (t *TelegramListener) answerTelegramCallback(ctx context.Context, params *b
 	return answer(ctx, params)
 }
 
+func (t *TelegramListener) editTelegramMessageText(ctx context.Context, params *bot.EditMessageTextParams) (*models.Message, error) {
+	edit := t.editMessageText
+	if edit == nil {
+		return nil, errors.New("telegram bot is not connected")
+	}
+	return edit(ctx, params)
+}
+
+func (t *TelegramListener) deleteTelegramMessage(ctx context.Context, params *bot.DeleteMessageParams) error {
+	deleteMessage := t.deleteMessage
+	if deleteMessage == nil {
+		return errors.New("telegram bot is not connected")
+	}
+	_, err := deleteMessage(ctx, params)
+	return err
+}
+
 func (t *TelegramListener) editTelegramReplyMarkup(ctx context.Context, params *bot.EditMessageReplyMarkupParams) (*models.Message, error) {
 	edit := t.editMessageReplyMarkup
 	if edit == nil {
@@ -688,7 +1099,7 @@ func (t *TelegramListener) sendTypingAction(ctx context.Context, chatID int64) {
 	})
 }
 
-func generateTelegramApprovalToken() (string, error) {
+func generateApprovalToken() (string, error) {
 	bytes := make([]byte, 8)
 	if _, err := rand.Read(bytes); err != nil {
 		return "", err
@@ -727,11 +1138,16 @@ func messagePreview(text string, limit int) string {
 	return string(runes[:limit])
 }
 
-func (t *TelegramListener) createTelegramBot(defaultHandler bot.HandlerFunc) (*bot.Bot, error) {
+func (t *TelegramListener) createTelegramBot(defaultHandler bot.HandlerFunc, webhookSecret string) (*bot.Bot, error) {
 	options := []bot.Option{
+		bot.WithWebhookSecretToken(webhookSecret),
 		bot.WithDefaultHandler(defaultHandler),
 		bot.WithCallbackQueryDataHandler(telegramApprovalApprovePrefix, bot.MatchTypePrefix, t.onApprovalApproveCallback),
 		bot.WithCallbackQueryDataHandler(telegramApprovalDenyPrefix, bot.MatchTypePrefix, t.onApprovalDenyCallback),
+		bot.WithCallbackQueryDataHandler(telegramApprovalResendPrefix, bot.MatchTypePrefix, t.onApprovalResendCallback),
+	}
+	if t.apiURL != "" {
+		options = append(options, bot.WithServerURL(t.apiURL))
 	}
 	return bot.New(strings.TrimSpace(t.token), options...)
 }
@@ -870,7 +1286,7 @@ func renderTelegramNode(builder *strings.Builder, node ast.Node, source []byte,
 		if err := renderTelegramChildren(&itemText, typed, source, true); err != nil {
 			return err
 		}
-		builder.WriteString("- ")
+		builder.WriteString(telegramListMarker(typed))
 		builder.WriteString(strings.TrimSpace(itemText.String()))
 		builder.WriteString("\n")
 		return nil
@@ -885,6 +1301,20 @@ func renderTelegramNode(builder *strings.Builder, node ast.Node, source []byte,
 	}
 }
 
+// telegramListMarker keeps ordered list numbers, which replies and commands
+// such as /list refer back to, and uses a dash for bullet items.
+func telegramListMarker(item *ast.ListItem) string {
+	list, ok := item.Parent().(*ast.List)
+	if !ok || !list.IsOrdered() {
+		return "- "
+	}
+	n := list.Start
+	for sibling := item.PreviousSibling(); sibling != nil; sibling = sibling.PreviousSibling() {
+		n++
+	}
+	return strconv.Itoa(n) + ". "
+}
+
 func renderTelegramChildren(builder *strings.Builder, node ast.Node, source []byte, inListItem bool) error {
 	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
 		if err := renderTelegramNode(builder, child, source, inListItem); err != nil {
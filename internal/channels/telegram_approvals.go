@@ -0,0 +1,328 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+const (
+	telegramApprovalResendPrefix = "approval:resend:"
+	telegramApprovalsCommand     = "/approvals"
+)
+
+// pendingApprovalRecord is an approval prompt waiting for an answer. It is
+// saved to disk so the prompt can be asked again after a restart.
+type pendingApprovalRecord struct {
+	Token       string         `json:"token"`
+	Tool        string         `json:"tool"`
+	Description string         `json:"description"`
+	Args        map[string]any `json:"args,omitempty"`
+	UserID      string         `json:"user_id"`
+	Username    string         `json:"username,omitempty"`
+	ChatID      int64          `json:"chat_id"`
+	// MessageIDs are every prompt posted for the request, so all of them
+	// are closed once it is answered or expires.
+	MessageIDs []int `json:"message_ids,omitempty"`
+	// Text is the message whose turn asked for approval. It is handled again
+	// when a prompt recovered after a restart is approved.
+	Text        string    `json:"text,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+func (r pendingApprovalRecord) request() approval.ApprovalRequest {
+	return approval.ApprovalRequest{Tool: r.Tool, Description: r.Description, Args: r.Args}
+}
+
+// ConfigurePendingApprovals sets the file where unanswered approval prompts
+// are saved. Prompts in it are asked again when the listener starts, since
+// the turn that was waiting on them did not survive the restart.
+func (t *TelegramListener) ConfigurePendingApprovals(path string) {
+	t.pendingApprovalsPath = path
+}
+
+// approvalPrompt is the text shown for req.
+func approvalPrompt(req approval.ApprovalRequest) string {
+	prompt := strings.TrimSpace(req.Description)
+	if prompt == "" {
+		prompt = fmt.Sprintf("Approve %s?", strings.TrimSpace(req.Tool))
+	}
+	return prompt
+}
+
+// sendApprovalPrompt posts text with Approve/Deny buttons answering token.
+func (t *TelegramListener) sendApprovalPrompt(ctx context.Context, chatID int64, text, token string) (*models.Message, error) {
+	return t.sendTelegramMessage(ctx, &bot.SendMessageParams{
+		ChatID: chatID,
+		Text:   text,
+		ReplyMarkup: &models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{
+				{
+					{
+						Text:         "✅ Approve",
+						CallbackData: telegramApprovalApprovePrefix + token,
+					},
+					{
+						Text:         "❌ Deny",
+						CallbackData: telegramApprovalDenyPrefix + token,
+					},
+				},
+			},
+		},
+	})
+}
+
+// closeApprovalPrompts replaces the buttons on every prompt posted for
+// record with status, such as "✅ Approved", so no stale keyboard is left
+// in the chat.
+func (t *TelegramListener) closeApprovalPrompts(ctx context.Context, record pendingApprovalRecord, status string) {
+	text := approvalPrompt(record.request()) + "\n\n" + status
+	for _, messageID := range record.MessageIDs {
+		if messageID == 0 {
+			continue
+		}
+		// Editing the text drops the keyboard. If the edit fails, e.g.
+		// because the message is too old, at least remove the buttons.
+		if _, err := t.editTelegramMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:    record.ChatID,
+			MessageID: messageID,
+			Text:      text,
+		}); err != nil {
+			t.clearApprovalKeyboard(ctx, record.ChatID, messageID)
+		}
+	}
+}
+
+// clearApprovalKeyboard removes the buttons from a prompt.
+func (t *TelegramListener) clearApprovalKeyboard(ctx context.Context, chatID int64, messageID int) {
+	if _, err := t.editTelegramReplyMarkup(ctx, &bot.EditMessageReplyMarkupParams{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		ReplyMarkup: nil,
+	}); err != nil {
+		logging.Logger().Warn("failed to clear approval keyboard", "chat_id", chatID, "message_id", messageID, "err", err)
+	}
+}
+
+// recoverPendingApprovals asks again about every prompt saved by a previous
+// run. The turns that asked are gone, so approving one handles its message
+// again instead of answering a waiting turn. Prompts posted before the
+// restart keep working until the request is answered.
+func (t *TelegramListener) recoverPendingApprovals(ctx context.Context) {
+	records, err := loadPendingApprovals(t.pendingApprovalsPath)
+	if err != nil {
+		logging.Logger().Warn("failed to load pending approvals", "path", t.pendingApprovalsPath, "err", err)
+		return
+	}
+	for _, record := range records {
+		if !t.isAllowedUser(record.UserID) {
+			continue
+		}
+		text := "NeoClaw restarted while waiting for your answer.\n" + approvalPrompt(record.request())
+		message, err := t.sendApprovalPrompt(ctx, record.ChatID, text, record.Token)
+		if err != nil {
+			logging.Logger().Warn("failed to resend pending approval", "chat_id", record.ChatID, "err", err)
+			continue
+		}
+		if message != nil {
+			record.MessageIDs = append(record.MessageIDs, message.ID)
+		}
+		t.storePendingApproval(record.Token, telegramPendingApproval{pendingApprovalRecord: record})
+	}
+	// Drop prompts for users that are no longer allowed.
+	t.savePendingApprovals()
+}
+
+// resumeApproval acts on the answer to a prompt recovered after a restart.
+func (t *TelegramListener) resumeApproval(ctx context.Context, pending telegramPendingApproval, decision approval.ApprovalDecision) {
+	if decision == approval.Denied || strings.TrimSpace(pending.Text) == "" {
+		if err := t.sendChatMessage(ctx, pending.ChatID, "Denied. The request from before the restart was dropped."); err != nil {
+			logging.Logger().Warn("failed to confirm recovered approval", "chat_id", pending.ChatID, "err", err)
+		}
+		return
+	}
+	key, _ := approval.RequestKey(pending.request())
+	if err := t.sendChatMessage(ctx, pending.ChatID, "Approved. Picking up where I left off: "+messagePreview(pending.Text, 100)); err != nil {
+		logging.Logger().Warn("failed to confirm recovered approval", "chat_id", pending.ChatID, "err", err)
+	}
+	writer := &telegramWriter{
+		listener:  t,
+		chatID:    pending.ChatID,
+		userID:    pending.UserID,
+		username:  pending.Username,
+		resumeKey: key,
+	}
+	if t.dispatcher == nil {
+		logging.Logger().Warn("cannot resume approved request: listener is not running", "chat_id", pending.ChatID)
+		return
+	}
+	if err := t.dispatcher.Enqueue(ctx, &runtime.Message{Text: pending.Text, UserID: pending.UserID}, writer); err != nil {
+		logging.Logger().Warn("failed to resume approved request", "chat_id", pending.ChatID, "err", err)
+	}
+}
+
+// sendPendingApprovalList answers /approvals with every prompt waiting on
+// userID, oldest first, and a button to post each one again.
+func (t *TelegramListener) sendPendingApprovalList(ctx context.Context, chatID int64, userID string, now time.Time) error {
+	var waiting []pendingApprovalRecord
+	t.approvalMu.Lock()
+	for _, pending := range t.pendingApprovals {
+		if pending.UserID == userID {
+			waiting = append(waiting, pending.pendingApprovalRecord)
+		}
+	}
+	t.approvalMu.Unlock()
+	if len(waiting) == 0 {
+		return t.sendChatMessage(ctx, chatID, "No approvals are waiting.")
+	}
+	sort.Slice(waiting, func(i, j int) bool { return waiting[i].RequestedAt.Before(waiting[j].RequestedAt) })
+
+	lines := []string{"Waiting for your answer:"}
+	var keyboard [][]models.InlineKeyboardButton
+	for i, record := range waiting {
+		n := strconv.Itoa(i + 1)
+		age := now.Sub(record.RequestedAt).Round(time.Second)
+		lines = append(lines, fmt.Sprintf("%s. %s (waiting %s)", n, approvalPrompt(record.request()), age))
+		keyboard = append(keyboard, []models.InlineKeyboardButton{{
+			Text:         "🔁 Re-send " + n,
+			CallbackData: telegramApprovalResendPrefix + record.Token,
+		}})
+	}
+	_, err := t.sendTelegramMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        strings.Join(lines, "\n"),
+		ReplyMarkup: &models.InlineKeyboardMarkup{InlineKeyboard: keyboard},
+	})
+	return err
+}
+
+// handleResendCallback posts a pending prompt again, below the /approvals
+// list. Earlier copies keep working until the request is answered.
+func (t *TelegramListener) handleResendCallback(ctx context.Context, callback *models.CallbackQuery) {
+	if callback == nil {
+		return
+	}
+	if _, err := t.answerTelegramCallback(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: callback.ID,
+	}); err != nil {
+		logging.Logger().Warn("failed to answer approval callback", "err", err)
+	}
+	token := parseApprovalToken(callback.Data, telegramApprovalResendPrefix)
+	if token == "" {
+		return
+	}
+	pending, found := t.pendingApproval(token)
+	if !found || strconv.FormatInt(callback.From.ID, 10) != pending.UserID {
+		return
+	}
+	chatID, _, ok := callbackMessageLocation(callback)
+	if !ok || chatID != pending.ChatID {
+		return
+	}
+	message, err := t.sendApprovalPrompt(ctx, pending.ChatID, approvalPrompt(pending.request()), token)
+	if err != nil {
+		logging.Logger().Warn("failed to resend approval prompt", "chat_id", pending.ChatID, "err", err)
+		return
+	}
+	if message != nil {
+		t.addPendingApprovalMessage(token, message.ID)
+	}
+}
+
+func (t *TelegramListener) onApprovalResendCallback(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	if update == nil || update.CallbackQuery == nil {
+		return
+	}
+	t.handleResendCallback(ctx, update.CallbackQuery)
+}
+
+func (t *TelegramListener) addPendingApprovalMessage(token string, messageID int) {
+	t.approvalMu.Lock()
+	defer t.approvalMu.Unlock()
+	pending, ok := t.pendingApprovals[token]
+	if !ok {
+		return
+	}
+	pending.MessageIDs = append(pending.MessageIDs, messageID)
+	t.pendingApprovals[token] = pending
+	t.savePendingApprovalsLocked()
+}
+
+// consumeResumedApproval reports whether key is the request approved after
+// a restart for the turn in ctx. It matches once.
+func (t *TelegramListener) consumeResumedApproval(ctx context.Context, key string) bool {
+	target, _ := ctx.Value(approvalTargetKey{}).(*telegramApprovalTarget)
+	t.approvalMu.Lock()
+	defer t.approvalMu.Unlock()
+	if key == "" || target == nil || target.resumeKey != key {
+		return false
+	}
+	target.resumeKey = ""
+	return true
+}
+
+// stopping reports whether the listener is shutting down.
+func (t *TelegramListener) stopping() bool {
+	return t.listenCtx != nil && t.listenCtx.Err() != nil
+}
+
+func (t *TelegramListener) savePendingApprovals() {
+	t.approvalMu.Lock()
+	defer t.approvalMu.Unlock()
+	t.savePendingApprovalsLocked()
+}
+
+// savePendingApprovalsLocked writes every pending prompt; approvalMu must be
+// held. A failed write is logged: the prompt still works until a restart.
+func (t *TelegramListener) savePendingApprovalsLocked() {
+	if t.pendingApprovalsPath == "" {
+		return
+	}
+	records := make([]pendingApprovalRecord, 0, len(t.pendingApprovals))
+	for _, pending := range t.pendingApprovals {
+		records = append(records, pending.pendingApprovalRecord)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].RequestedAt.Before(records[j].RequestedAt) })
+	encoded, err := json.MarshalIndent(records, "", "  ")
+	if err == nil {
+		err = store.WriteFile(t.pendingApprovalsPath, append(encoded, '\n'))
+	}
+	if err != nil {
+		logging.Logger().Warn("failed to save pending approvals", "path", t.pendingApprovalsPath, "err", err)
+	}
+}
+
+// loadPendingApprovals reads saved prompts. A missing file has none.
+func loadPendingApprovals(path string) ([]pendingApprovalRecord, error) {
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := store.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var records []pendingApprovalRecord
+	if err := json.Unmarshal([]byte(raw), &records); err != nil {
+		return nil, fmt.Errorf("decode pending approvals %s: %w", path, err)
+	}
+	return records, nil
+}
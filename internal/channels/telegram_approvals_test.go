@@ -0,0 +1,283 @@
+package channels
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+const aliceAllowedUsers = `{
+  "users": [
+    {"id":"111","channel":"telegram","username":"alice","name":"Alice","added_at":"2026-02-19T14:30:00Z"}
+  ]
+}
+`
+
+func TestTelegramListener_ApprovalsListsAndResendsPendingPrompts(t *testing.T) {
+	listener := NewTelegram("token", writeAllowedUsersFile(t, aliceAllowedUsers))
+	if err := listener.loadAllowedUsers(); err != nil {
+		t.Fatalf("load users: %v", err)
+	}
+	pendingPath := filepath.Join(t.TempDir(), "pending_approvals.json")
+	listener.ConfigurePendingApprovals(pendingPath)
+	ctx := withApprovalTarget(context.Background(), &telegramApprovalTarget{userID: "111", username: "alice", chatID: 42, text: "fix the config"})
+
+	api := newMockTelegramAPI()
+	listener.sendMessage = api.sendMessage
+	listener.answerCallbackQuery = api.answerCallback
+	listener.editMessageReplyMarkup = api.editReplyMarkup
+	listener.editMessageText = api.editText
+
+	done := make(chan approval.ApprovalDecision, 1)
+	go func() {
+		decision, _ := listener.RequestApproval(ctx, approval.ApprovalRequest{
+			Tool:        "write_file",
+			Description: "Write config.toml",
+		})
+		done <- decision
+	}()
+	api.waitForSend(t)
+
+	records, err := loadPendingApprovals(pendingPath)
+	if err != nil || len(records) != 1 || records[0].Text != "fix the config" || records[0].UserID != "111" {
+		t.Fatalf("expected the prompt to be saved, got %#v (%v)", records, err)
+	}
+
+	listener.handleInboundMessage(context.Background(), nil, &models.Message{
+		From: &models.User{ID: 111, Username: "alice"},
+		Chat: models.Chat{ID: 42},
+		Text: "/approvals",
+	})
+	list := api.waitForSend(t)
+	if !strings.Contains(list.Text, "1. Write config.toml (waiting") {
+		t.Fatalf("unexpected /approvals reply: %q", list.Text)
+	}
+	markup := list.ReplyMarkup.(*models.InlineKeyboardMarkup)
+	resendData := markup.InlineKeyboard[0][0].CallbackData
+
+	listener.onApprovalResendCallback(context.Background(), nil, &models.Update{
+		CallbackQuery: &models.CallbackQuery{
+			ID:      "callback-1",
+			From:    models.User{ID: 111},
+			Data:    resendData,
+			Message: models.MaybeInaccessibleMessage{Message: &models.Message{ID: 2, Chat: models.Chat{ID: 42}}},
+		},
+	})
+	resent := api.waitForSend(t)
+	if resent.Text != "Write config.toml" {
+		t.Fatalf("unexpected re-sent prompt: %q", resent.Text)
+	}
+	_, denyData := callbackDataFromReplyMarkup(t, resent)
+	listener.onApprovalDenyCallback(context.Background(), nil, &models.Update{
+		CallbackQuery: &models.CallbackQuery{
+			ID:      "callback-2",
+			From:    models.User{ID: 111},
+			Data:    denyData,
+			Message: models.MaybeInaccessibleMessage{Message: &models.Message{ID: 3, Chat: models.Chat{ID: 42}}},
+		},
+	})
+	select {
+	case decision := <-done:
+		if decision != approval.Denied {
+			t.Fatalf("expected Denied, got %v", decision)
+		}
+	case <-time.After(300 * time.Millisecond):
+		t.Fatal("request approval did not complete")
+	}
+	if records, err := loadPendingApprovals(pendingPath); err != nil || len(records) != 0 {
+		t.Fatalf("expected no saved prompts after the answer, got %#v (%v)", records, err)
+	}
+}
+
+// telegramResumeHandler asks for the same approval the interrupted turn did.
+type telegramResumeHandler struct {
+	listener  *TelegramListener
+	decisions chan approval.ApprovalDecision
+	texts     chan string
+}
+
+func (h *telegramResumeHandler) HandleMessage(ctx context.Context, _ runtime.ResponseWriter, msg *runtime.Message) error {
+	h.texts <- msg.Text
+	decision, err := h.listener.RequestApproval(ctx, approval.ApprovalRequest{
+		Tool:        "run_command",
+		Description: "Run Once: rm -rf build",
+		Args:        map[string]any{"command": "rm -rf build"},
+	})
+	h.decisions <- decision
+	return err
+}
+
+func TestTelegramListener_RecoversPendingApprovalAfterRestart(t *testing.T) {
+	listener := NewTelegram("token", writeAllowedUsersFile(t, aliceAllowedUsers))
+	if err := listener.loadAllowedUsers(); err != nil {
+		t.Fatalf("load users: %v", err)
+	}
+	pendingPath := filepath.Join(t.TempDir(), "pending_approvals.json")
+	if err := store.WriteFile(pendingPath, []byte(`[
+  {"token":"abc123","tool":"run_command","description":"Run Once: rm -rf build","args":{"command":"rm -rf build"},
+   "user_id":"111","username":"alice","chat_id":42,"message_ids":[7],"text":"clean up the build dir","requested_at":"2026-03-01T10:00:00Z"}
+]
+`)); err != nil {
+		t.Fatalf("write pending approvals: %v", err)
+	}
+	listener.ConfigurePendingApprovals(pendingPath)
+
+	api := newMockTelegramAPI()
+	listener.sendMessage = api.sendMessage
+	listener.answerCallbackQuery = api.answerCallback
+	listener.editMessageReplyMarkup = api.editReplyMarkup
+	listener.editMessageText = api.editText
+
+	handler := &telegramResumeHandler{
+		listener:  listener,
+		decisions: make(chan approval.ApprovalDecision, 1),
+		texts:     make(chan string, 1),
+	}
+	dispatcher, stop := startTestDispatcher(t, &telegramApprovalHandler{listener: listener, handler: handler})
+	defer stop()
+	listener.dispatcher = dispatcher
+
+	listener.recoverPendingApprovals(context.Background())
+	prompt := api.waitForSend(t)
+	if !strings.Contains(prompt.Text, "restarted") || !strings.Contains(prompt.Text, "Run Once: rm -rf build") {
+		t.Fatalf("unexpected recovered prompt: %q", prompt.Text)
+	}
+	approveData, _ := callbackDataFromReplyMarkup(t, prompt)
+	if approveData != telegramApprovalApprovePrefix+"abc123" {
+		t.Fatalf("expected the saved token to be reused, got %q", approveData)
+	}
+
+	listener.onApprovalApproveCallback(context.Background(), nil, &models.Update{
+		CallbackQuery: &models.CallbackQuery{
+			ID:      "callback-1",
+			From:    models.User{ID: 111},
+			Data:    approveData,
+			Message: models.MaybeInaccessibleMessage{Message: &models.Message{ID: 1, Chat: models.Chat{ID: 42}}},
+		},
+	})
+	select {
+	case text := <-handler.texts:
+		if text != "clean up the build dir" {
+			t.Fatalf("expected the original message to be handled again, got %q", text)
+		}
+	case <-time.After(300 * time.Millisecond):
+		t.Fatal("approved request was not resumed")
+	}
+	select {
+	case decision := <-handler.decisions:
+		if decision != approval.Approved {
+			t.Fatalf("expected the resumed turn to be approved, got %v", decision)
+		}
+	case <-time.After(300 * time.Millisecond):
+		t.Fatal("resumed turn did not finish")
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	// Both the prompt from before the restart and the new one are closed.
+	if len(api.textCalls) != 2 || api.textCalls[0].MessageID != 7 || api.textCalls[1].MessageID != 1 {
+		t.Fatalf("expected both prompts to be closed, got %#v", api.textCalls)
+	}
+	if !strings.HasSuffix(api.textCalls[0].Text, "✅ Approved") {
+		t.Fatalf("unexpected closed prompt %q", api.textCalls[0].Text)
+	}
+	for _, call := range api.sendCalls[1:] {
+		if _, ok := call.ReplyMarkup.(*models.InlineKeyboardMarkup); ok {
+			t.Fatalf("expected no second prompt, got %q", call.Text)
+		}
+	}
+	if records, err := loadPendingApprovals(pendingPath); err != nil || len(records) != 0 {
+		t.Fatalf("expected no saved prompts, got %#v (%v)", records, err)
+	}
+}
+
+func TestTelegramListenerRequestApproval_ExpiresAfterTimeout(t *testing.T) {
+	listener := NewTelegram("token", "")
+	listener.ConfigureApprovalTimeout(20 * time.Millisecond)
+	ctx := withApprovalTarget(context.Background(), &telegramApprovalTarget{userID: "111", username: "alice", chatID: 42})
+
+	api := newMockTelegramAPI()
+	listener.sendMessage = api.sendMessage
+	listener.editMessageReplyMarkup = api.editReplyMarkup
+	listener.editMessageText = api.editText
+
+	decision, err := listener.RequestApproval(ctx, approval.ApprovalRequest{
+		Tool:        "run_command",
+		Description: "Run: pwd",
+	})
+	if decision != approval.Denied || err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected a timeout, got %v (%v)", decision, err)
+	}
+	if len(api.textCalls) != 1 || api.textCalls[0].Text != "Run: pwd\n\n⌛ Expired: no answer within 20ms" {
+		t.Fatalf("expected the prompt to be marked expired, got %#v", api.textCalls)
+	}
+	if len(listener.pendingApprovals) != 0 {
+		t.Fatalf("expected no pending approvals left")
+	}
+}
+
+func TestTelegramListenerRequestApproval_OnlyPromptsTheTurnsUser(t *testing.T) {
+	listener := NewTelegram("token", "")
+	api := newMockTelegramAPI()
+	listener.sendMessage = api.sendMessage
+	listener.answerCallbackQuery = api.answerCallback
+	listener.editMessageReplyMarkup = api.editReplyMarkup
+	listener.editMessageText = api.editText
+
+	// Bob's turn is in flight, but a request outside it must not reach him.
+	listener.setActiveApprovalTarget(telegramApprovalTarget{userID: "222", username: "bob", chatID: 77})
+	decision, err := listener.RequestApproval(context.Background(), approval.ApprovalRequest{Tool: "run_command", Description: "Run: pwd"})
+	if decision != approval.Denied || err == nil {
+		t.Fatalf("expected a refusal without a turn, got %v (%v)", decision, err)
+	}
+	if len(api.sendCalls) != 0 {
+		t.Fatalf("expected no prompt, got %#v", api.sendCalls)
+	}
+
+	ctx := withApprovalTarget(context.Background(), &telegramApprovalTarget{userID: "111", username: "alice", chatID: 42})
+	done := make(chan approval.ApprovalDecision, 1)
+	go func() {
+		decision, _ := listener.RequestApproval(ctx, approval.ApprovalRequest{Tool: "run_command", Description: "Run: pwd"})
+		done <- decision
+	}()
+	sent := api.waitForSend(t)
+	if chatIDFromAny(sent.ChatID) != 42 {
+		t.Fatalf("expected the prompt in alice's chat, got %v", sent.ChatID)
+	}
+	approveData, _ := callbackDataFromReplyMarkup(t, sent)
+
+	// Bob cannot answer alice's prompt.
+	listener.onApprovalApproveCallback(context.Background(), nil, &models.Update{CallbackQuery: &models.CallbackQuery{
+		ID:      "bob",
+		From:    models.User{ID: 222},
+		Data:    approveData,
+		Message: models.MaybeInaccessibleMessage{Message: &models.Message{ID: 1, Chat: models.Chat{ID: 42}}},
+	}})
+	select {
+	case decision := <-done:
+		t.Fatalf("expected bob's answer to be ignored, got %v", decision)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	listener.onApprovalApproveCallback(context.Background(), nil, &models.Update{CallbackQuery: &models.CallbackQuery{
+		ID:      "alice",
+		From:    models.User{ID: 111},
+		Data:    approveData,
+		Message: models.MaybeInaccessibleMessage{Message: &models.Message{ID: 1, Chat: models.Chat{ID: 42}}},
+	}})
+	select {
+	case decision := <-done:
+		if decision != approval.Approved {
+			t.Fatalf("expected Approved, got %v", decision)
+		}
+	case <-time.After(300 * time.Millisecond):
+		t.Fatal("request approval did not complete")
+	}
+}
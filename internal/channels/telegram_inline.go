@@ -0,0 +1,137 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+const (
+	// defaultTelegramInlineDebounce waits out typing: clients send a query
+	// at every pause, and only the last one is worth a model call.
+	defaultTelegramInlineDebounce = 700 * time.Millisecond
+	// telegramInlineTitleChars and telegramInlineDescriptionChars bound the
+	// result shown above the keyboard.
+	telegramInlineTitleChars       = 64
+	telegramInlineDescriptionChars = 200
+)
+
+// TelegramInlineAnswerer answers inline queries (@bot <query>) typed in any
+// chat. chatKey is the scheduler channel key of the asker's private chat
+// with the bot.
+type TelegramInlineAnswerer interface {
+	AnswerInline(ctx context.Context, chatKey, query string) (string, error)
+}
+
+type telegramAnswerInlineQueryFunc func(context.Context, *bot.AnswerInlineQueryParams) (bool, error)
+
+// telegramInlineQuery is a user's inline query waiting for its answer.
+type telegramInlineQuery struct {
+	cancel context.CancelFunc
+}
+
+// ConfigureInlineQueries answers inline queries from paired users with
+// answerer. Inline mode must also be switched on for the bot with
+// BotFather's /setinline.
+func (t *TelegramListener) ConfigureInlineQueries(answerer TelegramInlineAnswerer) {
+	t.inlineAnswerer = answerer
+}
+
+// handleInlineQuery answers query in the background, so a slow model call
+// does not hold up messages. A newer query from the same user replaces one
+// still waiting for its answer.
+func (t *TelegramListener) handleInlineQuery(ctx context.Context, query *models.InlineQuery) {
+	if t.inlineAnswerer == nil || query == nil || query.From == nil {
+		return
+	}
+	userID := strconv.FormatInt(query.From.ID, 10)
+	if !t.isAllowedUser(userID) {
+		return
+	}
+	text := strings.TrimSpace(query.Query)
+	if text == "" {
+		return
+	}
+	logging.Logger().Info(
+		"telegram inline query",
+		"user_id", userID,
+		"text", messagePreview(text, 100),
+	)
+
+	if t.listenCtx != nil {
+		ctx = t.listenCtx
+	}
+	queryCtx, cancel := context.WithCancel(ctx)
+	pending := &telegramInlineQuery{cancel: cancel}
+	t.inlineMu.Lock()
+	if previous, ok := t.inlineQueries[userID]; ok {
+		previous.cancel()
+	}
+	t.inlineQueries[userID] = pending
+	t.inlineMu.Unlock()
+
+	go func() {
+		defer func() {
+			t.inlineMu.Lock()
+			if t.inlineQueries[userID] == pending {
+				delete(t.inlineQueries, userID)
+			}
+			t.inlineMu.Unlock()
+			cancel()
+		}()
+		if err := t.answerInline(queryCtx, query, text); err != nil && queryCtx.Err() == nil {
+			logging.Logger().Warn("failed to answer telegram inline query", "user_id", userID, "err", err)
+		}
+	}()
+}
+
+// answerInline waits out the debounce, asks the answerer, and offers its
+// reply as a single article that posts the answer when picked.
+func (t *TelegramListener) answerInline(ctx context.Context, query *models.InlineQuery, text string) error {
+	timer := time.NewTimer(t.inlineDebounce)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	answer, err := t.inlineAnswerer.AnswerInline(ctx, t.ChannelKey(query.From.ID), text)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	answer = t.filterOutbound(query.From.ID, answer)
+	if parts := splitTelegramMarkdown(answer, telegramChunkChars); len(parts) > 0 {
+		answer = parts[0]
+	}
+	content := &models.InputTextMessageContent{MessageText: answer}
+	if formatted, ok := formatTelegram(answer); ok {
+		content.MessageText = formatted
+		content.ParseMode = models.ParseModeHTML
+	}
+	send := t.answerInlineQuery
+	if send == nil {
+		return errors.New("telegram bot is not connected")
+	}
+	_, err = send(ctx, &bot.AnswerInlineQueryParams{
+		InlineQueryID: query.ID,
+		Results: []models.InlineQueryResult{&models.InlineQueryResultArticle{
+			ID:                  "answer",
+			Title:               messagePreview(text, telegramInlineTitleChars),
+			Description:         messagePreview(answer, telegramInlineDescriptionChars),
+			InputMessageContent: content,
+		}},
+		IsPersonal: true,
+	})
+	return err
+}
@@ -0,0 +1,79 @@
+package channels
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type inlineAnswererFunc func(ctx context.Context, chatKey, query string) (string, error)
+
+func (f inlineAnswererFunc) AnswerInline(ctx context.Context, chatKey, query string) (string, error) {
+	return f(ctx, chatKey, query)
+}
+
+func TestTelegramInlineQueryAnswersTheLatestQuery(t *testing.T) {
+	listener := NewTelegram("token", "")
+	listener.allowedTelegramUsers = map[string]struct{}{"42": {}}
+	listener.inlineDebounce = 50 * time.Millisecond
+
+	var (
+		mu    sync.Mutex
+		asked []string
+	)
+	listener.ConfigureInlineQueries(inlineAnswererFunc(func(_ context.Context, chatKey, query string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		asked = append(asked, chatKey+": "+query)
+		return "It is **sunny**.", nil
+	}))
+	answered := make(chan *bot.AnswerInlineQueryParams, 2)
+	listener.answerInlineQuery = func(_ context.Context, params *bot.AnswerInlineQueryParams) (bool, error) {
+		answered <- params
+		return true, nil
+	}
+
+	ctx := context.Background()
+	from := &models.User{ID: 42}
+	listener.handleInlineQuery(ctx, &models.InlineQuery{ID: "q1", From: from, Query: "weath"})
+	listener.handleInlineQuery(ctx, &models.InlineQuery{ID: "q2", From: from, Query: "weather today"})
+	// Strangers and blank queries get no answer.
+	listener.handleInlineQuery(ctx, &models.InlineQuery{ID: "q3", From: &models.User{ID: 7}, Query: "weather"})
+	listener.handleInlineQuery(ctx, &models.InlineQuery{ID: "q4", From: &models.User{ID: 42}, Query: "  "})
+
+	var params *bot.AnswerInlineQueryParams
+	select {
+	case params = <-answered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("inline query was not answered")
+	}
+	if params.InlineQueryID != "q2" || !params.IsPersonal || len(params.Results) != 1 {
+		t.Fatalf("expected one personal result for the latest query, got %#v", params)
+	}
+	article, ok := params.Results[0].(*models.InlineQueryResultArticle)
+	if !ok {
+		t.Fatalf("expected an article, got %T", params.Results[0])
+	}
+	content, ok := article.InputMessageContent.(*models.InputTextMessageContent)
+	if !ok || content.MessageText != "It is <b>sunny</b>." || content.ParseMode != models.ParseModeHTML {
+		t.Fatalf("expected the formatted answer as the message, got %#v", article.InputMessageContent)
+	}
+	if article.Title != "weather today" || article.Description != "It is **sunny**." {
+		t.Fatalf("unexpected article %#v", article)
+	}
+
+	select {
+	case extra := <-answered:
+		t.Fatalf("expected the superseded query to go unanswered, got %#v", extra)
+	case <-time.After(100 * time.Millisecond):
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(asked) != 1 || asked[0] != "telegram-42: weather today" {
+		t.Fatalf("expected one model call from the private chat, got %#v", asked)
+	}
+}
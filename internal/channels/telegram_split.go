@@ -0,0 +1,162 @@
+package channels
+
+import (
+	"strings"
+)
+
+// telegramChunkChars is the most Markdown sent in one Telegram message. It
+// leaves room under maxTelegramMessageChars for formatting that adds
+// characters, such as list bullets.
+const telegramChunkChars = 4000
+
+// splitTelegramMarkdown splits a reply into chunks of at most limit
+// characters, each rendered as its own message. Cuts fall between
+// paragraphs where possible, then between lines, then between words. A code
+// block that has to be cut is closed at the end of one chunk and reopened,
+// with its language, at the start of the next, so every chunk renders to
+// balanced HTML.
+func splitTelegramMarkdown(text string, limit int) []string {
+	text = strings.TrimSpace(text)
+	if len([]rune(text)) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentLen := 0
+	flush := func() {
+		if currentLen > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+	}
+	for _, block := range markdownBlocks(text) {
+		for _, piece := range splitMarkdownBlock(block, limit) {
+			pieceLen := len([]rune(piece))
+			if currentLen > 0 && currentLen+2+pieceLen > limit {
+				flush()
+			}
+			if currentLen > 0 {
+				current.WriteString("\n\n")
+				currentLen += 2
+			}
+			current.WriteString(piece)
+			currentLen += pieceLen
+		}
+	}
+	flush()
+	return chunks
+}
+
+// markdownBlock is a paragraph, or a fenced code block with its opening
+// fence line.
+type markdownBlock struct {
+	fence string
+	lines []string
+}
+
+// markdownBlocks breaks text into paragraphs and code blocks. Blank lines
+// separate paragraphs but are kept inside code blocks.
+func markdownBlocks(text string) []markdownBlock {
+	var blocks []markdownBlock
+	var current *markdownBlock
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case current != nil && current.fence != "":
+			if isClosingFence(trimmed, current.fence) {
+				current = nil
+				continue
+			}
+			current.lines = append(current.lines, line)
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			blocks = append(blocks, markdownBlock{fence: trimmed})
+			current = &blocks[len(blocks)-1]
+		case trimmed == "":
+			current = nil
+		default:
+			if current == nil {
+				blocks = append(blocks, markdownBlock{})
+				current = &blocks[len(blocks)-1]
+			}
+			current.lines = append(current.lines, line)
+		}
+	}
+	return blocks
+}
+
+// isClosingFence reports whether line closes a code block opened by fence.
+func isClosingFence(line, fence string) bool {
+	marker := fence[:3]
+	return strings.HasPrefix(line, marker) && strings.Trim(line, marker[:1]) == ""
+}
+
+// splitMarkdownBlock renders block back to Markdown in pieces of at most
+// limit characters.
+func splitMarkdownBlock(block markdownBlock, limit int) []string {
+	if block.fence == "" {
+		return packLines(block.lines, limit)
+	}
+	closing := block.fence[:3]
+	wrap := func(body string) string {
+		return block.fence + "\n" + body + "\n" + closing
+	}
+	overhead := len([]rune(wrap("")))
+	if overhead >= limit {
+		return packLines(block.lines, limit)
+	}
+	var pieces []string
+	for _, body := range packLines(block.lines, limit-overhead) {
+		pieces = append(pieces, wrap(body))
+	}
+	if len(pieces) == 0 {
+		pieces = []string{wrap("")}
+	}
+	return pieces
+}
+
+// packLines joins lines into pieces of at most limit characters, cutting
+// lines that are longer than limit on their own.
+func packLines(lines []string, limit int) []string {
+	var pieces []string
+	var current []string
+	currentLen := 0
+	for _, line := range lines {
+		for _, part := range cutLine(line, limit) {
+			partLen := len([]rune(part))
+			if len(current) > 0 && currentLen+1+partLen > limit {
+				pieces = append(pieces, strings.Join(current, "\n"))
+				current, currentLen = nil, 0
+			}
+			if len(current) > 0 {
+				currentLen++
+			}
+			current = append(current, part)
+			currentLen += partLen
+		}
+	}
+	if len(current) > 0 {
+		pieces = append(pieces, strings.Join(current, "\n"))
+	}
+	return pieces
+}
+
+// cutLine cuts line into parts of at most limit characters, at the last
+// space in the second half of each part when there is one.
+func cutLine(line string, limit int) []string {
+	runes := []rune(line)
+	var parts []string
+	for len(runes) > limit {
+		cut := limit
+		for i := limit - 1; i > limit/2; i-- {
+			if runes[i] == ' ' {
+				cut = i + 1
+				break
+			}
+		}
+		parts = append(parts, strings.TrimRight(string(runes[:cut]), " "))
+		runes = runes[cut:]
+	}
+	return append(parts, string(runes))
+}
@@ -0,0 +1,44 @@
+package channels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitTelegramMarkdown(t *testing.T) {
+	if chunks := splitTelegramMarkdown("short", 20); len(chunks) != 1 || chunks[0] != "short" {
+		t.Fatalf("unexpected chunks %q", chunks)
+	}
+
+	chunks := splitTelegramMarkdown("first paragraph\n\nsecond paragraph\n\nthird", 36)
+	if len(chunks) != 2 || chunks[0] != "first paragraph\n\nsecond paragraph" || chunks[1] != "third" {
+		t.Fatalf("expected cuts between paragraphs, got %q", chunks)
+	}
+
+	code := "intro\n\n```go\n" + strings.Repeat("fmt.Println(1)\n", 6) + "```\n\nafter"
+	chunks = splitTelegramMarkdown(code, 60)
+	for i, chunk := range chunks {
+		if len([]rune(chunk)) > 60 {
+			t.Fatalf("chunk %d is %d chars: %q", i, len([]rune(chunk)), chunk)
+		}
+		if strings.Count(chunk, "```")%2 != 0 {
+			t.Fatalf("chunk %d leaves a code block open: %q", i, chunk)
+		}
+		if strings.Contains(chunk, "Println") && !strings.Contains(chunk, "```go\n") {
+			t.Fatalf("chunk %d lost the code block language: %q", i, chunk)
+		}
+		if formatted, ok := formatTelegram(chunk); !ok || strings.Count(formatted, "<pre>") != strings.Count(formatted, "</pre>") {
+			t.Fatalf("chunk %d renders unbalanced: %q", i, formatted)
+		}
+	}
+	if len(chunks) < 3 || chunks[len(chunks)-1] != "after" {
+		t.Fatalf("unexpected chunks %q", chunks)
+	}
+
+	chunks = splitTelegramMarkdown(strings.Repeat("é ", 30), 20)
+	for _, chunk := range chunks {
+		if len([]rune(chunk)) > 20 || strings.HasSuffix(chunk, " ") {
+			t.Fatalf("expected word cuts within the limit, got %q", chunks)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+const (
+	// telegramStreamEditInterval spaces preview edits. Telegram throttles
+	// bots that edit messages in one chat much faster than once a second.
+	telegramStreamEditInterval = 1500 * time.Millisecond
+	// telegramStreamCursor marks a preview as still being written.
+	telegramStreamCursor = " ▍"
+)
+
+// telegramStream is the message a reply is previewed in while the model
+// writes it. Text that arrives between edits waits in pending and is shown
+// by the next edit.
+type telegramStream struct {
+	mu        sync.Mutex
+	messageID int
+	shown     string
+	pending   string
+	lastEdit  time.Time
+	timer     *time.Timer
+}
+
+// WriteStream shows text in the chat as the reply so far: the first call
+// sends a preview message and later calls edit it, at most once every
+// telegramStreamEditInterval. The preview is plain text; the reply is
+// formatted once WriteMessage delivers it.
+func (w *telegramWriter) WriteStream(ctx context.Context, text string) error {
+	if w == nil || w.listener == nil {
+		return errors.New("telegram sender is not configured")
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	s := &w.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = text
+	if s.messageID != 0 {
+		if wait := telegramStreamEditInterval - time.Since(s.lastEdit); wait > 0 {
+			if s.timer == nil {
+				s.timer = time.AfterFunc(wait, func() { w.flushStream(ctx) })
+			}
+			return nil
+		}
+	}
+	return w.showStreamLocked(ctx)
+}
+
+// flushStream shows text that arrived too soon after the last edit.
+func (w *telegramWriter) flushStream(ctx context.Context) {
+	s := &w.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timer = nil
+	if s.messageID == 0 || s.pending == s.shown {
+		return
+	}
+	if err := w.showStreamLocked(ctx); err != nil {
+		logging.Logger().Warn("failed to update telegram preview", "chat_id", w.chatID, "err", err)
+	}
+}
+
+// showStreamLocked sends or edits the preview to show s.pending.
+func (w *telegramWriter) showStreamLocked(ctx context.Context) error {
+	s := &w.stream
+	preview := telegramPreview(w.listener.filterOutbound(w.chatID, s.pending))
+	if s.messageID == 0 {
+		msg, err := w.listener.sendTelegramMessage(ctx, &bot.SendMessageParams{ChatID: w.chatID, Text: preview})
+		if err != nil {
+			return err
+		}
+		s.messageID = msg.ID
+	} else if s.pending != s.shown {
+		if _, err := w.listener.editTelegramMessageText(ctx, &bot.EditMessageTextParams{ChatID: w.chatID, MessageID: s.messageID, Text: preview}); err != nil {
+			return err
+		}
+	}
+	s.shown = s.pending
+	s.lastEdit = time.Now()
+	return nil
+}
+
+// finishStream turns the preview, if there is one, into the reply and
+// reports whether it did. The preview shows the first message of the reply
+// and the rest are sent after it. If the preview cannot be edited it is
+// deleted and the reply is sent as usual.
+func (w *telegramWriter) finishStream(ctx context.Context, text string) (bool, error) {
+	s := &w.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	messageID := s.messageID
+	s.messageID, s.shown, s.pending, s.lastEdit = 0, "", "", time.Time{}
+	if messageID == 0 {
+		return false, nil
+	}
+
+	chunks := w.listener.formatOutbound(w.chatID, text)
+	_, err := w.listener.editTelegramMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    w.chatID,
+		MessageID: messageID,
+		Text:      chunks[0].text,
+		ParseMode: chunks[0].parseMode,
+	})
+	if err != nil {
+		logging.Logger().Warn("failed to replace telegram preview with reply", "chat_id", w.chatID, "err", err)
+		if err := w.listener.deleteTelegramMessage(ctx, &bot.DeleteMessageParams{ChatID: w.chatID, MessageID: messageID}); err != nil {
+			logging.Logger().Warn("failed to delete telegram preview", "chat_id", w.chatID, "err", err)
+		}
+		return false, nil
+	}
+	for _, chunk := range chunks[1:] {
+		if _, err := w.listener.sendTelegramMessage(ctx, &bot.SendMessageParams{
+			ChatID:    w.chatID,
+			Text:      chunk.text,
+			ParseMode: chunk.parseMode,
+		}); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// telegramPreview cuts text to fit one message with the cursor after it.
+func telegramPreview(text string) string {
+	limit := maxTelegramMessageChars - len([]rune(telegramStreamCursor)) - 1
+	if runes := []rune(text); len(runes) > limit {
+		text = string(runes[:limit]) + "…"
+	}
+	return text + telegramStreamCursor
+}
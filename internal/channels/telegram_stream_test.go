@@ -0,0 +1,104 @@
+package channels
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func TestTelegramWriterStreamsIntoPreview(t *testing.T) {
+	listener := NewTelegram("token", "")
+	var sent []*bot.SendMessageParams
+	var edits []*bot.EditMessageTextParams
+	listener.sendMessage = func(_ context.Context, params *bot.SendMessageParams) (*models.Message, error) {
+		sent = append(sent, params)
+		return &models.Message{ID: 7}, nil
+	}
+	listener.editMessageText = func(_ context.Context, params *bot.EditMessageTextParams) (*models.Message, error) {
+		edits = append(edits, params)
+		return &models.Message{ID: params.MessageID}, nil
+	}
+	w := &telegramWriter{listener: listener, chatID: 42}
+	ctx := context.Background()
+
+	if err := w.WriteStream(ctx, "Hel"); err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	// Too soon after the preview was sent, so this waits for the next edit.
+	if err := w.WriteStream(ctx, "Hello"); err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if len(sent) != 1 || sent[0].Text != "Hel"+telegramStreamCursor || len(edits) != 0 {
+		t.Fatalf("expected one preview and no edit yet, got %d sends and %d edits", len(sent), len(edits))
+	}
+
+	if err := w.WriteMessage(ctx, "Hello **world**"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if len(sent) != 1 || len(edits) != 1 {
+		t.Fatalf("expected the reply to replace the preview, got %d sends and %d edits", len(sent), len(edits))
+	}
+	if edits[0].MessageID != 7 || edits[0].Text != "Hello <b>world</b>" || edits[0].ParseMode != models.ParseModeHTML {
+		t.Fatalf("unexpected final edit %#v", edits[0])
+	}
+
+	// With the preview used up, the next message is sent normally.
+	if err := w.WriteMessage(ctx, "Anything else?"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("expected a new message, got %d sends", len(sent))
+	}
+}
+
+func TestTelegramWriterLongReplyAfterPreview(t *testing.T) {
+	listener := NewTelegram("token", "")
+	var sent []string
+	var edited []string
+	var deleted []int
+	listener.sendMessage = func(_ context.Context, params *bot.SendMessageParams) (*models.Message, error) {
+		sent = append(sent, params.Text)
+		return &models.Message{ID: 9}, nil
+	}
+	listener.deleteMessage = func(_ context.Context, params *bot.DeleteMessageParams) (bool, error) {
+		deleted = append(deleted, params.MessageID)
+		return true, nil
+	}
+	w := &telegramWriter{listener: listener, chatID: 42}
+
+	long := strings.Repeat("word ", maxTelegramMessageChars/5+10)
+	if err := w.WriteStream(context.Background(), long); err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if got := len([]rune(sent[0])); got > maxTelegramMessageChars {
+		t.Fatalf("expected the preview cut to one message, got %d chars", got)
+	}
+
+	// The first part of the reply replaces the preview and the rest follows.
+	listener.editMessageText = func(_ context.Context, params *bot.EditMessageTextParams) (*models.Message, error) {
+		edited = append(edited, params.Text)
+		return &models.Message{ID: params.MessageID}, nil
+	}
+	if err := w.WriteMessage(context.Background(), long); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if len(edited) != 1 || len(sent) != 2 || len(deleted) != 0 {
+		t.Fatalf("expected one edit and one more send, got %d edits and %d sends", len(edited), len(sent))
+	}
+
+	// A preview that cannot be edited is deleted and the whole reply sent.
+	listener.editMessageText = nil
+	sent = nil
+	if err := w.WriteStream(context.Background(), "partial"); err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if err := w.WriteMessage(context.Background(), long); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != 9 || len(sent) != 3 {
+		t.Fatalf("expected the preview deleted and the reply sent in two parts, got deleted %v and %d sends", deleted, len(sent))
+	}
+}
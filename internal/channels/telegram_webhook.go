@@ -0,0 +1,155 @@
+package channels
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+const (
+	// DefaultTelegramWebhookListen is one of the ports Telegram delivers
+	// webhooks to.
+	DefaultTelegramWebhookListen = ":8443"
+
+	telegramSecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+	// maxWebhookBody is far above any real update; larger bodies are refused.
+	maxWebhookBody      = 1 << 20
+	webhookShutdownWait = 5 * time.Second
+)
+
+// TelegramWebhook makes Telegram push updates to NeoClaw instead of NeoClaw
+// long polling for them.
+type TelegramWebhook struct {
+	// URL is the public HTTPS address Telegram posts updates to. Its path
+	// is the path served locally.
+	URL string
+	// Listen is the local address to serve on; empty uses
+	// DefaultTelegramWebhookListen.
+	Listen string
+	// CertFile and KeyFile serve HTTPS directly. Without them the server
+	// speaks plain HTTP, for a reverse proxy that terminates TLS.
+	CertFile string
+	KeyFile  string
+}
+
+// ConfigureWebhook switches the listener to webhook mode. A zero webhook
+// keeps long polling.
+func (t *TelegramListener) ConfigureWebhook(webhook TelegramWebhook) {
+	t.webhook = webhook
+}
+
+// runWebhook registers the webhook with Telegram and serves it until ctx is
+// done. The secret is sent back by Telegram on every request, so requests
+// from anyone else are refused. An error means the webhook could not be
+// set up or its server stopped; Listen then falls back to long polling.
+func (t *TelegramListener) runWebhook(ctx context.Context, b *bot.Bot, secret string) error {
+	endpoint, err := url.Parse(t.webhook.URL)
+	if err != nil {
+		return fmt.Errorf("parse webhook url: %w", err)
+	}
+	path := endpoint.Path
+	if path == "" {
+		path = "/"
+	}
+	listen := strings.TrimSpace(t.webhook.Listen)
+	if listen == "" {
+		listen = DefaultTelegramWebhookListen
+	}
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("listen for telegram webhook on %s: %w", listen, err)
+	}
+
+	server := &http.Server{
+		Handler:           webhookHandler(path, secret, b.WebhookHandler()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		if t.webhook.CertFile != "" {
+			serveErr <- server.ServeTLS(listener, t.webhook.CertFile, t.webhook.KeyFile)
+		} else {
+			serveErr <- server.Serve(listener)
+		}
+	}()
+
+	// Register only once the server is up, so the first updates land.
+	if _, err := b.SetWebhook(ctx, &bot.SetWebhookParams{
+		URL:         t.webhook.URL,
+		SecretToken: secret,
+	}); err != nil {
+		server.Close()
+		return fmt.Errorf("set telegram webhook: %w", err)
+	}
+	logging.Logger().Info("Receiving Telegram updates by webhook", "url", t.webhook.URL, "listen", listen, "tls", t.webhook.CertFile != "")
+	// StartWebhook runs until its context ends; a server that fails must
+	// stop it too before long polling takes over.
+	webhookCtx, stopWebhook := context.WithCancel(ctx)
+	defer stopWebhook()
+	go b.StartWebhook(webhookCtx)
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownWait)
+		defer cancel()
+		// The webhook stays registered: Telegram holds updates until the
+		// server is back.
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logging.Logger().Warn("telegram webhook server shutdown failed", "err", err)
+		}
+		return nil
+	case err := <-serveErr:
+		return fmt.Errorf("telegram webhook server: %w", err)
+	}
+}
+
+// webhookHandler passes on POSTs to path that carry secret and refuses
+// everything else.
+func webhookHandler(path, secret string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(telegramSecretTokenHeader)), []byte(secret)) != 1 {
+			logging.Logger().Warn("refused telegram webhook request with a wrong secret", "remote", r.RemoteAddr)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBody)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// generateWebhookSecret makes a new secret for each start. Telegram allows
+// A-Z, a-z, 0-9, _ and - in secret tokens.
+func generateWebhookSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// deleteStaleWebhook removes a webhook left from webhook mode, since
+// Telegram refuses long polling while one is set.
+func deleteStaleWebhook(ctx context.Context, b *bot.Bot) {
+	if _, err := b.DeleteWebhook(ctx, &bot.DeleteWebhookParams{}); err != nil {
+		logging.Logger().Warn("failed to delete telegram webhook; long polling may fail", "err", err)
+	}
+}
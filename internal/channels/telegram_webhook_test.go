@@ -0,0 +1,101 @@
+package channels
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookHandlerChecksPathMethodAndSecret(t *testing.T) {
+	var delivered int
+	handler := webhookHandler("/telegram", "s3cret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered++
+	}))
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		secret string
+		want   int
+	}{
+		{"wrong path", http.MethodPost, "/other", "s3cret", http.StatusNotFound},
+		{"wrong method", http.MethodGet, "/telegram", "s3cret", http.StatusMethodNotAllowed},
+		{"missing secret", http.MethodPost, "/telegram", "", http.StatusUnauthorized},
+		{"wrong secret", http.MethodPost, "/telegram", "guess", http.StatusUnauthorized},
+		{"valid", http.MethodPost, "/telegram", "s3cret", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"update_id":1}`))
+		if tc.secret != "" {
+			req.Header.Set(telegramSecretTokenHeader, tc.secret)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("%s: expected status %d, got %d", tc.name, tc.want, rec.Code)
+		}
+	}
+	if delivered != 1 {
+		t.Fatalf("expected only the valid request to be delivered, got %d", delivered)
+	}
+}
+
+func TestListenFallsBackToLongPollingWhenWebhookCannotListen(t *testing.T) {
+	polled := make(chan struct{}, 1)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:] {
+		case "getMe":
+			w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"username":"test_bot"}}`))
+		case "getUpdates":
+			select {
+			case polled <- struct{}{}:
+			default:
+			}
+			w.Write([]byte(`{"ok":true,"result":[]}`))
+		default:
+			w.Write([]byte(`{"ok":true,"result":true}`))
+		}
+	}))
+	defer api.Close()
+
+	// Hold the webhook's address so the webhook server cannot start.
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer taken.Close()
+
+	listener := NewTelegram("123:abc", filepath.Join(t.TempDir(), "allowed_users.json"))
+	listener.apiURL = api.URL
+	listener.ConfigureWebhook(TelegramWebhook{URL: "https://bot.example.com/telegram", Listen: taken.Addr().String()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- listener.Listen(ctx, &testHandler{})
+	}()
+
+	select {
+	case <-polled:
+	case err := <-done:
+		t.Fatalf("listen returned before polling: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected long polling after the webhook failed")
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("listen did not stop")
+	}
+}
@@ -0,0 +1,470 @@
+// Package webui serves a minimal browser chat with the agent: an embedded
+// page that talks to the listener over a WebSocket, with approval prompts
+// shown as buttons.
+package webui
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/channels"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/redact"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+)
+
+const (
+	// Channel names the web chat in scheduler channel keys and is the user
+	// ID of its messages. Every open tab is the same user.
+	Channel = "web"
+	// DefaultListen keeps the page on the local machine unless configured
+	// otherwise.
+	DefaultListen = "127.0.0.1:8788"
+
+	// maxFrame bounds one message from the browser.
+	maxFrame = 1 << 20
+	// socketBuffer is how many frames a slow tab may fall behind by before
+	// it is disconnected.
+	socketBuffer = 64
+	writeTimeout = 10 * time.Second
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+var _ runtime.Listener = (*Listener)(nil)
+var _ approval.Approver = (*Listener)(nil)
+
+// Frame is one JSON message on the socket. The browser sends "message"
+// with Text and "approval" with ID and Approve. The server sends
+// "message", "approval" prompts, "approval_closed" with Status, "error",
+// and "done" when a turn ends.
+type Frame struct {
+	Type    string `json:"type"`
+	Text    string `json:"text,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Tool    string `json:"tool,omitempty"`
+	Approve bool   `json:"approve,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+// Listener serves the chat page at / and its WebSocket at /ws. Both need
+// the access token, which the page reads from its URL fragment.
+type Listener struct {
+	channels.Base
+
+	listen string
+	token  string
+	// generated is set when token was made up on start rather than
+	// configured.
+	generated bool
+	// linkOut receives the link with the token, printed once on start and
+	// never logged.
+	linkOut io.Writer
+
+	mu      sync.Mutex
+	sockets map[*socket]struct{}
+	busy    bool
+	pending map[string]chan approval.ApprovalDecision
+}
+
+// socket is one open browser tab.
+type socket struct {
+	frames chan Frame
+	// closed is closed when the tab fell too far behind.
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a web chat listener on listen (DefaultListen if empty). An
+// empty token generates a new one, shown in the link printed on stderr at
+// start.
+func New(listen, token string) (*Listener, error) {
+	if strings.TrimSpace(listen) == "" {
+		listen = DefaultListen
+	}
+	token = strings.TrimSpace(token)
+	generated := token == ""
+	if generated {
+		id, err := newID("")
+		if err != nil {
+			return nil, fmt.Errorf("generate web token: %w", err)
+		}
+		token = id
+	}
+	return &Listener{
+		Base:      channels.NewBase(),
+		listen:    listen,
+		token:     token,
+		generated: generated,
+		linkOut:   os.Stderr,
+		sockets:   make(map[*socket]struct{}),
+		pending:   make(map[string]chan approval.ApprovalDecision),
+	}, nil
+}
+
+// ChannelKey returns the scheduler channel key of the web chat.
+func (l *Listener) ChannelKey() string {
+	return Channel
+}
+
+// Listen serves the page until ctx is cancelled.
+func (l *Listener) Listen(ctx context.Context, handler runtime.Handler) error {
+	if handler == nil {
+		return errors.New("handler is required")
+	}
+	ln, err := net.Listen("tcp", l.listen)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", l.listen, err)
+	}
+
+	dispatchCtx, cancelDispatch := context.WithCancel(ctx)
+	defer cancelDispatch()
+	dispatcher := runtime.NewDispatcher(&turnHandler{listener: l, handler: handler}, l.QueueSize)
+	if err := dispatcher.Start(dispatchCtx); err != nil {
+		ln.Close()
+		return err
+	}
+	defer dispatcher.Wait()
+	defer dispatcher.Stop()
+
+	server := &http.Server{
+		Handler:           l.routes(dispatchCtx, dispatcher),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	addr := displayAddr(ln.Addr())
+	logging.Logger().Info("Web chat started", "address", addr, "generated_token", l.generated)
+	fmt.Fprintf(l.linkOut, "Web chat at http://%s/#token=%s\n", addr, l.token)
+	if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve web chat: %w", err)
+	}
+	return ctx.Err()
+}
+
+// displayAddr turns a wildcard listen address into one a browser can open.
+func displayAddr(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+func (l *Listener) routes(ctx context.Context, dispatcher *runtime.Dispatcher) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'")
+		w.Header().Set("X-Frame-Options", "DENY")
+		_, _ = w.Write(indexHTML)
+	})
+	mux.HandleFunc("GET /ws", func(w http.ResponseWriter, r *http.Request) {
+		l.serveSocket(ctx, dispatcher, w, r)
+	})
+	return mux
+}
+
+// serveSocket runs one browser tab. Accept rejects cross-origin pages, and
+// the token keeps out other local users and processes.
+func (l *Listener) serveSocket(ctx context.Context, dispatcher *runtime.Dispatcher, w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(l.token)) != 1 {
+		http.Error(w, "a valid token is required", http.StatusUnauthorized)
+		return
+	}
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(maxFrame)
+
+	s := &socket{frames: make(chan Frame, socketBuffer), closed: make(chan struct{})}
+	l.mu.Lock()
+	l.sockets[s] = struct{}{}
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.sockets, s)
+		l.mu.Unlock()
+	}()
+
+	connCtx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			select {
+			case <-connCtx.Done():
+				return
+			case <-ctx.Done():
+				return
+			case <-s.closed:
+				conn.Close(websocket.StatusPolicyViolation, "too far behind")
+				return
+			case frame := <-s.frames:
+				data, err := json.Marshal(frame)
+				if err != nil {
+					continue
+				}
+				writeCtx, cancelWrite := context.WithTimeout(connCtx, writeTimeout)
+				err = conn.Write(writeCtx, websocket.MessageText, data)
+				cancelWrite()
+				if err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.Read(connCtx)
+		if err != nil {
+			return
+		}
+		var frame Frame
+		if err := json.Unmarshal(data, &frame); err != nil {
+			continue
+		}
+		switch frame.Type {
+		case "message":
+			l.handleMessage(connCtx, dispatcher, s, strings.TrimSpace(frame.Text))
+		case "approval":
+			l.answerApproval(frame.ID, frame.Approve)
+		}
+	}
+}
+
+func (l *Listener) handleMessage(ctx context.Context, dispatcher *runtime.Dispatcher, s *socket, text string) {
+	if text == "" {
+		return
+	}
+	logging.Logger().Info("web inbound message", "text", preview(text, 100))
+	if err := dispatcher.Enqueue(ctx, &runtime.Message{Text: text, UserID: Channel}, &responseWriter{listener: l}); err != nil {
+		logging.Logger().Warn("web enqueue failed", "err", err)
+		s.send(Frame{Type: "error", Text: "The agent is not accepting messages."})
+	}
+}
+
+func (s *socket) send(frame Frame) bool {
+	select {
+	case s.frames <- frame:
+		return true
+	default:
+		s.closeOnce.Do(func() { close(s.closed) })
+		return false
+	}
+}
+
+// broadcast sends frame to every open tab and reports how many got it.
+func (l *Listener) broadcast(frame Frame) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delivered := 0
+	for s := range l.sockets {
+		if s.send(frame) {
+			delivered++
+		}
+	}
+	return delivered
+}
+
+// broadcastText shows a reply after applying the outbound secrets filter.
+func (l *Listener) broadcastText(text string) int {
+	text, found := redact.FilterSecrets(text, l.OutboundSecrets)
+	if len(found) > 0 {
+		logging.Logger().Warn("outbound web message contained credentials", "kinds", strings.Join(found, ", "), "mode", l.OutboundSecrets)
+	}
+	return l.broadcast(Frame{Type: "message", Text: text})
+}
+
+// RequestApproval shows Approve and Deny buttons in every open tab and
+// waits for the first answer.
+func (l *Listener) RequestApproval(ctx context.Context, req approval.ApprovalRequest) (approval.ApprovalDecision, error) {
+	if ctx.Err() != nil {
+		return approval.Denied, nil
+	}
+	id, err := newID("apr_")
+	if err != nil {
+		return approval.Denied, fmt.Errorf("generate approval id: %w", err)
+	}
+	prompt := strings.TrimSpace(req.Description)
+	if prompt == "" {
+		prompt = fmt.Sprintf("Approve %s?", strings.TrimSpace(req.Tool))
+	}
+	response := make(chan approval.ApprovalDecision, 1)
+	l.mu.Lock()
+	l.pending[id] = response
+	l.mu.Unlock()
+	defer l.takePending(id)
+	if l.broadcast(Frame{Type: "approval", ID: id, Tool: req.Tool, Text: prompt}) == 0 {
+		return approval.Denied, fmt.Errorf("approval for %s needs the web chat to be open", req.Tool)
+	}
+
+	var expired <-chan time.Time
+	if l.ApprovalTimeout > 0 {
+		timer := time.NewTimer(l.ApprovalTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case decision := <-response:
+		return decision, nil
+	case <-expired:
+		if l.takePending(id) {
+			l.broadcast(Frame{Type: "approval_closed", ID: id, Status: fmt.Sprintf("⌛ Expired: no answer within %s", l.ApprovalTimeout)})
+		}
+		return approval.Denied, fmt.Errorf("approval for %s timed out: the user did not answer within %s", req.Tool, l.ApprovalTimeout)
+	case <-ctx.Done():
+		if l.takePending(id) {
+			l.broadcast(Frame{Type: "approval_closed", ID: id, Status: "⌛ Expired: the request was cancelled"})
+		}
+		return approval.Denied, nil
+	}
+}
+
+// answerApproval resolves a prompt; later answers from other tabs are
+// ignored.
+func (l *Listener) answerApproval(id string, approve bool) {
+	l.mu.Lock()
+	response, ok := l.pending[id]
+	delete(l.pending, id)
+	l.mu.Unlock()
+	if !ok {
+		return
+	}
+	decision, status := approval.Denied, "❌ Denied"
+	if approve {
+		decision, status = approval.Approved, "✅ Approved"
+	}
+	l.broadcast(Frame{Type: "approval_closed", ID: id, Status: status})
+	response <- decision
+}
+
+func (l *Listener) takePending(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.pending[id]
+	delete(l.pending, id)
+	return ok
+}
+
+// ApproverName names who answers approval prompts.
+func (l *Listener) ApproverName() string {
+	return "web chat"
+}
+
+// Send shows a channel message in the open tabs.
+func (l *Listener) Send(_ context.Context, message string) error {
+	l.broadcastText(message)
+	return nil
+}
+
+// CurrentChannelID returns the scheduler channel key while a turn runs.
+func (l *Listener) CurrentChannelID() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.busy {
+		return ""
+	}
+	return Channel
+}
+
+// ChannelWriter returns an io.Writer that shows scheduler messages in the
+// open tabs.
+func (l *Listener) ChannelWriter() io.Writer {
+	return channelWriter{listener: l}
+}
+
+type channelWriter struct {
+	listener *Listener
+}
+
+func (w channelWriter) Write(p []byte) (int, error) {
+	text := strings.TrimSpace(string(p))
+	if text == "" {
+		return len(p), nil
+	}
+	if w.listener.broadcastText(text) == 0 {
+		return 0, errors.New("the web chat is not open")
+	}
+	return len(p), nil
+}
+
+// responseWriter shows replies to the message being handled.
+type responseWriter struct {
+	listener *Listener
+}
+
+func (w *responseWriter) WriteMessage(_ context.Context, text string) error {
+	w.listener.broadcastText(text)
+	return nil
+}
+
+// turnHandler marks the running turn for CurrentChannelID and tells the
+// tabs when it ends.
+type turnHandler struct {
+	listener *Listener
+	handler  runtime.Handler
+}
+
+func (h *turnHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	h.listener.setBusy(true)
+	defer h.listener.setBusy(false)
+	err := h.handler.HandleMessage(ctx, w, msg)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		// The dispatcher reports errors after the handler returns, which
+		// would be after done; report it here instead.
+		logging.Logger().Error("message handling failed", "err", err)
+		h.listener.broadcast(Frame{Type: "error", Text: "There was an error with your request. Check server logs for details"})
+		err = nil
+	}
+	h.listener.broadcast(Frame{Type: "done"})
+	return err
+}
+
+func (l *Listener) setBusy(busy bool) {
+	l.mu.Lock()
+	l.busy = busy
+	l.mu.Unlock()
+}
+
+func newID(prefix string) (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(raw), nil
+}
+
+func preview(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
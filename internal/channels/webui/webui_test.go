@@ -0,0 +1,182 @@
+package webui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+)
+
+const testToken = "web-0123456789abcdef0123456789"
+
+// approvingHandler asks for approval and replies with the decision.
+type approvingHandler struct {
+	approver approval.Approver
+}
+
+func (h approvingHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	decision, err := h.approver.RequestApproval(ctx, approval.ApprovalRequest{Tool: "run_command", Description: "Run: make test"})
+	if err != nil {
+		return err
+	}
+	if decision == approval.Approved {
+		return w.WriteMessage(ctx, "tests passed for "+msg.UserID)
+	}
+	return w.WriteMessage(ctx, "skipped")
+}
+
+func startServer(t *testing.T) (*Listener, *httptest.Server) {
+	t.Helper()
+	listener, err := New("", testToken)
+	if err != nil {
+		t.Fatalf("new listener: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher := runtime.NewDispatcher(&turnHandler{listener: listener, handler: approvingHandler{approver: listener}}, 4)
+	if err := dispatcher.Start(ctx); err != nil {
+		t.Fatalf("start dispatcher: %v", err)
+	}
+	server := httptest.NewServer(listener.routes(ctx, dispatcher))
+	t.Cleanup(func() {
+		cancel()
+		server.Close()
+		dispatcher.Wait()
+	})
+	return listener, server
+}
+
+func dial(t *testing.T, server *httptest.Server, token string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws?token="+token, nil)
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) Frame {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	var frame Frame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		t.Fatalf("decode frame: %v", err)
+	}
+	return frame
+}
+
+func writeFrame(t *testing.T, conn *websocket.Conn, frame Frame) {
+	t.Helper()
+	data, err := json.Marshal(frame)
+	if err != nil {
+		t.Fatalf("encode frame: %v", err)
+	}
+	if err := conn.Write(context.Background(), websocket.MessageText, data); err != nil {
+		t.Fatalf("write frame: %v", err)
+	}
+}
+
+func TestPageIsServed(t *testing.T) {
+	_, server := startServer(t)
+	resp, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatalf("get page: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Fatalf("expected the chat page, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestListenPrintsLinkWithoutLoggingToken(t *testing.T) {
+	listener, err := New("127.0.0.1:0", testToken)
+	if err != nil {
+		t.Fatalf("new listener: %v", err)
+	}
+	linkReader, linkWriter := io.Pipe()
+	listener.linkOut = linkWriter
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- listener.Listen(ctx, approvingHandler{approver: listener}) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	link, err := bufio.NewReader(linkReader).ReadString('\n')
+	if err != nil {
+		t.Fatalf("read link: %v", err)
+	}
+	if !strings.Contains(link, "/#token="+testToken) {
+		t.Fatalf("expected the link with the token, got %q", link)
+	}
+	for _, line := range logging.Recent() {
+		if strings.Contains(line, testToken) {
+			t.Fatalf("token was logged: %s", line)
+		}
+	}
+}
+
+func TestSocketNeedsToken(t *testing.T) {
+	_, server := startServer(t)
+	conn, resp, err := dial(t, server, "wrong")
+	if err == nil {
+		conn.CloseNow()
+		t.Fatal("expected the socket to be refused")
+	}
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %#v", resp)
+	}
+}
+
+func TestMessageApprovalReplyAndDone(t *testing.T) {
+	listener, server := startServer(t)
+	if _, err := listener.ChannelWriter().Write([]byte("Daily briefing")); err == nil {
+		t.Fatal("expected an error with no tab open")
+	}
+
+	conn, _, err := dial(t, server, testToken)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.CloseNow()
+
+	writeFrame(t, conn, Frame{Type: "message", Text: "run the tests"})
+	prompt := readFrame(t, conn)
+	if prompt.Type != "approval" || prompt.Text != "Run: make test" || prompt.Tool != "run_command" || prompt.ID == "" {
+		t.Fatalf("expected an approval prompt, got %#v", prompt)
+	}
+	if got := listener.CurrentChannelID(); got != Channel {
+		t.Fatalf("expected the running turn to be on %q, got %q", Channel, got)
+	}
+
+	writeFrame(t, conn, Frame{Type: "approval", ID: prompt.ID, Approve: true})
+	if closed := readFrame(t, conn); closed.Type != "approval_closed" || closed.ID != prompt.ID || !strings.Contains(closed.Status, "Approved") {
+		t.Fatalf("expected the prompt to close as approved, got %#v", closed)
+	}
+	if reply := readFrame(t, conn); reply.Type != "message" || reply.Text != "tests passed for "+Channel {
+		t.Fatalf("unexpected reply %#v", reply)
+	}
+	if done := readFrame(t, conn); done.Type != "done" {
+		t.Fatalf("expected done, got %#v", done)
+	}
+
+	if _, err := listener.ChannelWriter().Write([]byte("Daily briefing\n")); err != nil {
+		t.Fatalf("write scheduled message: %v", err)
+	}
+	if scheduled := readFrame(t, conn); scheduled.Type != "message" || scheduled.Text != "Daily briefing" {
+		t.Fatalf("unexpected scheduled message %#v", scheduled)
+	}
+}
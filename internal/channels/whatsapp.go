@@ -0,0 +1,574 @@
+package channels
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/redact"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+)
+
+const (
+	// WhatsAppChannel is the allowlist channel of WhatsApp users.
+	WhatsAppChannel = "whatsapp"
+	// DefaultWhatsAppListen is where the webhook is served when no listen
+	// address is set. A reverse proxy is expected to expose it over HTTPS.
+	DefaultWhatsAppListen = "127.0.0.1:8789"
+	// WhatsAppWebhookPath is the path the webhook is served on.
+	WhatsAppWebhookPath = "/whatsapp"
+	// DefaultWhatsAppTemplateLanguage is used for a template without a
+	// language.
+	DefaultWhatsAppTemplateLanguage = "en_US"
+
+	whatsappSignatureHeader = "X-Hub-Signature-256"
+	whatsappApprovePrefix   = "approval:ok:"
+	whatsappDenyPrefix      = "approval:no:"
+
+	// whatsappWindow is how long after a user's last message free-form
+	// messages may be sent to them. Later ones need an approved template.
+	whatsappWindow = 24 * time.Hour
+	// maxWhatsAppText is the longest text message the Cloud API accepts.
+	maxWhatsAppText = 4096
+	// maxWhatsAppButtonBody is the longest body of a message with buttons.
+	maxWhatsAppButtonBody = 1024
+	// maxWhatsAppTemplateParam keeps template parameters under the API
+	// limit.
+	maxWhatsAppTemplateParam = 1000
+)
+
+var _ runtime.Listener = (*WhatsAppListener)(nil)
+var _ approval.Approver = (*WhatsAppListener)(nil)
+
+// WhatsAppTemplate is an approved message template with one body
+// parameter, used for messages outside the 24-hour customer service window.
+type WhatsAppTemplate struct {
+	Name     string
+	Language string
+}
+
+// WhatsAppListener receives WhatsApp messages from the Cloud API webhook
+// and answers allowlisted users. Approvals are asked with reply buttons.
+type WhatsAppListener struct {
+	Base
+	allowlist
+
+	api         whatsappAPI
+	listen      string
+	verifyToken string
+	appSecret   string
+	template    WhatsAppTemplate
+
+	windowMu sync.Mutex
+	// lastInbound is when each user last wrote, which opens the window for
+	// free-form messages to them.
+	lastInbound map[string]time.Time
+
+	approvalMu       sync.Mutex
+	activeUser       string
+	pendingApprovals map[string]whatsappPendingApproval
+}
+
+type whatsappPendingApproval struct {
+	tool     string
+	userID   string
+	response chan approval.ApprovalDecision
+}
+
+// WhatsAppConfig holds the Cloud API settings of a WhatsApp listener.
+type WhatsAppConfig struct {
+	// PhoneNumberID is the business number messages are sent from; Token
+	// is its access token.
+	PhoneNumberID string
+	Token         string
+	// AppSecret checks the signature of webhook deliveries, and
+	// VerifyToken answers Meta's verification request for the webhook URL.
+	AppSecret   string
+	VerifyToken string
+	// Listen is the local address of the webhook; empty uses
+	// DefaultWhatsAppListen.
+	Listen string
+}
+
+// NewWhatsApp creates a WhatsApp listener.
+func NewWhatsApp(cfg WhatsAppConfig, allowedUsersPath string) *WhatsAppListener {
+	listen := strings.TrimSpace(cfg.Listen)
+	if listen == "" {
+		listen = DefaultWhatsAppListen
+	}
+	return &WhatsAppListener{
+		Base:             NewBase(),
+		allowlist:        newAllowlist(WhatsAppChannel, allowedUsersPath),
+		api:              whatsappAPI{phoneNumberID: strings.TrimSpace(cfg.PhoneNumberID), token: strings.TrimSpace(cfg.Token)},
+		listen:           listen,
+		verifyToken:      cfg.VerifyToken,
+		appSecret:        cfg.AppSecret,
+		lastInbound:      make(map[string]time.Time),
+		pendingApprovals: make(map[string]whatsappPendingApproval),
+	}
+}
+
+// ConfigureTemplate sets the template sent instead of a text message when
+// the user has not written in the last 24 hours. Without one such messages
+// are attempted as text and usually refused by WhatsApp.
+func (w *WhatsAppListener) ConfigureTemplate(template WhatsAppTemplate) {
+	template.Name = strings.TrimSpace(template.Name)
+	if strings.TrimSpace(template.Language) == "" {
+		template.Language = DefaultWhatsAppTemplateLanguage
+	}
+	w.template = template
+}
+
+// ChannelKey returns the scheduler channel key for one WhatsApp user.
+func (w *WhatsAppListener) ChannelKey(userID string) string {
+	return WhatsAppChannel + "-" + userID
+}
+
+// Listen serves the webhook and dispatches messages from allowlisted users.
+func (w *WhatsAppListener) Listen(ctx context.Context, handler runtime.Handler) error {
+	if handler == nil {
+		return errors.New("handler is required")
+	}
+	if w.api.phoneNumberID == "" || w.api.token == "" || w.appSecret == "" || w.verifyToken == "" {
+		return errors.New("whatsapp phone_number_id, token, app_secret, and verify_token are required")
+	}
+	if err := w.loadAllowedUsers(); err != nil {
+		return err
+	}
+	if len(w.allowedUsers) == 0 {
+		logging.Logger().Warn("No authorized WhatsApp users. Run claw pair --bot whatsapp to authorize your number.")
+	}
+	number, err := w.api.phoneNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("check whatsapp access token: %w", err)
+	}
+	logging.Logger().Info(fmt.Sprintf("Connected to WhatsApp as %s", number))
+
+	dispatchCtx, cancelDispatch := context.WithCancel(ctx)
+	defer cancelDispatch()
+	dispatcher := runtime.NewDispatcher(&whatsappApprovalHandler{listener: w, handler: handler}, w.QueueSize)
+	if err := dispatcher.Start(dispatchCtx); err != nil {
+		return err
+	}
+	defer dispatcher.Wait()
+	defer dispatcher.Stop()
+
+	// Messages are queued apart from the webhook so a full dispatch queue
+	// never holds up button replies, which the running turn may wait on.
+	inbound := make(chan whatsappMessage, w.QueueSize)
+	go func() {
+		for message := range inbound {
+			w.handleInboundMessage(dispatchCtx, dispatcher, message)
+		}
+	}()
+	defer close(inbound)
+
+	return serveWhatsAppWebhook(ctx, w.listen, w.verifyToken, w.appSecret, func(ctx context.Context, message whatsappMessage) {
+		if message.Type == "interactive" {
+			w.handleButtonReply(ctx, message)
+			return
+		}
+		select {
+		case inbound <- message:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// handleInboundMessage dispatches text messages from allowlisted users.
+func (w *WhatsAppListener) handleInboundMessage(ctx context.Context, dispatcher *runtime.Dispatcher, message whatsappMessage) {
+	if message.Type != "text" {
+		return
+	}
+	logging.Logger().Info(
+		"whatsapp inbound message",
+		"user_id", message.From,
+		"text", messagePreview(message.Text.Body, 100),
+	)
+	if !w.isAllowedUser(message.From) {
+		return
+	}
+	w.windowMu.Lock()
+	w.lastInbound[message.From] = time.Now()
+	w.windowMu.Unlock()
+
+	text := strings.TrimSpace(message.Text.Body)
+	if text == "" {
+		return
+	}
+	writer := &whatsappWriter{listener: w, userID: message.From}
+	if err := dispatcher.Enqueue(ctx, &runtime.Message{Text: text, UserID: message.From}, writer); err != nil {
+		logging.Logger().Warn("whatsapp enqueue failed", "user_id", message.From, "err", err)
+	}
+}
+
+// RequestApproval sends Approve/Deny reply buttons to the user whose message
+// is being handled and waits for them to tap one. A prompt too long for a
+// button message is sent in full first, so nothing is approved unseen.
+func (w *WhatsAppListener) RequestApproval(ctx context.Context, req approval.ApprovalRequest) (approval.ApprovalDecision, error) {
+	if ctx.Err() != nil {
+		return approval.Denied, nil
+	}
+	userID, ok := w.activeUserSnapshot()
+	if !ok {
+		return approval.Denied, errors.New("whatsapp approval target is unavailable")
+	}
+	token, err := generateApprovalToken()
+	if err != nil {
+		return approval.Denied, fmt.Errorf("generate approval token: %w", err)
+	}
+
+	prompt := approvalPrompt(req)
+	body := prompt
+	if len([]rune(prompt)) > maxWhatsAppButtonBody {
+		if err := w.sendText(ctx, userID, prompt); err != nil {
+			return approval.Denied, fmt.Errorf("send approval prompt: %w", err)
+		}
+		body = "Approve the request above?"
+	}
+	pending := whatsappPendingApproval{
+		tool:     req.Tool,
+		userID:   userID,
+		response: make(chan approval.ApprovalDecision, 1),
+	}
+	w.approvalMu.Lock()
+	w.pendingApprovals[token] = pending
+	w.approvalMu.Unlock()
+	defer w.takePendingApproval(token)
+
+	if err := w.api.send(ctx, userID, "interactive", whatsappApprovalButtons(body, token)); err != nil {
+		return approval.Denied, fmt.Errorf("send approval prompt: %w", err)
+	}
+
+	var expired <-chan time.Time
+	if w.ApprovalTimeout > 0 {
+		timer := time.NewTimer(w.ApprovalTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case decision := <-pending.response:
+		return decision, nil
+	case <-expired:
+		// Sent messages cannot be edited, so the expiry is a new message.
+		if _, ok := w.takePendingApproval(token); ok {
+			if err := w.sendText(context.Background(), userID, fmt.Sprintf("⌛ Expired: no answer within %s", w.ApprovalTimeout)); err != nil {
+				logging.Logger().Warn("failed to report expired whatsapp approval", "tool", req.Tool, "err", err)
+			}
+		}
+		return approval.Denied, fmt.Errorf("approval for %s timed out: the user did not answer within %s", req.Tool, w.ApprovalTimeout)
+	case <-ctx.Done():
+		return approval.Denied, nil
+	}
+}
+
+// ApproverName names the WhatsApp user who answers approval prompts.
+func (w *WhatsAppListener) ApproverName() string {
+	userID, ok := w.activeUserSnapshot()
+	if !ok {
+		return WhatsAppChannel
+	}
+	return "whatsapp user " + userID
+}
+
+func whatsappApprovalButtons(body, token string) map[string]any {
+	button := func(id, title string) map[string]any {
+		return map[string]any{"type": "reply", "reply": map[string]any{"id": id, "title": title}}
+	}
+	return map[string]any{
+		"type": "button",
+		"body": map[string]any{"text": body},
+		"action": map[string]any{"buttons": []any{
+			button(whatsappApprovePrefix+token, "✅ Approve"),
+			button(whatsappDenyPrefix+token, "❌ Deny"),
+		}},
+	}
+}
+
+// handleButtonReply answers an approval prompt. Replies from anyone other
+// than the user who was asked are ignored.
+func (w *WhatsAppListener) handleButtonReply(_ context.Context, message whatsappMessage) {
+	if message.Interactive.Type != "button_reply" {
+		return
+	}
+	id := message.Interactive.ButtonReply.ID
+	decision := approval.Denied
+	token := parseApprovalToken(id, whatsappDenyPrefix)
+	if approveToken := parseApprovalToken(id, whatsappApprovePrefix); approveToken != "" {
+		decision, token = approval.Approved, approveToken
+	}
+	if token == "" {
+		return
+	}
+
+	w.approvalMu.Lock()
+	pending, ok := w.pendingApprovals[token]
+	if !ok || pending.userID != message.From {
+		w.approvalMu.Unlock()
+		return
+	}
+	delete(w.pendingApprovals, token)
+	w.approvalMu.Unlock()
+
+	select {
+	case pending.response <- decision:
+	default:
+	}
+}
+
+func (w *WhatsAppListener) takePendingApproval(token string) (whatsappPendingApproval, bool) {
+	w.approvalMu.Lock()
+	defer w.approvalMu.Unlock()
+	pending, ok := w.pendingApprovals[token]
+	delete(w.pendingApprovals, token)
+	return pending, ok
+}
+
+func (w *WhatsAppListener) setActiveUser(userID string) {
+	w.approvalMu.Lock()
+	defer w.approvalMu.Unlock()
+	w.activeUser = userID
+}
+
+func (w *WhatsAppListener) activeUserSnapshot() (string, bool) {
+	w.approvalMu.Lock()
+	defer w.approvalMu.Unlock()
+	return w.activeUser, w.activeUser != ""
+}
+
+// inWindow reports whether userID wrote in the last 24 hours, as far as
+// this process knows.
+func (w *WhatsAppListener) inWindow(userID string) bool {
+	w.windowMu.Lock()
+	defer w.windowMu.Unlock()
+	last, ok := w.lastInbound[userID]
+	return ok && time.Since(last) < whatsappWindow
+}
+
+// sendText sends text, with credentials filtered, to userID. Long text is
+// split into several messages. Outside the 24-hour window the configured
+// template carries the text instead, flattened to one line and shortened.
+func (w *WhatsAppListener) sendText(ctx context.Context, userID, text string) error {
+	text, found := redact.FilterSecrets(text, w.OutboundSecrets)
+	if len(found) > 0 {
+		logging.Logger().Warn("outbound whatsapp message contained credentials", "user_id", userID, "kinds", strings.Join(found, ", "), "mode", w.OutboundSecrets)
+	}
+	if w.template.Name != "" && !w.inWindow(userID) {
+		return w.sendTemplate(ctx, userID, text)
+	}
+	for _, chunk := range splitWhatsAppText(text, maxWhatsAppText) {
+		err := w.api.send(ctx, userID, "text", map[string]any{"body": chunk})
+		var apiErr *whatsappAPIError
+		if errors.As(err, &apiErr) && apiErr.Code == whatsappReengagementErr && w.template.Name != "" {
+			return w.sendTemplate(ctx, userID, text)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendTemplate sends text as the body parameter of the configured template.
+func (w *WhatsAppListener) sendTemplate(ctx context.Context, userID, text string) error {
+	// Template parameters may not contain newlines, tabs, or runs of
+	// spaces.
+	param := strings.Join(strings.Fields(text), " ")
+	if runes := []rune(param); len(runes) > maxWhatsAppTemplateParam {
+		param = string(runes[:maxWhatsAppTemplateParam-1]) + "…"
+	}
+	return w.api.send(ctx, userID, "template", map[string]any{
+		"name":     w.template.Name,
+		"language": map[string]any{"code": w.template.Language},
+		"components": []any{map[string]any{
+			"type":       "body",
+			"parameters": []any{map[string]any{"type": "text", "text": param}},
+		}},
+	})
+}
+
+// splitWhatsAppText cuts text into pieces of at most limit runes, at a line
+// break where one is close enough.
+func splitWhatsAppText(text string, limit int) []string {
+	var chunks []string
+	runes := []rune(text)
+	for len(runes) > limit {
+		cut := limit
+		for i := limit - 1; i > limit/2; i-- {
+			if runes[i] == '\n' {
+				cut = i + 1
+				break
+			}
+		}
+		chunks = append(chunks, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	return append(chunks, string(runes))
+}
+
+// Send delivers a channel message to the user whose request is being
+// handled.
+func (w *WhatsAppListener) Send(ctx context.Context, message string) error {
+	userID, ok := w.activeUserSnapshot()
+	if !ok {
+		return errors.New("whatsapp chat target is unavailable")
+	}
+	return w.sendText(ctx, userID, message)
+}
+
+// CurrentChannelID returns the scheduler channel key of the user whose
+// request is being handled.
+func (w *WhatsAppListener) CurrentChannelID() string {
+	userID, ok := w.activeUserSnapshot()
+	if !ok {
+		return ""
+	}
+	return w.ChannelKey(userID)
+}
+
+type whatsappWriter struct {
+	listener *WhatsAppListener
+	userID   string
+}
+
+func (w *whatsappWriter) WriteMessage(ctx context.Context, text string) error {
+	if w == nil || w.listener == nil {
+		return errors.New("whatsapp sender is not configured")
+	}
+	return w.listener.sendText(ctx, w.userID, text)
+}
+
+type whatsappChannelWriter struct {
+	listener *WhatsAppListener
+	userID   string
+}
+
+func (w whatsappChannelWriter) Write(p []byte) (int, error) {
+	text := strings.TrimSpace(string(p))
+	if text == "" {
+		return len(p), nil
+	}
+	if err := w.listener.sendText(context.Background(), w.userID, text); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ChannelWriter returns an io.Writer that delivers scheduler messages to a
+// WhatsApp user.
+func (w *WhatsAppListener) ChannelWriter(userID string) io.Writer {
+	return whatsappChannelWriter{listener: w, userID: userID}
+}
+
+type whatsappApprovalHandler struct {
+	listener *WhatsAppListener
+	handler  runtime.Handler
+}
+
+func (h *whatsappApprovalHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	if writer, ok := w.(*whatsappWriter); ok {
+		h.listener.setActiveUser(writer.userID)
+		defer h.listener.setActiveUser("")
+	}
+	return h.handler.HandleMessage(ctx, w, msg)
+}
+
+// serveWhatsAppWebhook serves the webhook on listen until ctx is done and
+// passes each delivered message to handle.
+func serveWhatsAppWebhook(ctx context.Context, listen, verifyToken, appSecret string, handle func(context.Context, whatsappMessage)) error {
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("listen for whatsapp webhook on %s: %w", listen, err)
+	}
+	server := &http.Server{
+		Handler:           whatsappWebhookHandler(ctx, verifyToken, appSecret, handle),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(listener) }()
+	logging.Logger().Info("Receiving WhatsApp messages by webhook", "listen", listen, "path", WhatsAppWebhookPath)
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownWait)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logging.Logger().Warn("whatsapp webhook server shutdown failed", "err", err)
+		}
+		return nil
+	case err := <-serveErr:
+		return fmt.Errorf("whatsapp webhook server: %w", err)
+	}
+}
+
+// whatsappWebhookHandler answers Meta's verification request and accepts
+// deliveries signed with appSecret. Everything else is refused.
+func whatsappWebhookHandler(ctx context.Context, verifyToken, appSecret string, handle func(context.Context, whatsappMessage)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != WhatsAppWebhookPath {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+			if query.Get("hub.mode") != "subscribe" || subtle.ConstantTimeCompare([]byte(query.Get("hub.verify_token")), []byte(verifyToken)) != 1 {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			io.WriteString(w, query.Get("hub.challenge"))
+		case http.MethodPost:
+			raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+			if err != nil {
+				http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			if !validWhatsAppSignature(raw, r.Header.Get(whatsappSignatureHeader), appSecret) {
+				logging.Logger().Warn("refused whatsapp webhook request with a wrong signature", "remote", r.RemoteAddr)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			var payload whatsappPayload
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				http.Error(w, "malformed payload", http.StatusBadRequest)
+				return
+			}
+			for _, message := range payload.messages() {
+				handle(ctx, message)
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// validWhatsAppSignature checks the sha256=<hex> HMAC Meta sends with every
+// delivery.
+func validWhatsAppSignature(body []byte, header, appSecret string) bool {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
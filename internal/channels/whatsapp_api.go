@@ -0,0 +1,184 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// whatsappGraphURL is the Graph API version the Cloud API calls use.
+	whatsappGraphURL        = "https://graph.facebook.com/v21.0"
+	whatsappRequestTimeout  = 30 * time.Second
+	maxWhatsAppResponse     = 1 << 20
+	whatsappReengagementErr = 131047
+)
+
+// whatsappAPI sends messages from one business phone number through the
+// WhatsApp Cloud API.
+type whatsappAPI struct {
+	// baseURL overrides whatsappGraphURL in tests.
+	baseURL       string
+	phoneNumberID string
+	token         string
+	client        *http.Client
+}
+
+// whatsappAPIError is an error returned by the Graph API.
+type whatsappAPIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *whatsappAPIError) Error() string {
+	return fmt.Sprintf("whatsapp error %d: %s", e.Code, e.Message)
+}
+
+// call sends body as JSON to path under the Graph API and decodes the reply
+// into out. A nil body makes a GET.
+func (a whatsappAPI) call(ctx context.Context, path string, query url.Values, body, out any) error {
+	method := http.MethodGet
+	var payload io.Reader = http.NoBody
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode whatsapp %s: %w", path, err)
+		}
+		method = http.MethodPost
+		payload = bytes.NewReader(encoded)
+	}
+	base := a.baseURL
+	if base == "" {
+		base = whatsappGraphURL
+	}
+	endpoint := strings.TrimRight(base, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	ctx, cancel := context.WithTimeout(ctx, whatsappRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, payload)
+	if err != nil {
+		return fmt.Errorf("build whatsapp %s request: %w", path, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := a.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("whatsapp %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxWhatsAppResponse))
+	if err != nil {
+		return fmt.Errorf("read whatsapp %s response: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error *whatsappAPIError `json:"error"`
+		}
+		if json.Unmarshal(raw, &failure) == nil && failure.Error != nil {
+			return failure.Error
+		}
+		return fmt.Errorf("whatsapp %s: HTTP %d", path, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode whatsapp %s response: %w", path, err)
+	}
+	return nil
+}
+
+// phoneNumber returns the display number of the sender, which also checks
+// the token.
+func (a whatsappAPI) phoneNumber(ctx context.Context) (string, error) {
+	var number struct {
+		DisplayPhoneNumber string `json:"display_phone_number"`
+	}
+	err := a.call(ctx, "/"+url.PathEscape(a.phoneNumberID), url.Values{"fields": {"display_phone_number"}}, nil, &number)
+	return number.DisplayPhoneNumber, err
+}
+
+// send delivers one message of the given type to the WhatsApp user to.
+// content is the type's object, such as {"body": "hi"} for text.
+func (a whatsappAPI) send(ctx context.Context, to, messageType string, content any) error {
+	return a.call(ctx, "/"+url.PathEscape(a.phoneNumberID)+"/messages", nil, map[string]any{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                to,
+		"type":              messageType,
+		messageType:         content,
+	}, nil)
+}
+
+// whatsappPayload is the part of a webhook delivery NeoClaw uses.
+type whatsappPayload struct {
+	Object string `json:"object"`
+	Entry  []struct {
+		Changes []struct {
+			Field string `json:"field"`
+			Value struct {
+				Contacts []struct {
+					WaID    string `json:"wa_id"`
+					Profile struct {
+						Name string `json:"name"`
+					} `json:"profile"`
+				} `json:"contacts"`
+				Messages []whatsappMessage `json:"messages"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// whatsappMessage is one inbound message.
+type whatsappMessage struct {
+	From string `json:"from"`
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Text struct {
+		Body string `json:"body"`
+	} `json:"text"`
+	Interactive struct {
+		Type        string `json:"type"`
+		ButtonReply struct {
+			ID string `json:"id"`
+		} `json:"button_reply"`
+	} `json:"interactive"`
+	// name is the sender's profile name, copied from the contacts list.
+	name string
+}
+
+// messages returns every inbound message in the payload.
+func (p whatsappPayload) messages() []whatsappMessage {
+	var out []whatsappMessage
+	for _, entry := range p.Entry {
+		for _, change := range entry.Changes {
+			if change.Field != "messages" {
+				continue
+			}
+			names := make(map[string]string)
+			for _, contact := range change.Value.Contacts {
+				names[contact.WaID] = contact.Profile.Name
+			}
+			for _, message := range change.Value.Messages {
+				message.name = names[message.From]
+				out = append(out, message)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+// WhatsAppPairSession represents one active WhatsApp pairing session.
+type WhatsAppPairSession struct {
+	api              whatsappAPI
+	botNumber        string
+	expectedCode     string
+	userID           string
+	name             string
+	allowedUsersPath string
+}
+
+// BeginWhatsAppPairing serves the webhook and waits for the first text
+// message to the business number. The sender gets a code to enter in the
+// terminal and is added to the allowlist at allowedUsersPath once it
+// matches.
+func BeginWhatsAppPairing(ctx context.Context, cfg WhatsAppConfig, allowedUsersPath string) (*WhatsAppPairSession, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	listener := NewWhatsApp(cfg, allowedUsersPath)
+	if listener.api.phoneNumberID == "" || listener.api.token == "" || listener.appSecret == "" || listener.verifyToken == "" {
+		return nil, errors.New("whatsapp phone_number_id, token, app_secret, and verify_token are required")
+	}
+	return beginWhatsAppPairing(ctx, listener)
+}
+
+func beginWhatsAppPairing(ctx context.Context, listener *WhatsAppListener) (*WhatsAppPairSession, error) {
+	number, err := listener.api.phoneNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("check whatsapp access token: %w", err)
+	}
+	logging.Logger().Info(fmt.Sprintf("Connected to WhatsApp as %s", number))
+
+	serveCtx, stopServing := context.WithCancel(ctx)
+	defer stopServing()
+	firstInbound := make(chan whatsappMessage, 1)
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- serveWhatsAppWebhook(serveCtx, listener.listen, listener.verifyToken, listener.appSecret, func(_ context.Context, message whatsappMessage) {
+			if message.Type != "text" {
+				return
+			}
+			select {
+			case firstInbound <- message:
+			default:
+			}
+		})
+	}()
+
+	var inbound whatsappMessage
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-serveErr:
+		return nil, err
+	case inbound = <-firstInbound:
+	}
+
+	code, err := generatePairCode()
+	if err != nil {
+		return nil, fmt.Errorf("generate pairing code: %w", err)
+	}
+	session := &WhatsAppPairSession{
+		api:              listener.api,
+		botNumber:        number,
+		expectedCode:     code,
+		userID:           inbound.From,
+		name:             inbound.name,
+		allowedUsersPath: listener.allowedUsersPath,
+	}
+	if err := session.send(ctx, fmt.Sprintf("Pairing mode active. Your code is: %s - enter this in your terminal.", code)); err != nil {
+		return nil, fmt.Errorf("send pairing code: %w", err)
+	}
+	return session, nil
+}
+
+func (s *WhatsAppPairSession) send(ctx context.Context, text string) error {
+	return s.api.send(ctx, s.userID, "text", map[string]any{"body": text})
+}
+
+// BotUsername returns the business phone number.
+func (s *WhatsAppPairSession) BotUsername() string {
+	return s.botNumber
+}
+
+// UserID returns the paired user's WhatsApp ID, their phone number.
+func (s *WhatsAppPairSession) UserID() string {
+	return s.userID
+}
+
+// Username returns the paired user's WhatsApp ID; WhatsApp has no
+// usernames.
+func (s *WhatsAppPairSession) Username() string {
+	return s.userID
+}
+
+// Name returns the paired user's profile name, if they have one.
+func (s *WhatsAppPairSession) Name() string {
+	return s.name
+}
+
+// SubmitCode validates an entered code and persists the paired WhatsApp user on success.
+func (s *WhatsAppPairSession) SubmitCode(ctx context.Context, entered string) error {
+	if strings.TrimSpace(entered) != s.expectedCode {
+		return ErrWrongCode
+	}
+	if err := s.send(ctx, "You are now authorized. Restart the bot server to activate."); err != nil {
+		return fmt.Errorf("send pairing confirmation: %w", err)
+	}
+	if err := approval.AddUser(s.allowedUsersPath, approval.User{
+		ID:       s.userID,
+		Channel:  WhatsAppChannel,
+		Username: s.userID,
+		Name:     s.name,
+	}); err != nil {
+		return fmt.Errorf("persist paired user: %w", err)
+	}
+	logging.Logger().Info("whatsapp user paired", "user_id", s.userID, "channel", WhatsAppChannel)
+	return nil
+}
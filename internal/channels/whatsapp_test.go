@@ -0,0 +1,207 @@
+package channels
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+)
+
+// fakeGraphAPI records the messages the listener sends. Text messages are
+// refused with failText when it is set.
+type fakeGraphAPI struct {
+	server   *httptest.Server
+	sent     chan map[string]any
+	failText int
+}
+
+func newFakeGraphAPI(t *testing.T) *fakeGraphAPI {
+	f := &fakeGraphAPI{sent: make(chan map[string]any, 20)}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer wa-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"error":{"code":190,"message":"Invalid OAuth access token"}}`)
+			return
+		}
+		if r.Method == http.MethodGet {
+			io.WriteString(w, `{"display_phone_number":"+1 555 0100","id":"123"}`)
+			return
+		}
+		var body map[string]any
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+		if body["type"] == "text" && f.failText != 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":{"code":131047,"message":"Re-engagement message"}}`)
+			return
+		}
+		f.sent <- body
+		io.WriteString(w, `{"messages":[{"id":"wamid.1"}]}`)
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeGraphAPI) waitForSend(t *testing.T) map[string]any {
+	t.Helper()
+	select {
+	case body := <-f.sent:
+		return body
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a message to be sent")
+		return nil
+	}
+}
+
+func newTestWhatsApp(t *testing.T, api *fakeGraphAPI) *WhatsAppListener {
+	listener := NewWhatsApp(WhatsAppConfig{PhoneNumberID: "123", Token: "wa-test", AppSecret: "secret", VerifyToken: "verify"}, "")
+	listener.api.baseURL = api.server.URL
+	return listener
+}
+
+func TestWhatsAppWebhookVerifiesAndChecksSignatures(t *testing.T) {
+	var got []whatsappMessage
+	handler := whatsappWebhookHandler(context.Background(), "verify", "secret", func(_ context.Context, message whatsappMessage) {
+		got = append(got, message)
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/whatsapp?hub.mode=subscribe&hub.verify_token=verify&hub.challenge=42", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "42" {
+		t.Fatalf("expected the challenge back, got %d %q", recorder.Code, recorder.Body.String())
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/whatsapp?hub.mode=subscribe&hub.verify_token=wrong&hub.challenge=42", nil))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected a wrong verify token to be refused, got %d", recorder.Code)
+	}
+
+	payload := `{"object":"whatsapp_business_account","entry":[{"changes":[{"field":"messages","value":{` +
+		`"contacts":[{"wa_id":"15550001","profile":{"name":"Alice"}}],` +
+		`"messages":[{"from":"15550001","id":"wamid.in","type":"text","text":{"body":"hello"}}]}}]}]}`
+	post := func(signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/whatsapp", strings.NewReader(payload))
+		req.Header.Set(whatsappSignatureHeader, signature)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	if code := post("sha256=" + strings.Repeat("0", 64)); code != http.StatusUnauthorized || len(got) != 0 {
+		t.Fatalf("expected a bad signature to be refused, got %d with %d messages", code, len(got))
+	}
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(payload))
+	if code := post("sha256=" + hex.EncodeToString(mac.Sum(nil))); code != http.StatusOK {
+		t.Fatalf("expected a signed delivery to be accepted, got %d", code)
+	}
+	if len(got) != 1 || got[0].From != "15550001" || got[0].Text.Body != "hello" || got[0].name != "Alice" {
+		t.Fatalf("unexpected messages: %#v", got)
+	}
+}
+
+func TestWhatsAppApprovalButtons(t *testing.T) {
+	api := newFakeGraphAPI(t)
+	listener := newTestWhatsApp(t, api)
+	listener.setActiveUser("15550001")
+
+	decisions := make(chan approval.ApprovalDecision, 1)
+	go func() {
+		decision, err := listener.RequestApproval(context.Background(), approval.ApprovalRequest{Tool: "run_command", Description: "Run: ls"})
+		if err != nil {
+			t.Errorf("request approval: %v", err)
+		}
+		decisions <- decision
+	}()
+
+	sent := api.waitForSend(t)
+	interactive := sent["interactive"].(map[string]any)
+	if body := interactive["body"].(map[string]any)["text"]; body != "Run: ls" {
+		t.Fatalf("unexpected prompt body %v", body)
+	}
+	buttons := interactive["action"].(map[string]any)["buttons"].([]any)
+	approveID := buttons[0].(map[string]any)["reply"].(map[string]any)["id"].(string)
+
+	press := func(from string) {
+		message := whatsappMessage{From: from, Type: "interactive"}
+		message.Interactive.Type = "button_reply"
+		message.Interactive.ButtonReply.ID = approveID
+		listener.handleButtonReply(context.Background(), message)
+	}
+	press("15559999")
+	select {
+	case decision := <-decisions:
+		t.Fatalf("expected a reply from another user to be ignored, got %v", decision)
+	case <-time.After(50 * time.Millisecond):
+	}
+	press("15550001")
+	select {
+	case decision := <-decisions:
+		if decision != approval.Approved {
+			t.Fatalf("expected approval, got %v", decision)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the button reply to answer the prompt")
+	}
+}
+
+func TestWhatsAppTemplateOutsideWindow(t *testing.T) {
+	api := newFakeGraphAPI(t)
+	listener := newTestWhatsApp(t, api)
+	listener.ConfigureTemplate(WhatsAppTemplate{Name: "neoclaw_update"})
+
+	if err := listener.sendText(context.Background(), "15550001", "Reminder:\n\n  water the plants"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	sent := api.waitForSend(t)
+	template, _ := sent["template"].(map[string]any)
+	if sent["type"] != "template" || template["name"] != "neoclaw_update" {
+		t.Fatalf("expected the template outside the window, got %#v", sent)
+	}
+	if language := template["language"].(map[string]any)["code"]; language != DefaultWhatsAppTemplateLanguage {
+		t.Fatalf("expected the default language, got %v", language)
+	}
+	parameter := template["components"].([]any)[0].(map[string]any)["parameters"].([]any)[0].(map[string]any)
+	if parameter["text"] != "Reminder: water the plants" {
+		t.Fatalf("expected the text flattened to one line, got %q", parameter["text"])
+	}
+
+	listener.lastInbound["15550001"] = time.Now()
+	if err := listener.sendText(context.Background(), "15550001", "hi"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if sent := api.waitForSend(t); sent["type"] != "text" {
+		t.Fatalf("expected text inside the window, got %#v", sent)
+	}
+
+	// WhatsApp is the judge of the window; a refused text falls back too.
+	api.failText = whatsappReengagementErr
+	if err := listener.sendText(context.Background(), "15550001", "hi again"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if sent := api.waitForSend(t); sent["type"] != "template" {
+		t.Fatalf("expected a template after a re-engagement error, got %#v", sent)
+	}
+}
+
+func TestSplitWhatsAppText(t *testing.T) {
+	chunks := splitWhatsAppText("aaaaaa\nbbbbbb\ncc", 10)
+	if len(chunks) != 2 || chunks[0] != "aaaaaa\n" || chunks[1] != "bbbbbb\ncc" {
+		t.Fatalf("expected a cut at the line break, got %q", chunks)
+	}
+	chunks = splitWhatsAppText(strings.Repeat("é", 12), 10)
+	if len(chunks) != 2 || len([]rune(chunks[0])) != 10 {
+		t.Fatalf("expected a hard cut at the limit, got %q", chunks)
+	}
+	if chunks := splitWhatsAppText("short", 8); len(chunks) != 1 || chunks[0] != "short" {
+		t.Fatalf("unexpected chunks %q", chunks)
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/canary"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+// armCanaries plants the workspace canaries when security.canaries is on
+// and arms the tripwire for this process's tool calls. alarm tells the user
+// when one trips.
+func armCanaries(cfg *config.Config, alarm func(context.Context, string)) error {
+	if !cfg.Security.Canaries {
+		return nil
+	}
+	set, err := canary.Plant(cfg.WorkspaceDir(), cfg.CanariesPath())
+	if err != nil {
+		return err
+	}
+	approval.SetTripwire(canary.NewTripwire(set, cfg.SecurityAlertsPath(), alarm))
+	return nil
+}
+
+// canaryAlarm sends canary alerts straight to every channel, ignoring quiet
+// hours and do-not-disturb. Channels start after the tripwire is armed, so
+// their writers are added once they are up.
+type canaryAlarm struct {
+	mu      sync.Mutex
+	writers map[string]io.Writer
+}
+
+func (a *canaryAlarm) setWriters(writers map[string]io.Writer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.writers = maps.Clone(writers)
+}
+
+func (a *canaryAlarm) sound(_ context.Context, text string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for channelID, w := range a.writers {
+		if _, err := fmt.Fprintln(w, text); err != nil {
+			logging.Logger().Warn("failed to send canary alert", "channel", channelID, "err", err)
+		}
+	}
+}
@@ -5,25 +5,46 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/neoclaw-ai/neoclaw/internal/agent"
 	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
+	"github.com/neoclaw-ai/neoclaw/internal/calc"
 	"github.com/neoclaw-ai/neoclaw/internal/channels"
 	"github.com/neoclaw-ai/neoclaw/internal/commands"
 	"github.com/neoclaw-ai/neoclaw/internal/config"
 	"github.com/neoclaw-ai/neoclaw/internal/costs"
+	"github.com/neoclaw-ai/neoclaw/internal/embeddings"
+	"github.com/neoclaw-ai/neoclaw/internal/expenses"
+	"github.com/neoclaw-ai/neoclaw/internal/jsonschema"
+	"github.com/neoclaw-ai/neoclaw/internal/language"
+	"github.com/neoclaw-ai/neoclaw/internal/lists"
 	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
+	"github.com/neoclaw-ai/neoclaw/internal/postprocess"
+	"github.com/neoclaw-ai/neoclaw/internal/rerank"
 	"github.com/neoclaw-ai/neoclaw/internal/runtime"
 	"github.com/neoclaw-ai/neoclaw/internal/sandbox"
 	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
 	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/todo"
 	"github.com/neoclaw-ai/neoclaw/internal/tools"
+	"github.com/neoclaw-ai/neoclaw/internal/transcribe"
+	"github.com/neoclaw-ai/neoclaw/internal/trash"
+	"github.com/neoclaw-ai/neoclaw/internal/workflow"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func newCLICmd() *cobra.Command {
-	var prompt string
+	var (
+		prompt         string
+		responseFormat string
+		responseSchema string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "cli",
@@ -42,17 +63,26 @@ func newCLICmd() *cobra.Command {
 			warnStartupConditions(cfg)
 
 			llmCfg := cfg.DefaultLLM()
-			modelProvider, err := providerFactory(llmCfg)
+			out := cmd.ErrOrStderr()
+			if err := armCanaries(cfg, func(_ context.Context, text string) {
+				fmt.Fprintln(out, text)
+			}); err != nil {
+				return err
+			}
+			modelProvider, err := newModelProvider(cfg, func(_ context.Context, text string) {
+				fmt.Fprintln(out, text)
+			})
 			if err != nil {
 				return err
 			}
 
-			memoryStore, err := memory.New(cfg.MemoryDir())
+			memoryStore, err := openMemoryStore(cfg)
 			if err != nil {
 				return err
 			}
 			channelWriters := map[string]io.Writer{"cli": cmd.OutOrStdout()}
-			schedulerService, err := newSchedulerService(cfg, channelWriters)
+			alertMemoryQuarantines(channelWriters)
+			schedulerService, err := newSchedulerService(cfg, channelWriters, nil)
 			if err != nil {
 				return err
 			}
@@ -67,7 +97,16 @@ func newCLICmd() *cobra.Command {
 				}
 				approver = approval.NewCLIApprover(cmd.InOrStdin(), cmd.OutOrStdout())
 			} else {
+				plain := plainMode(cmd)
 				listener = channels.NewCLI(cmd.InOrStdin(), cmd.OutOrStdout())
+				listener.ConfigurePlain(plain)
+				if isTerminal(cmd.OutOrStdout()) {
+					listener.ConfigureAlerts(channels.CLIAlerts{
+						Bell:    cfg.CLI.Bell && !plain,
+						Desktop: cfg.CLI.DesktopNotify,
+						After:   cfg.CLI.NotifyAfter,
+					})
+				}
 				approver = listener
 			}
 
@@ -87,11 +126,20 @@ func newCLICmd() *cobra.Command {
 					cfg.Costs.DailyLimit,
 					cfg.Costs.MonthlyLimit,
 				)
+				if err := configureCodeReview(handler, cfg); err != nil {
+					return err
+				}
+				if err := configureResponseFormat(handler, responseFormat, responseSchema); err != nil {
+					return err
+				}
 				writer := &singleShotWriter{out: cmd.OutOrStdout()}
 				return handler.HandleMessage(cmd.Context(), writer, &runtime.Message{Text: trimmedPrompt})
 			}
 
-			sessionStore := session.New(cfg.CLIContextPath())
+			sessionStore, err := openSessionStore(cfg, cfg.CLIContextPath())
+			if err != nil {
+				return err
+			}
 			handler := agent.NewWithSession(
 				modelProvider,
 				registry,
@@ -113,8 +161,82 @@ func newCLICmd() *cobra.Command {
 				cfg.Costs.DailyLimit,
 				cfg.Costs.MonthlyLimit,
 			)
+			handler.ConfigureMemoryWrites(cfg.Privacy.MemoryWrites)
+			toolOutputs, err := openOutputStore(cfg, cfg.ToolOutputsDir())
+			if err != nil {
+				return err
+			}
+			handler.ConfigureOutputs(toolOutputs)
+			handler.ConfigureWorkspace(cfg.WorkspaceDir())
+			handler.ConfigureProjects(cfg.Projects)
+			languages := language.New(cfg.LanguagesPath())
+			handler.ConfigureLanguages(languages)
+			handler.ConfigureDeletionLog(cfg.SessionDeletionsPath())
+			if err := configureCodeReview(handler, cfg); err != nil {
+				return err
+			}
+			if err := configureResponseFormat(handler, responseFormat, responseSchema); err != nil {
+				return err
+			}
+			bridgeSessions, err := telegramChatSessions(cfg)
+			if err != nil {
+				return err
+			}
+			slackSession, err := openSessionStore(cfg, cfg.SlackContextPath())
+			if err != nil {
+				return err
+			}
+			matrixSession, err := openSessionStore(cfg, cfg.MatrixContextPath())
+			if err != nil {
+				return err
+			}
+			whatsappSession, err := openSessionStore(cfg, cfg.WhatsAppContextPath())
+			if err != nil {
+				return err
+			}
+			httpSession, err := openSessionStore(cfg, cfg.HTTPContextPath())
+			if err != nil {
+				return err
+			}
+			webSession, err := openSessionStore(cfg, cfg.WebContextPath())
+			if err != nil {
+				return err
+			}
+			bridgeSessions[config.SlackChannelName] = slackSession
+			bridgeSessions[config.MatrixChannelName] = matrixSession
+			bridgeSessions[config.WhatsAppChannelName] = whatsappSession
+			bridgeSessions[config.HTTPChannelName] = httpSession
+			bridgeSessions[config.WebChannelName] = webSession
+			handler.ConfigureBridge("cli", bridgeSessions)
+			defer handler.Detach()
+			commandHandler := commands.New(handler, schedulerService, costTracker, cfg.Costs.DailyLimit, cfg.Costs.MonthlyLimit)
+			commandHandler.ConfigureSessions(cfg.SessionsDir())
+			commandHandler.ConfigureProfile(cfg.AgentDir())
+			commandHandler.ConfigurePrompts(cfg.PromptsDir())
+			commandHandler.ConfigureArtifacts(artifacts.New(cfg.ArtifactsPath()))
+			commandHandler.ConfigureToolOutputs(toolOutputs)
+			commandHandler.ConfigureTasks(todo.New(cfg.TasksPath()))
+			commandHandler.ConfigureLists(lists.New(cfg.ListsPath()))
+			commandHandler.ConfigureLanguages(languages)
+			commandHandler.ConfigureIncognito(handler)
+			commandHandler.ConfigureCorrections(handler)
+			commandHandler.ConfigureForks(handler)
+			commandHandler.ConfigureBridge(handler)
+			commandHandler.ConfigureRewind(handler)
+			commandHandler.ConfigureDeletions(handler)
+			commandHandler.ConfigureLastTurn(handler)
+			commandHandler.ConfigurePromptBlocks(handler)
+			commandHandler.ConfigureProjects(handler)
+			commandHandler.ConfigureMemoryReview(memoryStore)
+			commandHandler.ConfigureWorkflows(&workflow.Runner{
+				Dir:      cfg.WorkflowsDir(),
+				StateDir: cfg.WorkflowRunsDir(),
+				Prompt:   handler.Complete,
+				Registry: registry,
+				Approver: listener,
+			})
 			router := commands.Router{
-				Commands: commands.New(handler, schedulerService, costTracker, cfg.Costs.DailyLimit, cfg.Costs.MonthlyLimit),
+				Commands: commandHandler,
 				Next:     handler,
 			}
 			return listener.Listen(cmd.Context(), router)
@@ -122,6 +244,8 @@ func newCLICmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&prompt, "prompt", "p", "", "Prompt message")
+	cmd.Flags().StringVar(&responseFormat, "format", config.ResponseFormatText, "Reply format: text or json")
+	cmd.Flags().StringVar(&responseSchema, "schema", "", "JSON Schema file replies must match (implies --format json)")
 
 	return cmd
 }
@@ -148,6 +272,10 @@ func buildToolRegistry(
 		}
 		proxyAddress = domainProxy.Addr()
 	}
+	tasks := todo.New(cfg.TasksPath())
+	sharedLists := lists.New(cfg.ListsPath())
+	spending := expenses.New(cfg.ExpensesPath())
+	trashBin := trash.New(cfg.TrashDir())
 
 	httpClient := &http.Client{
 		Transport: approval.RoundTripper{
@@ -157,17 +285,69 @@ func buildToolRegistry(
 			},
 		},
 	}
+	embedder, err := embeddings.NewFromConfig(cfg.Embeddings, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings: %w", err)
+	}
+	reranker, err := rerank.NewFromConfig(cfg.Rerank, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("rerank: %w", err)
+	}
 	coreTools := []tools.Tool{
 		tools.ReadFileTool{WorkspaceDir: cfg.WorkspaceDir()},
+		tools.ReadFilesTool{
+			WorkspaceDir: cfg.WorkspaceDir(),
+			MaxBytes:     cfg.Context.ToolOutputLength,
+		},
 		tools.ListDirTool{WorkspaceDir: cfg.WorkspaceDir()},
+		tools.TreeTool{WorkspaceDir: cfg.WorkspaceDir()},
+		tools.OutlineFileTool{WorkspaceDir: cfg.WorkspaceDir()},
+		tools.QueryTableTool{WorkspaceDir: cfg.WorkspaceDir()},
+		tools.QueryDataTool{WorkspaceDir: cfg.WorkspaceDir()},
 		tools.WriteFileTool{
 			WorkspaceDir: cfg.WorkspaceDir(),
 			SecurityMode: cfg.Security.Mode,
 		},
-		tools.MemoryAppendTool{Store: memoryStore},
-		tools.DailyLogAppendTool{Store: memoryStore},
+		tools.ApplyPatchTool{
+			WorkspaceDir: cfg.WorkspaceDir(),
+			SecurityMode: cfg.Security.Mode,
+		},
+		tools.RenderTemplateTool{
+			WorkspaceDir: cfg.WorkspaceDir(),
+			SecurityMode: cfg.Security.Mode,
+		},
+		tools.MoveFileTool{
+			WorkspaceDir: cfg.WorkspaceDir(),
+			SecurityMode: cfg.Security.Mode,
+		},
+		tools.CopyFileTool{
+			WorkspaceDir: cfg.WorkspaceDir(),
+			SecurityMode: cfg.Security.Mode,
+		},
+		tools.DeleteFileTool{
+			WorkspaceDir: cfg.WorkspaceDir(),
+			SecurityMode: cfg.Security.Mode,
+			Trash:        trashBin,
+		},
+		tools.DeleteDirTool{
+			WorkspaceDir: cfg.WorkspaceDir(),
+			SecurityMode: cfg.Security.Mode,
+			Trash:        trashBin,
+		},
+		tools.RestoreDeletedTool{Trash: trashBin},
+		tools.MemoryAppendTool{Store: memoryStore, Writes: cfg.Privacy.MemoryWrites},
+		tools.DailyLogAppendTool{Store: memoryStore, Writes: cfg.Privacy.MemoryWrites},
 		tools.MemoryTagsTool{Store: memoryStore},
+		tools.MemoryRetagTool{Store: memoryStore},
+		tools.MemoryMergeTool{Store: memoryStore},
+		tools.ContactLookupTool{Store: memoryStore},
 		tools.SearchLogsTool{Store: memoryStore},
+		tools.MemorySearchTool{
+			Store:      memoryStore,
+			Embedder:   embeddings.NewCache(embedder, embeddingCacheSize(cfg)),
+			Reranker:   reranker,
+			Candidates: cfg.Rerank.Candidates,
+		},
 		tools.JobListTool{Service: schedulerService},
 		tools.JobCreateTool{
 			Service:          schedulerService,
@@ -176,31 +356,106 @@ func buildToolRegistry(
 		},
 		tools.JobDeleteTool{Service: schedulerService},
 		tools.JobRunTool{Service: schedulerService},
+		tools.StartTimerTool{
+			Service:          schedulerService,
+			ChannelID:        "cli",
+			ResolveChannelID: resolveChannelID,
+		},
+		tools.CheckTimerTool{Service: schedulerService},
+		tools.TodoAddTool{
+			Store:            tasks,
+			Service:          schedulerService,
+			ChannelID:        "cli",
+			ResolveChannelID: resolveChannelID,
+		},
+		tools.TodoCompleteTool{Store: tasks},
+		tools.TodoListTool{Store: tasks},
+		tools.ListAddTool{Store: sharedLists},
+		tools.ListRemoveTool{Store: sharedLists},
+		tools.ListShowTool{Store: sharedLists},
+		tools.TrackMetricTool{Store: memoryStore, Writes: cfg.Privacy.MemoryWrites},
+		tools.MetricReportTool{Store: memoryStore},
+		tools.LogExpenseTool{Log: spending},
+		tools.ExpenseReportTool{Log: spending},
 		tools.RunCommandTool{
 			WorkspaceDir: cfg.WorkspaceDir(),
 			Timeout:      cfg.Security.CommandTimeout,
 			SecurityMode: cfg.Security.Mode,
 			ProxyAddress: proxyAddress,
+			Sandbox:      approval.SandboxesCommand,
 		},
 		tools.SendMessageTool{
 			Sender: channelSender,
 			Writer: out,
 		},
+		tools.SendFileTool{
+			Sender:       channelSender,
+			Writer:       out,
+			WorkspaceDir: cfg.WorkspaceDir(),
+			SecurityMode: cfg.Security.Mode,
+		},
 		tools.WebSearchTool{
 			Client:   httpClient,
 			Provider: cfg.Web.Search.Provider,
 			APIKey:   cfg.Web.Search.APIKey,
 		},
 		tools.HTTPRequestTool{Client: httpClient},
+		tools.TranslateTool{
+			Client:   httpClient,
+			Provider: cfg.Translation.Provider,
+			Endpoint: cfg.Translation.Endpoint,
+			APIKey:   cfg.Translation.APIKey,
+		},
+		tools.GetTranscriptTool{
+			Client:   httpClient,
+			MaxBytes: cfg.Context.ToolOutputLength,
+		},
+		tools.CalculateTool{Rates: &calc.FXRates{Path: cfg.FXRatesPath(), Client: httpClient}},
+		tools.RegisterArtifactTool{
+			WorkspaceDir:     cfg.WorkspaceDir(),
+			SecurityMode:     cfg.Security.Mode,
+			Store:            artifacts.New(cfg.ArtifactsPath()),
+			ResolveChannelID: resolveChannelID,
+		},
+		tools.TranscribeAudioTool{
+			WorkspaceDir: cfg.WorkspaceDir(),
+			Client: &transcribe.Client{
+				Endpoint:   cfg.Transcription.Endpoint,
+				APIKey:     cfg.Transcription.APIKey,
+				Model:      cfg.Transcription.Model,
+				HTTPClient: httpClient,
+			},
+			ChunkDuration:    cfg.Transcription.ChunkDuration,
+			Artifacts:        artifacts.New(cfg.ArtifactsPath()),
+			ResolveChannelID: resolveChannelID,
+		},
 	}
 	for _, tool := range coreTools {
 		if err := registry.Register(tool); err != nil {
 			return nil, fmt.Errorf("register tool %s: %w", tool.Name(), err)
 		}
 	}
+	registry.SetTrust(cfg.Security.Trust)
 	return registry, nil
 }
 
+const (
+	// lowMemoryQueueSize is the Telegram dispatch queue under low_memory.
+	lowMemoryQueueSize = 4
+	// lowMemoryEmbeddingCache is how many memory_search vectors low_memory
+	// keeps.
+	lowMemoryEmbeddingCache = 2000
+)
+
+// embeddingCacheSize returns the memory_search vector cache size; zero
+// means the embeddings package default.
+func embeddingCacheSize(cfg *config.Config) int {
+	if cfg.LowMemory {
+		return lowMemoryEmbeddingCache
+	}
+	return 0
+}
+
 type singleShotWriter struct {
 	out io.Writer
 }
@@ -210,3 +465,141 @@ func (w *singleShotWriter) WriteMessage(_ context.Context, text string) error {
 	fmt.Fprintln(w.out, text)
 	return nil
 }
+
+// isTerminal reports whether w is an interactive terminal, where alerts
+// make sense.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// plainMode reports whether output should avoid readline and escape
+// codes: --plain if given, otherwise whether the terminal is one that shows
+// them literally, such as TERM=dumb or an Emacs shell buffer.
+func plainMode(cmd *cobra.Command) bool {
+	if flag := cmd.Flags().Lookup("plain"); flag != nil && flag.Changed {
+		return flag.Value.String() == "true"
+	}
+	return os.Getenv("TERM") == "dumb" || os.Getenv("INSIDE_EMACS") != ""
+}
+
+// configureResponseFormat switches handler to validated JSON replies when
+// format is json or a schema file is given.
+func configureResponseFormat(handler *agent.Agent, format, schemaPath string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	schemaPath = strings.TrimSpace(schemaPath)
+	switch format {
+	case "", config.ResponseFormatText:
+		if schemaPath == "" {
+			return nil
+		}
+	case config.ResponseFormatJSON:
+	default:
+		return fmt.Errorf("invalid response format %s (allowed: %s, %s)", format, config.ResponseFormatText, config.ResponseFormatJSON)
+	}
+
+	var schema jsonschema.Schema
+	if schemaPath != "" {
+		loaded, err := jsonschema.Load(schemaPath)
+		if err != nil {
+			return err
+		}
+		schema = loaded
+	}
+	handler.ConfigureJSONResponse(schema)
+	return nil
+}
+
+// configurePostProcess applies a channel's reply post-processing settings.
+func configurePostProcess(handler *agent.Agent, channel config.ChannelConfig) {
+	pipeline := postprocess.Pipeline{
+		StripReasoning: channel.StripReasoning,
+		Command:        channel.PostprocessCommand,
+		MaxLength:      channel.MaxReplyLength,
+		Signature:      channel.Signature,
+	}
+	if pipeline.Empty() {
+		return
+	}
+	handler.ConfigurePostProcess(pipeline.Apply)
+}
+
+// telegramChatSessions opens the conversation of each paired Telegram
+// user's private chat with the default bot, keyed like its scheduler
+// channel, e.g. telegram-123456789. Private chat IDs equal user IDs.
+func telegramChatSessions(cfg *config.Config) (map[string]*session.Store, error) {
+	usersFile, err := approval.LoadUsers(cfg.AllowedUsersPath())
+	if err != nil {
+		return nil, fmt.Errorf("load allowed users %s: %w", cfg.AllowedUsersPath(), err)
+	}
+	sessions := make(map[string]*session.Store)
+	for _, user := range usersFile.Users {
+		id := strings.TrimSpace(user.ID)
+		if !strings.EqualFold(strings.TrimSpace(user.Channel), "telegram") || id == "" || user.IsObserver() {
+			continue
+		}
+		chatKey := "telegram-" + id
+		sessionStore, err := openSessionStore(cfg, cfg.TelegramChatContextPath(chatKey))
+		if err != nil {
+			return nil, err
+		}
+		sessions[chatKey] = sessionStore
+	}
+	return sessions, nil
+}
+
+// openMemoryStore loads the agent memory store with [privacy] redaction and
+// [memory.tags] rules applied.
+func openMemoryStore(cfg *config.Config) (*memory.Store, error) {
+	redactor, err := cfg.Privacy.Redactor()
+	if err != nil {
+		return nil, err
+	}
+	openStore := memory.New
+	if cfg.LowMemory {
+		openStore = memory.NewUncached
+	}
+	memoryStore, err := openStore(cfg.MemoryDir())
+	if err != nil {
+		return nil, err
+	}
+	if redactor != nil {
+		memoryStore.SetRedactor(redactor.Redact)
+	}
+	rules := make(map[string]memory.TagRule, len(cfg.Memory.Tags))
+	for tag, rule := range cfg.Memory.Tags {
+		rules[tag] = memory.TagRule{
+			Retention:         time.Duration(rule.RetentionDays) * 24 * time.Hour,
+			ExcludeFromPrompt: rule.ExcludeFromPrompt,
+		}
+	}
+	memoryStore.SetTagRules(rules)
+	return memoryStore, nil
+}
+
+// openSessionStore opens a session file with [privacy] redaction applied.
+func openSessionStore(cfg *config.Config, path string) (*session.Store, error) {
+	redactor, err := cfg.Privacy.Redactor()
+	if err != nil {
+		return nil, err
+	}
+	sessionStore := session.New(path)
+	if redactor != nil {
+		sessionStore.SetRedactor(redactor.Redact)
+	}
+	return sessionStore, nil
+}
+
+// openOutputStore opens a tool output directory with [privacy] redaction
+// applied.
+func openOutputStore(cfg *config.Config, dir string) (*outputs.Store, error) {
+	redactor, err := cfg.Privacy.Redactor()
+	if err != nil {
+		return nil, err
+	}
+	outputStore := outputs.New(dir)
+	if redactor != nil {
+		outputStore.SetRedactor(redactor.Redact)
+	}
+	return outputStore, nil
+}
@@ -2,11 +2,14 @@ package cli
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/neoclaw-ai/neoclaw/internal/config"
 	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/spf13/cobra"
 )
 
 func TestCLIFlagParsing(t *testing.T) {
@@ -87,3 +90,81 @@ func TestCLIOneShotRejectsSlashCommands(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestCLIOneShotJSONSchema(t *testing.T) {
+	dataDir := createTestHome(t)
+	writeValidConfig(t, dataDir)
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(schemaPath, []byte(`{"type":"object","required":["time"]}`), 0o644); err != nil {
+		t.Fatalf("write schema: %v", err)
+	}
+
+	origFactory := providerFactory
+	defer func() { providerFactory = origFactory }()
+	providerFactory = func(_ config.LLMProviderConfig) (provider.Provider, error) {
+		return fakeProvider{
+			resp: &provider.ChatResponse{Content: `{"time":"12:00"}`},
+		}, nil
+	}
+
+	cmd := NewRootCmd()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs([]string{"cli", "-p", "what time is it", "--schema", schemaPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute cli command: %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != `{"time":"12:00"}` {
+		t.Fatalf("expected JSON reply, got %q", got)
+	}
+}
+
+func TestCLIRejectsUnknownFormat(t *testing.T) {
+	dataDir := createTestHome(t)
+	writeValidConfig(t, dataDir)
+
+	cmd := NewRootCmd()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs([]string{"cli", "-p", "hello", "--format", "xml"})
+
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid response format xml") {
+		t.Fatalf("expected format error, got %v", err)
+	}
+}
+
+func TestPlainModeFollowsFlagThenTerminal(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("plain", false, "")
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatalf("parse flags: %v", err)
+		}
+		return cmd
+	}
+
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("INSIDE_EMACS", "")
+	if plainMode(newCmd()) {
+		t.Fatal("expected a capable terminal to keep readline")
+	}
+	if !plainMode(newCmd("--plain")) {
+		t.Fatal("expected --plain to force plain mode")
+	}
+
+	t.Setenv("INSIDE_EMACS", "29.1,comint")
+	if !plainMode(newCmd()) {
+		t.Fatal("expected an Emacs shell to be detected")
+	}
+	t.Setenv("INSIDE_EMACS", "")
+	t.Setenv("TERM", "dumb")
+	if !plainMode(newCmd()) {
+		t.Fatal("expected TERM=dumb to be detected")
+	}
+	if plainMode(newCmd("--plain=false")) {
+		t.Fatal("expected --plain=false to override detection")
+	}
+}
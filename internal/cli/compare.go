@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/agent"
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/compare"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/costs"
+	"github.com/spf13/cobra"
+)
+
+func newCompareCmd() *cobra.Command {
+	var (
+		promptA   string
+		promptB   string
+		inputPath string
+		width     int
+	)
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare two SOUL.md personas on the same inputs",
+		Long: `Send every input to a fresh agent with each SOUL.md and show the replies and
+tool choices side by side. The input file holds one message per line; blank
+lines and lines starting with # are skipped.
+
+Tools are offered to the model but never run: each call gets a placeholder
+result, so a comparison changes nothing. Both personas see your USER.md but
+not your memory or any session history. Every input costs two LLM turns,
+counted against [costs] limits.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if promptA == "" || promptB == "" || inputPath == "" {
+				return errors.New("--prompt-a, --prompt-b, and --input are required")
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			soulA, err := os.ReadFile(promptA)
+			if err != nil {
+				return fmt.Errorf("read --prompt-a: %w", err)
+			}
+			soulB, err := os.ReadFile(promptB)
+			if err != nil {
+				return fmt.Errorf("read --prompt-b: %w", err)
+			}
+			rawInputs, err := os.ReadFile(inputPath)
+			if err != nil {
+				return fmt.Errorf("read --input: %w", err)
+			}
+			inputs := compare.ParseInputs(string(rawInputs))
+			if len(inputs) == 0 {
+				return fmt.Errorf("%s has no inputs", inputPath)
+			}
+			userProfile, err := os.ReadFile(cfg.UserPath())
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("read USER.md: %w", err)
+			}
+
+			out := cmd.ErrOrStderr()
+			modelProvider, err := newModelProvider(cfg, func(_ context.Context, text string) {
+				fmt.Fprintln(out, text)
+			})
+			if err != nil {
+				return err
+			}
+			memoryStore, err := openMemoryStore(cfg)
+			if err != nil {
+				return err
+			}
+			// The registry only lends its tool names and schemas; compare
+			// never executes them.
+			registry, err := buildToolRegistry(cfg, io.Discard, memoryStore, approval.NewCLIApprover(cmd.InOrStdin(), out), nil, nil, nil)
+			if err != nil {
+				return err
+			}
+			llmCfg := cfg.DefaultLLM()
+			costTracker := costs.New(cfg.CostsPath())
+			runner := compare.Runner{
+				Provider:    modelProvider,
+				Tools:       registry.Tools(),
+				UserProfile: string(userProfile),
+				Context:     cfg.Context,
+				Configure: func(handler *agent.Agent) {
+					handler.ConfigureContext(cfg.Context.MaxToolCalls, cfg.Context.ToolOutputLength)
+					handler.ConfigureCosts(
+						costTracker,
+						llmCfg.Provider,
+						llmCfg.Model,
+						cfg.Costs.DailyLimit,
+						cfg.Costs.MonthlyLimit,
+					)
+				},
+			}
+
+			fmt.Fprintf(out, "Running %d inputs from %s against both personas...\n", len(inputs), inputPath)
+			a := compare.Persona{Name: filepath.Base(promptA), Soul: string(soulA)}
+			b := compare.Persona{Name: filepath.Base(promptB), Soul: string(soulB)}
+			if a.Name == b.Name {
+				a.Name, b.Name = promptA, promptB
+			}
+			results, err := runner.Run(cmd.Context(), a, b, inputs)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), strings.TrimSpace(compare.Format(a, b, results, width)))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&promptA, "prompt-a", "", "SOUL.md file for persona A")
+	cmd.Flags().StringVar(&promptB, "prompt-b", "", "SOUL.md file for persona B")
+	cmd.Flags().StringVar(&inputPath, "input", "", "file with one input message per line")
+	cmd.Flags().IntVar(&width, "width", 120, "total width of the side-by-side output")
+	return cmd
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/liveness"
+	"github.com/spf13/cobra"
+)
+
+func newHealthcheckCmd() *cobra.Command {
+	var maxAge time.Duration
+	cmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "Exit 0 if the server is running and responsive",
+		Long:  "Checks that the server process is alive and has written its heartbeat recently. Meant for container HEALTHCHECK and service monitors; exits 1 with the reason otherwise.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if err := liveness.Check(cfg.PIDPath(), cfg.HeartbeatPath(), maxAge, time.Now()); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&maxAge, "max-age", 4*liveness.DefaultInterval, "Oldest heartbeat that still counts as healthy")
+	return cmd
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/importer"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import existing notes into memory",
+		Long: "Import existing Markdown notes so a new agent doesn't start from zero.\n\n" +
+			fmt.Sprintf("Notes of up to %d characters become memory facts; longer notes are copied to\n", importer.MaxFactChars) +
+			"workspace/notes/<source>/ with an index.tsv. Tags come from folders and frontmatter.",
+	}
+	cmd.AddCommand(newImportFormatCmd(importer.FormatObsidian, "obsidian <vault>", "Import an Obsidian vault, including inline #tags"))
+	cmd.AddCommand(newImportFormatCmd(importer.FormatMarkdown, "markdown <dir>", "Import a directory of Markdown notes"))
+	return cmd
+}
+
+func newImportFormatCmd(format, use, short string) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			source, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("resolve %s: %w", args[0], err)
+			}
+			notes, skipped, err := importer.Scan(source, format)
+			if err != nil {
+				return err
+			}
+			docsDir := filepath.Join(cfg.WorkspaceDir(), "notes", filepath.Base(source))
+
+			out := cmd.OutOrStdout()
+			if dryRun {
+				for _, note := range notes {
+					kind := "document"
+					if importer.IsFact(note) {
+						kind = "fact"
+					}
+					fmt.Fprintf(out, "%-8s  %s  %v\n", kind, note.Path, note.Tags)
+				}
+				fmt.Fprintf(out, "%d notes found, %d skipped. Nothing was written.\n", len(notes), skipped)
+				return nil
+			}
+
+			memoryStore, err := openMemoryStore(cfg)
+			if err != nil {
+				return err
+			}
+			result, err := importer.Import(notes, memoryStore, docsDir)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Imported %d facts and %d documents (%d skipped).\n", result.Facts, result.Documents, skipped)
+			if result.Documents > 0 {
+				fmt.Fprintf(out, "Documents are in %s\n", docsDir)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "list what would be imported without writing anything")
+	return cmd
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newLoginCmd() *cobra.Command {
+	var profile string
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Sign an llm profile in with a Claude subscription (OAuth device flow)",
+		Long: "Sign an llm profile in with the OAuth device flow set in its [llm.<profile>.oauth] table.\n\n" +
+			"You open a link on any device and enter the code shown. The token is saved in\n" +
+			"the secrets directory and refreshed by the provider, so this is needed once, or\n" +
+			"again if the refresh token is revoked. A running server picks up the new token\n" +
+			"on its next request.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			llm, ok := cfg.LLM[profile]
+			if !ok {
+				return fmt.Errorf("no [llm.%s] profile in config.toml", profile)
+			}
+			if !llm.OAuth.Enabled() {
+				return fmt.Errorf("llm.%s does not use oauth. Set [llm.%s.oauth] client_id, device_authorization_url, and token_url in config.toml", profile, profile)
+			}
+			if err := llm.Validate(); err != nil {
+				return fmt.Errorf("llm.%s: %w", profile, err)
+			}
+
+			ctx := cmd.Context()
+			code, err := provider.StartDeviceLogin(ctx, http.DefaultClient, llm.OAuth)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "To sign in, open %s and enter the code %s\n", code.VerificationURI, code.UserCode)
+			if code.VerificationURIComplete != "" {
+				fmt.Fprintf(out, "Or open %s to skip typing the code.\n", code.VerificationURIComplete)
+			}
+			fmt.Fprintln(out, "Waiting for you to approve...")
+			if _, err := provider.WaitForDeviceToken(ctx, http.DefaultClient, llm.OAuth, code); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Signed in llm.%s. The token is saved in %s and refreshed automatically.\n", profile, llm.OAuth.SecretsDir)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&profile, "profile", "default", "llm profile to sign in")
+	return cmd
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+func TestLoginSavesTheDeviceFlowToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/device":
+			w.Write([]byte(`{"device_code":"dev-1","user_code":"ABCD-EFGH","verification_uri":"https://example.com/activate","expires_in":60,"interval":1}`))
+		case "/token":
+			w.Write([]byte(`{"access_token":"access-1","refresh_token":"refresh-1","expires_in":3600}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dataDir := createTestHome(t)
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatalf("mkdir data dir: %v", err)
+	}
+	configBody := `
+[llm.default]
+provider = "anthropic"
+model = "claude-sonnet-4-6"
+
+[llm.default.oauth]
+client_id = "neoclaw"
+device_authorization_url = "` + srv.URL + `/device"
+token_url = "` + srv.URL + `/token"
+
+[security]
+mode = "danger"
+`
+	if err := os.WriteFile(filepath.Join(dataDir, "config.toml"), []byte(configBody), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cmd := NewRootCmd()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs([]string{"login"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("login: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "open https://example.com/activate and enter the code ABCD-EFGH") || !strings.Contains(out.String(), "Signed in llm.default.") {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+	cfg := &config.Config{HomeDir: dataDir}
+	raw, err := os.ReadFile(filepath.Join(cfg.SecretsDir(), "oauth-default.json"))
+	if err != nil || !strings.Contains(string(raw), "access-1") {
+		t.Fatalf("expected the token saved, got %s (%v)", raw, err)
+	}
+}
+
+func TestLoginRequiresOAuthSettings(t *testing.T) {
+	dataDir := createTestHome(t)
+	writeValidConfig(t, dataDir)
+
+	cmd := NewRootCmd()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs([]string{"login"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "llm.default does not use oauth") {
+		t.Fatalf("expected missing oauth error, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultLogTailLines = 20
+	logPollInterval     = 500 * time.Millisecond
+)
+
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Read the server log",
+	}
+
+	var (
+		level     string
+		subsystem string
+		since     time.Duration
+		lines     int
+		follow    bool
+	)
+	tail := &cobra.Command{
+		Use:   "tail",
+		Short: "Print recent server log records and follow new ones",
+		Long: "Print recent records from the server log and keep printing new ones as they are written.\n\n" +
+			"--level hides records below a level (debug, info, warn, error). --subsystem shows only records that mention a word, such as telegram or scheduler, in their message or attributes. --since shows records from that long ago instead of the last --lines records.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			filter := logFilter{level: slog.LevelDebug, subsystem: strings.ToLower(strings.TrimSpace(subsystem))}
+			if level != "" {
+				if err := filter.level.UnmarshalText([]byte(level)); err != nil {
+					return fmt.Errorf("invalid --level %q: use debug, info, warn, or error", level)
+				}
+			}
+			if since < 0 {
+				return errors.New("--since must be positive")
+			}
+			if since > 0 {
+				filter.since = time.Now().Add(-since)
+				lines = 0
+			}
+			return tailLogs(cmd.Context(), cmd.OutOrStdout(), cfg.ServerLogPath(), filter, lines, follow)
+		},
+	}
+	tail.Flags().StringVar(&level, "level", "", "minimum level to show: debug, info, warn, or error")
+	tail.Flags().StringVar(&subsystem, "subsystem", "", "only show records that mention this word, such as telegram")
+	tail.Flags().DurationVar(&since, "since", 0, "show records from this long ago, such as 1h or 30m")
+	tail.Flags().IntVarP(&lines, "lines", "n", defaultLogTailLines, "number of recent records to show; 0 shows all")
+	tail.Flags().BoolVarP(&follow, "follow", "f", true, "keep printing new records")
+	cmd.AddCommand(tail)
+	return cmd
+}
+
+// logRecord is one line of the server log.
+type logRecord struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   []logAttr
+}
+
+// logAttr is a record attribute in the order it was logged. Values that
+// are not strings keep their JSON form.
+type logAttr struct {
+	Key   string
+	Value string
+}
+
+// logFilter selects the records claw logs tail prints.
+type logFilter struct {
+	level     slog.Level
+	subsystem string
+	since     time.Time
+}
+
+func (f logFilter) match(record logRecord) bool {
+	if record.Level < f.level {
+		return false
+	}
+	if !f.since.IsZero() && record.Time.Before(f.since) {
+		return false
+	}
+	if f.subsystem == "" || strings.Contains(strings.ToLower(record.Message), f.subsystem) {
+		return true
+	}
+	for _, attr := range record.Attrs {
+		if strings.Contains(strings.ToLower(attr.Key+"="+attr.Value), f.subsystem) {
+			return true
+		}
+	}
+	return false
+}
+
+// tailLogs prints the records in path and its rotated file that match
+// filter, only the last lines of them when lines is positive. With follow
+// it then polls path for new records until ctx is done.
+func tailLogs(ctx context.Context, out io.Writer, path string, filter logFilter, lines int, follow bool) error {
+	rotated, _, err := readLogLines(path+".1", 0)
+	if err != nil {
+		return err
+	}
+	current, offset, err := readLogLines(path, 0)
+	if err != nil {
+		return err
+	}
+	var records []logRecord
+	for _, line := range append(rotated, current...) {
+		if record, ok := parseLogRecord(line); ok && filter.match(record) {
+			records = append(records, record)
+		}
+	}
+	if lines > 0 && len(records) > lines {
+		records = records[len(records)-lines:]
+	}
+	for _, record := range records {
+		fmt.Fprintln(out, formatLogRecord(record))
+	}
+	if !follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		// A file smaller than what was read has been rotated; start over.
+		if info, err := os.Stat(path); err == nil && info.Size() < offset {
+			offset = 0
+		}
+		var fresh [][]byte
+		fresh, offset, err = readLogLines(path, offset)
+		if err != nil {
+			return err
+		}
+		for _, line := range fresh {
+			if record, ok := parseLogRecord(line); ok && filter.match(record) {
+				fmt.Fprintln(out, formatLogRecord(record))
+			}
+		}
+	}
+}
+
+// readLogLines returns the complete lines in path after offset and the
+// offset after the last of them. A missing file has no lines.
+func readLogLines(path string, offset int64) ([][]byte, int64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, offset, nil
+	}
+	if err != nil {
+		return nil, offset, fmt.Errorf("open log file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, fmt.Errorf("seek log file: %w", err)
+	}
+	raw, err := io.ReadAll(file)
+	if err != nil {
+		return nil, offset, fmt.Errorf("read log file: %w", err)
+	}
+	// A line still being written is left for the next read.
+	end := bytes.LastIndexByte(raw, '\n')
+	if end < 0 {
+		return nil, offset, nil
+	}
+	var lines [][]byte
+	for _, line := range bytes.Split(raw[:end], []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines, offset + int64(end) + 1, nil
+}
+
+// parseLogRecord decodes a line written by slog's JSON handler, keeping
+// attributes in their logged order.
+func parseLogRecord(line []byte) (logRecord, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return logRecord{}, false
+	}
+	var record logRecord
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return logRecord{}, false
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return logRecord{}, false
+		}
+		text := logValue(value)
+		switch key {
+		case slog.TimeKey:
+			record.Time, _ = time.Parse(time.RFC3339Nano, text)
+		case slog.LevelKey:
+			record.Level.UnmarshalText([]byte(text))
+		case slog.MessageKey:
+			record.Message = text
+		default:
+			record.Attrs = append(record.Attrs, logAttr{Key: key, Value: text})
+		}
+	}
+	return record, true
+}
+
+// logValue returns a JSON string unquoted and any other value as compact
+// JSON.
+func logValue(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var compact bytes.Buffer
+	if json.Compact(&compact, raw) != nil {
+		return string(raw)
+	}
+	return compact.String()
+}
+
+// formatLogRecord renders record as one line: local time, level, message,
+// then key=value attributes.
+func formatLogRecord(record logRecord) string {
+	var b strings.Builder
+	if !record.Time.IsZero() {
+		b.WriteString(record.Time.Local().Format(time.DateTime))
+		b.WriteByte(' ')
+	}
+	fmt.Fprintf(&b, "%-5s %s", record.Level, record.Message)
+	for _, attr := range record.Attrs {
+		value := attr.Value
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(&b, " %s=%s", attr.Key, value)
+	}
+	return b.String()
+}
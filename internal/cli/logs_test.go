@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTailLogsFiltersAndFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.jsonl")
+	rotated := `{"time":"2026-01-02T10:00:00Z","level":"WARN","msg":"old telegram warning","err":"timeout"}` + "\n"
+	current := `{"time":"2026-01-02T11:00:00Z","level":"INFO","msg":"starting server","agent":"default"}` + "\n" +
+		`not json` + "\n" +
+		`{"time":"2026-01-02T11:05:00Z","level":"ERROR","msg":"failed to send reply","channel":"telegram","attempt":2,"err":"bad gateway"}` + "\n" +
+		`{"time":"2026-01-02T11:06:00Z","level":"WARN","msg":"half written`
+	if err := os.WriteFile(path+".1", []byte(rotated), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(current), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	filter := logFilter{level: slog.LevelWarn, subsystem: "telegram"}
+	if err := tailLogs(context.Background(), &out, path, filter, 0, false); err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected the two telegram warnings, got:\n%s", out.String())
+	}
+	if !strings.HasSuffix(lines[0], "WARN  old telegram warning err=timeout") {
+		t.Fatalf("unexpected first line %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], `ERROR failed to send reply channel=telegram attempt=2 err="bad gateway"`) {
+		t.Fatalf("unexpected second line %q", lines[1])
+	}
+
+	out.Reset()
+	filter = logFilter{level: slog.LevelDebug, since: time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC)}
+	if err := tailLogs(context.Background(), &out, path, filter, 1, false); err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); !strings.Contains(got, "failed to send reply") || strings.Contains(got, "starting server") {
+		t.Fatalf("expected only the last matching record, got:\n%s", got)
+	}
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/spf13/cobra"
+)
+
+func newMemoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "memory",
+		Short: "Check, repair, and retag the agent's memory files",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "fsck",
+		Short: "Recover rows from quarantined memory files",
+		Long: "Check memory.tsv and the daily logs, setting aside any file with rows that do not parse as a .corrupt copy, " +
+			"then merge every row that can be read or repaired from the .corrupt copies back into the files they came from.\n\n" +
+			"A .corrupt copy is removed once all its rows are recovered. Lines that still do not parse stay in it; fix them by hand and run fsck again.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+
+			pidFilePath := cfg.PIDPath()
+			if _, err := os.Stat(pidFilePath); err == nil {
+				return errors.New("server is already running. Stop it first, then run claw memory fsck")
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("stat pid file %s: %w", pidFilePath, err)
+			}
+
+			results, err := memory.Fsck(cfg.MemoryDir())
+			if err != nil {
+				return err
+			}
+			printFsckResults(cmd.OutOrStdout(), cfg.MemoryDir(), results)
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "retag <from> <to>",
+		Short: "Rename a tag across memory facts and daily logs",
+		Long: "Rename a tag on every memory fact and daily log entry. The new tag may already exist; " +
+			"the newest fact per first tag stays active, so renaming a topic onto an existing one supersedes its older facts.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMemoryRetag(cmd, "retag", args[:1], args[1])
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "merge <into> <tag>...",
+		Short: "Merge several tags into one across memory facts and daily logs",
+		Long: "Replace each listed tag with <into> on every memory fact and daily log entry, " +
+			"e.g. claw memory merge location locations loc city.",
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMemoryRetag(cmd, "merge", args[1:], args[0])
+		},
+	})
+	return cmd
+}
+
+// runMemoryRetag replaces the from tags with to for claw memory retag and
+// claw memory merge, named by command.
+func runMemoryRetag(cmd *cobra.Command, command string, from []string, to string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	pidFilePath := cfg.PIDPath()
+	if _, err := os.Stat(pidFilePath); err == nil {
+		return fmt.Errorf("server is already running. Stop it first, then run claw memory %s", command)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("stat pid file %s: %w", pidFilePath, err)
+	}
+
+	memoryStore, err := memory.NewUncached(cfg.MemoryDir())
+	if err != nil {
+		return err
+	}
+	result, err := memoryStore.Retag(from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Retagged %d memory %s and %d daily log %s.\n",
+		result.Facts, pluralFacts(result.Facts), result.DailyEntries, pluralEntries(result.DailyEntries))
+	return nil
+}
+
+func printFsckResults(out io.Writer, dir string, results []memory.FsckResult) {
+	if len(results) == 0 {
+		fmt.Fprintln(out, "Memory files are healthy.")
+		return
+	}
+	for _, result := range results {
+		name := relativeMemoryPath(dir, result.CorruptPath)
+		fmt.Fprintf(out, "%s: recovered %d %s into %s", name, result.Recovered, pluralRows(result.Recovered), relativeMemoryPath(dir, result.Path))
+		if len(result.Unrecovered) == 0 {
+			fmt.Fprintln(out, "; removed.")
+			continue
+		}
+		fmt.Fprintf(out, "; %d %s could not be read and stay in it:\n", len(result.Unrecovered), pluralLines(len(result.Unrecovered)))
+		for _, line := range result.Unrecovered {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+}
+
+func relativeMemoryPath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func pluralRows(n int) string {
+	if n == 1 {
+		return "row"
+	}
+	return "rows"
+}
+
+func pluralLines(n int) string {
+	if n == 1 {
+		return "line"
+	}
+	return "lines"
+}
+
+func pluralFacts(n int) string {
+	if n == 1 {
+		return "fact"
+	}
+	return "facts"
+}
+
+func pluralEntries(n int) string {
+	if n == 1 {
+		return "entry"
+	}
+	return "entries"
+}
+
+// alertMemoryQuarantines tells every channel when a memory file is set
+// aside because rows in it did not parse.
+func alertMemoryQuarantines(channelWriters map[string]io.Writer) {
+	var mu sync.Mutex
+	memory.SetQuarantineNotifier(func(q memory.Quarantine) {
+		mu.Lock()
+		defer mu.Unlock()
+		text := memoryQuarantineAlert(q)
+		for channelID, w := range channelWriters {
+			if _, err := fmt.Fprintln(w, text); err != nil {
+				logging.Logger().Warn("failed to send memory alert", "channel", channelID, "err", err)
+			}
+		}
+	})
+}
+
+// memoryQuarantineAlert describes q for a chat message.
+func memoryQuarantineAlert(q memory.Quarantine) string {
+	return fmt.Sprintf("⚠️ Memory file %s had %d %s that could not be read. It was copied to %s and the bot carries on with the other %d %s. Run claw memory fsck to recover what it can.",
+		filepath.Base(q.Path), q.Lost, pluralLines(q.Lost), filepath.Base(q.CorruptPath), q.Kept, pluralRows(q.Kept))
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
 	"github.com/neoclaw-ai/neoclaw/internal/channels"
 	"github.com/neoclaw-ai/neoclaw/internal/config"
 	"github.com/neoclaw-ai/neoclaw/internal/logging"
@@ -19,18 +20,51 @@ import (
 const pairTimeout = 15 * time.Minute
 
 func newPairCmd() *cobra.Command {
-	return &cobra.Command{
+	var observer bool
+	var botName string
+	cmd := &cobra.Command{
 		Use:   "pair",
-		Short: "Authorize a Telegram user for bot access",
+		Short: "Authorize a Telegram, Slack, Matrix, or WhatsApp user for bot access",
+		Long: "Authorize a Telegram, Slack, Matrix, or WhatsApp user for bot access.\n\n" +
+			"With --observer the user receives a read-only mirror of the conversation\n" +
+			"(messages, replies, tool activity, and approval prompts) but cannot send\n" +
+			"messages or answer approvals. Observers are Telegram only.\n\n" +
+			"--bot picks the [channels.*] entry to pair with: a Telegram bot, slack, matrix, or whatsapp.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			cfg, err := config.Load()
 			if err != nil {
 				return err
 			}
 
-			token := strings.TrimSpace(cfg.TelegramChannel().Token)
-			if token == "" {
-				return errors.New("telegram bot token is not configured. Set [channels.telegram] token in config.toml")
+			channelCfg := cfg.Channels[botName]
+			switch {
+			case botName == config.SlackChannelName:
+				if observer {
+					return errors.New("observers are not supported on Slack")
+				}
+				if strings.TrimSpace(channelCfg.Token) == "" || strings.TrimSpace(channelCfg.AppToken) == "" {
+					return errors.New("slack tokens are not configured. Set [channels.slack] token and app_token in config.toml")
+				}
+			case botName == config.MatrixChannelName:
+				if observer {
+					return errors.New("observers are not supported on Matrix")
+				}
+				if strings.TrimSpace(channelCfg.Token) == "" || strings.TrimSpace(channelCfg.Homeserver) == "" {
+					return errors.New("matrix is not configured. Set [channels.matrix] homeserver and token in config.toml")
+				}
+			case botName == config.WhatsAppChannelName:
+				if observer {
+					return errors.New("observers are not supported on WhatsApp")
+				}
+				if strings.TrimSpace(channelCfg.Token) == "" || strings.TrimSpace(channelCfg.PhoneNumberID) == "" || strings.TrimSpace(channelCfg.AppSecret) == "" || strings.TrimSpace(channelCfg.VerifyToken) == "" {
+					return errors.New("whatsapp is not configured. Set [channels.whatsapp] token, phone_number_id, app_secret, and verify_token in config.toml")
+				}
+			case config.IsTelegramChannel(botName):
+				if strings.TrimSpace(channelCfg.Token) == "" {
+					return fmt.Errorf("telegram bot token is not configured. Set [channels.%s] token in config.toml", botName)
+				}
+			default:
+				return fmt.Errorf("%s is not a telegram bot, slack, matrix, or whatsapp; use telegram, telegram_<name>, slack, matrix, or whatsapp", botName)
 			}
 
 			pidFilePath := cfg.PIDPath()
@@ -43,12 +77,35 @@ func newPairCmd() *cobra.Command {
 			pairingCtx, cancel := context.WithTimeout(cmd.Context(), pairTimeout)
 			defer cancel()
 
-			logging.Logger().Info(
-				"connecting to telegram and waiting for first inbound message",
-				"timeout", pairTimeout.String(),
-			)
-
-			session, err := channels.BeginTelegramPairing(pairingCtx, token)
+			var session pairSession
+			service := "Telegram"
+			switch botName {
+			case config.SlackChannelName:
+				service = "Slack"
+				logging.Logger().Info("connecting to slack and waiting for a direct message", "timeout", pairTimeout.String())
+				session, err = channels.BeginSlackPairing(pairingCtx, channelCfg.AppToken, channelCfg.Token, cfg.AllowedUsersPath())
+			case config.MatrixChannelName:
+				service = "Matrix"
+				logging.Logger().Info("connecting to matrix and waiting for an invite and a message", "timeout", pairTimeout.String())
+				session, err = channels.BeginMatrixPairing(pairingCtx, channelCfg.Homeserver, channelCfg.Token, cfg.AllowedUsersPath())
+			case config.WhatsAppChannelName:
+				service = "WhatsApp"
+				logging.Logger().Info("serving the whatsapp webhook and waiting for a message", "timeout", pairTimeout.String())
+				session, err = channels.BeginWhatsAppPairing(pairingCtx, whatsAppConfig(channelCfg), cfg.AllowedUsersPath())
+			default:
+				logging.Logger().Info(
+					"connecting to telegram and waiting for first inbound message",
+					"timeout", pairTimeout.String(),
+				)
+				var telegramSession *channels.TelegramPairSession
+				telegramSession, err = channels.BeginTelegramPairing(pairingCtx, channelCfg.Token, cfg.AllowedUsersPathFor(botName))
+				if err == nil {
+					if observer {
+						telegramSession.SetRole(approval.RoleObserver)
+					}
+					session = telegramSession
+				}
+			}
 			if err != nil {
 				if errors.Is(err, context.DeadlineExceeded) {
 					fmt.Fprintln(cmd.OutOrStdout(), "Pairing timed out.")
@@ -57,8 +114,9 @@ func newPairCmd() *cobra.Command {
 			}
 			fmt.Fprintf(
 				cmd.OutOrStdout(),
-				"Bot connected: @%s. Code sent to Telegram. Enter the pairing code:\n",
+				"Bot connected: @%s. Code sent to %s. Enter the pairing code:\n",
 				session.BotUsername(),
+				service,
 			)
 
 			reader := bufio.NewReader(cmd.InOrStdin())
@@ -96,9 +154,14 @@ func newPairCmd() *cobra.Command {
 				if name == "" {
 					name = "Unknown"
 				}
+				role := ""
+				if observer {
+					role = " as observer"
+				}
 				fmt.Fprintf(
 					cmd.OutOrStdout(),
-					"Paired: %s (@%s | ID %s)\n",
+					"Paired%s: %s (@%s | ID %s)\n",
+					role,
 					name,
 					session.Username(),
 					session.UserID(),
@@ -107,4 +170,16 @@ func newPairCmd() *cobra.Command {
 			}
 		},
 	}
+	cmd.Flags().BoolVar(&observer, "observer", false, "Pair as a read-only observer")
+	cmd.Flags().StringVar(&botName, "bot", config.TelegramChannelName, "Bot to pair with, by its [channels.*] name")
+	return cmd
+}
+
+// pairSession is a pairing in progress on Telegram, Slack, Matrix, or WhatsApp.
+type pairSession interface {
+	BotUsername() string
+	UserID() string
+	Username() string
+	Name() string
+	SubmitCode(ctx context.Context, entered string) error
 }
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+const defaultPolicyHistoryLimit = 20
+
+func newPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Inspect and revert changes to the command, domain, and user allowlists",
+	}
+
+	var limit int
+	history := &cobra.Command{
+		Use:   "history",
+		Short: "List recent policy changes, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			changes, err := approval.LoadPolicyJournal(cfg.PolicyJournalPath())
+			if err != nil {
+				return err
+			}
+			if len(changes) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No policy changes recorded.")
+				return nil
+			}
+			if limit > 0 && len(changes) > limit {
+				changes = changes[len(changes)-limit:]
+			}
+			for i := len(changes) - 1; i >= 0; i-- {
+				fmt.Fprintln(cmd.OutOrStdout(), formatPolicyChange(changes[i]))
+				for _, line := range formatPolicyDiff(changes[i].Diff) {
+					fmt.Fprintln(cmd.OutOrStdout(), "    "+line)
+				}
+			}
+			return nil
+		},
+	}
+	history.Flags().IntVar(&limit, "limit", defaultPolicyHistoryLimit, "maximum number of changes to show; 0 shows all")
+	cmd.AddCommand(history)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "rollback <id>",
+		Short: "Revert one policy change",
+		Long: "Revert the policy change with the given id from claw policy history.\n\n" +
+			"Only that change is undone: rules it added are removed and rules it\n" +
+			"removed are restored, while later changes are kept. The rollback is\n" +
+			"itself recorded in the history.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(args[0]), "#"))
+			if err != nil || id < 1 {
+				return fmt.Errorf("invalid policy change id %q", args[0])
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+
+			// A running server keeps policy in memory and would write the
+			// reverted rules back.
+			pidFilePath := cfg.PIDPath()
+			if _, err := os.Stat(pidFilePath); err == nil {
+				return errors.New("server is already running. Stop it first, then run claw policy rollback")
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("stat pid file %s: %w", pidFilePath, err)
+			}
+
+			change, err := approval.RollbackPolicyChange(cfg.PolicyJournalPath(), id, "claw policy rollback")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Rolled back. %s\n", formatPolicyChange(change))
+			return nil
+		},
+	})
+	return cmd
+}
+
+// formatPolicyChange renders one journal entry on a single line.
+func formatPolicyChange(change approval.PolicyChange) string {
+	line := fmt.Sprintf("#%d %s %s: %s by %s", change.ID, change.Time.Local().Format("2006-01-02 15:04"), change.Policy, change.Summary, change.Actor)
+	if change.Reason != "" {
+		line += " (" + change.Reason + ")"
+	}
+	return line
+}
+
+// formatPolicyDiff renders one line per added (+) or removed (-) rule.
+func formatPolicyDiff(diff []approval.PolicyDiff) []string {
+	var lines []string
+	for _, field := range diff {
+		for _, entry := range field.Added {
+			lines = append(lines, "+ "+field.Field+": "+entry)
+		}
+		for _, entry := range field.Removed {
+			lines = append(lines, "- "+field.Field+": "+entry)
+		}
+	}
+	return lines
+}
+
+// alertPolicyChanges sends every policy change to the security.policy_alerts
+// channel, if one is set and has a writer.
+func alertPolicyChanges(cfg *config.Config, channelWriters map[string]io.Writer) {
+	channelID := strings.TrimSpace(cfg.Security.PolicyAlerts)
+	if channelID == "" {
+		return
+	}
+	w, ok := channelWriters[channelID]
+	if !ok {
+		logging.Logger().Warn("security.policy_alerts names an unknown channel; policy alerts are off", "channel", channelID)
+		return
+	}
+	var mu sync.Mutex
+	approval.SetPolicyNotifier(func(change approval.PolicyChange) {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := fmt.Fprintln(w, policyAlert(change)); err != nil {
+			logging.Logger().Warn("failed to send policy alert", "channel", channelID, "err", err)
+		}
+	})
+}
+
+// policyAlert describes change for a chat message.
+func policyAlert(change approval.PolicyChange) string {
+	lines := append([]string{"🔐 Policy change " + formatPolicyChange(change)}, formatPolicyDiff(change.Diff)...)
+	lines = append(lines, fmt.Sprintf("Undo with: claw policy rollback %d", change.ID))
+	return strings.Join(lines, "\n")
+}
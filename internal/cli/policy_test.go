@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+func TestAlertPolicyChangesSendsDiffToChannel(t *testing.T) {
+	t.Cleanup(func() { approval.SetPolicyNotifier(nil) })
+	var out bytes.Buffer
+	cfg := &config.Config{Security: config.SecurityConfig{PolicyAlerts: "telegram-42"}}
+	alertPolicyChanges(cfg, map[string]io.Writer{"telegram-42": &out})
+
+	path := filepath.Join(t.TempDir(), config.AllowedUsersFileName)
+	if err := approval.AddUser(path, approval.User{ID: "7", Channel: "telegram", Username: "mallory"}); err != nil {
+		t.Fatalf("add user: %v", err)
+	}
+	alert := out.String()
+	for _, want := range []string{"🔐 Policy change #1", "+ users: ", `"username":"mallory"`, "claw policy rollback 1"} {
+		if !strings.Contains(alert, want) {
+			t.Fatalf("expected %q in alert:\n%s", want, alert)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/commands"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/prompts"
+	"github.com/spf13/cobra"
+)
+
+func newPromptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Manage reusable prompt templates",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "add <name> [text]",
+		Short: "Save a prompt template (reads stdin when text is omitted)",
+		Long: "Save a prompt template for use with /prompt <name> [args].\n\n" +
+			"Templates may reference {{1}}, {{2}}, ... for positional arguments, {{args}} for all\n" +
+			"arguments, and {{date}} or {{weekday}} for today.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			body := strings.Join(args[1:], " ")
+			if body == "" {
+				raw, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("read prompt from stdin: %w", err)
+				}
+				body = string(raw)
+			}
+			name := strings.ToLower(args[0])
+			if err := prompts.Save(cfg.PromptsDir(), name, body); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Saved prompt %q. Use it with /prompt %s\n", name, name)
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List saved prompt templates",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			templates, err := prompts.List(cfg.PromptsDir())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), commands.FormatPromptList(templates))
+			return nil
+		},
+	})
+	return cmd
+}
@@ -2,14 +2,22 @@
 package cli
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/neoclaw-ai/neoclaw/internal/agent"
 	"github.com/neoclaw-ai/neoclaw/internal/bootstrap"
 	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/crash"
 	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/migrate"
 	"github.com/neoclaw-ai/neoclaw/internal/provider"
 	"github.com/neoclaw-ai/neoclaw/internal/sandbox"
 	"github.com/spf13/cobra"
@@ -17,9 +25,72 @@ import (
 
 var providerFactory = provider.NewProviderFromConfig
 
+// newModelProvider builds the default LLM profile with health tracking. When
+// llm.default.fallback is set, requests route to that profile while the
+// default is failing; notify, if non-nil, tells the user about the switch.
+func newModelProvider(cfg *config.Config, notify func(context.Context, string)) (provider.Provider, error) {
+	llmCfg := cfg.DefaultLLM()
+	primary, err := providerFactory(llmCfg)
+	if err != nil {
+		return nil, err
+	}
+	router := &provider.Router{
+		Primary: provider.Profile{Name: "default", Provider: primary},
+		Health:  provider.NewHealth(cfg.ProviderHealthPath()),
+		Notify:  notify,
+	}
+	if name := llmCfg.Fallback; name != "" {
+		fallback, err := providerFactory(cfg.LLM[name])
+		if err != nil {
+			return nil, fmt.Errorf("llm.%s: %w", name, err)
+		}
+		router.Fallback = &provider.Profile{Name: name, Provider: fallback}
+	}
+	return router, nil
+}
+
+// configureCodeReview has handler review generated code with the
+// security.code_review profile before write approvals, when one is set.
+func configureCodeReview(handler *agent.Agent, cfg *config.Config) error {
+	name := cfg.Security.CodeReview
+	if name == "" {
+		return nil
+	}
+	llmCfg := cfg.LLM[name]
+	reviewer, err := providerFactory(llmCfg)
+	if err != nil {
+		return fmt.Errorf("llm.%s: %w", name, err)
+	}
+	handler.ConfigureCodeReview(reviewer, llmCfg.Provider, llmCfg.Model)
+	return nil
+}
+
+// migrateDataDir upgrades the data dir for this release. It refuses while
+// another process serves the old format; a server restarted by claw update
+// migrates its own data.
+func migrateDataDir(cfg *config.Config) error {
+	pending, err := migrate.Pending(cfg.DataDir())
+	if err != nil {
+		return err
+	}
+	if pending {
+		if raw, err := os.ReadFile(cfg.PIDPath()); err == nil && strings.TrimSpace(string(raw)) != strconv.Itoa(os.Getpid()) {
+			return errors.New("the data dir needs migrating for this version, but the server is still running. Stop it first, then run claw start")
+		}
+	}
+	result, err := migrate.Run(cfg.DataDir(), cfg.BackupsDir(), time.Now())
+	if err != nil {
+		return err
+	}
+	if result.Backup != "" {
+		logging.Logger().Info("migrated data dir", "from", result.From, "to", result.To, "backup", result.Backup)
+	}
+	return nil
+}
+
 // NewRootCmd creates the root command and registers all subcommands.
 func NewRootCmd() *cobra.Command {
-	var verbose bool
+	var verbose, configFromEnv bool
 
 	root := &cobra.Command{
 		Use:   "claw",
@@ -28,6 +99,7 @@ func NewRootCmd() *cobra.Command {
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			logging.SetPlain(plainMode(cmd))
 			if verbose {
 				logging.SetLevel(slog.LevelDebug)
 			} else {
@@ -35,15 +107,28 @@ func NewRootCmd() *cobra.Command {
 			}
 
 			// The config command only reads and prints merged config and should not
-			// trigger bootstrap/first-run onboarding behavior.
-			if cmd.Name() == "config" || cmd.Name() == "version" {
+			// trigger bootstrap/first-run onboarding behavior. sandbox-run applies
+			// its own sandbox and execs straight away. healthcheck only reads
+			// the pid and heartbeat files and must not create anything. update
+			// replaces the claw binary, which lives outside the data directory
+			// the sandbox allows writes to.
+			switch cmd.Name() {
+			case "config", "version", "sandbox-run", "healthcheck", "update":
 				return nil
 			}
+			if configFromEnv {
+				config.EnableEnvOverrides()
+			}
 
 			cfg, err := config.Load()
 			if err != nil {
 				return err
 			}
+			crash.Configure(cfg.CrashDir(), Version, func() (string, error) {
+				var b bytes.Buffer
+				err := config.WriteRedacted(&b)
+				return b.String(), err
+			})
 
 			configPath := cfg.ConfigPath()
 			firstRun := false
@@ -53,11 +138,21 @@ func NewRootCmd() *cobra.Command {
 				return fmt.Errorf("stat NeoClaw config file %s: %w", configPath, err)
 			}
 
+			if err := bootstrap.CheckWritable(cfg.HomeDir); err != nil {
+				return err
+			}
+			if err := migrateDataDir(cfg); err != nil {
+				return err
+			}
 			if err := bootstrap.Initialize(cfg); err != nil {
 				return err
 			}
 
-			if firstRun {
+			if firstRun && config.EnvOverridesEnabled() {
+				// Configured from the environment, so there is nothing to edit
+				// and no one to restart the process.
+				logging.Logger().Info("first run setup complete", "config", configPath)
+			} else if firstRun {
 				// First-run bootstrap is an onboarding path, not a fatal error.
 				// Print guidance and exit cleanly so logs do not report failures.
 				fmt.Fprintf(
@@ -81,7 +176,10 @@ func NewRootCmd() *cobra.Command {
 				}
 			}
 
-			return nil
+			return configureStorage(cfg)
+		},
+		PersistentPostRun: func(cmd *cobra.Command, _ []string) {
+			flushStorage(cmd.Context())
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Default to `claw start` when no subcommand is provided.
@@ -96,10 +194,30 @@ func NewRootCmd() *cobra.Command {
 
 	root.AddCommand(newConfigCmd())
 	root.AddCommand(newStartCmd())
+	root.AddCommand(newServeCmd())
+	root.AddCommand(newHealthcheckCmd())
 	root.AddCommand(newCLICmd())
 	root.AddCommand(newPairCmd())
+	root.AddCommand(newLoginCmd())
+	root.AddCommand(newSessionCmd())
+	root.AddCommand(newCompareCmd())
+	root.AddCommand(newPromptCmd())
+	root.AddCommand(newImportCmd())
+	root.AddCommand(newPolicyCmd())
+	root.AddCommand(newLogsCmd())
+	root.AddCommand(newMemoryCmd())
+	root.AddCommand(newSoakCmd())
+	root.AddCommand(newStatusCmd())
+	root.AddCommand(newStorageCmd())
+	root.AddCommand(newRestoreCmd())
+	root.AddCommand(newTrashCmd())
+	root.AddCommand(newSandboxRunCmd())
 	root.AddCommand(newVersionCmd())
+	root.AddCommand(newUpdateCmd())
+	root.AddCommand(newTelemetryCmd())
 	root.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging (debug level)")
+	root.PersistentFlags().Bool("plain", false, "Plain output and line input: no colors, readline, or prompt redraws (default on when TERM=dumb or inside Emacs)")
+	root.PersistentFlags().BoolVar(&configFromEnv, "config-from-env", false, "Override config.toml with NEOCLAW_* environment variables, e.g. NEOCLAW_LLM_DEFAULT_API_KEY")
 
 	return root
 }
@@ -15,12 +15,21 @@ func TestRootCommandRegistersSubcommands(t *testing.T) {
 	if c := findSubcommand(t, cmd, "start"); c.Name() != "start" {
 		t.Fatalf("start command not registered")
 	}
+	if c := findSubcommand(t, cmd, "serve"); c.Name() != "serve" {
+		t.Fatalf("serve command not registered")
+	}
+	if c := findSubcommand(t, cmd, "healthcheck"); c.Name() != "healthcheck" {
+		t.Fatalf("healthcheck command not registered")
+	}
 	if c := findSubcommand(t, cmd, "cli"); c.Name() != "cli" {
 		t.Fatalf("cli command not registered")
 	}
 	if c := findSubcommand(t, cmd, "pair"); c.Name() != "pair" {
 		t.Fatalf("pair command not registered")
 	}
+	if c := findSubcommand(t, cmd, "status"); c.Name() != "status" {
+		t.Fatalf("status command not registered")
+	}
 }
 
 func findSubcommand(t *testing.T, root *cobra.Command, name string) *cobra.Command {
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"github.com/neoclaw-ai/neoclaw/internal/sandbox"
+	"github.com/spf13/cobra"
+)
+
+// newSandboxRunCmd is the helper run_command re-execs to confine a single
+// command, such as an approved python -c script, to the workspace.
+func newSandboxRunCmd() *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:    "sandbox-run --dir <dir> -- <command> [args...]",
+		Short:  "Run one command confined to a directory",
+		Hidden: true,
+		Args:   cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sandbox.ExecConfined(dir, args)
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "", "directory the command may write to")
+	_ = cmd.MarkFlagRequired("dir")
+	return cmd
+}
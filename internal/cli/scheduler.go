@@ -5,23 +5,39 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/neoclaw-ai/neoclaw/internal/agent"
 	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
 	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/costs"
+	"github.com/neoclaw-ai/neoclaw/internal/expenses"
+	"github.com/neoclaw-ai/neoclaw/internal/notify"
 	"github.com/neoclaw-ai/neoclaw/internal/sandbox"
 	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
+	"github.com/neoclaw-ai/neoclaw/internal/todo"
 	"github.com/neoclaw-ai/neoclaw/internal/tools"
+	"github.com/neoclaw-ai/neoclaw/internal/trash"
+	"github.com/neoclaw-ai/neoclaw/internal/workflow"
+	"github.com/neoclaw-ai/neoclaw/internal/workspace"
 )
 
-func newSchedulerService(cfg *config.Config, channelWriters map[string]io.Writer) (*scheduler.Service, error) {
-	runner, err := newSchedulerRunner(cfg, channelWriters)
+func newSchedulerService(cfg *config.Config, channelWriters map[string]io.Writer, gate *notify.Gate) (*scheduler.Service, error) {
+	// Check-ins read the service's own jobs, so the runner looks the service
+	// up once it exists.
+	var service *scheduler.Service
+	runner, err := newSchedulerRunner(cfg, channelWriters, gate, func() *scheduler.Service { return service })
 	if err != nil {
 		return nil, err
 	}
-	return scheduler.NewService(cfg.JobsPath(), runner), nil
+	service = scheduler.NewService(cfg.JobsPath(), runner)
+	return service, nil
 }
 
-func newSchedulerRunner(cfg *config.Config, channelWriters map[string]io.Writer) (*scheduler.Runner, error) {
+func newSchedulerRunner(cfg *config.Config, channelWriters map[string]io.Writer, gate *notify.Gate, jobs func() *scheduler.Service) (*scheduler.Runner, error) {
 	proxyAddress := ""
 	if cfg.Security.Mode != config.SecurityModeDanger {
 		domainProxy, err := sandbox.StartDomainProxy(approval.Checker{
@@ -46,6 +62,7 @@ func newSchedulerRunner(cfg *config.Config, channelWriters map[string]io.Writer)
 		Timeout:      cfg.Security.CommandTimeout,
 		SecurityMode: cfg.Security.Mode,
 		ProxyAddress: proxyAddress,
+		Sandbox:      approval.SandboxesCommand,
 	}
 	httpTool := tools.HTTPRequestTool{Client: httpClient}
 
@@ -81,5 +98,309 @@ func newSchedulerRunner(cfg *config.Config, channelWriters map[string]io.Writer)
 			}
 			return res.Output, nil
 		},
+		ProfileRefresh: func(ctx context.Context, writer io.Writer, args map[string]any) (string, error) {
+			return runProfileRefresh(ctx, cfg, writer, args)
+		},
+		ProactiveCheckIn: func(ctx context.Context, writer io.Writer, _ map[string]any) (string, error) {
+			return runProactiveCheckIn(ctx, cfg, writer, gate, jobs())
+		},
+		RunWorkflow: func(ctx context.Context, writer io.Writer, args map[string]any) (string, error) {
+			return runScheduledWorkflow(ctx, cfg, writer, args, []tools.Tool{
+				tools.ReadFileTool{WorkspaceDir: cfg.WorkspaceDir()},
+				tools.ListDirTool{WorkspaceDir: cfg.WorkspaceDir()},
+				runTool,
+				httpTool,
+			})
+		},
+		WorkspaceCleanup: func(ctx context.Context, _ map[string]any) (string, error) {
+			return runWorkspaceCleanup(ctx, cfg, time.Now())
+		},
+		MemoryRetention: func(context.Context, map[string]any) (string, error) {
+			return runMemoryRetention(cfg, time.Now())
+		},
+		MemoryDigest: func(ctx context.Context, _ map[string]any) (string, error) {
+			return runMemoryDigest(ctx, cfg, time.Now())
+		},
 	}, channelWriters), nil
 }
+
+// runWorkspaceCleanup prunes workspace/tmp, keeping files still referenced by
+// the recent context window of any session or registered as artifacts, and
+// purges trash items past their retention.
+func runWorkspaceCleanup(ctx context.Context, cfg *config.Config, now time.Time) (string, error) {
+	referenced, err := workspace.SessionReferences(ctx, cfg.SessionsDir(), cfg.Context.RecentMessages)
+	if err != nil {
+		return "", err
+	}
+	registered, err := artifacts.New(cfg.ArtifactsPath()).List()
+	if err != nil {
+		return "", err
+	}
+	artifactPaths := make(map[string]bool, len(registered))
+	for _, item := range registered {
+		artifactPaths[filepath.Clean(item.Path)] = true
+	}
+
+	report, err := workspace.CleanTmp(cfg.WorkspaceTmpDir(), workspace.Retention{
+		MaxAge:   cfg.Workspace.TmpMaxAge,
+		MaxBytes: int64(cfg.Workspace.TmpMaxSizeMB) * 1024 * 1024,
+	}, func(path string) bool {
+		return artifactPaths[filepath.Clean(path)] || referenced(path)
+	}, now)
+	if err != nil {
+		return "", err
+	}
+	purged, err := trash.New(cfg.TrashDir()).Purge(cfg.Workspace.TrashRetention, now)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s, purged %d trash item(s)", report, purged), nil
+}
+
+// registerWorkspaceCleanup adds the tmp and trash retention job unless its
+// schedule is empty or every limit is disabled.
+func registerWorkspaceCleanup(cfg *config.Config, service *scheduler.Service) error {
+	schedule := strings.TrimSpace(cfg.Workspace.CleanupSchedule)
+	if schedule == "" || (cfg.Workspace.TmpMaxAge == 0 && cfg.Workspace.TmpMaxSizeMB == 0 && cfg.Workspace.TrashRetention == 0) {
+		return nil
+	}
+	return service.AddBuiltin(scheduler.Job{
+		ID:          "builtin_workspace_cleanup",
+		Description: "Workspace tmp cleanup",
+		Cron:        schedule,
+		Action:      scheduler.ActionWorkspaceCleanup,
+		Args:        map[string]any{},
+		// Cleanup sends nothing; the channel only satisfies job validation.
+		ChannelID: "cli",
+	})
+}
+
+// runMemoryRetention deletes daily log entries past their [memory.tags]
+// retention.
+func runMemoryRetention(cfg *config.Config, now time.Time) (string, error) {
+	memoryStore, err := openMemoryStore(cfg)
+	if err != nil {
+		return "", err
+	}
+	removed, err := memoryStore.PruneDailyLogs(now)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("removed %d daily log entries", removed), nil
+}
+
+// registerMemoryRetention adds the daily log retention job unless its
+// schedule is empty or no tag sets a retention.
+func registerMemoryRetention(cfg *config.Config, service *scheduler.Service) error {
+	schedule := strings.TrimSpace(cfg.Memory.RetentionSchedule)
+	if schedule == "" {
+		return nil
+	}
+	hasRetention := false
+	for _, rule := range cfg.Memory.Tags {
+		if rule.RetentionDays > 0 {
+			hasRetention = true
+		}
+	}
+	if !hasRetention {
+		return nil
+	}
+	return service.AddBuiltin(scheduler.Job{
+		ID:          "builtin_memory_retention",
+		Description: "Daily log retention",
+		Cron:        schedule,
+		Action:      scheduler.ActionMemoryRetention,
+		Args:        map[string]any{},
+		// Retention sends nothing; the channel only satisfies job validation.
+		ChannelID: "cli",
+	})
+}
+
+// runMemoryDigest writes missing weekly and monthly digests. Like
+// runProfileRefresh it builds its provider and memory store per run.
+func runMemoryDigest(ctx context.Context, cfg *config.Config, now time.Time) (string, error) {
+	memoryStore, err := openMemoryStore(cfg)
+	if err != nil {
+		return "", err
+	}
+	modelProvider, err := newModelProvider(cfg, nil)
+	if err != nil {
+		return "", err
+	}
+	written, err := agent.RefreshDigests(ctx, modelProvider, memoryStore, now)
+	if err != nil {
+		return "", err
+	}
+	if len(written) == 0 {
+		return "digests are up to date", nil
+	}
+	return "wrote " + strings.Join(written, " and ") + " digest", nil
+}
+
+// registerMemoryDigest adds the weekly and monthly digest job unless its
+// schedule is empty.
+func registerMemoryDigest(cfg *config.Config, service *scheduler.Service) error {
+	schedule := strings.TrimSpace(cfg.Memory.DigestSchedule)
+	if schedule == "" {
+		return nil
+	}
+	return service.AddBuiltin(scheduler.Job{
+		ID:          "builtin_memory_digest",
+		Description: "Weekly and monthly memory digest",
+		Cron:        schedule,
+		Action:      scheduler.ActionMemoryDigest,
+		Args:        map[string]any{},
+		// Digests are stored in memory; the channel only satisfies job validation.
+		ChannelID: "cli",
+	})
+}
+
+// runScheduledWorkflow runs a workflow without an interactive approver, so
+// approval steps and unapproved tools fail the run; the user can then finish
+// it with /run <workflow> resume.
+func runScheduledWorkflow(ctx context.Context, cfg *config.Config, writer io.Writer, args map[string]any, stepTools []tools.Tool) (string, error) {
+	name, _ := args["workflow"].(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("run_workflow requires a workflow argument")
+	}
+
+	registry := tools.NewRegistry()
+	for _, tool := range stepTools {
+		if err := registry.Register(tool); err != nil {
+			return "", fmt.Errorf("register tool %s: %w", tool.Name(), err)
+		}
+	}
+	runner := &workflow.Runner{
+		Dir:      cfg.WorkflowsDir(),
+		StateDir: cfg.WorkflowRunsDir(),
+		Registry: registry,
+	}
+	// The provider is created lazily so workflows with no prompt steps run
+	// without LLM credentials.
+	runner.Prompt = func(ctx context.Context, text string) (string, error) {
+		llmCfg := cfg.DefaultLLM()
+		modelProvider, err := newModelProvider(cfg, nil)
+		if err != nil {
+			return "", err
+		}
+		memoryStore, err := openMemoryStore(cfg)
+		if err != nil {
+			return "", err
+		}
+		handler := agent.New(modelProvider, registry, nil, cfg.AgentDir(), memoryStore, cfg.Context)
+		handler.ConfigureContext(cfg.Context.MaxToolCalls, cfg.Context.ToolOutputLength)
+		handler.ConfigureCosts(
+			costs.New(cfg.CostsPath()),
+			llmCfg.Provider,
+			llmCfg.Model,
+			cfg.Costs.DailyLimit,
+			cfg.Costs.MonthlyLimit,
+		)
+		return handler.Complete(ctx, text)
+	}
+
+	res, err := runner.Run(ctx, name, false)
+	if err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprintln(writer, workflow.FormatResult(res)); err != nil {
+		return "", fmt.Errorf("send workflow result: %w", err)
+	}
+	if res.Status == workflow.StatusFailed {
+		return "", fmt.Errorf("workflow %s failed at %s: %s", res.Workflow, res.Step, res.Error)
+	}
+	return "workflow " + res.Status, nil
+}
+
+// runProfileRefresh builds its provider and memory store per run so the
+// scheduler can start without LLM credentials until the job actually fires.
+func runProfileRefresh(ctx context.Context, cfg *config.Config, writer io.Writer, args map[string]any) (string, error) {
+	modelProvider, err := newModelProvider(cfg, nil)
+	if err != nil {
+		return "", err
+	}
+	memoryStore, err := openMemoryStore(cfg)
+	if err != nil {
+		return "", err
+	}
+	lookbackDays := 0
+	if raw, ok := args["lookback_days"].(float64); ok {
+		lookbackDays = int(raw)
+	}
+
+	proposal, err := agent.ProposeUserProfile(ctx, modelProvider, cfg.AgentDir(), memoryStore, lookbackDays, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if proposal == "" {
+		return "profile is up to date", nil
+	}
+	if _, err := fmt.Fprintln(writer, agent.FormatProfileProposal(proposal)); err != nil {
+		return "", fmt.Errorf("send profile proposal: %w", err)
+	}
+	return "profile update proposed", nil
+}
+
+func runProactiveCheckIn(ctx context.Context, cfg *config.Config, writer io.Writer, gate *notify.Gate, jobs *scheduler.Service) (string, error) {
+	modelProvider, err := newModelProvider(cfg, nil)
+	if err != nil {
+		return "", err
+	}
+	memoryStore, err := openMemoryStore(cfg)
+	if err != nil {
+		return "", err
+	}
+	checkIn := agent.CheckIn{
+		Provider:  modelProvider,
+		Memory:    memoryStore,
+		Tasks:     todo.New(cfg.TasksPath()),
+		Jobs:      jobs,
+		Expenses:  expenses.New(cfg.ExpensesPath()),
+		StatePath: cfg.CheckInsPath(),
+		MaxPerDay: cfg.Proactive.MaxPerDay,
+	}
+	if gate != nil {
+		checkIn.Silenced = gate.Silenced
+	}
+	if cfg.Update.Notify {
+		checkIn.UpdateNotice = func(ctx context.Context) (string, string) {
+			return updateNotice(ctx, cfg)
+		}
+	}
+	return checkIn.Run(ctx, writer, time.Now())
+}
+
+// registerProactiveCheckIn adds the opt-in check-in job when enabled. Without
+// an explicit proactive.channel it delivers to the first paired Telegram user,
+// falling back to the CLI output. Telegram delivery follows the user to the
+// bot they last wrote to; see followPresence.
+func registerProactiveCheckIn(cfg *config.Config, service *scheduler.Service) error {
+	if !cfg.Proactive.Enabled {
+		return nil
+	}
+	channelID := strings.TrimSpace(cfg.Proactive.Channel)
+	if channelID == "" {
+		channelID = "cli"
+		if cfg.TelegramChannel().Enabled {
+			usersFile, err := approval.LoadUsers(cfg.AllowedUsersPath())
+			if err != nil {
+				return fmt.Errorf("load allowed users %s: %w", cfg.AllowedUsersPath(), err)
+			}
+			for _, user := range usersFile.Users {
+				if strings.EqualFold(strings.TrimSpace(user.Channel), "telegram") && strings.TrimSpace(user.ID) != "" && !user.IsObserver() {
+					channelID = "telegram-" + strings.TrimSpace(user.ID)
+					break
+				}
+			}
+		}
+	}
+	return service.AddBuiltin(scheduler.Job{
+		ID:          "builtin_proactive_checkin",
+		Description: "Proactive check-in",
+		Cron:        strings.TrimSpace(cfg.Proactive.Schedule),
+		Action:      scheduler.ActionProactiveCheckIn,
+		Args:        map[string]any{},
+		ChannelID:   channelID,
+	})
+}
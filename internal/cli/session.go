@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/commands"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/spf13/cobra"
+)
+
+func newSessionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "Manage conversation sessions",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List saved sessions with their titles",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			infos, err := session.List(cmd.Context(), cfg.SessionsDir())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), commands.FormatSessionList(infos))
+			return nil
+		},
+	})
+	cmd.AddCommand(newSessionEditCmd())
+	return cmd
+}
+
+func newSessionEditCmd() *cobra.Command {
+	var deletes []int
+	cmd := &cobra.Command{
+		Use:   "edit <session>",
+		Short: "Show a session's messages or delete some of them",
+		Long: `Show the numbered messages of a saved session, such as cli/default or
+telegram/default. With --delete, permanently remove the given messages, for
+example one where a password was pasted. Deletions are recorded, without the
+message text, in logs/session_deletions.jsonl.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			infos, err := session.List(cmd.Context(), cfg.SessionsDir())
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSuffix(strings.TrimSpace(args[0]), ".jsonl")
+			idx := slices.IndexFunc(infos, func(info session.Info) bool { return info.Name == name })
+			if idx < 0 {
+				return fmt.Errorf("session %q not found; see claw session list", name)
+			}
+			sessionStore := session.New(infos[idx].Path)
+
+			if len(deletes) == 0 {
+				messages, err := sessionStore.Load(cmd.Context())
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), formatSessionMessages(messages))
+				return nil
+			}
+
+			// A running server keeps the conversation in memory and would
+			// send the deleted messages with its next prompt.
+			pidFilePath := cfg.PIDPath()
+			if _, err := os.Stat(pidFilePath); err == nil {
+				return errors.New("server is already running. Stop it first, then run claw session edit")
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("stat pid file %s: %w", pidFilePath, err)
+			}
+
+			indexes := make([]int, 0, len(deletes))
+			for _, n := range deletes {
+				indexes = append(indexes, n-1)
+			}
+			removed, err := sessionStore.Delete(cmd.Context(), indexes, cfg.SessionDeletionsPath(), "claw session edit")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d %s from session %s.\n", len(removed), pluralMessages(len(removed)), name)
+			return nil
+		},
+	}
+	cmd.Flags().IntSliceVar(&deletes, "delete", nil, "message numbers to delete, as shown without --delete (e.g. --delete 3,4)")
+	return cmd
+}
+
+// sessionPreviewRunes caps each message shown by claw session edit.
+const sessionPreviewRunes = 120
+
+// formatSessionMessages numbers each message with a one-line preview.
+func formatSessionMessages(messages []provider.ChatMessage) string {
+	if len(messages) == 0 {
+		return "No messages in this session."
+	}
+	var b strings.Builder
+	for i, msg := range messages {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		text := strings.Join(strings.Fields(msg.Content), " ")
+		for _, call := range msg.ToolCalls {
+			text = strings.TrimSpace(text + " [" + call.Name + " call]")
+		}
+		if runes := []rune(text); len(runes) > sessionPreviewRunes {
+			text = string(runes[:sessionPreviewRunes]) + "…"
+		}
+		role := string(msg.Role)
+		if msg.Kind != "" {
+			role += " (" + msg.Kind + ")"
+		}
+		fmt.Fprintf(&b, "%d. %s: %s", i+1, role, text)
+	}
+	return b.String()
+}
+
+func pluralMessages(n int) string {
+	if n == 1 {
+		return "message"
+	}
+	return "messages"
+}
@@ -0,0 +1,447 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/agent"
+	"github.com/neoclaw-ai/neoclaw/internal/channels/httpapi"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultSoakConcurrency = 4
+	defaultSoakLatency     = 200 * time.Millisecond
+	// soakStartupWait bounds how long the throwaway server may take to
+	// accept connections.
+	soakStartupWait = 5 * time.Second
+	// soakSampleInterval is how often heap use is sampled for the peak.
+	soakSampleInterval = 100 * time.Millisecond
+)
+
+func newSoakCmd() *cobra.Command {
+	var (
+		script      string
+		concurrency int
+		rounds      int
+		latency     time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "soak",
+		Short: "Load a throwaway server with scripted conversations and report how it held up",
+		Long: "Start a throwaway HTTP API server on localhost, backed by a mock model that echoes after --latency, and have --concurrency simulated users send the messages in --script through it.\n\n" +
+			"The script has one message per line; blank lines and lines starting with # are skipped. Every user sends the whole script --rounds times, waiting for each reply before the next message. The report shows throughput, how long messages waited in the queue, reply times, and heap and goroutine growth.\n\n" +
+			"Nothing touches your sessions, memory, or API keys: the server keeps its files in a temporary directory under data/, removed at the end. The [context] settings from config.toml apply.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if concurrency < 1 {
+				return errors.New("--concurrency must be at least 1")
+			}
+			if rounds < 1 {
+				return errors.New("--rounds must be at least 1")
+			}
+			messages, err := loadSoakScript(script)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			// Per-message logs would bury the report.
+			if verbose, _ := cmd.Flags().GetBool("verbose"); !verbose {
+				logging.SetLevel(slog.LevelWarn)
+			}
+			result, err := runSoak(cmd.Context(), soakOptions{
+				dataDir:     cfg.DataDir(),
+				messages:    messages,
+				concurrency: concurrency,
+				rounds:      rounds,
+				latency:     latency,
+				context:     cfg.Context,
+				lowMemory:   cfg.LowMemory,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), result.format())
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&script, "script", "", "file with one message per line")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultSoakConcurrency, "number of simulated users sending at once")
+	cmd.Flags().IntVar(&rounds, "rounds", 1, "times each user sends the whole script")
+	cmd.Flags().DurationVar(&latency, "latency", defaultSoakLatency, "how long the mock model takes to answer")
+	_ = cmd.MarkFlagRequired("script")
+	return cmd
+}
+
+// loadSoakScript reads the messages in a soak script.
+func loadSoakScript(path string) ([]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read soak script: %w", err)
+	}
+	var messages []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		messages = append(messages, line)
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("soak script %s has no messages", path)
+	}
+	return messages, nil
+}
+
+type soakOptions struct {
+	// dataDir holds the throwaway agent's files while the soak runs.
+	dataDir     string
+	messages    []string
+	concurrency int
+	rounds      int
+	latency     time.Duration
+	context     config.ContextConfig
+	lowMemory   bool
+}
+
+// soakResult is what one soak run measured.
+type soakResult struct {
+	users   int
+	sent    int
+	failed  int
+	elapsed time.Duration
+	// firstError is the first failure, to show why messages failed.
+	firstError string
+	// waits are how long messages sat in the queue; replies are from
+	// posting a message to its done event.
+	waits   []time.Duration
+	replies []time.Duration
+
+	heapBefore, heapPeak, heapAfter   uint64
+	goroutinesBefore, goroutinesAfter int
+}
+
+// runSoak serves a throwaway agent over the HTTP API and drives it with
+// simulated users until every script is sent.
+func runSoak(ctx context.Context, opts soakOptions) (soakResult, error) {
+	// The process sandbox only allows writes under the data dir.
+	dir, err := os.MkdirTemp(opts.dataDir, "soak-")
+	if err != nil {
+		return soakResult{}, fmt.Errorf("create soak dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	agentDir, memoryDir := filepath.Join(dir, "agent"), filepath.Join(dir, "memory")
+	for _, sub := range []string{agentDir, memoryDir} {
+		if err := os.MkdirAll(sub, 0o755); err != nil {
+			return soakResult{}, fmt.Errorf("create soak dir: %w", err)
+		}
+	}
+	// Empty profile files keep the agent from warning on every turn.
+	for _, name := range []string{config.SoulFilePath, config.UserFilePath} {
+		if err := os.WriteFile(filepath.Join(agentDir, name), nil, 0o644); err != nil {
+			return soakResult{}, fmt.Errorf("create soak agent files: %w", err)
+		}
+	}
+	memoryStore, err := memory.New(memoryDir)
+	if err != nil {
+		return soakResult{}, err
+	}
+	handler := agent.NewWithSession(
+		provider.Mock{Latency: opts.latency},
+		tools.NewRegistry(),
+		nil,
+		agentDir,
+		session.New(filepath.Join(dir, "session.jsonl")),
+		memoryStore,
+		opts.context.MaxTokens,
+		opts.context.RecentMessages,
+		opts.context.MaxToolCalls,
+		opts.context.ToolOutputLength,
+		0,
+		opts.context,
+	)
+
+	addr, err := freeLocalAddr()
+	if err != nil {
+		return soakResult{}, err
+	}
+	users := make([]*soakUser, opts.concurrency)
+	keys := make([]string, opts.concurrency)
+	started := &soakHandler{next: handler, started: map[string]chan time.Time{}}
+	for i := range users {
+		keys[i] = fmt.Sprintf("soak-user-%d", i+1)
+		users[i] = &soakUser{addr: addr, key: keys[i], started: make(chan time.Time, 1)}
+		started.started[httpapi.ClientID(keys[i])] = users[i].started
+	}
+	listener := httpapi.New(addr, keys)
+	if opts.lowMemory {
+		listener.ConfigureQueueSize(lowMemoryQueueSize)
+	}
+
+	result := soakResult{users: opts.concurrency}
+	result.heapBefore, result.goroutinesBefore = measureMemory()
+
+	serverCtx, stopServer := context.WithCancel(ctx)
+	defer stopServer()
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- listener.Listen(serverCtx, started)
+	}()
+	if err := waitForListener(ctx, addr, serverErr); err != nil {
+		return soakResult{}, err
+	}
+
+	sampleCtx, stopSampling := context.WithCancel(ctx)
+	peak := make(chan uint64, 1)
+	go func() {
+		peak <- sampleHeapPeak(sampleCtx)
+	}()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	startedAt := time.Now()
+	for _, user := range users {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range opts.rounds {
+				for _, text := range opts.messages {
+					if ctx.Err() != nil {
+						return
+					}
+					wait, reply, err := user.send(ctx, text)
+					mu.Lock()
+					result.sent++
+					if err != nil {
+						result.failed++
+						if result.firstError == "" {
+							result.firstError = err.Error()
+						}
+					} else {
+						result.waits = append(result.waits, wait)
+						result.replies = append(result.replies, reply)
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	result.elapsed = time.Since(startedAt)
+	stopSampling()
+	result.heapPeak = <-peak
+	result.heapAfter, _ = measureMemory()
+
+	stopServer()
+	if err := <-serverErr; err != nil && !errors.Is(err, context.Canceled) {
+		return soakResult{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return soakResult{}, err
+	}
+	_, result.goroutinesAfter = measureMemory()
+	return result, nil
+}
+
+// soakHandler notes when each user's message leaves the queue.
+type soakHandler struct {
+	next    runtime.Handler
+	started map[string]chan time.Time
+}
+
+func (h *soakHandler) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	if started, ok := h.started[msg.UserID]; ok {
+		select {
+		case started <- time.Now():
+		default:
+		}
+	}
+	return h.next.HandleMessage(ctx, w, msg)
+}
+
+// soakUser is one simulated user with its own API key.
+type soakUser struct {
+	addr    string
+	key     string
+	started chan time.Time
+}
+
+// send posts text and reads the reply stream until done. wait is how long
+// the message was queued and reply how long the whole exchange took.
+func (u *soakUser) send(ctx context.Context, text string) (wait, reply time.Duration, err error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return 0, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+u.addr+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+u.key)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	// A start left over from a failed exchange is stale.
+	select {
+	case <-u.started:
+	default:
+	}
+	postedAt := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("post message: %s", resp.Status)
+	}
+	done := false
+	scanner := bufio.NewScanner(resp.Body)
+	for !done && scanner.Scan() {
+		switch strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "event:")) {
+		case "error":
+			err = errors.New("the server reported an error handling the message")
+		case "done":
+			done = true
+		}
+	}
+	if !done {
+		if scanErr := scanner.Err(); scanErr != nil {
+			return 0, 0, scanErr
+		}
+		return 0, 0, errors.New("reply stream ended before done")
+	}
+	reply = time.Since(postedAt)
+	if err != nil {
+		return 0, 0, err
+	}
+	select {
+	case startedAt := <-u.started:
+		wait = startedAt.Sub(postedAt)
+	default:
+	}
+	return wait, reply, nil
+}
+
+// freeLocalAddr returns a localhost address with a port that was free a
+// moment ago.
+func freeLocalAddr() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("find a free port: %w", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr, nil
+}
+
+// waitForListener returns once addr accepts connections, or with the
+// server's error if it stopped first.
+func waitForListener(ctx context.Context, addr string, serverErr <-chan error) error {
+	deadline := time.Now().Add(soakStartupWait)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, 100*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("soak server did not start on %s: %w", addr, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-serverErr:
+			return fmt.Errorf("soak server: %w", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+// measureMemory collects garbage and returns live heap bytes and the
+// number of goroutines.
+func measureMemory() (uint64, int) {
+	goruntime.GC()
+	var stats goruntime.MemStats
+	goruntime.ReadMemStats(&stats)
+	return stats.HeapAlloc, goruntime.NumGoroutine()
+}
+
+// sampleHeapPeak returns the highest heap use seen until ctx is done.
+func sampleHeapPeak(ctx context.Context) uint64 {
+	ticker := time.NewTicker(soakSampleInterval)
+	defer ticker.Stop()
+	var peak uint64
+	var stats goruntime.MemStats
+	for {
+		goruntime.ReadMemStats(&stats)
+		peak = max(peak, stats.HeapAlloc)
+		select {
+		case <-ctx.Done():
+			return peak
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r soakResult) format() string {
+	var b strings.Builder
+	rate := 0.0
+	if seconds := r.elapsed.Seconds(); seconds > 0 {
+		rate = float64(r.sent-r.failed) / seconds
+	}
+	fmt.Fprintf(&b, "Sent %d messages from %d users in %s: %.1f messages/s, %d failed.\n", r.sent, r.users, r.elapsed.Round(time.Millisecond), rate, r.failed)
+	if r.firstError != "" {
+		fmt.Fprintf(&b, "First failure: %s\n", r.firstError)
+	}
+	if len(r.replies) > 0 {
+		fmt.Fprintf(&b, "Queue wait:  %s\n", formatLatencies(r.waits))
+		fmt.Fprintf(&b, "Reply time:  %s\n", formatLatencies(r.replies))
+	}
+	growth := int64(r.heapAfter) - int64(r.heapBefore)
+	fmt.Fprintf(&b, "Heap:        %s before, %s peak, %s after (%+.1f MB)\n", formatMB(r.heapBefore), formatMB(r.heapPeak), formatMB(r.heapAfter), float64(growth)/(1<<20))
+	fmt.Fprintf(&b, "Goroutines:  %d before, %d after shutdown\n", r.goroutinesBefore, r.goroutinesAfter)
+	return b.String()
+}
+
+// formatLatencies summarizes durations as p50, p95, and max.
+func formatLatencies(durations []time.Duration) string {
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	percentile := func(p float64) time.Duration {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)].Round(time.Millisecond)
+	}
+	return fmt.Sprintf("p50 %s, p95 %s, max %s", percentile(0.5), percentile(0.95), sorted[len(sorted)-1].Round(time.Millisecond))
+}
+
+func formatMB(n uint64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
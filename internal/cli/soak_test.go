@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunSoakDrivesScriptThroughHTTPAPI(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "script.txt")
+	if err := os.WriteFile(script, []byte("# greeting\nhello\n\nwhat's the weather?\n"), 0o644); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	messages, err := loadSoakScript(script)
+	if err != nil {
+		t.Fatalf("load script: %v", err)
+	}
+	if len(messages) != 2 || messages[0] != "hello" {
+		t.Fatalf("unexpected messages %q", messages)
+	}
+
+	result, err := runSoak(context.Background(), soakOptions{dataDir: t.TempDir(), messages: messages, concurrency: 3, rounds: 2, latency: time.Millisecond})
+	if err != nil {
+		t.Fatalf("soak: %v", err)
+	}
+	if result.sent != 12 || result.failed != 0 || len(result.replies) != 12 || len(result.waits) != 12 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	report := result.format()
+	for _, want := range []string{"Sent 12 messages from 3 users", "0 failed", "Queue wait:  p50 ", "Heap: ", "Goroutines: "} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("expected %q in report:\n%s", want, report)
+		}
+	}
+}
@@ -5,129 +5,416 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/neoclaw-ai/neoclaw/internal/agent"
 	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
+	"github.com/neoclaw-ai/neoclaw/internal/bootstrap"
 	"github.com/neoclaw-ai/neoclaw/internal/channels"
+	"github.com/neoclaw-ai/neoclaw/internal/channels/httpapi"
+	"github.com/neoclaw-ai/neoclaw/internal/channels/webui"
 	"github.com/neoclaw-ai/neoclaw/internal/commands"
 	"github.com/neoclaw-ai/neoclaw/internal/config"
 	"github.com/neoclaw-ai/neoclaw/internal/costs"
+	"github.com/neoclaw-ai/neoclaw/internal/crash"
+	"github.com/neoclaw-ai/neoclaw/internal/language"
+	"github.com/neoclaw-ai/neoclaw/internal/lists"
+	"github.com/neoclaw-ai/neoclaw/internal/liveness"
 	"github.com/neoclaw-ai/neoclaw/internal/logging"
 	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/notify"
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
+	"github.com/neoclaw-ai/neoclaw/internal/presence"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
 	"github.com/neoclaw-ai/neoclaw/internal/sandbox"
 	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
 	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/telemetry"
+	"github.com/neoclaw-ai/neoclaw/internal/todo"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+	"github.com/neoclaw-ai/neoclaw/internal/update"
+	"github.com/neoclaw-ai/neoclaw/internal/workflow"
 	"github.com/spf13/cobra"
 )
 
-var startTelegramFunc = startTelegram
+var startChannelsFunc = startChannels
+
+// shutdownTimeout bounds how long the server waits for running jobs after a
+// stop signal. Container runtimes wait 10s before SIGKILL by default.
+const shutdownTimeout = 5 * time.Second
 
 func newStartCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "start",
 		Short: "Start the server",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runServer(cmd, false)
+		},
+	}
+}
+
+// newServeCmd is start for containers and service managers. It is usually
+// run with --config-from-env, which the root command applies.
+func newServeCmd() *cobra.Command {
+	var web bool
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the server in the foreground, e.g. in a container",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			cfg, err := config.Load()
 			if err != nil {
 				return err
 			}
-			if err := cfg.Validate(); err != nil {
-				return err
-			}
-			if cfg.Security.Mode == config.SecurityModeStrict && !sandbox.IsSandboxSupported() {
-				return errors.New("security.mode strict requires sandbox support on this platform")
+			if bootstrap.InContainer() {
+				if onVolume, ok := bootstrap.OnVolume(cfg.HomeDir); ok && !onVolume {
+					logging.Logger().Warn("NeoClaw home is not on a mounted volume; memory, sessions, and policy are lost when the container is removed", "home", cfg.HomeDir)
+				}
 			}
-			warnStartupConditions(cfg)
-
-			llm := cfg.DefaultLLM()
-			logging.Logger().Info(
-				"starting server",
-				"agent", cfg.Agent,
-				"provider", llm.Provider,
-				"model", llm.Model,
-				"security_mode", cfg.Security.Mode,
-				"data_dir", cfg.DataDir(),
-			)
+			return runServer(cmd, web)
+		},
+	}
+	cmd.Flags().BoolVar(&web, "web", false, "Also serve the browser chat ([channels.web]), on 127.0.0.1:8788 by default")
+	return cmd
+}
 
-			pidFilePath := cfg.PIDPath()
-			if err := os.WriteFile(pidFilePath, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0o644); err != nil {
-				return fmt.Errorf("write pid file %s: %w", pidFilePath, err)
-			}
-			defer func() {
-				os.Remove(pidFilePath)
-			}()
+// runServer runs the scheduler and channels until a stop signal or a
+// listener failure. web enables the browser chat even if [channels.web] is
+// not in config.toml.
+func runServer(cmd *cobra.Command, web bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if web {
+		if cfg.Channels == nil {
+			cfg.Channels = map[string]config.ChannelConfig{}
+		}
+		webCfg := cfg.Channels[config.WebChannelName]
+		webCfg.Enabled = true
+		cfg.Channels[config.WebChannelName] = webCfg
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.Security.Mode == config.SecurityModeStrict && !sandbox.IsSandboxSupported() {
+		return errors.New("security.mode strict requires sandbox support on this platform")
+	}
+	if err := logging.SetFile(cfg.ServerLogPath()); err != nil {
+		logging.Logger().Warn("failed to open log file; logging to stderr only", "path", cfg.ServerLogPath(), "err", err)
+	}
+	warnStartupConditions(cfg)
 
-			channelWriters := map[string]io.Writer{
-				"cli": cmd.OutOrStdout(),
-			}
-			service, err := newSchedulerService(cfg, channelWriters)
-			if err != nil {
-				return err
-			}
+	llm := cfg.DefaultLLM()
+	logging.Logger().Info(
+		"starting server",
+		"agent", cfg.Agent,
+		"provider", llm.Provider,
+		"model", llm.Model,
+		"security_mode", cfg.Security.Mode,
+		"data_dir", cfg.DataDir(),
+	)
 
-			runCtx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
-			defer stop()
-			telegramErrCh, err := startTelegramFunc(runCtx, cfg, cmd.OutOrStdout(), channelWriters, service)
-			if err != nil {
-				return err
-			}
-			if err := service.Start(runCtx); err != nil {
+	pidFilePath := cfg.PIDPath()
+	if err := os.WriteFile(pidFilePath, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0o644); err != nil {
+		return fmt.Errorf("write pid file %s: %w", pidFilePath, err)
+	}
+	defer func() {
+		os.Remove(pidFilePath)
+	}()
+
+	quietHours, err := config.ParseQuietHours(cfg.Notifications.QuietHours)
+	if err != nil {
+		return err
+	}
+	gate := notify.NewGate(quietHours)
+	if err := gate.LoadState(cfg.NotificationsPath()); err != nil {
+		logging.Logger().Warn("held notifications and do-not-disturb were not restored", "err", err)
+	}
+
+	channelWriters := map[string]io.Writer{
+		"cli": cmd.OutOrStdout(),
+	}
+	service, err := newSchedulerService(cfg, channelWriters, gate)
+	if err != nil {
+		return err
+	}
+	if err := registerProactiveCheckIn(cfg, service); err != nil {
+		return err
+	}
+	if err := registerWorkspaceCleanup(cfg, service); err != nil {
+		return err
+	}
+	if err := registerMemoryRetention(cfg, service); err != nil {
+		return err
+	}
+	if err := registerMemoryDigest(cfg, service); err != nil {
+		return err
+	}
+
+	alarm := &canaryAlarm{}
+	alarm.setWriters(channelWriters)
+	if err := armCanaries(cfg, alarm.sound); err != nil {
+		return err
+	}
+
+	runCtx, stop, restarting := shutdownContext(cmd.Context())
+	defer stop()
+	listenerErrCh, err := startChannelsFunc(runCtx, cfg, cmd.OutOrStdout(), channelWriters, service, gate)
+	if err != nil {
+		return err
+	}
+	alarm.setWriters(channelWriters)
+	// Scheduler output is unprompted, so route every channel writer
+	// through the quiet-hours/DND gate. Direct replies do not use these.
+	for channelID, writer := range channelWriters {
+		channelWriters[channelID] = gate.Writer(channelID, writer)
+	}
+	alertPolicyChanges(cfg, channelWriters)
+	alertMemoryQuarantines(channelWriters)
+	go gate.Run(runCtx, notify.DefaultFlushInterval)
+	if err := service.Start(runCtx); err != nil {
+		stop()
+		return err
+	}
+	go liveness.Run(runCtx, cfg.HeartbeatPath(), liveness.DefaultInterval)
+	if err := startTelemetry(runCtx, cfg); err != nil {
+		logging.Logger().Warn("telemetry disabled", "err", err)
+	}
+
+	var listenerErr error
+	if listenerErrCh == nil {
+		<-runCtx.Done()
+	} else {
+		for {
+			select {
+			case <-runCtx.Done():
+				listenerErrCh = nil
+			case err, ok := <-listenerErrCh:
+				if !ok {
+					listenerErrCh = nil
+					continue
+				}
+				listenerErr = err
 				stop()
-				return err
+				listenerErrCh = nil
 			}
+			if listenerErrCh == nil {
+				break
+			}
+		}
+	}
 
-			var listenerErr error
-			if telegramErrCh == nil {
-				<-runCtx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := service.Stop(shutdownCtx); err != nil {
+		return err
+	}
+	if listenerErr != nil {
+		if path := crash.Fatal(listenerErr); path != "" {
+			return fmt.Errorf("%w (crash report: %s)", listenerErr, path)
+		}
+		return listenerErr
+	}
+	if restarting() {
+		return restartServer(cmd.Context())
+	}
+	logging.Logger().Info("server stopped")
+	return nil
+}
+
+// startTelemetry reports usage counts in the background if the user opted in.
+func startTelemetry(ctx context.Context, cfg *config.Config) error {
+	state, err := telemetry.LoadState(cfg.TelemetryPath())
+	if err != nil || !state.Enabled {
+		return err
+	}
+	reporter := telemetry.Reporter{
+		StatePath:  cfg.TelemetryPath(),
+		CountsPath: cfg.TelemetryCountsPath(),
+		Endpoint:   strings.TrimSpace(cfg.Telemetry.Endpoint),
+		Version:    Version,
+		Client:     &http.Client{Timeout: 30 * time.Second},
+	}
+	go reporter.Run(ctx)
+	return nil
+}
+
+// shutdownContext is cancelled on the first SIGINT or SIGTERM so the server
+// can stop cleanly; a second signal exits at once in case shutdown hangs.
+// update.RestartSignal also stops the server, and restarting then reports
+// true so it can start again from the binary on disk.
+func shutdownContext(parent context.Context) (ctx context.Context, stop context.CancelFunc, restarting func() bool) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, update.RestartSignal)
+	released := make(chan struct{})
+	var restart atomic.Bool
+	var once sync.Once
+	go func() {
+		select {
+		case sig := <-signals:
+			if sig == update.RestartSignal {
+				logging.Logger().Info("restarting", "signal", sig.String())
+				restart.Store(true)
 			} else {
-				listenerErrCh := telegramErrCh
-				for {
-					select {
-					case <-runCtx.Done():
-						listenerErrCh = nil
-					case err, ok := <-listenerErrCh:
-						if !ok {
-							listenerErrCh = nil
-							continue
-						}
-						listenerErr = err
-						stop()
-						listenerErrCh = nil
-					}
-					if listenerErrCh == nil {
-						break
-					}
-				}
+				logging.Logger().Info("shutting down", "signal", sig.String())
 			}
+			cancel()
+		case <-ctx.Done():
+		case <-released:
+			return
+		}
+		select {
+		case sig := <-signals:
+			logging.Logger().Warn("second stop signal; exiting without cleanup", "signal", sig.String())
+			os.Exit(1)
+		case <-released:
+		}
+	}()
+	return ctx, func() {
+		cancel()
+		once.Do(func() {
+			signal.Stop(signals)
+			close(released)
+		})
+	}, restart.Load
+}
 
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if err := service.Stop(shutdownCtx); err != nil {
-				return err
-			}
-			if listenerErr != nil {
-				return listenerErr
+// restartServer replaces this process with the claw binary now on disk,
+// keeping the pid, arguments, and environment. It only returns on failure.
+func restartServer(ctx context.Context) error {
+	flushStorage(ctx)
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("restart: find claw binary: %w", err)
+	}
+	logging.Logger().Info("server stopped; starting again", "binary", exe)
+	if err := syscall.Exec(exe, os.Args, os.Environ()); err != nil {
+		return fmt.Errorf("restart %s: %w", exe, err)
+	}
+	return nil
+}
+
+// startChannels starts a listener for every enabled Telegram bot and for
+// Slack, Matrix, WhatsApp, the HTTP API, and the web chat. The returned channel carries listener failures and closes once all
+// listeners have stopped. With notifications.follow_presence, each
+// Telegram user's scheduler channels deliver through the bot that user last
+// wrote to.
+func startChannels(
+	ctx context.Context,
+	cfg *config.Config,
+	out io.Writer,
+	channelWriters map[string]io.Writer,
+	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+) (<-chan error, error) {
+	var seen *presence.Store
+	if cfg.Notifications.FollowPresence {
+		seen = presence.New(cfg.PresencePath())
+	}
+	var names []string
+	var errChs []<-chan error
+	for _, name := range cfg.TelegramBots() {
+		errCh, err := startTelegram(ctx, cfg, name, out, channelWriters, schedulerService, gate, seen)
+		if err != nil {
+			return nil, fmt.Errorf("channels.%s: %w", name, err)
+		}
+		names = append(names, name)
+		errChs = append(errChs, errCh)
+	}
+	if seen != nil {
+		followPresence(channelWriters, seen)
+	}
+	if cfg.Channels[config.SlackChannelName].Enabled {
+		errCh, err := startSlack(ctx, cfg, out, channelWriters, schedulerService, gate)
+		if err != nil {
+			return nil, fmt.Errorf("channels.%s: %w", config.SlackChannelName, err)
+		}
+		names = append(names, config.SlackChannelName)
+		errChs = append(errChs, errCh)
+	}
+	if cfg.Channels[config.MatrixChannelName].Enabled {
+		errCh, err := startMatrix(ctx, cfg, out, channelWriters, schedulerService, gate)
+		if err != nil {
+			return nil, fmt.Errorf("channels.%s: %w", config.MatrixChannelName, err)
+		}
+		names = append(names, config.MatrixChannelName)
+		errChs = append(errChs, errCh)
+	}
+	if cfg.Channels[config.WhatsAppChannelName].Enabled {
+		errCh, err := startWhatsApp(ctx, cfg, out, channelWriters, schedulerService, gate)
+		if err != nil {
+			return nil, fmt.Errorf("channels.%s: %w", config.WhatsAppChannelName, err)
+		}
+		names = append(names, config.WhatsAppChannelName)
+		errChs = append(errChs, errCh)
+	}
+	if cfg.Channels[config.HTTPChannelName].Enabled {
+		errCh, err := startHTTP(ctx, cfg, out, channelWriters, schedulerService, gate)
+		if err != nil {
+			return nil, fmt.Errorf("channels.%s: %w", config.HTTPChannelName, err)
+		}
+		names = append(names, config.HTTPChannelName)
+		errChs = append(errChs, errCh)
+	}
+	if cfg.Channels[config.WebChannelName].Enabled {
+		errCh, err := startWeb(ctx, cfg, out, channelWriters, schedulerService, gate)
+		if err != nil {
+			return nil, fmt.Errorf("channels.%s: %w", config.WebChannelName, err)
+		}
+		names = append(names, config.WebChannelName)
+		errChs = append(errChs, errCh)
+	}
+	switch len(errChs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return errChs[0], nil
+	}
+
+	merged := make(chan error, len(errChs))
+	var wg sync.WaitGroup
+	for i, errCh := range errChs {
+		wg.Add(1)
+		go func(name string, errCh <-chan error) {
+			defer wg.Done()
+			for err := range errCh {
+				merged <- fmt.Errorf("channels.%s: %w", name, err)
 			}
-			logging.Logger().Info("server stopped")
-			return nil
-		},
+		}(names[i], errCh)
 	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged, nil
 }
 
+// startTelegram starts the bot configured in [channels.<name>]. Bots bound
+// to another agent get that agent's sessions, memory, and workspace.
 func startTelegram(
 	ctx context.Context,
 	cfg *config.Config,
+	name string,
 	out io.Writer,
 	channelWriters map[string]io.Writer,
 	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+	seen *presence.Store,
 ) (<-chan error, error) {
-	telegramCfg := cfg.TelegramChannel()
+	telegramCfg := cfg.Channels[name]
 	if !telegramCfg.Enabled {
 		return nil, nil
 	}
@@ -136,21 +423,303 @@ func startTelegram(
 	if token == "" {
 		return nil, errors.New("telegram is enabled but token is empty")
 	}
+	if agent := telegramCfg.AgentName(); agent != cfg.Agent {
+		cfg = cfg.ForAgent(agent)
+		if err := bootstrap.Initialize(cfg); err != nil {
+			return nil, err
+		}
+	}
 
-	logging.Logger().Info("Starting Telegram listener")
-	allowedUsersPath := cfg.AllowedUsersPath()
+	logging.Logger().Info("Starting Telegram listener", "bot", name, "agent", cfg.Agent)
+	allowedUsersPath := cfg.AllowedUsersPathFor(name)
 	listener := channels.NewTelegram(token, allowedUsersPath)
+	listener.ConfigureChannelName(name)
+	listener.ConfigureOutboundFilter(cfg.Privacy.OutboundSecrets)
+	listener.ConfigurePendingApprovals(cfg.PendingApprovalsPathFor(name))
+	listener.ConfigureApprovalTimeout(cfg.Security.ApprovalTimeout)
+	listener.ConfigurePresence(seen)
+	listener.ConfigureWebhook(channels.TelegramWebhook{
+		URL:      telegramCfg.WebhookURL,
+		Listen:   telegramCfg.WebhookListen,
+		CertFile: telegramCfg.WebhookCertFile,
+		KeyFile:  telegramCfg.WebhookKeyFile,
+	})
+	if cfg.LowMemory {
+		listener.ConfigureQueueSize(lowMemoryQueueSize)
+	}
 	if err := registerTelegramChannelWriters(channelWriters, allowedUsersPath, listener); err != nil {
 		return nil, err
 	}
 
-	llmCfg := cfg.DefaultLLM()
-	modelProvider, err := providerFactory(llmCfg)
+	shared, err := newChannelAgent(cfg, name, telegramCfg, out, listener, schedulerService, gate)
 	if err != nil {
 		return nil, err
 	}
+	// Each chat, keyed like its scheduler channel, keeps its own history.
+	router := newChatRouters(shared, cfg.TelegramChatContextPath)
+	listener.ConfigureInlineQueries(router)
 
-	memoryStore, err := memory.New(cfg.MemoryDir())
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer router.Detach()
+		if err := listener.Listen(ctx, router); err != nil && !errors.Is(err, context.Canceled) {
+			errCh <- err
+		}
+	}()
+	return errCh, nil
+}
+
+// runnableListener is a chat listener startChannel can configure and run.
+type runnableListener interface {
+	channelListener
+	Listen(ctx context.Context, handler runtime.Handler) error
+	ConfigureQueueSize(size int)
+	ConfigureOutboundFilter(mode string)
+	ConfigureApprovalTimeout(timeout time.Duration)
+}
+
+// startChannel starts the listener of the [channels.<name>] entry. It
+// serves the agent named by the entry's agent key, like a Telegram bot.
+// open builds the listener for that agent's config, registers its
+// send_message writers, and returns the channel's session path.
+func startChannel(
+	ctx context.Context,
+	cfg *config.Config,
+	name string,
+	out io.Writer,
+	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+	open func(cfg *config.Config, channelCfg config.ChannelConfig) (runnableListener, string, error),
+) (<-chan error, error) {
+	channelCfg := cfg.Channels[name]
+	if agent := channelCfg.AgentName(); agent != cfg.Agent {
+		cfg = cfg.ForAgent(agent)
+		if err := bootstrap.Initialize(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, sessionPath, err := open(cfg, channelCfg)
+	if err != nil {
+		return nil, err
+	}
+	listener.ConfigureOutboundFilter(cfg.Privacy.OutboundSecrets)
+	listener.ConfigureApprovalTimeout(cfg.Security.ApprovalTimeout)
+	if cfg.LowMemory {
+		listener.ConfigureQueueSize(lowMemoryQueueSize)
+	}
+
+	router, handler, err := newChannelRouter(cfg, name, channelCfg, out, sessionPath, listener, schedulerService, gate)
+	if err != nil {
+		return nil, err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer handler.Detach()
+		if err := listener.Listen(ctx, router); err != nil && !errors.Is(err, context.Canceled) {
+			errCh <- err
+		}
+	}()
+	return errCh, nil
+}
+
+// userWriters is a listener that delivers to users by their channel ID.
+type userWriters interface {
+	ChannelKey(userID string) string
+	ChannelWriter(userID string) io.Writer
+}
+
+// addUserWriters registers a writer for every user of channel in the
+// allowed users file. Observers are only supported on Telegram.
+func addUserWriters(cfg *config.Config, channel string, listener userWriters, channelWriters map[string]io.Writer) error {
+	usersFile, err := approval.LoadUsers(cfg.AllowedUsersPath())
+	if err != nil {
+		return fmt.Errorf("load allowed users %s: %w", cfg.AllowedUsersPath(), err)
+	}
+	for _, user := range usersFile.Users {
+		if id := strings.TrimSpace(user.ID); id != "" && !user.IsObserver() && strings.EqualFold(strings.TrimSpace(user.Channel), channel) {
+			channelWriters[listener.ChannelKey(id)] = listener.ChannelWriter(id)
+		}
+	}
+	return nil
+}
+
+// startSlack starts the [channels.slack] listener.
+func startSlack(
+	ctx context.Context,
+	cfg *config.Config,
+	out io.Writer,
+	channelWriters map[string]io.Writer,
+	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+) (<-chan error, error) {
+	return startChannel(ctx, cfg, config.SlackChannelName, out, schedulerService, gate, func(cfg *config.Config, slackCfg config.ChannelConfig) (runnableListener, string, error) {
+		logging.Logger().Info("Starting Slack listener", "agent", cfg.Agent)
+		listener := channels.NewSlack(strings.TrimSpace(slackCfg.AppToken), strings.TrimSpace(slackCfg.Token), cfg.AllowedUsersPath())
+		if err := addUserWriters(cfg, channels.SlackChannel, listener, channelWriters); err != nil {
+			return nil, "", err
+		}
+		return listener, cfg.SlackContextPath(), nil
+	})
+}
+
+// startMatrix starts the [channels.matrix] listener.
+func startMatrix(
+	ctx context.Context,
+	cfg *config.Config,
+	out io.Writer,
+	channelWriters map[string]io.Writer,
+	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+) (<-chan error, error) {
+	return startChannel(ctx, cfg, config.MatrixChannelName, out, schedulerService, gate, func(cfg *config.Config, matrixCfg config.ChannelConfig) (runnableListener, string, error) {
+		logging.Logger().Info("Starting Matrix listener", "homeserver", matrixCfg.Homeserver, "agent", cfg.Agent)
+		listener := channels.NewMatrix(strings.TrimSpace(matrixCfg.Homeserver), strings.TrimSpace(matrixCfg.Token), cfg.AllowedUsersPath())
+		if err := addUserWriters(cfg, channels.MatrixChannel, listener, channelWriters); err != nil {
+			return nil, "", err
+		}
+		return listener, cfg.MatrixContextPath(), nil
+	})
+}
+
+// startWhatsApp starts the [channels.whatsapp] listener.
+func startWhatsApp(
+	ctx context.Context,
+	cfg *config.Config,
+	out io.Writer,
+	channelWriters map[string]io.Writer,
+	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+) (<-chan error, error) {
+	return startChannel(ctx, cfg, config.WhatsAppChannelName, out, schedulerService, gate, func(cfg *config.Config, whatsappCfg config.ChannelConfig) (runnableListener, string, error) {
+		logging.Logger().Info("Starting WhatsApp listener", "agent", cfg.Agent)
+		listener := channels.NewWhatsApp(whatsAppConfig(whatsappCfg), cfg.AllowedUsersPath())
+		listener.ConfigureTemplate(channels.WhatsAppTemplate{Name: whatsappCfg.Template, Language: whatsappCfg.TemplateLanguage})
+		if err := addUserWriters(cfg, channels.WhatsAppChannel, listener, channelWriters); err != nil {
+			return nil, "", err
+		}
+		return listener, cfg.WhatsAppContextPath(), nil
+	})
+}
+
+// whatsAppConfig picks the Cloud API settings out of a channel entry.
+func whatsAppConfig(channelCfg config.ChannelConfig) channels.WhatsAppConfig {
+	return channels.WhatsAppConfig{
+		PhoneNumberID: channelCfg.PhoneNumberID,
+		Token:         channelCfg.Token,
+		AppSecret:     channelCfg.AppSecret,
+		VerifyToken:   channelCfg.VerifyToken,
+		Listen:        channelCfg.Listen,
+	}
+}
+
+// startHTTP starts the [channels.http] API.
+func startHTTP(
+	ctx context.Context,
+	cfg *config.Config,
+	out io.Writer,
+	channelWriters map[string]io.Writer,
+	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+) (<-chan error, error) {
+	return startChannel(ctx, cfg, config.HTTPChannelName, out, schedulerService, gate, func(cfg *config.Config, httpCfg config.ChannelConfig) (runnableListener, string, error) {
+		logging.Logger().Info("Starting HTTP API", "agent", cfg.Agent)
+		listener := httpapi.New(strings.TrimSpace(httpCfg.Listen), httpCfg.APIKeys)
+		listener.ConfigureWorkflows(cfg.WorkflowsDir())
+		for _, id := range listener.ClientIDs() {
+			channelWriters[listener.ChannelKey(id)] = listener.ChannelWriter(id)
+		}
+		return listener, cfg.HTTPContextPath(), nil
+	})
+}
+
+// startWeb starts the browser chat. Every open tab shares one session.
+func startWeb(
+	ctx context.Context,
+	cfg *config.Config,
+	out io.Writer,
+	channelWriters map[string]io.Writer,
+	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+) (<-chan error, error) {
+	return startChannel(ctx, cfg, config.WebChannelName, out, schedulerService, gate, func(cfg *config.Config, webCfg config.ChannelConfig) (runnableListener, string, error) {
+		logging.Logger().Info("Starting web chat", "agent", cfg.Agent)
+		listener, err := webui.New(strings.TrimSpace(webCfg.Listen), webCfg.Token)
+		if err != nil {
+			return nil, "", err
+		}
+		channelWriters[listener.ChannelKey()] = listener.ChannelWriter()
+		return listener, cfg.WebContextPath(), nil
+	})
+}
+
+// channelListener is a chat listener serving one agent: it answers
+// approvals and delivers send_message output for the current request.
+type channelListener interface {
+	approval.Approver
+	tools.ChannelMessageSender
+	CurrentChannelID() string
+}
+
+// newChannelRouter builds the agent and slash commands behind one chat
+// channel. The session at sessionPath is the channel's conversation, and
+// /attach can share it with the CLI.
+func newChannelRouter(
+	cfg *config.Config,
+	name string,
+	channelCfg config.ChannelConfig,
+	out io.Writer,
+	sessionPath string,
+	listener channelListener,
+	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+) (commands.Router, *agent.Agent, error) {
+	shared, err := newChannelAgent(cfg, name, channelCfg, out, listener, schedulerService, gate)
+	if err != nil {
+		return commands.Router{}, nil, err
+	}
+	return shared.router(name, sessionPath, "")
+}
+
+// channelAgent holds what every conversation on one channel shares: the
+// model, memory, tools, and cost tracking.
+type channelAgent struct {
+	cfg              *config.Config
+	name             string
+	channelCfg       config.ChannelConfig
+	listener         channelListener
+	schedulerService *scheduler.Service
+	gate             *notify.Gate
+	modelProvider    provider.Provider
+	memoryStore      *memory.Store
+	registry         *tools.Registry
+	costTracker      *costs.Tracker
+	toolOutputs      *outputs.Store
+	languages        *language.Store
+}
+
+func newChannelAgent(
+	cfg *config.Config,
+	name string,
+	channelCfg config.ChannelConfig,
+	out io.Writer,
+	listener channelListener,
+	schedulerService *scheduler.Service,
+	gate *notify.Gate,
+) (*channelAgent, error) {
+	modelProvider, err := newModelProvider(cfg, func(ctx context.Context, text string) {
+		if err := listener.Send(ctx, text); err != nil {
+			logging.Logger().Warn("failed to send provider notice", "err", err)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	memoryStore, err := openMemoryStore(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -158,16 +727,63 @@ func startTelegram(
 	if err != nil {
 		return nil, err
 	}
+	toolOutputs, err := openOutputStore(cfg, cfg.ToolOutputsDir())
+	if err != nil {
+		return nil, err
+	}
+	return &channelAgent{
+		cfg:              cfg,
+		name:             name,
+		channelCfg:       channelCfg,
+		listener:         listener,
+		schedulerService: schedulerService,
+		gate:             gate,
+		modelProvider:    modelProvider,
+		memoryStore:      memoryStore,
+		registry:         registry,
+		costTracker:      costs.New(cfg.CostsPath()),
+		toolOutputs:      toolOutputs,
+		languages:        language.New(cfg.LanguagesPath()),
+	}, nil
+}
 
-	costTracker := costs.New(cfg.CostsPath())
-	sessionStore := session.New(cfg.TelegramContextPath())
+// router builds the agent and slash commands for one conversation kept at
+// sessionPath. bridgeName is what /attach calls it. A non-empty chatKey
+// makes it one chat among several on the channel: its tool outputs,
+// artifacts, and /session list are its own, and it cannot /attach to the
+// CLI conversation.
+func (c *channelAgent) router(bridgeName, sessionPath, chatKey string) (commands.Router, *agent.Agent, error) {
+	cfg := c.cfg
+	llmCfg := cfg.DefaultLLM()
+	sessionStore, err := openSessionStore(cfg, sessionPath)
+	if err != nil {
+		return commands.Router{}, nil, err
+	}
+	toolOutputs := c.toolOutputs
+	sessionsDir := cfg.SessionsDir()
+	artifactStore := artifacts.New(cfg.ArtifactsPath())
+	bridges := map[string]*session.Store{}
+	if chatKey != "" {
+		toolOutputs, err = openOutputStore(cfg, cfg.ChatToolOutputsDir(chatKey))
+		if err != nil {
+			return commands.Router{}, nil, err
+		}
+		sessionsDir = filepath.Dir(sessionPath)
+		artifactStore = artifactStore.ForChannel(chatKey)
+	} else {
+		cliSession, err := openSessionStore(cfg, cfg.CLIContextPath())
+		if err != nil {
+			return commands.Router{}, nil, err
+		}
+		bridges["cli"] = cliSession
+	}
 	handler := agent.NewWithSession(
-		modelProvider,
-		registry,
-		listener,
+		c.modelProvider,
+		c.registry,
+		c.listener,
 		cfg.AgentDir(),
 		sessionStore,
-		memoryStore,
+		c.memoryStore,
 		cfg.Context.MaxTokens,
 		cfg.Context.RecentMessages,
 		cfg.Context.MaxToolCalls,
@@ -176,26 +792,142 @@ func startTelegram(
 		cfg.Context,
 	)
 	handler.ConfigureCosts(
-		costTracker,
+		c.costTracker,
 		llmCfg.Provider,
 		llmCfg.Model,
 		cfg.Costs.DailyLimit,
 		cfg.Costs.MonthlyLimit,
 	)
+	handler.ConfigureMemoryWrites(cfg.Privacy.MemoryWrites)
+	handler.ConfigureOutputs(toolOutputs)
+	handler.ConfigureWorkspace(cfg.WorkspaceDir())
+	handler.ConfigureProjects(cfg.Projects)
+	handler.ConfigureLanguages(c.languages)
+	handler.ConfigureDeletionLog(cfg.SessionDeletionsPath())
+	if err := configureCodeReview(handler, cfg); err != nil {
+		return commands.Router{}, nil, err
+	}
+	if err := configureResponseFormat(handler, c.channelCfg.ResponseFormat, c.channelCfg.ResponseSchema); err != nil {
+		return commands.Router{}, nil, fmt.Errorf("%s: %w", c.name, err)
+	}
+	configurePostProcess(handler, c.channelCfg)
+	handler.ConfigureBridge(bridgeName, bridges)
 
+	commandHandler := commands.New(handler, c.schedulerService, c.costTracker, cfg.Costs.DailyLimit, cfg.Costs.MonthlyLimit)
+	commandHandler.ConfigureSessions(sessionsDir)
+	commandHandler.ConfigureProfile(cfg.AgentDir())
+	commandHandler.ConfigurePrompts(cfg.PromptsDir())
+	commandHandler.ConfigureArtifacts(artifactStore)
+	commandHandler.ConfigureToolOutputs(toolOutputs)
+	commandHandler.ConfigureTasks(todo.New(cfg.TasksPath()))
+	commandHandler.ConfigureLists(lists.New(cfg.ListsPath()))
+	commandHandler.ConfigureLanguages(c.languages)
+	commandHandler.ConfigureIncognito(handler)
+	commandHandler.ConfigureCorrections(handler)
+	commandHandler.ConfigureForks(handler)
+	commandHandler.ConfigureBridge(handler)
+	commandHandler.ConfigureRewind(handler)
+	commandHandler.ConfigureDeletions(handler)
+	commandHandler.ConfigureLastTurn(handler)
+	commandHandler.ConfigurePromptBlocks(handler)
+	commandHandler.ConfigureProjects(handler)
+	commandHandler.ConfigureMemoryReview(c.memoryStore)
+	commandHandler.ConfigureWorkflows(&workflow.Runner{
+		Dir:      cfg.WorkflowsDir(),
+		StateDir: cfg.WorkflowRunsDir(),
+		Prompt:   handler.Complete,
+		Registry: c.registry,
+		Approver: c.listener,
+	})
+	commandHandler.ConfigureDND(c.gate)
 	router := commands.Router{
-		Commands: commands.New(handler, schedulerService, costTracker, cfg.Costs.DailyLimit, cfg.Costs.MonthlyLimit),
+		Commands: commandHandler,
 		Next:     handler,
 	}
+	return router, handler, nil
+}
 
-	errCh := make(chan error, 1)
-	go func() {
-		defer close(errCh)
-		if err := listener.Listen(ctx, router); err != nil && !errors.Is(err, context.Canceled) {
-			errCh <- err
+// chatRouters gives every chat on a channel its own conversation, kept in
+// the session sessionPath returns for the chat's scheduler channel key.
+// Chats share the channel's memory, tools, and costs.
+type chatRouters struct {
+	shared      *channelAgent
+	sessionPath func(chatKey string) string
+
+	mu      sync.Mutex
+	routers map[string]commands.Router
+	agents  map[string]*agent.Agent
+}
+
+func newChatRouters(shared *channelAgent, sessionPath func(chatKey string) string) *chatRouters {
+	return &chatRouters{
+		shared:      shared,
+		sessionPath: sessionPath,
+		routers:     make(map[string]commands.Router),
+		agents:      make(map[string]*agent.Agent),
+	}
+}
+
+// HandleMessage passes msg to the conversation of the chat it came from.
+func (r *chatRouters) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg *runtime.Message) error {
+	chatKey := r.shared.listener.CurrentChannelID()
+	if chatKey == "" {
+		return errors.New("message has no chat")
+	}
+	router, _, err := r.router(chatKey)
+	if err != nil {
+		return err
+	}
+	return router.HandleMessage(ctx, w, msg)
+}
+
+// AnswerInline answers an inline query with the agent of the asker's
+// private chat, in one tool-free call that leaves its conversation alone.
+func (r *chatRouters) AnswerInline(ctx context.Context, chatKey, query string) (string, error) {
+	_, handler, err := r.router(chatKey)
+	if err != nil {
+		return "", err
+	}
+	return handler.QuickAnswer(ctx, query)
+}
+
+func (r *chatRouters) router(chatKey string) (commands.Router, *agent.Agent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if router, ok := r.routers[chatKey]; ok {
+		return router, r.agents[chatKey], nil
+	}
+	router, handler, err := r.shared.router(chatKey, r.sessionPath(chatKey), chatKey)
+	if err != nil {
+		return commands.Router{}, nil, err
+	}
+	r.routers[chatKey] = router
+	r.agents[chatKey] = handler
+	return router, handler, nil
+}
+
+// Detach detaches every chat's agent from its session.
+func (r *chatRouters) Detach() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, handler := range r.agents {
+		handler.Detach()
+	}
+}
+
+// followPresence wraps the writer of every Telegram chat channel so
+// messages for that user go to the bot they were last active on. Other
+// channels, and users not seen yet, keep their own writer.
+func followPresence(channelWriters map[string]io.Writer, seen *presence.Store) {
+	direct := maps.Clone(channelWriters)
+	for channelID := range channelWriters {
+		// Bot names may contain dashes; chat IDs of users do not.
+		i := strings.LastIndex(channelID, "-")
+		if i < 0 || !config.IsTelegramChannel(channelID[:i]) {
+			continue
 		}
-	}()
-	return errCh, nil
+		channelWriters[channelID] = seen.Writer(channelID[i+1:], direct, channelID)
+	}
 }
 
 func registerTelegramChannelWriters(channelWriters map[string]io.Writer, allowedUsersPath string, listener *channels.TelegramListener) error {
@@ -223,8 +955,7 @@ func registerTelegramChannelWriters(channelWriters map[string]io.Writer, allowed
 			continue
 		}
 
-		channelID := fmt.Sprintf("telegram-%d", chatID)
-		channelWriters[channelID] = listener.ChannelWriter(chatID)
+		channelWriters[listener.ChannelKey(chatID)] = listener.ChannelWriter(chatID)
 	}
 	return nil
 }
@@ -3,31 +3,46 @@ package cli
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
+	"github.com/neoclaw-ai/neoclaw/internal/bootstrap"
 	"github.com/neoclaw-ai/neoclaw/internal/channels"
 	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/costs"
+	"github.com/neoclaw-ai/neoclaw/internal/language"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/notify"
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
+	"github.com/neoclaw-ai/neoclaw/internal/presence"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
 	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
 	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
 )
 
 func TestStartLoadsDefaultsAndBootstraps(t *testing.T) {
 	dataDir := createTestHome(t)
 	writeValidConfig(t, dataDir)
-	origStartTelegram := startTelegramFunc
+	origStartChannels := startChannelsFunc
 	defer func() {
-		startTelegramFunc = origStartTelegram
+		startChannelsFunc = origStartChannels
 	}()
-	startTelegramFunc = func(
+	startChannelsFunc = func(
 		context.Context,
 		*config.Config,
 		io.Writer,
 		map[string]io.Writer,
 		*scheduler.Service,
+		*notify.Gate,
 	) (<-chan error, error) {
 		return nil, nil
 	}
@@ -55,6 +70,24 @@ func TestStartLoadsDefaultsAndBootstraps(t *testing.T) {
 	}
 }
 
+func TestHealthcheckFailsWithoutServer(t *testing.T) {
+	dataDir := createTestHome(t)
+
+	cmd := NewRootCmd()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs([]string{"healthcheck"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Fatalf("expected not running error, got %v", err)
+	}
+	// healthcheck must not bootstrap a home of its own.
+	if _, err := os.Stat(dataDir); !os.IsNotExist(err) {
+		t.Fatalf("expected no home created, got %v", err)
+	}
+}
+
 func TestRegisterTelegramChannelWriters(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "allowed_users.json")
 	if err := store.WriteFile(path, []byte(`{
@@ -81,3 +114,212 @@ func TestRegisterTelegramChannelWriters(t *testing.T) {
 		t.Fatalf("expected one valid telegram writer entry, got %d", len(channelWriters))
 	}
 }
+
+func TestFollowPresenceWrapsOnlyTelegramChats(t *testing.T) {
+	seen := presence.New(filepath.Join(t.TempDir(), "presence.json"))
+	var cli, home, work bytes.Buffer
+	channelWriters := map[string]io.Writer{
+		"cli":                  &cli,
+		"telegram-111":         &home,
+		"telegram_my-work-111": &work,
+	}
+	followPresence(channelWriters, seen)
+	if channelWriters["cli"] != &cli {
+		t.Fatalf("expected the cli writer to be left alone")
+	}
+
+	if err := seen.Record("111", "telegram_my-work-111", time.Now()); err != nil {
+		t.Fatalf("record presence: %v", err)
+	}
+	if _, err := channelWriters["telegram-111"].Write([]byte("briefing")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if home.Len() != 0 || work.String() != "briefing" {
+		t.Fatalf("expected the briefing on the work bot, got home %q, work %q", home.String(), work.String())
+	}
+}
+
+// chatListener is a channelListener whose current chat the test sets.
+type chatListener struct {
+	chatKey string
+}
+
+func (l *chatListener) RequestApproval(context.Context, approval.ApprovalRequest) (approval.ApprovalDecision, error) {
+	return approval.Denied, nil
+}
+
+func (l *chatListener) Send(context.Context, string) error { return nil }
+
+func (l *chatListener) CurrentChannelID() string { return l.chatKey }
+
+type replyRecorder struct {
+	replies []string
+}
+
+func (w *replyRecorder) WriteMessage(_ context.Context, text string) error {
+	w.replies = append(w.replies, text)
+	return nil
+}
+
+func TestChatRoutersKeepAConversationPerChat(t *testing.T) {
+	dataDir := createTestHome(t)
+	writeValidConfig(t, dataDir)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := bootstrap.Initialize(cfg); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	memoryStore, err := memory.New(cfg.MemoryDir())
+	if err != nil {
+		t.Fatalf("open memory: %v", err)
+	}
+	listener := &chatListener{}
+	shared := &channelAgent{
+		cfg:           cfg,
+		name:          "telegram",
+		listener:      listener,
+		modelProvider: provider.Mock{},
+		memoryStore:   memoryStore,
+		registry:      tools.NewRegistry(),
+		costTracker:   costs.New(cfg.CostsPath()),
+		toolOutputs:   outputs.New(cfg.ToolOutputsDir()),
+		languages:     language.New(cfg.LanguagesPath()),
+	}
+	routers := newChatRouters(shared, cfg.TelegramChatContextPath)
+	defer routers.Detach()
+
+	for _, turn := range []struct{ chat, text string }{
+		{"telegram-111", "alice here"},
+		{"telegram-222", "bob here"},
+		{"telegram-111", "alice again"},
+	} {
+		listener.chatKey = turn.chat
+		w := &replyRecorder{}
+		if err := routers.HandleMessage(context.Background(), w, &runtime.Message{Text: turn.text}); err != nil {
+			t.Fatalf("handle %q: %v", turn.text, err)
+		}
+		if len(w.replies) != 1 || w.replies[0] != "echo: "+turn.text {
+			t.Fatalf("unexpected replies to %q: %#v", turn.text, w.replies)
+		}
+	}
+
+	alice, err := os.ReadFile(cfg.TelegramChatContextPath("telegram-111"))
+	if err != nil {
+		t.Fatalf("read alice's session: %v", err)
+	}
+	bob, err := os.ReadFile(cfg.TelegramChatContextPath("telegram-222"))
+	if err != nil {
+		t.Fatalf("read bob's session: %v", err)
+	}
+	if !strings.Contains(string(alice), "alice again") || strings.Contains(string(alice), "bob here") {
+		t.Fatalf("unexpected session for alice: %s", alice)
+	}
+	if !strings.Contains(string(bob), "bob here") || strings.Contains(string(bob), "alice") {
+		t.Fatalf("unexpected session for bob: %s", bob)
+	}
+
+	listener.chatKey = ""
+	if err := routers.HandleMessage(context.Background(), &replyRecorder{}, &runtime.Message{Text: "lost"}); err == nil {
+		t.Fatal("expected a message without a chat to fail")
+	}
+
+	// Inline answers come from the asker's agent but stay out of its session.
+	answer, err := routers.AnswerInline(context.Background(), "telegram-111", "quick one")
+	if err != nil {
+		t.Fatalf("answer inline: %v", err)
+	}
+	if answer != "echo: quick one" {
+		t.Fatalf("unexpected inline answer %q", answer)
+	}
+	alice, err = os.ReadFile(cfg.TelegramChatContextPath("telegram-111"))
+	if err != nil {
+		t.Fatalf("reread alice's session: %v", err)
+	}
+	if strings.Contains(string(alice), "quick one") {
+		t.Fatalf("expected the inline query kept out of the session: %s", alice)
+	}
+}
+
+func TestChatRoutersKeepChatsApart(t *testing.T) {
+	dataDir := createTestHome(t)
+	writeValidConfig(t, dataDir)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := bootstrap.Initialize(cfg); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	memoryStore, err := memory.New(cfg.MemoryDir())
+	if err != nil {
+		t.Fatalf("open memory: %v", err)
+	}
+	listener := &chatListener{}
+	shared := &channelAgent{
+		cfg:           cfg,
+		name:          "telegram",
+		listener:      listener,
+		modelProvider: provider.Mock{},
+		memoryStore:   memoryStore,
+		registry:      tools.NewRegistry(),
+		costTracker:   costs.New(cfg.CostsPath()),
+		toolOutputs:   outputs.New(cfg.ToolOutputsDir()),
+		languages:     language.New(cfg.LanguagesPath()),
+	}
+	routers := newChatRouters(shared, cfg.TelegramChatContextPath)
+	defer routers.Detach()
+	send := func(chat, text string) (string, error) {
+		listener.chatKey = chat
+		w := &replyRecorder{}
+		err := routers.HandleMessage(context.Background(), w, &runtime.Message{Text: text})
+		return strings.Join(w.replies, "\n"), err
+	}
+
+	// Alice leaves a fork, a full tool output, and an artifact behind.
+	for _, text := range []string{"alice here", "/fork plans"} {
+		if _, err := send("telegram-111", text); err != nil {
+			t.Fatalf("alice %q: %v", text, err)
+		}
+	}
+	if _, err := outputs.New(cfg.ChatToolOutputsDir("telegram-111")).Save("http_request", "alice's secret page", time.Now()); err != nil {
+		t.Fatalf("save output: %v", err)
+	}
+	report := filepath.Join(cfg.WorkspaceDir(), "report.md")
+	if err := os.WriteFile(report, []byte("# Alice\n"), 0o644); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	artifact, err := artifacts.New(cfg.ArtifactsPath()).ForChannel("telegram-111").Add(report, "Alice's report", time.Now())
+	if err != nil {
+		t.Fatalf("add artifact: %v", err)
+	}
+
+	if _, err := send("telegram-222", "bob here"); err != nil {
+		t.Fatalf("bob: %v", err)
+	}
+	for _, check := range []struct{ text, want string }{
+		{"/session list", "default"},
+		{"/outputs", "No full tool outputs saved."},
+		{"/artifacts", "No artifacts yet."},
+		{fmt.Sprintf("/artifacts get %d", artifact.ID), fmt.Sprintf("No artifact %d.", artifact.ID)},
+	} {
+		reply, err := send("telegram-222", check.text)
+		if err != nil {
+			t.Fatalf("bob %q: %v", check.text, err)
+		}
+		if !strings.Contains(reply, check.want) || strings.Contains(reply, "plans") || strings.Contains(reply, "telegram-111") {
+			t.Fatalf("expected bob's %q to show only his own chat, got %q", check.text, reply)
+		}
+	}
+	reply, err := send("telegram-111", "/session list")
+	if err != nil || !strings.Contains(reply, "plans") {
+		t.Fatalf("expected alice to see her fork, got %q (%v)", reply, err)
+	}
+
+	// Chats cannot take over the operator's CLI conversation.
+	reply, err = send("telegram-222", "/attach cli")
+	if err != nil || !strings.Contains(reply, `Could not attach: unknown channel "cli"`) {
+		t.Fatalf("expected /attach cli to be refused, got %q (%v)", reply, err)
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show LLM provider health and available updates",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			profiles, err := provider.NewHealth(cfg.ProviderHealthPath()).Snapshot()
+			if err != nil {
+				return err
+			}
+			names := make([]string, 0, len(cfg.LLM))
+			for name := range cfg.LLM {
+				names = append(names, name)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), provider.FormatHealth(names, profiles, time.Now()))
+			if fallback := cfg.DefaultLLM().Fallback; fallback != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Fallback for default: %s\n", fallback)
+			}
+			if cfg.Update.Check {
+				if _, notice := updateNotice(cmd.Context(), cfg); notice != "" {
+					fmt.Fprintln(cmd.OutOrStdout(), notice)
+				}
+			}
+			return nil
+		},
+	}
+}
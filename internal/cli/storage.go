@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// storageFlushTimeout bounds the final upload when a command exits.
+const storageFlushTimeout = 30 * time.Second
+
+// storageMirror is the active off-machine mirror, or nil when state is local.
+var storageMirror *store.Mirror
+
+// newStorageMirror builds the mirror for [storage], or returns nil when the
+// backend is local. The workspace, secrets, and PID file are never mirrored.
+func newStorageMirror(cfg *config.Config) (*store.Mirror, error) {
+	backend := strings.ToLower(strings.TrimSpace(cfg.Storage.Backend))
+	if backend == "" || backend == config.StorageBackendLocal {
+		return nil, nil
+	}
+	remote, err := newStorageRemote(cfg, backend)
+	if err != nil {
+		return nil, err
+	}
+	skip, err := storageSkip(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &store.Mirror{
+		Remote: remote,
+		Root:   cfg.DataDir(),
+		Skip:   skip,
+	}, nil
+}
+
+// newStorageRemote builds the remote for a non-local backend from the
+// [storage] settings.
+func newStorageRemote(cfg *config.Config, backend string) (store.Remote, error) {
+	client := &http.Client{Timeout: time.Minute}
+	switch backend {
+	case config.StorageBackendS3:
+		s3 := cfg.Storage.S3
+		if strings.TrimSpace(s3.Bucket) == "" {
+			return nil, errors.New("storage.s3.bucket is not configured")
+		}
+		return &store.S3{
+			Endpoint:  s3.S3Endpoint(),
+			Region:    s3.Region,
+			Bucket:    s3.Bucket,
+			Prefix:    s3.Prefix,
+			AccessKey: s3.AccessKeyID,
+			SecretKey: s3.SecretAccessKey,
+			Client:    client,
+		}, nil
+	case config.StorageBackendWebDAV:
+		if strings.TrimSpace(cfg.Storage.WebDAV.URL) == "" {
+			return nil, errors.New("storage.webdav.url is not configured")
+		}
+		return &store.WebDAV{
+			URL:      cfg.Storage.WebDAV.URL,
+			Username: cfg.Storage.WebDAV.Username,
+			Password: cfg.Storage.WebDAV.Password,
+			Client:   client,
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid storage.backend %q", backend)
+	}
+}
+
+// storageSkip lists the data directory entries that stay on this machine.
+func storageSkip(cfg *config.Config) ([]string, error) {
+	workspace, err := filepath.Rel(cfg.DataDir(), cfg.WorkspaceDir())
+	if err != nil {
+		return nil, fmt.Errorf("resolve workspace directory: %w", err)
+	}
+	// Trash contents are moved with rename, not written through store, so
+	// the index would describe files the replica never received.
+	trashDir, err := filepath.Rel(cfg.DataDir(), cfg.TrashDir())
+	if err != nil {
+		return nil, fmt.Errorf("resolve trash directory: %w", err)
+	}
+	// OAuth tokens and other secrets stay on this machine.
+	return []string{workspace, trashDir, config.SecretsDirPath, config.PIDFilePath}, nil
+}
+
+// configureStorage installs the configured storage backend for this process.
+func configureStorage(cfg *config.Config) error {
+	mirror, err := newStorageMirror(cfg)
+	if err != nil {
+		return err
+	}
+	storageMirror = mirror
+	if mirror == nil {
+		store.SetBackend(nil)
+		return nil
+	}
+	store.SetBackend(mirror)
+	return nil
+}
+
+// flushStorage uploads changes still waiting for the storage backend.
+func flushStorage(ctx context.Context) {
+	if storageMirror == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), storageFlushTimeout)
+	defer cancel()
+	if err := storageMirror.Flush(ctx); err != nil {
+		logging.Logger().Warn("storage backend upload failed", "err", err)
+	}
+}
+
+func newStorageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Manage the off-machine storage backend",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "push",
+		Short: "Upload all agent state to the storage backend",
+		Long:  "Upload every file under the data directory to the configured [storage] backend. Run once after enabling a backend so state written earlier is copied too; later changes upload automatically.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if storageMirror == nil {
+				return fmt.Errorf("no storage backend configured; set [storage] backend in %s", cfg.ConfigPath())
+			}
+			count, err := storageMirror.QueueAll()
+			if err != nil {
+				return fmt.Errorf("list agent state: %w", err)
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Minute)
+			defer cancel()
+			if err := storageMirror.Flush(ctx); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Uploaded %d files to the %s backend.\n", count, strings.ToLower(strings.TrimSpace(cfg.Storage.Backend)))
+			return nil
+		},
+	})
+	return cmd
+}
+
+func newRestoreCmd() *cobra.Command {
+	var from string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "restore --from <replica>",
+		Short: "Restore agent state from a storage replica",
+		Long: "Replace local agent state with the copy in a replica. <replica> is s3 or\n" +
+			"webdav, using the [storage] settings in config.toml, or the path of a\n" +
+			"directory holding a copy of the data directory.\n\n" +
+			"Files in the replica overwrite local files; local files missing from the\n" +
+			"replica are kept. The workspace, secrets, and PID file are never restored.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+
+			pidFilePath := cfg.PIDPath()
+			if _, err := os.Stat(pidFilePath); err == nil {
+				return errors.New("server is already running. Stop it first, then run claw restore")
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("stat pid file %s: %w", pidFilePath, err)
+			}
+
+			replica := strings.TrimSpace(from)
+			var remote store.Remote
+			switch strings.ToLower(replica) {
+			case "":
+				return errors.New("--from is required")
+			case config.StorageBackendS3, config.StorageBackendWebDAV:
+				remote, err = newStorageRemote(cfg, strings.ToLower(replica))
+				if err != nil {
+					return err
+				}
+			default:
+				info, err := os.Stat(replica)
+				if err != nil {
+					return fmt.Errorf("replica %s: %w", replica, err)
+				}
+				if !info.IsDir() {
+					return fmt.Errorf("replica %s is not a directory", replica)
+				}
+				remote = store.Dir{Path: replica}
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Minute)
+			defer cancel()
+			listed, err := remote.List(ctx)
+			if err != nil {
+				return fmt.Errorf("list replica: %w", err)
+			}
+			skip, err := storageSkip(cfg)
+			if err != nil {
+				return err
+			}
+			filter := &store.Mirror{Root: cfg.DataDir(), Skip: skip}
+			var keys []string
+			for _, key := range listed {
+				if _, ok := filter.Key(filepath.Join(cfg.DataDir(), filepath.FromSlash(key))); ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			if len(keys) == 0 {
+				return fmt.Errorf("replica %s has no agent state", replica)
+			}
+
+			out := cmd.OutOrStdout()
+			if dryRun {
+				for _, key := range keys {
+					fmt.Fprintln(out, key)
+				}
+				fmt.Fprintf(out, "Would restore %d files into %s.\n", len(keys), cfg.DataDir())
+				return nil
+			}
+			if err := store.Restore(ctx, remote, cfg.DataDir(), keys); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Restored %d files into %s.\n", len(keys), cfg.DataDir())
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "replica to restore from: s3, webdav, or a directory path")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "list the files that would be restored without writing them")
+	return cmd
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+func TestNewStorageMirror(t *testing.T) {
+	cfg := &config.Config{HomeDir: t.TempDir(), Agent: "default"}
+	mirror, err := newStorageMirror(cfg)
+	if err != nil || mirror != nil {
+		t.Fatalf("expected no mirror for local storage, got %#v %v", mirror, err)
+	}
+
+	cfg.Storage = config.StorageConfig{
+		Backend: config.StorageBackendWebDAV,
+		WebDAV:  config.WebDAVStorageConfig{URL: "https://dav.example.com/neoclaw/"},
+	}
+	mirror, err = newStorageMirror(cfg)
+	if err != nil {
+		t.Fatalf("new storage mirror: %v", err)
+	}
+	if _, ok := mirror.Remote.(*store.WebDAV); !ok {
+		t.Fatalf("expected webdav remote, got %T", mirror.Remote)
+	}
+	if key, ok := mirror.Key(cfg.MemoryPath()); !ok || key != "agents/default/memory/memory.tsv" {
+		t.Fatalf("expected memory mirrored, got %q %v", key, ok)
+	}
+	for _, path := range []string{filepath.Join(cfg.WorkspaceDir(), "notes.md"), filepath.Join(cfg.SecretsDir(), "oauth-default.json"), cfg.PIDPath(), cfg.ConfigPath()} {
+		if _, ok := mirror.Key(path); ok {
+			t.Fatalf("expected %s to stay local", path)
+		}
+	}
+}
+
+func TestRestoreFromDirectoryReplica(t *testing.T) {
+	dataDir := createTestHome(t)
+	writeValidConfig(t, dataDir)
+	cfg := &config.Config{HomeDir: dataDir, Agent: "default"}
+
+	replica := t.TempDir()
+	memoryKey, err := filepath.Rel(cfg.DataDir(), cfg.MemoryPath())
+	if err != nil {
+		t.Fatalf("memory key: %v", err)
+	}
+	workspaceKey, err := filepath.Rel(cfg.DataDir(), filepath.Join(cfg.WorkspaceDir(), "notes.md"))
+	if err != nil {
+		t.Fatalf("workspace key: %v", err)
+	}
+	for key, body := range map[string]string{memoryKey: "restored fact\n", workspaceKey: "notes\n"} {
+		if err := store.WriteFile(filepath.Join(replica, key), []byte(body)); err != nil {
+			t.Fatalf("write replica: %v", err)
+		}
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := NewRootCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("restore", "--from", replica, "--dry-run")
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !strings.Contains(out, "Would restore 1 files") {
+		t.Fatalf("unexpected dry run output %q", out)
+	}
+	if got, _ := store.ReadFile(cfg.MemoryPath()); strings.Contains(got, "restored fact") {
+		t.Fatal("dry run must not write files")
+	}
+
+	if _, err := run("restore", "--from", replica); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got, err := store.ReadFile(cfg.MemoryPath()); err != nil || got != "restored fact\n" {
+		t.Fatalf("expected memory restored, got %q %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.WorkspaceDir(), "notes.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected workspace left alone, got %v", err)
+	}
+
+	if err := store.WriteFile(cfg.PIDPath(), []byte("12345\n")); err != nil {
+		t.Fatalf("write pid file: %v", err)
+	}
+	if _, err := run("restore", "--from", replica); err == nil || !strings.Contains(err.Error(), "running") {
+		t.Fatalf("expected running-server error, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"github.com/neoclaw-ai/neoclaw/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+func newTelemetryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage anonymous usage reports (off unless you turn them on)",
+		Long:  "Opt-in usage reports count which features are used and which kinds of errors happen. They never contain messages, memory, tool arguments, or file contents.",
+	}
+	cmd.AddCommand(newTelemetryStatusCmd())
+	cmd.AddCommand(newTelemetrySetCmd("on", true))
+	cmd.AddCommand(newTelemetrySetCmd("off", false))
+	cmd.AddCommand(newTelemetryPayloadCmd())
+	return cmd
+}
+
+func newTelemetryStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether telemetry is on and what has been counted",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			state, err := telemetry.LoadState(cfg.TelemetryPath())
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if !state.Enabled {
+				fmt.Fprintln(out, "Telemetry is off. Nothing is collected or sent. Run claw telemetry on to help prioritize development.")
+				return nil
+			}
+			fmt.Fprintf(out, "Telemetry is on (install id %s).\n", state.InstallID)
+			if endpoint := strings.TrimSpace(cfg.Telemetry.Endpoint); endpoint != "" {
+				fmt.Fprintf(out, "Reports go to %s once a day.\n", endpoint)
+			} else {
+				fmt.Fprintln(out, "No telemetry.endpoint is set, so counts stay on this machine.")
+			}
+			counts, err := telemetry.LoadCounts(cfg.TelemetryCountsPath())
+			if err != nil {
+				return err
+			}
+			if counts.Since.IsZero() {
+				fmt.Fprintln(out, "Nothing counted yet.")
+				return nil
+			}
+			fmt.Fprintf(out, "Counted since %s:\n", counts.Since.Local().Format("2006-01-02 15:04"))
+			writeTelemetryCounts(out, "Features", counts.Features)
+			writeTelemetryCounts(out, "Errors", counts.Errors)
+			fmt.Fprintln(out, "Run claw telemetry payload to see the exact report.")
+			return nil
+		},
+	}
+}
+
+func newTelemetrySetCmd(use string, enabled bool) *cobra.Command {
+	short := "Turn anonymous usage reports on"
+	if !enabled {
+		short = "Turn usage reports off and delete unsent counts"
+	}
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if _, err := telemetry.SetEnabled(cfg.TelemetryPath(), enabled, time.Now()); err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if enabled {
+				fmt.Fprintln(out, "Telemetry is on. Restart the server to start counting. See what is collected with claw telemetry payload.")
+				return nil
+			}
+			// A running server drops its own unsaved counts at its next save.
+			if err := store.RemoveFile(cfg.TelemetryCountsPath()); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "Telemetry is off. Unsent counts were deleted.")
+			return nil
+		},
+	}
+}
+
+func newTelemetryPayloadCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "payload",
+		Short: "Print the next report exactly as it would be sent",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			state, err := telemetry.LoadState(cfg.TelemetryPath())
+			if err != nil {
+				return err
+			}
+			counts, err := telemetry.LoadCounts(cfg.TelemetryCountsPath())
+			if err != nil {
+				return err
+			}
+			raw, err := json.MarshalIndent(telemetry.NewReport(state, counts, Version, time.Now()), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(raw))
+			return nil
+		},
+	}
+}
+
+func writeTelemetryCounts(out io.Writer, title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "  %s:\n", title)
+	for _, name := range telemetry.Names(counts) {
+		fmt.Fprintf(out, "    %s\t%d\n", name, counts[name])
+	}
+}
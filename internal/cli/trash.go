@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+	"github.com/neoclaw-ai/neoclaw/internal/trash"
+	"github.com/spf13/cobra"
+)
+
+func newTrashCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List and restore files the agent deleted",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List deleted files, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			items, err := trash.New(cfg.TrashDir()).List()
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "Trash is empty.")
+				return nil
+			}
+			for _, item := range items {
+				fmt.Fprintln(cmd.OutOrStdout(), tools.FormatTrashItem(item))
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "restore <id>",
+		Short: "Move a deleted file back to where it was",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(args[0]), "#"))
+			if err != nil || id < 1 {
+				return fmt.Errorf("invalid trash id %q", args[0])
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			item, err := trash.New(cfg.TrashDir()).Restore(id)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Restored %s\n", item.Path)
+			return nil
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/update"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// updateDownloadTimeout allows for a release archive on a slow link.
+	updateDownloadTimeout = 5 * time.Minute
+	// updateCheckTimeout keeps claw status and check-ins from waiting long
+	// on the release feed.
+	updateCheckTimeout = 5 * time.Second
+)
+
+// updateFeedURL is a variable so tests can point it at a fake feed.
+var updateFeedURL = update.DefaultFeedURL
+
+func newUpdateCmd() *cobra.Command {
+	var checkOnly, force bool
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Install the latest release and restart the server",
+		Long:  "Downloads the latest release for this platform, verifies it against the release checksums, replaces the claw binary, and restarts a running server.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			client := &http.Client{Timeout: updateDownloadTimeout}
+			release, err := update.Latest(cmd.Context(), client, updateFeedURL)
+			if err != nil {
+				return fmt.Errorf("check for updates: %w", err)
+			}
+			if err := update.Record(cfg.UpdateCheckPath(), release.Version, time.Now()); err != nil {
+				logging.Logger().Warn("failed to save update check", "err", err)
+			}
+
+			switch {
+			case checkOnly:
+				if notice := update.Notice(Version, release.Version); notice != "" {
+					fmt.Fprintln(out, notice)
+				} else {
+					fmt.Fprintf(out, "NeoClaw %s; the latest release is %s.\n", Version, release.Version)
+				}
+				return nil
+			case force:
+			case !update.IsRelease(Version):
+				fmt.Fprintf(out, "This is a development build (%s). Run claw update --force to replace it with release %s.\n", Version, release.Version)
+				return nil
+			case !update.Newer(Version, release.Version):
+				fmt.Fprintf(out, "NeoClaw %s is up to date.\n", Version)
+				return nil
+			}
+
+			binary, err := update.Download(cmd.Context(), client, release, update.AssetName(runtime.GOOS, runtime.GOARCH))
+			if err != nil {
+				return err
+			}
+			exe, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("find claw binary: %w", err)
+			}
+			if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+				exe = resolved
+			}
+			if err := update.Replace(exe, binary); err != nil {
+				return fmt.Errorf("replace %s: %w", exe, err)
+			}
+			fmt.Fprintf(out, "Updated NeoClaw %s to %s (%s).\n", Version, release.Version, exe)
+
+			restarted, err := update.Restart(cfg.PIDPath())
+			if err != nil {
+				return err
+			}
+			if restarted {
+				fmt.Fprintln(out, "Restarting the running server.")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&checkOnly, "check", false, "Only report whether an update is available")
+	cmd.Flags().BoolVar(&force, "force", false, "Install the latest release even if it is not newer, e.g. over a development build")
+	return cmd
+}
+
+// updateNotice returns the latest release and a notice about it, or an empty
+// notice when this build is current, is a development build, or the feed
+// cannot be reached.
+func updateNotice(ctx context.Context, cfg *config.Config) (version, notice string) {
+	if !update.IsRelease(Version) {
+		return "", ""
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+	latest, err := update.LatestCached(ctx, http.DefaultClient, updateFeedURL, cfg.UpdateCheckPath(), time.Now())
+	if err != nil {
+		logging.Logger().Debug("update check failed", "err", err)
+		return "", ""
+	}
+	return latest, update.Notice(Version, latest)
+}
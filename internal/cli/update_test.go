@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUpdateCheckReportsNewRelease(t *testing.T) {
+	dataDir := createTestHome(t)
+	writeValidConfig(t, dataDir)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name":"v1.5.0","assets":[]}`)
+	}))
+	defer server.Close()
+	origFeed, origVersion := updateFeedURL, Version
+	t.Cleanup(func() {
+		updateFeedURL, Version = origFeed, origVersion
+	})
+	updateFeedURL = server.URL
+
+	for _, tc := range []struct {
+		version string
+		args    []string
+		want    string
+	}{
+		{"1.4.2", []string{"update", "--check"}, "NeoClaw 1.5.0 is available (you have 1.4.2)"},
+		{"1.5.0", []string{"update"}, "NeoClaw 1.5.0 is up to date."},
+		{"dev", []string{"update"}, "Run claw update --force"},
+	} {
+		Version = tc.version
+		cmd := NewRootCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+		cmd.SetArgs(tc.args)
+		cmd.SetContext(context.Background())
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("execute %v: %v", tc.args, err)
+		}
+		if !strings.Contains(out.String(), tc.want) {
+			t.Fatalf("%s %v: expected %q in output, got %q", tc.version, tc.args, tc.want, out.String())
+		}
+	}
+}
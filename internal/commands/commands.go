@@ -5,18 +5,61 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/neoclaw-ai/neoclaw/internal/agent"
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
 	"github.com/neoclaw-ai/neoclaw/internal/costs"
+	"github.com/neoclaw-ai/neoclaw/internal/language"
+	"github.com/neoclaw-ai/neoclaw/internal/lists"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
+	"github.com/neoclaw-ai/neoclaw/internal/prompts"
 	"github.com/neoclaw-ai/neoclaw/internal/runtime"
 	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/telemetry"
+	"github.com/neoclaw-ai/neoclaw/internal/todo"
+	"github.com/neoclaw-ai/neoclaw/internal/workflow"
 )
 
 const helpText = `Available commands:
 /help - Show available commands
 /new, /reset - Clear the current session
 /jobs - List scheduled jobs
+/session list - List saved sessions
+/profile [apply|discard] - Review a proposed USER.md update
+/dnd [on|off|<duration>] - Hold scheduled and proactive messages
+/incognito [on|off] - Stop saving the conversation until turned off
+/correct [<topic>:] <text> - Correct the previous answer and remember it
+/rewind [N] - Remove the last N exchanges (default 1) from this session
+/delete-last - Permanently delete your last message and the reply to it
+/fork [name] - Continue in a copy of this session
+/merge-summary - Return from a fork with a summary of it
+/attach <channel> - Continue another channel's conversation here
+/where - Show which channels share this conversation
+/memory pending - Review memory writes waiting for approval
+/memory approve|reject <n...|all> - Save or drop pending memory writes
+/context [toggle <block>] - Show or toggle profile, facts, and daily_logs in this session
+/context coding on|off - Add a workspace snapshot to every turn of this session
+/project [<name>|off] - List projects or switch this session to one
+/prompt [<name> [args]] - List or send a saved prompt template
+/run [<workflow> [resume]] - List or run a workflow
+/todo [all|add <text> [due YYYY-MM-DD]|done <n>] - Show or edit your todo list
+/list [<name>] [show|add <items>|check <n>|remove <n>|clear] - Show or edit a shared list
+/language [<code>|auto|off] - Show or choose the language replies to you are in
+/artifacts - List files the agent produced for you
+/artifact_<id> - Download one artifact
+/outputs [n|show <n> [page]] - List or read full tool outputs that were cut short
+/approvals - List approval prompts waiting for your answer
+/last-turn - Show the tools, time, tokens, and cost of the last reply
 /usage - Show cost usage`
 
 // Resetter resets the active conversation/session state.
@@ -24,6 +67,76 @@ type Resetter interface {
 	Reset(ctx context.Context) error
 }
 
+// DoNotDisturb controls holding of unprompted messages.
+type DoNotDisturb interface {
+	SetDND(d time.Duration)
+	ClearDND()
+	Status() string
+}
+
+// Incognito toggles turns that are not saved to session history or memory.
+type Incognito interface {
+	SetIncognito(on bool)
+	Incognito() bool
+}
+
+// Corrector saves a correction of the previous answer to memory.
+type Corrector interface {
+	Correct(ctx context.Context, text string) (topic string, err error)
+}
+
+// Forker branches the conversation into a copy of the session and back.
+type Forker interface {
+	Fork(ctx context.Context, name string) (string, error)
+	MergeSummary(ctx context.Context) (name, summary string, err error)
+}
+
+// Bridger moves the conversation between the sessions of different
+// channels, so one conversation can continue on another channel.
+type Bridger interface {
+	Attach(ctx context.Context, channel string) error
+	BridgeChannels() []string
+	Where() (owner string, attached []session.Attachment, err error)
+}
+
+// rewindPreviewRunes caps how much of the last kept message /rewind echoes.
+const rewindPreviewRunes = 200
+
+// Rewinder rolls the conversation back by whole exchanges.
+type Rewinder interface {
+	Rewind(ctx context.Context, n int) (removed int, lastKept string, err error)
+}
+
+// Deleter permanently removes messages from the saved conversation.
+type Deleter interface {
+	DeleteLast(ctx context.Context) (removed int, err error)
+}
+
+// LastTurner reports how the latest turn of the current session ran.
+type LastTurner interface {
+	LastTurn() (*session.TurnReport, error)
+}
+
+// maxLastTurnTools caps the tool calls /last-turn lists.
+const maxLastTurnTools = 20
+
+// PromptBlocks turns system-prompt blocks on and off for the current session,
+// and flags it as a coding session with a workspace snapshot.
+type PromptBlocks interface {
+	DisabledPromptBlocks() ([]string, error)
+	TogglePromptBlock(block string) (enabled bool, err error)
+	LastPromptUsage() (usage agent.PromptUsage, ok bool)
+	CodingSession() (bool, error)
+	SetCodingSession(coding bool) error
+}
+
+// Projects switches the current session between project profiles.
+type Projects interface {
+	Projects() []string
+	ActiveProject() (string, error)
+	SetProject(name string) error
+}
+
 // Handler dispatches supported slash commands.
 type Handler struct {
 	resetter Resetter
@@ -31,6 +144,26 @@ type Handler struct {
 	costs    *costs.Tracker
 	daily    float64
 	monthly  float64
+	sessions string
+	agentDir string
+	dnd      DoNotDisturb
+	prompts  string
+	flows    *workflow.Runner
+	outputs  *artifacts.Store
+	results  *outputs.Store
+	private  Incognito
+	corrects Corrector
+	forks    Forker
+	bridge   Bridger
+	rewinds  Rewinder
+	deletes  Deleter
+	turns    LastTurner
+	blocks   PromptBlocks
+	projects Projects
+	pending  *memory.Store
+	tasks    *todo.Store
+	lists    *lists.Store
+	langs    *language.Store
 }
 
 // New creates a new slash command handler.
@@ -44,13 +177,163 @@ func New(resetter Resetter, jobs *scheduler.Service, costTracker *costs.Tracker,
 	}
 }
 
+// ConfigureSessions enables /session list for sessions stored under dir.
+func (h *Handler) ConfigureSessions(dir string) {
+	h.sessions = dir
+}
+
+// ConfigureProfile enables /profile for the agent directory holding USER.md.
+func (h *Handler) ConfigureProfile(agentDir string) {
+	h.agentDir = agentDir
+}
+
+// ConfigureDND enables /dnd backed by the notification gate.
+func (h *Handler) ConfigureDND(dnd DoNotDisturb) {
+	h.dnd = dnd
+}
+
+// ConfigurePrompts enables /prompt for templates stored under dir.
+func (h *Handler) ConfigurePrompts(dir string) {
+	h.prompts = dir
+}
+
+// ConfigureWorkflows enables /run for workflows executed by runner.
+func (h *Handler) ConfigureWorkflows(runner *workflow.Runner) {
+	h.flows = runner
+}
+
+// ConfigureArtifacts enables /artifacts and /artifact_<id>.
+func (h *Handler) ConfigureArtifacts(store *artifacts.Store) {
+	h.outputs = store
+}
+
+// ConfigureToolOutputs enables /outputs for the full tool outputs in store.
+func (h *Handler) ConfigureToolOutputs(store *outputs.Store) {
+	h.results = store
+}
+
+// ConfigureIncognito enables /incognito for the conversation handler.
+func (h *Handler) ConfigureIncognito(incognito Incognito) {
+	h.private = incognito
+}
+
+// ConfigureCorrections enables /correct for the conversation handler.
+func (h *Handler) ConfigureCorrections(corrector Corrector) {
+	h.corrects = corrector
+}
+
+// ConfigureForks enables /fork and /merge-summary for the conversation handler.
+func (h *Handler) ConfigureForks(forker Forker) {
+	h.forks = forker
+}
+
+// ConfigureBridge enables /attach and /where for the conversation handler.
+func (h *Handler) ConfigureBridge(bridge Bridger) {
+	h.bridge = bridge
+}
+
+// ConfigureRewind enables /rewind for the conversation handler.
+func (h *Handler) ConfigureRewind(rewinder Rewinder) {
+	h.rewinds = rewinder
+}
+
+// ConfigureDeletions enables /delete-last for the conversation handler.
+func (h *Handler) ConfigureDeletions(deleter Deleter) {
+	h.deletes = deleter
+}
+
+// ConfigureLastTurn enables /last-turn for the conversation handler.
+func (h *Handler) ConfigureLastTurn(turns LastTurner) {
+	h.turns = turns
+}
+
+// ConfigurePromptBlocks enables /context for the conversation handler.
+func (h *Handler) ConfigurePromptBlocks(blocks PromptBlocks) {
+	h.blocks = blocks
+}
+
+// ConfigureProjects enables /project for the conversation handler.
+func (h *Handler) ConfigureProjects(projects Projects) {
+	h.projects = projects
+}
+
+// ConfigureMemoryReview enables /memory for writes queued in store.
+func (h *Handler) ConfigureMemoryReview(store *memory.Store) {
+	h.pending = store
+}
+
+// ConfigureTasks enables /todo for the task list in store.
+func (h *Handler) ConfigureTasks(store *todo.Store) {
+	h.tasks = store
+}
+
+// ConfigureLists enables /list for the shared lists in store.
+func (h *Handler) ConfigureLists(store *lists.Store) {
+	h.lists = store
+}
+
+// ConfigureLanguages enables /language for the preferences in store.
+func (h *Handler) ConfigureLanguages(store *language.Store) {
+	h.langs = store
+}
+
 // Handle executes one command and reports whether it was handled.
 func (h *Handler) Handle(ctx context.Context, cmd string, w runtime.ResponseWriter) (handled bool, err error) {
 	if w == nil {
 		return false, errors.New("response writer is required")
 	}
 
-	switch normalize(cmd) {
+	normalized := normalize(cmd)
+	if normalized == "/dnd" || strings.HasPrefix(normalized, "/dnd ") {
+		return true, h.handleDND(ctx, strings.TrimSpace(strings.TrimPrefix(normalized, "/dnd")), w)
+	}
+	if id, ok := strings.CutPrefix(normalized, "/artifact_"); ok {
+		return true, h.handleArtifactGet(ctx, id, w)
+	}
+	if id, ok := strings.CutPrefix(normalized, "/artifacts get "); ok {
+		return true, h.handleArtifactGet(ctx, strings.TrimSpace(id), w)
+	}
+	if normalized == "/correct" || strings.HasPrefix(normalized, "/correct ") {
+		// Keep the original casing: the correction text is saved verbatim.
+		text := strings.TrimSpace(cmd)
+		return true, h.handleCorrect(ctx, strings.TrimSpace(text[len("/correct"):]), w)
+	}
+	if normalized == "/fork" || strings.HasPrefix(normalized, "/fork ") {
+		return true, h.handleFork(ctx, strings.Fields(strings.TrimPrefix(normalized, "/fork")), w)
+	}
+	if normalized == "/attach" || strings.HasPrefix(normalized, "/attach ") {
+		return true, h.handleAttach(ctx, strings.Fields(strings.TrimPrefix(normalized, "/attach")), w)
+	}
+	if normalized == "/rewind" || strings.HasPrefix(normalized, "/rewind ") {
+		return true, h.handleRewind(ctx, strings.Fields(strings.TrimPrefix(normalized, "/rewind")), w)
+	}
+	if normalized == "/context" || strings.HasPrefix(normalized, "/context ") {
+		return true, h.handleContext(ctx, strings.Fields(strings.TrimPrefix(normalized, "/context")), w)
+	}
+	if normalized == "/project" || strings.HasPrefix(normalized, "/project ") {
+		return true, h.handleProject(ctx, strings.Fields(strings.TrimPrefix(normalized, "/project")), w)
+	}
+	if normalized == "/memory" || strings.HasPrefix(normalized, "/memory ") {
+		return true, h.handleMemory(ctx, strings.Fields(strings.TrimPrefix(normalized, "/memory")), w)
+	}
+	if normalized == "/todo" || strings.HasPrefix(normalized, "/todo ") {
+		// Keep the original casing: task text is saved verbatim.
+		text := strings.TrimSpace(cmd)
+		return true, h.handleTodo(ctx, strings.TrimSpace(text[len("/todo"):]), w)
+	}
+	if normalized == "/list" || strings.HasPrefix(normalized, "/list ") {
+		// Keep the original casing: item text is saved verbatim.
+		text := strings.TrimSpace(cmd)
+		return true, h.handleList(ctx, strings.TrimSpace(text[len("/list"):]), w)
+	}
+	if normalized == "/outputs" || strings.HasPrefix(normalized, "/outputs ") {
+		return true, h.handleOutputs(ctx, strings.Fields(strings.TrimPrefix(normalized, "/outputs")), w)
+	}
+	if normalized == "/run" || strings.HasPrefix(normalized, "/run ") {
+		return true, h.handleRun(ctx, strings.Fields(strings.TrimPrefix(normalized, "/run")), w)
+	}
+
+	switch normalized {
 	case "/help":
 		return true, h.handleHelp(ctx, w)
 	case "/new", "/reset":
@@ -59,6 +342,26 @@ func (h *Handler) Handle(ctx context.Context, cmd string, w runtime.ResponseWrit
 		return true, h.handleJobs(ctx, w)
 	case "/usage":
 		return true, h.handleUsage(ctx, w)
+	case "/merge-summary", "/merge_summary":
+		return true, h.handleMergeSummary(ctx, w)
+	case "/where":
+		return true, h.handleWhere(ctx, w)
+	case "/delete-last", "/delete_last":
+		return true, h.handleDeleteLast(ctx, w)
+	case "/last-turn", "/last_turn":
+		return true, h.handleLastTurn(ctx, w)
+	case "/artifacts":
+		return true, h.handleArtifacts(ctx, w)
+	case "/approvals":
+		// Channels with asynchronous prompts answer /approvals themselves;
+		// elsewhere approvals are asked inline and never wait.
+		return true, w.WriteMessage(ctx, "No approvals are waiting.")
+	case "/incognito", "/incognito on", "/incognito off":
+		return true, h.handleIncognito(ctx, normalized, w)
+	case "/session list", "/sessions":
+		return true, h.handleSessionList(ctx, w)
+	case "/profile", "/profile apply", "/profile discard":
+		return true, h.handleProfile(ctx, normalized, w)
 	default:
 		return false, nil
 	}
@@ -96,7 +399,7 @@ func (h *Handler) handleJobs(ctx context.Context, w runtime.ResponseWriter) erro
 		if job.Enabled {
 			status = "enabled"
 		}
-		fmt.Fprintf(&b, "%d. %s (%s) - %s\n", i+1, job.Description, job.Cron, status)
+		fmt.Fprintf(&b, "%d. %s (%s) - %s\n", i+1, job.Description, job.Schedule(), status)
 		fmt.Fprintf(&b, "   id: %s, action: %s", job.ID, job.Action)
 		if i < len(jobs)-1 {
 			b.WriteByte('\n')
@@ -130,6 +433,940 @@ func (h *Handler) handleUsage(ctx context.Context, w runtime.ResponseWriter) err
 	return w.WriteMessage(ctx, b.String())
 }
 
+func (h *Handler) handleSessionList(ctx context.Context, w runtime.ResponseWriter) error {
+	if h.sessions == "" {
+		return errors.New("session command is unavailable")
+	}
+	infos, err := session.List(ctx, h.sessions)
+	if err != nil {
+		return err
+	}
+	return w.WriteMessage(ctx, FormatSessionList(infos))
+}
+
+func (h *Handler) handleProfile(ctx context.Context, cmd string, w runtime.ResponseWriter) error {
+	if h.agentDir == "" {
+		return errors.New("profile command is unavailable")
+	}
+	switch cmd {
+	case "/profile apply":
+		proposal, err := agent.PendingUserProfile(h.agentDir)
+		if err != nil {
+			return err
+		}
+		if proposal == "" {
+			return w.WriteMessage(ctx, "No profile update is pending.")
+		}
+		if err := agent.ApplyUserProfile(h.agentDir); err != nil {
+			return err
+		}
+		return w.WriteMessage(ctx, "Profile updated.")
+	case "/profile discard":
+		if err := agent.DiscardUserProfile(h.agentDir); err != nil {
+			return err
+		}
+		return w.WriteMessage(ctx, "Profile update discarded.")
+	default:
+		proposal, err := agent.PendingUserProfile(h.agentDir)
+		if err != nil {
+			return err
+		}
+		if proposal == "" {
+			return w.WriteMessage(ctx, "No profile update is pending.")
+		}
+		return w.WriteMessage(ctx, agent.FormatProfileProposal(proposal))
+	}
+}
+
+func (h *Handler) handleDND(ctx context.Context, arg string, w runtime.ResponseWriter) error {
+	if h.dnd == nil {
+		return errors.New("dnd command is unavailable")
+	}
+	switch arg {
+	case "":
+		return w.WriteMessage(ctx, h.dnd.Status())
+	case "on":
+		h.dnd.SetDND(0)
+	case "off":
+		h.dnd.ClearDND()
+	default:
+		d, err := time.ParseDuration(arg)
+		if err != nil || d <= 0 {
+			return w.WriteMessage(ctx, "Usage: /dnd [on|off|<duration>], for example /dnd 2h")
+		}
+		h.dnd.SetDND(d)
+	}
+	return w.WriteMessage(ctx, h.dnd.Status())
+}
+
+func (h *Handler) handleIncognito(ctx context.Context, cmd string, w runtime.ResponseWriter) error {
+	if h.private == nil {
+		return errors.New("incognito command is unavailable")
+	}
+	switch cmd {
+	case "/incognito on":
+		h.private.SetIncognito(true)
+		return w.WriteMessage(ctx, "Incognito on. Messages from now on are not saved to history, the daily log, or memory. Send /incognito off to resume.")
+	case "/incognito off":
+		if !h.private.Incognito() {
+			return w.WriteMessage(ctx, "Incognito is already off.")
+		}
+		h.private.SetIncognito(false)
+		return w.WriteMessage(ctx, "Incognito off. The private exchange was discarded.")
+	default:
+		if h.private.Incognito() {
+			return w.WriteMessage(ctx, "Incognito is on. Send /incognito off to resume saving.")
+		}
+		return w.WriteMessage(ctx, "Incognito is off. Send /incognito on to stop saving the conversation.")
+	}
+}
+
+func (h *Handler) handleCorrect(ctx context.Context, text string, w runtime.ResponseWriter) error {
+	if h.corrects == nil {
+		return errors.New("correct command is unavailable")
+	}
+	if text == "" {
+		return w.WriteMessage(ctx, "Usage: /correct [<topic>:] <what was wrong and what is right>")
+	}
+	topic, err := h.corrects.Correct(ctx, text)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("Could not save correction: %v", err))
+	}
+	return w.WriteMessage(ctx, fmt.Sprintf("Saved correction under topic %s.", topic))
+}
+
+func (h *Handler) handleFork(ctx context.Context, args []string, w runtime.ResponseWriter) error {
+	if h.forks == nil {
+		return errors.New("fork command is unavailable")
+	}
+	if len(args) > 1 {
+		return w.WriteMessage(ctx, "Usage: /fork [name]")
+	}
+	var name string
+	if len(args) == 1 {
+		name = args[0]
+	}
+	forked, err := h.forks.Fork(ctx, name)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("Could not fork: %v", err))
+	}
+	return w.WriteMessage(ctx, fmt.Sprintf("Forked into session %s. The main conversation is unchanged. Send /merge-summary to return with a summary of this fork.", forked))
+}
+
+func (h *Handler) handleMergeSummary(ctx context.Context, w runtime.ResponseWriter) error {
+	if h.forks == nil {
+		return errors.New("merge-summary command is unavailable")
+	}
+	name, summary, err := h.forks.MergeSummary(ctx)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("Could not merge fork: %v", err))
+	}
+	if summary == "" {
+		return w.WriteMessage(ctx, fmt.Sprintf("Back in the main conversation. Nothing was discussed in fork %s.", name))
+	}
+	return w.WriteMessage(ctx, fmt.Sprintf("Back in the main conversation. Summary of fork %s:\n%s", name, summary))
+}
+
+func (h *Handler) handleAttach(ctx context.Context, args []string, w runtime.ResponseWriter) error {
+	if h.bridge == nil {
+		return errors.New("attach command is unavailable")
+	}
+	if len(args) != 1 {
+		return w.WriteMessage(ctx, fmt.Sprintf("Usage: /attach <channel> (one of: %s)", strings.Join(h.bridge.BridgeChannels(), ", ")))
+	}
+	if err := h.bridge.Attach(ctx, args[0]); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("Could not attach: %v", err))
+	}
+	owner, _, err := h.bridge.Where()
+	if err != nil {
+		return err
+	}
+	return w.WriteMessage(ctx, fmt.Sprintf("Now continuing the %s conversation. Send /where to see who else is attached.", owner))
+}
+
+func (h *Handler) handleWhere(ctx context.Context, w runtime.ResponseWriter) error {
+	if h.bridge == nil {
+		return errors.New("where command is unavailable")
+	}
+	owner, attached, err := h.bridge.Where()
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "This is the %s conversation.", owner)
+	if len(attached) > 0 {
+		b.WriteString("\nAttached channels:")
+		for _, a := range attached {
+			fmt.Fprintf(&b, "\n- %s", a.Channel)
+			if a.Here {
+				b.WriteString(" (here)")
+			} else {
+				fmt.Fprintf(&b, " (pid %d)", a.PID)
+			}
+			fmt.Fprintf(&b, ", since %s", a.Since.Local().Format("Jan 2 15:04"))
+		}
+	}
+	fmt.Fprintf(&b, "\nSend /attach <channel> to switch (one of: %s).", strings.Join(h.bridge.BridgeChannels(), ", "))
+	return w.WriteMessage(ctx, b.String())
+}
+
+func (h *Handler) handleRewind(ctx context.Context, args []string, w runtime.ResponseWriter) error {
+	if h.rewinds == nil {
+		return errors.New("rewind command is unavailable")
+	}
+	n := 1
+	if len(args) > 1 {
+		return w.WriteMessage(ctx, "Usage: /rewind [N]")
+	}
+	if len(args) == 1 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 {
+			return w.WriteMessage(ctx, "Usage: /rewind [N] (N is a number of exchanges, at least 1)")
+		}
+		n = parsed
+	}
+	removed, lastKept, err := h.rewinds.Rewind(ctx, n)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("Could not rewind: %v", err))
+	}
+	exchanges := "exchanges"
+	if removed == 1 {
+		exchanges = "exchange"
+	}
+	if lastKept == "" {
+		return w.WriteMessage(ctx, fmt.Sprintf("Rewound %d %s. The session is now empty.", removed, exchanges))
+	}
+	if runes := []rune(lastKept); len(runes) > rewindPreviewRunes {
+		lastKept = string(runes[:rewindPreviewRunes]) + "…"
+	}
+	return w.WriteMessage(ctx, fmt.Sprintf("Rewound %d %s. The conversation continues after your message:\n%s", removed, exchanges, lastKept))
+}
+
+func (h *Handler) handleDeleteLast(ctx context.Context, w runtime.ResponseWriter) error {
+	if h.deletes == nil {
+		return errors.New("delete-last command is unavailable")
+	}
+	removed, err := h.deletes.DeleteLast(ctx)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("Could not delete: %v", err))
+	}
+	messages := "messages"
+	if removed == 1 {
+		messages = "message"
+	}
+	return w.WriteMessage(ctx, fmt.Sprintf("Deleted your last message and what followed it (%d %s) from the session. The deletion was logged without the message text. Copies outside the session, such as memory or daily logs, are not touched.", removed, messages))
+}
+
+func (h *Handler) handleLastTurn(ctx context.Context, w runtime.ResponseWriter) error {
+	if h.turns == nil {
+		return errors.New("last-turn command is unavailable")
+	}
+	report, err := h.turns.LastTurn()
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		return w.WriteMessage(ctx, "No turn has run in this session yet.")
+	}
+	return w.WriteMessage(ctx, formatTurnReport(*report))
+}
+
+// formatTurnReport renders a turn report for /last-turn.
+func formatTurnReport(report session.TurnReport) string {
+	var b strings.Builder
+	calls := "calls"
+	if report.ModelCalls == 1 {
+		calls = "call"
+	}
+	fmt.Fprintf(&b, "Last turn (%s): %s, %d model %s, %d input + %d output tokens, $%.4f",
+		report.Started.Local().Format("Jan 2 15:04"), formatMillis(report.DurationMS),
+		report.ModelCalls, calls, report.InputTokens, report.OutputTokens, report.CostUSD)
+	if len(report.Tools) == 0 {
+		b.WriteString("\nNo tools were called.")
+	} else {
+		fmt.Fprintf(&b, "\nTools (%d):", len(report.Tools))
+		for _, tool := range report.Tools[:min(len(report.Tools), maxLastTurnTools)] {
+			fmt.Fprintf(&b, "\n- %s %s", tool.Name, formatMillis(tool.DurationMS))
+			if tool.Error != "" {
+				fmt.Fprintf(&b, " (failed: %s)", truncateRunes(tool.Error, 120))
+			}
+		}
+		if extra := len(report.Tools) - maxLastTurnTools; extra > 0 {
+			fmt.Fprintf(&b, "\n… and %d more", extra)
+		}
+	}
+	if len(report.Approvals) > 0 {
+		b.WriteString("\nApprovals:")
+		for _, prompt := range report.Approvals {
+			fmt.Fprintf(&b, "\n- %s %s after %s", prompt.Tool, prompt.Decision, formatMillis(prompt.WaitMS))
+		}
+	}
+	if report.Error != "" {
+		fmt.Fprintf(&b, "\nThe turn failed: %s", truncateRunes(report.Error, 200))
+	}
+	return b.String()
+}
+
+// formatMillis renders a duration in milliseconds to a tenth of a second.
+func formatMillis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
+
+// truncateRunes cuts text to at most n runes, marking the cut.
+func truncateRunes(text string, n int) string {
+	if runes := []rune(text); len(runes) > n {
+		return string(runes[:n]) + "…"
+	}
+	return text
+}
+
+func (h *Handler) handleContext(ctx context.Context, args []string, w runtime.ResponseWriter) error {
+	if h.blocks == nil {
+		return errors.New("context command is unavailable")
+	}
+	switch {
+	case len(args) == 0:
+		disabled, err := h.blocks.DisabledPromptBlocks()
+		if err != nil {
+			return err
+		}
+		coding, err := h.blocks.CodingSession()
+		if err != nil {
+			return err
+		}
+		message := FormatPromptBlocks(disabled, coding)
+		if usage, ok := h.blocks.LastPromptUsage(); ok {
+			message += "\n\n" + FormatPromptUsage(usage)
+		}
+		return w.WriteMessage(ctx, message)
+	case len(args) == 2 && args[0] == "toggle":
+		enabled, err := h.blocks.TogglePromptBlock(args[1])
+		if err != nil {
+			return w.WriteMessage(ctx, fmt.Sprintf("Could not toggle %s: %v", args[1], err))
+		}
+		if enabled {
+			return w.WriteMessage(ctx, fmt.Sprintf("%s is back on for this session.", args[1]))
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("%s is off for this session.", args[1]))
+	case len(args) == 2 && args[0] == "coding" && (args[1] == "on" || args[1] == "off"):
+		coding := args[1] == "on"
+		if err := h.blocks.SetCodingSession(coding); err != nil {
+			return w.WriteMessage(ctx, fmt.Sprintf("Could not change the coding flag: %v", err))
+		}
+		if coding {
+			return w.WriteMessage(ctx, "This is a coding session: each turn includes a snapshot of the workspace and its git status.")
+		}
+		return w.WriteMessage(ctx, "This is no longer a coding session.")
+	default:
+		return w.WriteMessage(ctx, "Usage: /context [toggle <block> | coding on|off]")
+	}
+}
+
+func (h *Handler) handleProject(ctx context.Context, args []string, w runtime.ResponseWriter) error {
+	if h.projects == nil {
+		return errors.New("project command is unavailable")
+	}
+	switch {
+	case len(args) == 0:
+		active, err := h.projects.ActiveProject()
+		if err != nil {
+			return err
+		}
+		return w.WriteMessage(ctx, FormatProjectList(h.projects.Projects(), active))
+	case len(args) == 1 && args[0] == "off":
+		if err := h.projects.SetProject(""); err != nil {
+			return w.WriteMessage(ctx, fmt.Sprintf("Could not leave the project: %v", err))
+		}
+		return w.WriteMessage(ctx, "No project is active in this session.")
+	case len(args) == 1:
+		if err := h.projects.SetProject(args[0]); err != nil {
+			return w.WriteMessage(ctx, fmt.Sprintf("Could not switch to %s: %v", args[0], err))
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("Switched this session to %s. Commands run in its directory, with its pinned files, instructions, and allowed commands, until /project off.", args[0]))
+	default:
+		return w.WriteMessage(ctx, "Usage: /project [<name>|off]")
+	}
+}
+
+// FormatProjectList renders the configured projects, marking the active one.
+func FormatProjectList(names []string, active string) string {
+	if len(names) == 0 {
+		return "No projects configured. Add [projects.<name>] entries to config.toml."
+	}
+	var b strings.Builder
+	b.WriteString("Projects:\n")
+	for _, name := range names {
+		if name == active {
+			fmt.Fprintf(&b, "- %s (active)\n", name)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s\n", name)
+	}
+	b.WriteString("Send /project <name> to switch, or /project off.")
+	return b.String()
+}
+
+// FormatPromptBlocks renders which system-prompt blocks are on for a session
+// and whether it is a coding session.
+func FormatPromptBlocks(disabled []string, coding bool) string {
+	var b strings.Builder
+	b.WriteString("Context blocks for this session:\n")
+	for _, block := range agent.PromptBlocks {
+		state := "on"
+		if slices.Contains(disabled, block) {
+			state = "off"
+		}
+		fmt.Fprintf(&b, "%s: %s\n", block, state)
+	}
+	state := "off"
+	if coding {
+		state = "on"
+	}
+	fmt.Fprintf(&b, "coding session (workspace snapshot): %s\n", state)
+	b.WriteString("Send /context toggle <block> to switch one, or /context coding on|off.")
+	return b.String()
+}
+
+// FormatPromptUsage renders the estimated tokens each part of the last
+// request took, largest first.
+func FormatPromptUsage(usage agent.PromptUsage) string {
+	blocks := slices.Clone(usage.Blocks)
+	slices.SortStableFunc(blocks, func(a, b agent.BlockTokens) int { return b.Tokens - a.Tokens })
+	width := len("total")
+	for _, block := range blocks {
+		width = max(width, len(block.Block))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Estimated tokens in the last request (%s):\n", usage.At.Local().Format("15:04"))
+	for _, block := range blocks {
+		fmt.Fprintf(&b, "%-*s %6d  %3d%%\n", width, block.Block, block.Tokens, block.Tokens*100/max(usage.Total, 1))
+	}
+	fmt.Fprintf(&b, "%-*s %6d", width, "total", usage.Total)
+	return b.String()
+}
+
+func (h *Handler) handleMemory(ctx context.Context, args []string, w runtime.ResponseWriter) error {
+	if h.pending == nil {
+		return errors.New("memory command is unavailable")
+	}
+	const usage = "Usage: /memory pending | /memory approve <n...|all> | /memory reject <n...|all>"
+	if len(args) == 0 {
+		return w.WriteMessage(ctx, usage)
+	}
+	switch args[0] {
+	case "pending":
+		entries, err := h.pending.Pending()
+		if err != nil {
+			return err
+		}
+		return w.WriteMessage(ctx, FormatPendingMemory(entries))
+	case "approve", "reject":
+		if len(args) < 2 {
+			return w.WriteMessage(ctx, usage)
+		}
+		var positions []int
+		if !(len(args) == 2 && args[1] == "all") {
+			for _, arg := range args[1:] {
+				pos, err := strconv.Atoi(arg)
+				if err != nil {
+					return w.WriteMessage(ctx, usage)
+				}
+				positions = append(positions, pos)
+			}
+		}
+		approve := args[0] == "approve"
+		resolved, err := h.pending.ResolvePending(positions, approve)
+		if err != nil {
+			return w.WriteMessage(ctx, fmt.Sprintf("Could not %s memory writes: %v", args[0], err))
+		}
+		if len(resolved) == 0 {
+			return w.WriteMessage(ctx, "No pending memory writes.")
+		}
+		if approve {
+			return w.WriteMessage(ctx, fmt.Sprintf("Saved %d memory write(s).", len(resolved)))
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("Discarded %d memory write(s).", len(resolved)))
+	default:
+		return w.WriteMessage(ctx, usage)
+	}
+}
+
+// FormatPendingMemory renders queued memory writes for review.
+func FormatPendingMemory(entries []memory.PendingEntry) string {
+	if len(entries) == 0 {
+		return "No pending memory writes."
+	}
+	var b strings.Builder
+	b.WriteString("Pending memory writes:\n")
+	for i, entry := range entries {
+		target := "fact"
+		if entry.Target == memory.PendingDailyLog {
+			target = "daily log"
+		}
+		fmt.Fprintf(&b, "%d. [%s] %s: %s\n", i+1, target, strings.Join(entry.Tags, ","), entry.Text)
+	}
+	b.WriteString("Send /memory approve <n...|all> or /memory reject <n...|all>.")
+	return b.String()
+}
+
+func (h *Handler) handleTodo(ctx context.Context, text string, w runtime.ResponseWriter) error {
+	if h.tasks == nil {
+		return errors.New("todo command is unavailable")
+	}
+	const usage = "Usage: /todo | /todo all | /todo add <text> [due YYYY-MM-DD] | /todo done <n>"
+	now := time.Now()
+	verb, rest, _ := strings.Cut(text, " ")
+	rest = strings.TrimSpace(rest)
+	switch strings.ToLower(verb) {
+	case "", "all":
+		tasks, err := h.tasks.List(verb != "")
+		if err != nil {
+			return err
+		}
+		return w.WriteMessage(ctx, todo.FormatList(tasks, now))
+	case "add":
+		if rest == "" {
+			return w.WriteMessage(ctx, usage)
+		}
+		var due string
+		fields := strings.Fields(rest)
+		if n := len(fields); n > 2 && strings.EqualFold(fields[n-2], "due") {
+			due = fields[n-1]
+			rest = strings.Join(fields[:n-2], " ")
+		}
+		task, err := h.tasks.Add(rest, due, now)
+		if err != nil {
+			return w.WriteMessage(ctx, fmt.Sprintf("Could not add task: %v", err))
+		}
+		return w.WriteMessage(ctx, "Added "+task.Format(now))
+	case "done":
+		id, err := strconv.Atoi(strings.TrimPrefix(rest, "#"))
+		if err != nil {
+			return w.WriteMessage(ctx, usage)
+		}
+		task, err := h.tasks.Complete(id, now)
+		if err != nil {
+			return w.WriteMessage(ctx, fmt.Sprintf("Could not complete task: %v", err))
+		}
+		return w.WriteMessage(ctx, "Done: "+task.Format(now))
+	default:
+		return w.WriteMessage(ctx, usage)
+	}
+}
+
+// handleLanguage shows or changes the reply language of the user who sent
+// the command.
+func (h *Handler) handleLanguage(ctx context.Context, userID string, args []string, w runtime.ResponseWriter) error {
+	if h.langs == nil {
+		return errors.New("language command is unavailable")
+	}
+	const usage = "Usage: /language | /language <code> | /language auto | /language off"
+	pref, err := h.langs.Get(userID)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return w.WriteMessage(ctx, usage)
+	}
+	if len(args) == 0 {
+		return w.WriteMessage(ctx, FormatLanguage(pref)+"\nSend /language <code>, /language auto, or /language off to change it.")
+	}
+	pref.UpdatedAt = time.Now().UTC()
+	switch args[0] {
+	case language.ModeAuto, language.ModeOff:
+		pref.Mode = args[0]
+	default:
+		code, err := language.Normalize(args[0])
+		if err != nil {
+			return w.WriteMessage(ctx, fmt.Sprintf("%v\n%s", err, usage))
+		}
+		pref.Language, pref.Mode = code, language.ModeSet
+	}
+	if err := h.langs.Set(userID, pref); err != nil {
+		return err
+	}
+	return w.WriteMessage(ctx, FormatLanguage(pref))
+}
+
+// FormatLanguage describes one user's reply language setting.
+func FormatLanguage(pref language.Preference) string {
+	switch {
+	case pref.Mode == language.ModeOff:
+		return "Language matching is off: replies follow the system prompt."
+	case pref.Mode == language.ModeSet:
+		return fmt.Sprintf("Replies to you are in %s.", language.Name(pref.Language))
+	case pref.Language != "":
+		return fmt.Sprintf("Replies to you follow the language of your messages, currently %s.", language.Name(pref.Language))
+	default:
+		return "Replies to you will follow the language of your messages."
+	}
+}
+
+func (h *Handler) handleList(ctx context.Context, text string, w runtime.ResponseWriter) error {
+	if h.lists == nil {
+		return errors.New("list command is unavailable")
+	}
+	const usage = "Usage: /list [<name>] [show | add <item, item...> | check <n|item> | remove <n|item> | clear]"
+	name := lists.DefaultList
+	verb, rest, _ := strings.Cut(text, " ")
+	switch strings.ToLower(verb) {
+	case "", "show", "add", "check", "remove", "clear":
+	default:
+		// A leading word that is not a verb names the list.
+		name = verb
+		verb, rest, _ = strings.Cut(strings.TrimSpace(rest), " ")
+	}
+	rest = strings.TrimSpace(rest)
+
+	switch strings.ToLower(verb) {
+	case "", "show":
+		if rest != "" {
+			name = rest
+		}
+	case "add":
+		if rest == "" {
+			return w.WriteMessage(ctx, usage)
+		}
+		if _, err := h.lists.Add(name, strings.Split(rest, ","), time.Now()); err != nil {
+			return err
+		}
+	case "check", "remove":
+		if rest == "" {
+			return w.WriteMessage(ctx, usage)
+		}
+		change := h.lists.Toggle
+		if strings.EqualFold(verb, "remove") {
+			change = h.lists.Remove
+		}
+		if _, err := change(name, rest); err != nil {
+			return w.WriteMessage(ctx, fmt.Sprintf("Could not update list: %v", err))
+		}
+	case "clear":
+		if _, err := h.lists.ClearChecked(name); err != nil {
+			return err
+		}
+	default:
+		return w.WriteMessage(ctx, usage)
+	}
+	items, err := h.lists.Items(name)
+	if err != nil {
+		return err
+	}
+	return w.WriteMessage(ctx, lists.Format(name, items))
+}
+
+func (h *Handler) handleArtifacts(ctx context.Context, w runtime.ResponseWriter) error {
+	if h.outputs == nil {
+		return errors.New("artifacts command is unavailable")
+	}
+	items, err := h.outputs.List()
+	if err != nil {
+		return err
+	}
+	return w.WriteMessage(ctx, FormatArtifactList(items))
+}
+
+func (h *Handler) handleArtifactGet(ctx context.Context, rawID string, w runtime.ResponseWriter) error {
+	if h.outputs == nil {
+		return errors.New("artifacts command is unavailable")
+	}
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		return w.WriteMessage(ctx, "Usage: /artifact_<id>, for example /artifact_3")
+	}
+	item, ok, err := h.outputs.Get(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return w.WriteMessage(ctx, fmt.Sprintf("No artifact %d. Send /artifacts to list them.", id))
+	}
+	if _, err := os.Stat(item.Path); err != nil {
+		return w.WriteMessage(ctx, fmt.Sprintf("Artifact %d (%s) is no longer on disk.", id, item.Name()))
+	}
+	if sender, ok := w.(runtime.FileSender); ok {
+		return sender.SendFile(ctx, item.Path, item.Description)
+	}
+	return w.WriteMessage(ctx, fmt.Sprintf("%s: %s", item.Description, item.Path))
+}
+
+// FormatArtifactList renders artifacts newest first with their download command.
+func FormatArtifactList(items []artifacts.Artifact) string {
+	if len(items) == 0 {
+		return "No artifacts yet."
+	}
+	var b strings.Builder
+	b.WriteString("Artifacts:\n")
+	for i, item := range items {
+		fmt.Fprintf(&b, "%d. %s - %s\n", item.ID, item.Name(), item.Description)
+		fmt.Fprintf(&b, "   %s, %s  /artifact_%d", formatSize(item.Size), item.CreatedAt.Local().Format("2006-01-02 15:04"), item.ID)
+		if i < len(items)-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+const (
+	// defaultOutputsListed is how many outputs /outputs lists by default.
+	defaultOutputsListed = 10
+	// outputPageBytes keeps one page of /outputs show within a chat message.
+	outputPageBytes = 3500
+)
+
+func (h *Handler) handleOutputs(ctx context.Context, args []string, w runtime.ResponseWriter) error {
+	if h.results == nil {
+		return errors.New("outputs command is unavailable")
+	}
+	const usage = "Usage: /outputs [n] or /outputs show <n> [page]"
+	if len(args) > 0 && args[0] == "show" {
+		return h.handleOutputShow(ctx, args[1:], w)
+	}
+	limit := defaultOutputsListed
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 || len(args) > 1 {
+			return w.WriteMessage(ctx, usage)
+		}
+		limit = n
+	}
+	items, err := h.results.List(limit)
+	if err != nil {
+		return err
+	}
+	return w.WriteMessage(ctx, FormatOutputList(items))
+}
+
+func (h *Handler) handleOutputShow(ctx context.Context, args []string, w runtime.ResponseWriter) error {
+	const usage = "Usage: /outputs show <n> [page], for example /outputs show 1"
+	if len(args) == 0 || len(args) > 2 {
+		return w.WriteMessage(ctx, usage)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return w.WriteMessage(ctx, usage)
+	}
+	page := 1
+	if len(args) == 2 {
+		if page, err = strconv.Atoi(args[1]); err != nil || page <= 0 {
+			return w.WriteMessage(ctx, usage)
+		}
+	}
+	items, err := h.results.List(n)
+	if err != nil {
+		return err
+	}
+	if n > len(items) {
+		return w.WriteMessage(ctx, fmt.Sprintf("No output %d. Send /outputs to list them.", n))
+	}
+	item := items[n-1]
+	raw, err := os.ReadFile(item.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return w.WriteMessage(ctx, fmt.Sprintf("Output %d is no longer on disk.", n))
+		}
+		return fmt.Errorf("read tool output: %w", err)
+	}
+	pages := splitOutputPages(string(raw), outputPageBytes)
+	if page > len(pages) {
+		return w.WriteMessage(ctx, fmt.Sprintf("Output %d has %d page(s).", n, len(pages)))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s, page %d of %d:\n%s", item.Tool, page, len(pages), pages[page-1])
+	if page < len(pages) {
+		fmt.Fprintf(&b, "\n\nNext page: /outputs show %d %d", n, page+1)
+	}
+	return w.WriteMessage(ctx, b.String())
+}
+
+// FormatOutputList renders saved tool outputs newest first, numbered for
+// /outputs show.
+func FormatOutputList(items []outputs.Output) string {
+	if len(items) == 0 {
+		return "No full tool outputs saved. Outputs are kept when they are too long to give to the agent in full."
+	}
+	var b strings.Builder
+	b.WriteString("Full tool outputs:\n")
+	for i, item := range items {
+		fmt.Fprintf(&b, "%d. %s - %s, %s  /outputs show %d", i+1, item.Tool, formatSize(item.Size), item.CreatedAt.Local().Format("2006-01-02 15:04"), i+1)
+		if i < len(items)-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// splitOutputPages cuts text into pages of at most size bytes, preferring
+// line breaks and never splitting a UTF-8 sequence.
+func splitOutputPages(text string, size int) []string {
+	var pages []string
+	for len(text) > size {
+		cut := size
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		if nl := strings.LastIndexByte(text[:cut], '\n'); nl > size/2 {
+			cut = nl + 1
+		}
+		pages = append(pages, text[:cut])
+		text = text[cut:]
+	}
+	return append(pages, text)
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+func (h *Handler) handleRun(ctx context.Context, args []string, w runtime.ResponseWriter) error {
+	if h.flows == nil {
+		return errors.New("run command is unavailable")
+	}
+	if len(args) == 0 {
+		defs, err := workflow.List(h.flows.Dir)
+		if len(defs) == 0 && err != nil {
+			return err
+		}
+		if err != nil {
+			logging.Logger().Warn("skipping invalid workflows", "err", err)
+		}
+		return w.WriteMessage(ctx, FormatWorkflowList(defs))
+	}
+	if len(args) > 2 || (len(args) == 2 && args[1] != "resume") {
+		return w.WriteMessage(ctx, "Usage: /run <workflow> [resume]")
+	}
+
+	name := args[0]
+	resume := len(args) == 2
+	res, err := h.flows.Run(ctx, name, resume)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return w.WriteMessage(ctx, fmt.Sprintf("Could not run workflow %s: %v", name, err))
+	}
+	return w.WriteMessage(ctx, workflow.FormatResult(res))
+}
+
+// FormatWorkflowList renders available workflows with their descriptions.
+func FormatWorkflowList(defs []workflow.Definition) string {
+	if len(defs) == 0 {
+		return "No workflows."
+	}
+	var b strings.Builder
+	b.WriteString("Workflows:\n")
+	for i, def := range defs {
+		steps := "steps"
+		if len(def.Steps) == 1 {
+			steps = "step"
+		}
+		fmt.Fprintf(&b, "- %s (%d %s)", def.Name, len(def.Steps), steps)
+		if desc := strings.TrimSpace(def.Description); desc != "" {
+			fmt.Fprintf(&b, ": %s", desc)
+		}
+		if i < len(defs)-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// expandPrompt handles /prompt. It returns the expanded template text when the
+// message should be forwarded to the agent, or handled=true when a reply
+// (template list or usage error) was written instead.
+func (h *Handler) expandPrompt(ctx context.Context, text string, w runtime.ResponseWriter) (expanded string, handled bool, err error) {
+	fields := prompts.SplitArgs(strings.TrimSpace(text))
+	if len(fields) == 0 || strings.ToLower(fields[0]) != "/prompt" {
+		return "", false, nil
+	}
+	if h.prompts == "" {
+		return "", true, errors.New("prompt command is unavailable")
+	}
+	if len(fields) == 1 || strings.ToLower(fields[1]) == "list" {
+		templates, err := prompts.List(h.prompts)
+		if err != nil {
+			return "", true, err
+		}
+		return "", true, w.WriteMessage(ctx, FormatPromptList(templates))
+	}
+
+	name := strings.ToLower(fields[1])
+	tmpl, err := prompts.Load(h.prompts, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", true, w.WriteMessage(ctx, fmt.Sprintf("No prompt named %q. Send /prompt to list saved prompts.", name))
+	}
+	if err != nil {
+		return "", true, err
+	}
+	out, err := prompts.Expand(tmpl.Body, fields[2:], time.Now())
+	if err != nil {
+		return "", true, w.WriteMessage(ctx, fmt.Sprintf("Prompt %q: %v", name, err))
+	}
+	return out, true, nil
+}
+
+// FormatPromptList renders saved prompt templates with their first line.
+func FormatPromptList(templates []prompts.Template) string {
+	if len(templates) == 0 {
+		return "No saved prompts."
+	}
+	var b strings.Builder
+	b.WriteString("Prompts:\n")
+	for i, tmpl := range templates {
+		fmt.Fprintf(&b, "- %s: %s", tmpl.Name, tmpl.Summary())
+		if i < len(templates)-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// FormatSessionList renders sessions as a numbered list, one per entry.
+func FormatSessionList(infos []session.Info) string {
+	if len(infos) == 0 {
+		return "No saved sessions."
+	}
+	var b strings.Builder
+	b.WriteString("Sessions:\n")
+	for i, info := range infos {
+		title := info.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		fmt.Fprintf(&b, "%d. %s - %s\n", i+1, info.Name, title)
+		fmt.Fprintf(&b, "   turns: %d, updated: %s", info.Turns, info.UpdatedAt.Format("2006-01-02 15:04"))
+		if i < len(infos)-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
 // Router dispatches slash commands before delegating to the next runtime.Handler.
 type Router struct {
 	Commands *Handler
@@ -145,7 +1382,26 @@ func (r Router) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg
 		return errors.New("next handler is required")
 	}
 	if r.Commands != nil {
-		handled, err := r.Commands.Handle(ctx, msg.Text, w)
+		expanded, handled, err := r.Commands.expandPrompt(ctx, msg.Text, w)
+		if err != nil {
+			return err
+		}
+		if expanded != "" {
+			forwarded := *msg
+			forwarded.Text = expanded
+			return r.Next.HandleMessage(ctx, w, &forwarded)
+		}
+		if handled {
+			return nil
+		}
+		// /language applies to the sender, so it needs the whole message.
+		if normalized := normalize(msg.Text); normalized == "/language" || strings.HasPrefix(normalized, "/language ") {
+			return r.Commands.handleLanguage(ctx, msg.UserID, strings.Fields(strings.TrimPrefix(normalized, "/language")), w)
+		}
+		handled, err = r.Commands.Handle(ctx, msg.Text, w)
+		if handled {
+			telemetry.Feature("command." + commandName(msg.Text))
+		}
 		if handled || err != nil {
 			return err
 		}
@@ -153,6 +1409,16 @@ func (r Router) HandleMessage(ctx context.Context, w runtime.ResponseWriter, msg
 	return r.Next.HandleMessage(ctx, w, msg)
 }
 
+// commandName is the command without arguments or ids, e.g. "/artifact" for
+// /artifact_3, so telemetry never records what the user typed after it.
+func commandName(text string) string {
+	name, _, _ := strings.Cut(normalize(text), " ")
+	if strings.HasPrefix(name, "/artifact_") {
+		return "/artifact"
+	}
+	return name
+}
+
 func normalize(text string) string {
 	return strings.ToLower(strings.TrimSpace(text))
 }
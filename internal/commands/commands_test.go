@@ -3,14 +3,26 @@ package commands
 import (
 	"context"
 	"errors"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/neoclaw-ai/neoclaw/internal/agent"
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
 	"github.com/neoclaw-ai/neoclaw/internal/costs"
+	"github.com/neoclaw-ai/neoclaw/internal/language"
+	"github.com/neoclaw-ai/neoclaw/internal/lists"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/outputs"
+	"github.com/neoclaw-ai/neoclaw/internal/prompts"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
 	"github.com/neoclaw-ai/neoclaw/internal/runtime"
 	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+	"github.com/neoclaw-ai/neoclaw/internal/todo"
+	"github.com/neoclaw-ai/neoclaw/internal/workflow"
 )
 
 func TestHelpCommand(t *testing.T) {
@@ -127,6 +139,154 @@ func TestRouterHandlesSlashCommand(t *testing.T) {
 	}
 }
 
+func TestRouterExpandsPrompt(t *testing.T) {
+	dir := t.TempDir()
+	if err := prompts.Save(dir, "email", "Draft an email to {{1}} about {{2}}."); err != nil {
+		t.Fatalf("save prompt: %v", err)
+	}
+	next := &fakeRuntimeHandler{}
+	handler := New(nil, nil, nil, 0, 0)
+	handler.ConfigurePrompts(dir)
+	router := Router{Commands: handler, Next: next}
+
+	w := &captureWriter{}
+	if err := router.HandleMessage(context.Background(), w, &runtime.Message{Text: `/prompt email "Sarah Lee" invoices`}); err != nil {
+		t.Fatalf("router /prompt: %v", err)
+	}
+	if next.calls != 1 || next.last != "Draft an email to Sarah Lee about invoices." {
+		t.Fatalf("unexpected forwarded message: calls=%d text=%q", next.calls, next.last)
+	}
+
+	w = &captureWriter{}
+	if err := router.HandleMessage(context.Background(), w, &runtime.Message{Text: "/prompt email Sam"}); err != nil {
+		t.Fatalf("router /prompt missing arg: %v", err)
+	}
+	if next.calls != 1 || len(w.messages) != 1 || !strings.Contains(w.messages[0], "missing arguments") {
+		t.Fatalf("expected usage error, got calls=%d messages=%#v", next.calls, w.messages)
+	}
+
+	w = &captureWriter{}
+	if err := router.HandleMessage(context.Background(), w, &runtime.Message{Text: "/prompt"}); err != nil {
+		t.Fatalf("router /prompt list: %v", err)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "Prompts:\n- email: Draft an email to {{1}} about {{2}}." {
+		t.Fatalf("unexpected list output: %#v", w.messages)
+	}
+}
+
+func TestRunWorkflowCommand(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "review.yaml"), []byte("description: Weekly review\nsteps:\n  - type: prompt\n    prompt: Review my week\n"), 0o644); err != nil {
+		t.Fatalf("write workflow: %v", err)
+	}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureWorkflows(&workflow.Runner{
+		Dir:      dir,
+		StateDir: filepath.Join(dir, "runs"),
+		Prompt: func(_ context.Context, text string) (string, error) {
+			return "Reviewed: " + text, nil
+		},
+	})
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/run", w); err != nil {
+		t.Fatalf("handle /run: %v", err)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "Workflows:\n- review (1 step): Weekly review" {
+		t.Fatalf("unexpected list output: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/run review", w); err != nil {
+		t.Fatalf("handle /run review: %v", err)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "Workflow review completed.\n\nReviewed: Review my week" {
+		t.Fatalf("unexpected run output: %#v", w.messages)
+	}
+}
+
+func TestArtifactCommands(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.md")
+	if err := os.WriteFile(report, []byte("# Report\n"), 0o644); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	store := artifacts.New(filepath.Join(dir, "artifacts.json"))
+	if _, err := store.Add(report, "Weekly report", time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)); err != nil {
+		t.Fatalf("add artifact: %v", err)
+	}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureArtifacts(store)
+
+	w := &fileCaptureWriter{}
+	if _, err := h.Handle(context.Background(), "/artifacts", w); err != nil {
+		t.Fatalf("handle /artifacts: %v", err)
+	}
+	want := "Artifacts:\n1. report.md - Weekly report\n   9 B, 2026-03-01 10:00  /artifact_1"
+	if len(w.messages) != 1 || w.messages[0] != want {
+		t.Fatalf("unexpected list output: %#v", w.messages)
+	}
+
+	w = &fileCaptureWriter{}
+	handled, err := h.Handle(context.Background(), "/artifact_1", w)
+	if err != nil || !handled {
+		t.Fatalf("handle /artifact_1: handled=%v err=%v", handled, err)
+	}
+	if len(w.files) != 1 || w.files[0] != report {
+		t.Fatalf("expected report sent as file, got %#v", w.files)
+	}
+
+	plain := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/artifacts get 1", plain); err != nil {
+		t.Fatalf("handle /artifacts get 1: %v", err)
+	}
+	if len(plain.messages) != 1 || plain.messages[0] != "Weekly report: "+report {
+		t.Fatalf("unexpected fallback output: %#v", plain.messages)
+	}
+}
+
+func TestOutputsCommands(t *testing.T) {
+	store := outputs.New(t.TempDir())
+	long := strings.Repeat("line of output\n", 400)
+	if _, err := store.Save("run_command", long, time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)); err != nil {
+		t.Fatalf("save output: %v", err)
+	}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureToolOutputs(store)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/outputs", w); err != nil {
+		t.Fatalf("handle /outputs: %v", err)
+	}
+	want := "Full tool outputs:\n1. run_command - 5.9 KB, 2026-03-01 10:00  /outputs show 1"
+	if len(w.messages) != 1 || w.messages[0] != want {
+		t.Fatalf("unexpected list output: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/outputs show 1", w); err != nil {
+		t.Fatalf("handle /outputs show 1: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "run_command, page 1 of 2:\nline of output\n") || !strings.HasSuffix(w.messages[0], "\n\nNext page: /outputs show 1 2") {
+		t.Fatalf("unexpected first page: %#v", w.messages)
+	}
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/outputs show 1 2", w); err != nil {
+		t.Fatalf("handle /outputs show 1 2: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "run_command, page 2 of 2:\nline of output\n") || strings.Contains(w.messages[0], "Next page") {
+		t.Fatalf("unexpected last page: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/outputs show 2", w); err != nil {
+		t.Fatalf("handle /outputs show 2: %v", err)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "No output 2. Send /outputs to list them." {
+		t.Fatalf("unexpected missing output reply: %#v", w.messages)
+	}
+}
+
 func TestResetErrorReturned(t *testing.T) {
 	resetter := &fakeResetter{err: errors.New("boom")}
 	h := New(resetter, nil, nil, 0, 0)
@@ -176,6 +336,77 @@ func TestUsageCommand(t *testing.T) {
 	}
 }
 
+func TestSessionListCommand(t *testing.T) {
+	dir := t.TempDir()
+	store := session.New(filepath.Join(dir, "telegram", "default.jsonl"))
+	if err := store.Append(context.Background(), []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "plan my trip"},
+		{Role: provider.RoleAssistant, Content: "sure"},
+	}); err != nil {
+		t.Fatalf("append session: %v", err)
+	}
+	if err := store.SetTitle("Lisbon trip planning"); err != nil {
+		t.Fatalf("set title: %v", err)
+	}
+
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureSessions(dir)
+	w := &captureWriter{}
+
+	handled, err := h.Handle(context.Background(), "/session list", w)
+	if err != nil {
+		t.Fatalf("handle /session list: %v", err)
+	}
+	if !handled {
+		t.Fatalf("expected /session list handled")
+	}
+	if len(w.messages) != 1 {
+		t.Fatalf("expected one message, got %#v", w.messages)
+	}
+	if !strings.Contains(w.messages[0], "1. telegram/default - Lisbon trip planning") {
+		t.Fatalf("unexpected session list output: %q", w.messages[0])
+	}
+	if !strings.Contains(w.messages[0], "turns: 1") {
+		t.Fatalf("expected turn count in output: %q", w.messages[0])
+	}
+}
+
+func TestProfileApplyCommand(t *testing.T) {
+	agentDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(agentDir, "USER.proposed.md"), []byte("Name: Sam\n"), 0o644); err != nil {
+		t.Fatalf("write proposal: %v", err)
+	}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureProfile(agentDir)
+	w := &captureWriter{}
+
+	handled, err := h.Handle(context.Background(), "/profile apply", w)
+	if err != nil {
+		t.Fatalf("handle /profile apply: %v", err)
+	}
+	if !handled {
+		t.Fatalf("expected /profile apply handled")
+	}
+	if len(w.messages) != 1 || w.messages[0] != "Profile updated." {
+		t.Fatalf("unexpected output: %#v", w.messages)
+	}
+	got, err := os.ReadFile(filepath.Join(agentDir, "USER.md"))
+	if err != nil {
+		t.Fatalf("read USER.md: %v", err)
+	}
+	if string(got) != "Name: Sam\n" {
+		t.Fatalf("unexpected USER.md: %q", got)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/profile", w); err != nil {
+		t.Fatalf("handle /profile: %v", err)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "No profile update is pending." {
+		t.Fatalf("unexpected output: %#v", w.messages)
+	}
+}
+
 type fakeResetter struct {
 	calls int
 	err   error
@@ -188,10 +419,12 @@ func (r *fakeResetter) Reset(_ context.Context) error {
 
 type fakeRuntimeHandler struct {
 	calls int
+	last  string
 }
 
-func (h *fakeRuntimeHandler) HandleMessage(_ context.Context, _ runtime.ResponseWriter, _ *runtime.Message) error {
+func (h *fakeRuntimeHandler) HandleMessage(_ context.Context, _ runtime.ResponseWriter, msg *runtime.Message) error {
 	h.calls++
+	h.last = msg.Text
 	return nil
 }
 
@@ -203,3 +436,629 @@ func (w *captureWriter) WriteMessage(_ context.Context, text string) error {
 	w.messages = append(w.messages, text)
 	return nil
 }
+
+func TestDNDCommand(t *testing.T) {
+	dnd := &fakeDND{}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureDND(dnd)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/dnd 2h", w); err != nil {
+		t.Fatalf("handle /dnd 2h: %v", err)
+	}
+	if dnd.duration != 2*time.Hour || !dnd.on {
+		t.Fatalf("expected dnd set for 2h, got %+v", dnd)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/dnd off", w); err != nil {
+		t.Fatalf("handle /dnd off: %v", err)
+	}
+	if dnd.on {
+		t.Fatalf("expected dnd cleared")
+	}
+	if len(w.messages) != 1 || w.messages[0] != "off" {
+		t.Fatalf("unexpected output: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/dnd soon", w); err != nil {
+		t.Fatalf("handle /dnd soon: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "Usage: /dnd") {
+		t.Fatalf("unexpected output: %#v", w.messages)
+	}
+}
+
+func TestIncognitoCommand(t *testing.T) {
+	private := &fakeIncognito{}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureIncognito(private)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/incognito on", w); err != nil {
+		t.Fatalf("handle /incognito on: %v", err)
+	}
+	if !private.on || len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "Incognito on.") {
+		t.Fatalf("expected incognito on, got %v %#v", private.on, w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/incognito", w); err != nil {
+		t.Fatalf("handle /incognito: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "Incognito is on.") {
+		t.Fatalf("unexpected status: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/incognito off", w); err != nil {
+		t.Fatalf("handle /incognito off: %v", err)
+	}
+	if private.on || len(w.messages) != 1 || !strings.Contains(w.messages[0], "discarded") {
+		t.Fatalf("expected incognito off, got %v %#v", private.on, w.messages)
+	}
+}
+
+func TestCorrectCommandKeepsTextCasing(t *testing.T) {
+	corrector := &fakeCorrector{topic: "nas"}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureCorrections(corrector)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/correct NAS: it runs TrueNAS, not Unraid", w); err != nil {
+		t.Fatalf("handle /correct: %v", err)
+	}
+	if corrector.text != "NAS: it runs TrueNAS, not Unraid" {
+		t.Fatalf("expected verbatim correction text, got %q", corrector.text)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "Saved correction under topic nas." {
+		t.Fatalf("unexpected reply: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/correct", w); err != nil {
+		t.Fatalf("handle bare /correct: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "Usage: /correct") {
+		t.Fatalf("expected usage, got %#v", w.messages)
+	}
+
+	corrector.err = errors.New("there is no previous answer to correct")
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/correct wrong", w); err != nil {
+		t.Fatalf("handle failing /correct: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.Contains(w.messages[0], "no previous answer") {
+		t.Fatalf("expected failure message, got %#v", w.messages)
+	}
+}
+
+func TestAttachAndWhereCommands(t *testing.T) {
+	since := time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	bridge := &fakeBridge{owner: "cli", attached: []session.Attachment{
+		{Channel: "telegram", PID: 4121, Since: since},
+		{Channel: "cli", PID: 77, Since: since, Here: true},
+	}}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureBridge(bridge)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/attach", w); err != nil {
+		t.Fatalf("handle /attach: %v", err)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "Usage: /attach <channel> (one of: cli, telegram)" {
+		t.Fatalf("unexpected usage reply: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/attach telegram", w); err != nil {
+		t.Fatalf("handle /attach telegram: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "Now continuing the telegram conversation.") {
+		t.Fatalf("unexpected attach reply: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/where", w); err != nil {
+		t.Fatalf("handle /where: %v", err)
+	}
+	want := "This is the telegram conversation.\nAttached channels:\n- telegram (pid 4121), since Mar 1 10:00\n- cli (here), since Mar 1 10:00\nSend /attach <channel> to switch (one of: cli, telegram)."
+	if len(w.messages) != 1 || w.messages[0] != want {
+		t.Fatalf("unexpected where reply: %#v", w.messages)
+	}
+}
+
+func TestForkAndMergeSummaryCommands(t *testing.T) {
+	forker := &fakeForker{}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureForks(forker)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/fork try-sqlite", w); err != nil {
+		t.Fatalf("handle /fork: %v", err)
+	}
+	if forker.name != "try-sqlite" || len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "Forked into session try-sqlite.") {
+		t.Fatalf("unexpected fork reply: %q %#v", forker.name, w.messages)
+	}
+
+	forker.summary = "SQLite is enough for one user."
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/merge_summary", w); err != nil {
+		t.Fatalf("handle /merge_summary: %v", err)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "Back in the main conversation. Summary of fork try-sqlite:\nSQLite is enough for one user." {
+		t.Fatalf("unexpected merge reply: %#v", w.messages)
+	}
+
+	forker.err = errors.New("not in a fork; use /fork first")
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/merge-summary", w); err != nil {
+		t.Fatalf("handle /merge-summary: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "Could not merge fork: not in a fork") {
+		t.Fatalf("expected merge failure, got %#v", w.messages)
+	}
+}
+
+func TestRewindCommand(t *testing.T) {
+	rewinder := &fakeRewinder{removed: 2, lastKept: "name the project"}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureRewind(rewinder)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/rewind 2", w); err != nil {
+		t.Fatalf("handle /rewind: %v", err)
+	}
+	if rewinder.n != 2 || len(w.messages) != 1 || w.messages[0] != "Rewound 2 exchanges. The conversation continues after your message:\nname the project" {
+		t.Fatalf("unexpected rewind reply: %d %#v", rewinder.n, w.messages)
+	}
+
+	rewinder.removed, rewinder.lastKept = 1, ""
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/rewind", w); err != nil {
+		t.Fatalf("handle /rewind: %v", err)
+	}
+	if rewinder.n != 1 || w.messages[0] != "Rewound 1 exchange. The session is now empty." {
+		t.Fatalf("unexpected default rewind reply: %d %#v", rewinder.n, w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/rewind zero", w); err != nil {
+		t.Fatalf("handle /rewind zero: %v", err)
+	}
+	if !strings.HasPrefix(w.messages[0], "Usage: /rewind [N]") {
+		t.Fatalf("expected usage, got %#v", w.messages)
+	}
+
+	rewinder.err = errors.New("nothing to rewind")
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/rewind", w); err != nil {
+		t.Fatalf("handle /rewind: %v", err)
+	}
+	if w.messages[0] != "Could not rewind: nothing to rewind" {
+		t.Fatalf("expected rewind failure, got %#v", w.messages)
+	}
+}
+
+func TestDeleteLastCommand(t *testing.T) {
+	deleter := &fakeDeleter{removed: 2}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureDeletions(deleter)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/delete_last", w); err != nil {
+		t.Fatalf("handle /delete_last: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "Deleted your last message and what followed it (2 messages)") {
+		t.Fatalf("unexpected delete reply: %#v", w.messages)
+	}
+
+	deleter.err = errors.New("nothing to delete")
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/delete-last", w); err != nil {
+		t.Fatalf("handle /delete-last: %v", err)
+	}
+	if w.messages[0] != "Could not delete: nothing to delete" {
+		t.Fatalf("expected delete failure, got %#v", w.messages)
+	}
+}
+
+func TestContextCommandTogglesBlocks(t *testing.T) {
+	blocks := &fakePromptBlocks{}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigurePromptBlocks(blocks)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/context toggle facts", w); err != nil {
+		t.Fatalf("handle /context toggle: %v", err)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "facts is off for this session." {
+		t.Fatalf("unexpected toggle reply: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/context", w); err != nil {
+		t.Fatalf("handle /context: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.Contains(w.messages[0], "profile: on\nfacts: off\ndaily_logs: on") {
+		t.Fatalf("unexpected status: %#v", w.messages)
+	}
+	if strings.Contains(w.messages[0], "Estimated tokens") {
+		t.Fatalf("expected no token breakdown before the first turn: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/context coding on", w); err != nil {
+		t.Fatalf("handle /context coding: %v", err)
+	}
+	if !blocks.coding || len(w.messages) != 1 || !strings.Contains(w.messages[0], "coding session") {
+		t.Fatalf("expected the session to be flagged as coding: %#v", w.messages)
+	}
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/context", w); err != nil {
+		t.Fatalf("handle /context: %v", err)
+	}
+	if !strings.Contains(w.messages[0], "coding session (workspace snapshot): on") {
+		t.Fatalf("expected the coding flag in the status: %#v", w.messages)
+	}
+
+	blocks.usage = agent.PromptUsage{
+		At: time.Date(2026, 3, 1, 9, 30, 0, 0, time.Local),
+		Blocks: []agent.BlockTokens{
+			{Block: "instructions", Tokens: 900},
+			{Block: "tool_schemas", Tokens: 3000},
+			{Block: "history", Tokens: 1100},
+		},
+		Total: 5000,
+	}
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/context", w); err != nil {
+		t.Fatalf("handle /context: %v", err)
+	}
+	want := "Estimated tokens in the last request (09:30):\n" +
+		"tool_schemas   3000   60%\n" +
+		"history        1100   22%\n" +
+		"instructions    900   18%\n" +
+		"total          5000"
+	if !strings.HasSuffix(w.messages[0], want) {
+		t.Fatalf("unexpected token breakdown:\n%s", w.messages[0])
+	}
+}
+
+func TestMemoryPendingCommand(t *testing.T) {
+	store, err := memory.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("new memory store: %v", err)
+	}
+	if err := store.QueuePending(memory.PendingMemory, memory.LogEntry{Tags: []string{"diet"}, Text: "Vegetarian"}); err != nil {
+		t.Fatalf("queue: %v", err)
+	}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureMemoryReview(store)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/memory pending", w); err != nil {
+		t.Fatalf("handle /memory pending: %v", err)
+	}
+	if len(w.messages) != 1 || !strings.Contains(w.messages[0], "1. [fact] diet: Vegetarian") {
+		t.Fatalf("unexpected pending list: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/memory approve all", w); err != nil {
+		t.Fatalf("handle /memory approve: %v", err)
+	}
+	if len(w.messages) != 1 || w.messages[0] != "Saved 1 memory write(s)." {
+		t.Fatalf("unexpected approve reply: %#v", w.messages)
+	}
+	if facts := store.ActiveFacts(time.Now()); len(facts) != 1 {
+		t.Fatalf("expected approved fact, got %#v", facts)
+	}
+}
+
+func TestProjectCommand(t *testing.T) {
+	projects := &fakeProjects{names: []string{"blog", "dotfiles"}}
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureProjects(projects)
+
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/project blog", w); err != nil {
+		t.Fatalf("handle /project blog: %v", err)
+	}
+	if projects.active != "blog" || len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], "Switched this session to blog.") {
+		t.Fatalf("unexpected switch: active=%q %#v", projects.active, w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/project", w); err != nil {
+		t.Fatalf("handle /project: %v", err)
+	}
+	if want := "Projects:\n- blog (active)\n- dotfiles\n"; len(w.messages) != 1 || !strings.HasPrefix(w.messages[0], want) {
+		t.Fatalf("unexpected project list: %#v", w.messages)
+	}
+
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/project off", w); err != nil {
+		t.Fatalf("handle /project off: %v", err)
+	}
+	if projects.active != "" {
+		t.Fatalf("expected no active project, got %q", projects.active)
+	}
+}
+
+type fakeProjects struct {
+	names  []string
+	active string
+}
+
+func (f *fakeProjects) Projects() []string { return f.names }
+
+func (f *fakeProjects) ActiveProject() (string, error) { return f.active, nil }
+
+func (f *fakeProjects) SetProject(name string) error {
+	f.active = name
+	return nil
+}
+
+type fakePromptBlocks struct {
+	disabled []string
+	usage    agent.PromptUsage
+	coding   bool
+}
+
+func (f *fakePromptBlocks) CodingSession() (bool, error) { return f.coding, nil }
+
+func (f *fakePromptBlocks) SetCodingSession(coding bool) error {
+	f.coding = coding
+	return nil
+}
+
+func (f *fakePromptBlocks) DisabledPromptBlocks() ([]string, error) { return f.disabled, nil }
+
+func (f *fakePromptBlocks) LastPromptUsage() (agent.PromptUsage, bool) {
+	return f.usage, !f.usage.At.IsZero()
+}
+
+func (f *fakePromptBlocks) TogglePromptBlock(block string) (bool, error) {
+	f.disabled = append(f.disabled, block)
+	return false, nil
+}
+
+type fakeForker struct {
+	name    string
+	summary string
+	err     error
+}
+
+func (f *fakeForker) Fork(_ context.Context, name string) (string, error) {
+	f.name = name
+	return name, f.err
+}
+
+func (f *fakeForker) MergeSummary(context.Context) (string, string, error) {
+	if f.err != nil {
+		return "", "", f.err
+	}
+	return f.name, f.summary, nil
+}
+
+type fakeBridge struct {
+	owner    string
+	attached []session.Attachment
+	err      error
+}
+
+func (f *fakeBridge) Attach(_ context.Context, channel string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.owner = channel
+	return nil
+}
+
+func (f *fakeBridge) BridgeChannels() []string {
+	return []string{"cli", "telegram"}
+}
+
+func (f *fakeBridge) Where() (string, []session.Attachment, error) {
+	return f.owner, f.attached, nil
+}
+
+type fakeRewinder struct {
+	n        int
+	removed  int
+	lastKept string
+	err      error
+}
+
+func (f *fakeRewinder) Rewind(_ context.Context, n int) (int, string, error) {
+	f.n = n
+	if f.err != nil {
+		return 0, "", f.err
+	}
+	return f.removed, f.lastKept, nil
+}
+
+type fakeDeleter struct {
+	removed int
+	err     error
+}
+
+func (f *fakeDeleter) DeleteLast(context.Context) (int, error) {
+	return f.removed, f.err
+}
+
+type fakeCorrector struct {
+	text  string
+	topic string
+	err   error
+}
+
+func (f *fakeCorrector) Correct(_ context.Context, text string) (string, error) {
+	f.text = text
+	return f.topic, f.err
+}
+
+type fakeIncognito struct {
+	on bool
+}
+
+func (f *fakeIncognito) SetIncognito(on bool) { f.on = on }
+func (f *fakeIncognito) Incognito() bool      { return f.on }
+
+type fakeDND struct {
+	on       bool
+	duration time.Duration
+}
+
+func (d *fakeDND) SetDND(duration time.Duration) {
+	d.on = true
+	d.duration = duration
+}
+
+func (d *fakeDND) ClearDND() {
+	d.on = false
+}
+
+func (d *fakeDND) Status() string {
+	if d.on {
+		return "on"
+	}
+	return "off"
+}
+
+type fileCaptureWriter struct {
+	captureWriter
+	files []string
+}
+
+func (w *fileCaptureWriter) SendFile(_ context.Context, path, _ string) error {
+	w.files = append(w.files, path)
+	return nil
+}
+
+func TestTodoCommand(t *testing.T) {
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureTasks(todo.New(filepath.Join(t.TempDir(), "tasks.json")))
+
+	w := &captureWriter{}
+	for _, cmd := range []string{"/todo add Call Dr. Lee due 2099-01-02", "/todo add water plants", "/todo done 2", "/todo", "/todo done x"} {
+		if handled, err := h.Handle(context.Background(), cmd, w); err != nil || !handled {
+			t.Fatalf("handle %q: handled=%v err=%v", cmd, handled, err)
+		}
+	}
+	want := []string{
+		"Added 1. Call Dr. Lee (due Fri 2099-01-02)",
+		"Added 2. water plants",
+		"Done: 2. [done] water plants",
+		"Todo:\n1. Call Dr. Lee (due Fri 2099-01-02)",
+		"Usage: /todo | /todo all | /todo add <text> [due YYYY-MM-DD] | /todo done <n>",
+	}
+	if strings.Join(w.messages, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected messages %#v", w.messages)
+	}
+}
+
+func TestListCommand(t *testing.T) {
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureLists(lists.New(filepath.Join(t.TempDir(), "lists.json")))
+
+	w := &captureWriter{}
+	for _, cmd := range []string{"/list add Oat milk, eggs", "/list check 2", "/list hardware add screws", "/list clear", "/list show hardware", "/list remove bread"} {
+		if handled, err := h.Handle(context.Background(), cmd, w); err != nil || !handled {
+			t.Fatalf("handle %q: handled=%v err=%v", cmd, handled, err)
+		}
+	}
+	want := []string{
+		"Shopping list:\n1. ☐ Oat milk\n2. ☐ eggs",
+		"Shopping list:\n1. ☐ Oat milk\n2. ☑ eggs",
+		"Hardware list:\n1. ☐ screws",
+		"Shopping list:\n1. ☐ Oat milk",
+		"Hardware list:\n1. ☐ screws",
+		`Could not update list: "bread" is not on the shopping list`,
+	}
+	if strings.Join(w.messages, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected messages %#v", w.messages)
+	}
+}
+
+func TestRouterLanguageAppliesToSender(t *testing.T) {
+	next := &fakeRuntimeHandler{}
+	handler := New(nil, nil, nil, 0, 0)
+	languages := language.New(filepath.Join(t.TempDir(), "languages.json"))
+	handler.ConfigureLanguages(languages)
+	router := Router{Commands: handler, Next: next}
+
+	send := func(userID, text string) string {
+		t.Helper()
+		w := &captureWriter{}
+		if err := router.HandleMessage(context.Background(), w, &runtime.Message{Text: text, UserID: userID}); err != nil {
+			t.Fatalf("router %s: %v", text, err)
+		}
+		if len(w.messages) != 1 {
+			t.Fatalf("expected one reply to %s, got %#v", text, w.messages)
+		}
+		return w.messages[0]
+	}
+
+	if got := send("1", "/language Spanish"); got != "Replies to you are in Spanish." {
+		t.Fatalf("unexpected reply %q", got)
+	}
+	if got := send("2", "/language"); !strings.HasPrefix(got, "Replies to you will follow the language of your messages.") {
+		t.Fatalf("expected user 2 unaffected, got %q", got)
+	}
+	if got := send("1", "/language off"); !strings.Contains(got, "off") {
+		t.Fatalf("unexpected reply %q", got)
+	}
+	if pref, err := languages.Get("1"); err != nil || pref.Mode != language.ModeOff || pref.Language != "es" {
+		t.Fatalf("unexpected stored preference %+v (%v)", pref, err)
+	}
+	if got := send("1", "/language not a code"); !strings.HasPrefix(got, "Usage:") {
+		t.Fatalf("expected usage, got %q", got)
+	}
+	if next.calls != 0 {
+		t.Fatalf("expected /language not forwarded, got %d calls", next.calls)
+	}
+}
+
+type fakeLastTurner struct {
+	report *session.TurnReport
+}
+
+func (f fakeLastTurner) LastTurn() (*session.TurnReport, error) {
+	return f.report, nil
+}
+
+func TestLastTurnCommand(t *testing.T) {
+	h := New(nil, nil, nil, 0, 0)
+	h.ConfigureLastTurn(fakeLastTurner{})
+	w := &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/last-turn", w); err != nil {
+		t.Fatalf("handle /last-turn: %v", err)
+	}
+	if w.messages[0] != "No turn has run in this session yet." {
+		t.Fatalf("unexpected reply %#v", w.messages)
+	}
+
+	h.ConfigureLastTurn(fakeLastTurner{report: &session.TurnReport{
+		Started:      time.Date(2026, 3, 1, 9, 30, 0, 0, time.Local),
+		DurationMS:   91234,
+		ModelCalls:   3,
+		InputTokens:  12000,
+		OutputTokens: 800,
+		CostUSD:      0.4,
+		Tools:        []session.ToolReport{{Name: "run_command", DurationMS: 62000}, {Name: "web_fetch", DurationMS: 800, Error: "timeout"}},
+		Approvals:    []session.ApprovalReport{{Tool: "run_command", Decision: "approved", WaitMS: 60000}},
+	}})
+	w = &captureWriter{}
+	if _, err := h.Handle(context.Background(), "/last_turn", w); err != nil {
+		t.Fatalf("handle /last_turn: %v", err)
+	}
+	want := "Last turn (Mar 1 09:30): 1m31.2s, 3 model calls, 12000 input + 800 output tokens, $0.4000\n" +
+		"Tools (2):\n- run_command 1m2s\n- web_fetch 800ms (failed: timeout)\n" +
+		"Approvals:\n- run_command approved after 1m0s"
+	if w.messages[0] != want {
+		t.Fatalf("unexpected report:\n%s", w.messages[0])
+	}
+}
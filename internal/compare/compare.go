@@ -0,0 +1,195 @@
+// Package compare runs the same inputs against two personas and lays their
+// replies and tool choices side by side. Tools are never executed: every call
+// gets a placeholder result, so a comparison has no side effects.
+package compare
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/neoclaw-ai/neoclaw/internal/agent"
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/runtime"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+// Persona is one side of a comparison: a SOUL.md and a label for it.
+type Persona struct {
+	Name string
+	Soul string
+}
+
+// Turn is what one persona did with one input.
+type Turn struct {
+	Reply string
+	// Tools lists the tools the model called, in order.
+	Tools []string
+	// Err is set when the turn failed; Reply is then empty.
+	Err string
+}
+
+// Result pairs both personas' turns for one input.
+type Result struct {
+	Input string
+	A, B  Turn
+}
+
+// Runner runs inputs through fresh agents, one per input and persona, so no
+// input sees another's history.
+type Runner struct {
+	Provider provider.Provider
+	// Tools are offered to the model but never executed.
+	Tools []tools.Tool
+	// UserProfile is the USER.md both personas see.
+	UserProfile string
+	Context     config.ContextConfig
+	// Configure, if set, is applied to every agent before it runs, for
+	// example to set tool limits or track costs.
+	Configure func(*agent.Agent)
+}
+
+// ParseInputs reads one input per line. Blank lines and lines starting with
+// # are skipped.
+func ParseInputs(content string) []string {
+	var inputs []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		inputs = append(inputs, line)
+	}
+	return inputs
+}
+
+// Run sends every input to both personas and returns the turns in input
+// order. A failed turn is recorded in its Result; only cancellation and
+// setup failures stop the run.
+func (r Runner) Run(ctx context.Context, a, b Persona, inputs []string) ([]Result, error) {
+	dir, err := os.MkdirTemp("", "claw-compare-")
+	if err != nil {
+		return nil, fmt.Errorf("create compare directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	results := make([]Result, 0, len(inputs))
+	for i, input := range inputs {
+		result := Result{Input: input}
+		result.A, err = r.turn(ctx, filepath.Join(dir, "a", strconv.Itoa(i)), a, input)
+		if err != nil {
+			return nil, err
+		}
+		result.B, err = r.turn(ctx, filepath.Join(dir, "b", strconv.Itoa(i)), b, input)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (r Runner) turn(ctx context.Context, agentDir string, persona Persona, input string) (Turn, error) {
+	if err := store.WriteFile(filepath.Join(agentDir, config.SoulFilePath), []byte(persona.Soul)); err != nil {
+		return Turn{}, fmt.Errorf("write compare SOUL.md: %w", err)
+	}
+	if r.UserProfile != "" {
+		if err := store.WriteFile(filepath.Join(agentDir, config.UserFilePath), []byte(r.UserProfile)); err != nil {
+			return Turn{}, fmt.Errorf("write compare USER.md: %w", err)
+		}
+	}
+	memoryDir := filepath.Join(agentDir, config.MemoryDirPath)
+	if err := os.MkdirAll(memoryDir, 0o755); err != nil {
+		return Turn{}, fmt.Errorf("create compare memory directory: %w", err)
+	}
+	memoryStore, err := memory.New(memoryDir)
+	if err != nil {
+		return Turn{}, err
+	}
+
+	calls := &callRecorder{}
+	registry := tools.NewRegistry()
+	for _, tool := range r.Tools {
+		if err := registry.Register(stubTool{Tool: tool, calls: calls}); err != nil {
+			return Turn{}, fmt.Errorf("register tool %s: %w", tool.Name(), err)
+		}
+	}
+	handler := agent.New(r.Provider, registry, denyApprover{}, agentDir, memoryStore, r.Context)
+	if r.Configure != nil {
+		r.Configure(handler)
+	}
+
+	w := &replyWriter{}
+	err = handler.HandleMessage(ctx, w, &runtime.Message{Text: input})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return Turn{}, ctxErr
+	}
+	turn := Turn{Tools: calls.names()}
+	if err != nil {
+		turn.Err = err.Error()
+		return turn, nil
+	}
+	turn.Reply = strings.Join(w.messages, "\n\n")
+	return turn, nil
+}
+
+// stubTool offers a real tool's name and schema but records calls instead
+// of running them.
+type stubTool struct {
+	tools.Tool
+	calls *callRecorder
+}
+
+func (t stubTool) Permission() tools.Permission {
+	return tools.AutoApprove
+}
+
+func (t stubTool) Execute(_ context.Context, _ map[string]any) (*tools.ToolResult, error) {
+	t.calls.add(t.Name())
+	return &tools.ToolResult{Output: fmt.Sprintf("[%s was not run: this is a comparison, so there is no real result. Answer as well as you can without it.]", t.Name())}, nil
+}
+
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *callRecorder) add(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, name)
+}
+
+func (r *callRecorder) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+// denyApprover is never asked, because stub tools need no approval; it
+// denies anything that slips through.
+type denyApprover struct{}
+
+func (denyApprover) RequestApproval(context.Context, approval.ApprovalRequest) (approval.ApprovalDecision, error) {
+	return approval.Denied, nil
+}
+
+type replyWriter struct {
+	messages []string
+}
+
+func (w *replyWriter) WriteMessage(_ context.Context, text string) error {
+	w.messages = append(w.messages, text)
+	return nil
+}
@@ -0,0 +1,107 @@
+package compare
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+// personaProvider answers tersely when the system prompt says so, and looks
+// things up otherwise.
+type personaProvider struct {
+	mu       sync.Mutex
+	executed bool
+}
+
+func (p *personaProvider) Chat(_ context.Context, req provider.ChatRequest) (*provider.ChatResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	last := req.Messages[len(req.Messages)-1]
+	if strings.Contains(req.SystemPrompt, "Be terse.") {
+		return &provider.ChatResponse{Content: "Paris."}, nil
+	}
+	if last.Role == provider.RoleTool {
+		if !strings.Contains(last.Content, "was not run") {
+			p.executed = true
+		}
+		return &provider.ChatResponse{Content: "The capital of France is Paris.\nIt has been since 987."}, nil
+	}
+	return &provider.ChatResponse{ToolCalls: []provider.ToolCall{{ID: "1", Name: "web_search", Arguments: `{"query":"capital of France"}`}}}, nil
+}
+
+type explodingTool struct{}
+
+func (explodingTool) Name() string                 { return "web_search" }
+func (explodingTool) Description() string          { return "Search the web" }
+func (explodingTool) Schema() map[string]any       { return map[string]any{"type": "object"} }
+func (explodingTool) Permission() tools.Permission { return tools.RequiresApproval }
+func (explodingTool) Execute(context.Context, map[string]any) (*tools.ToolResult, error) {
+	panic("compare must not execute tools")
+}
+
+func TestRunStubsToolsAndRecordsChoices(t *testing.T) {
+	modelProvider := &personaProvider{}
+	runner := Runner{Provider: modelProvider, Tools: []tools.Tool{explodingTool{}}}
+	a := Persona{Name: "terse.md", Soul: "Be terse."}
+	b := Persona{Name: "thorough.md", Soul: "Check your facts."}
+
+	results, err := runner.Run(context.Background(), a, b, ParseInputs("# geography\n\nWhat is the capital of France?\n"))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(results) != 1 || results[0].Input != "What is the capital of France?" {
+		t.Fatalf("unexpected results %#v", results)
+	}
+	if got := results[0].A; got.Reply != "Paris." || len(got.Tools) != 0 {
+		t.Fatalf("unexpected A turn %#v", got)
+	}
+	if got := results[0].B; !strings.HasPrefix(got.Reply, "The capital of France") || len(got.Tools) != 1 || got.Tools[0] != "web_search" {
+		t.Fatalf("unexpected B turn %#v", got)
+	}
+	if modelProvider.executed {
+		t.Fatalf("expected the tool result to be a placeholder")
+	}
+
+	out := Format(a, b, results, 80)
+	for _, want := range []string{
+		"1 input, 1 with different replies, 1 with different tool choices.",
+		"tools: none",
+		"| tools: web_search",
+		"Paris.",
+		"> It has been since 987.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestSideBySideAlignsCommonLines(t *testing.T) {
+	rows := sideBySide([]string{"hello", "old", "bye"}, []string{"hello", "new", "extra", "bye"})
+	want := [][3]string{
+		{"hello", " ", "hello"},
+		{"old", "|", "new"},
+		{"", ">", "extra"},
+		{"bye", " ", "bye"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("unexpected rows %q", rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Fatalf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := wrap("one two three\n\nabcdefghij", 7)
+	want := []string{"one two", "three", "", "abcdefg", "hij"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("wrap = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package compare
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// minColumn keeps columns readable in very narrow terminals.
+const minColumn = 20
+
+// Format renders results as two columns, A on the left and B on the right,
+// width characters wide in total. Rows that differ are marked with | in the
+// gutter, and rows found on only one side with < or >.
+func Format(a, b Persona, results []Result, width int) string {
+	column := max((width-3)/2, minColumn)
+
+	replies, choices := 0, 0
+	for _, result := range results {
+		if result.A.Reply != result.B.Reply || result.A.Err != result.B.Err {
+			replies++
+		}
+		if !slices.Equal(result.A.Tools, result.B.Tools) {
+			choices++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "A: %s\nB: %s\n", a.Name, b.Name)
+	fmt.Fprintf(&out, "%d %s, %d with different replies, %d with different tool choices.\n",
+		len(results), plural(len(results), "input", "inputs"), replies, choices)
+	for i, result := range results {
+		fmt.Fprintf(&out, "\n=== Input %d of %d ===\n%s\n\n", i+1, len(results), result.Input)
+		writeRow(&out, column, "A", " ", "B")
+		writeRow(&out, column, strings.Repeat("-", column), " ", strings.Repeat("-", column))
+		for _, row := range sideBySide(wrap(toolLine(result.A), column), wrap(toolLine(result.B), column)) {
+			writeRow(&out, column, row[0], row[1], row[2])
+		}
+		writeRow(&out, column, "", " ", "")
+		for _, row := range sideBySide(wrap(replyText(result.A), column), wrap(replyText(result.B), column)) {
+			writeRow(&out, column, row[0], row[1], row[2])
+		}
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
+
+func toolLine(turn Turn) string {
+	if len(turn.Tools) == 0 {
+		return "tools: none"
+	}
+	return "tools: " + strings.Join(turn.Tools, ", ")
+}
+
+func replyText(turn Turn) string {
+	if turn.Err != "" {
+		return "[error: " + turn.Err + "]"
+	}
+	return turn.Reply
+}
+
+func writeRow(out *strings.Builder, column int, left, gutter, right string) {
+	line := fmt.Sprintf("%-*s %s %s", column, left, gutter, right)
+	out.WriteString(strings.TrimRight(line, " "))
+	out.WriteByte('\n')
+}
+
+// sideBySide aligns two line lists on their longest common subsequence and
+// returns rows of left line, gutter mark, and right line.
+func sideBySide(left, right []string) [][3]string {
+	n, m := len(left), len(right)
+	common := make([][]int, n+1)
+	for i := range common {
+		common[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if left[i] == right[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var rows [][3]string
+	var onlyLeft, onlyRight []string
+	flush := func() {
+		for k := range max(len(onlyLeft), len(onlyRight)) {
+			var row [3]string
+			switch {
+			case k < len(onlyLeft) && k < len(onlyRight):
+				row = [3]string{onlyLeft[k], "|", onlyRight[k]}
+			case k < len(onlyLeft):
+				row = [3]string{onlyLeft[k], "<", ""}
+			default:
+				row = [3]string{"", ">", onlyRight[k]}
+			}
+			rows = append(rows, row)
+		}
+		onlyLeft, onlyRight = nil, nil
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && left[i] == right[j]:
+			flush()
+			rows = append(rows, [3]string{left[i], " ", right[j]})
+			i++
+			j++
+		case j >= m || (i < n && common[i+1][j] >= common[i][j+1]):
+			onlyLeft = append(onlyLeft, left[i])
+			i++
+		default:
+			onlyRight = append(onlyRight, right[j])
+			j++
+		}
+	}
+	flush()
+	return rows
+}
+
+// wrap breaks text into lines of at most width characters at spaces, and
+// inside words longer than width. Line breaks in text are kept.
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func plural(count int, singular, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -6,17 +6,46 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/go-viper/mapstructure/v2"
+	"github.com/neoclaw-ai/neoclaw/internal/redact"
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
 )
 
 const defaultAgent = "default"
 
+const (
+	// TelegramChannelName is the first Telegram bot's [channels.telegram] entry.
+	TelegramChannelName = "telegram"
+	// SlackChannelName is the [channels.slack] entry.
+	SlackChannelName = "slack"
+	// MatrixChannelName is the [channels.matrix] entry.
+	MatrixChannelName = "matrix"
+	// HTTPChannelName is the [channels.http] entry.
+	HTTPChannelName = "http"
+	// WebChannelName is the [channels.web] entry, also enabled by serve --web.
+	WebChannelName = "web"
+	// WhatsAppChannelName is the [channels.whatsapp] entry.
+	WhatsAppChannelName = "whatsapp"
+	// telegramChannelPrefix names further bots: [channels.telegram_work].
+	telegramChannelPrefix = "telegram_"
+	// defaultWebhookListen matches channels.DefaultTelegramWebhookListen.
+	defaultWebhookListen = ":8443"
+)
+
+// namePattern limits agent and bot names to ones safe as directory names.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
 const (
 	// SecurityModeStandard is the default sandbox/security behavior.
 	SecurityModeStandard = "standard"
@@ -26,33 +55,189 @@ const (
 	SecurityModeStrict = "strict"
 )
 
+const (
+	// UnlistedBinsAllow leaves programs missing from allowed_bins.json to the
+	// command policy.
+	UnlistedBinsAllow = "allow"
+	// UnlistedBinsPrompt asks before running them, even when an allow pattern
+	// matches the command.
+	UnlistedBinsPrompt = "prompt"
+	// UnlistedBinsDeny refuses them outright.
+	UnlistedBinsDeny = "deny"
+)
+
+const (
+	// InlineScriptsPolicy matches interpreter one-liners such as python -c
+	// against the command policy like any other command.
+	InlineScriptsPolicy = "policy"
+	// InlineScriptsPrompt always asks before running them, showing the
+	// script, and never remembers the answer.
+	InlineScriptsPrompt = "prompt"
+	// InlineScriptsSandbox asks like InlineScriptsPrompt and then runs them
+	// in a per-command sandbox confined to the workspace.
+	InlineScriptsSandbox = "sandbox"
+)
+
+const (
+	// TrustUser marks tool output the user wrote or keeps, such as memory
+	// facts and todos.
+	TrustUser = "user"
+	// TrustWorkspace marks local files and command output, which may have
+	// come from anyone.
+	TrustWorkspace = "workspace"
+	// TrustInternet marks content fetched from the web.
+	TrustInternet = "internet"
+)
+
+const (
+	// ResponseFormatText delivers agent replies as free text.
+	ResponseFormatText = "text"
+	// ResponseFormatJSON constrains agent replies to validated JSON.
+	ResponseFormatJSON = "json"
+)
+
+const (
+	// MemoryWritesAuto lets the agent write memory and daily logs freely.
+	MemoryWritesAuto = "auto"
+	// MemoryWritesApprove asks for approval before each memory write.
+	MemoryWritesApprove = "approve"
+	// MemoryWritesQueue holds memory writes for review with /memory pending.
+	MemoryWritesQueue = "queue"
+)
+
+const (
+	// StorageBackendLocal keeps agent state on local disk only.
+	StorageBackendLocal = "local"
+	// StorageBackendS3 mirrors agent state to an S3-compatible bucket.
+	StorageBackendS3 = "s3"
+	// StorageBackendWebDAV mirrors agent state to a WebDAV collection.
+	StorageBackendWebDAV = "webdav"
+)
+
 // Config is the runtime configuration loaded from defaults, config.toml, and env vars.
 type Config struct {
 	// HomeDir is runtime-resolved from NEOCLAW_HOME and not read from config.
 	HomeDir string `mapstructure:"-"`
 	// Agent is runtime-selected (MVP default: "default"), not read from config.
-	Agent    string                       `mapstructure:"-"`
-	Channels map[string]ChannelConfig     `mapstructure:"channels"`
-	LLM      map[string]LLMProviderConfig `mapstructure:"llm"`
-	Security SecurityConfig               `mapstructure:"security"`
-	Costs    CostsConfig                  `mapstructure:"costs"`
-	Context  ContextConfig                `mapstructure:"context"`
-	Web      WebConfig                    `mapstructure:"web"`
+	Agent string `mapstructure:"-"`
+	// LowMemory trades speed for memory on small hosts such as a Raspberry
+	// Pi Zero: daily logs are read from disk instead of cached, queues are
+	// shorter, and [context] defaults are smaller.
+	LowMemory     bool                         `mapstructure:"low_memory"`
+	Channels      map[string]ChannelConfig     `mapstructure:"channels"`
+	CLI           CLIConfig                    `mapstructure:"cli"`
+	LLM           map[string]LLMProviderConfig `mapstructure:"llm"`
+	Security      SecurityConfig               `mapstructure:"security"`
+	Costs         CostsConfig                  `mapstructure:"costs"`
+	Context       ContextConfig                `mapstructure:"context"`
+	Web           WebConfig                    `mapstructure:"web"`
+	Proactive     ProactiveConfig              `mapstructure:"proactive"`
+	Notifications NotificationsConfig          `mapstructure:"notifications"`
+	Workspace     WorkspaceConfig              `mapstructure:"workspace"`
+	Privacy       PrivacyConfig                `mapstructure:"privacy"`
+	Memory        MemoryConfig                 `mapstructure:"memory"`
+	Storage       StorageConfig                `mapstructure:"storage"`
+	Transcription TranscriptionConfig          `mapstructure:"transcription"`
+	Translation   TranslationConfig            `mapstructure:"translation"`
+	Embeddings    EmbeddingsConfig             `mapstructure:"embeddings"`
+	Rerank        RerankConfig                 `mapstructure:"rerank"`
+	Update        UpdateConfig                 `mapstructure:"update"`
+	Telemetry     TelemetryConfig              `mapstructure:"telemetry"`
+	// Projects are named workspace subdirectories selected with /project.
+	Projects map[string]ProjectConfig `mapstructure:"projects"`
 }
 
 // ChannelConfig configures one inbound/outbound channel.
 type ChannelConfig struct {
 	Enabled bool   `mapstructure:"enabled"`
 	Token   string `mapstructure:"token"`
+	// AppToken is the Slack app-level token (xapp-) that opens the Socket
+	// Mode connection. Token is then the bot token (xoxb-).
+	AppToken string `mapstructure:"app_token"`
+	// Homeserver is the Matrix client-server API base URL. Token is then
+	// the bot account's access token.
+	Homeserver string `mapstructure:"homeserver"`
+	// APIKeys are the bearer tokens the HTTP API accepts, one per client,
+	// and Listen is the address it serves on.
+	APIKeys []string `mapstructure:"api_keys"`
+	Listen  string   `mapstructure:"listen"`
+	// PhoneNumberID is the WhatsApp Cloud API sender; Token is then its
+	// access token. AppSecret checks webhook signatures, VerifyToken answers
+	// Meta's webhook verification, and Template (in TemplateLanguage) is the
+	// approved template sent outside the 24-hour customer service window.
+	PhoneNumberID    string `mapstructure:"phone_number_id"`
+	AppSecret        string `mapstructure:"app_secret"`
+	VerifyToken      string `mapstructure:"verify_token"`
+	Template         string `mapstructure:"template"`
+	TemplateLanguage string `mapstructure:"template_language"`
+	// ResponseFormat is "text" (default) or "json". In json mode every agent
+	// reply on the channel is a JSON value, validated before delivery.
+	ResponseFormat string `mapstructure:"response_format"`
+	// ResponseSchema is an optional JSON Schema file that json replies must match.
+	ResponseSchema string `mapstructure:"response_schema"`
+	// StripReasoning, PostprocessCommand, MaxReplyLength, and Signature
+	// rewrite text replies before they are sent, in that order.
+	StripReasoning     bool   `mapstructure:"strip_reasoning"`
+	PostprocessCommand string `mapstructure:"postprocess_command"`
+	MaxReplyLength     int    `mapstructure:"max_reply_length"`
+	Signature          string `mapstructure:"signature"`
+	// WebhookURL switches Telegram from long polling to webhook delivery at
+	// this public HTTPS URL. WebhookListen is the local address to serve it
+	// on. WebhookCertFile and WebhookKeyFile serve HTTPS directly; without
+	// them a reverse proxy is expected to terminate TLS.
+	WebhookURL      string `mapstructure:"webhook_url"`
+	WebhookListen   string `mapstructure:"webhook_listen"`
+	WebhookCertFile string `mapstructure:"webhook_cert_file"`
+	WebhookKeyFile  string `mapstructure:"webhook_key_file"`
+	// Agent is the agent a Telegram bot serves; empty serves the default
+	// agent. Each bot needs its own agent so sessions and memory stay apart.
+	Agent string `mapstructure:"agent"`
 }
 
-// LLMProviderConfig configures one LLM provider profile.
+// CLIConfig controls alerts in interactive claw cli sessions, so the
+// terminal can be left while a turn runs.
+type CLIConfig struct {
+	// Bell rings the terminal bell when a turn finishes or needs approval.
+	Bell bool `mapstructure:"bell"`
+	// DesktopNotify also shows a desktop notification, via notify-send on
+	// Linux and osascript on macOS.
+	DesktopNotify bool `mapstructure:"desktop_notify"`
+	// NotifyAfter skips the finished-turn alert for turns shorter than
+	// this. Approval prompts always alert.
+	NotifyAfter time.Duration `mapstructure:"notify_after"`
+}
+
+// LLMProviderConfig configures one LLM provider profile. AuthToken is sent
+// as a bearer token instead of APIKey, and OAuth signs in with a Claude
+// subscription instead of either (anthropic only). Fallback names another
+// llm profile used while this one is failing.
 type LLMProviderConfig struct {
 	APIKey         string        `mapstructure:"api_key"`
+	AuthToken      string        `mapstructure:"auth_token"`
+	OAuth          OAuthConfig   `mapstructure:"oauth"`
 	Provider       string        `mapstructure:"provider"`
 	Model          string        `mapstructure:"model"`
 	MaxTokens      int           `mapstructure:"max_tokens"`
 	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	Fallback       string        `mapstructure:"fallback"`
+}
+
+// OAuthConfig signs a provider profile in with the OAuth 2.0 device flow
+// (RFC 8628). `claw login` gets the first token; the provider refreshes it.
+type OAuthConfig struct {
+	ClientID               string `mapstructure:"client_id"`
+	DeviceAuthorizationURL string `mapstructure:"device_authorization_url"`
+	TokenURL               string `mapstructure:"token_url"`
+	Scope                  string `mapstructure:"scope"`
+	// SecretsDir and SecretName are derived from the home directory and the
+	// profile name and are not configurable. The token is kept there.
+	SecretsDir string `mapstructure:"-"`
+	SecretName string `mapstructure:"-"`
+}
+
+// Enabled reports whether the profile signs in with OAuth.
+func (c OAuthConfig) Enabled() bool {
+	return strings.TrimSpace(c.ClientID) != ""
 }
 
 // SecurityConfig controls command execution and sandbox behavior.
@@ -61,6 +246,26 @@ type SecurityConfig struct {
 	Workspace      string        `mapstructure:"-"`
 	CommandTimeout time.Duration `mapstructure:"command_timeout"`
 	Mode           string        `mapstructure:"mode"`
+	// UnlistedBins is a UnlistedBins* value for programs missing from
+	// allowed_bins.json; empty means allow.
+	UnlistedBins string `mapstructure:"unlisted_bins"`
+	// InlineScripts is an InlineScripts* value for python -c, node -e, sh -c
+	// and similar payloads; empty means prompt.
+	InlineScripts string `mapstructure:"inline_scripts"`
+	// ApprovalTimeout expires unanswered Telegram approval prompts and
+	// refuses the action; 0 waits indefinitely.
+	ApprovalTimeout time.Duration `mapstructure:"approval_timeout"`
+	// CodeReview names an llm profile that checks code from write_file and
+	// apply_patch before the approval prompt; empty disables the review.
+	CodeReview string `mapstructure:"code_review"`
+	// Canaries plants fake credentials in the workspace and raises an
+	// alert when a tool call touches them.
+	Canaries bool `mapstructure:"canaries"`
+	// PolicyAlerts is a scheduler channel ID, e.g. telegram-123456789, told
+	// about every change to a policy file; empty disables the alerts.
+	PolicyAlerts string `mapstructure:"policy_alerts"`
+	// Trust overrides the Trust* level of named tools' output.
+	Trust map[string]string `mapstructure:"trust"`
 }
 
 // CostsConfig defines soft USD spending limits.
@@ -76,6 +281,136 @@ type ContextConfig struct {
 	MaxToolCalls         int `mapstructure:"max_tool_calls"`
 	ToolOutputLength     int `mapstructure:"tool_output_length"`
 	DailyLogLookbackDays int `mapstructure:"daily_log_lookback_days"`
+	// MaxTurnTokens and MaxTurnDuration cap a single turn; 0 disables each limit.
+	MaxTurnTokens   int           `mapstructure:"max_turn_tokens"`
+	MaxTurnDuration time.Duration `mapstructure:"max_turn_duration"`
+	// ProgressUpdateAfter sends a "still working" update once a turn runs this long; 0 disables it.
+	ProgressUpdateAfter time.Duration `mapstructure:"progress_update_after"`
+	// LazyTools advertises only CoreTools plus a load_tools tool that adds
+	// the others on demand, instead of every tool schema in every request.
+	LazyTools bool     `mapstructure:"lazy_tools"`
+	CoreTools []string `mapstructure:"core_tools"`
+	// WorkspaceSummary adds a snapshot of the workspace to the system prompt
+	// of sessions flagged as coding sessions.
+	WorkspaceSummary WorkspaceSummaryConfig `mapstructure:"workspace_summary"`
+}
+
+// WorkspaceSummaryConfig controls the workspace snapshot: its top-level
+// entries and the git branch and status of the repositories in it.
+type WorkspaceSummaryConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxEntries caps the top-level entries listed.
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// ProactiveConfig configures opt-in agent-initiated check-in messages.
+type ProactiveConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Schedule is the cron expression for check-in evaluations.
+	Schedule  string `mapstructure:"schedule"`
+	MaxPerDay int    `mapstructure:"max_per_day"`
+	// Channel is the scheduler channel ID to deliver to; empty picks the
+	// default channel. Telegram channels follow the user's presence when
+	// notifications.follow_presence is set.
+	Channel string `mapstructure:"channel"`
+}
+
+// NotificationsConfig controls delivery of unprompted messages such as
+// scheduled job output and proactive check-ins.
+type NotificationsConfig struct {
+	// QuietHours is a local-time window (HH:MM-HH:MM) during which unprompted
+	// messages are held and delivered afterwards.
+	QuietHours string `mapstructure:"quiet_hours"`
+	// FollowPresence delivers a Telegram user's unprompted messages to the
+	// bot they last wrote to instead of the one the job was scheduled on.
+	FollowPresence bool `mapstructure:"follow_presence"`
+}
+
+// WorkspaceConfig controls retention of scratch files under workspace/tmp.
+type WorkspaceConfig struct {
+	// TmpMaxAge removes tmp files older than this; 0 disables age-based cleanup.
+	TmpMaxAge time.Duration `mapstructure:"tmp_max_age"`
+	// TmpMaxSizeMB removes the oldest tmp files once the directory exceeds this; 0 disables it.
+	TmpMaxSizeMB int `mapstructure:"tmp_max_size_mb"`
+	// CleanupSchedule is the cron expression for the cleanup job; empty disables it.
+	CleanupSchedule string `mapstructure:"cleanup_schedule"`
+	// TrashRetention purges deleted files from the trash after this long; 0 keeps them.
+	TrashRetention time.Duration `mapstructure:"trash_retention"`
+}
+
+// ProjectConfig is one [projects.<name>] entry: a subdirectory of the
+// workspace with its own context and commands.
+type ProjectConfig struct {
+	// Path is the project directory, relative to the workspace.
+	Path string `mapstructure:"path"`
+	// Pins are files, relative to Path, included in every turn.
+	Pins []string `mapstructure:"pins"`
+	// Instructions are added to the system prompt while the project is active.
+	Instructions string `mapstructure:"instructions"`
+	// AllowedCommands are run_command patterns approved without asking while
+	// the project is active, in the allowed_commands.json format.
+	AllowedCommands []string `mapstructure:"allowed_commands"`
+}
+
+// StorageConfig mirrors files under the data directory to an off-machine
+// backend. Local disk stays the working copy.
+type StorageConfig struct {
+	// Backend is local (default), s3, or webdav.
+	Backend string              `mapstructure:"backend"`
+	S3      S3StorageConfig     `mapstructure:"s3"`
+	WebDAV  WebDAVStorageConfig `mapstructure:"webdav"`
+}
+
+// S3StorageConfig configures an S3-compatible bucket.
+type S3StorageConfig struct {
+	// Endpoint defaults to AWS S3 in Region.
+	Endpoint        string `mapstructure:"endpoint"`
+	Region          string `mapstructure:"region"`
+	Bucket          string `mapstructure:"bucket"`
+	Prefix          string `mapstructure:"prefix"`
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+}
+
+// WebDAVStorageConfig configures a WebDAV collection.
+type WebDAVStorageConfig struct {
+	URL      string `mapstructure:"url"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// MemoryConfig configures daily log housekeeping and digests.
+type MemoryConfig struct {
+	// RetentionSchedule is the cron expression for the retention job; empty disables it.
+	RetentionSchedule string `mapstructure:"retention_schedule"`
+	// DigestSchedule is the cron expression for the weekly and monthly digest
+	// job; empty disables it.
+	DigestSchedule string `mapstructure:"digest_schedule"`
+	// Tags holds per-tag rules, keyed by the first tag of a daily log entry.
+	Tags map[string]MemoryTagConfig `mapstructure:"tags"`
+}
+
+// MemoryTagConfig sets retention and prompt inclusion for one daily log tag.
+type MemoryTagConfig struct {
+	// RetentionDays deletes matching entries older than this many days; 0 keeps them.
+	RetentionDays int `mapstructure:"retention_days"`
+	// ExcludeFromPrompt keeps matching entries out of the system prompt. They
+	// can still be found with search_logs.
+	ExcludeFromPrompt bool `mapstructure:"exclude_from_prompt"`
+}
+
+// PrivacyConfig controls redaction of personal data before it is persisted.
+type PrivacyConfig struct {
+	// Redact masks matches in session history and daily logs before writing.
+	Redact bool `mapstructure:"redact"`
+	// RedactKinds selects built-in patterns: email, card, phone.
+	RedactKinds []string `mapstructure:"redact_kinds"`
+	// RedactPatterns are extra regular expressions to mask.
+	RedactPatterns []string `mapstructure:"redact_patterns"`
+	// OutboundSecrets handles chat replies containing credentials: redact, block, or off.
+	OutboundSecrets string `mapstructure:"outbound_secrets"`
+	// MemoryWrites controls agent writes to memory and daily logs: auto, approve, or queue.
+	MemoryWrites string `mapstructure:"memory_writes"`
 }
 
 // WebConfig configures built-in web tool behavior.
@@ -89,13 +424,88 @@ type WebSearchConfig struct {
 	APIKey   string `mapstructure:"api_key"`
 }
 
+// TranscriptionConfig configures the speech-to-text API used by
+// transcribe_audio. Any OpenAI-compatible /audio/transcriptions endpoint works.
+type TranscriptionConfig struct {
+	Endpoint string `mapstructure:"endpoint"`
+	APIKey   string `mapstructure:"api_key"`
+	Model    string `mapstructure:"model"`
+	// ChunkDuration is how long each uploaded piece of a recording is; zero
+	// sends recordings whole.
+	ChunkDuration time.Duration `mapstructure:"chunk_duration"`
+}
+
+// TranslationConfig configures the translation service used by translate.
+type TranslationConfig struct {
+	// Provider is "libretranslate" or "deepl"; empty disables translate.
+	Provider string `mapstructure:"provider"`
+	// Endpoint overrides the provider's public API, e.g. a self-hosted
+	// LibreTranslate.
+	Endpoint string `mapstructure:"endpoint"`
+	APIKey   string `mapstructure:"api_key"`
+}
+
+// EmbeddingsConfig configures the text embeddings used by memory_search,
+// independently of the chat provider.
+type EmbeddingsConfig struct {
+	// Provider is "lexical" (the default when empty), "openai", "voyage",
+	// or "ollama". Only the model-backed providers match by meaning.
+	Provider string `mapstructure:"provider"`
+	// Endpoint overrides the provider's default API URL, e.g. any
+	// OpenAI-compatible /embeddings endpoint or a remote Ollama.
+	Endpoint string `mapstructure:"endpoint"`
+	APIKey   string `mapstructure:"api_key"`
+	// Model defaults to a small model for the provider.
+	Model string `mapstructure:"model"`
+	// Dimensions sizes lexical vectors; zero uses 256. The other providers
+	// ignore it.
+	Dimensions int `mapstructure:"dimensions"`
+}
+
+// RerankConfig configures the optional second pass that reorders semantic
+// search matches by relevance to the query.
+type RerankConfig struct {
+	// Provider is "local", "voyage", or "cohere"; empty disables reranking.
+	Provider string `mapstructure:"provider"`
+	// Endpoint overrides the provider's rerank API URL, e.g. a
+	// Cohere-compatible service such as Jina.
+	Endpoint string `mapstructure:"endpoint"`
+	APIKey   string `mapstructure:"api_key"`
+	Model    string `mapstructure:"model"`
+	// Candidates caps how many of the closest embedding matches are
+	// reranked.
+	Candidates int `mapstructure:"candidates"`
+}
+
+// UpdateConfig controls checks for new NeoClaw releases. claw update works
+// either way.
+type UpdateConfig struct {
+	// Check lets claw status look up the latest release, at most once a day.
+	Check bool `mapstructure:"check"`
+	// Notify also mentions an available update in the proactive check-in.
+	Notify bool `mapstructure:"notify"`
+}
+
+// TelemetryConfig says where opt-in usage reports go. Whether they are
+// collected at all is set with claw telemetry on|off, not here.
+type TelemetryConfig struct {
+	// Endpoint receives a JSON report once a day; empty keeps counts local.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
 var defaultConfig = Config{
 	Channels: map[string]ChannelConfig{
 		"telegram": {
-			Enabled: true,
-			Token:   "",
+			Enabled:        true,
+			Token:          "",
+			ResponseFormat: ResponseFormatText,
 		},
 	},
+	CLI: CLIConfig{
+		Bell:          true,
+		DesktopNotify: false,
+		NotifyAfter:   10 * time.Second,
+	},
 	LLM: map[string]LLMProviderConfig{
 		"default": {
 			APIKey:         "",
@@ -108,6 +518,8 @@ var defaultConfig = Config{
 	Security: SecurityConfig{
 		CommandTimeout: 5 * time.Minute,
 		Mode:           SecurityModeStandard,
+		UnlistedBins:   UnlistedBinsAllow,
+		InlineScripts:  InlineScriptsPrompt,
 	},
 	Costs: CostsConfig{
 		DailyLimit:   0,
@@ -119,6 +531,18 @@ var defaultConfig = Config{
 		MaxToolCalls:         15,
 		ToolOutputLength:     12000,
 		DailyLogLookbackDays: 2,
+		MaxTurnTokens:        0,
+		MaxTurnDuration:      0,
+		ProgressUpdateAfter:  time.Minute,
+		LazyTools:            false,
+		CoreTools: []string{
+			"read_file", "list_dir", "write_file", "apply_patch", "run_command",
+			"web_search", "http_request", "memory_append", "search_logs", "job_create",
+		},
+		WorkspaceSummary: WorkspaceSummaryConfig{
+			Enabled:    true,
+			MaxEntries: 40,
+		},
 	},
 	Web: WebConfig{
 		Search: WebSearchConfig{
@@ -126,6 +550,65 @@ var defaultConfig = Config{
 			APIKey:   "",
 		},
 	},
+	Proactive: ProactiveConfig{
+		Enabled:   false,
+		Schedule:  "0 10,15,19 * * *",
+		MaxPerDay: 2,
+		Channel:   "",
+	},
+	Notifications: NotificationsConfig{
+		QuietHours:     "",
+		FollowPresence: true,
+	},
+	Workspace: WorkspaceConfig{
+		TmpMaxAge:       7 * 24 * time.Hour,
+		TmpMaxSizeMB:    500,
+		CleanupSchedule: "30 3 * * *",
+		TrashRetention:  30 * 24 * time.Hour,
+	},
+	Privacy: PrivacyConfig{
+		Redact:          false,
+		RedactKinds:     []string{redact.KindEmail, redact.KindCard, redact.KindPhone},
+		RedactPatterns:  []string{},
+		OutboundSecrets: redact.SecretsRedact,
+		MemoryWrites:    MemoryWritesAuto,
+	},
+	Memory: MemoryConfig{
+		RetentionSchedule: "45 3 * * *",
+		DigestSchedule:    "30 4 * * *",
+		Tags:              map[string]MemoryTagConfig{},
+	},
+	Storage: StorageConfig{
+		Backend: StorageBackendLocal,
+		S3: S3StorageConfig{
+			Region: "us-east-1",
+			Prefix: "neoclaw/",
+		},
+	},
+	Transcription: TranscriptionConfig{
+		Endpoint:      "https://api.openai.com/v1/audio/transcriptions",
+		Model:         "whisper-1",
+		ChunkDuration: 10 * time.Minute,
+	},
+	Embeddings: EmbeddingsConfig{
+		Provider:   "lexical",
+		Dimensions: 256,
+	},
+	Rerank: RerankConfig{
+		Candidates: 30,
+	},
+	Update: UpdateConfig{
+		Check: true,
+	},
+}
+
+// lowMemoryContext replaces the [context] defaults when low_memory is on.
+// Values set in config.toml still win.
+var lowMemoryContext = ContextConfig{
+	MaxTokens:            6000,
+	RecentMessages:       8,
+	ToolOutputLength:     6000,
+	DailyLogLookbackDays: 1,
 }
 
 // defaultUserConfig is the minimal bootstrap config written for first-time
@@ -156,6 +639,45 @@ var defaultUserConfig = Config{
 	},
 }
 
+// EnvPrefix starts the environment variables that override config keys
+// when env overrides are on: llm.default.api_key is read from
+// NEOCLAW_LLM_DEFAULT_API_KEY.
+const EnvPrefix = "NEOCLAW"
+
+// envOverrides is set by EnableEnvOverrides for the rest of the process.
+var envOverrides bool
+
+// EnableEnvOverrides makes Load and Write read NEOCLAW_* environment
+// variables on top of config.toml, for containers configured entirely from
+// their environment. Only keys with a default can be overridden, so extra
+// [llm.*] profiles still need config.toml.
+func EnableEnvOverrides() {
+	envOverrides = true
+}
+
+// EnvOverridesEnabled reports whether EnableEnvOverrides was called.
+func EnvOverridesEnabled() bool {
+	return envOverrides
+}
+
+func applyEnvOverrides(v *viper.Viper) {
+	if !envOverrides {
+		return
+	}
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+}
+
+// envOverridden reports whether an env override sets key.
+func envOverridden(key string) bool {
+	if !envOverrides {
+		return false
+	}
+	_, ok := os.LookupEnv(EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+	return ok
+}
+
 // homeDir returns the NeoClaw home directory.
 // Uses NEOCLAW_HOME env var if set, otherwise defaults to ~/.neoclaw.
 func homeDir() (string, error) {
@@ -180,6 +702,7 @@ func Load() (*Config, error) {
 
 	v := viper.New()
 	setDefaults(v)
+	applyEnvOverrides(v)
 	v.SetConfigFile(homeConfigPath(homeDir))
 	v.SetConfigType("toml")
 
@@ -189,6 +712,10 @@ func Load() (*Config, error) {
 			return nil, fmt.Errorf("read config file: %w", err)
 		}
 	}
+	if err := nestedTelegramBots(v); err != nil {
+		return nil, err
+	}
+	setTelegramBotDefaults(v)
 
 	var cfg Config
 	decodeHook := mapstructure.ComposeDecodeHookFunc(
@@ -203,10 +730,18 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("decode config: %w", err)
 	}
 
+	applyLowMemoryDefaults(&cfg, func(key string) bool {
+		return v.InConfig(key) || envOverridden(key)
+	})
 	applyZeroValueDefaults(&cfg)
 	cfg.HomeDir = homeDir
 	cfg.Agent = defaultAgent
 	cfg.Security.Workspace = cfg.WorkspaceDir()
+	for name, llm := range cfg.LLM {
+		llm.OAuth.SecretsDir = cfg.SecretsDir()
+		llm.OAuth.SecretName = "oauth-" + name
+		cfg.LLM[name] = llm
+	}
 
 	return &cfg, nil
 }
@@ -214,6 +749,28 @@ func Load() (*Config, error) {
 // Write writes the merged configuration (defaults overlaid by user
 // config) to w in TOML format.
 func Write(w io.Writer) error {
+	return write(w, false)
+}
+
+// WriteRedacted is Write with API keys, tokens, and passwords replaced, for
+// crash reports and anything else that may be shared.
+func WriteRedacted(w io.Writer) error {
+	return write(w, true)
+}
+
+// secretKeys name the config keys, in any table, that hold credentials.
+var secretKeys = map[string]bool{
+	"api_key":           true,
+	"auth_token":        true,
+	"app_token":         true,
+	"api_keys":          true,
+	"token":             true,
+	"access_key_id":     true,
+	"secret_access_key": true,
+	"password":          true,
+}
+
+func write(w io.Writer, redactSecrets bool) error {
 	if w == nil {
 		return errors.New("writer is required")
 	}
@@ -225,6 +782,7 @@ func Write(w io.Writer) error {
 
 	v := viper.New()
 	setDefaults(v)
+	applyEnvOverrides(v)
 	v.SetConfigFile(homeConfigPath(homeDir))
 	v.SetConfigType("toml")
 
@@ -234,10 +792,29 @@ func Write(w io.Writer) error {
 			return fmt.Errorf("read config file: %w", err)
 		}
 	}
+	if err := nestedTelegramBots(v); err != nil {
+		return err
+	}
+	setTelegramBotDefaults(v)
 
 	// Keep duration fields human-readable in generated TOML.
+	v.Set("cli.notify_after", v.GetDuration("cli.notify_after").String())
 	v.Set("llm.default.request_timeout", v.GetDuration("llm.default.request_timeout").String())
 	v.Set("security.command_timeout", v.GetDuration("security.command_timeout").String())
+	v.Set("security.approval_timeout", v.GetDuration("security.approval_timeout").String())
+	v.Set("context.max_turn_duration", v.GetDuration("context.max_turn_duration").String())
+	v.Set("context.progress_update_after", v.GetDuration("context.progress_update_after").String())
+	v.Set("workspace.tmp_max_age", v.GetDuration("workspace.tmp_max_age").String())
+	v.Set("workspace.trash_retention", v.GetDuration("workspace.trash_retention").String())
+	v.Set("transcription.chunk_duration", v.GetDuration("transcription.chunk_duration").String())
+
+	if redactSecrets {
+		for _, key := range v.AllKeys() {
+			if secretKeys[key[strings.LastIndex(key, ".")+1:]] && (v.GetString(key) != "" || len(v.GetStringSlice(key)) > 0) {
+				v.Set(key, "[redacted]")
+			}
+		}
+	}
 
 	if err := v.WriteConfigTo(w); err != nil {
 		return fmt.Errorf("write config: %w", err)
@@ -272,9 +849,58 @@ func DefaultUserConfigTOML() (string, error) {
 	return out.String(), nil
 }
 
+// nestedTelegramBots moves bots written as nested tables, such as
+// [channels.telegram.work], to their [channels.telegram_work] name, and
+// refuses tables nested in any other channel, which would otherwise be
+// ignored without a word.
+func nestedTelegramBots(v *viper.Viper) error {
+	tables := map[string]bool{}
+	for _, key := range v.AllKeys() {
+		// channels.<channel>.<table>.<key>
+		parts := strings.Split(key, ".")
+		if len(parts) < 4 || parts[0] != "channels" {
+			continue
+		}
+		if parts[1] != TelegramChannelName {
+			return fmt.Errorf("channels.%s.%s: unexpected table in a channel", parts[1], parts[2])
+		}
+		tables[parts[2]] = true
+	}
+	for table := range tables {
+		name := telegramChannelPrefix + table
+		if v.IsSet("channels." + name) {
+			return fmt.Errorf("channels.%s.%s: bot is also configured as [channels.%s]", TelegramChannelName, table, name)
+		}
+		for key, value := range v.GetStringMap("channels." + TelegramChannelName + "." + table) {
+			v.Set("channels."+name+"."+key, value)
+		}
+	}
+	return nil
+}
+
+// setTelegramBotDefaults gives every [channels.telegram_*] bot in the
+// config file the same defaults as [channels.telegram].
+func setTelegramBotDefaults(v *viper.Viper) {
+	for _, key := range v.AllKeys() {
+		parts := strings.Split(key, ".")
+		if len(parts) < 3 || parts[0] != "channels" || !strings.HasPrefix(parts[1], telegramChannelPrefix) {
+			continue
+		}
+		v.SetDefault("channels."+parts[1]+".enabled", defaultConfig.Channels["telegram"].Enabled)
+		v.SetDefault("channels."+parts[1]+".response_format", defaultConfig.Channels["telegram"].ResponseFormat)
+	}
+}
+
 func setDefaults(v *viper.Viper) {
+	v.SetDefault("low_memory", defaultConfig.LowMemory)
+
 	v.SetDefault("channels.telegram.enabled", defaultConfig.Channels["telegram"].Enabled)
 	v.SetDefault("channels.telegram.token", defaultConfig.Channels["telegram"].Token)
+	v.SetDefault("channels.telegram.response_format", defaultConfig.Channels["telegram"].ResponseFormat)
+
+	v.SetDefault("cli.bell", defaultConfig.CLI.Bell)
+	v.SetDefault("cli.desktop_notify", defaultConfig.CLI.DesktopNotify)
+	v.SetDefault("cli.notify_after", defaultConfig.CLI.NotifyAfter)
 
 	v.SetDefault("llm.default.api_key", defaultConfig.LLM["default"].APIKey)
 	v.SetDefault("llm.default.provider", defaultConfig.LLM["default"].Provider)
@@ -284,6 +910,12 @@ func setDefaults(v *viper.Viper) {
 
 	v.SetDefault("security.command_timeout", defaultConfig.Security.CommandTimeout)
 	v.SetDefault("security.mode", defaultConfig.Security.Mode)
+	v.SetDefault("security.unlisted_bins", defaultConfig.Security.UnlistedBins)
+	v.SetDefault("security.inline_scripts", defaultConfig.Security.InlineScripts)
+	v.SetDefault("security.approval_timeout", defaultConfig.Security.ApprovalTimeout)
+	v.SetDefault("security.code_review", defaultConfig.Security.CodeReview)
+	v.SetDefault("security.canaries", defaultConfig.Security.Canaries)
+	v.SetDefault("security.policy_alerts", defaultConfig.Security.PolicyAlerts)
 
 	v.SetDefault("costs.daily_limit", defaultConfig.Costs.DailyLimit)
 	v.SetDefault("costs.monthly_limit", defaultConfig.Costs.MonthlyLimit)
@@ -293,9 +925,95 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("context.max_tool_calls", defaultConfig.Context.MaxToolCalls)
 	v.SetDefault("context.tool_output_length", defaultConfig.Context.ToolOutputLength)
 	v.SetDefault("context.daily_log_lookback_days", defaultConfig.Context.DailyLogLookbackDays)
+	v.SetDefault("context.max_turn_tokens", defaultConfig.Context.MaxTurnTokens)
+	v.SetDefault("context.max_turn_duration", defaultConfig.Context.MaxTurnDuration)
+	v.SetDefault("context.progress_update_after", defaultConfig.Context.ProgressUpdateAfter)
+	v.SetDefault("context.lazy_tools", defaultConfig.Context.LazyTools)
+	v.SetDefault("context.core_tools", defaultConfig.Context.CoreTools)
+	v.SetDefault("context.workspace_summary.enabled", defaultConfig.Context.WorkspaceSummary.Enabled)
+	v.SetDefault("context.workspace_summary.max_entries", defaultConfig.Context.WorkspaceSummary.MaxEntries)
 
 	v.SetDefault("web.search.provider", defaultConfig.Web.Search.Provider)
 	v.SetDefault("web.search.api_key", defaultConfig.Web.Search.APIKey)
+
+	v.SetDefault("proactive.enabled", defaultConfig.Proactive.Enabled)
+	v.SetDefault("proactive.schedule", defaultConfig.Proactive.Schedule)
+	v.SetDefault("proactive.max_per_day", defaultConfig.Proactive.MaxPerDay)
+	v.SetDefault("proactive.channel", defaultConfig.Proactive.Channel)
+
+	v.SetDefault("notifications.quiet_hours", defaultConfig.Notifications.QuietHours)
+	v.SetDefault("notifications.follow_presence", defaultConfig.Notifications.FollowPresence)
+
+	v.SetDefault("workspace.tmp_max_age", defaultConfig.Workspace.TmpMaxAge)
+	v.SetDefault("workspace.tmp_max_size_mb", defaultConfig.Workspace.TmpMaxSizeMB)
+	v.SetDefault("workspace.cleanup_schedule", defaultConfig.Workspace.CleanupSchedule)
+	v.SetDefault("workspace.trash_retention", defaultConfig.Workspace.TrashRetention)
+
+	v.SetDefault("privacy.redact", defaultConfig.Privacy.Redact)
+	v.SetDefault("privacy.redact_kinds", defaultConfig.Privacy.RedactKinds)
+	v.SetDefault("privacy.redact_patterns", defaultConfig.Privacy.RedactPatterns)
+	v.SetDefault("privacy.outbound_secrets", defaultConfig.Privacy.OutboundSecrets)
+	v.SetDefault("privacy.memory_writes", defaultConfig.Privacy.MemoryWrites)
+
+	v.SetDefault("memory.retention_schedule", defaultConfig.Memory.RetentionSchedule)
+	v.SetDefault("memory.digest_schedule", defaultConfig.Memory.DigestSchedule)
+
+	v.SetDefault("storage.backend", defaultConfig.Storage.Backend)
+	v.SetDefault("storage.s3.endpoint", defaultConfig.Storage.S3.Endpoint)
+	v.SetDefault("storage.s3.region", defaultConfig.Storage.S3.Region)
+	v.SetDefault("storage.s3.bucket", defaultConfig.Storage.S3.Bucket)
+	v.SetDefault("storage.s3.prefix", defaultConfig.Storage.S3.Prefix)
+	v.SetDefault("storage.s3.access_key_id", defaultConfig.Storage.S3.AccessKeyID)
+	v.SetDefault("storage.s3.secret_access_key", defaultConfig.Storage.S3.SecretAccessKey)
+	v.SetDefault("storage.webdav.url", defaultConfig.Storage.WebDAV.URL)
+	v.SetDefault("storage.webdav.username", defaultConfig.Storage.WebDAV.Username)
+	v.SetDefault("storage.webdav.password", defaultConfig.Storage.WebDAV.Password)
+
+	v.SetDefault("transcription.endpoint", defaultConfig.Transcription.Endpoint)
+	v.SetDefault("transcription.api_key", defaultConfig.Transcription.APIKey)
+	v.SetDefault("transcription.model", defaultConfig.Transcription.Model)
+	v.SetDefault("transcription.chunk_duration", defaultConfig.Transcription.ChunkDuration)
+
+	v.SetDefault("translation.provider", defaultConfig.Translation.Provider)
+	v.SetDefault("translation.endpoint", defaultConfig.Translation.Endpoint)
+	v.SetDefault("translation.api_key", defaultConfig.Translation.APIKey)
+
+	v.SetDefault("embeddings.provider", defaultConfig.Embeddings.Provider)
+	v.SetDefault("embeddings.endpoint", defaultConfig.Embeddings.Endpoint)
+	v.SetDefault("embeddings.api_key", defaultConfig.Embeddings.APIKey)
+	v.SetDefault("embeddings.model", defaultConfig.Embeddings.Model)
+	v.SetDefault("embeddings.dimensions", defaultConfig.Embeddings.Dimensions)
+
+	v.SetDefault("rerank.provider", defaultConfig.Rerank.Provider)
+	v.SetDefault("rerank.endpoint", defaultConfig.Rerank.Endpoint)
+	v.SetDefault("rerank.api_key", defaultConfig.Rerank.APIKey)
+	v.SetDefault("rerank.model", defaultConfig.Rerank.Model)
+	v.SetDefault("rerank.candidates", defaultConfig.Rerank.Candidates)
+
+	v.SetDefault("update.check", defaultConfig.Update.Check)
+	v.SetDefault("update.notify", defaultConfig.Update.Notify)
+
+	v.SetDefault("telemetry.endpoint", defaultConfig.Telemetry.Endpoint)
+}
+
+// applyLowMemoryDefaults swaps in the smaller low_memory [context] values
+// for keys the config file does not set.
+func applyLowMemoryDefaults(cfg *Config, inConfig func(key string) bool) {
+	if cfg == nil || !cfg.LowMemory {
+		return
+	}
+	if !inConfig("context.max_tokens") {
+		cfg.Context.MaxTokens = lowMemoryContext.MaxTokens
+	}
+	if !inConfig("context.recent_messages") {
+		cfg.Context.RecentMessages = lowMemoryContext.RecentMessages
+	}
+	if !inConfig("context.tool_output_length") {
+		cfg.Context.ToolOutputLength = lowMemoryContext.ToolOutputLength
+	}
+	if !inConfig("context.daily_log_lookback_days") {
+		cfg.Context.DailyLogLookbackDays = lowMemoryContext.DailyLogLookbackDays
+	}
 }
 
 // applyZeroValueDefaults replaces explicit zero numeric config values with runtime defaults.
@@ -323,6 +1041,9 @@ func applyZeroValueDefaults(cfg *Config) {
 	if cfg.Context.DailyLogLookbackDays == 0 {
 		cfg.Context.DailyLogLookbackDays = defaultConfig.Context.DailyLogLookbackDays
 	}
+	if cfg.Context.WorkspaceSummary.MaxEntries <= 0 {
+		cfg.Context.WorkspaceSummary.MaxEntries = defaultConfig.Context.WorkspaceSummary.MaxEntries
+	}
 
 	for name, llm := range cfg.LLM {
 		if llm.MaxTokens == 0 {
@@ -351,6 +1072,45 @@ func (c *Config) TelegramChannel() ChannelConfig {
 	return defaultConfig.Channels["telegram"]
 }
 
+// IsTelegramChannel reports whether a channels entry is a Telegram bot.
+func IsTelegramChannel(name string) bool {
+	return name == TelegramChannelName || strings.HasPrefix(name, telegramChannelPrefix)
+}
+
+// TelegramBots returns the names of the enabled Telegram bots,
+// [channels.telegram] first and the rest sorted.
+func (c *Config) TelegramBots() []string {
+	var names []string
+	for name, ch := range c.Channels {
+		if IsTelegramChannel(name) && ch.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == TelegramChannelName || names[j] == TelegramChannelName {
+			return names[i] == TelegramChannelName
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// AgentName returns the agent this channel serves.
+func (c ChannelConfig) AgentName() string {
+	if agent := strings.TrimSpace(c.Agent); agent != "" {
+		return agent
+	}
+	return defaultAgent
+}
+
+// ForAgent returns a copy of the config whose agent paths point at agent.
+func (c *Config) ForAgent(agent string) *Config {
+	agentCfg := *c
+	agentCfg.Agent = agent
+	agentCfg.Security.Workspace = agentCfg.WorkspaceDir()
+	return &agentCfg
+}
+
 func validateSecurityMode(mode string) error {
 	switch mode {
 	case SecurityModeStandard, SecurityModeDanger, SecurityModeStrict:
@@ -380,8 +1140,19 @@ func (c LLMProviderConfig) Validate() error {
 		return errors.New("request_timeout must be >= 0")
 	}
 
+	if c.OAuth.Enabled() && c.Provider != "anthropic" {
+		return errors.New("oauth is only supported by the anthropic provider")
+	}
 	switch c.Provider {
-	case "anthropic", "openrouter":
+	case "anthropic":
+		if c.OAuth.Enabled() {
+			if err := c.OAuth.validate(); err != nil {
+				return fmt.Errorf("oauth: %w", err)
+			}
+		} else if c.APIKey == "" && c.AuthToken == "" {
+			return errors.New("api_key is required unless auth_token or oauth is set")
+		}
+	case "openrouter":
 		if c.APIKey == "" {
 			return errors.New("api_key is required")
 		}
@@ -393,6 +1164,16 @@ func (c LLMProviderConfig) Validate() error {
 	return nil
 }
 
+func (c OAuthConfig) validate() error {
+	if err := validateStorageURL(c.DeviceAuthorizationURL, false); err != nil {
+		return fmt.Errorf("device_authorization_url: %w", err)
+	}
+	if err := validateStorageURL(c.TokenURL, false); err != nil {
+		return fmt.Errorf("token_url: %w", err)
+	}
+	return nil
+}
+
 // Validate checks required channel fields when the channel is enabled.
 func (c ChannelConfig) Validate() error {
 	if !c.Enabled {
@@ -401,6 +1182,136 @@ func (c ChannelConfig) Validate() error {
 	if c.Token == "" {
 		return errors.New("token is required when enabled=true")
 	}
+	return c.validateOptions()
+}
+
+// minAPIKeyLength keeps HTTP API keys long enough not to be guessed.
+const minAPIKeyLength = 24
+
+// validateHTTP checks the [channels.http] entry, which authenticates
+// clients with api_keys instead of a token.
+func (c ChannelConfig) validateHTTP() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.APIKeys) == 0 {
+		return errors.New("api_keys is required when enabled=true")
+	}
+	for i, key := range c.APIKeys {
+		if len(strings.TrimSpace(key)) < minAPIKeyLength {
+			return fmt.Errorf("api_keys[%d] must be at least %d characters; generate one with openssl rand -hex 32", i, minAPIKeyLength)
+		}
+	}
+	if listen := strings.TrimSpace(c.Listen); listen != "" {
+		if _, _, err := net.SplitHostPort(listen); err != nil {
+			return fmt.Errorf("listen must be host:port, got %q", c.Listen)
+		}
+	}
+	return c.validateOptions()
+}
+
+// validateWeb checks the [channels.web] entry, whose token is optional: an
+// empty one is generated on each start.
+func (c ChannelConfig) validateWeb() error {
+	if !c.Enabled {
+		return nil
+	}
+	if token := strings.TrimSpace(c.Token); token != "" && len(token) < minAPIKeyLength {
+		return fmt.Errorf("token must be at least %d characters; generate one with openssl rand -hex 32, or leave it empty", minAPIKeyLength)
+	}
+	if listen := strings.TrimSpace(c.Listen); listen != "" {
+		if _, _, err := net.SplitHostPort(listen); err != nil {
+			return fmt.Errorf("listen must be host:port, got %q", c.Listen)
+		}
+	}
+	return c.validateOptions()
+}
+
+// validateWhatsApp checks the [channels.whatsapp] entry, which needs the
+// Cloud API sender and webhook secrets besides the token.
+func (c ChannelConfig) validateWhatsApp() error {
+	if !c.Enabled {
+		return nil
+	}
+	for _, required := range []struct{ key, value string }{
+		{"token", c.Token},
+		{"phone_number_id", c.PhoneNumberID},
+		{"app_secret", c.AppSecret},
+		{"verify_token", c.VerifyToken},
+	} {
+		if strings.TrimSpace(required.value) == "" {
+			return fmt.Errorf("%s is required when enabled=true", required.key)
+		}
+	}
+	if listen := strings.TrimSpace(c.Listen); listen != "" {
+		if _, _, err := net.SplitHostPort(listen); err != nil {
+			return fmt.Errorf("listen must be host:port, got %q", c.Listen)
+		}
+	}
+	return c.validateOptions()
+}
+
+// validateOptions checks the settings shared by all channels.
+func (c ChannelConfig) validateOptions() error {
+	switch c.ResponseFormat {
+	case "", ResponseFormatText, ResponseFormatJSON:
+	default:
+		return fmt.Errorf("invalid response_format %s (allowed: %s, %s)", c.ResponseFormat, ResponseFormatText, ResponseFormatJSON)
+	}
+	if strings.TrimSpace(c.ResponseSchema) != "" && c.ResponseFormat != ResponseFormatJSON {
+		return fmt.Errorf("response_schema requires response_format = %q", ResponseFormatJSON)
+	}
+	if c.MaxReplyLength < 0 {
+		return errors.New("max_reply_length must be >= 0")
+	}
+	if c.MaxReplyLength > 0 && len([]rune(strings.TrimSpace(c.Signature)))+2 >= c.MaxReplyLength {
+		return errors.New("max_reply_length must leave room for the signature")
+	}
+	if c.WebhookURL != "" {
+		// Telegram only delivers webhooks over HTTPS.
+		if endpoint, err := url.Parse(c.WebhookURL); err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+			return fmt.Errorf("webhook_url must be an https:// URL, got %q", c.WebhookURL)
+		}
+	}
+	if (c.WebhookCertFile == "") != (c.WebhookKeyFile == "") {
+		return errors.New("webhook_cert_file and webhook_key_file must be set together")
+	}
+	if agent := strings.TrimSpace(c.Agent); agent != "" && !namePattern.MatchString(agent) {
+		return fmt.Errorf("invalid agent %q (use lowercase letters, digits, - and _)", c.Agent)
+	}
+	return nil
+}
+
+// validateTelegramBots keeps bots apart: every enabled bot needs its own
+// token, agent, and webhook address.
+func validateTelegramBots(cfg *Config) error {
+	tokens := map[string]string{}
+	agents := map[string]string{}
+	listens := map[string]string{}
+	for _, name := range cfg.TelegramBots() {
+		if name != TelegramChannelName && !namePattern.MatchString(strings.TrimPrefix(name, telegramChannelPrefix)) {
+			return fmt.Errorf("channels.%s: invalid bot name (use channels.telegram_<name> with lowercase letters, digits, - and _)", name)
+		}
+		ch := cfg.Channels[name]
+		if other, ok := tokens[ch.Token]; ok {
+			return fmt.Errorf("channels.%s: token is already used by channels.%s", name, other)
+		}
+		tokens[ch.Token] = name
+		if other, ok := agents[ch.AgentName()]; ok {
+			return fmt.Errorf("channels.%s: agent %q is already served by channels.%s; set a different agent", name, ch.AgentName(), other)
+		}
+		agents[ch.AgentName()] = name
+		if ch.WebhookURL != "" {
+			listen := ch.WebhookListen
+			if listen == "" {
+				listen = defaultWebhookListen
+			}
+			if other, ok := listens[listen]; ok {
+				return fmt.Errorf("channels.%s: webhook_listen %s is already used by channels.%s", name, listen, other)
+			}
+			listens[listen] = name
+		}
+	}
 	return nil
 }
 
@@ -412,6 +1323,26 @@ func (c SecurityConfig) Validate() error {
 	if c.CommandTimeout < 0 {
 		return errors.New("command_timeout must be >= 0")
 	}
+	if c.ApprovalTimeout < 0 {
+		return errors.New("approval_timeout must be >= 0")
+	}
+	switch strings.ToLower(strings.TrimSpace(c.UnlistedBins)) {
+	case "", UnlistedBinsAllow, UnlistedBinsPrompt, UnlistedBinsDeny:
+	default:
+		return fmt.Errorf("invalid security.unlisted_bins %s (allowed: %s, %s, %s)", c.UnlistedBins, UnlistedBinsAllow, UnlistedBinsPrompt, UnlistedBinsDeny)
+	}
+	switch strings.ToLower(strings.TrimSpace(c.InlineScripts)) {
+	case "", InlineScriptsPolicy, InlineScriptsPrompt, InlineScriptsSandbox:
+	default:
+		return fmt.Errorf("invalid security.inline_scripts %s (allowed: %s, %s, %s)", c.InlineScripts, InlineScriptsPolicy, InlineScriptsPrompt, InlineScriptsSandbox)
+	}
+	for tool, level := range c.Trust {
+		switch level {
+		case TrustUser, TrustWorkspace, TrustInternet:
+		default:
+			return fmt.Errorf("invalid security.trust.%s %s (allowed: %s, %s, %s)", tool, level, TrustUser, TrustWorkspace, TrustInternet)
+		}
+	}
 	return nil
 }
 
@@ -446,6 +1377,20 @@ func (c ContextConfig) Validate() error {
 	if c.DailyLogLookbackDays < 0 {
 		return errors.New("daily_log_lookback_days must be >= 0")
 	}
+	if c.MaxTurnTokens < 0 {
+		return errors.New("max_turn_tokens must be >= 0")
+	}
+	if c.MaxTurnDuration < 0 {
+		return errors.New("max_turn_duration must be >= 0")
+	}
+	if c.ProgressUpdateAfter < 0 {
+		return errors.New("progress_update_after must be >= 0")
+	}
+	for _, name := range c.CoreTools {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("core_tools must not contain empty names")
+		}
+	}
 	return nil
 }
 
@@ -459,6 +1404,239 @@ func (c WebConfig) Validate() error {
 	}
 }
 
+// Validate validates proactive check-in settings.
+func (c ProactiveConfig) Validate() error {
+	if c.MaxPerDay < 0 {
+		return errors.New("max_per_day must be >= 0")
+	}
+	if !c.Enabled {
+		return nil
+	}
+	if _, err := cron.ParseStandard(strings.TrimSpace(c.Schedule)); err != nil {
+		return fmt.Errorf("invalid schedule %q: %w", c.Schedule, err)
+	}
+	return nil
+}
+
+// Validate validates notification delivery settings.
+func (c NotificationsConfig) Validate() error {
+	_, err := ParseQuietHours(c.QuietHours)
+	return err
+}
+
+// Validate validates the storage backend settings.
+func (c StorageConfig) Validate() error {
+	switch strings.ToLower(strings.TrimSpace(c.Backend)) {
+	case "", StorageBackendLocal:
+	case StorageBackendS3:
+		if strings.TrimSpace(c.S3.Bucket) == "" {
+			return errors.New("s3.bucket is required")
+		}
+		if strings.TrimSpace(c.S3.Region) == "" {
+			return errors.New("s3.region is required")
+		}
+		if c.S3.AccessKeyID == "" || c.S3.SecretAccessKey == "" {
+			return errors.New("s3.access_key_id and s3.secret_access_key are required")
+		}
+		if err := validateStorageURL(c.S3.Endpoint, true); err != nil {
+			return fmt.Errorf("s3.endpoint: %w", err)
+		}
+	case StorageBackendWebDAV:
+		if err := validateStorageURL(c.WebDAV.URL, false); err != nil {
+			return fmt.Errorf("webdav.url: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid backend %q (allowed: %s, %s, %s)", c.Backend, StorageBackendLocal, StorageBackendS3, StorageBackendWebDAV)
+	}
+	return nil
+}
+
+// S3Endpoint returns the configured endpoint or the AWS endpoint for the region.
+func (c S3StorageConfig) S3Endpoint() string {
+	if endpoint := strings.TrimSpace(c.Endpoint); endpoint != "" {
+		return endpoint
+	}
+	return "https://s3." + strings.TrimSpace(c.Region) + ".amazonaws.com"
+}
+
+func validateStorageURL(raw string, optional bool) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		if optional {
+			return nil
+		}
+		return errors.New("is required")
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return fmt.Errorf("must be an http(s) URL, got %q", raw)
+	}
+	return nil
+}
+
+// Validate validates transcription settings.
+func (c TranscriptionConfig) Validate() error {
+	if err := validateStorageURL(c.Endpoint, true); err != nil {
+		return fmt.Errorf("endpoint: %w", err)
+	}
+	if c.ChunkDuration < 0 {
+		return errors.New("chunk_duration must be >= 0")
+	}
+	if c.ChunkDuration > 0 && c.ChunkDuration < time.Minute {
+		return errors.New("chunk_duration must be at least 1m")
+	}
+	return nil
+}
+
+// Validate validates translation settings.
+func (c TranslationConfig) Validate() error {
+	switch strings.ToLower(strings.TrimSpace(c.Provider)) {
+	case "", "libretranslate", "deepl":
+	default:
+		return fmt.Errorf("unsupported provider %q (allowed: libretranslate, deepl)", c.Provider)
+	}
+	if err := validateStorageURL(c.Endpoint, true); err != nil {
+		return fmt.Errorf("endpoint: %w", err)
+	}
+	return nil
+}
+
+// maxEmbeddingDimensions bounds lexical vectors, which are kept in memory
+// for every indexed text.
+const maxEmbeddingDimensions = 4096
+
+// Validate validates embeddings settings.
+func (c EmbeddingsConfig) Validate() error {
+	switch strings.ToLower(strings.TrimSpace(c.Provider)) {
+	case "", "lexical", "openai", "voyage", "ollama":
+	default:
+		return fmt.Errorf("unsupported provider %q (allowed: lexical, openai, voyage, ollama)", c.Provider)
+	}
+	if err := validateStorageURL(c.Endpoint, true); err != nil {
+		return fmt.Errorf("endpoint: %w", err)
+	}
+	if c.Dimensions != 0 && (c.Dimensions < 16 || c.Dimensions > maxEmbeddingDimensions) {
+		return fmt.Errorf("dimensions must be between 16 and %d", maxEmbeddingDimensions)
+	}
+	return nil
+}
+
+// maxRerankCandidates keeps one rerank request to a size every provider
+// accepts.
+const maxRerankCandidates = 200
+
+// Validate validates rerank settings.
+func (c RerankConfig) Validate() error {
+	switch strings.ToLower(strings.TrimSpace(c.Provider)) {
+	case "", "local", "voyage", "cohere":
+	default:
+		return fmt.Errorf("unsupported provider %q (allowed: local, voyage, cohere)", c.Provider)
+	}
+	if err := validateStorageURL(c.Endpoint, true); err != nil {
+		return fmt.Errorf("endpoint: %w", err)
+	}
+	if c.Candidates < 0 || c.Candidates > maxRerankCandidates {
+		return fmt.Errorf("candidates must be between 0 and %d", maxRerankCandidates)
+	}
+	return nil
+}
+
+// Validate checks that the project stays inside the workspace.
+func (c ProjectConfig) Validate() error {
+	if strings.TrimSpace(c.Path) == "" {
+		return errors.New("path is required")
+	}
+	if !filepath.IsLocal(c.Path) {
+		return fmt.Errorf("path must be relative to the workspace and stay inside it, got %q", c.Path)
+	}
+	for i, pin := range c.Pins {
+		if !filepath.IsLocal(pin) {
+			return fmt.Errorf("pins[%d] must be relative to the project and stay inside it, got %q", i, pin)
+		}
+	}
+	for i, pattern := range c.AllowedCommands {
+		if strings.TrimSpace(pattern) == "" || strings.TrimSpace(pattern) == "*" {
+			return fmt.Errorf("allowed_commands[%d] must name a command, got %q", i, pattern)
+		}
+	}
+	return nil
+}
+
+// Validate validates CLI alert settings.
+func (c CLIConfig) Validate() error {
+	if c.NotifyAfter < 0 {
+		return errors.New("notify_after must be >= 0")
+	}
+	return nil
+}
+
+// Validate validates workspace retention settings.
+func (c WorkspaceConfig) Validate() error {
+	if c.TmpMaxAge < 0 {
+		return errors.New("tmp_max_age must be >= 0")
+	}
+	if c.TmpMaxSizeMB < 0 {
+		return errors.New("tmp_max_size_mb must be >= 0")
+	}
+	if c.TrashRetention < 0 {
+		return errors.New("trash_retention must be >= 0")
+	}
+	if schedule := strings.TrimSpace(c.CleanupSchedule); schedule != "" {
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			return fmt.Errorf("invalid cleanup_schedule %q: %w", c.CleanupSchedule, err)
+		}
+	}
+	return nil
+}
+
+// Validate validates daily log retention and digest settings.
+func (c MemoryConfig) Validate() error {
+	if schedule := strings.TrimSpace(c.RetentionSchedule); schedule != "" {
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			return fmt.Errorf("invalid retention_schedule %q: %w", c.RetentionSchedule, err)
+		}
+	}
+	if schedule := strings.TrimSpace(c.DigestSchedule); schedule != "" {
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			return fmt.Errorf("invalid digest_schedule %q: %w", c.DigestSchedule, err)
+		}
+	}
+	for tag, rule := range c.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return errors.New("tags: tag name is required")
+		}
+		if rule.RetentionDays < 0 {
+			return fmt.Errorf("tags.%s: retention_days must be >= 0", tag)
+		}
+	}
+	return nil
+}
+
+// Validate validates redaction settings.
+func (c PrivacyConfig) Validate() error {
+	switch c.OutboundSecrets {
+	case "", redact.SecretsRedact, redact.SecretsBlock, redact.SecretsOff:
+	default:
+		return fmt.Errorf("invalid outbound_secrets %q (allowed: %s, %s, %s)", c.OutboundSecrets, redact.SecretsRedact, redact.SecretsBlock, redact.SecretsOff)
+	}
+	switch c.MemoryWrites {
+	case "", MemoryWritesAuto, MemoryWritesApprove, MemoryWritesQueue:
+	default:
+		return fmt.Errorf("invalid memory_writes %q (allowed: %s, %s, %s)", c.MemoryWrites, MemoryWritesAuto, MemoryWritesApprove, MemoryWritesQueue)
+	}
+	_, err := c.Redactor()
+	return err
+}
+
+// Redactor builds the configured redaction filter, or nil when redaction is off.
+func (c PrivacyConfig) Redactor() (*redact.Redactor, error) {
+	r, err := redact.New(c.RedactKinds, c.RedactPatterns)
+	if err != nil || !c.Redact {
+		return nil, err
+	}
+	return r, nil
+}
+
 func (cfg *Config) firstValidationError() error {
 	var errs []error
 
@@ -469,6 +1647,9 @@ func (cfg *Config) firstValidationError() error {
 		errs = append(errs, errors.New("at least one channels.* entry is required"))
 	}
 
+	if err := cfg.CLI.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("cli: %w", err))
+	}
 	if err := cfg.Security.Validate(); err != nil {
 		errs = append(errs, fmt.Errorf("security: %w", err))
 	}
@@ -481,17 +1662,82 @@ func (cfg *Config) firstValidationError() error {
 	if err := cfg.Web.Validate(); err != nil {
 		errs = append(errs, fmt.Errorf("web: %w", err))
 	}
+	if err := cfg.Proactive.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("proactive: %w", err))
+	}
+	if err := cfg.Notifications.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("notifications: %w", err))
+	}
+	if err := cfg.Workspace.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("workspace: %w", err))
+	}
+	if err := cfg.Privacy.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("privacy: %w", err))
+	}
+	if err := cfg.Memory.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("memory: %w", err))
+	}
+	if err := cfg.Storage.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("storage: %w", err))
+	}
+	if err := cfg.Transcription.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("transcription: %w", err))
+	}
+	if err := cfg.Translation.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("translation: %w", err))
+	}
+	if err := cfg.Embeddings.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("embeddings: %w", err))
+	}
+	if err := cfg.Rerank.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("rerank: %w", err))
+	}
 
 	for name, llmCfg := range cfg.LLM {
 		if err := llmCfg.Validate(); err != nil {
 			errs = append(errs, fmt.Errorf("llm.%s: %w", name, err))
 		}
+		if fallback := llmCfg.Fallback; fallback != "" {
+			if _, ok := cfg.LLM[fallback]; !ok || fallback == name {
+				errs = append(errs, fmt.Errorf("llm.%s: fallback %q must name another llm profile", name, fallback))
+			}
+		}
+	}
+	if name := cfg.Security.CodeReview; name != "" {
+		if _, ok := cfg.LLM[name]; !ok {
+			errs = append(errs, fmt.Errorf("security.code_review %q must name an llm profile", name))
+		}
+	}
+	for name, project := range cfg.Projects {
+		if err := project.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("projects.%s: %w", name, err))
+		}
 	}
 	for name, chCfg := range cfg.Channels {
-		if err := chCfg.Validate(); err != nil {
+		validate := chCfg.Validate
+		switch name {
+		case HTTPChannelName:
+			validate = chCfg.validateHTTP
+		case WebChannelName:
+			validate = chCfg.validateWeb
+		case WhatsAppChannelName:
+			validate = chCfg.validateWhatsApp
+		}
+		if err := validate(); err != nil {
 			errs = append(errs, fmt.Errorf("channels.%s: %w", name, err))
 		}
 	}
+	if slack := cfg.Channels[SlackChannelName]; slack.Enabled && strings.TrimSpace(slack.AppToken) == "" {
+		errs = append(errs, fmt.Errorf("channels.%s: app_token is required when enabled=true", SlackChannelName))
+	}
+	if matrix := cfg.Channels[MatrixChannelName]; matrix.Enabled {
+		if endpoint, err := url.Parse(strings.TrimSpace(matrix.Homeserver)); err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+			errs = append(errs, fmt.Errorf("channels.%s: homeserver must be an http:// or https:// URL, got %q", MatrixChannelName, matrix.Homeserver))
+		}
+	}
+	if err := validateTelegramBots(cfg); err != nil {
+		errs = append(errs, err)
+	}
 
 	if len(errs) > 0 {
 		return errs[0]
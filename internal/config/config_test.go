@@ -59,6 +59,159 @@ token = "bot-token"
 	}
 }
 
+func TestLoad_ExtraTelegramBotsGetChannelDefaults(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".neoclaw")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatalf("mkdir data dir: %v", err)
+	}
+	t.Setenv("NEOCLAW_HOME", dataDir)
+
+	configBody := `
+[channels.telegram_work]
+token = "work-token"
+agent = "work"
+`
+	if err := os.WriteFile(filepath.Join(dataDir, "config.toml"), []byte(configBody), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	work := cfg.Channels["telegram_work"]
+	if !work.Enabled || work.ResponseFormat != ResponseFormatText || work.AgentName() != "work" {
+		t.Fatalf("expected telegram defaults for the extra bot, got %#v", work)
+	}
+
+	workCfg := cfg.ForAgent(work.AgentName())
+	if workCfg.Security.Workspace != filepath.Join(dataDir, "data", "agents", "work", "workspace") || cfg.Agent != defaultAgent {
+		t.Fatalf("unexpected agent config %q (base agent %q)", workCfg.Security.Workspace, cfg.Agent)
+	}
+	if got := workCfg.AllowedUsersPathFor("telegram_work"); got != filepath.Join(dataDir, "data", "channels", "telegram_work", "allowed_users.json") {
+		t.Fatalf("unexpected allowlist path %q", got)
+	}
+	if workCfg.AllowedUsersPathFor("telegram") != workCfg.AllowedUsersPath() {
+		t.Fatalf("expected the first bot to keep the shared allowlist")
+	}
+}
+
+func TestLoad_AcceptsBothTelegramBotSpellings(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".neoclaw")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatalf("mkdir data dir: %v", err)
+	}
+	t.Setenv("NEOCLAW_HOME", dataDir)
+
+	configBody := `
+[channels.telegram]
+token = "home-token"
+
+[channels.telegram.work]
+token = "work-token"
+agent = "work"
+
+[channels.telegram_family]
+token = "family-token"
+agent = "family"
+`
+	if err := os.WriteFile(filepath.Join(dataDir, "config.toml"), []byte(configBody), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := strings.Join(cfg.TelegramBots(), ","); got != "telegram,telegram_family,telegram_work" {
+		t.Fatalf("unexpected bots %q", got)
+	}
+	work := cfg.Channels["telegram_work"]
+	if !work.Enabled || work.Token != "work-token" || work.AgentName() != "work" || work.ResponseFormat != ResponseFormatText {
+		t.Fatalf("expected the nested bot with telegram defaults, got %#v", work)
+	}
+	if cfg.Channels["telegram_family"].Token != "family-token" || cfg.Channels["telegram"].Token != "home-token" {
+		t.Fatalf("unexpected channels %#v", cfg.Channels)
+	}
+
+	configBody += `
+[channels.telegram_work]
+token = "other-token"
+`
+	if err := os.WriteFile(filepath.Join(dataDir, "config.toml"), []byte(configBody), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "also configured as [channels.telegram_work]") {
+		t.Fatalf("expected an error for a bot configured twice, got %v", err)
+	}
+}
+
+func TestLoad_LowMemoryShrinksUnsetContextDefaults(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".neoclaw")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatalf("mkdir data dir: %v", err)
+	}
+	t.Setenv("NEOCLAW_HOME", dataDir)
+
+	configBody := `
+low_memory = true
+
+[context]
+recent_messages = 20
+`
+	if err := os.WriteFile(filepath.Join(dataDir, "config.toml"), []byte(configBody), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if !cfg.LowMemory {
+		t.Fatalf("expected low_memory from file")
+	}
+	if cfg.Context.MaxTokens != lowMemoryContext.MaxTokens || cfg.Context.DailyLogLookbackDays != lowMemoryContext.DailyLogLookbackDays {
+		t.Fatalf("expected low-memory context defaults, got %+v", cfg.Context)
+	}
+	if cfg.Context.RecentMessages != 20 {
+		t.Fatalf("expected configured recent_messages to win, got %d", cfg.Context.RecentMessages)
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".neoclaw")
+	t.Setenv("NEOCLAW_HOME", dataDir)
+	t.Setenv("NEOCLAW_LLM_DEFAULT_API_KEY", "env-key")
+	t.Setenv("NEOCLAW_CHANNELS_TELEGRAM_TOKEN", "env-token")
+	t.Setenv("NEOCLAW_CONTEXT_MAX_TOOL_CALLS", "7")
+	t.Setenv("NEOCLAW_LOW_MEMORY", "true")
+	t.Setenv("NEOCLAW_CONTEXT_RECENT_MESSAGES", "20")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.DefaultLLM().APIKey == "env-key" {
+		t.Fatalf("env overrides applied before EnableEnvOverrides")
+	}
+
+	EnableEnvOverrides()
+	t.Cleanup(func() { envOverrides = false })
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.DefaultLLM().APIKey != "env-key" || cfg.TelegramChannel().Token != "env-token" {
+		t.Fatalf("expected env values, got api key %q token %q", cfg.DefaultLLM().APIKey, cfg.TelegramChannel().Token)
+	}
+	if cfg.Context.MaxToolCalls != 7 || !cfg.LowMemory {
+		t.Fatalf("expected typed env values, got %+v low_memory=%v", cfg.Context, cfg.LowMemory)
+	}
+	if cfg.Context.RecentMessages != 20 || cfg.Context.MaxTokens != lowMemoryContext.MaxTokens {
+		t.Fatalf("expected env recent_messages to beat low-memory defaults, got %+v", cfg.Context)
+	}
+}
+
 func TestLoad_ExpandsEnvVarsInStringValues(t *testing.T) {
 	dataDir := filepath.Join(t.TempDir(), ".neoclaw")
 	if err := os.MkdirAll(dataDir, 0o755); err != nil {
@@ -141,6 +294,9 @@ func TestLoad_DefaultsApplyWithoutConfigFile(t *testing.T) {
 	if cfg.Security.Workspace != expectedWorkspace {
 		t.Fatalf("expected derived workspace %q, got %q", expectedWorkspace, cfg.Security.Workspace)
 	}
+	if llm.OAuth.SecretsDir != filepath.Join(expectedDataDir, "secrets") || llm.OAuth.SecretName != "oauth-default" {
+		t.Fatalf("unexpected derived oauth token location %q %q", llm.OAuth.SecretsDir, llm.OAuth.SecretName)
+	}
 
 	telegram := cfg.TelegramChannel()
 	if !telegram.Enabled {
@@ -289,6 +445,48 @@ daily_log_lookback_days = 3
 	}
 }
 
+func TestLoad_MemoryTagRules(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".neoclaw")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatalf("mkdir data dir: %v", err)
+	}
+	t.Setenv("NEOCLAW_HOME", dataDir)
+
+	configBody := `
+[llm.default]
+api_key = "test-key"
+provider = "anthropic"
+model = "claude-sonnet-4-6"
+
+[memory.tags.health]
+retention_days = 1825
+exclude_from_prompt = true
+
+[memory.tags.chores]
+retention_days = 30
+`
+	if err := os.WriteFile(filepath.Join(dataDir, "config.toml"), []byte(configBody), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := cfg.Memory.Tags["health"]; got.RetentionDays != 1825 || !got.ExcludeFromPrompt {
+		t.Fatalf("unexpected health rule: %#v", got)
+	}
+	if got := cfg.Memory.Tags["chores"]; got.RetentionDays != 30 || got.ExcludeFromPrompt {
+		t.Fatalf("unexpected chores rule: %#v", got)
+	}
+	if cfg.Memory.RetentionSchedule != "45 3 * * *" {
+		t.Fatalf("expected default retention schedule, got %q", cfg.Memory.RetentionSchedule)
+	}
+	if cfg.Memory.DigestSchedule != "30 4 * * *" {
+		t.Fatalf("expected default digest schedule, got %q", cfg.Memory.DigestSchedule)
+	}
+}
+
 func TestLoad_NeoClawHomeOverridesDefault(t *testing.T) {
 	customDir := filepath.Join(t.TempDir(), "custom-home")
 	if err := os.MkdirAll(customDir, 0o755); err != nil {
@@ -409,3 +607,42 @@ model = "deepseek/deepseek-chat"
 		t.Fatalf("expected defaults section costs in output, got %q", got)
 	}
 }
+
+func TestWriteRedacted_HidesCredentials(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".neoclaw")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatalf("mkdir data dir: %v", err)
+	}
+	t.Setenv("NEOCLAW_HOME", dataDir)
+
+	configBody := `
+[llm.default]
+api_key = "sk-secret"
+
+[channels.telegram]
+token = "123:bot-secret"
+
+[channels.http]
+api_keys = ["script-key-secret"]
+
+[storage.webdav]
+password = "hunter2"
+`
+	if err := os.WriteFile(filepath.Join(dataDir, "config.toml"), []byte(configBody), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := WriteRedacted(&out); err != nil {
+		t.Fatalf("write redacted toml: %v", err)
+	}
+	got := out.String()
+	for _, secret := range []string{"sk-secret", "bot-secret", "script-key-secret", "hunter2"} {
+		if strings.Contains(got, secret) {
+			t.Fatalf("expected %q redacted, got %q", secret, got)
+		}
+	}
+	if !strings.Contains(got, "api_key = '[redacted]'") || !strings.Contains(got, "max_tokens = ") {
+		t.Fatalf("expected redacted key and untouched settings, got %q", got)
+	}
+}
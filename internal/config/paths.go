@@ -8,11 +8,24 @@ const (
 	DataDirPath    = "data"
 	PolicyDirPath  = "policy"
 	LogsDirPath    = "logs"
+	// BackupsDirPath holds data dir copies made before migrations.
+	BackupsDirPath = "backups"
 	PIDFilePath    = "claw.pid"
+	// HeartbeatFilePath is touched by a running server for `claw healthcheck`.
+	HeartbeatFilePath = "claw.heartbeat"
+	// CrashDirPath holds crash reports under the logs dir.
+	CrashDirPath = "crash"
+	// ChannelsDirPath holds per-bot state under the data dir.
+	ChannelsDirPath = "channels"
+	// SecretsDirPath holds credentials NeoClaw obtains itself, such as OAuth
+	// tokens, under the data dir.
+	SecretsDirPath = "secrets"
 
 	// Agent directory layout under NEOCLAW_HOME/data/agents/{agent}/.
 	AgentsDirPath      = "agents"
 	WorkspaceDirPath   = "workspace"
+	TmpDirPath         = "tmp"
+	TrashDirPath       = "trash"
 	MemoryDirPath      = "memory"
 	DailyDirPath       = "daily"
 	SessionsDirPath    = "sessions"
@@ -22,11 +35,42 @@ const (
 	SoulFilePath       = "SOUL.md"
 	UserFilePath       = "USER.md"
 	MemoryFilePath     = "memory.tsv"
+	PendingFilePath    = "pending.jsonl"
+	WeeklyDigestPath   = "weekly.md"
+	MonthlyDigestPath  = "monthly.md"
+	CheckInsFilePath   = "checkins.json"
+	PromptsDirPath     = "prompts"
+	WorkflowsDirPath   = "workflows"
+	WorkflowRunsPath   = "runs"
+	ArtifactsFilePath  = "artifacts.json"
+	TasksFilePath      = "tasks.json"
+	ListsFilePath      = "lists.json"
+	ExpensesFilePath   = "expenses.tsv"
+	LanguagesFilePath  = "languages.json"
+	ToolOutputsDirPath = "tool_outputs"
 
-	AllowedDomainsFileName  = "allowed_domains.json"
-	AllowedCommandsFileName = "allowed_commands.json"
-	AllowedUsersFileName    = "allowed_users.json"
-	CostsFileName           = "costs.tsv"
+	// ProposedUserFilePath holds a USER.md update waiting for user approval.
+	ProposedUserFilePath = "USER.proposed.md"
+
+	AllowedDomainsFileName   = "allowed_domains.json"
+	AllowedCommandsFileName  = "allowed_commands.json"
+	AllowedUsersFileName     = "allowed_users.json"
+	AllowedBinsFileName      = "allowed_bins.json"
+	TrustedOverlaysFileName  = "trusted_overlays.json"
+	PolicyJournalFileName    = "policy_journal.jsonl"
+	CostsFileName            = "costs.tsv"
+	SessionDeletionsFileName = "session_deletions.jsonl"
+	ProviderHealthFileName   = "provider_health.json"
+	FXRatesFileName          = "fx_rates.json"
+	UpdateCheckFileName      = "update_check.json"
+	TelemetryFileName        = "telemetry.json"
+	TelemetryCountsFileName  = "telemetry_counts.json"
+	PendingApprovalsFileName = "pending_approvals.json"
+	PresenceFileName         = "presence.json"
+	NotificationsFileName    = "notifications.json"
+	CanariesFileName         = "canaries.json"
+	SecurityAlertsFileName   = "security_alerts.jsonl"
+	ServerLogFileName        = "server.jsonl"
 )
 
 func homeConfigPath(home string) string {
@@ -49,10 +93,18 @@ func (c *Config) DataDir() string {
 	return homeDataPath(c.HomeDir)
 }
 
+func (c *Config) BackupsDir() string {
+	return filepath.Join(c.HomeDir, BackupsDirPath)
+}
+
 func (c *Config) PolicyDir() string {
 	return filepath.Join(c.DataDir(), PolicyDirPath)
 }
 
+func (c *Config) SecretsDir() string {
+	return filepath.Join(c.DataDir(), SecretsDirPath)
+}
+
 func (c *Config) LogsDir() string {
 	return filepath.Join(c.DataDir(), LogsDirPath)
 }
@@ -69,14 +121,115 @@ func (c *Config) AllowedUsersPath() string {
 	return filepath.Join(c.PolicyDir(), AllowedUsersFileName)
 }
 
+func (c *Config) AllowedBinsPath() string {
+	return filepath.Join(c.PolicyDir(), AllowedBinsFileName)
+}
+
+func (c *Config) TrustedOverlaysPath() string {
+	return filepath.Join(c.PolicyDir(), TrustedOverlaysFileName)
+}
+
+func (c *Config) PolicyJournalPath() string {
+	return filepath.Join(c.PolicyDir(), PolicyJournalFileName)
+}
+
 func (c *Config) CostsPath() string {
 	return filepath.Join(c.LogsDir(), CostsFileName)
 }
 
+// SessionDeletionsPath is the audit log of messages deleted from sessions.
+func (c *Config) SessionDeletionsPath() string {
+	return filepath.Join(c.LogsDir(), SessionDeletionsFileName)
+}
+
+// SecurityAlertsPath is the audit log of tripped canaries.
+func (c *Config) SecurityAlertsPath() string {
+	return filepath.Join(c.LogsDir(), SecurityAlertsFileName)
+}
+
+// ServerLogPath is the JSON lines log a running server writes.
+func (c *Config) ServerLogPath() string {
+	return filepath.Join(c.LogsDir(), ServerLogFileName)
+}
+
+// CanariesPath holds the tokens planted in the workspace canaries.
+func (c *Config) CanariesPath() string {
+	return filepath.Join(c.DataDir(), CanariesFileName)
+}
+
+func (c *Config) ProviderHealthPath() string {
+	return filepath.Join(c.DataDir(), ProviderHealthFileName)
+}
+
+func (c *Config) FXRatesPath() string {
+	return filepath.Join(c.DataDir(), FXRatesFileName)
+}
+
+func (c *Config) PendingApprovalsPath() string {
+	return filepath.Join(c.DataDir(), PendingApprovalsFileName)
+}
+
+// ChannelDir holds the state of a Telegram bot other than
+// [channels.telegram].
+func (c *Config) ChannelDir(channel string) string {
+	return filepath.Join(c.DataDir(), ChannelsDirPath, channel)
+}
+
+// AllowedUsersPathFor is the allowlist of one Telegram bot.
+// [channels.telegram] keeps the shared policy file.
+func (c *Config) AllowedUsersPathFor(channel string) string {
+	if channel == TelegramChannelName {
+		return c.AllowedUsersPath()
+	}
+	return filepath.Join(c.ChannelDir(channel), AllowedUsersFileName)
+}
+
+// PendingApprovalsPathFor holds the unanswered prompts of one Telegram bot.
+func (c *Config) PendingApprovalsPathFor(channel string) string {
+	if channel == TelegramChannelName {
+		return c.PendingApprovalsPath()
+	}
+	return filepath.Join(c.ChannelDir(channel), PendingApprovalsFileName)
+}
+
+// UpdateCheckPath caches the latest release version seen on the feed.
+func (c *Config) UpdateCheckPath() string {
+	return filepath.Join(c.DataDir(), UpdateCheckFileName)
+}
+
+// TelemetryPath holds the telemetry opt-in choice.
+func (c *Config) TelemetryPath() string {
+	return filepath.Join(c.DataDir(), TelemetryFileName)
+}
+
+// TelemetryCountsPath holds usage counts not yet reported.
+func (c *Config) TelemetryCountsPath() string {
+	return filepath.Join(c.DataDir(), TelemetryCountsFileName)
+}
+
+// PresencePath records the channel each user last wrote from.
+func (c *Config) PresencePath() string {
+	return filepath.Join(c.DataDir(), PresenceFileName)
+}
+
+// NotificationsPath holds do-not-disturb and the notifications held by quiet
+// hours or do-not-disturb, so a restart keeps both.
+func (c *Config) NotificationsPath() string {
+	return filepath.Join(c.DataDir(), NotificationsFileName)
+}
+
 func (c *Config) PIDPath() string {
 	return filepath.Join(c.DataDir(), PIDFilePath)
 }
 
+func (c *Config) HeartbeatPath() string {
+	return filepath.Join(c.DataDir(), HeartbeatFilePath)
+}
+
+func (c *Config) CrashDir() string {
+	return filepath.Join(c.LogsDir(), CrashDirPath)
+}
+
 func (c *Config) AgentDir() string {
 	return filepath.Join(c.DataDir(), AgentsDirPath, c.Agent)
 }
@@ -89,6 +242,14 @@ func (c *Config) WorkspaceDir() string {
 	return filepath.Join(c.AgentDir(), WorkspaceDirPath)
 }
 
+func (c *Config) WorkspaceTmpDir() string {
+	return filepath.Join(c.WorkspaceDir(), TmpDirPath)
+}
+
+func (c *Config) TrashDir() string {
+	return filepath.Join(c.AgentDir(), TrashDirPath)
+}
+
 func (c *Config) MemoryDir() string {
 	return filepath.Join(c.AgentDir(), MemoryDirPath)
 }
@@ -109,8 +270,31 @@ func (c *Config) CLIContextPath() string {
 	return filepath.Join(c.CLISessionDir(), DefaultSessionPath)
 }
 
-func (c *Config) TelegramContextPath() string {
-	return filepath.Join(c.SessionsDir(), "telegram", DefaultSessionPath)
+// TelegramChatContextPath is the conversation of one Telegram chat, named
+// by its scheduler channel key such as telegram-123456789. Each chat gets a
+// directory of its own so its forks stay apart from other chats'.
+func (c *Config) TelegramChatContextPath(chatKey string) string {
+	return filepath.Join(c.SessionsDir(), "telegram", chatKey, DefaultSessionPath)
+}
+
+func (c *Config) SlackContextPath() string {
+	return filepath.Join(c.SessionsDir(), SlackChannelName, DefaultSessionPath)
+}
+
+func (c *Config) MatrixContextPath() string {
+	return filepath.Join(c.SessionsDir(), MatrixChannelName, DefaultSessionPath)
+}
+
+func (c *Config) HTTPContextPath() string {
+	return filepath.Join(c.SessionsDir(), HTTPChannelName, DefaultSessionPath)
+}
+
+func (c *Config) WebContextPath() string {
+	return filepath.Join(c.SessionsDir(), WebChannelName, DefaultSessionPath)
+}
+
+func (c *Config) WhatsAppContextPath() string {
+	return filepath.Join(c.SessionsDir(), WhatsAppChannelName, DefaultSessionPath)
 }
 
 func (c *Config) JobsPath() string {
@@ -125,6 +309,51 @@ func (c *Config) UserPath() string {
 	return filepath.Join(c.AgentDir(), UserFilePath)
 }
 
+func (c *Config) CheckInsPath() string {
+	return filepath.Join(c.AgentDir(), CheckInsFilePath)
+}
+
+func (c *Config) PromptsDir() string {
+	return filepath.Join(c.AgentDir(), PromptsDirPath)
+}
+
+func (c *Config) WorkflowsDir() string {
+	return filepath.Join(c.AgentDir(), WorkflowsDirPath)
+}
+
+func (c *Config) WorkflowRunsDir() string {
+	return filepath.Join(c.WorkflowsDir(), WorkflowRunsPath)
+}
+
+func (c *Config) ArtifactsPath() string {
+	return filepath.Join(c.AgentDir(), ArtifactsFilePath)
+}
+
+func (c *Config) TasksPath() string {
+	return filepath.Join(c.AgentDir(), TasksFilePath)
+}
+
+func (c *Config) ListsPath() string {
+	return filepath.Join(c.AgentDir(), ListsFilePath)
+}
+
+func (c *Config) ExpensesPath() string {
+	return filepath.Join(c.AgentDir(), ExpensesFilePath)
+}
+
+func (c *Config) LanguagesPath() string {
+	return filepath.Join(c.AgentDir(), LanguagesFilePath)
+}
+
+func (c *Config) ToolOutputsDir() string {
+	return filepath.Join(c.AgentDir(), ToolOutputsDirPath)
+}
+
+// ChatToolOutputsDir holds the full tool outputs of one Telegram chat.
+func (c *Config) ChatToolOutputsDir(chatKey string) string {
+	return filepath.Join(c.ToolOutputsDir(), chatKey)
+}
+
 func (c *Config) MemoryPath() string {
 	return filepath.Join(c.MemoryDir(), MemoryFilePath)
 }
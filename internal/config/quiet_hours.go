@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours is a daily local-time window, such as 22:00-08:00, that may wrap
+// past midnight. The zero value is an empty window.
+type QuietHours struct {
+	start time.Duration
+	end   time.Duration
+	set   bool
+}
+
+// ParseQuietHours parses "HH:MM-HH:MM". An empty string disables quiet hours.
+func ParseQuietHours(spec string) (QuietHours, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return QuietHours{}, nil
+	}
+	startRaw, endRaw, ok := strings.Cut(spec, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q (expected HH:MM-HH:MM)", spec)
+	}
+	start, err := parseClock(startRaw)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: %w", spec, err)
+	}
+	end, err := parseClock(endRaw)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: %w", spec, err)
+	}
+	if start == end {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: start and end are equal", spec)
+	}
+	return QuietHours{start: start, end: end, set: true}, nil
+}
+
+// Contains reports whether t falls inside the window in t's location.
+func (q QuietHours) Contains(t time.Time) bool {
+	if !q.set {
+		return false
+	}
+	clock := sinceMidnight(t)
+	if q.start < q.end {
+		return clock >= q.start && clock < q.end
+	}
+	return clock >= q.start || clock < q.end
+}
+
+// End returns the first time at or after t when the window is over.
+func (q QuietHours) End(t time.Time) time.Time {
+	if !q.Contains(t) {
+		return t
+	}
+	midnight := t.Add(-sinceMidnight(t))
+	end := midnight.Add(q.end)
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+func parseClock(raw string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", strings.TrimSpace(raw))
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursWrapsMidnight(t *testing.T) {
+	q, err := ParseQuietHours("22:00-08:00")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+
+	if !q.Contains(day.Add(23 * time.Hour)) {
+		t.Fatalf("expected 23:00 to be quiet")
+	}
+	if !q.Contains(day.Add(7*time.Hour + 59*time.Minute)) {
+		t.Fatalf("expected 07:59 to be quiet")
+	}
+	if q.Contains(day.Add(8 * time.Hour)) {
+		t.Fatalf("expected 08:00 not to be quiet")
+	}
+	if q.Contains(day.Add(12 * time.Hour)) {
+		t.Fatalf("expected noon not to be quiet")
+	}
+
+	if got, want := q.End(day.Add(23*time.Hour)), day.AddDate(0, 0, 1).Add(8*time.Hour); !got.Equal(want) {
+		t.Fatalf("End(23:00) = %s, want %s", got, want)
+	}
+	if got, want := q.End(day.Add(2*time.Hour)), day.Add(8*time.Hour); !got.Equal(want) {
+		t.Fatalf("End(02:00) = %s, want %s", got, want)
+	}
+	noon := day.Add(12 * time.Hour)
+	if got := q.End(noon); !got.Equal(noon) {
+		t.Fatalf("End outside window should return input, got %s", got)
+	}
+}
+
+func TestQuietHoursSameDayWindow(t *testing.T) {
+	q, err := ParseQuietHours("13:00-14:30")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	if !q.Contains(day.Add(14 * time.Hour)) {
+		t.Fatalf("expected 14:00 to be quiet")
+	}
+	if q.Contains(day.Add(15 * time.Hour)) {
+		t.Fatalf("expected 15:00 not to be quiet")
+	}
+}
+
+func TestParseQuietHoursEmptyAndInvalid(t *testing.T) {
+	q, err := ParseQuietHours("")
+	if err != nil {
+		t.Fatalf("parse empty: %v", err)
+	}
+	if q.Contains(time.Now()) {
+		t.Fatalf("expected empty quiet hours to never match")
+	}
+	for _, spec := range []string{"22:00", "25:00-08:00", "08:00-08:00", "late-early"} {
+		if _, err := ParseQuietHours(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
@@ -13,6 +13,16 @@ var (
 	_ Validatable = CostsConfig{}
 	_ Validatable = ContextConfig{}
 	_ Validatable = WebConfig{}
+	_ Validatable = ProactiveConfig{}
+	_ Validatable = NotificationsConfig{}
+	_ Validatable = WorkspaceConfig{}
+	_ Validatable = PrivacyConfig{}
+	_ Validatable = MemoryConfig{}
+	_ Validatable = StorageConfig{}
+	_ Validatable = TranscriptionConfig{}
+	_ Validatable = TranslationConfig{}
+	_ Validatable = EmbeddingsConfig{}
+	_ Validatable = RerankConfig{}
 )
 
 func TestValidateStartup_HardFailNoLLM(t *testing.T) {
@@ -54,6 +64,63 @@ func TestValidateStartup_AnthropicRequiresAPIKey(t *testing.T) {
 	}
 }
 
+func TestValidateStartup_AnthropicAcceptsAuthToken(t *testing.T) {
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", AuthToken: "tok", Model: "m", RequestTimeout: time.Second}},
+		Channels: map[string]ChannelConfig{"telegram": {Enabled: true, Token: "t"}},
+		Security: SecurityConfig{Mode: SecurityModeStandard},
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected auth_token to satisfy anthropic credentials, got %v", err)
+	}
+}
+
+func TestValidateStartup_LLMFallbackMustNameProfile(t *testing.T) {
+	cfg := &Config{
+		LLM: map[string]LLMProviderConfig{
+			"default": {Provider: "anthropic", APIKey: "k", Model: "m", Fallback: "backup"},
+		},
+		Channels: map[string]ChannelConfig{"telegram": {Enabled: true, Token: "t"}},
+		Security: SecurityConfig{Mode: SecurityModeStandard},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), `fallback "backup" must name another llm profile`) {
+		t.Fatalf("expected missing fallback error, got %v", err)
+	}
+
+	cfg.LLM["backup"] = LLMProviderConfig{Provider: "openrouter", APIKey: "k", Model: "m"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected fallback profile to be valid, got %v", err)
+	}
+}
+
+func TestValidateStartup_AnthropicOAuth(t *testing.T) {
+	oauth := OAuthConfig{
+		ClientID:               "neoclaw",
+		DeviceAuthorizationURL: "https://auth.example.com/device",
+		TokenURL:               "https://auth.example.com/token",
+	}
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", OAuth: oauth, Model: "m", RequestTimeout: time.Second}},
+		Channels: map[string]ChannelConfig{"telegram": {Enabled: true, Token: "t"}},
+		Security: SecurityConfig{Mode: SecurityModeStandard},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected oauth to satisfy anthropic credentials, got %v", err)
+	}
+
+	oauth.TokenURL = ""
+	cfg.LLM["default"] = LLMProviderConfig{Provider: "anthropic", OAuth: oauth, Model: "m", RequestTimeout: time.Second}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "token_url") {
+		t.Fatalf("expected token_url validation error, got %v", err)
+	}
+
+	cfg.LLM["default"] = LLMProviderConfig{Provider: "openrouter", APIKey: "k", OAuth: oauth, Model: "m", RequestTimeout: time.Second}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "only supported by the anthropic provider") {
+		t.Fatalf("expected oauth to be refused for openrouter, got %v", err)
+	}
+}
+
 func TestValidateStartup_OllamaDoesNotRequireAPIKey(t *testing.T) {
 	cfg := &Config{
 		LLM:      map[string]LLMProviderConfig{"default": {Provider: "ollama", APIKey: "", Model: "llama3", RequestTimeout: time.Second}},
@@ -276,6 +343,27 @@ func TestValidateStartup_NegativeNumericFieldsFail(t *testing.T) {
 			},
 			wantErr: "recent_messages must be >= 0",
 		},
+		{
+			name: "context.max_turn_tokens",
+			mutate: func(cfg *Config) {
+				cfg.Context.MaxTurnTokens = -1
+			},
+			wantErr: "max_turn_tokens must be >= 0",
+		},
+		{
+			name: "context.max_turn_duration",
+			mutate: func(cfg *Config) {
+				cfg.Context.MaxTurnDuration = -1 * time.Second
+			},
+			wantErr: "max_turn_duration must be >= 0",
+		},
+		{
+			name: "context.progress_update_after",
+			mutate: func(cfg *Config) {
+				cfg.Context.ProgressUpdateAfter = -1 * time.Second
+			},
+			wantErr: "progress_update_after must be >= 0",
+		},
 	}
 
 	for _, tc := range cases {
@@ -293,6 +381,233 @@ func TestValidateStartup_NegativeNumericFieldsFail(t *testing.T) {
 	}
 }
 
+func TestSecurityConfigValidateUnlistedBins(t *testing.T) {
+	for _, value := range []string{"", UnlistedBinsAllow, UnlistedBinsPrompt, "Deny"} {
+		if err := (SecurityConfig{Mode: SecurityModeStrict, UnlistedBins: value}).Validate(); err != nil {
+			t.Fatalf("expected unlisted_bins %q to be valid, got %v", value, err)
+		}
+	}
+	if err := (SecurityConfig{Mode: SecurityModeStrict, UnlistedBins: "block"}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid security.unlisted_bins") {
+		t.Fatalf("expected unlisted_bins error, got %v", err)
+	}
+}
+
+func TestSecurityConfigValidateInlineScripts(t *testing.T) {
+	for _, value := range []string{"", InlineScriptsPolicy, InlineScriptsPrompt, "Sandbox"} {
+		if err := (SecurityConfig{Mode: SecurityModeStrict, InlineScripts: value}).Validate(); err != nil {
+			t.Fatalf("expected inline_scripts %q to be valid, got %v", value, err)
+		}
+	}
+	if err := (SecurityConfig{Mode: SecurityModeStrict, InlineScripts: "allow"}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid security.inline_scripts") {
+		t.Fatalf("expected inline_scripts error, got %v", err)
+	}
+}
+
+func TestProactiveConfigValidate(t *testing.T) {
+	if err := (ProactiveConfig{Enabled: false, Schedule: ""}).Validate(); err != nil {
+		t.Fatalf("expected disabled proactive config to skip schedule validation, got %v", err)
+	}
+	if err := (ProactiveConfig{Enabled: true, Schedule: "0 10 * * *"}).Validate(); err != nil {
+		t.Fatalf("expected valid proactive config, got %v", err)
+	}
+	if err := (ProactiveConfig{Enabled: true, Schedule: "bogus"}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid schedule") {
+		t.Fatalf("expected invalid schedule error, got %v", err)
+	}
+	if err := (ProactiveConfig{MaxPerDay: -1}).Validate(); err == nil || !strings.Contains(err.Error(), "max_per_day must be >= 0") {
+		t.Fatalf("expected max_per_day error, got %v", err)
+	}
+}
+
+func TestCLIConfigValidate(t *testing.T) {
+	if err := (CLIConfig{Bell: true, NotifyAfter: 10 * time.Second}).Validate(); err != nil {
+		t.Fatalf("expected valid cli config, got %v", err)
+	}
+	if err := (CLIConfig{NotifyAfter: -time.Second}).Validate(); err == nil {
+		t.Fatalf("expected negative notify_after error")
+	}
+}
+
+func TestNotificationsConfigValidate(t *testing.T) {
+	if err := (NotificationsConfig{}).Validate(); err != nil {
+		t.Fatalf("expected empty quiet hours to be valid, got %v", err)
+	}
+	if err := (NotificationsConfig{QuietHours: "22:00-07:00"}).Validate(); err != nil {
+		t.Fatalf("expected valid quiet hours, got %v", err)
+	}
+	if err := (NotificationsConfig{QuietHours: "late"}).Validate(); err == nil {
+		t.Fatalf("expected invalid quiet hours error")
+	}
+}
+
+func TestChannelConfigValidateResponseFormat(t *testing.T) {
+	if err := (ChannelConfig{Enabled: true, Token: "t", ResponseFormat: ResponseFormatJSON, ResponseSchema: "schema.json"}).Validate(); err != nil {
+		t.Fatalf("expected json response format to be valid, got %v", err)
+	}
+	if err := (ChannelConfig{Enabled: true, Token: "t", ResponseFormat: "xml"}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid response_format") {
+		t.Fatalf("expected response_format error, got %v", err)
+	}
+	if err := (ChannelConfig{Enabled: true, Token: "t", ResponseSchema: "schema.json"}).Validate(); err == nil || !strings.Contains(err.Error(), "response_schema requires") {
+		t.Fatalf("expected response_schema error, got %v", err)
+	}
+	if err := (ChannelConfig{Enabled: true, Token: "t", MaxReplyLength: -1}).Validate(); err == nil || !strings.Contains(err.Error(), "max_reply_length must be >= 0") {
+		t.Fatalf("expected max_reply_length error, got %v", err)
+	}
+	if err := (ChannelConfig{Enabled: true, Token: "t", MaxReplyLength: 5, Signature: "-- bot"}).Validate(); err == nil || !strings.Contains(err.Error(), "room for the signature") {
+		t.Fatalf("expected signature length error, got %v", err)
+	}
+	if err := (ChannelConfig{Enabled: true, Token: "t", MaxReplyLength: 4000, Signature: "-- bot", StripReasoning: true}).Validate(); err != nil {
+		t.Fatalf("expected post-processing settings to be valid, got %v", err)
+	}
+}
+
+func TestChannelConfigValidateWebhook(t *testing.T) {
+	if err := (ChannelConfig{Enabled: true, Token: "t", WebhookURL: "https://bot.example.com/telegram", WebhookListen: "127.0.0.1:8080"}).Validate(); err != nil {
+		t.Fatalf("expected reverse proxy webhook to be valid, got %v", err)
+	}
+	if err := (ChannelConfig{Enabled: true, Token: "t", WebhookURL: "http://bot.example.com/telegram"}).Validate(); err == nil || !strings.Contains(err.Error(), "https://") {
+		t.Fatalf("expected https error, got %v", err)
+	}
+	if err := (ChannelConfig{Enabled: true, Token: "t", WebhookURL: "https://bot.example.com", WebhookCertFile: "cert.pem"}).Validate(); err == nil || !strings.Contains(err.Error(), "set together") {
+		t.Fatalf("expected cert and key error, got %v", err)
+	}
+}
+
+func TestValidateStartup_TelegramBotsNeedTheirOwnTokenAndAgent(t *testing.T) {
+	newConfig := func(channels map[string]ChannelConfig) *Config {
+		return &Config{
+			LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", APIKey: "k", Model: "m", RequestTimeout: time.Second}},
+			Channels: channels,
+			Security: SecurityConfig{Mode: SecurityModeStandard},
+		}
+	}
+
+	cfg := newConfig(map[string]ChannelConfig{
+		"telegram":      {Enabled: true, Token: "home"},
+		"telegram_work": {Enabled: true, Token: "work", Agent: "work"},
+	})
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected two bots to be valid, got %v", err)
+	}
+	if bots := cfg.TelegramBots(); len(bots) != 2 || bots[0] != "telegram" || bots[1] != "telegram_work" {
+		t.Fatalf("unexpected bots %v", bots)
+	}
+
+	cfg = newConfig(map[string]ChannelConfig{
+		"telegram":      {Enabled: true, Token: "home"},
+		"telegram_work": {Enabled: true, Token: "work"},
+	})
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), `agent "default" is already served`) {
+		t.Fatalf("expected shared agent error, got %v", err)
+	}
+
+	cfg = newConfig(map[string]ChannelConfig{
+		"telegram":      {Enabled: true, Token: "same"},
+		"telegram_work": {Enabled: true, Token: "same", Agent: "work"},
+	})
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "token is already used") {
+		t.Fatalf("expected shared token error, got %v", err)
+	}
+
+	cfg = newConfig(map[string]ChannelConfig{
+		"telegram":      {Enabled: false},
+		"telegram_work": {Enabled: true, Token: "work", Agent: "../work"},
+	})
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid agent") {
+		t.Fatalf("expected invalid agent error, got %v", err)
+	}
+}
+
+func TestValidateStartup_SlackNeedsAppToken(t *testing.T) {
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", APIKey: "k", Model: "m", RequestTimeout: time.Second}},
+		Channels: map[string]ChannelConfig{"slack": {Enabled: true, Token: "xoxb-1"}},
+		Security: SecurityConfig{Mode: SecurityModeStandard},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "channels.slack: app_token is required") {
+		t.Fatalf("expected app_token error, got %v", err)
+	}
+	cfg.Channels["slack"] = ChannelConfig{Enabled: true, Token: "xoxb-1", AppToken: "xapp-1"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected slack config to be valid, got %v", err)
+	}
+	if bots := cfg.TelegramBots(); len(bots) != 0 {
+		t.Fatalf("expected slack not to count as a telegram bot, got %v", bots)
+	}
+}
+
+func TestValidateStartup_MatrixNeedsHomeserverURL(t *testing.T) {
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", APIKey: "k", Model: "m", RequestTimeout: time.Second}},
+		Channels: map[string]ChannelConfig{"matrix": {Enabled: true, Token: "syt-1", Homeserver: "matrix.example.org"}},
+		Security: SecurityConfig{Mode: SecurityModeStandard},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "channels.matrix: homeserver must be") {
+		t.Fatalf("expected homeserver error, got %v", err)
+	}
+	cfg.Channels["matrix"] = ChannelConfig{Enabled: true, Token: "syt-1", Homeserver: "http://127.0.0.1:8009"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected matrix config to be valid, got %v", err)
+	}
+}
+
+func TestWorkspaceConfigValidate(t *testing.T) {
+	if err := (WorkspaceConfig{}).Validate(); err != nil {
+		t.Fatalf("expected zero workspace config to be valid, got %v", err)
+	}
+	if err := (WorkspaceConfig{TmpMaxAge: time.Hour, TmpMaxSizeMB: 10, CleanupSchedule: "0 3 * * *"}).Validate(); err != nil {
+		t.Fatalf("expected valid workspace config, got %v", err)
+	}
+	if err := (WorkspaceConfig{TmpMaxAge: -time.Hour}).Validate(); err == nil || !strings.Contains(err.Error(), "tmp_max_age must be >= 0") {
+		t.Fatalf("expected tmp_max_age error, got %v", err)
+	}
+	if err := (WorkspaceConfig{TmpMaxSizeMB: -1}).Validate(); err == nil || !strings.Contains(err.Error(), "tmp_max_size_mb must be >= 0") {
+		t.Fatalf("expected tmp_max_size_mb error, got %v", err)
+	}
+	if err := (WorkspaceConfig{TrashRetention: -time.Hour}).Validate(); err == nil || !strings.Contains(err.Error(), "trash_retention must be >= 0") {
+		t.Fatalf("expected trash_retention error, got %v", err)
+	}
+	if err := (WorkspaceConfig{CleanupSchedule: "nightly"}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid cleanup_schedule") {
+		t.Fatalf("expected cleanup_schedule error, got %v", err)
+	}
+}
+
+func TestPrivacyConfigValidate(t *testing.T) {
+	if err := (PrivacyConfig{Redact: true, RedactKinds: []string{"email", "card"}, OutboundSecrets: "block", RedactPatterns: []string{`ACCT-\d+`}}).Validate(); err != nil {
+		t.Fatalf("expected valid privacy config, got %v", err)
+	}
+	if err := (PrivacyConfig{RedactKinds: []string{"ssn"}}).Validate(); err == nil || !strings.Contains(err.Error(), "unknown redaction kind") {
+		t.Fatalf("expected redact_kinds error, got %v", err)
+	}
+	if err := (PrivacyConfig{RedactPatterns: []string{"("}}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid redaction pattern") {
+		t.Fatalf("expected redact_patterns error, got %v", err)
+	}
+	if err := (PrivacyConfig{OutboundSecrets: "warn"}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid outbound_secrets") {
+		t.Fatalf("expected outbound_secrets error, got %v", err)
+	}
+	if err := (PrivacyConfig{MemoryWrites: "ask"}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid memory_writes") {
+		t.Fatalf("expected memory_writes error, got %v", err)
+	}
+	if r, err := (PrivacyConfig{RedactKinds: []string{"email"}}).Redactor(); err != nil || r != nil {
+		t.Fatalf("expected no redactor while redact is off, got %v err=%v", r, err)
+	}
+}
+
+func TestMemoryConfigValidate(t *testing.T) {
+	valid := MemoryConfig{
+		RetentionSchedule: "45 3 * * *",
+		Tags:              map[string]MemoryTagConfig{"health": {RetentionDays: 1825, ExcludeFromPrompt: true}},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid memory config, got %v", err)
+	}
+	if err := (MemoryConfig{Tags: map[string]MemoryTagConfig{"chores": {RetentionDays: -1}}}).Validate(); err == nil || !strings.Contains(err.Error(), "tags.chores: retention_days") {
+		t.Fatalf("expected retention_days error, got %v", err)
+	}
+	if err := (MemoryConfig{RetentionSchedule: "daily"}).Validate(); err == nil || !strings.Contains(err.Error(), "invalid retention_schedule") {
+		t.Fatalf("expected retention_schedule error, got %v", err)
+	}
+}
+
 func TestValidateStartup_WebSearchProviderAllowlist(t *testing.T) {
 	cfg := &Config{
 		LLM: map[string]LLMProviderConfig{
@@ -312,3 +627,237 @@ func TestValidateStartup_WebSearchProviderAllowlist(t *testing.T) {
 		t.Fatalf("expected unsupported provider error, got %v", err)
 	}
 }
+
+func TestStorageConfigValidate(t *testing.T) {
+	valid := []StorageConfig{
+		{},
+		{Backend: StorageBackendLocal},
+		{Backend: StorageBackendS3, S3: S3StorageConfig{Region: "us-east-1", Bucket: "b", AccessKeyID: "k", SecretAccessKey: "s"}},
+		{Backend: StorageBackendWebDAV, WebDAV: WebDAVStorageConfig{URL: "https://dav.example.com/neoclaw/"}},
+	}
+	for _, cfg := range valid {
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("expected valid storage config %#v, got %v", cfg, err)
+		}
+	}
+
+	invalid := map[string]StorageConfig{
+		"invalid backend":                 {Backend: "ftp"},
+		"s3.bucket is required":           {Backend: StorageBackendS3, S3: S3StorageConfig{Region: "us-east-1", AccessKeyID: "k", SecretAccessKey: "s"}},
+		"s3.access_key_id":                {Backend: StorageBackendS3, S3: S3StorageConfig{Region: "us-east-1", Bucket: "b"}},
+		"s3.endpoint: must be an http(s)": {Backend: StorageBackendS3, S3: S3StorageConfig{Region: "us-east-1", Bucket: "b", AccessKeyID: "k", SecretAccessKey: "s", Endpoint: "minio:9000"}},
+		"webdav.url: is required":         {Backend: StorageBackendWebDAV},
+	}
+	for want, cfg := range invalid {
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+
+	if got := (S3StorageConfig{Region: "eu-west-1"}).S3Endpoint(); got != "https://s3.eu-west-1.amazonaws.com" {
+		t.Fatalf("unexpected default endpoint %q", got)
+	}
+}
+
+func TestTranscriptionConfigValidate(t *testing.T) {
+	valid := []TranscriptionConfig{
+		{},
+		defaultConfig.Transcription,
+		{Endpoint: "http://localhost:8080/v1/audio/transcriptions", ChunkDuration: 0},
+	}
+	for _, cfg := range valid {
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("expected valid transcription config %#v, got %v", cfg, err)
+		}
+	}
+
+	invalid := map[string]TranscriptionConfig{
+		"endpoint: must be an http(s)": {Endpoint: "whisper:8080"},
+		"must be >= 0":                 {ChunkDuration: -time.Minute},
+		"at least 1m":                  {ChunkDuration: 30 * time.Second},
+	}
+	for want, cfg := range invalid {
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestEmbeddingsConfigValidate(t *testing.T) {
+	valid := []EmbeddingsConfig{
+		{},
+		{Provider: "lexical", Dimensions: 512},
+		{Provider: "Ollama", Endpoint: "http://nas.local:11434/api/embed", Model: "nomic-embed-text"},
+		{Provider: "voyage", APIKey: "pa-key"},
+	}
+	for _, cfg := range valid {
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("expected valid embeddings config %#v, got %v", cfg, err)
+		}
+	}
+
+	invalid := map[string]EmbeddingsConfig{
+		"unsupported provider":         {Provider: "word2vec"},
+		"endpoint: must be an http(s)": {Provider: "ollama", Endpoint: "localhost:11434"},
+		"dimensions must be between":   {Provider: "lexical", Dimensions: 8},
+	}
+	for want, cfg := range invalid {
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestRerankConfigValidate(t *testing.T) {
+	valid := []RerankConfig{
+		{},
+		{Provider: "local", Candidates: 50},
+		{Provider: "Cohere", Endpoint: "https://api.jina.ai/v1/rerank", APIKey: "jina-key"},
+	}
+	for _, cfg := range valid {
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("expected valid rerank config %#v, got %v", cfg, err)
+		}
+	}
+
+	invalid := map[string]RerankConfig{
+		"unsupported provider":       {Provider: "bm42"},
+		"candidates must be between": {Provider: "local", Candidates: 1000},
+	}
+	for want, cfg := range invalid {
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestTranslationConfigValidate(t *testing.T) {
+	valid := []TranslationConfig{
+		{},
+		{Provider: "libretranslate", Endpoint: "http://localhost:5000"},
+		{Provider: "DeepL", APIKey: "key:fx"},
+	}
+	for _, cfg := range valid {
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("expected valid translation config %#v, got %v", cfg, err)
+		}
+	}
+
+	invalid := map[string]TranslationConfig{
+		"unsupported provider":         {Provider: "babelfish"},
+		"endpoint: must be an http(s)": {Provider: "libretranslate", Endpoint: "localhost:5000"},
+	}
+	for want, cfg := range invalid {
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestValidateStartup_HTTPNeedsLongAPIKeys(t *testing.T) {
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", APIKey: "k", Model: "m", RequestTimeout: time.Second}},
+		Channels: map[string]ChannelConfig{"http": {Enabled: true}},
+		Security: SecurityConfig{Mode: SecurityModeStandard},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "channels.http: api_keys is required") {
+		t.Fatalf("expected api_keys error, got %v", err)
+	}
+	cfg.Channels["http"] = ChannelConfig{Enabled: true, APIKeys: []string{"short"}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "api_keys[0] must be at least") {
+		t.Fatalf("expected key length error, got %v", err)
+	}
+	cfg.Channels["http"] = ChannelConfig{Enabled: true, APIKeys: []string{strings.Repeat("k", 32)}, Listen: "8787"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "listen must be host:port") {
+		t.Fatalf("expected listen error, got %v", err)
+	}
+	cfg.Channels["http"] = ChannelConfig{Enabled: true, APIKeys: []string{strings.Repeat("k", 32)}, Listen: ":8787"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected http config to be valid without a token, got %v", err)
+	}
+}
+
+func TestValidateStartup_WebTokenIsOptional(t *testing.T) {
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", APIKey: "k", Model: "m", RequestTimeout: time.Second}},
+		Channels: map[string]ChannelConfig{"web": {Enabled: true}},
+		Security: SecurityConfig{Mode: SecurityModeStandard},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected web config to be valid without a token, got %v", err)
+	}
+	cfg.Channels["web"] = ChannelConfig{Enabled: true, Token: "short"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "channels.web: token must be at least") {
+		t.Fatalf("expected token length error, got %v", err)
+	}
+}
+
+func TestValidateStartup_WhatsAppNeedsWebhookSecrets(t *testing.T) {
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", APIKey: "k", Model: "m", RequestTimeout: time.Second}},
+		Channels: map[string]ChannelConfig{"whatsapp": {Enabled: true, Token: "t", PhoneNumberID: "123", AppSecret: "s"}},
+		Security: SecurityConfig{Mode: SecurityModeStandard},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "channels.whatsapp: verify_token is required") {
+		t.Fatalf("expected verify_token error, got %v", err)
+	}
+	cfg.Channels["whatsapp"] = ChannelConfig{Enabled: true, Token: "t", PhoneNumberID: "123", AppSecret: "s", VerifyToken: "v"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected whatsapp config to be valid, got %v", err)
+	}
+}
+
+func TestValidateStartup_ProjectsStayInWorkspace(t *testing.T) {
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", APIKey: "k", Model: "m", RequestTimeout: time.Second}},
+		Channels: map[string]ChannelConfig{"telegram": {Enabled: true, Token: "t"}},
+		Security: SecurityConfig{Mode: SecurityModeStandard},
+		Projects: map[string]ProjectConfig{"blog": {Path: "../blog"}},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "projects.blog: path must be relative") {
+		t.Fatalf("expected path error, got %v", err)
+	}
+	cfg.Projects["blog"] = ProjectConfig{Path: "blog", Pins: []string{"/etc/passwd"}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "pins[0]") {
+		t.Fatalf("expected pin error, got %v", err)
+	}
+	cfg.Projects["blog"] = ProjectConfig{Path: "blog", Pins: []string{"README.md"}, AllowedCommands: []string{"*"}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "allowed_commands[0]") {
+		t.Fatalf("expected allowed_commands error, got %v", err)
+	}
+	cfg.Projects["blog"] = ProjectConfig{Path: "blog", Pins: []string{"README.md"}, AllowedCommands: []string{"hugo *"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected project to be valid, got %v", err)
+	}
+}
+
+func TestValidateStartup_CodeReviewMustNameProfile(t *testing.T) {
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", APIKey: "k", Model: "m"}},
+		Channels: map[string]ChannelConfig{"telegram": {Enabled: true, Token: "t"}},
+		Security: SecurityConfig{Mode: SecurityModeStandard, CodeReview: "cheap"},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), `security.code_review "cheap" must name an llm profile`) {
+		t.Fatalf("expected missing code review profile error, got %v", err)
+	}
+
+	cfg.LLM["cheap"] = LLMProviderConfig{Provider: "openrouter", APIKey: "k", Model: "m"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected code review profile to be valid, got %v", err)
+	}
+}
+
+func TestValidateStartup_TrustLevels(t *testing.T) {
+	cfg := &Config{
+		LLM:      map[string]LLMProviderConfig{"default": {Provider: "anthropic", APIKey: "k", Model: "m"}},
+		Channels: map[string]ChannelConfig{"telegram": {Enabled: true, Token: "t"}},
+		Security: SecurityConfig{Mode: SecurityModeStandard, Trust: map[string]string{"read_file": "internet"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected a valid trust override, got %v", err)
+	}
+	cfg.Security.Trust["read_file"] = "high"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid security.trust.read_file high") {
+		t.Fatalf("expected invalid trust level error, got %v", err)
+	}
+}
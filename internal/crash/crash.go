@@ -0,0 +1,100 @@
+// Package crash writes crash reports: the panic or fatal error, the stack,
+// the recent log tail, and the config with credentials redacted, in one
+// file that can be attached to a bug report.
+package crash
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+var (
+	mu      sync.Mutex
+	dir     string
+	version string
+	config  func() (string, error)
+)
+
+// Configure sets where reports go and how to render the redacted config.
+// Until it is called, reports are not written.
+func Configure(reportDir, buildVersion string, redactedConfig func() (string, error)) {
+	mu.Lock()
+	defer mu.Unlock()
+	dir, version, config = reportDir, buildVersion, redactedConfig
+}
+
+// Write saves a report and returns its path.
+func Write(reason string, stack []byte) (string, error) {
+	mu.Lock()
+	reportDir, buildVersion, renderConfig := dir, version, config
+	mu.Unlock()
+	if reportDir == "" {
+		return "", fmt.Errorf("crash reports are not configured")
+	}
+
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "NeoClaw crash report\n\n")
+	fmt.Fprintf(&b, "time:    %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "version: %s\n", buildVersion)
+	fmt.Fprintf(&b, "go:      %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "reason:  %s\n", reason)
+	fmt.Fprintf(&b, "\n== stack ==\n%s\n", strings.TrimRight(string(stack), "\n"))
+	fmt.Fprintf(&b, "\n== recent log ==\n%s\n", strings.Join(logging.Recent(), "\n"))
+	b.WriteString("\n== config (credentials redacted) ==\n")
+	if renderConfig == nil {
+		b.WriteString("(unavailable)\n")
+	} else if text, err := renderConfig(); err != nil {
+		fmt.Fprintf(&b, "(unavailable: %v)\n", err)
+	} else {
+		b.WriteString(text)
+	}
+
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	path := filepath.Join(reportDir, fmt.Sprintf("crash-%s-%s.txt", now.Format("20060102-150405"), hex.EncodeToString(suffix)))
+	if err := os.MkdirAll(reportDir, 0o700); err != nil {
+		return "", fmt.Errorf("create crash report dir: %w", err)
+	}
+	// Reports include the log tail, so keep them private to the user.
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return "", fmt.Errorf("write crash report: %w", err)
+	}
+	return path, nil
+}
+
+// Recovered reports a value returned by recover() from where, logs it, and
+// returns the report path, or "" if the report could not be written. Call
+// it from the deferred function that recovered.
+func Recovered(where string, recovered any) string {
+	return report(fmt.Sprintf("panic in %s: %v", where, recovered), debug.Stack())
+}
+
+// Fatal reports an error that stops the server, with every goroutine's
+// stack, logs it, and returns the report path, or "" if it could not be
+// written.
+func Fatal(cause error) string {
+	stack := make([]byte, 1<<20)
+	stack = stack[:runtime.Stack(stack, true)]
+	return report(fmt.Sprintf("fatal error: %v", cause), stack)
+}
+
+func report(reason string, stack []byte) string {
+	path, err := Write(reason, stack)
+	if err != nil {
+		logging.Logger().Error(reason, "crash_report_err", err)
+		return ""
+	}
+	logging.Logger().Error(reason, "crash_report", path)
+	return path
+}
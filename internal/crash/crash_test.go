@@ -0,0 +1,58 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+func TestRecoveredWritesReport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "crash")
+	Configure(dir, "1.2.3", func() (string, error) {
+		return "[llm.default]\napi_key = '[redacted]'\n", nil
+	})
+	t.Cleanup(func() { Configure("", "", nil) })
+	logging.Logger().Info("handling message", "chat", 42)
+
+	var path string
+	func() {
+		defer func() {
+			path = Recovered("test handler", recover())
+		}()
+		var m map[string]int
+		m["boom"]++
+	}()
+
+	if filepath.Dir(path) != dir {
+		t.Fatalf("unexpected report path %q", path)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	report := string(raw)
+	for _, want := range []string{
+		"version: 1.2.3",
+		"reason:  panic in test handler: assignment to entry in nil map",
+		"crash.TestRecoveredWritesReport",
+		`msg="handling message" chat=42`,
+		"api_key = '[redacted]'",
+	} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("expected %q in report:\n%s", want, report)
+		}
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected private report, got %v", info.Mode().Perm())
+	}
+}
+
+func TestWriteRequiresConfigure(t *testing.T) {
+	if _, err := Write("boom", nil); err == nil {
+		t.Fatalf("expected error before Configure")
+	}
+}
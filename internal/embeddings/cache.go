@@ -0,0 +1,71 @@
+package embeddings
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultCacheSize bounds the vectors a Cache keeps; at 256 lexical
+// dimensions that is about 20 MB.
+const defaultCacheSize = 20000
+
+// Cache remembers vectors by text, so searching the same notes again only
+// embeds what is new. It is safe for concurrent use.
+type Cache struct {
+	embedder Embedder
+	max      int
+
+	mu      sync.Mutex
+	vectors map[string][]float32
+}
+
+// NewCache wraps embedder; max <= 0 uses the default size. When the cache
+// is full it starts over rather than tracking which vectors are oldest.
+func NewCache(embedder Embedder, max int) *Cache {
+	if max <= 0 {
+		max = defaultCacheSize
+	}
+	return &Cache{embedder: embedder, max: max, vectors: map[string][]float32{}}
+}
+
+// Model returns the wrapped embedder's model.
+func (c *Cache) Model() string {
+	return c.embedder.Model()
+}
+
+// Embed returns cached vectors and embeds the rest in one call.
+func (c *Cache) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	var missing []string
+	var missingAt []int
+	c.mu.Lock()
+	for i, text := range texts {
+		if vector, ok := c.vectors[text]; ok {
+			vectors[i] = vector
+			continue
+		}
+		missing = append(missing, text)
+		missingAt = append(missingAt, i)
+	}
+	c.mu.Unlock()
+	if len(missing) == 0 {
+		return vectors, nil
+	}
+
+	embedded, err := c.embedder.Embed(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.vectors)+len(missing) > c.max {
+		c.vectors = map[string][]float32{}
+	}
+	for j, i := range missingAt {
+		vectors[i] = embedded[j]
+		if len(missing) <= c.max {
+			c.vectors[missing[j]] = embedded[j]
+		}
+	}
+	return vectors, nil
+}
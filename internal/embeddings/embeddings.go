@@ -0,0 +1,92 @@
+// Package embeddings turns text into vectors for similarity search. Remote
+// backends call an embeddings API; the lexical backend needs no network or
+// model files.
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+// Embedder turns texts into vectors.
+type Embedder interface {
+	// Embed returns one vector per text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model identifies the backend and model. Vectors from different models
+	// cannot be compared, so callers store it next to saved vectors and
+	// re-embed when it changes.
+	Model() string
+}
+
+const (
+	openAIEndpoint = "https://api.openai.com/v1/embeddings"
+	openAIModel    = "text-embedding-3-small"
+	voyageEndpoint = "https://api.voyageai.com/v1/embeddings"
+	voyageModel    = "voyage-3.5-lite"
+	ollamaEndpoint = "http://localhost:11434/api/embed"
+	ollamaModel    = "nomic-embed-text"
+)
+
+// NewFromConfig builds the embedder selected in [embeddings].
+func NewFromConfig(cfg config.EmbeddingsConfig, client *http.Client) (Embedder, error) {
+	provider := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	if provider == "" || provider == "lexical" {
+		return NewLexical(cfg.Dimensions), nil
+	}
+	if client == nil {
+		return nil, errors.New("http client is required")
+	}
+	endpoint := strings.TrimSpace(cfg.Endpoint)
+	model := strings.TrimSpace(cfg.Model)
+	switch provider {
+	case "openai", "voyage":
+		defaultEndpoint, defaultModel := openAIEndpoint, openAIModel
+		if provider == "voyage" {
+			defaultEndpoint, defaultModel = voyageEndpoint, voyageModel
+		}
+		if endpoint == "" {
+			endpoint = defaultEndpoint
+		}
+		if model == "" {
+			model = defaultModel
+		}
+		if cfg.APIKey == "" && endpoint == defaultEndpoint {
+			return nil, fmt.Errorf("embeddings.api_key is required for %s", provider)
+		}
+		return &openAIEmbedder{client: client, endpoint: endpoint, apiKey: cfg.APIKey, model: model, label: provider}, nil
+	case "ollama":
+		if endpoint == "" {
+			endpoint = ollamaEndpoint
+		}
+		if model == "" {
+			model = ollamaModel
+		}
+		return &ollamaEmbedder{client: client, endpoint: endpoint, model: model}, nil
+	default:
+		return nil, fmt.Errorf("unsupported embeddings.provider %s", cfg.Provider)
+	}
+}
+
+// Cosine returns the cosine similarity of two vectors, or 0 when their
+// lengths differ or either is all zeros.
+func Cosine(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / math.Sqrt(normA*normB))
+}
@@ -0,0 +1,164 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+func TestLexicalRanksSharedWordingHigher(t *testing.T) {
+	embedder := NewLexical(0)
+	vectors, err := embedder.Embed(context.Background(), []string{
+		"remind me to water the plants",
+		"plant watering reminder for Sunday",
+		"quarterly tax payment is due",
+		"",
+	})
+	if err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if len(vectors[0]) != defaultDimensions || embedder.Model() != "lexical:hash-256" {
+		t.Fatalf("unexpected size %d or model %q", len(vectors[0]), embedder.Model())
+	}
+	related, unrelated := Cosine(vectors[0], vectors[1]), Cosine(vectors[0], vectors[2])
+	if related <= unrelated {
+		t.Fatalf("expected related texts to score higher: %f <= %f", related, unrelated)
+	}
+	if self := Cosine(vectors[0], vectors[0]); self < 0.999 {
+		t.Fatalf("expected unit vectors, self similarity %f", self)
+	}
+	if empty := Cosine(vectors[0], vectors[3]); empty != 0 {
+		t.Fatalf("expected empty text to score 0, got %f", empty)
+	}
+}
+
+func TestOpenAIEmbedderBatchesAndOrders(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if got := r.Header.Get("Authorization"); got != "Bearer pa-key" {
+			t.Errorf("unexpected auth header %q", got)
+		}
+		var payload struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if payload.Model != voyageModel {
+			t.Errorf("unexpected model %q", payload.Model)
+		}
+		// Answer in reverse to check vectors are put back by index.
+		var data []string
+		for i := len(payload.Input) - 1; i >= 0; i-- {
+			var n int
+			fmt.Sscanf(payload.Input[i], "text %d", &n)
+			data = append(data, fmt.Sprintf(`{"index":%d,"embedding":[%d,1]}`, i, n))
+		}
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	}))
+	defer server.Close()
+
+	embedder, err := NewFromConfig(config.EmbeddingsConfig{Provider: "voyage", Endpoint: server.URL, APIKey: "pa-key"}, server.Client())
+	if err != nil {
+		t.Fatalf("new embedder: %v", err)
+	}
+	texts := make([]string, maxBatch+3)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+	vectors, err := embedder.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if requests != 2 || len(vectors) != len(texts) {
+		t.Fatalf("expected 2 requests and %d vectors, got %d and %d", len(texts), requests, len(vectors))
+	}
+	for i, vector := range vectors {
+		if int(vector[0]) != i {
+			t.Fatalf("vector %d out of order: %v", i, vector)
+		}
+	}
+	if embedder.Model() != "voyage:"+voyageModel {
+		t.Fatalf("unexpected model %q", embedder.Model())
+	}
+}
+
+func TestOllamaEmbedder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"embeddings":[[0.5,0.5]]}`)
+	}))
+	defer server.Close()
+
+	embedder, err := NewFromConfig(config.EmbeddingsConfig{Provider: "ollama", Endpoint: server.URL + "/api/embed"}, server.Client())
+	if err != nil {
+		t.Fatalf("new embedder: %v", err)
+	}
+	vectors, err := embedder.Embed(context.Background(), []string{"hello"})
+	if err != nil || len(vectors) != 1 || vectors[0][0] != 0.5 {
+		t.Fatalf("unexpected vectors %v (%v)", vectors, err)
+	}
+
+	if _, err := embedder.Embed(context.Background(), []string{"a", "b"}); err == nil || !strings.Contains(err.Error(), "1 vectors for 2 texts") {
+		t.Fatalf("expected count mismatch error, got %v", err)
+	}
+}
+
+func TestNewFromConfigRequiresKeyForHostedAPIs(t *testing.T) {
+	if _, err := NewFromConfig(config.EmbeddingsConfig{Provider: "openai"}, http.DefaultClient); err == nil || !strings.Contains(err.Error(), "api_key") {
+		t.Fatalf("expected api_key error, got %v", err)
+	}
+	embedder, err := NewFromConfig(config.EmbeddingsConfig{}, nil)
+	if err != nil || embedder.Model() != "lexical:hash-256" {
+		t.Fatalf("expected lexical default, got %v (%v)", embedder, err)
+	}
+}
+
+type countingEmbedder struct {
+	texts int
+}
+
+func (e *countingEmbedder) Model() string {
+	return "counting"
+}
+
+func (e *countingEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.texts += len(texts)
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text))}
+	}
+	return vectors, nil
+}
+
+func TestCacheEmbedsOnlyNewTexts(t *testing.T) {
+	inner := &countingEmbedder{}
+	cache := NewCache(inner, 3)
+	if _, err := cache.Embed(context.Background(), []string{"a", "bb"}); err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	vectors, err := cache.Embed(context.Background(), []string{"bb", "ccc"})
+	if err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if inner.texts != 3 || vectors[0][0] != 2 || vectors[1][0] != 3 {
+		t.Fatalf("expected 3 texts embedded and vectors in order, got %d and %v", inner.texts, vectors)
+	}
+	// Full: the cache starts over instead of growing.
+	if _, err := cache.Embed(context.Background(), []string{"dddd"}); err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if len(cache.vectors) != 1 {
+		t.Fatalf("expected cache to reset, has %d vectors", len(cache.vectors))
+	}
+}
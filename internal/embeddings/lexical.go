@@ -0,0 +1,88 @@
+package embeddings
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultDimensions = 256
+	// trigramWeight lets shared word parts ("remind", "reminder") count for
+	// less than shared whole words.
+	trigramWeight = 0.5
+)
+
+// Lexical embeds text by hashing its words and character trigrams into a
+// fixed-size vector. It needs no network or model files and is fast on
+// small hosts, but it matches wording rather than meaning: "car" and
+// "vehicle" share nothing. Use a remote provider when that matters.
+type Lexical struct {
+	dimensions int
+}
+
+// NewLexical returns a lexical embedder; dimensions <= 0 uses the default.
+func NewLexical(dimensions int) *Lexical {
+	if dimensions <= 0 {
+		dimensions = defaultDimensions
+	}
+	return &Lexical{dimensions: dimensions}
+}
+
+// Model identifies the lexical embedder and its size.
+func (l *Lexical) Model() string {
+	return "lexical:hash-" + strconv.Itoa(l.dimensions)
+}
+
+// Embed returns a unit-length vector per text; empty text gives all zeros.
+func (l *Lexical) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		vectors[i] = l.embed(text)
+	}
+	return vectors, nil
+}
+
+func (l *Lexical) embed(text string) []float32 {
+	vector := make([]float32, l.dimensions)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		l.add(vector, "w:"+word, 1)
+		padded := []rune("^" + word + "$")
+		for i := 0; i+3 <= len(padded); i++ {
+			l.add(vector, "t:"+string(padded[i:i+3]), trigramWeight)
+		}
+	}
+	var norm float64
+	for _, value := range vector {
+		norm += float64(value) * float64(value)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vector {
+			vector[i] *= scale
+		}
+	}
+	return vector
+}
+
+// add hashes feature into a bucket. The hash also picks a sign, so features
+// that collide tend to cancel out instead of piling up.
+func (l *Lexical) add(vector []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+	bucket := int(sum % uint64(l.dimensions))
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vector[bucket] += weight
+}
@@ -0,0 +1,140 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// maxBatch caps the texts sent in one request; every supported API
+	// accepts at least this many.
+	maxBatch = 64
+	// maxResponseBytes caps one response; 64 vectors of 3072 floats as JSON
+	// fit comfortably.
+	maxResponseBytes = 32 << 20
+)
+
+// openAIEmbedder calls an OpenAI-shaped /embeddings endpoint. Voyage AI,
+// which Anthropic recommends for embeddings, uses the same request and
+// response format.
+type openAIEmbedder struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	model    string
+	label    string
+}
+
+func (e *openAIEmbedder) Model() string {
+	return e.label + ":" + e.model
+}
+
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return batched(texts, func(batch []string) ([][]float32, error) {
+		var response struct {
+			Data []struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			} `json:"data"`
+		}
+		payload := map[string]any{"model": e.model, "input": batch}
+		if err := postJSON(ctx, e.client, e.endpoint, e.apiKey, payload, &response); err != nil {
+			return nil, err
+		}
+		vectors := make([][]float32, len(batch))
+		for _, item := range response.Data {
+			if item.Index < 0 || item.Index >= len(batch) {
+				return nil, fmt.Errorf("embeddings response has unexpected index %d", item.Index)
+			}
+			vectors[item.Index] = item.Embedding
+		}
+		return vectors, nil
+	})
+}
+
+// ollamaEmbedder calls Ollama's /api/embed.
+type ollamaEmbedder struct {
+	client   *http.Client
+	endpoint string
+	model    string
+}
+
+func (e *ollamaEmbedder) Model() string {
+	return "ollama:" + e.model
+}
+
+func (e *ollamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return batched(texts, func(batch []string) ([][]float32, error) {
+		var response struct {
+			Embeddings [][]float32 `json:"embeddings"`
+		}
+		payload := map[string]any{"model": e.model, "input": batch}
+		if err := postJSON(ctx, e.client, e.endpoint, "", payload, &response); err != nil {
+			return nil, err
+		}
+		return response.Embeddings, nil
+	})
+}
+
+// batched splits texts into maxBatch-sized requests and checks that every
+// text got a vector.
+func batched(texts []string, embed func([]string) ([][]float32, error)) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += maxBatch {
+		batch := texts[start:min(start+maxBatch, len(texts))]
+		got, err := embed(batch)
+		if err != nil {
+			return nil, err
+		}
+		if len(got) != len(batch) {
+			return nil, fmt.Errorf("embeddings response has %d vectors for %d texts", len(got), len(batch))
+		}
+		for _, vector := range got {
+			if len(vector) == 0 {
+				return nil, fmt.Errorf("embeddings response is missing a vector")
+			}
+		}
+		vectors = append(vectors, got...)
+	}
+	return vectors, nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, endpoint, apiKey string, payload any, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("embeddings request: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("read embeddings response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail := strings.TrimSpace(string(raw))
+		if len(detail) > 300 {
+			detail = detail[:300]
+		}
+		return fmt.Errorf("embeddings request failed: %s %s", resp.Status, detail)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode embeddings response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// Package expenses records what the user spends in a TSV log and adds it up
+// by month, category, and currency.
+package expenses
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/calc"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// DateLayout is the format of the date an expense was made.
+const DateLayout = "2006-01-02"
+
+// MonthLayout is the format of report months.
+const MonthLayout = "2006-01"
+
+// DefaultCategory is used when no category is given.
+const DefaultCategory = "other"
+
+// Entry is one recorded expense. Negative amounts are refunds.
+type Entry struct {
+	Timestamp time.Time
+	Date      string
+	Amount    float64
+	Currency  string
+	Category  string
+	Merchant  string
+	Note      string
+}
+
+// Format renders the entry on one line, e.g. "2026-03-07 12.50 USD dining at Joe's".
+func (e Entry) Format() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s %s", e.Date, formatAmount(e.Amount), e.Currency, e.Category)
+	if e.Merchant != "" {
+		fmt.Fprintf(&b, " at %s", e.Merchant)
+	}
+	if e.Note != "" {
+		fmt.Fprintf(&b, " (%s)", e.Note)
+	}
+	return b.String()
+}
+
+// Log appends expenses to a TSV file and reads them back.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New returns a Log for the configured expenses TSV path.
+func New(path string) *Log {
+	return &Log{path: path}
+}
+
+// Append validates and normalizes entry, then writes it as a TSV line. The
+// normalized entry is returned.
+func (l *Log) Append(entry Entry) (Entry, error) {
+	if entry.Amount == 0 {
+		return Entry{}, errors.New("amount must not be zero")
+	}
+	entry.Currency = strings.ToUpper(strings.TrimSpace(entry.Currency))
+	if !calc.IsCurrency(entry.Currency) {
+		return Entry{}, fmt.Errorf("currency must be a three-letter code like USD, got %q", entry.Currency)
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	entry.Date = strings.TrimSpace(entry.Date)
+	if entry.Date == "" {
+		entry.Date = entry.Timestamp.In(time.Local).Format(DateLayout)
+	} else if _, err := time.ParseInLocation(DateLayout, entry.Date, time.Local); err != nil {
+		return Entry{}, fmt.Errorf("date must be YYYY-MM-DD, got %q", entry.Date)
+	}
+	entry.Category = NormalizeCategory(entry.Category)
+	entry.Merchant = cleanField(entry.Merchant)
+	entry.Note = cleanField(entry.Note)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		entry.Timestamp.Format(time.RFC3339),
+		entry.Date,
+		strconv.FormatFloat(entry.Amount, 'f', -1, 64),
+		entry.Currency,
+		entry.Category,
+		entry.Merchant,
+		entry.Note,
+	)
+	if err := store.AppendFile(l.path, []byte(line)); err != nil {
+		return Entry{}, fmt.Errorf("append expense: %w", err)
+	}
+	return entry, nil
+}
+
+// Entries returns expenses dated from through to inclusive (YYYY-MM-DD), in
+// file order. Empty bounds are open.
+func (l *Log) Entries(from, to string) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	content, err := store.ReadFile(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read expenses file: %w", err)
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "ts\t") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			continue
+		}
+		amount, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			continue
+		}
+		entry := Entry{
+			Timestamp: ts,
+			Date:      fields[1],
+			Amount:    amount,
+			Currency:  fields[3],
+			Category:  fields[4],
+			Merchant:  fields[5],
+			Note:      fields[6],
+		}
+		if (from != "" && entry.Date < from) || (to != "" && entry.Date > to) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan expenses file: %w", err)
+	}
+	return entries, nil
+}
+
+// Month returns the expenses dated in month (YYYY-MM).
+func (l *Log) Month(month string) ([]Entry, error) {
+	start, err := time.ParseInLocation(MonthLayout, strings.TrimSpace(month), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("month must be YYYY-MM, got %q", month)
+	}
+	end := start.AddDate(0, 1, -1)
+	return l.Entries(start.Format(DateLayout), end.Format(DateLayout))
+}
+
+// Summary totals a set of expenses.
+type Summary struct {
+	Count int
+	// Totals maps currency to the sum spent in it.
+	Totals map[string]float64
+	// Categories holds per-category sums, largest first.
+	Categories []CategoryTotal
+}
+
+// CategoryTotal is the sum spent in one category and currency.
+type CategoryTotal struct {
+	Category string
+	Currency string
+	Amount   float64
+	Count    int
+}
+
+// Summarize adds entries up by currency and by category. Amounts in
+// different currencies are never converted or combined.
+func Summarize(entries []Entry) Summary {
+	summary := Summary{Count: len(entries), Totals: map[string]float64{}}
+	byKey := map[[2]string]*CategoryTotal{}
+	for _, entry := range entries {
+		summary.Totals[entry.Currency] += entry.Amount
+		key := [2]string{entry.Category, entry.Currency}
+		total, ok := byKey[key]
+		if !ok {
+			total = &CategoryTotal{Category: entry.Category, Currency: entry.Currency}
+			byKey[key] = total
+		}
+		total.Amount += entry.Amount
+		total.Count++
+	}
+	for _, total := range byKey {
+		summary.Categories = append(summary.Categories, *total)
+	}
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		a, b := summary.Categories[i], summary.Categories[j]
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		if a.Amount != b.Amount {
+			return a.Amount > b.Amount
+		}
+		return a.Category < b.Category
+	})
+	return summary
+}
+
+// Format renders the summary under title, e.g.
+//
+//	Expenses for March 2026 (3 entries)
+//	Total: 62.50 USD
+//	- groceries: 50.00 USD (2)
+//	- dining: 12.50 USD (1)
+func (s Summary) Format(title string) string {
+	if s.Count == 0 {
+		return fmt.Sprintf("No expenses recorded for %s.", title)
+	}
+	var b strings.Builder
+	noun := "entries"
+	if s.Count == 1 {
+		noun = "entry"
+	}
+	fmt.Fprintf(&b, "Expenses for %s (%d %s)\n", title, s.Count, noun)
+	currencies := make([]string, 0, len(s.Totals))
+	for currency := range s.Totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	totals := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		totals = append(totals, formatAmount(s.Totals[currency])+" "+currency)
+	}
+	fmt.Fprintf(&b, "Total: %s", strings.Join(totals, ", "))
+	for _, total := range s.Categories {
+		fmt.Fprintf(&b, "\n- %s: %s %s (%d)", total.Category, formatAmount(total.Amount), total.Currency, total.Count)
+	}
+	return b.String()
+}
+
+// MonthTitle renders a YYYY-MM month as "March 2026", or returns it unchanged
+// when it does not parse.
+func MonthTitle(month string) string {
+	parsed, err := time.Parse(MonthLayout, month)
+	if err != nil {
+		return month
+	}
+	return parsed.Format("January 2006")
+}
+
+// NormalizeCategory lowercases a category and falls back to DefaultCategory.
+func NormalizeCategory(category string) string {
+	category = strings.ToLower(strings.Join(strings.Fields(category), "_"))
+	if category == "" {
+		return DefaultCategory
+	}
+	return category
+}
+
+// cleanField collapses whitespace, including tabs and newlines that would
+// break the TSV layout.
+func cleanField(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,82 @@
+package expenses
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendMonthAndSummarize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expenses.tsv")
+	if err := os.WriteFile(path, []byte("ts\tdate\tamount\tcurrency\tcategory\tmerchant\tnote\n"), 0o644); err != nil {
+		t.Fatalf("seed header: %v", err)
+	}
+	log := New(path)
+	now := time.Date(2026, 3, 7, 12, 0, 0, 0, time.Local)
+
+	entry, err := log.Append(Entry{Timestamp: now, Amount: 12.5, Currency: "usd", Category: "Eating Out", Merchant: "Joe's\tDiner"})
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if entry.Date != "2026-03-07" || entry.Currency != "USD" || entry.Category != "eating_out" || entry.Merchant != "Joe's Diner" {
+		t.Fatalf("unexpected normalized entry %#v", entry)
+	}
+	for _, e := range []Entry{
+		{Timestamp: now, Date: "2026-03-01", Amount: 40, Currency: "USD", Category: "groceries"},
+		{Timestamp: now, Date: "2026-03-02", Amount: 10, Currency: "USD", Category: "groceries"},
+		{Timestamp: now, Date: "2026-03-03", Amount: 20, Currency: "EUR", Category: "groceries"},
+		{Timestamp: now, Date: "2026-02-28", Amount: 99, Currency: "USD"},
+	} {
+		if _, err := log.Append(e); err != nil {
+			t.Fatalf("append %v: %v", e, err)
+		}
+	}
+
+	entries, err := log.Month("2026-03")
+	if err != nil {
+		t.Fatalf("month: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected February entry excluded, got %#v", entries)
+	}
+	got := Summarize(entries).Format(MonthTitle("2026-03"))
+	want := "Expenses for March 2026 (4 entries)\n" +
+		"Total: 20.00 EUR, 62.50 USD\n" +
+		"- groceries: 20.00 EUR (1)\n" +
+		"- groceries: 50.00 USD (2)\n" +
+		"- eating_out: 12.50 USD (1)"
+	if got != want {
+		t.Fatalf("unexpected summary:\n%s", got)
+	}
+
+	february, err := log.Month("2026-02")
+	if err != nil || len(february) != 1 || february[0].Category != DefaultCategory {
+		t.Fatalf("unexpected February entries %#v %v", february, err)
+	}
+}
+
+func TestAppendRejectsInvalidEntries(t *testing.T) {
+	log := New(filepath.Join(t.TempDir(), "expenses.tsv"))
+	cases := []Entry{
+		{Amount: 0, Currency: "USD"},
+		{Amount: 5, Currency: "dollars"},
+		{Amount: 5, Currency: "USD", Date: "March 7"},
+	}
+	for _, entry := range cases {
+		if _, err := log.Append(entry); err == nil {
+			t.Fatalf("expected error for %#v", entry)
+		}
+	}
+	if entries, err := log.Entries("", ""); err != nil || len(entries) != 0 {
+		t.Fatalf("expected nothing written, got %#v %v", entries, err)
+	}
+}
+
+func TestSummaryFormatEmpty(t *testing.T) {
+	got := Summarize(nil).Format("March 2026")
+	if !strings.HasPrefix(got, "No expenses recorded") {
+		t.Fatalf("unexpected empty summary %q", got)
+	}
+}
@@ -0,0 +1,296 @@
+// Package importer converts existing Markdown notes, such as an Obsidian vault, into memory facts and workspace documents.
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"go.yaml.in/yaml/v3"
+)
+
+// Source formats.
+const (
+	// FormatMarkdown reads plain Markdown files with optional YAML frontmatter.
+	FormatMarkdown = "markdown"
+	// FormatObsidian also reads inline #tags and resolves [[wikilinks]].
+	FormatObsidian = "obsidian"
+)
+
+// MaxFactChars is the longest note body imported as a memory fact. Longer
+// notes are copied as documents instead.
+const MaxFactChars = 280
+
+// IndexFileName lists imported documents with their tags.
+const IndexFileName = "index.tsv"
+
+// Notes larger than this are skipped.
+const maxNoteBytes = 1 << 20
+
+var (
+	inlineTagPattern = regexp.MustCompile(`(?:^|\s)#([\p{L}][\p{L}\p{N}_/-]*)`)
+	embedPattern     = regexp.MustCompile(`!\[\[[^\]]*\]\]`)
+	wikilinkPattern  = regexp.MustCompile(`\[\[([^\]|]*)(?:\|([^\]]*))?\]\]`)
+)
+
+// Note is one Markdown file read from the source directory.
+type Note struct {
+	// Path is relative to the source directory, with forward slashes.
+	Path     string
+	Title    string
+	Tags     []string
+	Body     string
+	Modified time.Time
+}
+
+// Result counts what an import wrote.
+type Result struct {
+	Facts     int
+	Documents int
+}
+
+// Scan reads every .md file under dir, skipping hidden directories such as
+// .obsidian and .trash. Tags come from the folders a note sits in, its
+// frontmatter tags, and, for FormatObsidian, inline #tags. It also returns how
+// many empty, oversized, or unreadable notes were skipped.
+func Scan(dir, format string) ([]Note, int, error) {
+	if format != FormatMarkdown && format != FormatObsidian {
+		return nil, 0, fmt.Errorf("unknown import format %q", format)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !info.IsDir() {
+		return nil, 0, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	var notes []Note
+	skipped := 0
+	err = filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if p != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil || info.Size() > maxNoteBytes {
+			skipped++
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil || !utf8.Valid(content) {
+			skipped++
+			return nil
+		}
+		note := parseNote(filepath.ToSlash(rel), string(content), format)
+		if strings.TrimSpace(note.Body) == "" {
+			skipped++
+			return nil
+		}
+		note.Modified = info.ModTime()
+		notes = append(notes, note)
+		return nil
+	})
+	if err != nil {
+		return nil, skipped, fmt.Errorf("scan %s: %w", dir, err)
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].Path < notes[j].Path })
+	return notes, skipped, nil
+}
+
+// Import saves notes up to MaxFactChars as memory facts, with a topic named
+// after the note, and copies longer notes into docsDir with an index.tsv
+// listing their tags.
+func Import(notes []Note, memoryStore *memory.Store, docsDir string) (Result, error) {
+	if memoryStore == nil {
+		return Result{}, errors.New("memory store is required")
+	}
+	var result Result
+	var index [][]string
+	for _, note := range notes {
+		if IsFact(note) {
+			text := strings.Join(strings.Fields(note.Body), " ")
+			if !strings.Contains(strings.ToLower(text), strings.ToLower(note.Title)) {
+				text = note.Title + ": " + text
+			}
+			err := memoryStore.AppendMemory(memory.LogEntry{
+				Timestamp: note.Modified,
+				Tags:      append([]string{topicFor(note)}, note.Tags...),
+				Text:      text,
+				// KV values cannot contain spaces.
+				KV: "source=" + strings.ReplaceAll(note.Path, " ", "%20"),
+			})
+			if err != nil {
+				return result, fmt.Errorf("import %s: %w", note.Path, err)
+			}
+			result.Facts++
+			continue
+		}
+
+		target := filepath.Join(docsDir, filepath.FromSlash(note.Path))
+		content := "# " + note.Title + "\n\n" + note.Body + "\n"
+		if err := store.WriteFile(target, []byte(content)); err != nil {
+			return result, fmt.Errorf("import %s: %w", note.Path, err)
+		}
+		tags := strings.Join(note.Tags, ",")
+		if tags == "" {
+			tags = "-"
+		}
+		index = append(index, []string{note.Path, tags, note.Title})
+		result.Documents++
+	}
+
+	if len(index) > 0 {
+		var b bytes.Buffer
+		b.WriteString("path\ttags\ttitle\n")
+		for _, row := range index {
+			b.WriteString(strings.Join(row, "\t"))
+			b.WriteByte('\n')
+		}
+		if err := store.WriteFile(filepath.Join(docsDir, IndexFileName), b.Bytes()); err != nil {
+			return result, fmt.Errorf("write import index: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// IsFact reports whether Import would save note as a memory fact.
+func IsFact(note Note) bool {
+	return utf8.RuneCountInString(strings.Join(strings.Fields(note.Body), " ")) <= MaxFactChars
+}
+
+func parseNote(rel, content, format string) Note {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	front, body := splitFrontmatter(content)
+
+	var tags []string
+	for _, dir := range strings.Split(path.Dir(rel), "/") {
+		if dir != "." {
+			tags = append(tags, dir)
+		}
+	}
+	tags = append(tags, frontmatterTags(front["tags"])...)
+	tags = append(tags, frontmatterTags(front["tag"])...)
+
+	if format == FormatObsidian {
+		for _, match := range inlineTagPattern.FindAllStringSubmatch(body, -1) {
+			tags = append(tags, match[1])
+		}
+		body = embedPattern.ReplaceAllString(body, "")
+		body = wikilinkPattern.ReplaceAllStringFunc(body, func(link string) string {
+			parts := wikilinkPattern.FindStringSubmatch(link)
+			if parts[2] != "" {
+				return parts[2]
+			}
+			return parts[1]
+		})
+	}
+
+	title, _ := front["title"].(string)
+	body = strings.TrimSpace(body)
+	if heading, rest, ok := strings.Cut(body, "\n"); strings.HasPrefix(heading, "# ") {
+		if title == "" {
+			title = strings.TrimSpace(strings.TrimPrefix(heading, "# "))
+		}
+		if ok {
+			body = strings.TrimSpace(rest)
+		} else {
+			body = ""
+		}
+	}
+	if strings.TrimSpace(title) == "" {
+		title = strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+	}
+	return Note{Path: rel, Title: strings.TrimSpace(title), Tags: cleanTags(tags), Body: body}
+}
+
+// splitFrontmatter separates a leading YAML block delimited by --- lines.
+// Unparseable frontmatter is kept as part of the body.
+func splitFrontmatter(content string) (map[string]any, string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, content
+	}
+	raw, body, ok := strings.Cut(content[len("---\n"):], "\n---")
+	if !ok {
+		return nil, content
+	}
+	var front map[string]any
+	if err := yaml.Unmarshal([]byte(raw), &front); err != nil {
+		return nil, content
+	}
+	_, body, _ = strings.Cut(body, "\n")
+	return front, body
+}
+
+// frontmatterTags accepts a YAML list or a comma- or space-separated string.
+func frontmatterTags(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+	case []any:
+		tags := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				tags = append(tags, s)
+			}
+		}
+		return tags
+	default:
+		return nil
+	}
+}
+
+// cleanTags strips # prefixes and turns nested tags (a/b) and commas into
+// underscores, which memory tags cannot contain.
+func cleanTags(tags []string) []string {
+	replacer := strings.NewReplacer("/", "_", ",", "_", "\t", "_")
+	cleaned := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = replacer.Replace(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+		cleaned = append(cleaned, tag)
+	}
+	return memory.NormalizeTags(cleaned)
+}
+
+// topicFor names a fact's topic after its note, so re-importing an edited note
+// supersedes the earlier fact.
+func topicFor(note Note) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSuffix(note.Path, path.Ext(note.Path))) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			if s := b.String(); s != "" && !strings.HasSuffix(s, "_") {
+				b.WriteByte('_')
+			}
+		}
+	}
+	if slug := strings.TrimSuffix(b.String(), "_"); slug != "" {
+		return "note_" + slug
+	}
+	return "note"
+}
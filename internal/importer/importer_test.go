@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+)
+
+func TestScanAndImportObsidianVault(t *testing.T) {
+	vault := t.TempDir()
+	writeNote(t, vault, "Health/Allergies.md", "---\ntags: [medical, Food]\n---\n# Allergies\n\nAllergic to peanuts. See [[Doctor visits|my doctor]].\n")
+	writeNote(t, vault, "Projects/Garden plan.md", "Plan for the #garden/veg beds.\n\n"+strings.Repeat("Tomatoes along the south fence. ", 20))
+	writeNote(t, vault, "Empty.md", "---\ntitle: Nothing\n---\n")
+	writeNote(t, vault, ".obsidian/workspace.md", "internal state")
+	writeNote(t, vault, "image.png", "not a note")
+
+	notes, skipped, err := Scan(vault, FormatObsidian)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if len(notes) != 2 || skipped != 1 {
+		t.Fatalf("expected two notes and one skipped, got %d notes, %d skipped", len(notes), skipped)
+	}
+	allergies := notes[0]
+	if allergies.Title != "Allergies" || strings.Join(allergies.Tags, ",") != "health,medical,food" {
+		t.Fatalf("unexpected allergies note %#v", allergies)
+	}
+	if allergies.Body != "Allergic to peanuts. See my doctor." {
+		t.Fatalf("expected resolved wikilink and no heading, got %q", allergies.Body)
+	}
+	if strings.Join(notes[1].Tags, ",") != "projects,garden_veg" {
+		t.Fatalf("expected folder and inline tags, got %v", notes[1].Tags)
+	}
+
+	memoryDir := t.TempDir()
+	store, err := memory.New(memoryDir)
+	if err != nil {
+		t.Fatalf("new memory store: %v", err)
+	}
+	docsDir := filepath.Join(t.TempDir(), "notes", "vault")
+	result, err := Import(notes, store, docsDir)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Facts != 1 || result.Documents != 1 {
+		t.Fatalf("expected one fact and one document, got %#v", result)
+	}
+
+	facts := store.ActiveFacts(time.Now())
+	if len(facts) != 1 {
+		t.Fatalf("expected one fact, got %#v", facts)
+	}
+	if facts[0].Tags[0] != "note_health_allergies" || facts[0].Text != "Allergies: Allergic to peanuts. See my doctor." {
+		t.Fatalf("unexpected fact %#v", facts[0])
+	}
+	if facts[0].KV != "source=Health/Allergies.md" {
+		t.Fatalf("unexpected fact source %q", facts[0].KV)
+	}
+
+	doc, err := os.ReadFile(filepath.Join(docsDir, "Projects", "Garden plan.md"))
+	if err != nil {
+		t.Fatalf("read imported document: %v", err)
+	}
+	if !strings.HasPrefix(string(doc), "# Garden plan\n\nPlan for the #garden/veg beds.") {
+		t.Fatalf("unexpected document %q", doc)
+	}
+	index, err := os.ReadFile(filepath.Join(docsDir, IndexFileName))
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	if string(index) != "path\ttags\ttitle\nProjects/Garden plan.md\tprojects,garden_veg\tGarden plan\n" {
+		t.Fatalf("unexpected index %q", index)
+	}
+}
+
+func TestScanMarkdownIgnoresObsidianSyntax(t *testing.T) {
+	dir := t.TempDir()
+	writeNote(t, dir, "note.md", "---\ntags: reading, books\n---\nFinished #dune, see [[Reviews]].\n")
+
+	notes, _, err := Scan(dir, FormatMarkdown)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if len(notes) != 1 || strings.Join(notes[0].Tags, ",") != "reading,books" || !strings.Contains(notes[0].Body, "[[Reviews]]") {
+		t.Fatalf("unexpected markdown note %#v", notes)
+	}
+	if _, _, err := Scan(dir, "notion"); err == nil {
+		t.Fatalf("expected unknown format to fail")
+	}
+}
+
+func writeNote(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
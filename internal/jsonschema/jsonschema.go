@@ -0,0 +1,236 @@
+// Package jsonschema validates decoded JSON values against the commonly used subset of JSON Schema.
+//
+// Supported keywords: type, enum, const, properties, required,
+// additionalProperties (boolean or schema), items, minItems, maxItems,
+// minLength, maxLength, minimum, and maximum. Other keywords are ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// Schema is a decoded JSON Schema document.
+type Schema map[string]any
+
+// Load reads a schema from a JSON file.
+func Load(path string) (Schema, error) {
+	raw, err := store.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema %s: %w", path, err)
+	}
+	schema, err := Parse([]byte(raw))
+	if err != nil {
+		return nil, fmt.Errorf("schema %s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// Parse decodes a schema document.
+func Parse(raw []byte) (Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+	if schema == nil {
+		return nil, errors.New("schema must be a JSON object")
+	}
+	return schema, nil
+}
+
+// String returns the schema as compact JSON.
+func (s Schema) String() string {
+	raw, err := json.Marshal(map[string]any(s))
+	if err != nil {
+		return "{}"
+	}
+	return string(raw)
+}
+
+// Validate checks a value decoded with encoding/json against the schema.
+// A nil schema accepts any value.
+func (s Schema) Validate(value any) error {
+	if s == nil {
+		return nil
+	}
+	return validate("$", map[string]any(s), value)
+}
+
+func validate(path string, schema map[string]any, value any) error {
+	if raw, ok := schema["type"]; ok {
+		if err := checkType(path, raw, value); err != nil {
+			return err
+		}
+	}
+	if want, ok := schema["const"]; ok && !reflect.DeepEqual(want, value) {
+		return fmt.Errorf("%s: must equal %v", path, want)
+	}
+	if options, ok := schema["enum"].([]any); ok {
+		matched := false
+		for _, option := range options {
+			if reflect.DeepEqual(option, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: must be one of %v", path, options)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return validateObject(path, schema, v)
+	case []any:
+		if n, ok := number(schema["minItems"]); ok && float64(len(v)) < n {
+			return fmt.Errorf("%s: must have at least %v items", path, n)
+		}
+		if n, ok := number(schema["maxItems"]); ok && float64(len(v)) > n {
+			return fmt.Errorf("%s: must have at most %v items", path, n)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validate(fmt.Sprintf("%s[%d]", path, i), items, item); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if n, ok := number(schema["minLength"]); ok && length < n {
+			return fmt.Errorf("%s: must be at least %v characters", path, n)
+		}
+		if n, ok := number(schema["maxLength"]); ok && length > n {
+			return fmt.Errorf("%s: must be at most %v characters", path, n)
+		}
+	case float64:
+		if n, ok := number(schema["minimum"]); ok && v < n {
+			return fmt.Errorf("%s: must be >= %v", path, n)
+		}
+		if n, ok := number(schema["maximum"]); ok && v > n {
+			return fmt.Errorf("%s: must be <= %v", path, n)
+		}
+	}
+	return nil
+}
+
+func validateObject(path string, schema map[string]any, value map[string]any) error {
+	if required, ok := schema["required"].([]any); ok {
+		for _, key := range required {
+			name, _ := key.(string)
+			if _, present := value[name]; !present {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		childPath := path + "." + key
+		if sub, ok := properties[key].(map[string]any); ok {
+			if err := validate(childPath, sub, value[key]); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, declared := properties[key]; declared {
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				return fmt.Errorf("%s: unexpected property", childPath)
+			}
+		case map[string]any:
+			if err := validate(childPath, extra, value[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkType(path string, raw any, value any) error {
+	var types []string
+	switch t := raw.(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+	default:
+		return nil
+	}
+	for _, name := range types {
+		if hasType(name, value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(types, " or "), typeName(value))
+}
+
+func hasType(name string, value any) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return false
+	}
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func number(raw any) (float64, bool) {
+	n, ok := raw.(float64)
+	return n, ok
+}
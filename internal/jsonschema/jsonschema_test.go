@@ -0,0 +1,96 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func decode(t *testing.T, raw string) any {
+	t.Helper()
+	var value any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+	return value
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := Parse([]byte(`{
+		"type": "object",
+		"required": ["status", "items"],
+		"additionalProperties": false,
+		"properties": {
+			"status": {"type": "string", "enum": ["ok", "error"]},
+			"count": {"type": "integer", "minimum": 0},
+			"items": {
+				"type": "array",
+				"maxItems": 2,
+				"items": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "minLength": 1}}}
+			},
+			"note": {"type": ["string", "null"]}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "valid", value: `{"status":"ok","count":2,"items":[{"name":"a"}],"note":null}`},
+		{name: "missing required", value: `{"status":"ok"}`, wantErr: `$: missing required property "items"`},
+		{name: "wrong type", value: `[]`, wantErr: "$: expected object, got array"},
+		{name: "enum", value: `{"status":"maybe","items":[]}`, wantErr: "$.status: must be one of"},
+		{name: "integer", value: `{"status":"ok","count":1.5,"items":[]}`, wantErr: "$.count: expected integer"},
+		{name: "minimum", value: `{"status":"ok","count":-1,"items":[]}`, wantErr: "$.count: must be >= 0"},
+		{name: "nested", value: `{"status":"ok","items":[{"name":""}]}`, wantErr: "$.items[0].name: must be at least 1 characters"},
+		{name: "max items", value: `{"status":"ok","items":[{"name":"a"},{"name":"b"},{"name":"c"}]}`, wantErr: "$.items: must have at most 2 items"},
+		{name: "additional", value: `{"status":"ok","items":[],"extra":1}`, wantErr: "$.extra: unexpected property"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := schema.Validate(decode(t, tc.value))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected valid, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNilSchemaAcceptsAnything(t *testing.T) {
+	var schema Schema
+	if err := schema.Validate(decode(t, `[1, "two"]`)); err != nil {
+		t.Fatalf("expected nil schema to accept any value, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, []byte(`{"type":"string"}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	schema, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if schema.String() != `{"type":"string"}` {
+		t.Fatalf("unexpected schema %s", schema)
+	}
+	if err := os.WriteFile(path, []byte(`[]`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected non-object schema to fail")
+	}
+}
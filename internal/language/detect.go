@@ -0,0 +1,161 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minLetters and minWords keep short replies like "ok" or "👍" from
+// switching anyone's language.
+const (
+	minLetters = 8
+	minWords   = 3
+)
+
+// scripts maps writing systems that identify one language on their own.
+var scripts = []struct {
+	table *unicode.RangeTable
+	code  string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// stopwords are frequent short words that tell Latin-script languages apart.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "you", "to", "of", "it", "that", "what", "for", "with", "this", "have", "can", "please", "my", "me", "i", "do", "was", "be", "on", "at", "how", "will", "your", "not"},
+	"de": {"der", "die", "das", "und", "ist", "ich", "du", "nicht", "ein", "eine", "zu", "mit", "auf", "für", "wie", "was", "bitte", "kannst", "mir", "mich", "wir", "sind", "es", "den", "dem", "noch", "auch", "heute"},
+	"fr": {"le", "la", "les", "et", "est", "je", "tu", "vous", "une", "un", "des", "de", "pour", "avec", "que", "qui", "pas", "ce", "dans", "du", "au", "moi", "mon", "sur", "peux", "merci", "bonjour", "nous"},
+	"es": {"el", "la", "los", "las", "y", "es", "que", "de", "en", "un", "una", "por", "para", "con", "no", "me", "mi", "qué", "cómo", "está", "estoy", "puedes", "hoy", "gracias", "hola", "del", "lo", "se"},
+	"it": {"il", "lo", "la", "gli", "le", "e", "è", "che", "di", "un", "una", "per", "con", "non", "mi", "ti", "sono", "come", "cosa", "puoi", "grazie", "ciao", "oggi", "della", "del", "anche", "questo", "ho"},
+	"pt": {"o", "a", "os", "as", "e", "é", "que", "de", "em", "um", "uma", "para", "com", "não", "eu", "você", "do", "da", "no", "na", "meu", "minha", "obrigado", "obrigada", "olá", "hoje", "pode", "isso"},
+	"nl": {"de", "het", "een", "en", "is", "ik", "je", "jij", "niet", "van", "op", "met", "voor", "dat", "wat", "hoe", "kun", "kunt", "mijn", "ook", "maar", "zijn", "naar", "dank", "vandaag", "graag", "er", "wij"},
+	"pl": {"i", "w", "na", "nie", "się", "jest", "to", "że", "z", "do", "co", "jak", "mi", "mnie", "czy", "proszę", "dzięki", "ale", "tak", "dla", "jestem", "możesz", "dzisiaj", "ten"},
+	"sv": {"och", "är", "jag", "du", "det", "att", "en", "ett", "inte", "på", "med", "för", "som", "vad", "hur", "kan", "min", "mitt", "tack", "hej", "idag", "vi", "har", "den", "av"},
+	"tr": {"ve", "bir", "bu", "ne", "için", "ile", "de", "da", "mi", "mı", "ben", "sen", "var", "yok", "nasıl", "lütfen", "teşekkürler", "merhaba", "bugün", "çok", "ama", "gibi", "evet", "hayır"},
+}
+
+// markers are letters found in only one of the Latin-script languages above.
+var markers = map[string]string{
+	"de": "ß",
+	"es": "ñ¿¡",
+	"pt": "ãõ",
+	"fr": "œêèç",
+	"pl": "ąęłśźżń",
+	"sv": "å",
+	"tr": "ğış",
+}
+
+var stopwordSets = func() map[string]map[string]bool {
+	sets := make(map[string]map[string]bool, len(stopwords))
+	for code, words := range stopwords {
+		sets[code] = make(map[string]bool, len(words))
+		for _, word := range words {
+			sets[code][word] = true
+		}
+	}
+	return sets
+}()
+
+// Detect guesses the language of a message. It reports false when the text
+// is too short or too mixed to tell.
+func Detect(text string) (string, bool) {
+	letters, latin := 0, 0
+	var kana, han, cyrillic, arabic int
+	counts := make([]int, len(scripts))
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		default:
+			for i, script := range scripts {
+				if unicode.Is(script.table, r) {
+					counts[i]++
+				}
+			}
+		}
+	}
+	// Chinese and Japanese need fewer characters to be clear.
+	if kana > 0 && kana+han > letters/2 {
+		return "ja", true
+	}
+	if han >= 2 && han > letters/2 {
+		return "zh", true
+	}
+	if letters < minLetters {
+		return "", false
+	}
+	switch {
+	case cyrillic > letters/2:
+		if strings.ContainsAny(strings.ToLower(text), "іїєґ") {
+			return "uk", true
+		}
+		return "ru", true
+	case arabic > letters/2:
+		if strings.ContainsAny(text, "پچژگ") {
+			return "fa", true
+		}
+		return "ar", true
+	}
+	for i, script := range scripts {
+		if counts[i] > letters/2 {
+			return script.code, true
+		}
+	}
+	if latin <= letters/2 {
+		return "", false
+	}
+	return detectLatin(text)
+}
+
+// detectLatin scores Latin-script text by stopwords and marker letters and
+// needs a clear winner.
+func detectLatin(text string) (string, bool) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) < minWords {
+		return "", false
+	}
+	scores := map[string]int{}
+	for _, word := range words {
+		for code, set := range stopwordSets {
+			if set[word] {
+				scores[code]++
+			}
+		}
+		for code, letters := range markers {
+			if strings.ContainsAny(word, letters) {
+				scores[code]++
+			}
+		}
+	}
+	best, bestScore, runnerUp := "", 0, 0
+	for code, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, runnerUp = code, score, bestScore
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+	if bestScore < 2 || bestScore == runnerUp {
+		return "", false
+	}
+	return best, true
+}
@@ -0,0 +1,197 @@
+// Package language keeps each user's preferred reply language, detected from
+// their messages or set with /language.
+package language
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+const (
+	// ModeAuto follows the language detected in the user's messages.
+	ModeAuto = "auto"
+	// ModeSet keeps a language the user chose until they change it.
+	ModeSet = "set"
+	// ModeOff leaves the reply language to the system prompt.
+	ModeOff = "off"
+)
+
+// localUser keys the preference of the local CLI user, whose messages carry
+// no user ID.
+const localUser = "local"
+
+// Preference is one user's reply language setting.
+type Preference struct {
+	// Language is a code such as "de" or "pt-BR"; empty until one is known.
+	Language  string    `json:"language,omitempty"`
+	Mode      string    `json:"mode"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store persists preferences in one JSON object keyed by user ID.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// New creates a preference store backed by path.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Get returns a user's preference. Users without one are in ModeAuto.
+func (s *Store) Get(userID string) (Preference, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return Preference{}, err
+	}
+	pref, ok := all[key(userID)]
+	if !ok || pref.Mode == "" {
+		pref.Mode = ModeAuto
+	}
+	return pref, nil
+}
+
+// Set stores a user's preference.
+func (s *Store) Set(userID string, pref Preference) error {
+	switch pref.Mode {
+	case ModeAuto, ModeSet, ModeOff:
+	default:
+		return fmt.Errorf("unknown language mode %q", pref.Mode)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+	all[key(userID)] = pref
+	return s.save(all)
+}
+
+// Observe updates an auto-mode user's language from one of their messages
+// and returns the preference to use for the reply. Messages too short to
+// tell, and users who chose a language or turned the feature off, leave the
+// stored preference alone.
+func (s *Store) Observe(userID, text string, now time.Time) (Preference, error) {
+	pref, err := s.Get(userID)
+	if err != nil || pref.Mode != ModeAuto {
+		return pref, err
+	}
+	detected, ok := Detect(text)
+	if !ok || detected == pref.Language {
+		return pref, nil
+	}
+	pref.Language = detected
+	pref.UpdatedAt = now.UTC()
+	return pref, s.Set(userID, pref)
+}
+
+var codePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,4})?$`)
+
+// Normalize validates a language code or English language name and returns
+// the code, e.g. "German" and "DE" both give "de", "pt-br" gives "pt-BR".
+func Normalize(input string) (string, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	for code, name := range names {
+		if strings.ToLower(name) == input {
+			return code, nil
+		}
+	}
+	input = strings.ReplaceAll(input, "_", "-")
+	if !codePattern.MatchString(input) {
+		return "", fmt.Errorf("%q is not a language code like en, de, or pt-BR", input)
+	}
+	if base, region, ok := strings.Cut(input, "-"); ok {
+		return base + "-" + strings.ToUpper(region), nil
+	}
+	return input, nil
+}
+
+// Name returns the English name of a language code, or the code itself when
+// it is not a common one.
+func Name(code string) string {
+	base, _, _ := strings.Cut(code, "-")
+	if name, ok := names[base]; ok {
+		if base != code {
+			return fmt.Sprintf("%s (%s)", name, code)
+		}
+		return name
+	}
+	return code
+}
+
+var names = map[string]string{
+	"ar": "Arabic",
+	"cs": "Czech",
+	"da": "Danish",
+	"de": "German",
+	"el": "Greek",
+	"en": "English",
+	"es": "Spanish",
+	"fa": "Persian",
+	"fi": "Finnish",
+	"fr": "French",
+	"he": "Hebrew",
+	"hi": "Hindi",
+	"hu": "Hungarian",
+	"id": "Indonesian",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"no": "Norwegian",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"ro": "Romanian",
+	"ru": "Russian",
+	"sv": "Swedish",
+	"th": "Thai",
+	"tr": "Turkish",
+	"uk": "Ukrainian",
+	"vi": "Vietnamese",
+	"zh": "Chinese",
+}
+
+func key(userID string) string {
+	if userID = strings.TrimSpace(userID); userID != "" {
+		return userID
+	}
+	return localUser
+}
+
+func (s *Store) load() (map[string]Preference, error) {
+	all := map[string]Preference{}
+	raw, err := store.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return all, nil
+		}
+		return nil, fmt.Errorf("read languages: %w", err)
+	}
+	if err := json.Unmarshal([]byte(raw), &all); err != nil {
+		return nil, fmt.Errorf("decode languages: %w", err)
+	}
+	return all, nil
+}
+
+func (s *Store) save(all map[string]Preference) error {
+	encoded, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode languages: %w", err)
+	}
+	if err := store.WriteFile(s.path, append(encoded, '\n')); err != nil {
+		return fmt.Errorf("write languages: %w", err)
+	}
+	return nil
+}
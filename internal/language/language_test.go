@@ -0,0 +1,82 @@
+package language
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDetect(t *testing.T) {
+	cases := map[string]string{
+		"Can you remind me to call the dentist tomorrow?":          "en",
+		"Kannst du mir bitte sagen, wie das Wetter heute ist?":     "de",
+		"Est-ce que tu peux ajouter du lait à la liste de courses": "fr",
+		"¿Puedes recordarme que tengo que llamar a mi madre hoy?":  "es",
+		"Puoi dirmi che tempo fa oggi a Milano per favore":         "it",
+		"Você pode me lembrar de comprar pão hoje?":                "pt",
+		"Kun je de boodschappenlijst voor vandaag laten zien":      "nl",
+		"Czy możesz mi przypomnieć o spotkaniu jutro":              "pl",
+		"Kan du påminna mig om att köpa mjölk idag":                "sv",
+		"Bugün hava nasıl, lütfen söyler misin":                    "tr",
+		"Напомни мне завтра позвонить маме":                        "ru",
+		"Нагадай мені завтра зателефонувати мамі":                  "uk",
+		"明日の天気を教えてください":                                            "ja",
+		"请提醒我明天给妈妈打电话":                                             "zh",
+		"내일 날씨 알려줄래요":                                              "ko",
+		"Πες μου τον καιρό για αύριο":                              "el",
+	}
+	for text, want := range cases {
+		if got, ok := Detect(text); !ok || got != want {
+			t.Errorf("Detect(%q) = %q, %v; want %q", text, got, ok, want)
+		}
+	}
+
+	for _, text := range []string{"ok", "👍", "thanks!", "Paris", "la la la"} {
+		if got, ok := Detect(text); ok {
+			t.Errorf("Detect(%q) = %q; want no guess", text, got)
+		}
+	}
+}
+
+func TestStoreObserveFollowsAutoModeOnly(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "languages.json"))
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	pref, err := store.Observe("42", "Kannst du mir bitte sagen, wie das Wetter heute ist?", now)
+	if err != nil || pref.Language != "de" || pref.Mode != ModeAuto {
+		t.Fatalf("expected detected German, got %+v (%v)", pref, err)
+	}
+	// Too short to tell: the stored language stays.
+	if pref, _ = store.Observe("42", "ok", now); pref.Language != "de" {
+		t.Fatalf("short message changed language: %+v", pref)
+	}
+
+	if err := store.Set("42", Preference{Language: "pt-BR", Mode: ModeSet, UpdatedAt: now}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if pref, _ = store.Observe("42", "Can you remind me to call the dentist tomorrow?", now); pref.Language != "pt-BR" || pref.Mode != ModeSet {
+		t.Fatalf("chosen language was overridden: %+v", pref)
+	}
+
+	if pref, _ = store.Get("7"); pref.Mode != ModeAuto || pref.Language != "" {
+		t.Fatalf("unexpected default preference %+v", pref)
+	}
+	if err := store.Set("7", Preference{Mode: "sometimes"}); err == nil {
+		t.Fatalf("expected unknown mode error")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{"DE": "de", "German": "de", "pt_br": "pt-BR", "zh-hant": "zh-HANT"}
+	for input, want := range cases {
+		if got, err := Normalize(input); err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := Normalize("klingon!"); err == nil {
+		t.Fatalf("expected invalid code error")
+	}
+	if got := Name("pt-BR"); got != "Portuguese (pt-BR)" {
+		t.Fatalf("unexpected name %q", got)
+	}
+}
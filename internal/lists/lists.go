@@ -0,0 +1,238 @@
+// Package lists stores named checklists, such as a shopping list, that every
+// paired user of an agent shares independently of conversation sessions.
+package lists
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// DefaultList is used when no list name is given.
+const DefaultList = "shopping"
+
+// Item is one entry on a list.
+type Item struct {
+	Text    string    `json:"text"`
+	Checked bool      `json:"checked,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Store persists all lists in one JSON object keyed by list name.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// New creates a list store backed by path.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Add appends items to a list, skipping ones already on it. Adding an item
+// that is checked off unchecks it instead. It returns the items added or
+// unchecked.
+func (s *Store) Add(list string, texts []string, now time.Time) ([]string, error) {
+	list = NormalizeName(list)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	items := all[list]
+	var added []string
+	for _, text := range texts {
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" {
+			continue
+		}
+		if i := indexOf(items, text); i >= 0 {
+			if items[i].Checked {
+				items[i].Checked = false
+				added = append(added, items[i].Text)
+			}
+			continue
+		}
+		items = append(items, Item{Text: text, AddedAt: now.UTC()})
+		added = append(added, text)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	all[list] = items
+	return added, s.save(all)
+}
+
+// Remove deletes the item matching ref, a 1-based position or the item text.
+func (s *Store) Remove(list, ref string) (Item, error) {
+	return s.update(list, ref, func(items []Item, i int) ([]Item, Item) {
+		removed := items[i]
+		return append(items[:i], items[i+1:]...), removed
+	})
+}
+
+// Toggle checks or unchecks the item matching ref.
+func (s *Store) Toggle(list, ref string) (Item, error) {
+	return s.update(list, ref, func(items []Item, i int) ([]Item, Item) {
+		items[i].Checked = !items[i].Checked
+		return items, items[i]
+	})
+}
+
+// ClearChecked removes checked items and returns how many were removed.
+func (s *Store) ClearChecked(list string) (int, error) {
+	list = NormalizeName(list)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	items := all[list]
+	kept := items[:0]
+	for _, item := range items {
+		if !item.Checked {
+			kept = append(kept, item)
+		}
+	}
+	removed := len(items) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	all[list] = kept
+	return removed, s.save(all)
+}
+
+// Items returns a list's items in the order they were added.
+func (s *Store) Items(list string) ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return all[NormalizeName(list)], nil
+}
+
+// Names returns the names of lists that have items, sorted.
+func (s *Store) Names() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(all))
+	for name, items := range all {
+		if len(items) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Format renders a list with checkboxes, numbering items for /list commands.
+func Format(list string, items []Item) string {
+	list = NormalizeName(list)
+	if len(items) == 0 {
+		return fmt.Sprintf("The %s list is empty.", list)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s list:", strings.ToUpper(list[:1])+list[1:])
+	for i, item := range items {
+		box := "☐"
+		if item.Checked {
+			box = "☑"
+		}
+		fmt.Fprintf(&b, "\n%d. %s %s", i+1, box, item.Text)
+	}
+	return b.String()
+}
+
+// NormalizeName lowercases a list name and falls back to DefaultList.
+func NormalizeName(name string) string {
+	name = strings.ToLower(strings.Join(strings.Fields(name), "_"))
+	if name == "" {
+		return DefaultList
+	}
+	return name
+}
+
+func (s *Store) update(list, ref string, change func(items []Item, i int) ([]Item, Item)) (Item, error) {
+	list = NormalizeName(list)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return Item{}, err
+	}
+	i := find(all[list], ref)
+	if i < 0 {
+		return Item{}, fmt.Errorf("%q is not on the %s list", strings.TrimSpace(ref), list)
+	}
+	var item Item
+	all[list], item = change(all[list], i)
+	return item, s.save(all)
+}
+
+// find resolves a 1-based position or case-insensitive item text.
+func find(items []Item, ref string) int {
+	ref = strings.Join(strings.Fields(ref), " ")
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n >= 1 && n <= len(items) {
+			return n - 1
+		}
+		return -1
+	}
+	return indexOf(items, ref)
+}
+
+func indexOf(items []Item, text string) int {
+	for i, item := range items {
+		if strings.EqualFold(item.Text, text) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Store) load() (map[string][]Item, error) {
+	all := map[string][]Item{}
+	raw, err := store.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return all, nil
+		}
+		return nil, fmt.Errorf("read lists: %w", err)
+	}
+	if err := json.Unmarshal([]byte(raw), &all); err != nil {
+		return nil, fmt.Errorf("decode lists: %w", err)
+	}
+	return all, nil
+}
+
+func (s *Store) save(all map[string][]Item) error {
+	for name, items := range all {
+		if len(items) == 0 {
+			delete(all, name)
+		}
+	}
+	raw, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode lists: %w", err)
+	}
+	if err := store.WriteFile(s.path, append(raw, '\n')); err != nil {
+		return fmt.Errorf("write lists: %w", err)
+	}
+	return nil
+}
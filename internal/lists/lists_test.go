@@ -0,0 +1,62 @@
+package lists
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddToggleRemoveClear(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "lists.json"))
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	added, err := s.Add("", []string{"milk", " eggs ", "", "Milk"}, now)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if len(added) != 2 {
+		t.Fatalf("expected duplicates and blanks skipped, got %#v", added)
+	}
+	if _, err := s.Toggle("Shopping", "EGGS"); err != nil {
+		t.Fatalf("toggle: %v", err)
+	}
+	if _, err := s.Add("hardware store", []string{"screws"}, now); err != nil {
+		t.Fatalf("add hardware: %v", err)
+	}
+
+	items, err := s.Items("shopping")
+	if err != nil {
+		t.Fatalf("items: %v", err)
+	}
+	if got := Format("shopping", items); got != "Shopping list:\n1. ☐ milk\n2. ☑ eggs" {
+		t.Fatalf("unexpected format %q", got)
+	}
+	names, err := s.Names()
+	if err != nil || len(names) != 2 || names[0] != "hardware_store" {
+		t.Fatalf("unexpected names %#v %v", names, err)
+	}
+
+	// Re-adding a checked item unchecks it.
+	if added, _ := s.Add("shopping", []string{"eggs"}, now); len(added) != 1 {
+		t.Fatalf("expected checked item to be re-added, got %#v", added)
+	}
+	if _, err := s.Toggle("shopping", "2"); err != nil {
+		t.Fatalf("toggle by number: %v", err)
+	}
+	if removed, err := s.ClearChecked("shopping"); err != nil || removed != 1 {
+		t.Fatalf("clear checked: %d %v", removed, err)
+	}
+	removed, err := s.Remove("shopping", "1")
+	if err != nil || removed.Text != "milk" {
+		t.Fatalf("remove: %#v %v", removed, err)
+	}
+	if _, err := s.Remove("shopping", "bread"); err == nil {
+		t.Fatalf("expected missing item to fail")
+	}
+	if got := Format("shopping", nil); got != "The shopping list is empty." {
+		t.Fatalf("unexpected empty format %q", got)
+	}
+	if names, _ := s.Names(); len(names) != 1 {
+		t.Fatalf("expected empty list dropped, got %#v", names)
+	}
+}
@@ -0,0 +1,93 @@
+// Package liveness lets a running server prove it is alive: it touches a
+// heartbeat file on an interval, and Check reads that file from another
+// process, such as a container HEALTHCHECK.
+package liveness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// DefaultInterval is how often Run writes the heartbeat.
+const DefaultInterval = 15 * time.Second
+
+// Run writes the current time to path now and every interval until ctx is
+// done, then removes the file so a stopped server never looks healthy.
+func Run(ctx context.Context, path string, interval time.Duration) {
+	beat := func() {
+		if err := store.WriteFile(path, []byte(time.Now().UTC().Format(time.RFC3339)+"\n")); err != nil {
+			logging.Logger().Warn("failed to write heartbeat", "path", path, "err", err)
+		}
+	}
+	beat()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			os.Remove(path)
+			return
+		case <-ticker.C:
+			beat()
+		}
+	}
+}
+
+// Check reports why the server is not healthy, or nil if it is: the
+// process in pidPath must be running and the heartbeat in heartbeatPath
+// must be newer than maxAge.
+func Check(pidPath, heartbeatPath string, maxAge time.Duration, now time.Time) error {
+	rawPID, err := os.ReadFile(pidPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.New("server is not running (no pid file)")
+	}
+	if err != nil {
+		return fmt.Errorf("read pid file: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(rawPID)))
+	if err != nil || pid <= 0 {
+		return fmt.Errorf("pid file %s is malformed", pidPath)
+	}
+	if err := signalZero(pid); err != nil {
+		return fmt.Errorf("server process %d is not running: %w", pid, err)
+	}
+
+	rawBeat, err := os.ReadFile(heartbeatPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.New("server has not written a heartbeat yet")
+	}
+	if err != nil {
+		return fmt.Errorf("read heartbeat: %w", err)
+	}
+	last, err := time.Parse(time.RFC3339, strings.TrimSpace(string(rawBeat)))
+	if err != nil {
+		return fmt.Errorf("heartbeat file %s is malformed", heartbeatPath)
+	}
+	if age := now.Sub(last); age > maxAge {
+		return fmt.Errorf("server heartbeat is %s old (limit %s); it may be stuck", age.Round(time.Second), maxAge)
+	}
+	return nil
+}
+
+// signalZero checks that pid exists without sending it a signal. EPERM
+// means it exists but belongs to another user, which still counts.
+func signalZero(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	err = process.Signal(syscall.Signal(0))
+	if err == nil || errors.Is(err, syscall.EPERM) {
+		return nil
+	}
+	return err
+}
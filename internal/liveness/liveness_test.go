@@ -0,0 +1,62 @@
+package liveness
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	pidPath := filepath.Join(dir, "claw.pid")
+	heartbeatPath := filepath.Join(dir, "claw.heartbeat")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := Check(pidPath, heartbeatPath, time.Minute, now); err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Fatalf("expected not running error, got %v", err)
+	}
+	if err := os.WriteFile(pidPath, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0o644); err != nil {
+		t.Fatalf("write pid: %v", err)
+	}
+	if err := Check(pidPath, heartbeatPath, time.Minute, now); err == nil || !strings.Contains(err.Error(), "heartbeat yet") {
+		t.Fatalf("expected missing heartbeat error, got %v", err)
+	}
+	if err := os.WriteFile(heartbeatPath, []byte(now.Add(-30*time.Second).Format(time.RFC3339)), 0o644); err != nil {
+		t.Fatalf("write heartbeat: %v", err)
+	}
+	if err := Check(pidPath, heartbeatPath, time.Minute, now); err != nil {
+		t.Fatalf("expected healthy, got %v", err)
+	}
+	if err := Check(pidPath, heartbeatPath, time.Minute, now.Add(time.Minute)); err == nil || !strings.Contains(err.Error(), "stuck") {
+		t.Fatalf("expected stale heartbeat error, got %v", err)
+	}
+}
+
+func TestRunRemovesHeartbeatOnStop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claw.heartbeat")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, path, time.Hour)
+		close(done)
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("heartbeat was not written")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected heartbeat removed, got %v", err)
+	}
+}
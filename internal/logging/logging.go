@@ -2,8 +2,11 @@
 package logging
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/lmittmann/tint"
 	"golang.org/x/term"
@@ -11,11 +14,34 @@ import (
 
 const defaultLogLevel = slog.LevelInfo
 
-var logger = slog.New(newHandler(defaultLogLevel))
+// recentLines is how many log lines Recent keeps for crash reports.
+const recentLines = 200
+
+var (
+	recent = &ringWriter{lines: make([]string, 0, recentLines)}
+	logger = slog.New(newHandler(defaultLogLevel))
+
+	// currentLevel, plain, and file are the settings the logger was last
+	// built with.
+	currentLevel = defaultLogLevel
+	plain        bool
+	file         *rotatingFile
+)
 
 func newHandler(level slog.Level) slog.Handler {
+	handler := teeHandler{
+		primary: newOutputHandler(level),
+		recent:  slog.NewTextHandler(recent, &slog.HandlerOptions{Level: level}),
+	}
+	if file != nil {
+		handler.file = slog.NewJSONHandler(file, &slog.HandlerOptions{Level: level})
+	}
+	return handler
+}
+
+func newOutputHandler(level slog.Level) slog.Handler {
 	opts := &slog.HandlerOptions{Level: level}
-	if isTerminal(os.Stderr) {
+	if isTerminal(os.Stderr) && !plain {
 		return tint.NewHandler(os.Stderr, &tint.Options{
 			Level:      level,
 			TimeFormat: "15:04:05",
@@ -55,5 +81,101 @@ func Logger() *slog.Logger {
 
 // SetLevel updates the global logger level.
 func SetLevel(level slog.Level) {
+	currentLevel = level
 	logger = slog.New(newHandler(level))
 }
+
+// SetPlain turns off colored output on terminals that show escape codes
+// literally, such as Emacs shell-mode.
+func SetPlain(enabled bool) {
+	plain = enabled
+	logger = slog.New(newHandler(currentLevel))
+}
+
+// SetFile also writes every record as a line of JSON to the file at path,
+// replacing any file set before. The file is rotated to path.1 when it
+// reaches maxFileBytes.
+func SetFile(path string) error {
+	rotating, err := openRotatingFile(path)
+	if err != nil {
+		return err
+	}
+	previous := file
+	file = rotating
+	logger = slog.New(newHandler(currentLevel))
+	if previous != nil {
+		previous.Close()
+	}
+	return nil
+}
+
+// Recent returns the last log lines in plain text, oldest first.
+func Recent() []string {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	lines := make([]string, 0, len(recent.lines))
+	lines = append(lines, recent.lines[recent.next:]...)
+	return append(lines, recent.lines[:recent.next]...)
+}
+
+// ringWriter keeps the last recentLines lines written to it. The text
+// handler writes one record per call.
+type ringWriter struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+}
+
+func (w *ringWriter) Write(p []byte) (int, error) {
+	line := strings.TrimRight(string(p), "\n")
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.lines) < recentLines {
+		w.lines = append(w.lines, line)
+		return len(p), nil
+	}
+	w.lines[w.next] = line
+	w.next = (w.next + 1) % recentLines
+	return len(p), nil
+}
+
+// teeHandler sends each record to the output handler, to the recent lines
+// buffer, and to the log file when one is set.
+type teeHandler struct {
+	primary slog.Handler
+	recent  slog.Handler
+	file    slog.Handler
+}
+
+func (h teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.primary.Enabled(ctx, level) || h.recent.Enabled(ctx, level)
+}
+
+func (h teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.recent.Enabled(ctx, record.Level) {
+		h.recent.Handle(ctx, record.Clone())
+	}
+	if h.file != nil && h.file.Enabled(ctx, record.Level) {
+		h.file.Handle(ctx, record.Clone())
+	}
+	if !h.primary.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.primary.Handle(ctx, record)
+}
+
+func (h teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := teeHandler{primary: h.primary.WithAttrs(attrs), recent: h.recent.WithAttrs(attrs)}
+	if h.file != nil {
+		out.file = h.file.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (h teeHandler) WithGroup(name string) slog.Handler {
+	out := teeHandler{primary: h.primary.WithGroup(name), recent: h.recent.WithGroup(name)}
+	if h.file != nil {
+		out.file = h.file.WithGroup(name)
+	}
+	return out
+}
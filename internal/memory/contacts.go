@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContactTag marks facts about a person. The kv name= key says who the fact
+// is about; relationship= and last_interaction= (YYYY-MM-DD) are optional.
+const ContactTag = "contact"
+
+// maxContactDetails caps how many facts are shown per contact, newest first.
+const maxContactDetails = 10
+
+// Contact is everything memory holds about one person, assembled from
+// contact-tagged facts.
+type Contact struct {
+	Name            string
+	Relationship    string
+	Details         []string
+	LastInteraction time.Time
+	Updated         time.Time
+}
+
+// Format renders the contact on one line for the prompt or a tool result.
+func (c Contact) Format() string {
+	var b strings.Builder
+	b.WriteString(c.Name)
+	if c.Relationship != "" {
+		fmt.Fprintf(&b, " (%s)", c.Relationship)
+	}
+	if !c.LastInteraction.IsZero() {
+		fmt.Fprintf(&b, ", last interaction %s", c.LastInteraction.Format("2006-01-02"))
+	}
+	if len(c.Details) > 0 {
+		b.WriteString(": ")
+		b.WriteString(strings.Join(c.Details, "; "))
+	}
+	return b.String()
+}
+
+// IsContactFact reports whether entry is a fact about a contact.
+func IsContactFact(entry LogEntry) bool {
+	return slices.Contains(entry.Tags, ContactTag)
+}
+
+// ContactName returns the display name stored in a name= kv value, where
+// spaces are written as underscores.
+func ContactName(kv string) string {
+	return strings.TrimSpace(strings.ReplaceAll(ParseKV(kv)["name"], "_", " "))
+}
+
+// Contacts returns every contact with unexpired facts, sorted by name.
+// Unlike other facts, contact facts accumulate instead of superseding each
+// other, and facts are grouped by name regardless of their first tag.
+func (s *Store) Contacts(now time.Time) []Contact {
+	s.mu.RLock()
+	entries := make([]LogEntry, 0)
+	for _, entry := range s.memoryFacts {
+		if IsContactFact(entry) && !isExpired(entry, now) {
+			entries = append(entries, entry)
+		}
+	}
+	s.mu.RUnlock()
+
+	// Newest first, so the latest relationship wins and details are capped
+	// to the most recent.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+	byName := make(map[string]*Contact)
+	for _, entry := range entries {
+		name := ContactName(entry.KV)
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		contact, ok := byName[key]
+		if !ok {
+			contact = &Contact{Name: name, Updated: entry.Timestamp}
+			byName[key] = contact
+		}
+		kv := ParseKV(entry.KV)
+		if contact.Relationship == "" {
+			contact.Relationship = strings.ReplaceAll(kv["relationship"], "_", " ")
+		}
+		if last, err := time.ParseInLocation("2006-01-02", kv["last_interaction"], time.Local); err == nil && last.After(contact.LastInteraction) {
+			contact.LastInteraction = last
+		}
+		if text := strings.TrimSpace(entry.Text); text != "" && len(contact.Details) < maxContactDetails && !slices.Contains(contact.Details, text) {
+			contact.Details = append(contact.Details, text)
+		}
+	}
+
+	contacts := make([]Contact, 0, len(byName))
+	for _, contact := range byName {
+		contacts = append(contacts, *contact)
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		return strings.ToLower(contacts[i].Name) < strings.ToLower(contacts[j].Name)
+	})
+	return contacts
+}
+
+// FindContacts returns contacts whose name, relationship, or details contain
+// query, case-insensitively. An empty query returns all contacts.
+func FindContacts(contacts []Contact, query string) []Contact {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return contacts
+	}
+	var found []Contact
+	for _, contact := range contacts {
+		haystack := strings.ToLower(contact.Name + "\n" + contact.Relationship + "\n" + strings.Join(contact.Details, "\n"))
+		if strings.Contains(haystack, query) {
+			found = append(found, contact)
+		}
+	}
+	return found
+}
+
+// MentionedContacts returns contacts referred to in text by full name, by
+// any part of their name, or by relationship ("my sister"), matching whole
+// words case-insensitively.
+func MentionedContacts(contacts []Contact, text string) []Contact {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	var mentioned []Contact
+	for _, contact := range contacts {
+		terms := append([]string{contact.Name}, strings.Fields(contact.Name)...)
+		if contact.Relationship != "" {
+			terms = append(terms, contact.Relationship)
+		}
+		if slices.ContainsFunc(terms, func(term string) bool { return mentionsWord(text, term) }) {
+			mentioned = append(mentioned, contact)
+		}
+	}
+	return mentioned
+}
+
+func mentionsWord(text, term string) bool {
+	// Very short name parts such as initials would match too often.
+	if len([]rune(term)) < 3 {
+		return false
+	}
+	pattern := `(?i)(^|[^\pL\pN])` + regexp.QuoteMeta(term) + `($|[^\pL\pN])`
+	matched, err := regexp.MatchString(pattern, text)
+	return err == nil && matched
+}
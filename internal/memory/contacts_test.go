@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContactsGroupsFactsByName(t *testing.T) {
+	store := mustNewStore(t, t.TempDir())
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	for i, entry := range []LogEntry{
+		{Tags: []string{"contact"}, Text: "Lives in Berlin", KV: "name=Anna_Schmidt relationship=sister"},
+		{Tags: []string{"birthday", "contact"}, Text: "Birthday is May 3", KV: "name=anna_schmidt last_interaction=2026-02-20"},
+		{Tags: []string{"contact"}, Text: "Dentist, books on Tuesdays", KV: "name=Dr_Lee relationship=dentist"},
+		{Tags: []string{"contact"}, Text: "No name given", KV: "-"},
+		{Tags: []string{"location"}, Text: "Lives in New York", KV: "-"},
+	} {
+		entry.Timestamp = base.Add(time.Duration(i) * time.Hour)
+		if err := store.AppendMemory(entry); err != nil {
+			t.Fatalf("append memory: %v", err)
+		}
+	}
+
+	contacts := store.Contacts(base.AddDate(0, 0, 1))
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 contacts, got %#v", contacts)
+	}
+	anna := contacts[0]
+	if anna.Name != "anna schmidt" || anna.Relationship != "sister" {
+		t.Fatalf("unexpected contact %#v", anna)
+	}
+	if len(anna.Details) != 2 || anna.Details[0] != "Birthday is May 3" {
+		t.Fatalf("expected newest details first, got %#v", anna.Details)
+	}
+	if got := anna.Format(); got != "anna schmidt (sister), last interaction 2026-02-20: Birthday is May 3; Lives in Berlin" {
+		t.Fatalf("unexpected format %q", got)
+	}
+
+	if found := FindContacts(contacts, "DENTIST"); len(found) != 1 || found[0].Name != "Dr Lee" {
+		t.Fatalf("unexpected lookup result %#v", found)
+	}
+	if found := FindContacts(contacts, ""); len(found) != 2 {
+		t.Fatalf("expected empty query to list everyone, got %#v", found)
+	}
+}
+
+func TestMentionedContactsMatchesWholeWords(t *testing.T) {
+	contacts := []Contact{
+		{Name: "Anna Schmidt", Relationship: "sister"},
+		{Name: "Bo", Relationship: "coworker"},
+	}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "What should I get Anna for her birthday?", want: "Anna Schmidt"},
+		{text: "call my sister tomorrow", want: "Anna Schmidt"},
+		{text: "Annabelle is coming over", want: ""},
+		{text: "Bo said hi", want: ""},
+		{text: "lunch with a coworker", want: "Bo"},
+	}
+	for _, tt := range tests {
+		var names []string
+		for _, contact := range MentionedContacts(contacts, tt.text) {
+			names = append(names, contact.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Fatalf("MentionedContacts(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
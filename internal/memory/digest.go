@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// Digest kinds.
+const (
+	DigestWeekly  = "weekly"
+	DigestMonthly = "monthly"
+)
+
+// DigestTag is the second tag of memory facts written for digests.
+const DigestTag = "digest"
+
+var digestHeadingPattern = regexp.MustCompile(`^# (?:Weekly|Monthly) digest: (\d{4}-\d{2}-\d{2}) to (\d{4}-\d{2}-\d{2})$`)
+
+// Digest summarizes the daily logs of one week or month.
+type Digest struct {
+	// Kind is DigestWeekly or DigestMonthly.
+	Kind string
+	// From and To are the first and last local calendar days covered.
+	From time.Time
+	To   time.Time
+	Text string
+}
+
+// Period formats the covered days as "2006-01-02 to 2006-01-02".
+func (d Digest) Period() string {
+	return d.From.Format("2006-01-02") + " to " + d.To.Format("2006-01-02")
+}
+
+// Covers reports whether day's local calendar date falls inside the digest.
+func (d Digest) Covers(day time.Time) bool {
+	key := day.In(time.Local).Format("2006-01-02")
+	return key >= d.From.Format("2006-01-02") && key <= d.To.Format("2006-01-02")
+}
+
+// SaveDigest replaces the latest digest of its kind (weekly.md or monthly.md)
+// and records it as a memory fact tagged with the kind and DigestTag.
+func (s *Store) SaveDigest(d Digest) error {
+	path, err := digestPath(s.dir, d.Kind)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(d.Text)
+	if text == "" {
+		return errors.New("digest text is required")
+	}
+	title := strings.ToUpper(d.Kind[:1]) + d.Kind[1:]
+	content := fmt.Sprintf("# %s digest: %s\n\n%s\n", title, d.Period(), text)
+	if err := store.WriteFile(path, []byte(content)); err != nil {
+		return fmt.Errorf("write %s digest: %w", d.Kind, err)
+	}
+	return s.AppendMemory(LogEntry{
+		Tags: []string{d.Kind + "_digest", DigestTag},
+		// Facts are single TSV rows, so the digest is flattened to one line.
+		Text: strings.Join(strings.Fields(text), " "),
+		KV:   "period=" + d.From.Format("2006-01-02") + ".." + d.To.Format("2006-01-02"),
+	})
+}
+
+// LatestDigest returns the saved digest of kind. ok is false when none exists.
+func (s *Store) LatestDigest(kind string) (digest Digest, ok bool, err error) {
+	path, err := digestPath(s.dir, kind)
+	if err != nil {
+		return Digest{}, false, err
+	}
+	content, err := store.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Digest{}, false, nil
+	}
+	if err != nil {
+		return Digest{}, false, fmt.Errorf("read %s digest: %w", kind, err)
+	}
+	heading, body, _ := strings.Cut(content, "\n")
+	match := digestHeadingPattern.FindStringSubmatch(strings.TrimSpace(heading))
+	if match == nil {
+		return Digest{}, false, fmt.Errorf("parse %s digest: missing period heading", kind)
+	}
+	from, err := time.ParseInLocation("2006-01-02", match[1], time.Local)
+	if err != nil {
+		return Digest{}, false, fmt.Errorf("parse %s digest: %w", kind, err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", match[2], time.Local)
+	if err != nil {
+		return Digest{}, false, fmt.Errorf("parse %s digest: %w", kind, err)
+	}
+	return Digest{Kind: kind, From: from, To: to, Text: strings.TrimSpace(body)}, true, nil
+}
+
+// IsDigestFact reports whether entry was written by SaveDigest.
+func IsDigestFact(entry LogEntry) bool {
+	return len(entry.Tags) > 1 && entry.Tags[1] == DigestTag
+}
+
+func digestPath(dir, kind string) (string, error) {
+	switch kind {
+	case DigestWeekly:
+		return filepath.Join(dir, config.WeeklyDigestPath), nil
+	case DigestMonthly:
+		return filepath.Join(dir, config.MonthlyDigestPath), nil
+	default:
+		return "", fmt.Errorf("unknown digest kind %q", kind)
+	}
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSaveDigestRoundTripsAndRecordsFact(t *testing.T) {
+	store := mustNewStore(t, t.TempDir())
+	from := time.Date(2026, 2, 23, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+
+	if _, ok, err := store.LatestDigest(DigestWeekly); err != nil || ok {
+		t.Fatalf("expected no digest yet, got ok=%v err=%v", ok, err)
+	}
+	if err := store.SaveDigest(Digest{Kind: DigestWeekly, From: from, To: to, Text: "Finished the migration.\nStarted running."}); err != nil {
+		t.Fatalf("save digest: %v", err)
+	}
+
+	digest, ok, err := store.LatestDigest(DigestWeekly)
+	if err != nil || !ok {
+		t.Fatalf("load digest: ok=%v err=%v", ok, err)
+	}
+	if digest.Period() != "2026-02-23 to 2026-03-01" || digest.Text != "Finished the migration.\nStarted running." {
+		t.Fatalf("unexpected digest %#v", digest)
+	}
+	if !digest.Covers(to.Add(20*time.Hour)) || digest.Covers(to.AddDate(0, 0, 1)) {
+		t.Fatalf("unexpected coverage for %s", digest.Period())
+	}
+
+	facts := store.ActiveFacts(to.AddDate(0, 0, 2))
+	if len(facts) != 1 || !IsDigestFact(facts[0]) || facts[0].Text != "Finished the migration. Started running." {
+		t.Fatalf("expected one flattened digest fact, got %#v", facts)
+	}
+	if _, _, err := store.LatestDigest("daily"); err == nil {
+		t.Fatalf("expected unknown digest kind to fail")
+	}
+}
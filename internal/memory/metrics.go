@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricTag marks daily log entries that record one reading of a numeric
+// series. The entry's first tag is the series category (health, fitness, or
+// metric by default), so [memory.tags] retention and prompt rules apply.
+const MetricTag = "metric"
+
+// Reading is one recorded value of a metric.
+type Reading struct {
+	Timestamp time.Time
+	Name      string
+	Value     float64
+	Unit      string
+}
+
+// MetricEntry builds the daily log entry for a reading. category becomes
+// the first tag and defaults to MetricTag.
+func MetricEntry(category string, reading Reading, note string) LogEntry {
+	tags := NormalizeTags([]string{category, MetricTag})
+	name := NormalizeMetricName(reading.Name)
+	value := strconv.FormatFloat(reading.Value, 'f', -1, 64)
+	kv := "metric=" + name + " value=" + value
+	text := name + ": " + value
+	if unit := strings.Join(strings.Fields(reading.Unit), "_"); unit != "" {
+		kv += " unit=" + unit
+		text += " " + unit
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		text += " (" + note + ")"
+	}
+	return LogEntry{Timestamp: reading.Timestamp, Tags: tags, Text: text, KV: kv}
+}
+
+// NormalizeMetricName lowercases a metric name and joins words with
+// underscores, e.g. "Sleep Hours" becomes sleep_hours.
+func NormalizeMetricName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "_"))
+}
+
+// Readings returns the readings of metric between from and to inclusive,
+// oldest first. Readings deleted by tag retention are gone from the daily log
+// and so are not returned.
+func (s *Store) Readings(metric string, from, to time.Time) []Reading {
+	metric = NormalizeMetricName(metric)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var readings []Reading
+	for _, entry := range s.dailyEntriesOrEmpty(from, to) {
+		if entry.Timestamp.Before(from) || entry.Timestamp.After(to) || !slices.Contains(entry.Tags, MetricTag) {
+			continue
+		}
+		reading, ok := parseReading(entry)
+		if ok && reading.Name == metric {
+			readings = append(readings, reading)
+		}
+	}
+	return readings
+}
+
+// MetricNames returns every metric with at least one reading, sorted.
+func (s *Store) MetricNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	seen := map[string]bool{}
+	for _, entry := range s.dailyEntriesOrEmpty(time.Time{}, time.Time{}) {
+		if !slices.Contains(entry.Tags, MetricTag) {
+			continue
+		}
+		if reading, ok := parseReading(entry); ok {
+			seen[reading.Name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseReading(entry LogEntry) (Reading, bool) {
+	kv := ParseKV(entry.KV)
+	name := kv["metric"]
+	value, err := strconv.ParseFloat(kv["value"], 64)
+	if name == "" || err != nil {
+		return Reading{}, false
+	}
+	return Reading{Timestamp: entry.Timestamp, Name: name, Value: value, Unit: kv["unit"]}, true
+}
+
+// DayValue is a metric's value for one local day.
+type DayValue struct {
+	Day   string
+	Value float64
+}
+
+// DailyValues combines readings per local day, oldest first. With sum, a
+// day's readings are added (workout minutes, steps); otherwise they are
+// averaged (weight, sleep).
+func DailyValues(readings []Reading, sum bool) []DayValue {
+	var days []DayValue
+	counts := map[string]int{}
+	for _, reading := range readings {
+		day := reading.Timestamp.In(time.Local).Format("2006-01-02")
+		if len(days) == 0 || days[len(days)-1].Day != day {
+			days = append(days, DayValue{Day: day})
+		}
+		days[len(days)-1].Value += reading.Value
+		counts[day]++
+	}
+	if !sum {
+		for i := range days {
+			days[i].Value /= float64(counts[days[i].Day])
+		}
+	}
+	return days
+}
+
+// SummarizeTrend describes a metric's daily values: latest value, average,
+// range, change over the period, and the last 7 days against the 7 before.
+func SummarizeTrend(name, unit string, days []DayValue, now time.Time) string {
+	if len(days) == 0 {
+		return fmt.Sprintf("No readings for %s.", name)
+	}
+	withUnit := func(value float64) string {
+		text := formatMetricValue(value)
+		if unit != "" {
+			text += " " + unit
+		}
+		return text
+	}
+
+	total, low, high := 0.0, math.Inf(1), math.Inf(-1)
+	for _, day := range days {
+		total += day.Value
+		low = math.Min(low, day.Value)
+		high = math.Max(high, day.Value)
+	}
+	first, last := days[0], days[len(days)-1]
+	lines := []string{
+		fmt.Sprintf("%s: latest %s on %s", name, withUnit(last.Value), last.Day),
+		fmt.Sprintf("%d days from %s: average %s, min %s, max %s", len(days), first.Day, withUnit(total/float64(len(days))), withUnit(low), withUnit(high)),
+	}
+	if len(days) > 1 {
+		lines = append(lines, fmt.Sprintf("Change since %s: %s", first.Day, signed(last.Value-first.Value, withUnit)))
+	}
+
+	today := now.In(time.Local)
+	weekStart := today.AddDate(0, 0, -6).Format("2006-01-02")
+	prevStart := today.AddDate(0, 0, -13).Format("2006-01-02")
+	var week, prev []float64
+	for _, day := range days {
+		switch {
+		case day.Day >= weekStart:
+			week = append(week, day.Value)
+		case day.Day >= prevStart:
+			prev = append(prev, day.Value)
+		}
+	}
+	if len(week) > 0 && len(prev) > 0 {
+		weekAvg, prevAvg := mean(week), mean(prev)
+		lines = append(lines, fmt.Sprintf("Last 7 days average %s vs %s the week before (%s)", withUnit(weekAvg), withUnit(prevAvg), signed(weekAvg-prevAvg, withUnit)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func signed(delta float64, format func(float64) string) string {
+	if delta > 0 {
+		return "+" + format(delta)
+	}
+	return format(delta)
+}
+
+func mean(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
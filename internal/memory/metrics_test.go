@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadingsAndTrend(t *testing.T) {
+	store := mustNewStore(t, t.TempDir())
+	now := time.Date(2026, 3, 14, 20, 0, 0, 0, time.Local)
+	for i, value := range []float64{75, 74.5, 74, 73.5, 73, 72.5, 72} {
+		day := now.AddDate(0, 0, -12+2*i)
+		if err := store.AppendDailyLog(MetricEntry("health", Reading{Timestamp: day, Name: "Weight", Value: value, Unit: "kg"}, "")); err != nil {
+			t.Fatalf("append reading: %v", err)
+		}
+	}
+	if err := store.AppendDailyLog(MetricEntry("", Reading{Timestamp: now, Name: "workout minutes", Value: 30}, "run")); err != nil {
+		t.Fatalf("append workout: %v", err)
+	}
+	if err := store.AppendDailyLog(MetricEntry("", Reading{Timestamp: now.Add(time.Hour), Name: "workout minutes", Value: 20}, "")); err != nil {
+		t.Fatalf("append workout: %v", err)
+	}
+
+	if names := store.MetricNames(); strings.Join(names, ",") != "weight,workout_minutes" {
+		t.Fatalf("unexpected metric names %#v", names)
+	}
+	workouts := store.Readings("Workout Minutes", now.AddDate(0, 0, -1), now.Add(2*time.Hour))
+	if days := DailyValues(workouts, true); len(days) != 1 || days[0].Value != 50 {
+		t.Fatalf("expected summed workout day, got %#v", days)
+	}
+
+	readings := store.Readings("weight", now.AddDate(0, 0, -29), now)
+	if len(readings) != 7 {
+		t.Fatalf("expected 7 weight readings, got %#v", readings)
+	}
+	got := SummarizeTrend("weight", "kg", DailyValues(readings, false), now)
+	want := "weight: latest 72 kg on 2026-03-14\n" +
+		"7 days from 2026-03-02: average 73.5 kg, min 72 kg, max 75 kg\n" +
+		"Change since 2026-03-02: -3 kg\n" +
+		"Last 7 days average 72.75 kg vs 74.5 kg the week before (-1.75 kg)"
+	if got != want {
+		t.Fatalf("unexpected trend:\n%s", got)
+	}
+}
+
+func TestMetricEntriesFollowTagRules(t *testing.T) {
+	store := mustNewStore(t, t.TempDir())
+	now := time.Date(2026, 3, 14, 9, 0, 0, 0, time.Local)
+	store.SetTagRules(map[string]TagRule{"health": {Retention: 7 * 24 * time.Hour, ExcludeFromPrompt: true}})
+	for _, day := range []time.Time{now.AddDate(0, 0, -10), now} {
+		if err := store.AppendDailyLog(MetricEntry("health", Reading{Timestamp: day, Name: "sleep", Value: 7, Unit: "hours"}, "")); err != nil {
+			t.Fatalf("append reading: %v", err)
+		}
+	}
+
+	if entries := store.DailyLogsForPrompt([]time.Time{now}); len(entries) != 0 {
+		t.Fatalf("expected health metrics kept out of the prompt, got %#v", entries)
+	}
+	if removed, err := store.PruneDailyLogs(now); err != nil || removed != 1 {
+		t.Fatalf("expected old reading pruned, got %d %v", removed, err)
+	}
+	if readings := store.Readings("sleep", now.AddDate(0, 0, -30), now); len(readings) != 1 {
+		t.Fatalf("expected only the recent reading, got %#v", readings)
+	}
+}
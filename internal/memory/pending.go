@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// Pending entry targets.
+const (
+	PendingMemory   = "memory"
+	PendingDailyLog = "daily_log"
+)
+
+// PendingEntry is an agent memory write held for user review.
+type PendingEntry struct {
+	// Target is PendingMemory or PendingDailyLog.
+	Target    string    `json:"target"`
+	Timestamp time.Time `json:"ts"`
+	Tags      []string  `json:"tags"`
+	Text      string    `json:"text"`
+	KV        string    `json:"kv,omitempty"`
+}
+
+// QueuePending holds a memory or daily log write until the user reviews it.
+// Daily log entries are redacted before they are queued.
+func (s *Store) QueuePending(target string, entry LogEntry) error {
+	if target != PendingMemory && target != PendingDailyLog {
+		return fmt.Errorf("unknown pending target %q", target)
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	s.mu.RLock()
+	redact := s.redact
+	s.mu.RUnlock()
+	if target == PendingDailyLog && redact != nil {
+		entry.Text = redact(entry.Text)
+		entry.KV = redact(entry.KV)
+	}
+	if strings.TrimSpace(entry.Text) == "" {
+		return errors.New("entry text is required")
+	}
+
+	encoded, err := json.Marshal(PendingEntry{
+		Target:    target,
+		Timestamp: entry.Timestamp,
+		Tags:      NormalizeTags(entry.Tags),
+		Text:      entry.Text,
+		KV:        entry.KV,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal pending entry: %w", err)
+	}
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if err := store.AppendFile(s.pendingPath(), append(encoded, '\n')); err != nil {
+		return fmt.Errorf("append pending entry: %w", err)
+	}
+	return nil
+}
+
+// Pending returns queued writes, oldest first.
+func (s *Store) Pending() ([]PendingEntry, error) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	return s.loadPending()
+}
+
+// ResolvePending approves or rejects queued writes by their 1-based position
+// in Pending. No positions selects every entry. Approved entries are written
+// to memory or the daily log; both outcomes remove them from the queue. It
+// returns the resolved entries.
+func (s *Store) ResolvePending(positions []int, approve bool) ([]PendingEntry, error) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	entries, err := s.loadPending()
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		if pos < 1 || pos > len(entries) {
+			return nil, fmt.Errorf("no pending entry %d", pos)
+		}
+		selected[pos-1] = true
+	}
+
+	var resolved, remaining []PendingEntry
+	for i, entry := range entries {
+		if len(positions) > 0 && !selected[i] {
+			remaining = append(remaining, entry)
+			continue
+		}
+		if approve {
+			logEntry := LogEntry{Timestamp: entry.Timestamp, Tags: entry.Tags, Text: entry.Text, KV: entry.KV}
+			if entry.Target == PendingDailyLog {
+				err = s.AppendDailyLog(logEntry)
+			} else {
+				err = s.AppendMemory(logEntry)
+			}
+			if err != nil {
+				// Keep the queue consistent with what was already written.
+				remaining = append(remaining, entries[i:]...)
+				if writeErr := s.writePending(remaining); writeErr != nil {
+					return resolved, errors.Join(err, writeErr)
+				}
+				return resolved, err
+			}
+		}
+		resolved = append(resolved, entry)
+	}
+	if err := s.writePending(remaining); err != nil {
+		return resolved, err
+	}
+	return resolved, nil
+}
+
+func (s *Store) pendingPath() string {
+	return filepath.Join(s.dir, config.PendingFilePath)
+}
+
+func (s *Store) loadPending() ([]PendingEntry, error) {
+	content, err := store.ReadFile(s.pendingPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read pending entries: %w", err)
+	}
+	var entries []PendingEntry
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry PendingEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("parse pending entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read pending entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *Store) writePending(entries []PendingEntry) error {
+	var b strings.Builder
+	for _, entry := range entries {
+		encoded, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("marshal pending entry: %w", err)
+		}
+		b.Write(encoded)
+		b.WriteByte('\n')
+	}
+	if err := store.WriteFile(s.pendingPath(), []byte(b.String())); err != nil {
+		return fmt.Errorf("write pending entries: %w", err)
+	}
+	return nil
+}
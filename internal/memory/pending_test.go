@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolvePendingWritesApprovedAndDropsRejected(t *testing.T) {
+	store := mustNewStore(t, t.TempDir())
+	store.SetRedactor(func(text string) string { return strings.ReplaceAll(text, "sam@example.com", "[redacted]") })
+
+	if err := store.QueuePending(PendingMemory, LogEntry{Tags: []string{"Diet"}, Text: "Vegetarian"}); err != nil {
+		t.Fatalf("queue fact: %v", err)
+	}
+	if err := store.QueuePending(PendingDailyLog, LogEntry{Tags: []string{"email"}, Text: "Wrote to sam@example.com"}); err != nil {
+		t.Fatalf("queue daily log: %v", err)
+	}
+	if err := store.QueuePending(PendingMemory, LogEntry{Tags: []string{"health"}, Text: "Sensitive detail"}); err != nil {
+		t.Fatalf("queue fact: %v", err)
+	}
+	if err := store.QueuePending("notes", LogEntry{Text: "x"}); err == nil {
+		t.Fatalf("expected unknown target to be rejected")
+	}
+
+	pending, err := store.Pending()
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(pending) != 3 || pending[0].Tags[0] != "diet" || pending[1].Text != "Wrote to [redacted]" {
+		t.Fatalf("unexpected pending entries: %#v", pending)
+	}
+	if len(store.ActiveFacts(time.Now())) != 0 {
+		t.Fatalf("expected nothing written before review")
+	}
+
+	if _, err := store.ResolvePending([]int{4}, true); err == nil {
+		t.Fatalf("expected out-of-range position to fail")
+	}
+	rejected, err := store.ResolvePending([]int{3}, false)
+	if err != nil || len(rejected) != 1 || rejected[0].Text != "Sensitive detail" {
+		t.Fatalf("unexpected reject result %#v err=%v", rejected, err)
+	}
+	approved, err := store.ResolvePending(nil, true)
+	if err != nil || len(approved) != 2 {
+		t.Fatalf("unexpected approve result %#v err=%v", approved, err)
+	}
+
+	facts := store.ActiveFacts(time.Now())
+	if len(facts) != 1 || facts[0].Text != "Vegetarian" {
+		t.Fatalf("expected approved fact only, got %#v", facts)
+	}
+	if logs := store.DailyLogsByDate([]time.Time{time.Now()}); len(logs) != 1 {
+		t.Fatalf("expected approved daily log entry, got %#v", logs)
+	}
+	if pending, err := store.Pending(); err != nil || len(pending) != 0 {
+		t.Fatalf("expected empty queue, got %#v err=%v", pending, err)
+	}
+}
@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// CorruptSuffix ends the name of a copy of a memory file that held rows
+// that did not parse. Fsck recovers what it can from these copies.
+const CorruptSuffix = ".corrupt"
+
+// Quarantine describes a memory file that held rows that did not parse.
+// The file was copied to CorruptPath as it was and Path rewritten with the
+// Kept rows, so the store carries on with the rest of its data.
+type Quarantine struct {
+	Path        string
+	CorruptPath string
+	Kept        int
+	Lost        int
+}
+
+var (
+	// quarantineMu serializes quarantines so two readers of the same file
+	// set it aside once.
+	quarantineMu       sync.Mutex
+	quarantineNotifier func(Quarantine)
+	// unheardQuarantines waits for a notifier: stores open before the
+	// channels that would carry the news are up.
+	unheardQuarantines []Quarantine
+)
+
+// SetQuarantineNotifier makes notify hear about every memory file this
+// process quarantines, starting with any quarantined before it was set; nil
+// stops the notifications.
+func SetQuarantineNotifier(notify func(Quarantine)) {
+	quarantineMu.Lock()
+	quarantineNotifier = notify
+	var unheard []Quarantine
+	if notify != nil {
+		unheard, unheardQuarantines = unheardQuarantines, nil
+	}
+	quarantineMu.Unlock()
+	for _, q := range unheard {
+		notify(q)
+	}
+}
+
+// parseTSV returns the rows in content. ok is false when a row did not
+// parse or the reader ran rows together, as a stray quote makes it do.
+func parseTSV(path, content string) (entries []LogEntry, ok bool) {
+	rows, err := readTSVRows(content)
+	entries = make([]LogEntry, 0, len(rows))
+	ok = err == nil
+	if err != nil {
+		logging.Logger().Warn("malformed tsv row", "path", path, "err", err)
+	}
+	for _, fields := range rows {
+		var entry LogEntry
+		if err := entry.UnmarshalTSV(fields); err != nil {
+			logging.Logger().Warn("malformed tsv row", "path", path, "err", err)
+			ok = false
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, ok && len(entries) == len(tsvLines(content))
+}
+
+// recoverTSV parses content a line at a time, so one bad row cannot take
+// the rows after it along. It returns the rows that parse and the lines
+// that do not. With repair it also accepts rows with a tab in their text
+// or no kv column, as hand edits leave them.
+func recoverTSV(content string, repair bool) (entries []LogEntry, lost []string) {
+	for _, line := range tsvLines(content) {
+		fields := strings.Split(line, "\t")
+		for i, field := range fields {
+			if len(field) >= 2 && strings.HasPrefix(field, `"`) && strings.HasSuffix(field, `"`) {
+				fields[i] = strings.ReplaceAll(field[1:len(field)-1], `""`, `"`)
+			}
+		}
+		if repair && len(fields) == 3 {
+			fields = append(fields, "-")
+		}
+		if repair && len(fields) > 4 {
+			fields = []string{fields[0], fields[1], strings.Join(fields[2:len(fields)-1], " "), fields[len(fields)-1]}
+		}
+		var entry LogEntry
+		if err := entry.UnmarshalTSV(fields); err != nil {
+			lost = append(lost, line)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, lost
+}
+
+// tsvLines returns the non-blank lines of content after the header.
+func tsvLines(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "ts\t") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// quarantine copies the file at path, which held content, aside and
+// rewrites it with the rows recoverTSV finds. It returns those rows. If
+// the copy cannot be made the file is left alone and its rows still used.
+func quarantine(path, content string) []LogEntry {
+	quarantineMu.Lock()
+	entries, lost := recoverTSV(content, false)
+	if current, err := store.ReadFile(path); err != nil || current != content {
+		// Another reader got here first.
+		quarantineMu.Unlock()
+		return entries
+	}
+	q := Quarantine{Path: path, CorruptPath: corruptPath(path), Kept: len(entries), Lost: len(lost)}
+	if err := store.WriteFile(q.CorruptPath, []byte(content)); err != nil {
+		quarantineMu.Unlock()
+		logging.Logger().Error("failed to quarantine corrupt memory file", "path", path, "err", err)
+		return entries
+	}
+	if err := writeTSVFile(path, entries); err != nil {
+		logging.Logger().Error("failed to rewrite quarantined memory file", "path", path, "err", err)
+	}
+	logging.Logger().Error("quarantined corrupt memory file", "path", path, "corrupt_path", q.CorruptPath, "kept", q.Kept, "lost", q.Lost)
+	notify := quarantineNotifier
+	if notify == nil {
+		unheardQuarantines = append(unheardQuarantines, q)
+	}
+	quarantineMu.Unlock()
+	if notify != nil {
+		notify(q)
+	}
+	return entries
+}
+
+// corruptPath names the quarantine copy of path, adding the time when an
+// earlier copy is still there.
+func corruptPath(path string) string {
+	candidate := path + CorruptSuffix
+	if _, err := os.Stat(candidate); errors.Is(err, os.ErrNotExist) {
+		return candidate
+	}
+	return path + "." + time.Now().Format("20060102T150405.000000000") + CorruptSuffix
+}
+
+// FsckResult reports what Fsck recovered from one quarantined copy.
+type FsckResult struct {
+	CorruptPath string
+	// Path is the memory file the recovered rows went back into.
+	Path string
+	// Recovered counts rows added to Path; rows it already held are not
+	// counted.
+	Recovered int
+	// Unrecovered holds the lines that still do not parse. They stay in
+	// CorruptPath, which is removed once none are left.
+	Unrecovered []string
+}
+
+// Fsck checks memory.tsv and the daily logs under dir, quarantining any
+// file with rows that do not parse, then merges every row it can read or
+// repair from the quarantined copies back into the files they came from.
+// It must not run while a server is using dir.
+func Fsck(dir string) ([]FsckResult, error) {
+	dailyDir := filepath.Join(dir, config.DailyDirPath)
+	live := []string{filepath.Join(dir, config.MemoryFilePath)}
+	days, err := filepath.Glob(filepath.Join(dailyDir, "*.tsv"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range append(live, days...) {
+		if _, err := loadTSVFile(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	// Files quarantined just now are recovered with the rest.
+	var corrupt []string
+	for _, pattern := range []string{filepath.Join(dir, "*.tsv*"+CorruptSuffix), filepath.Join(dailyDir, "*.tsv*"+CorruptSuffix)} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		corrupt = append(corrupt, matches...)
+	}
+	sort.Strings(corrupt)
+
+	results := make([]FsckResult, 0, len(corrupt))
+	for _, path := range corrupt {
+		result, err := recoverCorrupt(path)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// recoverCorrupt merges the rows of the quarantined copy at path back into
+// the file it came from and keeps only the lines that still do not parse.
+func recoverCorrupt(path string) (FsckResult, error) {
+	name := filepath.Base(path)
+	original := filepath.Join(filepath.Dir(path), name[:strings.Index(name, ".tsv")+len(".tsv")])
+	result := FsckResult{CorruptPath: path, Path: original}
+	content, err := store.ReadFile(path)
+	if err != nil {
+		return result, err
+	}
+	recovered, lost := recoverTSV(content, true)
+	result.Unrecovered = lost
+
+	current, err := loadTSVFile(original)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return result, err
+	}
+	seen := make(map[string]bool, len(current))
+	for _, entry := range current {
+		seen[strings.Join(entry.MarshalTSV(), "\t")] = true
+	}
+	merged := current
+	for _, entry := range recovered {
+		key := strings.Join(entry.MarshalTSV(), "\t")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, entry)
+		result.Recovered++
+	}
+	if result.Recovered > 0 {
+		if err := writeTSVFile(original, merged); err != nil {
+			return result, err
+		}
+	}
+
+	if len(lost) == 0 {
+		return result, store.RemoveFile(path)
+	}
+	if len(recovered) > 0 {
+		if err := store.WriteFile(path, []byte(strings.Join(lost, "\n")+"\n")); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
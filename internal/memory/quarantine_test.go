@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const quarantineHeader = "ts\ttags\ttext\tkv\n"
+
+func TestNewQuarantinesCorruptMemoryFile(t *testing.T) {
+	var notified []Quarantine
+	SetQuarantineNotifier(func(q Quarantine) { notified = append(notified, q) })
+	t.Cleanup(func() { SetQuarantineNotifier(nil) })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "memory.tsv")
+	// The stray quote makes the reader swallow every row after it.
+	content := quarantineHeader +
+		"2026-01-01T10:00:00Z\tdiet\tVegetarian\t-\n" +
+		"2026-01-02T10:00:00Z\tnote\t\"half quoted\t-\n" +
+		"garbage\n" +
+		"2026-01-03T10:00:00Z\tlocation\tLives in Oslo\t-\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write memory file: %v", err)
+	}
+
+	store := mustNewStore(t, dir)
+	if len(store.memoryFacts) != 3 {
+		t.Fatalf("expected the 3 readable facts, got %#v", store.memoryFacts)
+	}
+	if store.memoryFacts[2].Text != "Lives in Oslo" {
+		t.Fatalf("expected the fact after the bad rows to survive, got %q", store.memoryFacts[2].Text)
+	}
+
+	corrupt, err := os.ReadFile(path + CorruptSuffix)
+	if err != nil {
+		t.Fatalf("read quarantined copy: %v", err)
+	}
+	if string(corrupt) != content {
+		t.Fatalf("expected the quarantined copy to keep the original, got %q", corrupt)
+	}
+	rewritten, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read rewritten file: %v", err)
+	}
+	if strings.Contains(string(rewritten), "garbage") || !strings.Contains(string(rewritten), "Lives in Oslo") {
+		t.Fatalf("expected only good rows in the rewritten file, got %q", rewritten)
+	}
+
+	if len(notified) != 1 {
+		t.Fatalf("expected one notice, got %#v", notified)
+	}
+	if q := notified[0]; q.Path != path || q.CorruptPath != path+CorruptSuffix || q.Kept != 3 || q.Lost != 1 {
+		t.Fatalf("unexpected notice: %#v", q)
+	}
+
+	// The rewritten file is clean, so opening again quarantines nothing.
+	mustNewStore(t, dir)
+	if len(notified) != 1 {
+		t.Fatalf("expected no second notice, got %#v", notified)
+	}
+}
+
+func TestQuarantineNoticesWaitForNotifier(t *testing.T) {
+	t.Cleanup(func() { SetQuarantineNotifier(nil) })
+	dir := t.TempDir()
+	dailyDir := filepath.Join(dir, "daily")
+	if err := os.MkdirAll(dailyDir, 0o755); err != nil {
+		t.Fatalf("mkdir daily: %v", err)
+	}
+	path := filepath.Join(dailyDir, "2026-01-02.tsv")
+	if err := os.WriteFile(path, []byte(quarantineHeader+"not a row\n"), 0o644); err != nil {
+		t.Fatalf("write daily log: %v", err)
+	}
+
+	mustNewStore(t, dir)
+
+	var notified []Quarantine
+	SetQuarantineNotifier(func(q Quarantine) { notified = append(notified, q) })
+	if len(notified) != 1 || notified[0].Path != path || notified[0].Lost != 1 {
+		t.Fatalf("expected the earlier quarantine on SetQuarantineNotifier, got %#v", notified)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected a daily log with no good rows to be removed, got %v", err)
+	}
+}
+
+func TestFsckRecoversRepairableRows(t *testing.T) {
+	t.Cleanup(func() { SetQuarantineNotifier(nil) })
+	dir := t.TempDir()
+	path := filepath.Join(dir, "memory.tsv")
+	live := quarantineHeader + "2026-01-01T10:00:00Z\tdiet\tVegetarian\t-\n"
+	if err := os.WriteFile(path, []byte(live), 0o644); err != nil {
+		t.Fatalf("write memory file: %v", err)
+	}
+	corrupt := quarantineHeader +
+		"2026-01-01T10:00:00Z\tdiet\tVegetarian\t-\n" +
+		"2026-01-02T10:00:00Z\tnote\tTab\tin text\t-\n" +
+		"2026-01-03T10:00:00Z\tlocation\tNo kv column\n" +
+		"truncated li\n"
+	if err := os.WriteFile(path+CorruptSuffix, []byte(corrupt), 0o644); err != nil {
+		t.Fatalf("write corrupt copy: %v", err)
+	}
+
+	results, err := Fsck(dir)
+	if err != nil {
+		t.Fatalf("fsck: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %#v", results)
+	}
+	result := results[0]
+	if result.Path != path || result.Recovered != 2 {
+		t.Fatalf("expected 2 rows recovered into memory.tsv, got %#v", result)
+	}
+	if len(result.Unrecovered) != 1 || result.Unrecovered[0] != "truncated li" {
+		t.Fatalf("expected the truncated line unrecovered, got %#v", result.Unrecovered)
+	}
+
+	store := mustNewStore(t, dir)
+	if len(store.memoryFacts) != 3 {
+		t.Fatalf("expected 3 facts after fsck, got %#v", store.memoryFacts)
+	}
+	if store.memoryFacts[1].Text != "Tab in text" || store.memoryFacts[2].KV != "-" {
+		t.Fatalf("unexpected repaired facts: %#v", store.memoryFacts)
+	}
+	left, err := os.ReadFile(path + CorruptSuffix)
+	if err != nil {
+		t.Fatalf("read corrupt copy: %v", err)
+	}
+	if string(left) != "truncated li\n" {
+		t.Fatalf("expected only the unrecovered line left, got %q", left)
+	}
+
+	// Once the last line is fixed by hand the copy is merged and removed.
+	if err := os.WriteFile(path+CorruptSuffix, []byte("2026-01-04T10:00:00Z\tnote\tFixed\t-\n"), 0o644); err != nil {
+		t.Fatalf("write fixed copy: %v", err)
+	}
+	results, err = Fsck(dir)
+	if err != nil {
+		t.Fatalf("second fsck: %v", err)
+	}
+	if len(results) != 1 || results[0].Recovered != 1 || len(results[0].Unrecovered) != 0 {
+		t.Fatalf("unexpected second fsck: %#v", results)
+	}
+	if _, err := os.Stat(path + CorruptSuffix); !os.IsNotExist(err) {
+		t.Fatalf("expected the recovered copy removed, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+// RetagResult counts the entries a retag changed.
+type RetagResult struct {
+	Facts        int
+	DailyEntries int
+}
+
+// Retag replaces each of the from tags with to on every memory fact and
+// daily log entry, so topics that drifted apart (locations and location)
+// count and supersede as one. An entry that already has to just loses the
+// from tags. Tags are normalized like entry tags.
+func (s *Store) Retag(from []string, to string) (RetagResult, error) {
+	var result RetagResult
+	targets := NormalizeTags([]string{to})
+	if len(targets) == 0 {
+		return result, errors.New("target tag is required")
+	}
+	to = targets[0]
+	renamed := make(map[string]bool, len(from))
+	for _, tag := range NormalizeTags(from) {
+		if tag != to {
+			renamed[tag] = true
+		}
+	}
+	if len(renamed) == 0 {
+		return result, fmt.Errorf("no tags to fold into %s", to)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	memoryPath := filepath.Join(s.dir, config.MemoryFilePath)
+	changed, err := retagFile(memoryPath, renamed, to)
+	if err != nil {
+		return result, err
+	}
+	result.Facts = changed
+	if changed > 0 {
+		facts, err := s.loadMemoryFacts()
+		if err != nil {
+			return result, err
+		}
+		s.memoryFacts = facts
+	}
+
+	dailyDir, err := s.dailyDirPath()
+	if err != nil {
+		return result, err
+	}
+	files, err := os.ReadDir(dailyDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return result, fmt.Errorf("read daily log directory %s: %w", dailyDir, err)
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".tsv") {
+			continue
+		}
+		changed, err := retagFile(filepath.Join(dailyDir, file.Name()), renamed, to)
+		if err != nil {
+			return result, err
+		}
+		result.DailyEntries += changed
+	}
+	if result.DailyEntries > 0 && !s.uncached {
+		for i, entry := range s.dailyLog {
+			if tags, ok := retagTags(entry.Tags, renamed, to); ok {
+				s.dailyLog[i].Tags = tags
+			}
+		}
+	}
+
+	logging.Logger().Debug(
+		"memory write",
+		"operation", "retag",
+		"from", strings.Join(NormalizeTags(from), ","),
+		"to", to,
+		"facts", result.Facts,
+		"daily_entries", result.DailyEntries,
+	)
+	return result, nil
+}
+
+// retagFile rewrites the memory file at path with renamed tags replaced by
+// to and returns how many of its entries changed. A missing file has none.
+func retagFile(path string, renamed map[string]bool, to string) (int, error) {
+	entries, err := loadTSVFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for i, entry := range entries {
+		if tags, ok := retagTags(entry.Tags, renamed, to); ok {
+			entries[i].Tags = tags
+			changed++
+		}
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, writeTSVFile(path, entries)
+}
+
+// retagTags returns tags with renamed tags replaced by to, keeping their
+// position so a renamed primary tag stays primary. ok is false when no tag
+// was renamed.
+func retagTags(tags []string, renamed map[string]bool, to string) (retagged []string, ok bool) {
+	retagged = make([]string, len(tags))
+	for i, tag := range tags {
+		if renamed[tag] {
+			tag = to
+			ok = true
+		}
+		retagged[i] = tag
+	}
+	if !ok {
+		return tags, false
+	}
+	return NormalizeTags(retagged), true
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetagFoldsTagsInFactsAndDailyLogs(t *testing.T) {
+	dir := t.TempDir()
+	store := mustNewStore(t, dir)
+	now := time.Now()
+	for _, entry := range []LogEntry{
+		{Timestamp: now.Add(-2 * time.Hour), Tags: []string{"locations"}, Text: "Lives in Oslo"},
+		{Timestamp: now.Add(-time.Hour), Tags: []string{"location"}, Text: "Moved to Bergen"},
+		{Timestamp: now.Add(-time.Hour), Tags: []string{"diet", "Locations"}, Text: "Eats local"},
+	} {
+		if err := store.AppendMemory(entry); err != nil {
+			t.Fatalf("append memory: %v", err)
+		}
+	}
+	for _, entry := range []LogEntry{
+		{Timestamp: now, Tags: []string{"note", "loc", "location"}, Text: "Looked at flats"},
+		{Timestamp: now, Tags: []string{"note"}, Text: "Unrelated"},
+	} {
+		if err := store.AppendDailyLog(entry); err != nil {
+			t.Fatalf("append daily log: %v", err)
+		}
+	}
+
+	result, err := store.Retag([]string{"Locations", "loc", "location"}, "location")
+	if err != nil {
+		t.Fatalf("retag: %v", err)
+	}
+	if result.Facts != 2 || result.DailyEntries != 1 {
+		t.Fatalf("unexpected result %#v", result)
+	}
+
+	tags := store.FactTags()
+	if tags["location"] != 2 || tags["locations"] != 0 {
+		t.Fatalf("expected locations folded into location, got %#v", tags)
+	}
+	var active []string
+	for _, fact := range store.ActiveFacts(now) {
+		active = append(active, fact.Text)
+	}
+	if got := strings.Join(active, "|"); got != "Moved to Bergen|Eats local" {
+		t.Fatalf("expected the newer location to supersede the older, got %q", got)
+	}
+
+	daily := store.DailyLogsByDate([]time.Time{now})
+	if len(daily) != 2 || strings.Join(daily[0].Tags, ",") != "note,location" {
+		t.Fatalf("expected duplicate tags collapsed in the daily log, got %#v", daily)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "daily", now.Format("2006-01-02")+".tsv"))
+	if err != nil {
+		t.Fatalf("read daily log: %v", err)
+	}
+	if !strings.Contains(string(raw), "note,location\tLooked at flats") {
+		t.Fatalf("expected the daily log rewritten, got %q", raw)
+	}
+
+	reopened := mustNewStore(t, dir)
+	if got := reopened.FactTags(); got["location"] != 2 || got["diet"] != 1 {
+		t.Fatalf("expected the retag on disk, got %#v", got)
+	}
+}
+
+func TestRetagRejectsNothingToFold(t *testing.T) {
+	store := mustNewStore(t, t.TempDir())
+	if _, err := store.Retag([]string{"location"}, "Location"); err == nil {
+		t.Fatal("expected an error when the only tag is the target")
+	}
+	if _, err := store.Retag([]string{"locations"}, " "); err == nil {
+		t.Fatal("expected an error without a target tag")
+	}
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// TagRule sets retention and prompt inclusion for daily log entries whose
+// first tag matches.
+type TagRule struct {
+	// Retention deletes matching entries older than this; 0 keeps them.
+	Retention time.Duration
+	// ExcludeFromPrompt keeps matching entries out of model prompts.
+	ExcludeFromPrompt bool
+}
+
+// SetTagRules replaces the per-tag daily log rules. Tag names are normalized
+// like entry tags.
+func (s *Store) SetTagRules(rules map[string]TagRule) {
+	normalized := make(map[string]TagRule, len(rules))
+	for tag, rule := range rules {
+		if names := NormalizeTags([]string{tag}); len(names) == 1 {
+			normalized[names[0]] = rule
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tagRules = normalized
+}
+
+// DailyLogsForPrompt is DailyLogsByDate without entries whose tag rule
+// excludes them from prompts.
+func (s *Store) DailyLogsForPrompt(dates []time.Time) []LogEntry {
+	entries := s.DailyLogsByDate(dates)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.tagRules) == 0 {
+		return entries
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		if s.ruleFor(entry).ExcludeFromPrompt {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	return kept
+}
+
+// PruneDailyLogs deletes daily log entries older than their tag's retention
+// and returns how many were removed. Files left without entries are removed.
+func (s *Store) PruneDailyLogs(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hasRetention := false
+	for _, rule := range s.tagRules {
+		if rule.Retention > 0 {
+			hasRetention = true
+			break
+		}
+	}
+	if !hasRetention {
+		return 0, nil
+	}
+
+	dailyDir, err := s.dailyDirPath()
+	if err != nil {
+		return 0, err
+	}
+	files, err := os.ReadDir(dailyDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read daily log directory %s: %w", dailyDir, err)
+	}
+
+	removed := 0
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".tsv") {
+			continue
+		}
+		path := filepath.Join(dailyDir, file.Name())
+		entries, err := loadTSVFile(path)
+		if err != nil {
+			return removed, err
+		}
+		kept := make([]LogEntry, 0, len(entries))
+		for _, entry := range entries {
+			rule := s.ruleFor(entry)
+			if rule.Retention > 0 && entry.Timestamp.Before(now.Add(-rule.Retention)) {
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		if len(kept) == len(entries) {
+			continue
+		}
+		if err := writeTSVFile(path, kept); err != nil {
+			return removed, err
+		}
+		removed += len(entries) - len(kept)
+	}
+
+	if removed > 0 {
+		kept := make([]LogEntry, 0, len(s.dailyLog))
+		for _, entry := range s.dailyLog {
+			rule := s.ruleFor(entry)
+			if rule.Retention > 0 && entry.Timestamp.Before(now.Add(-rule.Retention)) {
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		s.dailyLog = kept
+		logging.Logger().Info("pruned daily log entries", "removed", removed)
+	}
+	return removed, nil
+}
+
+// ruleFor returns the rule for entry's first tag. Callers hold s.mu.
+func (s *Store) ruleFor(entry LogEntry) TagRule {
+	if len(entry.Tags) == 0 || len(s.tagRules) == 0 {
+		return TagRule{}
+	}
+	// Hand-edited files may hold tags that were never normalized.
+	tags := NormalizeTags(entry.Tags[:1])
+	if len(tags) == 0 {
+		return TagRule{}
+	}
+	return s.tagRules[tags[0]]
+}
+
+// writeTSVFile replaces the memory file at path with entries, oldest
+// first. A file left with no entries is removed.
+func writeTSVFile(path string, entries []LogEntry) error {
+	if len(entries) == 0 {
+		if err := store.RemoveFile(path); err != nil {
+			return fmt.Errorf("remove %s: %w", filepath.Base(path), err)
+		}
+		return nil
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	rows := [][]string{{"ts", "tags", "text", "kv"}}
+	for _, entry := range entries {
+		rows = append(rows, entry.MarshalTSV())
+	}
+	data, err := marshalTSVRows(rows...)
+	if err != nil {
+		return err
+	}
+	if err := store.WriteFile(path, data); err != nil {
+		return fmt.Errorf("rewrite %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPruneDailyLogsAppliesTagRetention(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	old := now.AddDate(0, 0, -40)
+	oldPath := filepath.Join(dir, "daily", old.Format("2006-01-02")+".tsv")
+	writeTSVTestFile(t, oldPath, [][]string{
+		{old.Format(time.RFC3339Nano), "chores", "Took out recycling", "-"},
+		{old.Add(time.Minute).Format(time.RFC3339Nano), "Health", "Blood pressure 120/80", "-"},
+	})
+	recentPath := filepath.Join(dir, "daily", now.Format("2006-01-02")+".tsv")
+	writeTSVTestFile(t, recentPath, [][]string{
+		{now.Format(time.RFC3339Nano), "chores", "Vacuumed", "-"},
+	})
+	store := mustNewStore(t, dir)
+	store.SetTagRules(map[string]TagRule{
+		"Chores": {Retention: 30 * 24 * time.Hour},
+		"health": {Retention: 5 * 365 * 24 * time.Hour, ExcludeFromPrompt: true},
+	})
+
+	removed, err := store.PruneDailyLogs(now)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected one entry removed, got %d", removed)
+	}
+	remaining, err := loadTSVFile(oldPath)
+	if err != nil {
+		t.Fatalf("load pruned file: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].Tags[0] != "health" {
+		t.Fatalf("expected only the health entry to survive, got %#v", remaining)
+	}
+	if _, err := os.Stat(recentPath); err != nil {
+		t.Fatalf("expected recent file untouched: %v", err)
+	}
+	if logs := store.DailyLogsByDate([]time.Time{old}); len(logs) != 1 {
+		t.Fatalf("expected in-memory log to match disk, got %#v", logs)
+	}
+	if logs := store.DailyLogsForPrompt([]time.Time{old, now}); len(logs) != 1 || logs[0].Text != "Vacuumed" {
+		t.Fatalf("expected health entries left out of the prompt, got %#v", logs)
+	}
+}
@@ -25,14 +25,31 @@ const maxLoggedChars = 200
 
 // Store manages long-term memory and daily log files.
 type Store struct {
-	dir         string
-	mu          sync.RWMutex
-	dailyLog    []LogEntry
+	dir      string
+	mu       sync.RWMutex
+	dailyLog []LogEntry
+	// uncached means dailyLog is not kept and reads load day files from
+	// disk instead.
+	uncached    bool
 	memoryFacts []LogEntry
+	redact      func(string) string
+	pendingMu   sync.Mutex
+	tagRules    map[string]TagRule
 }
 
 // New creates a Store for the given memory directory, loading existing TSV files into memory.
 func New(dir string) (*Store, error) {
+	return open(dir, true)
+}
+
+// NewUncached creates a Store that keeps memory facts in memory but reads
+// daily logs from disk on each use, loading only the days a read needs. It
+// trades speed for memory on small hosts with a long history.
+func NewUncached(dir string) (*Store, error) {
+	return open(dir, false)
+}
+
+func open(dir string, cacheDailyLog bool) (*Store, error) {
 	dir = strings.TrimSpace(dir)
 	if dir == "" {
 		return nil, errors.New("memory directory is required")
@@ -45,20 +62,30 @@ func New(dir string) (*Store, error) {
 		return nil, fmt.Errorf("memory path %s is not a directory", dir)
 	}
 
-	s := &Store{dir: dir}
-	dailyLog, err := s.loadDailyLog()
-	if err != nil {
-		return nil, err
+	s := &Store{dir: dir, uncached: !cacheDailyLog}
+	if cacheDailyLog {
+		dailyLog, err := s.loadDailyLog()
+		if err != nil {
+			return nil, err
+		}
+		s.dailyLog = dailyLog
 	}
 	memoryFacts, err := s.loadMemoryFacts()
 	if err != nil {
 		return nil, err
 	}
-	s.dailyLog = dailyLog
 	s.memoryFacts = memoryFacts
 	return s, nil
 }
 
+// SetRedactor filters daily log entries before they are written, so the
+// original text is never stored.
+func (s *Store) SetRedactor(redact func(string) string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.redact = redact
+}
+
 // AppendDailyLog appends an entry to today's daily log.
 func (s *Store) AppendDailyLog(entry LogEntry) error {
 	s.mu.Lock()
@@ -67,6 +94,10 @@ func (s *Store) AppendDailyLog(entry LogEntry) error {
 	if entry.Timestamp.IsZero() {
 		entry.Timestamp = time.Now()
 	}
+	if s.redact != nil {
+		entry.Text = s.redact(entry.Text)
+		entry.KV = s.redact(entry.KV)
+	}
 	if strings.TrimSpace(entry.Text) == "" {
 		return errors.New("entry text is required")
 	}
@@ -80,8 +111,10 @@ func (s *Store) AppendDailyLog(entry LogEntry) error {
 	if err := appendTSVRow(path, entry.MarshalTSV()); err != nil {
 		return err
 	}
-	s.dailyLog = append(s.dailyLog, entry)
-	sortEntries(s.dailyLog)
+	if !s.uncached {
+		s.dailyLog = append(s.dailyLog, entry)
+		sortEntries(s.dailyLog)
+	}
 	logging.Logger().Debug(
 		"memory write",
 		"operation", "append_daily_log",
@@ -139,8 +172,12 @@ func (s *Store) Search(query string, fromTime, toTime time.Time) ([]LogEntry, er
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	dailyLog, err := s.dailyEntries(fromBound, toBound)
+	if err != nil {
+		return nil, err
+	}
 	results := make([]LogEntry, 0)
-	for _, entry := range s.dailyLog {
+	for _, entry := range dailyLog {
 		if entry.Timestamp.Before(fromBound) || entry.Timestamp.After(toBound) {
 			continue
 		}
@@ -162,6 +199,29 @@ func (s *Store) Search(query string, fromTime, toTime time.Time) ([]LogEntry, er
 	return results, nil
 }
 
+// Entries returns daily log and memory fact entries in the inclusive
+// [fromTime, toTime] range, oldest first. A zero toTime means no upper bound.
+func (s *Store) Entries(fromTime, toTime time.Time) []LogEntry {
+	if toTime.IsZero() {
+		toTime = farFutureTime()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dailyLog := s.dailyEntriesOrEmpty(fromTime, toTime)
+	results := make([]LogEntry, 0, len(dailyLog)+len(s.memoryFacts))
+	for _, entries := range [][]LogEntry{dailyLog, s.memoryFacts} {
+		for _, entry := range entries {
+			if entry.Timestamp.Before(fromTime) || entry.Timestamp.After(toTime) {
+				continue
+			}
+			results = append(results, entry)
+		}
+	}
+	sortEntries(results)
+	return results
+}
+
 // ActiveFacts returns the deduplicated, expiry-filtered list of active persistent facts.
 func (s *Store) ActiveFacts(now time.Time) []LogEntry {
 	s.mu.RLock()
@@ -199,18 +259,25 @@ func (s *Store) DailyLogsByDate(dates []time.Time) []LogEntry {
 	defer s.mu.RUnlock()
 
 	allowed := make(map[string]struct{}, len(dates))
+	var first, last time.Time
 	for _, date := range dates {
 		if date.IsZero() {
 			continue
 		}
 		allowed[date.In(time.Local).Format("2006-01-02")] = struct{}{}
+		if first.IsZero() || date.Before(first) {
+			first = date
+		}
+		if date.After(last) {
+			last = date
+		}
 	}
 	if len(allowed) == 0 {
 		return []LogEntry{}
 	}
 
 	results := make([]LogEntry, 0)
-	for _, entry := range s.dailyLog {
+	for _, entry := range s.dailyEntriesOrEmpty(first, last) {
 		day := entry.Timestamp.In(time.Local).Format("2006-01-02")
 		if _, ok := allowed[day]; !ok {
 			continue
@@ -248,8 +315,12 @@ func (s *Store) GetDailyLogs(fromTime, toTime time.Time) ([]LogEntry, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	results := make([]LogEntry, 0, len(s.dailyLog))
-	for _, entry := range s.dailyLog {
+	dailyLog, err := s.dailyEntries(fromBound, toBound)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]LogEntry, 0, len(dailyLog))
+	for _, entry := range dailyLog {
 		if entry.Timestamp.Before(fromBound) || entry.Timestamp.After(toBound) {
 			continue
 		}
@@ -265,7 +336,36 @@ func (s *Store) dailyDirPath() (string, error) {
 	return filepath.Join(s.dir, config.DailyDirPath), nil
 }
 
+// dailyEntries returns the daily log entries that may fall in [fromTime,
+// toTime]: all cached entries, or the matching day files when uncached.
+// Callers hold s.mu and still filter by timestamp.
+func (s *Store) dailyEntries(fromTime, toTime time.Time) ([]LogEntry, error) {
+	if !s.uncached {
+		return s.dailyLog, nil
+	}
+	return s.loadDailyRange(fromTime, toTime)
+}
+
+// dailyEntriesOrEmpty is dailyEntries for readers that cannot report an
+// error; a failed read is logged and treated as no entries.
+func (s *Store) dailyEntriesOrEmpty(fromTime, toTime time.Time) []LogEntry {
+	entries, err := s.dailyEntries(fromTime, toTime)
+	if err != nil {
+		logging.Logger().Warn("failed to read daily logs", "err", err)
+		return nil
+	}
+	return entries
+}
+
 func (s *Store) loadDailyLog() ([]LogEntry, error) {
+	return s.loadDailyRange(time.Time{}, time.Time{})
+}
+
+// loadDailyRange reads the day files that may hold entries between fromTime
+// and toTime; zero times leave that end open. Files are named by the date
+// in each entry's own time zone, so a day of slack on each side keeps
+// entries written in another zone.
+func (s *Store) loadDailyRange(fromTime, toTime time.Time) ([]LogEntry, error) {
 	dailyDir, err := s.dailyDirPath()
 	if err != nil {
 		return nil, err
@@ -278,11 +378,24 @@ func (s *Store) loadDailyLog() ([]LogEntry, error) {
 		return nil, fmt.Errorf("read daily log directory %s: %w", dailyDir, err)
 	}
 
+	lowest, highest := "", ""
+	if !fromTime.IsZero() {
+		lowest = fromTime.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	if !toTime.IsZero() && toTime.Before(farFutureTime()) {
+		highest = toTime.AddDate(0, 0, 1).Format("2006-01-02")
+	}
 	names := make([]string, 0, len(files))
 	for _, file := range files {
 		if file.IsDir() || !strings.HasSuffix(file.Name(), ".tsv") {
 			continue
 		}
+		day := strings.TrimSuffix(file.Name(), ".tsv")
+		if _, err := time.Parse("2006-01-02", day); err == nil {
+			if (lowest != "" && day < lowest) || (highest != "" && day > highest) {
+				continue
+			}
+		}
 		names = append(names, file.Name())
 	}
 	sort.Strings(names)
@@ -291,7 +404,9 @@ func (s *Store) loadDailyLog() ([]LogEntry, error) {
 	for _, name := range names {
 		loaded, err := loadTSVFile(filepath.Join(dailyDir, name))
 		if err != nil {
-			return nil, err
+			// One unreadable day should not hide the others.
+			logging.Logger().Error("failed to read daily log", "file", name, "err", err)
+			continue
 		}
 		entries = append(entries, loaded...)
 	}
@@ -312,37 +427,48 @@ func (s *Store) loadMemoryFacts() ([]LogEntry, error) {
 	return entries, nil
 }
 
+// loadTSVFile returns the rows of the memory file at path. A file with rows
+// that do not parse is quarantined and the rest of its rows returned.
 func loadTSVFile(path string) ([]LogEntry, error) {
 	content, err := store.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	entries, ok := parseTSV(path, content)
+	if !ok {
+		return quarantine(path, content), nil
+	}
+	return entries, nil
+}
 
+// readTSVRows splits content into rows, skipping the header. It keeps
+// reading past a row the reader rejects and returns the first such error.
+func readTSVRows(content string) ([][]string, error) {
 	reader := csv.NewReader(strings.NewReader(content))
 	reader.Comma = '\t'
 	reader.FieldsPerRecord = -1
 
-	entries := make([]LogEntry, 0)
+	var (
+		rows     [][]string
+		firstErr error
+	)
 	for {
 		fields, err := reader.Read()
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			logging.Logger().Warn("skip malformed tsv row", "path", path, "err", err)
+			if firstErr == nil {
+				firstErr = err
+			}
 			continue
 		}
 		if len(fields) > 0 && fields[0] == "ts" {
 			continue
 		}
-		var entry LogEntry
-		if err := entry.UnmarshalTSV(fields); err != nil {
-			logging.Logger().Warn("skip malformed tsv row", "path", path, "err", err)
-			continue
-		}
-		entries = append(entries, entry)
+		rows = append(rows, fields)
 	}
-	return entries, nil
+	return rows, firstErr
 }
 
 func normalizeEntryForWrite(entry LogEntry) LogEntry {
@@ -62,6 +62,30 @@ func TestAppendDailyLogZeroTimestampWritesTSVAndUpdatesCache(t *testing.T) {
 	}
 }
 
+func TestAppendDailyLogAppliesRedactor(t *testing.T) {
+	store := mustNewStore(t, t.TempDir())
+	store.SetRedactor(func(text string) string {
+		return strings.ReplaceAll(text, "sarah@example.com", "[redacted email]")
+	})
+
+	if err := store.AppendDailyLog(LogEntry{Text: "Emailed sarah@example.com", KV: "to=sarah@example.com"}); err != nil {
+		t.Fatalf("append daily log: %v", err)
+	}
+
+	entry := store.dailyLog[0]
+	path := filepath.Join(store.dir, "daily", entry.Timestamp.Format("2006-01-02")+".tsv")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read daily log: %v", err)
+	}
+	if strings.Contains(string(raw), "sarah@example.com") || strings.Contains(entry.Text+entry.KV, "sarah@example.com") {
+		t.Fatalf("expected address to be redacted, got file %q entry %#v", raw, entry)
+	}
+	if !strings.Contains(string(raw), "Emailed [redacted email]\tto=[redacted email]") {
+		t.Fatalf("expected placeholder in daily log, got %q", raw)
+	}
+}
+
 func TestAppendDailyLogUsesProvidedTimestamp(t *testing.T) {
 	store := mustNewStore(t, t.TempDir())
 	ts := time.Date(2026, 2, 17, 10, 30, 0, 123456789, time.UTC)
@@ -262,6 +286,61 @@ func TestSearchTimeBounds(t *testing.T) {
 	}
 }
 
+func TestEntriesCombinesLogsAndFactsInRange(t *testing.T) {
+	dir := t.TempDir()
+	writeTSVTestFile(t, filepath.Join(dir, "daily", "2026-02-15.tsv"), [][]string{
+		{"2026-02-15T08:00:00Z", "event", "migration kickoff", "-"},
+	})
+	writeTSVTestFile(t, filepath.Join(dir, "daily", "2026-02-17.tsv"), [][]string{
+		{"2026-02-17T09:00:00Z", "event", "migration followup", "-"},
+	})
+	writeTSVTestFile(t, filepath.Join(dir, "memory.tsv"), [][]string{
+		{"2026-02-16T11:00:00Z", "diet", "Vegetarian", "-"},
+	})
+
+	store := mustNewStore(t, dir)
+	got := store.Entries(time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC), time.Time{})
+	if len(got) != 2 || got[0].Text != "Vegetarian" || got[1].Text != "migration followup" {
+		t.Fatalf("unexpected entries: %#v", got)
+	}
+}
+
+func TestNewUncachedReadsDailyLogsFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	writeTSVTestFile(t, filepath.Join(dir, "daily", "2026-02-10.tsv"), [][]string{
+		{"2026-02-10T08:00:00Z", "event", "migration kickoff", "-"},
+	})
+	writeTSVTestFile(t, filepath.Join(dir, "daily", "2026-02-17.tsv"), [][]string{
+		{"2026-02-17T09:00:00Z", "event", "migration followup", "-"},
+	})
+
+	store, err := NewUncached(dir)
+	if err != nil {
+		t.Fatalf("new uncached store: %v", err)
+	}
+	if store.dailyLog != nil {
+		t.Fatalf("expected no cached daily log, got %d entries", len(store.dailyLog))
+	}
+	if err := store.AppendDailyLog(LogEntry{Timestamp: time.Date(2026, 2, 18, 7, 0, 0, 0, time.UTC), Tags: []string{"event"}, Text: "migration done"}); err != nil {
+		t.Fatalf("append daily log: %v", err)
+	}
+	if store.dailyLog != nil {
+		t.Fatalf("append cached the entry")
+	}
+
+	got, err := store.GetDailyLogs(time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC), time.Time{})
+	if err != nil {
+		t.Fatalf("get daily logs: %v", err)
+	}
+	if len(got) != 2 || got[0].Text != "migration followup" || got[1].Text != "migration done" {
+		t.Fatalf("unexpected entries: %#v", got)
+	}
+	matches, err := store.Search("kickoff", time.Time{}, time.Time{})
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("expected old entry to stay searchable, got %#v (%v)", matches, err)
+	}
+}
+
 func TestActiveFactsDedupesAndFallsBackFromExpired(t *testing.T) {
 	dir := t.TempDir()
 	futureExpires := time.Date(2036, 2, 17, 0, 0, 0, 0, time.UTC).Unix()
@@ -0,0 +1,185 @@
+// Package migrate upgrades the data directory between releases. The data
+// dir records its format version in a marker file; at startup any newer
+// migrations run in order, after the data dir is backed up.
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// VersionFileName is the marker file in the data dir.
+const VersionFileName = "data_version"
+
+// Migration upgrades the data dir to Version from the version before it.
+// Apply must leave the data dir readable by this release when it returns nil.
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(dataDir string) error
+}
+
+// migrations is every data format change, oldest first, numbered from 1
+// without gaps.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "start tracking the data dir version",
+		Apply:       func(string) error { return nil },
+	},
+}
+
+// backupSkipDirs are not copied into backups: migrations never touch the
+// user's own files, and they can be large.
+var backupSkipDirs = map[string]bool{"workspace": true, "trash": true}
+
+// Result describes a Run.
+type Result struct {
+	From, To int
+	// Backup is the copy made before migrating, or "" if nothing ran.
+	Backup string
+}
+
+// Current is the data dir version this build reads and writes.
+func Current() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// Pending reports whether the data dir needs migrating.
+func Pending(dataDir string) (bool, error) {
+	version, fresh, err := readVersion(dataDir)
+	if err != nil {
+		return false, err
+	}
+	return !fresh && version < Current(), nil
+}
+
+// Run brings dataDir up to Current. A new data dir is stamped without
+// migrating. Otherwise dataDir is copied into backupRoot first and each
+// pending migration runs in order, recording progress as it goes.
+func Run(dataDir, backupRoot string, now time.Time) (Result, error) {
+	return run(dataDir, backupRoot, migrations, now)
+}
+
+func run(dataDir, backupRoot string, list []Migration, now time.Time) (Result, error) {
+	current := list[len(list)-1].Version
+	version, fresh, err := readVersion(dataDir)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{From: version, To: version}
+	if fresh {
+		result.To = current
+		return result, writeVersion(dataDir, current)
+	}
+	if version > current {
+		return result, fmt.Errorf("data dir %s is at version %d, newer than this claw supports (%d). Update claw or restore an older backup", dataDir, version, current)
+	}
+	if version == current {
+		return result, nil
+	}
+
+	result.Backup = filepath.Join(backupRoot, fmt.Sprintf("data-v%d-%s", version, now.Format("20060102-150405")))
+	if err := backup(dataDir, result.Backup); err != nil {
+		return result, fmt.Errorf("back up data dir before migrating: %w", err)
+	}
+	for _, migration := range list {
+		if migration.Version <= version {
+			continue
+		}
+		if err := migration.Apply(dataDir); err != nil {
+			return result, fmt.Errorf("migrate data dir to version %d (%s): %w. The data dir is at version %d; a backup from before migrating is in %s", migration.Version, migration.Description, err, result.To, result.Backup)
+		}
+		if err := writeVersion(dataDir, migration.Version); err != nil {
+			return result, err
+		}
+		result.To = migration.Version
+	}
+	return result, nil
+}
+
+// readVersion returns the recorded version. fresh is true for a data dir
+// that does not exist or is empty; an existing one without a marker
+// predates versioning and is version 0.
+func readVersion(dataDir string) (version int, fresh bool, err error) {
+	raw, err := os.ReadFile(filepath.Join(dataDir, VersionFileName))
+	if err == nil {
+		version, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+		if err != nil || version < 0 {
+			return 0, false, fmt.Errorf("data dir version file %s is malformed", filepath.Join(dataDir, VersionFileName))
+		}
+		return version, false, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return 0, false, fmt.Errorf("read data dir version: %w", err)
+	}
+	entries, err := os.ReadDir(dataDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, true, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read data dir: %w", err)
+	}
+	return 0, len(entries) == 0, nil
+}
+
+func writeVersion(dataDir string, version int) error {
+	if err := store.WriteFile(filepath.Join(dataDir, VersionFileName), []byte(strconv.Itoa(version)+"\n")); err != nil {
+		return fmt.Errorf("write data dir version: %w", err)
+	}
+	return nil
+}
+
+// backup copies the regular files under src into dst, keeping modes.
+func backup(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if entry.IsDir() {
+			if backupSkipDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0o700)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
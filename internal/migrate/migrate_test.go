@@ -0,0 +1,117 @@
+package migrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func readVersionFile(t *testing.T, dataDir string) string {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join(dataDir, VersionFileName))
+	if err != nil {
+		t.Fatalf("read version: %v", err)
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+func TestRunStampsFreshDataDir(t *testing.T) {
+	home := t.TempDir()
+	dataDir := filepath.Join(home, "data")
+	result, err := Run(dataDir, filepath.Join(home, "backups"), time.Now())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.To != Current() || result.Backup != "" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if got := readVersionFile(t, dataDir); got != "1" {
+		t.Fatalf("unexpected version %q", got)
+	}
+	if pending, err := Pending(dataDir); err != nil || pending {
+		t.Fatalf("expected nothing pending, got %v (%v)", pending, err)
+	}
+}
+
+func TestRunMigratesInOrderAfterBackup(t *testing.T) {
+	home := t.TempDir()
+	dataDir := filepath.Join(home, "data")
+	writeFile(t, filepath.Join(dataDir, "agents", "default", "memory", "memory.tsv"), "old format\n")
+	writeFile(t, filepath.Join(dataDir, "agents", "default", "workspace", "big.bin"), "user file")
+
+	var order []int
+	list := []Migration{
+		{Version: 1, Description: "one", Apply: func(string) error { order = append(order, 1); return nil }},
+		{Version: 2, Description: "two", Apply: func(dir string) error {
+			order = append(order, 2)
+			return os.WriteFile(filepath.Join(dir, "agents", "default", "memory", "memory.tsv"), []byte("new format\n"), 0o644)
+		}},
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	result, err := run(dataDir, filepath.Join(home, "backups"), list, now)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.From != 0 || result.To != 2 || len(order) != 2 || order[0] != 1 {
+		t.Fatalf("unexpected result %+v order %v", result, order)
+	}
+	if result.Backup != filepath.Join(home, "backups", "data-v0-20260301-120000") {
+		t.Fatalf("unexpected backup path %q", result.Backup)
+	}
+	backedUp, err := os.ReadFile(filepath.Join(result.Backup, "agents", "default", "memory", "memory.tsv"))
+	if err != nil || string(backedUp) != "old format\n" {
+		t.Fatalf("expected old memory in backup, got %q (%v)", backedUp, err)
+	}
+	if _, err := os.Stat(filepath.Join(result.Backup, "agents", "default", "workspace")); !os.IsNotExist(err) {
+		t.Fatalf("expected workspace skipped in backup, got %v", err)
+	}
+	if got := readVersionFile(t, dataDir); got != "2" {
+		t.Fatalf("unexpected version %q", got)
+	}
+
+	// Already current: nothing runs and no backup is made.
+	order = nil
+	result, err = run(dataDir, filepath.Join(home, "backups"), list, now.Add(time.Hour))
+	if err != nil || len(order) != 0 || result.Backup != "" {
+		t.Fatalf("expected no-op, got %+v order %v (%v)", result, order, err)
+	}
+}
+
+func TestRunStopsAtFailedMigration(t *testing.T) {
+	home := t.TempDir()
+	dataDir := filepath.Join(home, "data")
+	writeFile(t, filepath.Join(dataDir, VersionFileName), "1\n")
+	list := []Migration{
+		{Version: 1, Description: "one", Apply: func(string) error { return nil }},
+		{Version: 2, Description: "two", Apply: func(string) error { return nil }},
+		{Version: 3, Description: "three", Apply: func(string) error { return errors.New("bad row") }},
+	}
+	result, err := run(dataDir, filepath.Join(home, "backups"), list, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "bad row") || !strings.Contains(err.Error(), result.Backup) {
+		t.Fatalf("expected failure naming the backup, got %v", err)
+	}
+	if got := readVersionFile(t, dataDir); got != "2" || result.To != 2 {
+		t.Fatalf("expected progress recorded at 2, got %q / %+v", got, result)
+	}
+}
+
+func TestRunRejectsNewerDataDir(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "data")
+	writeFile(t, filepath.Join(dataDir, VersionFileName), "99\n")
+	if _, err := Run(dataDir, t.TempDir(), time.Now()); err == nil || !strings.Contains(err.Error(), "newer than this claw") {
+		t.Fatalf("expected newer version error, got %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+// Package notify holds unprompted outbound messages during quiet hours and do-not-disturb, delivering them once the user is reachable again.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// DefaultFlushInterval is how often Run checks whether held messages can go out.
+const DefaultFlushInterval = time.Minute
+
+// Gate decides whether unprompted messages may be delivered now and queues
+// them otherwise. Direct replies to the user never pass through a Gate.
+type Gate struct {
+	mu       sync.Mutex
+	quiet    config.QuietHours
+	dndOn    bool
+	dndUntil time.Time
+	queue    []heldMessage
+	// writers delivers held messages by channel ID, so messages loaded from
+	// the state file reach the writer registered after a restart.
+	writers map[string]io.Writer
+	// path persists do-not-disturb and held messages; empty keeps them in
+	// memory only.
+	path string
+	now  func() time.Time
+}
+
+type heldMessage struct {
+	ChannelID string    `json:"channel_id"`
+	Text      string    `json:"text"`
+	HeldAt    time.Time `json:"held_at"`
+}
+
+// gateState is the persisted form of a gate.
+type gateState struct {
+	DND      bool          `json:"dnd,omitempty"`
+	DNDUntil time.Time     `json:"dnd_until,omitzero"`
+	Held     []heldMessage `json:"held,omitempty"`
+}
+
+// NewGate creates a gate for the given quiet-hours window.
+func NewGate(quiet config.QuietHours) *Gate {
+	return &Gate{quiet: quiet, writers: make(map[string]io.Writer), now: time.Now}
+}
+
+// LoadState restores do-not-disturb and held messages saved at path, and
+// saves every later change there, so both survive a restart. Held messages
+// wait until a writer is registered for their channel.
+func (g *Gate) LoadState(path string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.path = path
+	raw, err := store.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read notification state: %w", err)
+	}
+	var state gateState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return fmt.Errorf("decode notification state: %w", err)
+	}
+	g.dndOn = state.DND
+	g.dndUntil = state.DNDUntil
+	g.queue = append(state.Held, g.queue...)
+	if len(state.Held) > 0 {
+		logging.Logger().Info("restored held notifications", "count", len(state.Held))
+	}
+	return nil
+}
+
+// Writer wraps w so writes are held while the gate is silenced.
+func (g *Gate) Writer(channelID string, w io.Writer) io.Writer {
+	g.mu.Lock()
+	g.writers[channelID] = w
+	g.mu.Unlock()
+	return &gatedWriter{gate: g, channelID: channelID, writer: w}
+}
+
+// SetDND silences delivery for d, or until cleared when d is zero.
+func (g *Gate) SetDND(d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.dndOn = true
+	g.dndUntil = time.Time{}
+	if d > 0 {
+		g.dndUntil = g.now().Add(d)
+	}
+	g.saveLocked()
+}
+
+// ClearDND ends do-not-disturb and delivers anything held that quiet hours allow.
+func (g *Gate) ClearDND() {
+	g.mu.Lock()
+	g.dndOn = false
+	g.dndUntil = time.Time{}
+	g.saveLocked()
+	g.mu.Unlock()
+	g.Flush()
+}
+
+// Status describes the current delivery state for the /dnd command.
+func (g *Gate) Status() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	held := len(g.queue)
+
+	var state string
+	switch {
+	case g.dndActiveLocked(now) && g.dndUntil.IsZero():
+		state = "Do not disturb is on until you turn it off (/dnd off)."
+	case g.dndActiveLocked(now):
+		state = fmt.Sprintf("Do not disturb is on until %s.", g.dndUntil.Format("Mon 15:04"))
+	case g.quiet.Contains(now):
+		state = fmt.Sprintf("Quiet hours until %s.", g.quiet.End(now).Format("15:04"))
+	default:
+		state = "Notifications are on."
+	}
+	if held > 0 {
+		state += fmt.Sprintf(" %d held %s waiting.", held, pluralMessages(held))
+	}
+	return state
+}
+
+// Silenced reports whether unprompted messages are being held right now.
+func (g *Gate) Silenced() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.silencedLocked(g.now())
+}
+
+// Flush delivers held messages in order if the gate is no longer silenced.
+// Messages for a channel without a writer stay held. It returns how many
+// were delivered.
+func (g *Gate) Flush() int {
+	g.mu.Lock()
+	if g.silencedLocked(g.now()) || len(g.queue) == 0 {
+		g.mu.Unlock()
+		return 0
+	}
+	pending := g.queue
+	g.queue = nil
+	writers := make(map[string]io.Writer, len(g.writers))
+	for channelID, w := range g.writers {
+		writers[channelID] = w
+	}
+	g.mu.Unlock()
+
+	var kept []heldMessage
+	delivered := 0
+	for i, msg := range pending {
+		writer, ok := writers[msg.ChannelID]
+		if !ok {
+			kept = append(kept, msg)
+			continue
+		}
+		if _, err := writer.Write([]byte(msg.Text)); err != nil {
+			logging.Logger().Warn("deliver held notification failed", "channel_id", msg.ChannelID, "err", err)
+			// Keep the failed message and everything after it for the next flush.
+			kept = append(kept, pending[i:]...)
+			break
+		}
+		delivered++
+	}
+
+	g.mu.Lock()
+	g.queue = append(kept, g.queue...)
+	if delivered > 0 {
+		g.saveLocked()
+	}
+	g.mu.Unlock()
+	if delivered > 0 {
+		logging.Logger().Info("delivered held notifications", "count", delivered)
+	}
+	return delivered
+}
+
+// Run flushes held messages every interval until ctx is done.
+func (g *Gate) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.Flush()
+		}
+	}
+}
+
+func (g *Gate) silencedLocked(now time.Time) bool {
+	return g.dndActiveLocked(now) || g.quiet.Contains(now)
+}
+
+func (g *Gate) dndActiveLocked(now time.Time) bool {
+	if !g.dndOn {
+		return false
+	}
+	if !g.dndUntil.IsZero() && !now.Before(g.dndUntil) {
+		g.dndOn = false
+		g.dndUntil = time.Time{}
+		g.saveLocked()
+		return false
+	}
+	return true
+}
+
+// saveLocked persists the gate state when a path is configured. A failed
+// save is logged: delivery must not depend on the disk. Callers hold g.mu.
+func (g *Gate) saveLocked() {
+	if strings.TrimSpace(g.path) == "" {
+		return
+	}
+	encoded, err := json.MarshalIndent(gateState{DND: g.dndOn, DNDUntil: g.dndUntil, Held: g.queue}, "", "  ")
+	if err == nil {
+		err = store.WriteFile(g.path, append(encoded, '\n'))
+	}
+	if err != nil {
+		logging.Logger().Warn("failed to save notification state", "err", err)
+	}
+}
+
+type gatedWriter struct {
+	gate      *Gate
+	channelID string
+	writer    io.Writer
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	g := w.gate
+	g.mu.Lock()
+	if g.silencedLocked(g.now()) {
+		g.queue = append(g.queue, heldMessage{
+			ChannelID: w.channelID,
+			Text:      string(p),
+			HeldAt:    g.now().UTC(),
+		})
+		g.saveLocked()
+		g.mu.Unlock()
+		logging.Logger().Info("holding notification", "channel_id", w.channelID)
+		return len(p), nil
+	}
+	g.mu.Unlock()
+	// Deliver anything still held first so messages arrive in order.
+	g.Flush()
+	return w.writer.Write(p)
+}
+
+func pluralMessages(n int) string {
+	if n == 1 {
+		return "message"
+	}
+	return "messages"
+}
@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+func TestGateHoldsDuringDNDAndFlushesInOrder(t *testing.T) {
+	g := NewGate(config.QuietHours{})
+	var out bytes.Buffer
+	w := g.Writer("cli", &out)
+
+	g.SetDND(0)
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatalf("write first: %v", err)
+	}
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatalf("write second: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected nothing delivered during dnd, got %q", out.String())
+	}
+	if got := g.Status(); !strings.Contains(got, "2 held messages") {
+		t.Fatalf("expected held count in status, got %q", got)
+	}
+
+	g.ClearDND()
+	if _, err := w.Write([]byte("third\n")); err != nil {
+		t.Fatalf("write third: %v", err)
+	}
+	if out.String() != "first\nsecond\nthird\n" {
+		t.Fatalf("unexpected delivery order: %q", out.String())
+	}
+}
+
+func TestGateHoldsDuringQuietHours(t *testing.T) {
+	quiet, err := config.ParseQuietHours("22:00-08:00")
+	if err != nil {
+		t.Fatalf("parse quiet hours: %v", err)
+	}
+	g := NewGate(quiet)
+	now := time.Date(2026, 3, 1, 23, 30, 0, 0, time.Local)
+	g.now = func() time.Time { return now }
+
+	var out bytes.Buffer
+	w := g.Writer("cli", &out)
+	if _, err := w.Write([]byte("briefing\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if !g.Silenced() || out.Len() != 0 {
+		t.Fatalf("expected message held during quiet hours, got %q", out.String())
+	}
+	if got := g.Flush(); got != 0 {
+		t.Fatalf("expected no flush during quiet hours, got %d", got)
+	}
+
+	now = time.Date(2026, 3, 2, 8, 1, 0, 0, time.Local)
+	if got := g.Flush(); got != 1 {
+		t.Fatalf("expected one message flushed, got %d", got)
+	}
+	if out.String() != "briefing\n" {
+		t.Fatalf("unexpected delivery: %q", out.String())
+	}
+}
+
+func TestGateTimedDNDExpires(t *testing.T) {
+	g := NewGate(config.QuietHours{})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	g.now = func() time.Time { return now }
+
+	g.SetDND(time.Hour)
+	if !g.Silenced() {
+		t.Fatalf("expected silenced during timed dnd")
+	}
+	now = now.Add(time.Hour)
+	if g.Silenced() {
+		t.Fatalf("expected dnd to expire")
+	}
+	if got := g.Status(); got != "Notifications are on." {
+		t.Fatalf("unexpected status: %q", got)
+	}
+}
+
+func TestGateStateSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.json")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+
+	g := NewGate(config.QuietHours{})
+	g.now = func() time.Time { return now }
+	if err := g.LoadState(path); err != nil {
+		t.Fatalf("load missing state: %v", err)
+	}
+	var before bytes.Buffer
+	w := g.Writer("telegram-1", &before)
+	g.SetDND(2 * time.Hour)
+	if _, err := w.Write([]byte("backup finished\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// A new process restores do-not-disturb and delivers the held message
+	// through the writer registered for its channel once it ends.
+	restarted := NewGate(config.QuietHours{})
+	restarted.now = func() time.Time { return now.Add(time.Hour) }
+	if err := restarted.LoadState(path); err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	var after bytes.Buffer
+	restarted.Writer("telegram-1", &after)
+	if !restarted.Silenced() || !strings.Contains(restarted.Status(), "until") || !strings.Contains(restarted.Status(), "1 held message") {
+		t.Fatalf("expected dnd and the held message restored, got %q", restarted.Status())
+	}
+	restarted.now = func() time.Time { return now.Add(3 * time.Hour) }
+	if got := restarted.Flush(); got != 1 || after.String() != "backup finished\n" || before.Len() != 0 {
+		t.Fatalf("expected the held message delivered after restart, got %d %q", got, after.String())
+	}
+
+	// Nothing is delivered twice after another restart.
+	again := NewGate(config.QuietHours{})
+	if err := again.LoadState(path); err != nil {
+		t.Fatalf("load state again: %v", err)
+	}
+	if got := again.Status(); got != "Notifications are on." {
+		t.Fatalf("expected a clean state, got %q", got)
+	}
+}
@@ -0,0 +1,137 @@
+// Package outputs keeps the full text of tool results that were too long to
+// give to the model, so the user can read them later without another turn.
+package outputs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxKept is how many full outputs are kept; older ones are deleted.
+const MaxKept = 50
+
+// nameLayout starts every file name so names sort by time.
+const nameLayout = "20060102T150405.000000000Z"
+
+// Output is one saved tool result.
+type Output struct {
+	Path      string
+	Tool      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// Store saves full outputs as one text file each under a directory.
+type Store struct {
+	mu     sync.Mutex
+	dir    string
+	redact func(string) string
+}
+
+// New creates an output store backed by dir.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// SetRedactor filters outputs before they are written, so the original
+// text is never stored.
+func (s *Store) SetRedactor(redact func(string) string) {
+	s.redact = redact
+}
+
+// Save writes output from tool and returns its path. It keeps only the
+// newest MaxKept outputs.
+func (s *Store) Save(tool, output string, now time.Time) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return "", fmt.Errorf("create tool outputs dir: %w", err)
+	}
+	path := filepath.Join(s.dir, now.UTC().Format(nameLayout)+"-"+safeName(tool)+".txt")
+	if s.redact != nil {
+		output = s.redact(output)
+	}
+	if err := os.WriteFile(path, []byte(output), 0o600); err != nil {
+		return "", fmt.Errorf("write tool output: %w", err)
+	}
+	items, err := s.list()
+	if err != nil {
+		return "", err
+	}
+	for _, old := range items[min(len(items), MaxKept):] {
+		if err := os.Remove(old.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("remove old tool output: %w", err)
+		}
+	}
+	return path, nil
+}
+
+// List returns up to limit outputs, newest first. A limit of zero or less
+// returns all of them.
+func (s *Store) List(limit int) ([]Output, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (s *Store) list() ([]Output, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read tool outputs dir: %w", err)
+	}
+	items := make([]Output, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		base, ok := strings.CutSuffix(name, ".txt")
+		if !ok || !entry.Type().IsRegular() || len(base) <= len(nameLayout)+1 {
+			continue
+		}
+		createdAt, err := time.Parse(nameLayout, base[:len(nameLayout)])
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		items = append(items, Output{
+			Path:      filepath.Join(s.dir, name),
+			Tool:      base[len(nameLayout)+1:],
+			Size:      info.Size(),
+			CreatedAt: createdAt,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.After(items[j].CreatedAt) })
+	return items, nil
+}
+
+// safeName keeps tool names usable as part of a file name.
+func safeName(tool string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, tool)
+	if cleaned == "" {
+		return "tool"
+	}
+	return cleaned
+}
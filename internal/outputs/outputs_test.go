@@ -0,0 +1,81 @@
+package outputs
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/redact"
+)
+
+func TestSaveListAndPrune(t *testing.T) {
+	s := New(t.TempDir())
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	first, err := s.Save("run_command", "first", now)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := s.Save("mcp/fetch page", "second output", now.Add(time.Second)); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	items, err := s.List(0)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 2 || items[0].Tool != "mcp_fetch_page" || items[0].Size != 13 || !items[0].CreatedAt.Equal(now.Add(time.Second)) {
+		t.Fatalf("unexpected list: %#v", items)
+	}
+	if items[1].Path != first {
+		t.Fatalf("expected oldest last, got %#v", items[1])
+	}
+	if limited, _ := s.List(1); len(limited) != 1 || limited[0].Tool != "mcp_fetch_page" {
+		t.Fatalf("unexpected limited list: %#v", limited)
+	}
+
+	for i := range MaxKept {
+		if _, err := s.Save("read_file", "x", now.Add(time.Minute+time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+	items, err = s.List(0)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != MaxKept || items[len(items)-1].Tool != "read_file" {
+		t.Fatalf("expected the oldest outputs to be pruned, got %d", len(items))
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, got %v", first, err)
+	}
+}
+
+func TestSaveRedactsBeforeWriting(t *testing.T) {
+	redactor, err := redact.New([]string{redact.KindEmail}, []string{`acct-\d+`})
+	if err != nil {
+		t.Fatalf("redactor: %v", err)
+	}
+	s := New(t.TempDir())
+	s.SetRedactor(redactor.Redact)
+
+	path, err := s.Save("run_command", "owner ada@example.com, account acct-4411", time.Now())
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if strings.Contains(string(raw), "ada@example.com") || strings.Contains(string(raw), "acct-4411") {
+		t.Fatalf("expected output to be redacted on disk, got %q", raw)
+	}
+}
+
+func TestListMissingDir(t *testing.T) {
+	items, err := New(t.TempDir() + "/missing").List(5)
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected no outputs, got %#v err=%v", items, err)
+	}
+}
@@ -0,0 +1,273 @@
+// Package patch parses unified diffs and applies their hunks to text, tolerating moved lines and small context drift the way patch(1) does.
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxFuzz is how many context lines at each end of a hunk may be ignored
+// when the hunk does not match as written, the same limit patch(1) uses.
+const maxFuzz = 2
+
+// FileDiff is the set of hunks for one file. OldPath is empty when the file
+// is created and NewPath is empty when it is deleted.
+type FileDiff struct {
+	OldPath string
+	NewPath string
+	Hunks   []Hunk
+}
+
+// Hunk is one @@ section of a diff.
+type Hunk struct {
+	OldStart int
+	NewStart int
+	// Lines keep their ' ', '-', or '+' prefix.
+	Lines []string
+	// Header is the @@ line, for reporting.
+	Header string
+}
+
+// Result describes how one hunk applied.
+type Result struct {
+	Hunk Hunk
+	// Applied is false for a rejected hunk.
+	Applied bool
+	// Offset is how many lines from its stated position the hunk matched.
+	Offset int
+	// Fuzz is how many context lines at each end had to be ignored.
+	Fuzz int
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Parse reads a unified diff, such as git diff or diff -u output. Text
+// before the first --- line of each file is ignored.
+func Parse(diff string) ([]FileDiff, error) {
+	lines := strings.Split(strings.ReplaceAll(diff, "\r\n", "\n"), "\n")
+	var files []FileDiff
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "--- ") || i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+			continue
+		}
+		file := FileDiff{
+			OldPath: diffPath(lines[i][4:]),
+			NewPath: diffPath(lines[i+1][4:]),
+		}
+		if file.OldPath == "" && file.NewPath == "" {
+			return nil, fmt.Errorf("line %d: diff has no file path", i+1)
+		}
+		i += 2
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@") {
+			hunk, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			file.Hunks = append(file.Hunks, hunk)
+			i = next
+		}
+		if len(file.Hunks) == 0 {
+			return nil, fmt.Errorf("diff for %s has no hunks", file.path())
+		}
+		files = append(files, file)
+		i--
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no file diffs found; expected --- and +++ headers followed by @@ hunks")
+	}
+	return files, nil
+}
+
+// parseHunk reads the hunk starting at lines[start] and returns the index of
+// the line after it.
+func parseHunk(lines []string, start int) (Hunk, int, error) {
+	match := hunkHeader.FindStringSubmatch(lines[start])
+	if match == nil {
+		return Hunk{}, 0, fmt.Errorf("line %d: malformed hunk header %q", start+1, lines[start])
+	}
+	hunk := Hunk{Header: lines[start]}
+	hunk.OldStart, _ = strconv.Atoi(match[1])
+	oldCount := countOrOne(match[2])
+	hunk.NewStart, _ = strconv.Atoi(match[3])
+	newCount := countOrOne(match[4])
+
+	i := start + 1
+	for ; i < len(lines) && (oldCount > 0 || newCount > 0); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, `\`) {
+			// "\ No newline at end of file"
+			continue
+		}
+		if line == "" {
+			// Editors and models often strip the space from blank context.
+			line = " "
+		}
+		switch line[0] {
+		case ' ':
+			oldCount--
+			newCount--
+		case '-':
+			oldCount--
+		case '+':
+			newCount--
+		default:
+			return Hunk{}, 0, fmt.Errorf("line %d: unexpected %q in hunk %s", i+1, line, hunk.Header)
+		}
+		hunk.Lines = append(hunk.Lines, line)
+	}
+	if oldCount != 0 || newCount != 0 {
+		return Hunk{}, 0, fmt.Errorf("hunk %s: line counts do not match its header", hunk.Header)
+	}
+	for i < len(lines) && strings.HasPrefix(lines[i], `\`) {
+		i++
+	}
+	return hunk, i, nil
+}
+
+func countOrOne(raw string) int {
+	if raw == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(raw)
+	return n
+}
+
+// diffPath strips the a/ or b/ prefix and any trailing timestamp from a
+// header path. /dev/null becomes "".
+func diffPath(raw string) string {
+	path := raw
+	if tab := strings.IndexByte(path, '\t'); tab >= 0 {
+		path = path[:tab]
+	}
+	path = strings.TrimSpace(path)
+	if path == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		path = path[2:]
+	}
+	return path
+}
+
+// path returns the path the diff is about.
+func (f FileDiff) path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Apply applies hunks to content in order and returns the new content with a
+// result per hunk. Rejected hunks leave their part of content unchanged.
+func Apply(content string, hunks []Hunk) (string, []Result) {
+	lines, trailingNewline := splitLines(content)
+	results := make([]Result, 0, len(hunks))
+	// delta tracks how earlier hunks shifted line numbers.
+	delta := 0
+	// floor keeps a hunk from matching above one already applied.
+	floor := 0
+	for _, hunk := range hunks {
+		oldLines, newLines := hunkSides(hunk.Lines)
+		expected := hunk.OldStart - 1 + delta
+		if len(oldLines) == 0 {
+			// Pure insertion; OldStart names the line after which to insert.
+			expected = hunk.OldStart + delta
+		}
+		result := Result{Hunk: hunk}
+		for fuzz := 0; fuzz <= maxFuzz && !result.Applied; fuzz++ {
+			head, tail := fuzzTrim(hunk.Lines, fuzz)
+			if head+tail > 0 && head+tail >= len(oldLines) {
+				break
+			}
+			want := oldLines[head : len(oldLines)-tail]
+			at, ok := findBlock(lines, want, expected+head, floor)
+			if !ok {
+				continue
+			}
+			replacement := newLines[head : len(newLines)-tail]
+			lines = append(lines[:at], append(append([]string{}, replacement...), lines[at+len(want):]...)...)
+			result.Applied = true
+			result.Offset = at - head - expected
+			result.Fuzz = fuzz
+			delta += len(replacement) - len(want)
+			floor = at + len(replacement)
+		}
+		results = append(results, result)
+	}
+	out := strings.Join(lines, "\n")
+	if trailingNewline && len(lines) > 0 {
+		out += "\n"
+	}
+	return out, results
+}
+
+// hunkSides returns the lines a hunk expects and the lines it leaves.
+func hunkSides(hunkLines []string) ([]string, []string) {
+	var oldLines, newLines []string
+	for _, line := range hunkLines {
+		switch line[0] {
+		case ' ':
+			oldLines = append(oldLines, line[1:])
+			newLines = append(newLines, line[1:])
+		case '-':
+			oldLines = append(oldLines, line[1:])
+		case '+':
+			newLines = append(newLines, line[1:])
+		}
+	}
+	return oldLines, newLines
+}
+
+// fuzzTrim returns how many leading and trailing lines may be dropped at
+// the given fuzz: up to fuzz context lines at each end, never a change.
+func fuzzTrim(hunkLines []string, fuzz int) (int, int) {
+	head := 0
+	for head < fuzz && head < len(hunkLines) && hunkLines[head][0] == ' ' {
+		head++
+	}
+	tail := 0
+	for tail < fuzz && tail < len(hunkLines)-head && hunkLines[len(hunkLines)-1-tail][0] == ' ' {
+		tail++
+	}
+	return head, tail
+}
+
+// findBlock finds want in lines at or after floor, trying expected first and
+// then alternately further before and after it.
+func findBlock(lines, want []string, expected, floor int) (int, bool) {
+	limit := len(lines) - len(want)
+	if limit < floor {
+		return 0, false
+	}
+	matches := func(at int) bool {
+		if at < floor || at > limit {
+			return false
+		}
+		for i, line := range want {
+			if lines[at+i] != line {
+				return false
+			}
+		}
+		return true
+	}
+	for distance := 0; distance <= len(lines); distance++ {
+		if matches(expected - distance) {
+			return expected - distance, true
+		}
+		if distance > 0 && matches(expected+distance) {
+			return expected + distance, true
+		}
+	}
+	return 0, false
+}
+
+func splitLines(content string) ([]string, bool) {
+	if content == "" {
+		return nil, true
+	}
+	trailing := strings.HasSuffix(content, "\n")
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n"), trailing
+}
@@ -0,0 +1,126 @@
+package patch
+
+import (
+	"strings"
+	"testing"
+)
+
+const original = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+}
+`
+
+func TestParseAndApply(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -3,5 +3,6 @@
+ import "fmt"
+
+ func main() {
+-	fmt.Println("hello")
++	fmt.Println("hello, world")
++	fmt.Println("bye")
+ }
+`
+	files, err := Parse(diff)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(files) != 1 || files[0].OldPath != "main.go" || files[0].NewPath != "main.go" || len(files[0].Hunks) != 1 {
+		t.Fatalf("unexpected parse: %#v", files)
+	}
+
+	got, results := Apply(original, files[0].Hunks)
+	if !results[0].Applied || results[0].Offset != 0 || results[0].Fuzz != 0 {
+		t.Fatalf("unexpected result: %#v", results[0])
+	}
+	want := strings.Replace(original, "\tfmt.Println(\"hello\")\n", "\tfmt.Println(\"hello, world\")\n\tfmt.Println(\"bye\")\n", 1)
+	if got != want {
+		t.Fatalf("unexpected content:\n%s", got)
+	}
+}
+
+func TestApplyDetectsOffsetAndFuzz(t *testing.T) {
+	content := "// header\n// more\n" + strings.Replace(original, "func main() {", "func main() { // entry", 1)
+	files, err := Parse(`--- main.go
++++ main.go
+@@ -5,3 +5,3 @@
+ func main() {
+-	fmt.Println("hello")
++	fmt.Println("hi")
+ }
+`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got, results := Apply(content, files[0].Hunks)
+	if !results[0].Applied || results[0].Fuzz != 1 || results[0].Offset != 2 {
+		t.Fatalf("expected fuzz 1 at offset 2, got %#v", results[0])
+	}
+	if !strings.Contains(got, `fmt.Println("hi")`) || !strings.Contains(got, "// entry") {
+		t.Fatalf("unexpected content:\n%s", got)
+	}
+}
+
+func TestApplyRejectsMissingLines(t *testing.T) {
+	files, err := Parse(`--- a/main.go
++++ b/main.go
+@@ -6,1 +6,1 @@
+-	fmt.Println("goodbye")
++	fmt.Println("hi")
+`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got, results := Apply(original, files[0].Hunks)
+	if results[0].Applied {
+		t.Fatalf("expected rejected hunk, got %#v", results[0])
+	}
+	if got != original {
+		t.Fatalf("expected content unchanged, got:\n%s", got)
+	}
+}
+
+func TestParseNewAndDeletedFiles(t *testing.T) {
+	files, err := Parse(`--- /dev/null
++++ b/notes.md
+@@ -0,0 +1,2 @@
++# Notes
++first
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(files) != 2 || files[0].OldPath != "" || files[1].NewPath != "" {
+		t.Fatalf("unexpected parse: %#v", files)
+	}
+	created, results := Apply("", files[0].Hunks)
+	if !results[0].Applied || created != "# Notes\nfirst\n" {
+		t.Fatalf("unexpected new file %q %#v", created, results[0])
+	}
+	deleted, results := Apply("gone\n", files[1].Hunks)
+	if !results[0].Applied || deleted != "" {
+		t.Fatalf("unexpected deleted file %q %#v", deleted, results[0])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, diff := range []string{
+		"just some text",
+		"--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n-only one line\n",
+		"--- a/x\n+++ b/x\n@@ bogus @@\n",
+	} {
+		if _, err := Parse(diff); err == nil {
+			t.Errorf("expected parse error for %q", diff)
+		}
+	}
+}
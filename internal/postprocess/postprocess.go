@@ -0,0 +1,105 @@
+// Package postprocess rewrites assistant replies before they reach a channel: stripping reasoning markers, running a custom script, enforcing a length limit, and appending a signature.
+package postprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+// DefaultCommandTimeout bounds a post-processing script when none is set.
+const DefaultCommandTimeout = 10 * time.Second
+
+const truncationMarker = "… (truncated)"
+
+var reasoningBlocks = regexp.MustCompile(`(?is)<think>.*?</think>|<thinking>.*?</thinking>|<reasoning>.*?</reasoning>`)
+
+// Pipeline is an ordered set of reply rewrites. The zero value changes nothing.
+type Pipeline struct {
+	// StripReasoning removes <think>, <thinking>, and <reasoning> blocks.
+	StripReasoning bool
+	// Command is a shell command that receives the reply on stdin and prints
+	// the replacement on stdout. Failures keep the reply unchanged.
+	Command        string
+	CommandTimeout time.Duration
+	// MaxLength caps the reply in characters, signature included; 0 disables it.
+	MaxLength int
+	// Signature is appended after a blank line.
+	Signature string
+}
+
+// Empty reports whether the pipeline has no steps.
+func (p Pipeline) Empty() bool {
+	return !p.StripReasoning && strings.TrimSpace(p.Command) == "" && p.MaxLength <= 0 && strings.TrimSpace(p.Signature) == ""
+}
+
+// Apply runs each configured step in order: strip reasoning, custom command,
+// length limit, signature.
+func (p Pipeline) Apply(ctx context.Context, text string) string {
+	if p.StripReasoning {
+		text = strings.TrimSpace(reasoningBlocks.ReplaceAllString(text, ""))
+	}
+	if command := strings.TrimSpace(p.Command); command != "" {
+		rewritten, err := p.runCommand(ctx, command, text)
+		if err != nil {
+			logging.Logger().Warn("reply post-processing command failed; sending reply unchanged", "err", err)
+		} else {
+			text = rewritten
+		}
+	}
+
+	signature := strings.TrimSpace(p.Signature)
+	suffix := ""
+	if signature != "" {
+		suffix = "\n\n" + signature
+	}
+	if p.MaxLength > 0 {
+		text = truncate(text, p.MaxLength-len([]rune(suffix)))
+	}
+	return text + suffix
+}
+
+func (p Pipeline) runCommand(ctx context.Context, command, text string) (string, error) {
+	timeout := p.CommandTimeout
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, "sh", "-c", command)
+	cmd.Stdin = strings.NewReader(text)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	rewritten := strings.TrimRight(string(out), "\n")
+	if strings.TrimSpace(rewritten) == "" {
+		return "", errors.New("command printed nothing")
+	}
+	return rewritten, nil
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	marker := []rune(truncationMarker)
+	keep := limit - len(marker) - 1
+	if keep <= 0 {
+		return string(runes[:max(limit, 0)])
+	}
+	return strings.TrimRight(string(runes[:keep]), " \n") + "\n" + truncationMarker
+}
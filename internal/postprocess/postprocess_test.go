@@ -0,0 +1,50 @@
+package postprocess
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestPipelineApply(t *testing.T) {
+	p := Pipeline{
+		StripReasoning: true,
+		Signature:      "— sent by NeoClaw",
+	}
+	got := p.Apply(context.Background(), "<thinking>\nplan the answer\n</thinking>\nThe answer is 42.")
+	if got != "The answer is 42.\n\n— sent by NeoClaw" {
+		t.Fatalf("unexpected reply: %q", got)
+	}
+	if !(Pipeline{}).Empty() || p.Empty() {
+		t.Fatalf("unexpected Empty result")
+	}
+}
+
+func TestPipelineMaxLengthKeepsSignature(t *testing.T) {
+	p := Pipeline{MaxLength: 40, Signature: "-- bot"}
+	got := p.Apply(context.Background(), strings.Repeat("word ", 30))
+	if n := utf8.RuneCountInString(got); n > 40 {
+		t.Fatalf("expected at most 40 characters, got %d: %q", n, got)
+	}
+	if !strings.HasSuffix(got, truncationMarker+"\n\n-- bot") {
+		t.Fatalf("expected truncation marker and signature, got %q", got)
+	}
+
+	short := p.Apply(context.Background(), "short")
+	if short != "short\n\n-- bot" {
+		t.Fatalf("expected short reply untouched, got %q", short)
+	}
+}
+
+func TestPipelineCommand(t *testing.T) {
+	p := Pipeline{Command: "tr a-z A-Z"}
+	if got := p.Apply(context.Background(), "hello"); got != "HELLO" {
+		t.Fatalf("expected command output, got %q", got)
+	}
+
+	failing := Pipeline{Command: "echo broken >&2; exit 3"}
+	if got := failing.Apply(context.Background(), "hello"); got != "hello" {
+		t.Fatalf("expected failed command to keep the reply, got %q", got)
+	}
+}
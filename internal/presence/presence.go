@@ -0,0 +1,127 @@
+// Package presence remembers the channel each user was last active on, so
+// unprompted messages can follow them there.
+package presence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// refreshAfter limits writes: activity on the same channel is recorded at
+// most this often.
+const refreshAfter = time.Minute
+
+// Seen is where and when a user was last active.
+type Seen struct {
+	ChannelID string    `json:"channel_id"`
+	At        time.Time `json:"at"`
+}
+
+// Store persists last activity in one JSON object keyed by user ID.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// New creates a presence store backed by path.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Record notes that userID was active on channelID at now.
+func (s *Store) Record(userID, channelID string, now time.Time) error {
+	userID = strings.TrimSpace(userID)
+	if s == nil || userID == "" || strings.TrimSpace(channelID) == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+	if last, ok := all[userID]; ok && last.ChannelID == channelID && now.Sub(last.At) < refreshAfter {
+		return nil
+	}
+	all[userID] = Seen{ChannelID: channelID, At: now.UTC()}
+	return s.save(all)
+}
+
+// Last returns where userID was last active.
+func (s *Store) Last(userID string) (Seen, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return Seen{}, false, err
+	}
+	seen, ok := all[strings.TrimSpace(userID)]
+	return seen, ok, nil
+}
+
+// Writer returns a writer that delivers to the channel userID was last
+// active on when writers has one for it, and to writers[fallback]
+// otherwise. The channel is looked up on every write, so held messages
+// follow the user too. writers must not change afterwards.
+func (s *Store) Writer(userID string, writers map[string]io.Writer, fallback string) io.Writer {
+	return &followWriter{store: s, userID: userID, writers: writers, fallback: fallback}
+}
+
+type followWriter struct {
+	store    *Store
+	userID   string
+	writers  map[string]io.Writer
+	fallback string
+}
+
+func (w *followWriter) Write(p []byte) (int, error) {
+	target := w.fallback
+	seen, ok, err := w.store.Last(w.userID)
+	if err != nil {
+		logging.Logger().Warn("failed to read presence; using the scheduled channel", "err", err)
+	} else if ok {
+		if _, exists := w.writers[seen.ChannelID]; exists {
+			target = seen.ChannelID
+		}
+	}
+	writer, ok := w.writers[target]
+	if !ok {
+		return 0, fmt.Errorf("no writer for channel %s", target)
+	}
+	return writer.Write(p)
+}
+
+func (s *Store) load() (map[string]Seen, error) {
+	all := map[string]Seen{}
+	raw, err := store.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return all, nil
+		}
+		return nil, fmt.Errorf("read presence: %w", err)
+	}
+	if err := json.Unmarshal([]byte(raw), &all); err != nil {
+		return nil, fmt.Errorf("decode presence: %w", err)
+	}
+	return all, nil
+}
+
+func (s *Store) save(all map[string]Seen) error {
+	encoded, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode presence: %w", err)
+	}
+	if err := store.WriteFile(s.path, append(encoded, '\n')); err != nil {
+		return fmt.Errorf("write presence: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package presence
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordKeepsLatestChannelPerUser(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "presence.json"))
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	if _, ok, err := s.Last("111"); err != nil || ok {
+		t.Fatalf("expected no presence yet, got %v (%v)", ok, err)
+	}
+	if err := s.Record("111", "telegram-111", now); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if err := s.Record("111", "telegram_work-111", now.Add(time.Hour)); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	// Repeated activity on the same channel is not rewritten right away.
+	if err := s.Record("111", "telegram_work-111", now.Add(time.Hour+time.Second)); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	seen, ok, err := New(s.path).Last("111")
+	if err != nil || !ok {
+		t.Fatalf("expected presence, got %v (%v)", ok, err)
+	}
+	if seen.ChannelID != "telegram_work-111" || !seen.At.Equal(now.Add(time.Hour)) {
+		t.Fatalf("unexpected presence %#v", seen)
+	}
+}
+
+func TestWriterFollowsUserAndFallsBack(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "presence.json"))
+	var home, work bytes.Buffer
+	writers := map[string]io.Writer{
+		"telegram-111":      &home,
+		"telegram_work-111": &work,
+	}
+	w := s.Writer("111", writers, "telegram-111")
+
+	if _, err := w.Write([]byte("before")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := s.Record("111", "telegram_work-111", time.Now()); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if _, err := w.Write([]byte("after")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// A channel with no writer, e.g. a bot since removed, is not followed.
+	if err := s.Record("111", "telegram_old-111", time.Now()); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if _, err := w.Write([]byte("|removed")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if home.String() != "before|removed" || work.String() != "after" {
+		t.Fatalf("unexpected delivery: home %q, work %q", home.String(), work.String())
+	}
+}
@@ -0,0 +1,170 @@
+// Package prompts stores reusable prompt templates as Markdown files and expands them with arguments.
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+const templateExt = ".md"
+
+var (
+	namePattern     = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	variablePattern = regexp.MustCompile(`\{\{\s*([a-z0-9_]+)\s*\}\}`)
+)
+
+// Template is one saved prompt.
+type Template struct {
+	Name string
+	Body string
+}
+
+// Summary returns the first non-empty line of the template body.
+func (t Template) Summary() string {
+	for _, line := range strings.Split(t.Body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// ValidateName reports whether name can be used as a template file name.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid prompt name %q (use lowercase letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// Save writes a template to dir, replacing any existing template with that name.
+func Save(dir, name, body string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return errors.New("prompt body is required")
+	}
+	if err := store.WriteFile(filepath.Join(dir, name+templateExt), []byte(body+"\n")); err != nil {
+		return fmt.Errorf("write prompt %q: %w", name, err)
+	}
+	return nil
+}
+
+// Load reads one template from dir. It returns fs.ErrNotExist if it is missing.
+func Load(dir, name string) (Template, error) {
+	if err := ValidateName(name); err != nil {
+		return Template{}, err
+	}
+	body, err := store.ReadFile(filepath.Join(dir, name+templateExt))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Template{}, fmt.Errorf("prompt %q not found: %w", name, fs.ErrNotExist)
+		}
+		return Template{}, fmt.Errorf("read prompt %q: %w", name, err)
+	}
+	return Template{Name: name, Body: strings.TrimSpace(body)}, nil
+}
+
+// List returns every template in dir sorted by name. A missing dir is empty.
+func List(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read prompts directory: %w", err)
+	}
+	var templates []Template
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), templateExt)
+		if entry.IsDir() || !ok || ValidateName(name) != nil {
+			continue
+		}
+		tmpl, err := Load(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// Expand fills template variables from args:
+//
+//	{{1}}, {{2}}, ... positional arguments
+//	{{args}}          all arguments joined by spaces
+//	{{date}}          today's date (YYYY-MM-DD)
+//	{{weekday}}       today's weekday name
+//
+// It fails if a positional argument the template needs was not supplied.
+func Expand(body string, args []string, now time.Time) (string, error) {
+	var missing []string
+	out := variablePattern.ReplaceAllStringFunc(body, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		switch name {
+		case "args":
+			return strings.Join(args, " ")
+		case "date":
+			return now.Format("2006-01-02")
+		case "weekday":
+			return now.Weekday().String()
+		}
+		if n, err := strconv.Atoi(name); err == nil && n >= 1 {
+			if n <= len(args) {
+				return args[n-1]
+			}
+			missing = append(missing, match)
+			return match
+		}
+		// Unknown names are left untouched so literal braces survive.
+		return match
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing arguments for %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// SplitArgs splits a command line into arguments, keeping double-quoted
+// phrases together.
+func SplitArgs(input string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quoted  bool
+		started bool
+	)
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if started {
+				args = append(args, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, current.String())
+	}
+	return args
+}
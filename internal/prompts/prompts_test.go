@@ -0,0 +1,68 @@
+package prompts
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSaveListLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := Save(dir, "weekly-review", "Review my week ending {{date}}.\nFocus on {{args}}."); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := Save(dir, "standup", "Draft my standup."); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	templates, err := List(dir)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "standup" || templates[1].Name != "weekly-review" {
+		t.Fatalf("unexpected templates: %#v", templates)
+	}
+	if got := templates[1].Summary(); got != "Review my week ending {{date}}." {
+		t.Fatalf("unexpected summary: %q", got)
+	}
+
+	if _, err := Load(dir, "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+	if err := Save(dir, "../escape", "x"); err == nil {
+		t.Fatalf("expected invalid name error")
+	}
+}
+
+func TestListMissingDir(t *testing.T) {
+	templates, err := List(t.TempDir() + "/nope")
+	if err != nil || len(templates) != 0 {
+		t.Fatalf("expected empty list, got %#v, %v", templates, err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	now := time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC)
+	got, err := Expand("Week of {{date}} ({{weekday}}): {{1}} then {{ 2 }}. All: {{args}}. Keep {{other}}.", []string{"gym", "taxes"}, now)
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	want := "Week of 2026-03-06 (Friday): gym then taxes. All: gym taxes. Keep {{other}}."
+	if got != want {
+		t.Fatalf("expand mismatch:\n got %q\nwant %q", got, want)
+	}
+
+	if _, err := Expand("Need {{1}} and {{2}}", []string{"one"}, now); err == nil {
+		t.Fatalf("expected missing argument error")
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	got := SplitArgs(`/prompt email "Sarah Lee" tomorrow`)
+	want := []string{"/prompt", "email", "Sarah Lee", "tomorrow"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("split mismatch: %#v", got)
+	}
+}
@@ -19,14 +19,15 @@ type anthropicProvider struct {
 }
 
 func newAnthropicProvider(cfg config.LLMProviderConfig) (Provider, error) {
-	if strings.TrimSpace(cfg.APIKey) == "" {
-		return nil, fmt.Errorf("anthropic api key is required")
+	auth, err := anthropicAuth(cfg)
+	if err != nil {
+		return nil, err
 	}
 	if strings.TrimSpace(cfg.Model) == "" {
 		return nil, fmt.Errorf("anthropic model is required")
 	}
 
-	client := anthropic.NewClient(option.WithAPIKey(cfg.APIKey))
+	client := anthropic.NewClient(auth)
 	return &anthropicProvider{
 		client:    client,
 		model:     anthropic.Model(cfg.Model),
@@ -34,6 +35,35 @@ func newAnthropicProvider(cfg config.LLMProviderConfig) (Provider, error) {
 	}, nil
 }
 
+// anthropicAuth prefers the API key, then auth_token sent as a bearer token
+// for gateways and access tokens issued outside NeoClaw, then the OAuth
+// token saved by claw login, refreshed as it nears expiry.
+func anthropicAuth(cfg config.LLMProviderConfig) (option.RequestOption, error) {
+	if apiKey := strings.TrimSpace(cfg.APIKey); apiKey != "" {
+		return option.WithAPIKey(apiKey), nil
+	}
+	if token := strings.TrimSpace(cfg.AuthToken); token != "" {
+		return option.WithAuthToken(token), nil
+	}
+	if cfg.OAuth.Enabled() {
+		return anthropicOAuth(newOAuthTokenSource(cfg.OAuth, nil)), nil
+	}
+	return nil, fmt.Errorf("anthropic api key, auth token, or oauth is required")
+}
+
+// anthropicOAuth sets a current OAuth access token on every request.
+func anthropicOAuth(source *oauthTokenSource) option.RequestOption {
+	return option.WithMiddleware(func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+		token, err := source.AccessToken(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("anthropic oauth: %w", err)
+		}
+		req.Header.Del("X-Api-Key")
+		req.Header.Set("Authorization", "Bearer "+token)
+		return next(req)
+	})
+}
+
 func newAnthropicProviderForTest(apiKey, model string, maxTokens int, baseURL string, httpClient *http.Client) (Provider, error) {
 	if strings.TrimSpace(apiKey) == "" {
 		return nil, fmt.Errorf("anthropic api key is required")
@@ -81,19 +111,19 @@ func (p *anthropicProvider) Chat(ctx context.Context, req ChatRequest) (*ChatRes
 		body.Tools = toAnthropicTools(req.Tools)
 	}
 
-	msg, err := p.client.Messages.New(ctx, body)
+	var msg *anthropic.Message
+	if onText := StreamFrom(ctx); onText != nil {
+		msg, err = p.streamMessage(ctx, body, onText)
+	} else {
+		msg, err = p.client.Messages.New(ctx, body)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	var contentParts []string
 	var calls []ToolCall
 	for _, block := range msg.Content {
 		switch v := block.AsAny().(type) {
-		case anthropic.TextBlock:
-			if v.Text != "" {
-				contentParts = append(contentParts, v.Text)
-			}
 		case anthropic.ToolUseBlock:
 			calls = append(calls, ToolCall{
 				ID:        v.ID,
@@ -110,12 +140,47 @@ func (p *anthropicProvider) Chat(ctx context.Context, req ChatRequest) (*ChatRes
 	usage.TotalTokens = usage.InputTokens + usage.OutputTokens
 
 	return &ChatResponse{
-		Content:   strings.Join(contentParts, "\n"),
+		Content:   anthropicText(msg),
 		ToolCalls: calls,
 		Usage:     usage,
 	}, nil
 }
 
+// streamMessage sends body as a streaming request, calling onText as text
+// arrives, and returns the assembled message.
+func (p *anthropicProvider) streamMessage(ctx context.Context, body anthropic.MessageNewParams, onText func(string)) (*anthropic.Message, error) {
+	stream := p.client.Messages.NewStreaming(ctx, body)
+	defer stream.Close()
+
+	msg := anthropic.Message{}
+	for stream.Next() {
+		event := stream.Current()
+		if err := msg.Accumulate(event); err != nil {
+			return nil, fmt.Errorf("read anthropic stream: %w", err)
+		}
+		if delta, ok := event.AsAny().(anthropic.ContentBlockDeltaEvent); ok {
+			if _, isText := delta.Delta.AsAny().(anthropic.TextDelta); isText {
+				onText(anthropicText(&msg))
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// anthropicText joins the text blocks of msg.
+func anthropicText(msg *anthropic.Message) string {
+	var parts []string
+	for _, block := range msg.Content {
+		if block.Type == "text" && block.Text != "" {
+			parts = append(parts, block.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
 func toAnthropicMessages(messages []ChatMessage) ([]anthropic.MessageParam, error) {
 	out := make([]anthropic.MessageParam, 0, len(messages))
 	for i := 0; i < len(messages); {
@@ -151,7 +216,7 @@ func toAnthropicMessages(messages []ChatMessage) ([]anthropic.MessageParam, erro
 				if messages[i].ToolCallID == "" {
 					return nil, fmt.Errorf("tool message requires tool_call_id")
 				}
-				blocks = append(blocks, anthropic.NewToolResultBlock(messages[i].ToolCallID, messages[i].Content, false))
+				blocks = append(blocks, anthropic.NewToolResultBlock(messages[i].ToolCallID, messages[i].ToolResultContent(), false))
 				i++
 			}
 			out = append(out, anthropic.NewUserMessage(blocks...))
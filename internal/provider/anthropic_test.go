@@ -3,9 +3,15 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
 )
 
 func TestAnthropicProviderChat_RequestAndResponse(t *testing.T) {
@@ -146,3 +152,115 @@ func TestAnthropicProviderChat_RequestAndResponse(t *testing.T) {
 		t.Fatalf("unexpected usage: %+v", resp.Usage)
 	}
 }
+
+func TestAnthropicAuthTokenSendsBearerHeader(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant","model":"m","content":[{"type":"text","text":"ok"}],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	auth, err := anthropicAuth(config.LLMProviderConfig{AuthToken: "oauth-token"})
+	if err != nil {
+		t.Fatalf("auth option: %v", err)
+	}
+	p := &anthropicProvider{
+		client:    anthropic.NewClient(auth, option.WithBaseURL(srv.URL), option.WithHTTPClient(srv.Client())),
+		model:     "m",
+		maxTokens: 16,
+	}
+	if _, err := p.Chat(context.Background(), ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: "hi"}}}); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if gotAuth != "Bearer oauth-token" {
+		t.Fatalf("expected bearer auth header, got %q", gotAuth)
+	}
+
+	if _, err := anthropicAuth(config.LLMProviderConfig{}); err == nil {
+		t.Fatalf("expected error without api key or auth token")
+	}
+}
+
+func TestAnthropicOAuthSendsSavedToken(t *testing.T) {
+	var gotAuth, gotAPIKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotAPIKey = r.Header.Get("X-Api-Key")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant","model":"m","content":[{"type":"text","text":"ok"}],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	oauth := testOAuthConfig(t, srv.URL)
+	if err := saveOAuthToken(oauth, OAuthToken{AccessToken: "subscription-token", Expiry: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("save token: %v", err)
+	}
+	auth, err := anthropicAuth(config.LLMProviderConfig{OAuth: oauth})
+	if err != nil {
+		t.Fatalf("auth option: %v", err)
+	}
+	p := &anthropicProvider{
+		client:    anthropic.NewClient(auth, option.WithBaseURL(srv.URL), option.WithHTTPClient(srv.Client())),
+		model:     "m",
+		maxTokens: 16,
+	}
+	if _, err := p.Chat(context.Background(), ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: "hi"}}}); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if gotAuth != "Bearer subscription-token" || gotAPIKey != "" {
+		t.Fatalf("expected only the oauth bearer header, got auth %q, api key %q", gotAuth, gotAPIKey)
+	}
+}
+
+func TestAnthropicProviderChat_Streams(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4-6","content":[],"stop_reason":null,"usage":{"input_tokens":12,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me "}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"check."}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"SF\"}"}}`,
+		`{"type":"content_block_stop","index":1}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":9}}`,
+		`{"type":"message_stop"}`,
+	}
+	var gotReq map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			var typed struct{ Type string }
+			json.Unmarshal([]byte(event), &typed)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typed.Type, event)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := newAnthropicProviderForTest("test-key", "claude-sonnet-4-6", 8192, srv.URL, srv.Client())
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	var streamed []string
+	ctx := WithStream(context.Background(), func(text string) { streamed = append(streamed, text) })
+	resp, err := p.Chat(ctx, ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: "weather in SF?"}}})
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if gotReq["stream"] != true {
+		t.Fatalf("expected a streaming request, got %#v", gotReq)
+	}
+	if len(streamed) != 2 || streamed[0] != "Let me " || streamed[1] != "Let me check." {
+		t.Fatalf("unexpected streamed text %q", streamed)
+	}
+	if resp.Content != "Let me check." || len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Arguments != `{"city":"SF"}` {
+		t.Fatalf("unexpected response %#v", resp)
+	}
+	if resp.Usage.InputTokens != 12 || resp.Usage.OutputTokens != 9 {
+		t.Fatalf("unexpected usage %#v", resp.Usage)
+	}
+}
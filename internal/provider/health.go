@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+const (
+	// cooldownAfterFailures consecutive errors put a profile into cooldown.
+	cooldownAfterFailures = 3
+	// healthCooldown is how long a failing profile is skipped.
+	healthCooldown = 5 * time.Minute
+	// healthSmoothing weights the latest request in the error rate and latency averages.
+	healthSmoothing = 0.2
+	// degradedErrorRate marks a profile degraded even before cooldown.
+	degradedErrorRate = 0.5
+)
+
+// ProfileHealth is the recorded health of one LLM profile. ErrorRate and
+// AvgLatency are moving averages weighted toward recent requests.
+type ProfileHealth struct {
+	Requests            int           `json:"requests"`
+	Errors              int           `json:"errors"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	ErrorRate           float64       `json:"error_rate"`
+	AvgLatency          time.Duration `json:"avg_latency"`
+	LastError           string        `json:"last_error,omitempty"`
+	LastErrorAt         time.Time     `json:"last_error_at,omitempty"`
+	CooldownUntil       time.Time     `json:"cooldown_until,omitempty"`
+}
+
+// CoolingDown reports whether the profile is being skipped at now.
+func (h ProfileHealth) CoolingDown(now time.Time) bool {
+	return now.Before(h.CooldownUntil)
+}
+
+// Degraded reports whether the profile is cooling down or failing often.
+func (h ProfileHealth) Degraded(now time.Time) bool {
+	return h.CoolingDown(now) || (h.Requests > 0 && h.ErrorRate >= degradedErrorRate)
+}
+
+// Health records per-profile request outcomes in a JSON file so `claw status`
+// can report on a running instance.
+type Health struct {
+	path string
+	now  func() time.Time
+}
+
+var healthMu sync.Mutex
+
+// NewHealth creates a health tracker backed by path.
+func NewHealth(path string) *Health {
+	return &Health{path: path, now: time.Now}
+}
+
+// Snapshot returns the recorded health of every profile.
+func (h *Health) Snapshot() (map[string]ProfileHealth, error) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	return h.load()
+}
+
+// Get returns the recorded health of one profile.
+func (h *Health) Get(name string) ProfileHealth {
+	profiles, err := h.Snapshot()
+	if err != nil {
+		logging.Logger().Warn("failed to read provider health", "err", err)
+		return ProfileHealth{}
+	}
+	return profiles[name]
+}
+
+// Record adds one request outcome for a profile and starts a cooldown once
+// it has failed cooldownAfterFailures times in a row.
+func (h *Health) Record(name string, latency time.Duration, reqErr error) (ProfileHealth, error) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	profiles, err := h.load()
+	if err != nil {
+		return ProfileHealth{}, err
+	}
+	now := h.now()
+	entry := profiles[name]
+	outcome := 0.0
+	if reqErr != nil {
+		outcome = 1
+	}
+	if entry.Requests == 0 {
+		entry.ErrorRate = outcome
+		entry.AvgLatency = latency
+	} else {
+		entry.ErrorRate += healthSmoothing * (outcome - entry.ErrorRate)
+		entry.AvgLatency += time.Duration(healthSmoothing * float64(latency-entry.AvgLatency))
+	}
+	entry.Requests++
+	if reqErr != nil {
+		entry.Errors++
+		entry.ConsecutiveFailures++
+		entry.LastError = reqErr.Error()
+		entry.LastErrorAt = now.UTC()
+		if entry.ConsecutiveFailures >= cooldownAfterFailures {
+			entry.CooldownUntil = now.Add(healthCooldown).UTC()
+		}
+	} else {
+		entry.ConsecutiveFailures = 0
+		entry.CooldownUntil = time.Time{}
+	}
+	profiles[name] = entry
+
+	raw, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return ProfileHealth{}, fmt.Errorf("marshal provider health: %w", err)
+	}
+	if err := store.WriteFile(h.path, append(raw, '\n')); err != nil {
+		return ProfileHealth{}, fmt.Errorf("write provider health: %w", err)
+	}
+	return entry, nil
+}
+
+func (h *Health) load() (map[string]ProfileHealth, error) {
+	profiles := map[string]ProfileHealth{}
+	raw, err := store.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read provider health: %w", err)
+	}
+	if strings.TrimSpace(raw) == "" {
+		return profiles, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &profiles); err != nil {
+		return nil, fmt.Errorf("decode provider health: %w", err)
+	}
+	return profiles, nil
+}
+
+// Profile is one named LLM profile served by a Router.
+type Profile struct {
+	Name     string
+	Provider Provider
+}
+
+// Router sends requests to a primary profile, tracking its health, and routes
+// to an optional fallback while the primary is failing or cooling down.
+type Router struct {
+	Primary  Profile
+	Fallback *Profile
+	Health   *Health
+	// Notify tells the user when requests switch to or back from the fallback.
+	Notify func(ctx context.Context, text string)
+
+	mu         sync.Mutex
+	onFallback bool
+}
+
+// Chat implements Provider.
+func (r *Router) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	now := r.Health.now()
+	primary := r.Health.Get(r.Primary.Name)
+	if primary.CoolingDown(now) {
+		if r.Fallback == nil {
+			return nil, fmt.Errorf("llm profile %s is cooling down after %d consecutive errors until %s (last error: %s)",
+				r.Primary.Name, primary.ConsecutiveFailures, primary.CooldownUntil.Local().Format("15:04"), primary.LastError)
+		}
+		return r.useFallback(ctx, req)
+	}
+
+	resp, err := r.call(ctx, r.Primary, req)
+	if err == nil {
+		r.switchBack(ctx)
+		return resp, nil
+	}
+	if r.Fallback == nil || ctx.Err() != nil {
+		return nil, err
+	}
+	logging.Logger().Warn("llm profile failed; trying fallback", "profile", r.Primary.Name, "fallback", r.Fallback.Name, "err", err)
+	return r.useFallback(ctx, req)
+}
+
+func (r *Router) call(ctx context.Context, profile Profile, req ChatRequest) (*ChatResponse, error) {
+	start := r.Health.now()
+	resp, err := profile.Provider.Chat(ctx, req)
+	if err != nil && ctx.Err() != nil {
+		// Cancelled turns say nothing about provider health.
+		return nil, err
+	}
+	if _, recordErr := r.Health.Record(profile.Name, r.Health.now().Sub(start), err); recordErr != nil {
+		logging.Logger().Warn("failed to record provider health", "profile", profile.Name, "err", recordErr)
+	}
+	return resp, err
+}
+
+func (r *Router) useFallback(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	r.mu.Lock()
+	switched := !r.onFallback
+	r.onFallback = true
+	r.mu.Unlock()
+	if switched && r.Notify != nil {
+		r.Notify(ctx, fmt.Sprintf("⚠️ LLM profile %s is failing; using %s until it recovers.", r.Primary.Name, r.Fallback.Name))
+	}
+	return r.call(ctx, *r.Fallback, req)
+}
+
+func (r *Router) switchBack(ctx context.Context) {
+	r.mu.Lock()
+	switched := r.onFallback
+	r.onFallback = false
+	r.mu.Unlock()
+	if switched && r.Notify != nil {
+		r.Notify(ctx, fmt.Sprintf("LLM profile %s has recovered.", r.Primary.Name))
+	}
+}
+
+// FormatHealth renders profile health for `claw status`. Configured profiles
+// without recorded requests are listed too.
+func FormatHealth(names []string, profiles map[string]ProfileHealth, now time.Time) string {
+	sorted := append([]string{}, names...)
+	for name := range profiles {
+		if !slices.Contains(sorted, name) {
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	b.WriteString("LLM profiles:")
+	for _, name := range sorted {
+		entry, ok := profiles[name]
+		b.WriteString("\n- " + name + ": ")
+		switch {
+		case !ok || entry.Requests == 0:
+			b.WriteString("no requests yet")
+			continue
+		case entry.CoolingDown(now):
+			fmt.Fprintf(&b, "cooling down until %s", entry.CooldownUntil.Local().Format("15:04"))
+		case entry.Degraded(now):
+			b.WriteString("degraded")
+		default:
+			b.WriteString("ok")
+		}
+		fmt.Fprintf(&b, " — error rate %.0f%%, avg latency %s, %d requests, %d errors",
+			entry.ErrorRate*100, entry.AvgLatency.Round(100*time.Millisecond), entry.Requests, entry.Errors)
+		if entry.LastError != "" && entry.Degraded(now) {
+			fmt.Fprintf(&b, "\n  last error (%s): %s", entry.LastErrorAt.Local().Format("2006-01-02 15:04"), entry.LastError)
+		}
+	}
+	return b.String()
+}
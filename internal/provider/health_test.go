@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type scriptedProvider struct {
+	errs  []error
+	calls int
+}
+
+func (p *scriptedProvider) Chat(_ context.Context, _ ChatRequest) (*ChatResponse, error) {
+	p.calls++
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &ChatResponse{Content: "ok"}, nil
+}
+
+func newTestHealth(t *testing.T, now *time.Time) *Health {
+	t.Helper()
+	health := NewHealth(filepath.Join(t.TempDir(), "provider_health.json"))
+	health.now = func() time.Time { return *now }
+	return health
+}
+
+func TestHealthRecordStartsCooldownAfterConsecutiveFailures(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	health := newTestHealth(t, &now)
+	boom := errors.New("503 overloaded")
+
+	for i := 0; i < cooldownAfterFailures-1; i++ {
+		entry, err := health.Record("default", time.Second, boom)
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+		if entry.CoolingDown(now) {
+			t.Fatalf("expected no cooldown after %d failures", i+1)
+		}
+	}
+	entry, err := health.Record("default", time.Second, boom)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if !entry.CoolingDown(now) || entry.CoolingDown(now.Add(healthCooldown)) {
+		t.Fatalf("expected a %s cooldown, got until %s", healthCooldown, entry.CooldownUntil)
+	}
+
+	entry, err = health.Record("default", time.Second, nil)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if entry.CoolingDown(now) || entry.ConsecutiveFailures != 0 || entry.Errors != 3 || entry.Requests != 4 {
+		t.Fatalf("expected success to clear cooldown, got %#v", entry)
+	}
+	if got := health.Get("default"); got.Requests != 4 {
+		t.Fatalf("expected health to be persisted, got %#v", got)
+	}
+}
+
+func TestRouterFallsBackAndNotifies(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	health := newTestHealth(t, &now)
+	boom := errors.New("503 overloaded")
+	primary := &scriptedProvider{errs: []error{boom, boom, boom}}
+	backup := &scriptedProvider{}
+	var notices []string
+	router := &Router{
+		Primary:  Profile{Name: "default", Provider: primary},
+		Fallback: &Profile{Name: "backup", Provider: backup},
+		Health:   health,
+		Notify:   func(_ context.Context, text string) { notices = append(notices, text) },
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := router.Chat(context.Background(), ChatRequest{}); err != nil {
+			t.Fatalf("chat %d: %v", i, err)
+		}
+	}
+	if primary.calls != 3 {
+		t.Fatalf("expected primary to be skipped during cooldown, got %d calls", primary.calls)
+	}
+	if backup.calls != 4 {
+		t.Fatalf("expected fallback to serve every request, got %d calls", backup.calls)
+	}
+	if len(notices) != 1 || !strings.Contains(notices[0], "using backup") {
+		t.Fatalf("expected one switch notice, got %#v", notices)
+	}
+
+	now = now.Add(healthCooldown)
+	if _, err := router.Chat(context.Background(), ChatRequest{}); err != nil {
+		t.Fatalf("chat after cooldown: %v", err)
+	}
+	if primary.calls != 4 || len(notices) != 2 || !strings.Contains(notices[1], "recovered") {
+		t.Fatalf("expected primary retry and recovery notice, got %d calls %#v", primary.calls, notices)
+	}
+}
+
+func TestRouterWithoutFallbackFailsFastDuringCooldown(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	health := newTestHealth(t, &now)
+	boom := errors.New("503 overloaded")
+	primary := &scriptedProvider{errs: []error{boom, boom, boom}}
+	router := &Router{Primary: Profile{Name: "default", Provider: primary}, Health: health}
+
+	for i := 0; i < cooldownAfterFailures; i++ {
+		if _, err := router.Chat(context.Background(), ChatRequest{}); !errors.Is(err, boom) {
+			t.Fatalf("expected provider error, got %v", err)
+		}
+	}
+	_, err := router.Chat(context.Background(), ChatRequest{})
+	if err == nil || !strings.Contains(err.Error(), "cooling down") {
+		t.Fatalf("expected cooldown error, got %v", err)
+	}
+	if primary.calls != cooldownAfterFailures {
+		t.Fatalf("expected no call during cooldown, got %d", primary.calls)
+	}
+}
+
+func TestFormatHealth(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	out := FormatHealth([]string{"default", "backup"}, map[string]ProfileHealth{
+		"default": {Requests: 10, Errors: 4, ErrorRate: 0.6, AvgLatency: 2300 * time.Millisecond, LastError: "503 overloaded", LastErrorAt: now},
+	}, now)
+	if !strings.Contains(out, "- backup: no requests yet") {
+		t.Fatalf("expected unused profile, got %q", out)
+	}
+	if !strings.Contains(out, "- default: degraded — error rate 60%, avg latency 2.3s, 10 requests, 4 errors") || !strings.Contains(out, "503 overloaded") {
+		t.Fatalf("expected degraded profile details, got %q", out)
+	}
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"context"
+	"time"
+)
+
+// Mock is an offline provider that answers every request with an echo of
+// the latest user message after Latency. It lets claw soak load the server
+// without an API key or spending money.
+type Mock struct {
+	Latency time.Duration
+}
+
+// Chat waits Latency, then echoes the latest user message. Token counts are
+// rough estimates of four characters per token.
+func (m Mock) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if m.Latency > 0 {
+		timer := time.NewTimer(m.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	latest := ""
+	inputChars := len(req.SystemPrompt)
+	for _, message := range req.Messages {
+		inputChars += len(message.Content)
+		if message.Role == RoleUser {
+			latest = message.Content
+		}
+	}
+	content := "echo: " + latest
+	usage := TokenUsage{InputTokens: inputChars / 4, OutputTokens: len(content) / 4}
+	usage.TotalTokens = usage.InputTokens + usage.OutputTokens
+	return &ChatResponse{Content: content, Usage: usage}, nil
+}
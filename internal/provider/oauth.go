@@ -0,0 +1,296 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/secrets"
+)
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// defaultDevicePollInterval is used when the server does not say how
+	// often to poll (RFC 8628 section 3.2).
+	defaultDevicePollInterval = 5 * time.Second
+	// oauthRefreshMargin refreshes a token this long before it expires, so a
+	// request never starts with a token about to lapse.
+	oauthRefreshMargin    = time.Minute
+	maxOAuthResponseBytes = 1 << 20
+)
+
+// devicePollUnit is the unit of the device code's interval and expires_in;
+// tests shorten it.
+var devicePollUnit = time.Second
+
+// ErrOAuthLoginRequired means the profile has no usable token; run
+// `claw login` to sign in.
+var ErrOAuthLoginRequired = errors.New("not signed in; run claw login")
+
+// DeviceCode is a pending device sign-in: the user opens VerificationURI and
+// enters UserCode while the client polls with the device code.
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// OAuthToken is a stored access token and the refresh token that renews it.
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// expiresSoon reports whether the token should be refreshed before use.
+func (t OAuthToken) expiresSoon(now time.Time) bool {
+	return !t.Expiry.IsZero() && !now.Add(oauthRefreshMargin).Before(t.Expiry)
+}
+
+// tokenResponse is a token endpoint reply, successful or not (RFC 6749
+// sections 5.1 and 5.2).
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// StartDeviceLogin asks the authorization server for a device code.
+func StartDeviceLogin(ctx context.Context, client *http.Client, cfg config.OAuthConfig) (DeviceCode, error) {
+	form := url.Values{"client_id": {cfg.ClientID}}
+	if scope := strings.TrimSpace(cfg.Scope); scope != "" {
+		form.Set("scope", scope)
+	}
+	status, raw, err := postOAuthForm(ctx, client, cfg.DeviceAuthorizationURL, form)
+	if err != nil {
+		return DeviceCode{}, fmt.Errorf("device authorization: %w", err)
+	}
+	if status != http.StatusOK {
+		return DeviceCode{}, fmt.Errorf("device authorization: %s", oauthErrorText(status, raw))
+	}
+	var code DeviceCode
+	if err := json.Unmarshal(raw, &code); err != nil {
+		return DeviceCode{}, fmt.Errorf("decode device authorization: %w", err)
+	}
+	if code.DeviceCode == "" || code.UserCode == "" || code.VerificationURI == "" {
+		return DeviceCode{}, errors.New("device authorization: response is missing the device code, user code, or verification URI")
+	}
+	return code, nil
+}
+
+// WaitForDeviceToken polls the token endpoint until the user approves or
+// denies the sign-in, or the device code expires, and saves the token for the
+// provider.
+func WaitForDeviceToken(ctx context.Context, client *http.Client, cfg config.OAuthConfig, code DeviceCode) (OAuthToken, error) {
+	interval := time.Duration(code.Interval) * devicePollUnit
+	if interval <= 0 {
+		interval = defaultDevicePollInterval
+	}
+	if code.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(code.ExpiresIn)*devicePollUnit)
+		defer cancel()
+	}
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {code.DeviceCode},
+		"client_id":   {cfg.ClientID},
+	}
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return OAuthToken{}, errors.New("the sign-in code expired; run claw login again")
+			}
+			return OAuthToken{}, ctx.Err()
+		case <-timer.C:
+		}
+
+		resp, err := requestToken(ctx, client, cfg.TokenURL, form)
+		if err != nil {
+			return OAuthToken{}, err
+		}
+		switch resp.Error {
+		case "":
+			token := resp.token(time.Now(), "")
+			if err := saveOAuthToken(cfg, token); err != nil {
+				return OAuthToken{}, err
+			}
+			return token, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * devicePollUnit
+		case "access_denied":
+			return OAuthToken{}, errors.New("sign-in was denied")
+		case "expired_token":
+			return OAuthToken{}, errors.New("the sign-in code expired; run claw login again")
+		default:
+			return OAuthToken{}, fmt.Errorf("token request: %s", resp.describe())
+		}
+	}
+}
+
+// oauthTokenSource hands out the profile's access token, refreshing it with
+// the refresh token when it is about to expire and saving the result.
+type oauthTokenSource struct {
+	cfg    config.OAuthConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	token *OAuthToken
+}
+
+func newOAuthTokenSource(cfg config.OAuthConfig, client *http.Client) *oauthTokenSource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &oauthTokenSource{cfg: cfg, client: client, now: time.Now}
+}
+
+// AccessToken returns a current access token.
+func (s *oauthTokenSource) AccessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == nil {
+		// Read the saved token lazily, so a claw login while the server runs
+		// takes effect on the next request.
+		token, ok, err := loadOAuthToken(s.cfg)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", ErrOAuthLoginRequired
+		}
+		s.token = &token
+	}
+	if !s.token.expiresSoon(s.now()) {
+		return s.token.AccessToken, nil
+	}
+	if s.token.RefreshToken == "" {
+		s.token = nil
+		return "", fmt.Errorf("access token expired: %w", ErrOAuthLoginRequired)
+	}
+
+	resp, err := requestToken(ctx, s.client, s.cfg.TokenURL, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {s.token.RefreshToken},
+		"client_id":     {s.cfg.ClientID},
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		if resp.Error == "invalid_grant" {
+			s.token = nil
+			return "", fmt.Errorf("refresh token was rejected: %w", ErrOAuthLoginRequired)
+		}
+		return "", fmt.Errorf("refresh token: %s", resp.describe())
+	}
+	token := resp.token(s.now(), s.token.RefreshToken)
+	if err := saveOAuthToken(s.cfg, token); err != nil {
+		return "", err
+	}
+	s.token = &token
+	logging.Logger().Info("oauth token refreshed", "expiry", token.Expiry)
+	return token.AccessToken, nil
+}
+
+// token converts a successful response. Servers may leave out the refresh
+// token on refresh, meaning the old one stays valid.
+func (r tokenResponse) token(now time.Time, refreshToken string) OAuthToken {
+	token := OAuthToken{AccessToken: r.AccessToken, RefreshToken: r.RefreshToken}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	if r.ExpiresIn > 0 {
+		token.Expiry = now.Add(time.Duration(r.ExpiresIn) * time.Second).UTC()
+	}
+	return token
+}
+
+func (r tokenResponse) describe() string {
+	if r.ErrorDescription != "" {
+		return r.Error + ": " + r.ErrorDescription
+	}
+	return r.Error
+}
+
+// requestToken posts form to the token endpoint. OAuth errors come back in
+// the response rather than as err.
+func requestToken(ctx context.Context, client *http.Client, tokenURL string, form url.Values) (tokenResponse, error) {
+	status, raw, err := postOAuthForm(ctx, client, tokenURL, form)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("token request: %w", err)
+	}
+	var resp tokenResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return tokenResponse{}, fmt.Errorf("token request: %s", oauthErrorText(status, raw))
+	}
+	if resp.Error == "" && (status != http.StatusOK || resp.AccessToken == "") {
+		return tokenResponse{}, fmt.Errorf("token request: %s", oauthErrorText(status, raw))
+	}
+	return resp, nil
+}
+
+func postOAuthForm(ctx context.Context, client *http.Client, endpoint string, form url.Values) (int, []byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxOAuthResponseBytes))
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, raw, nil
+}
+
+func oauthErrorText(status int, raw []byte) string {
+	body := strings.TrimSpace(string(raw))
+	if len(body) > 200 {
+		body = body[:200] + "..."
+	}
+	if body == "" {
+		return http.StatusText(status)
+	}
+	return fmt.Sprintf("%s: %s", http.StatusText(status), body)
+}
+
+func loadOAuthToken(cfg config.OAuthConfig) (OAuthToken, bool, error) {
+	var token OAuthToken
+	ok, err := secrets.New(cfg.SecretsDir).Load(cfg.SecretName, &token)
+	if err != nil || !ok || token.AccessToken == "" {
+		return OAuthToken{}, false, err
+	}
+	return token, true, nil
+}
+
+func saveOAuthToken(cfg config.OAuthConfig, token OAuthToken) error {
+	return secrets.New(cfg.SecretsDir).Save(cfg.SecretName, token)
+}
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+func testOAuthConfig(t *testing.T, serverURL string) config.OAuthConfig {
+	t.Helper()
+	return config.OAuthConfig{
+		ClientID:               "neoclaw",
+		DeviceAuthorizationURL: serverURL + "/device",
+		TokenURL:               serverURL + "/token",
+		Scope:                  "user:inference",
+		SecretsDir:             t.TempDir(),
+		SecretName:             "oauth-default",
+	}
+}
+
+func TestDeviceLoginWaitsForApprovalAndSavesToken(t *testing.T) {
+	orig := devicePollUnit
+	devicePollUnit = time.Millisecond
+	defer func() { devicePollUnit = orig }()
+
+	var (
+		mu    sync.Mutex
+		polls int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/device":
+			if r.PostForm.Get("client_id") != "neoclaw" || r.PostForm.Get("scope") != "user:inference" {
+				t.Errorf("unexpected device request %v", r.PostForm)
+			}
+			w.Write([]byte(`{"device_code":"dev-1","user_code":"ABCD-EFGH","verification_uri":"https://example.com/activate","expires_in":1000,"interval":1}`))
+		case "/token":
+			if r.PostForm.Get("grant_type") != deviceCodeGrantType || r.PostForm.Get("device_code") != "dev-1" {
+				t.Errorf("unexpected token request %v", r.PostForm)
+			}
+			mu.Lock()
+			polls++
+			n := polls
+			mu.Unlock()
+			if n < 3 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"authorization_pending"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token":"access-1","refresh_token":"refresh-1","expires_in":3600,"token_type":"Bearer"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	cfg := testOAuthConfig(t, srv.URL)
+
+	code, err := StartDeviceLogin(context.Background(), srv.Client(), cfg)
+	if err != nil {
+		t.Fatalf("start device login: %v", err)
+	}
+	if code.UserCode != "ABCD-EFGH" || code.VerificationURI != "https://example.com/activate" {
+		t.Fatalf("unexpected device code %#v", code)
+	}
+	token, err := WaitForDeviceToken(context.Background(), srv.Client(), cfg, code)
+	if err != nil {
+		t.Fatalf("wait for token: %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.Expiry.IsZero() {
+		t.Fatalf("unexpected token %#v", token)
+	}
+
+	saved, ok, err := loadOAuthToken(cfg)
+	if err != nil || !ok || saved.AccessToken != "access-1" {
+		t.Fatalf("expected the token saved, got %#v ok=%v err=%v", saved, ok, err)
+	}
+}
+
+func TestDeviceLoginReportsDenial(t *testing.T) {
+	orig := devicePollUnit
+	devicePollUnit = time.Millisecond
+	defer func() { devicePollUnit = orig }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"access_denied"}`))
+	}))
+	defer srv.Close()
+	cfg := testOAuthConfig(t, srv.URL)
+
+	_, err := WaitForDeviceToken(context.Background(), srv.Client(), cfg, DeviceCode{DeviceCode: "dev-1", Interval: 1})
+	if err == nil || err.Error() != "sign-in was denied" {
+		t.Fatalf("expected denial, got %v", err)
+	}
+	if _, ok, _ := loadOAuthToken(cfg); ok {
+		t.Fatal("expected no token saved")
+	}
+}
+
+func TestOAuthTokenSourceRefreshesExpiringToken(t *testing.T) {
+	var refreshes int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "refresh-1" {
+			t.Errorf("unexpected refresh request %v", r.PostForm)
+		}
+		refreshes++
+		w.Header().Set("Content-Type", "application/json")
+		// No refresh_token in the reply: the old one stays valid.
+		w.Write([]byte(`{"access_token":"access-2","expires_in":3600}`))
+	}))
+	defer srv.Close()
+	cfg := testOAuthConfig(t, srv.URL)
+
+	source := newOAuthTokenSource(cfg, srv.Client())
+	if _, err := source.AccessToken(context.Background()); !errors.Is(err, ErrOAuthLoginRequired) {
+		t.Fatalf("expected login required before claw login, got %v", err)
+	}
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	source.now = func() time.Time { return now }
+	if err := saveOAuthToken(cfg, OAuthToken{AccessToken: "access-1", RefreshToken: "refresh-1", Expiry: now.Add(30 * time.Second)}); err != nil {
+		t.Fatalf("save token: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		token, err := source.AccessToken(context.Background())
+		if err != nil {
+			t.Fatalf("access token: %v", err)
+		}
+		if token != "access-2" {
+			t.Fatalf("expected the refreshed token, got %q", token)
+		}
+	}
+	if refreshes != 1 {
+		t.Fatalf("expected one refresh, got %d", refreshes)
+	}
+	saved, ok, err := loadOAuthToken(cfg)
+	if err != nil || !ok || saved.AccessToken != "access-2" || saved.RefreshToken != "refresh-1" || !saved.Expiry.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected the refreshed token saved, got %#v ok=%v err=%v", saved, ok, err)
+	}
+}
+
+func TestOAuthTokenSourceAsksForLoginWhenRefreshIsRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	defer srv.Close()
+	cfg := testOAuthConfig(t, srv.URL)
+	if err := saveOAuthToken(cfg, OAuthToken{AccessToken: "old", RefreshToken: "revoked", Expiry: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatalf("save token: %v", err)
+	}
+
+	_, err := newOAuthTokenSource(cfg, srv.Client()).AccessToken(context.Background())
+	if !errors.Is(err, ErrOAuthLoginRequired) {
+		t.Fatalf("expected login required, got %v", err)
+	}
+}
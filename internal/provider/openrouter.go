@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -88,6 +89,12 @@ func (p *openRouterProvider) Chat(ctx context.Context, req ChatRequest) (*ChatRe
 		}
 	}
 
+	onText := StreamFrom(ctx)
+	if onText != nil {
+		payload.Stream = true
+		payload.StreamOptions = &openRouterStreamOptions{IncludeUsage: true}
+	}
+
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("marshal openrouter request: %w", err)
@@ -106,17 +113,28 @@ func (p *openRouterProvider) Chat(ctx context.Context, req ChatRequest) (*ChatRe
 	}
 	defer httpResp.Body.Close()
 
-	respBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read openrouter response: %w", err)
-	}
 	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		respBody, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read openrouter response: %w", err)
+		}
 		return nil, fmt.Errorf("openrouter API returned %s: %s", httpResp.Status, strings.TrimSpace(string(respBody)))
 	}
 
 	var parsed openRouterResponse
-	if err := json.Unmarshal(respBody, &parsed); err != nil {
-		return nil, fmt.Errorf("decode openrouter response: %w", err)
+	if onText != nil {
+		parsed, err = readOpenRouterStream(httpResp.Body, onText)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		respBody, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read openrouter response: %w", err)
+		}
+		if err := json.Unmarshal(respBody, &parsed); err != nil {
+			return nil, fmt.Errorf("decode openrouter response: %w", err)
+		}
 	}
 	if len(parsed.Choices) == 0 {
 		return nil, fmt.Errorf("openrouter response has no choices")
@@ -145,10 +163,16 @@ func (p *openRouterProvider) Chat(ctx context.Context, req ChatRequest) (*ChatRe
 }
 
 type openRouterRequest struct {
-	Model     string              `json:"model"`
-	Messages  []openRouterMessage `json:"messages"`
-	Tools     []openRouterTool    `json:"tools,omitempty"`
-	MaxTokens int                 `json:"max_tokens,omitempty"`
+	Model         string                   `json:"model"`
+	Messages      []openRouterMessage      `json:"messages"`
+	Tools         []openRouterTool         `json:"tools,omitempty"`
+	MaxTokens     int                      `json:"max_tokens,omitempty"`
+	Stream        bool                     `json:"stream,omitempty"`
+	StreamOptions *openRouterStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openRouterStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
 }
 
 type openRouterMessage struct {
@@ -177,15 +201,94 @@ type openRouterToolCall struct {
 }
 
 type openRouterResponse struct {
+	Choices []openRouterChoice `json:"choices"`
+	Usage   openRouterUsage    `json:"usage"`
+}
+
+type openRouterChoice struct {
+	Message openRouterMessage `json:"message"`
+}
+
+type openRouterUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+	Cost             any `json:"cost"`
+}
+
+// openRouterChunk is one server-sent event of a streaming response. Tool
+// call fields arrive in pieces keyed by Index.
+type openRouterChunk struct {
 	Choices []struct {
-		Message openRouterMessage `json:"message"`
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    int                `json:"index"`
+				ID       string             `json:"id"`
+				Function openRouterFunction `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
 	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-		Cost             any `json:"cost"`
-	} `json:"usage"`
+	Usage *openRouterUsage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// readOpenRouterStream assembles a streaming response from body, calling
+// onText with the content so far as it grows.
+func readOpenRouterStream(body io.Reader, onText func(string)) (openRouterResponse, error) {
+	var content strings.Builder
+	var calls []openRouterToolCall
+	var usage openRouterUsage
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			// Blank lines end events; lines starting with ':' are keep-alives.
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var chunk openRouterChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return openRouterResponse{}, fmt.Errorf("decode openrouter stream: %w", err)
+		}
+		if chunk.Error != nil {
+			return openRouterResponse{}, fmt.Errorf("openrouter stream failed: %s", chunk.Error.Message)
+		}
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		delta := chunk.Choices[0].Delta
+		for _, part := range delta.ToolCalls {
+			for len(calls) <= part.Index {
+				calls = append(calls, openRouterToolCall{Type: "function"})
+			}
+			call := &calls[part.Index]
+			call.ID += part.ID
+			call.Function.Name += part.Function.Name
+			call.Function.Arguments += part.Function.Arguments
+		}
+		if delta.Content != "" {
+			content.WriteString(delta.Content)
+			onText(content.String())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return openRouterResponse{}, fmt.Errorf("read openrouter stream: %w", err)
+	}
+	return openRouterResponse{
+		Choices: []openRouterChoice{{Message: openRouterMessage{Role: "assistant", Content: content.String(), ToolCalls: calls}}},
+		Usage:   usage,
+	}, nil
 }
 
 func parseOptionalCost(raw any) *float64 {
@@ -217,6 +320,7 @@ func toOpenRouterMessages(messages []ChatMessage) []openRouterMessage {
 		}
 		if msg.Role == RoleTool {
 			m.ToolCallID = msg.ToolCallID
+			m.Content = msg.ToolResultContent()
 		}
 		if len(msg.ToolCalls) > 0 {
 			m.ToolCalls = make([]openRouterToolCall, 0, len(msg.ToolCalls))
@@ -127,3 +127,55 @@ func TestOpenRouterProviderChat_ParsesUsageCost(t *testing.T) {
 		t.Fatalf("expected positive usage cost, got %v", *resp.Usage.CostUSD)
 	}
 }
+
+func TestToOpenRouterMessagesTagsToolResultTrust(t *testing.T) {
+	messages := toOpenRouterMessages([]ChatMessage{
+		{Role: RoleUser, Content: "fetch it"},
+		{Role: RoleTool, ToolCallID: "call_1", Content: "<html>", Trust: "internet"},
+	})
+	if messages[0].Content != "fetch it" {
+		t.Fatalf("expected user messages untagged, got %q", messages[0].Content)
+	}
+	if messages[1].Content != "[trust: internet]\n<html>" {
+		t.Fatalf("expected the trust tag on the tool result, got %q", messages[1].Content)
+	}
+}
+
+func TestOpenRouterProviderChat_Streams(t *testing.T) {
+	var gotReq map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": OPENROUTER PROCESSING\n\n" +
+			`data: {"choices":[{"delta":{"role":"assistant","content":"Two "}}]}` + "\n\n" +
+			`data: {"choices":[{"delta":{"content":"plus two"}}]}` + "\n\n" +
+			`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"calculator","arguments":"{\"expr\":"}}]}}]}` + "\n\n" +
+			`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"2+2\"}"}}]}}]}` + "\n\n" +
+			`data: {"choices":[],"usage":{"prompt_tokens":11,"completion_tokens":7,"total_tokens":18,"cost":0.001}}` + "\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	p, err := newOpenRouterProviderForTest("test-key", "deepseek/deepseek-chat", 8192, srv.URL, srv.Client())
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	var streamed []string
+	ctx := WithStream(context.Background(), func(text string) { streamed = append(streamed, text) })
+	resp, err := p.Chat(ctx, ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: "2+2?"}}})
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if gotReq["stream"] != true || gotReq["stream_options"] == nil {
+		t.Fatalf("expected a streaming request with usage, got %#v", gotReq)
+	}
+	if len(streamed) != 2 || streamed[1] != "Two plus two" {
+		t.Fatalf("unexpected streamed text %q", streamed)
+	}
+	if resp.Content != "Two plus two" || len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "call_1" || resp.ToolCalls[0].Arguments != `{"expr":"2+2"}` {
+		t.Fatalf("unexpected response %#v", resp)
+	}
+	if resp.Usage.TotalTokens != 18 || resp.Usage.CostUSD == nil {
+		t.Fatalf("unexpected usage %#v", resp.Usage)
+	}
+}
@@ -29,6 +29,18 @@ type ChatMessage struct {
 	Content    string
 	ToolCallID string
 	ToolCalls  []ToolCall
+	// Trust is the config.Trust* level of a tool result's content. Providers
+	// send it as a tag at the start of the result; see ToolResultContent.
+	Trust string
+}
+
+// ToolResultContent returns the content of a tool result as sent to the
+// model, tagged with its trust level when it has one.
+func (m ChatMessage) ToolResultContent() string {
+	if m.Trust == "" {
+		return m.Content
+	}
+	return "[trust: " + m.Trust + "]\n" + m.Content
 }
 
 // ToolDefinition describes a callable tool exposed to the model.
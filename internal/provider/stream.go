@@ -0,0 +1,19 @@
+package provider
+
+import "context"
+
+type streamKey struct{}
+
+// WithStream asks providers that can stream to call onText with the text of
+// the response so far each time more of it arrives. Chat still returns the
+// whole response. A provider that falls back or retries starts the text
+// over, so onText should show only the latest value.
+func WithStream(ctx context.Context, onText func(text string)) context.Context {
+	return context.WithValue(ctx, streamKey{}, onText)
+}
+
+// StreamFrom returns the text callback set by WithStream, or nil.
+func StreamFrom(ctx context.Context) func(string) {
+	onText, _ := ctx.Value(streamKey{}).(func(string))
+	return onText
+}
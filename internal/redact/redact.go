@@ -0,0 +1,108 @@
+// Package redact masks personal data such as email addresses, card numbers, and phone numbers before text is written to disk, and credentials before replies are sent to a chat.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Built-in pattern kinds.
+const (
+	KindEmail = "email"
+	KindCard  = "card"
+	KindPhone = "phone"
+)
+
+// Kinds lists the built-in pattern kinds in the order they are applied.
+var Kinds = []string{KindEmail, KindCard, KindPhone}
+
+var builtinPatterns = map[string]*regexp.Regexp{
+	KindEmail: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	// Card candidates are 13-19 digits optionally grouped by spaces or
+	// dashes; a Luhn check filters out other long numbers.
+	KindCard: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+	// Phone numbers need an explicit +country prefix or the common
+	// 3-3-4 grouping, so dates, amounts, and ids are left alone.
+	KindPhone: regexp.MustCompile(`(?:\+\d{1,3}[ .-]?(?:\(\d{1,4}\)|\d{1,4})(?:[ .-]?\d{2,4}){2,4}\b|(?:\(\d{3}\) ?|\b\d{3}[ .-])\d{3}[ .-]\d{4}\b)`),
+}
+
+type rule struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+// Redactor replaces matches of its patterns with a placeholder naming the
+// kind of data removed. A nil Redactor leaves text unchanged.
+type Redactor struct {
+	rules []rule
+}
+
+// New builds a Redactor from built-in kinds and extra regular expressions.
+// It returns nil when there is nothing to redact.
+func New(kinds, patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, kind := range kinds {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		pattern, ok := builtinPatterns[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction kind %q (expected one of %s)", kind, strings.Join(Kinds, ", "))
+		}
+		r.rules = append(r.rules, rule{kind: kind, pattern: pattern})
+	}
+	for _, raw := range patterns {
+		pattern, err := regexp.Compile(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", raw, err)
+		}
+		r.rules = append(r.rules, rule{pattern: pattern})
+	}
+	if len(r.rules) == 0 {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// Redact returns text with every match replaced by a placeholder.
+func (r *Redactor) Redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	for _, rule := range r.rules {
+		text = rule.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if rule.kind == KindCard && !luhnValid(match) {
+				return match
+			}
+			return placeholder(rule.kind)
+		})
+	}
+	return text
+}
+
+func placeholder(kind string) string {
+	if kind == "" {
+		return "[redacted]"
+	}
+	return "[redacted " + kind + "]"
+}
+
+func luhnValid(number string) bool {
+	sum := 0
+	digits := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
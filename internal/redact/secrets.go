@@ -0,0 +1,53 @@
+package redact
+
+import "regexp"
+
+// Outbound secret filter modes.
+const (
+	SecretsOff    = "off"
+	SecretsRedact = "redact"
+	SecretsBlock  = "block"
+)
+
+type secretPattern struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// secretPatterns match well-known credential formats. They are deliberately
+// specific so ordinary text is never withheld.
+var secretPatterns = []secretPattern{
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY(?: BLOCK)?-----(?s:.*?)(?:-----END [A-Z0-9 ]*PRIVATE KEY(?: BLOCK)?-----|\z)`)},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA|AIDA|AROA|AGPA)[0-9A-Z]{16}\b`)},
+	{"AWS secret key", regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"API key", regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}`)},
+	{"Telegram bot token", regexp.MustCompile(`\b\d{8,10}:AA[A-Za-z0-9_-]{33}\b`)},
+}
+
+// FilterSecrets applies an outbound secret filter mode to text. In redact
+// mode each credential is replaced with a placeholder; in block mode the
+// whole text is replaced with a notice. It also returns the names of the
+// credential kinds found, in pattern order.
+func FilterSecrets(text, mode string) (string, []string) {
+	if mode != SecretsRedact && mode != SecretsBlock {
+		return text, nil
+	}
+	var found []string
+	filtered := text
+	for _, secret := range secretPatterns {
+		if !secret.pattern.MatchString(filtered) {
+			continue
+		}
+		found = append(found, secret.name)
+		filtered = secret.pattern.ReplaceAllLiteralString(filtered, "[redacted "+secret.name+"]")
+	}
+	if len(found) == 0 {
+		return text, nil
+	}
+	if mode == SecretsBlock {
+		return "⚠️ Message withheld: it contained what looks like a credential. The full reply is not shown here.", found
+	}
+	return filtered, found
+}
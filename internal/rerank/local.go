@@ -0,0 +1,97 @@
+package rerank
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters: k1 limits how much repeating a word helps, b how much
+// long documents are penalized.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+	// stemRunes is how many leading letters two words must share to count
+	// as the same word, so "remind" matches "reminder".
+	stemRunes = 5
+)
+
+// Local scores candidates with BM25 over their words, weighting query words
+// by how rare they are among the candidates. It is not a cross-encoder: it
+// needs no model and adds no latency, and it mostly helps by putting exact
+// names and terms above loosely similar matches.
+type Local struct{}
+
+// Rerank scores documents by BM25 against query.
+func (Local) Rerank(_ context.Context, query string, documents []string) ([]float64, error) {
+	queryTerms := dedupe(terms(query))
+	docs := make([][]string, len(documents))
+	var totalLength int
+	for i, document := range documents {
+		docs[i] = terms(document)
+		totalLength += len(docs[i])
+	}
+	scores := make([]float64, len(documents))
+	if len(documents) == 0 || totalLength == 0 {
+		return scores, nil
+	}
+	averageLength := float64(totalLength) / float64(len(documents))
+
+	for _, term := range queryTerms {
+		counts := make([]int, len(docs))
+		containing := 0
+		for i, doc := range docs {
+			for _, word := range doc {
+				if sameWord(term, word) {
+					counts[i]++
+				}
+			}
+			if counts[i] > 0 {
+				containing++
+			}
+		}
+		if containing == 0 {
+			continue
+		}
+		idf := math.Log(1 + (float64(len(docs)-containing)+0.5)/(float64(containing)+0.5))
+		for i, count := range counts {
+			if count == 0 {
+				continue
+			}
+			tf := float64(count)
+			norm := 1 - bm25B + bm25B*float64(len(docs[i]))/averageLength
+			scores[i] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+	return scores, nil
+}
+
+func terms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func dedupe(words []string) []string {
+	seen := make(map[string]bool, len(words))
+	out := words[:0]
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			out = append(out, word)
+		}
+	}
+	return out
+}
+
+func sameWord(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < stemRunes || len(rb) < stemRunes {
+		return false
+	}
+	return string(ra[:stemRunes]) == string(rb[:stemRunes])
+}
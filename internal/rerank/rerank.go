@@ -0,0 +1,138 @@
+// Package rerank reorders search matches by how well each answers the query.
+// It runs after a cheap first pass, such as embedding similarity, on only
+// the closest few candidates.
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+// Reranker scores documents against a query.
+type Reranker interface {
+	// Rerank returns one relevance score per document, in order. Higher is
+	// more relevant; scores are only comparable within one call.
+	Rerank(ctx context.Context, query string, documents []string) ([]float64, error)
+}
+
+const (
+	voyageEndpoint = "https://api.voyageai.com/v1/rerank"
+	voyageModel    = "rerank-2.5-lite"
+	cohereEndpoint = "https://api.cohere.com/v2/rerank"
+	cohereModel    = "rerank-v3.5"
+	// maxResponseBytes caps one rerank response.
+	maxResponseBytes = 1 << 20
+)
+
+// NewFromConfig builds the reranker selected in [rerank], or returns nil
+// when reranking is off.
+func NewFromConfig(cfg config.RerankConfig, client *http.Client) (Reranker, error) {
+	provider := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	switch provider {
+	case "":
+		return nil, nil
+	case "local":
+		return Local{}, nil
+	case "voyage", "cohere":
+	default:
+		return nil, fmt.Errorf("unsupported rerank.provider %s", cfg.Provider)
+	}
+	if client == nil {
+		return nil, errors.New("http client is required")
+	}
+	api := &apiReranker{client: client, endpoint: cfg.Endpoint, apiKey: cfg.APIKey, model: cfg.Model}
+	defaultEndpoint, defaultModel := voyageEndpoint, voyageModel
+	if provider == "cohere" {
+		defaultEndpoint, defaultModel = cohereEndpoint, cohereModel
+	}
+	if strings.TrimSpace(api.endpoint) == "" {
+		api.endpoint = defaultEndpoint
+	}
+	if strings.TrimSpace(api.model) == "" {
+		api.model = defaultModel
+	}
+	if api.apiKey == "" && api.endpoint == defaultEndpoint {
+		return nil, fmt.Errorf("rerank.api_key is required for %s", provider)
+	}
+	return api, nil
+}
+
+// apiReranker calls a hosted rerank endpoint. Voyage AI answers with a
+// "data" list and Cohere-compatible services with "results"; both items
+// carry the document index and a relevance score.
+type apiReranker struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	model    string
+}
+
+type rankedDocument struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+func (r *apiReranker) Rerank(ctx context.Context, query string, documents []string) ([]float64, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(map[string]any{
+		"model":     r.model,
+		"query":     query,
+		"documents": documents,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create rerank request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank request: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read rerank response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail := strings.TrimSpace(string(raw))
+		if len(detail) > 300 {
+			detail = detail[:300]
+		}
+		return nil, fmt.Errorf("rerank request failed: %s %s", resp.Status, detail)
+	}
+	var response struct {
+		Data    []rankedDocument `json:"data"`
+		Results []rankedDocument `json:"results"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("decode rerank response: %w", err)
+	}
+	ranked := append(response.Data, response.Results...)
+	if len(ranked) != len(documents) {
+		return nil, fmt.Errorf("rerank response scored %d of %d documents", len(ranked), len(documents))
+	}
+	scores := make([]float64, len(documents))
+	for _, item := range ranked {
+		if item.Index < 0 || item.Index >= len(documents) {
+			return nil, fmt.Errorf("rerank response has unexpected index %d", item.Index)
+		}
+		scores[item.Index] = item.RelevanceScore
+	}
+	return scores, nil
+}
@@ -0,0 +1,74 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+func TestLocalPrefersRareQueryWords(t *testing.T) {
+	scores, err := Local{}.Rerank(context.Background(), "dentist appointment for Maria", []string{
+		"appointment with the plumber on Friday",
+		"Maria has a dentist appointment on Tuesday",
+		"bought groceries",
+	})
+	if err != nil {
+		t.Fatalf("rerank: %v", err)
+	}
+	if !(scores[1] > scores[0] && scores[0] > scores[2]) {
+		t.Fatalf("unexpected scores %v", scores)
+	}
+	if scores[2] != 0 {
+		t.Fatalf("expected no overlap to score 0, got %f", scores[2])
+	}
+
+	// Shared stems count: "reminders" matches "remind".
+	scores, _ = Local{}.Rerank(context.Background(), "remind", []string{"set reminders", "set alarms"})
+	if scores[0] <= scores[1] {
+		t.Fatalf("expected stem match to score higher: %v", scores)
+	}
+}
+
+func TestAPIRerankerReadsBothResponseShapes(t *testing.T) {
+	for _, tc := range []struct {
+		provider, field string
+	}{
+		{"voyage", "data"},
+		{"cohere", "results"},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload struct {
+				Query     string   `json:"query"`
+				Documents []string `json:"documents"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Query != "q" || len(payload.Documents) != 2 {
+				t.Errorf("unexpected request %+v (%v)", payload, err)
+			}
+			fmt.Fprintf(w, `{"%s":[{"index":1,"relevance_score":0.9},{"index":0,"relevance_score":0.2}]}`, tc.field)
+		}))
+		reranker, err := NewFromConfig(config.RerankConfig{Provider: tc.provider, Endpoint: server.URL}, server.Client())
+		if err != nil {
+			t.Fatalf("%s: new reranker: %v", tc.provider, err)
+		}
+		scores, err := reranker.Rerank(context.Background(), "q", []string{"a", "b"})
+		server.Close()
+		if err != nil || scores[0] != 0.2 || scores[1] != 0.9 {
+			t.Fatalf("%s: unexpected scores %v (%v)", tc.provider, scores, err)
+		}
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	if reranker, err := NewFromConfig(config.RerankConfig{}, nil); reranker != nil || err != nil {
+		t.Fatalf("expected reranking off, got %v (%v)", reranker, err)
+	}
+	if _, err := NewFromConfig(config.RerankConfig{Provider: "voyage"}, http.DefaultClient); err == nil || !strings.Contains(err.Error(), "api_key") {
+		t.Fatalf("expected api_key error, got %v", err)
+	}
+}
@@ -3,13 +3,18 @@ package runtime
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/neoclaw-ai/neoclaw/internal/crash"
 	"github.com/neoclaw-ai/neoclaw/internal/logging"
 )
 
-const userVisibleHandlerError = "There was an error with your request. Check server logs for details"
+const (
+	userVisibleHandlerError = "There was an error with your request. Check server logs for details"
+	userVisibleCrashError   = "Something went wrong handling your request. A crash report was saved to %s; please attach it if you report a bug"
+)
 
 // Dispatcher executes queued messages sequentially against a Handler.
 type Dispatcher struct {
@@ -149,20 +154,37 @@ func (d *Dispatcher) run(ctx context.Context) {
 			}
 			runCtx, cancel := context.WithCancel(ctx)
 			d.setCurrentRun(cancel)
-			err := d.handler.HandleMessage(runCtx, item.writer, item.msg)
+			crashReport, err := d.handle(runCtx, item)
 			d.clearCurrentRun()
 			cancel()
 			if err == nil || errors.Is(err, context.Canceled) {
 				continue
 			}
-			logging.Logger().Error("message handling failed", "err", err)
-			if writeErr := item.writer.WriteMessage(ctx, userVisibleHandlerError); writeErr != nil {
+			message := userVisibleHandlerError
+			if crashReport != "" {
+				message = fmt.Sprintf(userVisibleCrashError, crashReport)
+			} else {
+				logging.Logger().Error("message handling failed", "err", err)
+			}
+			if writeErr := item.writer.WriteMessage(ctx, message); writeErr != nil {
 				logging.Logger().Warn("failed to write handler error message", "err", writeErr)
 			}
 		}
 	}
 }
 
+// handle runs the handler, turning a panic into an error and a crash report
+// so one bad message does not take the server down.
+func (d *Dispatcher) handle(ctx context.Context, item dispatchItem) (crashReport string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			crashReport = crash.Recovered("message handler", recovered)
+			err = fmt.Errorf("message handler panic: %v", recovered)
+		}
+	}()
+	return "", d.handler.HandleMessage(ctx, item.writer, item.msg)
+}
+
 func (d *Dispatcher) dispatchContext() (context.Context, bool) {
 	d.stateMu.Lock()
 	defer d.stateMu.Unlock()
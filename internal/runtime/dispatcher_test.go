@@ -3,9 +3,12 @@ package runtime
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/crash"
 )
 
 func TestDispatcherFIFO(t *testing.T) {
@@ -170,6 +173,37 @@ func TestDispatcherWritesHandlerErrors(t *testing.T) {
 	}
 }
 
+func TestDispatcherSurvivesHandlerPanic(t *testing.T) {
+	crashDir := t.TempDir()
+	crash.Configure(crashDir, "test", nil)
+	t.Cleanup(func() { crash.Configure("", "", nil) })
+	handler := &panicHandler{}
+	writer := &recordingWriter{}
+	d := NewDispatcher(handler, 20)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := d.Start(ctx); err != nil {
+		t.Fatalf("start dispatcher: %v", err)
+	}
+	for _, text := range []string{"panic", "ok"} {
+		if err := d.Enqueue(context.Background(), &Message{Text: text}, writer); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	if err := d.WaitUntilIdle(context.Background()); err != nil {
+		t.Fatalf("wait until idle: %v", err)
+	}
+	cancel()
+	d.Wait()
+
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	if len(writer.messages) != 2 || !strings.Contains(writer.messages[0], "crash report was saved to "+crashDir) || writer.messages[1] != "handled ok" {
+		t.Fatalf("expected crash notice then a normal reply, got %#v", writer.messages)
+	}
+}
+
 func TestDispatcherWaitUntilIdle(t *testing.T) {
 	handler := &recordingHandler{}
 	writer := &recordingWriter{}
@@ -315,6 +349,15 @@ func (h *errorHandler) HandleMessage(_ context.Context, _ ResponseWriter, _ *Mes
 	return h.err
 }
 
+type panicHandler struct{}
+
+func (h *panicHandler) HandleMessage(ctx context.Context, w ResponseWriter, msg *Message) error {
+	if msg.Text == "panic" {
+		panic("boom")
+	}
+	return w.WriteMessage(ctx, "handled "+msg.Text)
+}
+
 type recordingWriter struct {
 	mu       sync.Mutex
 	messages []string
@@ -1,11 +1,24 @@
 // Package runtime defines the core channel contracts — Message, Handler, ResponseWriter, and Listener — following the pattern of net/http.
 package runtime
 
-import "context"
+import (
+	"context"
+
+	"github.com/neoclaw-ai/neoclaw/internal/jsonschema"
+)
 
 // Message is an inbound message delivered by a channel transport.
 type Message struct {
 	Text string
+	// UserID identifies the sender on channels shared by several users; it
+	// is empty for the local CLI.
+	UserID string
+	// ResponseFormat overrides the handler's reply format for this message
+	// only: "text" or "json". Empty keeps the configured format.
+	ResponseFormat string
+	// ResponseSchema is a JSON Schema this message's reply must match. It
+	// implies the json format.
+	ResponseSchema jsonschema.Schema
 }
 
 // ResponseWriter sends handler responses back to the active channel transport.
@@ -13,6 +26,25 @@ type ResponseWriter interface {
 	WriteMessage(ctx context.Context, text string) error
 }
 
+// FileSender is implemented by ResponseWriters whose channel can deliver files,
+// like http.Flusher for http.ResponseWriter. Callers type-assert for it.
+type FileSender interface {
+	SendFile(ctx context.Context, path, caption string) error
+}
+
+// ActivityWriter is implemented by ResponseWriters that surface tool activity
+// separately from replies, for example to read-only observers.
+type ActivityWriter interface {
+	WriteActivity(ctx context.Context, text string) error
+}
+
+// StreamWriter is implemented by ResponseWriters that can show a reply while
+// the model is still writing it. Each WriteStream replaces the text shown
+// before; the next WriteMessage delivers the finished reply in its place.
+type StreamWriter interface {
+	WriteStream(ctx context.Context, text string) error
+}
+
 // Handler processes inbound messages and writes responses.
 type Handler interface {
 	HandleMessage(ctx context.Context, w ResponseWriter, msg *Message) error
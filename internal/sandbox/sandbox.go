@@ -16,3 +16,10 @@ func RestrictProcess(mode, dataDir string) error {
 func IsAlreadySandboxed() bool {
 	return strings.TrimSpace(os.Getenv(sandboxedEnvVar)) == "1"
 }
+
+// ExecConfined replaces the current process with argv, confined to dir: the
+// program may write only under dir and read only dir and system paths. It
+// returns only on failure, and fails rather than running unconfined.
+func ExecConfined(dir string, argv []string) error {
+	return execConfinedImpl(dir, argv)
+}
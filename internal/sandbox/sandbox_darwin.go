@@ -3,6 +3,7 @@
 package sandbox
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -71,6 +72,12 @@ func restrictProcessImpl(mode, dataDir string) error {
 	return nil
 }
 
+// execConfinedImpl always fails: claw already runs under sandbox-exec, and
+// macOS does not allow a sandboxed process to apply another profile.
+func execConfinedImpl(dir string, argv []string) error {
+	return errors.New("per-command sandbox is not supported on macOS")
+}
+
 // darwinProfile builds the SBPL profile for the given security mode.
 func darwinProfile(mode, dataDir string) string {
 	switch strings.TrimSpace(mode) {
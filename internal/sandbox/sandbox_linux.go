@@ -5,6 +5,8 @@ package sandbox
 import (
 	"errors"
 	"fmt"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -54,7 +56,7 @@ func restrictProcessImpl(mode, dataDir string) error {
 		landlock.RWDirs("/dev"),
 	}
 	if trimmedMode == config.SecurityModeStrict {
-		rules = append(rules, strictLinuxReadRules(absDataDir)...)
+		rules = append(rules, strictLinuxReadRules(filepath.Dir(absDataDir))...)
 	} else {
 		rules = append(rules, landlock.RODirs("/"))
 	}
@@ -65,9 +67,43 @@ func restrictProcessImpl(mode, dataDir string) error {
 	return nil
 }
 
-func strictLinuxReadRules(dataDir string) []landlock.Rule {
+func execConfinedImpl(dir string, argv []string) error {
+	if len(argv) == 0 {
+		return errors.New("command is required")
+	}
+	if !IsSandboxSupported() {
+		return errors.New("landlock is unavailable on this host")
+	}
+	absDir, err := filepath.Abs(strings.TrimSpace(dir))
+	if err != nil {
+		return fmt.Errorf("resolve sandbox dir: %w", err)
+	}
+	absDir, err = filepath.EvalSymlinks(absDir)
+	if err != nil {
+		return fmt.Errorf("resolve sandbox dir symlinks: %w", err)
+	}
+	program, err := exec.LookPath(argv[0])
+	if err != nil {
+		return fmt.Errorf("find %s: %w", argv[0], err)
+	}
+
+	rules := append([]landlock.Rule{
+		landlock.RWDirs(absDir),
+		landlock.RWDirs("/dev"),
+	}, strictLinuxReadRules(absDir)...)
+	if err := landlock.V6.BestEffort().RestrictPaths(rules...); err != nil {
+		return fmt.Errorf("restrict command with landlock: %w", err)
+	}
+	if err := unix.Exec(program, argv, os.Environ()); err != nil {
+		return fmt.Errorf("exec %s: %w", argv[0], err)
+	}
+	return nil
+}
+
+// strictLinuxReadRules allows reading homeDir and system paths.
+func strictLinuxReadRules(homeDir string) []landlock.Rule {
 	readRoots := []string{
-		filepath.Dir(dataDir),
+		homeDir,
 		"/bin",
 		"/sbin",
 		"/usr",
@@ -2,6 +2,8 @@
 
 package sandbox
 
+import "errors"
+
 // IsSandboxSupported reports sandbox support on non-Linux/non-Darwin platforms.
 func IsSandboxSupported() bool {
 	return false
@@ -10,3 +12,7 @@ func IsSandboxSupported() bool {
 func restrictProcessImpl(mode, dataDir string) error {
 	return nil
 }
+
+func execConfinedImpl(dir string, argv []string) error {
+	return errors.New("per-command sandbox is not supported on this platform")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/neoclaw-ai/neoclaw/internal/crash"
 	"github.com/neoclaw-ai/neoclaw/internal/logging"
 )
 
@@ -16,6 +17,18 @@ type ActionRunners struct {
 	SendMessage func(ctx context.Context, writer io.Writer, args map[string]any) (string, error)
 	RunCommand  func(ctx context.Context, args map[string]any) (string, error)
 	HTTPRequest func(ctx context.Context, args map[string]any) (string, error)
+	// ProfileRefresh proposes USER.md updates and reports them to writer.
+	ProfileRefresh func(ctx context.Context, writer io.Writer, args map[string]any) (string, error)
+	// ProactiveCheckIn evaluates and, if warranted, sends a check-in to writer.
+	ProactiveCheckIn func(ctx context.Context, writer io.Writer, args map[string]any) (string, error)
+	// RunWorkflow runs the workflow named in args and reports the result to writer.
+	RunWorkflow func(ctx context.Context, writer io.Writer, args map[string]any) (string, error)
+	// WorkspaceCleanup prunes workspace/tmp and returns a summary.
+	WorkspaceCleanup func(ctx context.Context, args map[string]any) (string, error)
+	// MemoryRetention prunes expired daily log entries and returns a summary.
+	MemoryRetention func(ctx context.Context, args map[string]any) (string, error)
+	// MemoryDigest writes missing weekly and monthly digests and returns a summary.
+	MemoryDigest func(ctx context.Context, args map[string]any) (string, error)
 }
 
 // Runner executes scheduler jobs by dispatching to action-specific handlers.
@@ -23,6 +36,12 @@ type Runner struct {
 	sendMessage func(ctx context.Context, writer io.Writer, args map[string]any) (string, error)
 	runCommand  func(ctx context.Context, args map[string]any) (string, error)
 	httpRequest func(ctx context.Context, args map[string]any) (string, error)
+	profile     func(ctx context.Context, writer io.Writer, args map[string]any) (string, error)
+	checkIn     func(ctx context.Context, writer io.Writer, args map[string]any) (string, error)
+	workflow    func(ctx context.Context, writer io.Writer, args map[string]any) (string, error)
+	cleanup     func(ctx context.Context, args map[string]any) (string, error)
+	retention   func(ctx context.Context, args map[string]any) (string, error)
+	digest      func(ctx context.Context, args map[string]any) (string, error)
 	writers     map[string]io.Writer
 }
 
@@ -32,29 +51,36 @@ func NewRunner(r ActionRunners, writers map[string]io.Writer) *Runner {
 		sendMessage: r.SendMessage,
 		runCommand:  r.RunCommand,
 		httpRequest: r.HTTPRequest,
+		profile:     r.ProfileRefresh,
+		checkIn:     r.ProactiveCheckIn,
+		workflow:    r.RunWorkflow,
+		cleanup:     r.WorkspaceCleanup,
+		retention:   r.MemoryRetention,
+		digest:      r.MemoryDigest,
 		writers:     writers,
 	}
 }
 
 // Run executes one job action and returns tool output text.
-func (r *Runner) Run(ctx context.Context, job Job) (string, error) {
+func (r *Runner) Run(ctx context.Context, job Job) (output string, err error) {
+	// A panicking job must not take the scheduler, or the server, down.
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job %s panicked: %v", job.ID, recovered)
+			if path := crash.Recovered("scheduled job "+job.ID, recovered); path != "" {
+				err = fmt.Errorf("%w (crash report: %s)", err, path)
+			}
+		}
+	}()
 	args := cloneArgs(job.Args)
 	switch job.Action {
 	case ActionSendMessage:
 		if r.sendMessage == nil {
 			return "", errors.New("send_message runner is not configured")
 		}
-		if r.writers == nil {
-			return "", errors.New("send_message writers registry is not configured")
-		}
-		writer, ok := r.writers[job.ChannelID]
-		if !ok {
-			logging.Logger().Warn(
-				"scheduled send_message skipped: unknown channel",
-				"job_id", job.ID,
-				"channel_id", job.ChannelID,
-			)
-			return "", nil
+		writer, ok, err := r.channelWriter(job)
+		if err != nil || !ok {
+			return "", err
 		}
 		return r.sendMessage(ctx, writer, args)
 	case ActionRunCommand:
@@ -68,7 +94,68 @@ func (r *Runner) Run(ctx context.Context, job Job) (string, error) {
 			return "", errors.New("http_request runner is not configured")
 		}
 		return r.httpRequest(ctx, args)
+	case ActionProfileRefresh:
+		if r.profile == nil {
+			return "", errors.New("profile_refresh runner is not configured")
+		}
+		writer, ok, err := r.channelWriter(job)
+		if err != nil || !ok {
+			return "", err
+		}
+		return r.profile(ctx, writer, args)
+	case ActionProactiveCheckIn:
+		if r.checkIn == nil {
+			return "", errors.New("proactive_checkin runner is not configured")
+		}
+		writer, ok, err := r.channelWriter(job)
+		if err != nil || !ok {
+			return "", err
+		}
+		return r.checkIn(ctx, writer, args)
+	case ActionRunWorkflow:
+		if r.workflow == nil {
+			return "", errors.New("run_workflow runner is not configured")
+		}
+		writer, ok, err := r.channelWriter(job)
+		if err != nil || !ok {
+			return "", err
+		}
+		return r.workflow(ctx, writer, args)
+	case ActionWorkspaceCleanup:
+		if r.cleanup == nil {
+			return "", errors.New("workspace_cleanup runner is not configured")
+		}
+		return r.cleanup(ctx, args)
+	case ActionMemoryRetention:
+		if r.retention == nil {
+			return "", errors.New("memory_retention runner is not configured")
+		}
+		return r.retention(ctx, args)
+	case ActionMemoryDigest:
+		if r.digest == nil {
+			return "", errors.New("memory_digest runner is not configured")
+		}
+		return r.digest(ctx, args)
 	default:
 		return "", fmt.Errorf("unsupported action %s", job.Action)
 	}
 }
+
+// channelWriter resolves the job's delivery channel. Unknown channels are
+// logged and skipped rather than failing the job.
+func (r *Runner) channelWriter(job Job) (io.Writer, bool, error) {
+	if r.writers == nil {
+		return nil, false, fmt.Errorf("%s writers registry is not configured", job.Action)
+	}
+	writer, ok := r.writers[job.ChannelID]
+	if !ok {
+		logging.Logger().Warn(
+			"scheduled job skipped: unknown channel",
+			"job_id", job.ID,
+			"action", job.Action,
+			"channel_id", job.ChannelID,
+		)
+		return nil, false, nil
+	}
+	return writer, true, nil
+}
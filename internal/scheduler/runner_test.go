@@ -34,6 +34,27 @@ func TestNewRunnerDispatch(t *testing.T) {
 			}
 			return "fetched", nil
 		},
+		ProfileRefresh: func(_ context.Context, writer io.Writer, _ map[string]any) (string, error) {
+			if writer != telegramWriter {
+				t.Fatalf("unexpected profile writer: %#v", writer)
+			}
+			return "proposed", nil
+		},
+		RunWorkflow: func(_ context.Context, writer io.Writer, args map[string]any) (string, error) {
+			if writer != telegramWriter || args["workflow"] != "weekly-review" {
+				t.Fatalf("unexpected workflow call: writer=%#v args=%#v", writer, args)
+			}
+			return "workflow completed", nil
+		},
+		WorkspaceCleanup: func(context.Context, map[string]any) (string, error) {
+			return "cleaned", nil
+		},
+		MemoryRetention: func(context.Context, map[string]any) (string, error) {
+			return "pruned", nil
+		},
+		MemoryDigest: func(context.Context, map[string]any) (string, error) {
+			return "digested", nil
+		},
 	}, map[string]io.Writer{
 		"telegram-123": telegramWriter,
 	})
@@ -54,6 +75,26 @@ func TestNewRunnerDispatch(t *testing.T) {
 	if err != nil || out != "fetched" {
 		t.Fatalf("http: out=%q err=%v", out, err)
 	}
+	out, err = r.Run(context.Background(), Job{Action: ActionProfileRefresh, ChannelID: "telegram-123", Args: map[string]any{}})
+	if err != nil || out != "proposed" {
+		t.Fatalf("profile refresh: out=%q err=%v", out, err)
+	}
+	out, err = r.Run(context.Background(), Job{Action: ActionRunWorkflow, ChannelID: "telegram-123", Args: map[string]any{"workflow": "weekly-review"}})
+	if err != nil || out != "workflow completed" {
+		t.Fatalf("run workflow: out=%q err=%v", out, err)
+	}
+	out, err = r.Run(context.Background(), Job{Action: ActionWorkspaceCleanup, Args: map[string]any{}})
+	if err != nil || out != "cleaned" {
+		t.Fatalf("workspace cleanup: out=%q err=%v", out, err)
+	}
+	out, err = r.Run(context.Background(), Job{Action: ActionMemoryRetention, Args: map[string]any{}})
+	if err != nil || out != "pruned" {
+		t.Fatalf("memory retention: out=%q err=%v", out, err)
+	}
+	out, err = r.Run(context.Background(), Job{Action: ActionMemoryDigest, Args: map[string]any{}})
+	if err != nil || out != "digested" {
+		t.Fatalf("memory digest: out=%q err=%v", out, err)
+	}
 }
 
 func TestNewRunnerMissingActionRunner(t *testing.T) {
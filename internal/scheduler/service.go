@@ -13,12 +13,14 @@ import (
 
 // Service runs scheduled jobs backed by one jobs.json file.
 type Service struct {
-	store   *jobStore
-	runner  *Runner
-	cron    *cron.Cron
-	started bool
-	runCtx  context.Context
-	mu      sync.Mutex
+	store    *jobStore
+	runner   *Runner
+	builtins []Job
+	cron     *cron.Cron
+	timers   map[string]*time.Timer
+	started  bool
+	runCtx   context.Context
+	mu       sync.Mutex
 }
 
 // NewService creates a direct cron-backed scheduler service over one jobs.json path.
@@ -47,7 +49,7 @@ func (s *Service) Start(ctx context.Context) error {
 	}
 
 	s.store.clearEntryIDs()
-	for _, job := range jobs {
+	for _, job := range append(jobs, s.builtins...) {
 		if !job.Enabled {
 			continue
 		}
@@ -63,6 +65,22 @@ func (s *Service) Start(ctx context.Context) error {
 	return nil
 }
 
+// AddBuiltin registers a config-driven job that runs on the cron schedule but
+// is not persisted to jobs.json or shown by List. Call it before Start.
+func (s *Service) AddBuiltin(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return errors.New("builtin jobs must be added before the scheduler starts")
+	}
+	job.Enabled = true
+	if err := validateJob(job); err != nil {
+		return fmt.Errorf("builtin job %s: %w", job.ID, err)
+	}
+	s.builtins = append(s.builtins, job)
+	return nil
+}
+
 // Stop stops cron and waits for in-flight callbacks to finish or ctx cancellation.
 func (s *Service) Stop(ctx context.Context) error {
 	s.mu.Lock()
@@ -72,6 +90,10 @@ func (s *Service) Stop(ctx context.Context) error {
 	}
 
 	doneCtx := s.cron.Stop()
+	for id, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, id)
+	}
 	s.started = false
 	s.runCtx = nil
 	s.store.clearEntryIDs()
@@ -120,6 +142,13 @@ func (s *Service) RunNow(ctx context.Context, jobID string) (string, error) {
 	return output, nil
 }
 
+// Running reports whether the scheduler has been started and is firing jobs.
+func (s *Service) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.started
+}
+
 // List returns all persisted scheduled jobs.
 func (s *Service) List(ctx context.Context) ([]Job, error) {
 	return s.store.List(ctx)
@@ -171,6 +200,11 @@ func (s *Service) unregister(jobID string) {
 		return
 	}
 
+	if timer, ok := s.timers[jobID]; ok {
+		timer.Stop()
+		delete(s.timers, jobID)
+		return
+	}
 	entryID, ok := s.store.entryID(jobID)
 	if !ok {
 		return
@@ -180,6 +214,10 @@ func (s *Service) unregister(jobID string) {
 }
 
 func (s *Service) addCronEntry(job Job, runCtx context.Context) error {
+	if !job.RunAt.IsZero() {
+		s.addOneShot(job, runCtx)
+		return nil
+	}
 	capturedJob := job
 	entryID, err := s.cron.AddFunc(capturedJob.Cron, func() {
 		output, runErr := s.runner.Run(runCtx, capturedJob)
@@ -205,3 +243,26 @@ func (s *Service) addCronEntry(job Job, runCtx context.Context) error {
 	s.store.setEntryID(capturedJob.ID, entryID)
 	return nil
 }
+
+// addOneShot runs a RunAt job once, immediately if its time passed while the
+// scheduler was stopped, and then deletes it. Callers hold s.mu.
+func (s *Service) addOneShot(job Job, runCtx context.Context) {
+	if s.timers == nil {
+		s.timers = make(map[string]*time.Timer)
+	}
+	s.timers[job.ID] = time.AfterFunc(max(time.Until(job.RunAt), 0), func() {
+		s.mu.Lock()
+		delete(s.timers, job.ID)
+		s.mu.Unlock()
+
+		output, runErr := s.runner.Run(runCtx, job)
+		if runErr != nil {
+			logging.Logger().Warn("scheduled job failed", "job_id", job.ID, "action", job.Action, "err", runErr)
+		} else {
+			logging.Logger().Info("scheduled job succeeded", "job_id", job.ID, "action", job.Action, "output_len", len(output))
+		}
+		if err := s.store.Delete(context.WithoutCancel(runCtx), job.ID); err != nil {
+			logging.Logger().Warn("delete one-shot job", "job_id", job.ID, "err", err)
+		}
+	})
+}
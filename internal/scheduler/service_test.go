@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -171,3 +172,131 @@ func TestRegisterAndUnregisterManageEntryMapping(t *testing.T) {
 		t.Fatalf("expected cron entry mapping removed for job %q", job.ID)
 	}
 }
+
+func TestAddBuiltinRegistersOnStartWithoutPersisting(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(filepath.Join(t.TempDir(), "jobs.json"), NewRunner(ActionRunners{}, map[string]io.Writer{
+		"cli": io.Discard,
+	}))
+	if err := svc.AddBuiltin(Job{
+		ID:          "builtin_proactive_checkin",
+		Description: "Proactive check-in",
+		Cron:        "0 10 * * *",
+		Action:      ActionProactiveCheckIn,
+		Args:        map[string]any{},
+		ChannelID:   "cli",
+	}); err != nil {
+		t.Fatalf("add builtin: %v", err)
+	}
+
+	if err := svc.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer svc.Stop(context.Background())
+
+	if _, ok := svc.store.entryID("builtin_proactive_checkin"); !ok {
+		t.Fatalf("expected builtin job to be registered in cron")
+	}
+	jobs, err := svc.List(context.Background())
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Fatalf("expected builtin job not to be persisted, got %#v", jobs)
+	}
+
+	if err := svc.AddBuiltin(Job{ID: "late", Description: "late", Cron: "0 10 * * *", Action: ActionSendMessage, Args: map[string]any{}, ChannelID: "cli"}); err == nil {
+		t.Fatalf("expected error adding builtin after start")
+	}
+}
+
+func TestAddBuiltinRejectsInvalidCron(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(filepath.Join(t.TempDir(), "jobs.json"), NewRunner(ActionRunners{}, nil))
+	err := svc.AddBuiltin(Job{
+		ID:          "builtin",
+		Description: "bad",
+		Cron:        "not a cron",
+		Action:      ActionProactiveCheckIn,
+		Args:        map[string]any{},
+		ChannelID:   "cli",
+	})
+	if err == nil {
+		t.Fatalf("expected invalid cron error")
+	}
+}
+
+func TestOneShotJobRunsOnceAndIsDeleted(t *testing.T) {
+	t.Parallel()
+
+	sent := make(chan string, 1)
+	svc := NewService(filepath.Join(t.TempDir(), "jobs.json"), NewRunner(ActionRunners{
+		SendMessage: func(_ context.Context, _ io.Writer, args map[string]any) (string, error) {
+			sent <- args["message"].(string)
+			return "sent", nil
+		},
+	}, map[string]io.Writer{
+		"cli": io.Discard,
+	}))
+	job, err := svc.Create(context.Background(), CreateInput{
+		Description: "timer",
+		RunAt:       time.Now().Add(50 * time.Millisecond),
+		Action:      ActionSendMessage,
+		Args:        map[string]any{"message": "done"},
+		ChannelID:   "cli",
+	})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	if got := job.Schedule(); !strings.HasPrefix(got, "once at ") {
+		t.Fatalf("expected one-shot schedule, got %q", got)
+	}
+
+	if err := svc.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer svc.Stop(context.Background())
+
+	select {
+	case message := <-sent:
+		if message != "done" {
+			t.Fatalf("expected message done, got %q", message)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("one-shot job did not run")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		jobs, err := svc.List(context.Background())
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		if len(jobs) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected one-shot job to be deleted, got %#v", jobs)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCreateRejectsCronWithRunAt(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(filepath.Join(t.TempDir(), "jobs.json"), NewRunner(ActionRunners{}, nil))
+	_, err := svc.Create(context.Background(), CreateInput{
+		Description: "both",
+		Cron:        "0 9 * * *",
+		RunAt:       time.Now().Add(time.Hour),
+		Action:      ActionSendMessage,
+		Args:        map[string]any{"message": "x"},
+		ChannelID:   "cli",
+	})
+	if err == nil {
+		t.Fatalf("expected error for cron with run_at")
+	}
+}
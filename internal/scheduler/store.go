@@ -26,6 +26,19 @@ const (
 	ActionRunCommand Action = "run_command"
 	// ActionHTTPRequest performs an HTTP request.
 	ActionHTTPRequest Action = "http_request"
+	// ActionProfileRefresh reviews recent memory and proposes USER.md updates.
+	ActionProfileRefresh Action = "profile_refresh"
+	// ActionProactiveCheckIn lets the agent decide whether to message the user
+	// unprompted. It is registered from config, not created as a user job.
+	ActionProactiveCheckIn Action = "proactive_checkin"
+	// ActionRunWorkflow runs a workflow from the workflows directory.
+	ActionRunWorkflow Action = "run_workflow"
+	// ActionWorkspaceCleanup prunes old and oversized files under workspace/tmp.
+	ActionWorkspaceCleanup Action = "workspace_cleanup"
+	// ActionMemoryRetention deletes daily log entries past their tag's retention.
+	ActionMemoryRetention Action = "memory_retention"
+	// ActionMemoryDigest writes weekly and monthly digests of the daily log.
+	ActionMemoryDigest Action = "memory_digest"
 )
 
 // Job is one persisted scheduled task in jobs.json.
@@ -39,12 +52,43 @@ type Job struct {
 	Enabled     bool           `json:"enabled"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
+	// RunAt makes the job run once at this time instead of on Cron; it is
+	// deleted after running.
+	RunAt time.Time `json:"run_at,omitzero"`
 }
 
-// CreateInput contains fields required to create a job.
+// Schedule describes when the job runs: its cron expression, or the time of
+// a one-shot run.
+func (j Job) Schedule() string {
+	if !j.RunAt.IsZero() {
+		return "once at " + j.RunAt.In(time.Local).Format("2006-01-02 15:04:05")
+	}
+	return j.Cron
+}
+
+// NextRun returns when an enabled job runs next after t: RunAt for a one-shot
+// job, even when that has already passed, or the next time its cron expression
+// matches in local time. It is zero for disabled jobs and invalid expressions.
+func (j Job) NextRun(t time.Time) time.Time {
+	if !j.Enabled {
+		return time.Time{}
+	}
+	if !j.RunAt.IsZero() {
+		return j.RunAt
+	}
+	schedule, err := cron.ParseStandard(strings.TrimSpace(j.Cron))
+	if err != nil {
+		return time.Time{}
+	}
+	return schedule.Next(t.In(time.Local))
+}
+
+// CreateInput contains fields required to create a job. Set either Cron or
+// RunAt.
 type CreateInput struct {
 	Description string
 	Cron        string
+	RunAt       time.Time
 	Action      Action
 	Args        map[string]any
 	ChannelID   string
@@ -116,6 +160,7 @@ func (s *jobStore) Create(ctx context.Context, in CreateInput) (Job, error) {
 		ID:          newJobID(now),
 		Description: strings.TrimSpace(in.Description),
 		Cron:        strings.TrimSpace(in.Cron),
+		RunAt:       in.RunAt,
 		Action:      in.Action,
 		Args:        cloneArgs(in.Args),
 		ChannelID:   strings.TrimSpace(in.ChannelID),
@@ -135,7 +180,7 @@ func (s *jobStore) Create(ctx context.Context, in CreateInput) (Job, error) {
 		"scheduled job created",
 		"job_id", job.ID,
 		"description", job.Description,
-		"cron", job.Cron,
+		"schedule", job.Schedule(),
 		"action", job.Action,
 		"channel_id", job.ChannelID,
 	)
@@ -257,8 +302,12 @@ func validateJob(job Job) error {
 	if err := validateAction(job.Action); err != nil {
 		return err
 	}
-	if err := validateCron(job.Cron); err != nil {
-		return err
+	if job.RunAt.IsZero() {
+		if err := validateCron(job.Cron); err != nil {
+			return err
+		}
+	} else if strings.TrimSpace(job.Cron) != "" {
+		return errors.New("job cannot have both cron and run_at")
 	}
 	if job.Args == nil {
 		return errors.New("job args are required")
@@ -268,7 +317,7 @@ func validateJob(job Job) error {
 
 func validateAction(action Action) error {
 	switch action {
-	case ActionSendMessage, ActionRunCommand, ActionHTTPRequest:
+	case ActionSendMessage, ActionRunCommand, ActionHTTPRequest, ActionProfileRefresh, ActionProactiveCheckIn, ActionRunWorkflow, ActionWorkspaceCleanup, ActionMemoryRetention, ActionMemoryDigest:
 		return nil
 	default:
 		return fmt.Errorf("unsupported job action %s", action)
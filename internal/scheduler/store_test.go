@@ -5,6 +5,7 @@ import (
 	"errors"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestStoreListMissingFileReturnsEmpty(t *testing.T) {
@@ -175,3 +176,22 @@ func TestStoreContextCancel(t *testing.T) {
 		t.Fatalf("expected context canceled, got %v", err)
 	}
 }
+
+func TestJobNextRun(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 30, 0, 0, time.Local)
+	runAt := now.Add(-time.Hour)
+	cases := []struct {
+		job  Job
+		want time.Time
+	}{
+		{Job{Enabled: true, Cron: "0 9 * * *"}, time.Date(2026, 3, 3, 9, 0, 0, 0, time.Local)},
+		{Job{Enabled: true, RunAt: runAt}, runAt},
+		{Job{Enabled: false, Cron: "0 9 * * *"}, time.Time{}},
+		{Job{Enabled: true, Cron: "bogus"}, time.Time{}},
+	}
+	for _, tc := range cases {
+		if got := tc.job.NextRun(now); !got.Equal(tc.want) {
+			t.Fatalf("NextRun(%+v) = %v, want %v", tc.job, got, tc.want)
+		}
+	}
+}
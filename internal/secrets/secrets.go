@@ -0,0 +1,107 @@
+// Package secrets keeps credentials NeoClaw obtains at runtime, such as OAuth
+// tokens, in owner-only files. They are written straight to the local disk,
+// never through the store backend, so storage replicas do not copy them.
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Store holds named secrets as JSON files in one directory.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// New creates a secrets store backed by dir.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Load decodes the secret called name into v. It reports false when the
+// secret does not exist.
+func (s *Store) Load(name string, v any) (bool, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("read secret %s: %w", name, err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("decode secret %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// Save stores v as the secret called name, readable only by its owner.
+func (s *Store) Save(name string, v any) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode secret %s: %w", name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("create secrets directory: %w", err)
+	}
+	tempFile, err := os.CreateTemp(s.dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write secret %s: %w", name, err)
+	}
+	tempPath := tempFile.Name()
+	defer os.Remove(tempPath)
+	if _, err := tempFile.Write(append(raw, '\n')); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("write secret %s: %w", name, err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("write secret %s: %w", name, err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("write secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// Delete removes the secret called name. Deleting a missing secret is not an
+// error.
+func (s *Store) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete secret %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Store) path(name string) (string, error) {
+	if strings.TrimSpace(s.dir) == "" {
+		return "", errors.New("secrets directory is required")
+	}
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+	return filepath.Join(s.dir, name+".json"), nil
+}
@@ -0,0 +1,49 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoadDelete(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "secrets")
+	s := New(dir)
+
+	var got map[string]string
+	if ok, err := s.Load("oauth-default", &got); err != nil || ok {
+		t.Fatalf("expected no secret yet, ok=%v err=%v", ok, err)
+	}
+	if err := s.Save("oauth-default", map[string]string{"access_token": "tok"}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "oauth-default.json"))
+	if err != nil {
+		t.Fatalf("stat secret: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected an owner-only file, got %v", info.Mode().Perm())
+	}
+	if ok, err := s.Load("oauth-default", &got); err != nil || !ok || got["access_token"] != "tok" {
+		t.Fatalf("load: %#v ok=%v err=%v", got, ok, err)
+	}
+
+	if err := s.Delete("oauth-default"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.Delete("oauth-default"); err != nil {
+		t.Fatalf("delete again: %v", err)
+	}
+	if ok, err := s.Load("oauth-default", &got); err != nil || ok {
+		t.Fatalf("expected the secret gone, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRejectsNamesOutsideTheDirectory(t *testing.T) {
+	s := New(t.TempDir())
+	for _, name := range []string{"", "../escape", "a/b", ".hidden"} {
+		if err := s.Save(name, "x"); err == nil {
+			t.Fatalf("expected %q to be refused", name)
+		}
+	}
+}
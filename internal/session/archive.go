@@ -0,0 +1,60 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// archiveFileExt is not sessionFileExt so archived messages never show up as
+// a session of their own.
+const archiveFileExt = ".rewound"
+
+// Archive appends messages removed from the session to a JSONL file next to
+// it, so a rewind can be undone by hand.
+func (s *Store) Archive(ctx context.Context, messages []provider.ChatMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	if s == nil || s.path == "" {
+		return errors.New("session path is required")
+	}
+	var b strings.Builder
+
+	for _, msg := range messages {
+		encoded, err := json.Marshal(s.record(msg))
+		if err != nil {
+			return fmt.Errorf("marshal session record: %w", err)
+		}
+		b.Write(encoded)
+		b.WriteByte('\n')
+	}
+
+	if err := store.AppendFile(archivePath(s.path), []byte(b.String())); err != nil {
+		return fmt.Errorf("append session archive: %w", err)
+	}
+	return nil
+}
+
+// Archived reads the messages archived for the session, oldest first.
+func (s *Store) Archived(ctx context.Context) ([]provider.ChatMessage, error) {
+	if s == nil || s.path == "" {
+		return nil, errors.New("session path is required")
+	}
+	return New(archivePath(s.path)).Load(ctx)
+}
+
+func archivePath(sessionPath string) string {
+	return strings.TrimSuffix(sessionPath, sessionFileExt) + archiveFileExt
+}
@@ -0,0 +1,156 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// attachFileExt is not sessionFileExt so the list never shows up as a
+// session of its own.
+const attachFileExt = ".attached.json"
+
+// Attachment is one channel, in one process, with the session open.
+type Attachment struct {
+	Channel string    `json:"channel"`
+	PID     int       `json:"pid"`
+	Since   time.Time `json:"since"`
+	// Here marks the caller's own entry; it is not stored.
+	Here bool `json:"-"`
+}
+
+// Attach records that channel in this process continues the session, so
+// other channels can see it with Attached. Entries left by processes that
+// have exited are dropped.
+func (s *Store) Attach(channel string) error {
+	return s.updateAttachments(func(attached []Attachment) []Attachment {
+		for _, a := range attached {
+			if a.Channel == channel && a.PID == os.Getpid() {
+				return attached
+			}
+		}
+		return append(attached, Attachment{Channel: channel, PID: os.Getpid(), Since: time.Now().UTC()})
+	})
+}
+
+// Detach removes the entry Attach made for channel in this process.
+func (s *Store) Detach(channel string) error {
+	return s.updateAttachments(func(attached []Attachment) []Attachment {
+		return slices.DeleteFunc(attached, func(a Attachment) bool {
+			return a.Channel == channel && a.PID == os.Getpid()
+		})
+	})
+}
+
+// Attached returns the channels with the session open, oldest first.
+func (s *Store) Attached() ([]Attachment, error) {
+	if s == nil || s.path == "" {
+		return nil, errors.New("session path is required")
+	}
+	return readAttachments(attachPath(s.path))
+}
+
+func (s *Store) updateAttachments(update func([]Attachment) []Attachment) error {
+	if s == nil || s.path == "" {
+		return errors.New("session path is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := attachPath(s.path)
+	attached, err := readAttachments(path)
+	if err != nil {
+		return err
+	}
+	attached = update(attached)
+	if len(attached) == 0 {
+		if err := store.RemoveFile(path); err != nil {
+			return fmt.Errorf("remove session attachments: %w", err)
+		}
+		return nil
+	}
+	encoded, err := json.MarshalIndent(attached, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal session attachments: %w", err)
+	}
+	if err := store.WriteFile(path, append(encoded, '\n')); err != nil {
+		return fmt.Errorf("write session attachments: %w", err)
+	}
+	return nil
+}
+
+// readAttachments returns the attachments of processes still running.
+func readAttachments(path string) ([]Attachment, error) {
+	content, err := store.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session attachments: %w", err)
+	}
+	var attached []Attachment
+	if err := json.Unmarshal([]byte(content), &attached); err != nil {
+		return nil, fmt.Errorf("parse session attachments: %w", err)
+	}
+	return slices.DeleteFunc(attached, func(a Attachment) bool {
+		return !processRunning(a.PID)
+	}), nil
+}
+
+func attachPath(sessionPath string) string {
+	return strings.TrimSuffix(sessionPath, sessionFileExt) + attachFileExt
+}
+
+// processRunning checks pid with signal 0. EPERM means it exists but
+// belongs to another user, which still counts.
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// fileStamp identifies one version of the session file.
+type fileStamp struct {
+	size    int64
+	modTime int64
+}
+
+func statSession(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{size: info.Size(), modTime: info.ModTime().UnixNano()}
+}
+
+// ChangedElsewhere reports whether the session file changed since this
+// store last read or wrote it, e.g. because another channel attached to the
+// session added a turn.
+func (s *Store) ChangedElsewhere() bool {
+	if s == nil || s.path == "" {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stamped && statSession(s.path) != s.stamp
+}
+
+// remember records the current file version as seen by this store. The
+// caller must hold s.mu.
+func (s *Store) remember() {
+	s.stamp = statSession(s.path)
+	s.stamped = true
+}
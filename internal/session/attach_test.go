@@ -0,0 +1,67 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+func TestAttachRecordsChannelsAndDropsExitedProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telegram", "default.jsonl")
+	s := New(path)
+	if err := store.WriteFile(attachPath(path), []byte(`[{"channel":"cli","pid":-1,"since":"2026-03-01T10:00:00Z"}]`)); err != nil {
+		t.Fatalf("seed attachments: %v", err)
+	}
+
+	if err := s.Attach("telegram"); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	if err := s.Attach("telegram"); err != nil {
+		t.Fatalf("attach again: %v", err)
+	}
+	attached, err := s.Attached()
+	if err != nil || len(attached) != 1 || attached[0].Channel != "telegram" {
+		t.Fatalf("expected only the live attachment, got %#v (%v)", attached, err)
+	}
+
+	if err := s.Detach("telegram"); err != nil {
+		t.Fatalf("detach: %v", err)
+	}
+	if attached, err := s.Attached(); err != nil || len(attached) != 0 {
+		t.Fatalf("expected no attachments, got %#v (%v)", attached, err)
+	}
+	infos, err := List(context.Background(), filepath.Dir(filepath.Dir(path)))
+	if err != nil || len(infos) != 0 {
+		t.Fatalf("expected attachments not to be listed as sessions, got %#v (%v)", infos, err)
+	}
+}
+
+func TestChangedElsewhereSeesOtherWriters(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "default.jsonl")
+	mine, theirs := New(path), New(path)
+	if mine.ChangedElsewhere() {
+		t.Fatalf("expected an unread store not to report changes")
+	}
+	if err := mine.Append(ctx, []provider.ChatMessage{{Role: provider.RoleUser, Content: "hi"}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if mine.ChangedElsewhere() {
+		t.Fatalf("expected own writes not to count")
+	}
+	if err := theirs.Append(ctx, []provider.ChatMessage{{Role: provider.RoleAssistant, Content: "hello"}}); err != nil {
+		t.Fatalf("append elsewhere: %v", err)
+	}
+	if !mine.ChangedElsewhere() {
+		t.Fatalf("expected the other writer's append to be seen")
+	}
+	if _, err := mine.Load(ctx); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if mine.ChangedElsewhere() {
+		t.Fatalf("expected a reload to catch up")
+	}
+}
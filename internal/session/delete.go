@@ -0,0 +1,115 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// Deletion is one audit record of messages removed from a session. Only the
+// role and length of each message are kept, so deleting a pasted secret does
+// not copy it, or a hash that could be guessed, into the audit log.
+type Deletion struct {
+	Time time.Time `json:"time"`
+	// Actor is what removed the messages, e.g. /delete-last or a claw command.
+	Actor    string           `json:"actor"`
+	Session  string           `json:"session"`
+	Messages []DeletedMessage `json:"messages"`
+}
+
+// DeletedMessage describes one removed message.
+type DeletedMessage struct {
+	// Index is the message's 0-based position in the session before deletion.
+	Index int           `json:"index"`
+	Role  provider.Role `json:"role"`
+	Chars int           `json:"chars"`
+}
+
+var auditMu sync.Mutex
+
+// Delete removes the messages at indexes, as numbered by Load, and returns
+// them. The removal is recorded in the audit log at auditPath before the
+// session is rewritten, so the log never misses a deletion that reached disk.
+func (s *Store) Delete(ctx context.Context, indexes []int, auditPath, actor string) ([]provider.ChatMessage, error) {
+	if len(indexes) == 0 {
+		return nil, errors.New("no messages to delete")
+	}
+	messages, err := s.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remove := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		if index < 0 || index >= len(messages) {
+			return nil, fmt.Errorf("message %d does not exist; the session has %d messages", index+1, len(messages))
+		}
+		remove[index] = true
+	}
+
+	deletion := Deletion{Time: time.Now().UTC(), Actor: actor, Session: s.Name()}
+	var kept, removed []provider.ChatMessage
+	for i, msg := range messages {
+		if !remove[i] {
+			kept = append(kept, msg)
+			continue
+		}
+		removed = append(removed, msg)
+		deletion.Messages = append(deletion.Messages, DeletedMessage{
+			Index: i,
+			Role:  msg.Role,
+			Chars: messageChars(msg),
+		})
+	}
+	if err := RecordDeletion(auditPath, deletion); err != nil {
+		return nil, err
+	}
+	if err := s.Rewrite(ctx, kept); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// LastExchange returns the indexes, as numbered by Load, of the last user
+// message in messages and everything after it.
+func LastExchange(messages []provider.ChatMessage) []int {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != provider.RoleUser || messages[i].Kind != "" {
+			continue
+		}
+		indexes := make([]int, 0, len(messages)-i)
+		for j := i; j < len(messages); j++ {
+			indexes = append(indexes, j)
+		}
+		return indexes
+	}
+	return nil
+}
+
+// RecordDeletion appends one deletion to the audit log at path.
+func RecordDeletion(path string, deletion Deletion) error {
+	encoded, err := json.Marshal(deletion)
+	if err != nil {
+		return fmt.Errorf("encode session deletion: %w", err)
+	}
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if err := store.AppendFile(path, append(encoded, '\n')); err != nil {
+		return fmt.Errorf("write session deletion log: %w", err)
+	}
+	return nil
+}
+
+func messageChars(msg provider.ChatMessage) int {
+	chars := utf8.RuneCountInString(msg.Content)
+	for _, call := range msg.ToolCalls {
+		chars += utf8.RuneCountInString(call.Arguments)
+	}
+	return chars
+}
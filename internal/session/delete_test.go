@@ -0,0 +1,82 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+func TestDeleteRemovesMessagesAndLogsWithoutContent(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := New(filepath.Join(dir, "sessions", "cli", "default.jsonl"))
+	if err := store.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "log in to the router"},
+		{Role: provider.RoleAssistant, Content: "what is the password?"},
+		{Role: provider.RoleUser, Content: "hunter2"},
+		{Role: provider.RoleAssistant, Content: "logged in"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	auditPath := filepath.Join(dir, "logs", "session_deletions.jsonl")
+	removed, err := store.Delete(ctx, []int{2}, auditPath, "claw session edit")
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(removed) != 1 || removed[0].Content != "hunter2" {
+		t.Fatalf("unexpected removed messages %#v", removed)
+	}
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(loaded) != 3 || loaded[2].Content != "logged in" {
+		t.Fatalf("unexpected session after delete %#v", loaded)
+	}
+
+	raw, err := os.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	if strings.Contains(string(raw), "hunter2") {
+		t.Fatalf("audit log leaked deleted content: %s", raw)
+	}
+	var deletion Deletion
+	if err := json.Unmarshal(raw, &deletion); err != nil {
+		t.Fatalf("decode audit log: %v", err)
+	}
+	if deletion.Actor != "claw session edit" || deletion.Session != "default" || len(deletion.Messages) != 1 {
+		t.Fatalf("unexpected deletion record %+v", deletion)
+	}
+	if got := deletion.Messages[0]; got.Index != 2 || got.Role != provider.RoleUser || got.Chars != 7 {
+		t.Fatalf("unexpected deleted message record %+v", got)
+	}
+
+	if _, err := store.Delete(ctx, []int{3}, auditPath, "claw session edit"); err == nil {
+		t.Fatalf("expected out-of-range delete to fail")
+	}
+}
+
+func TestLastExchange(t *testing.T) {
+	messages := []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "hi"},
+		{Role: provider.RoleAssistant, Content: "hello"},
+		{Role: provider.RoleUser, Content: "secret"},
+		{Role: provider.RoleAssistant, ToolCalls: []provider.ToolCall{{ID: "1", Name: "list_dir"}}},
+		{Role: provider.RoleTool, ToolCallID: "1", Content: "a.txt"},
+		{Role: provider.RoleAssistant, Content: "done"},
+	}
+	got := LastExchange(messages)
+	if len(got) != 4 || got[0] != 2 || got[3] != 5 {
+		t.Fatalf("unexpected last exchange %v", got)
+	}
+	if got := LastExchange(nil); got != nil {
+		t.Fatalf("expected no exchange, got %v", got)
+	}
+}
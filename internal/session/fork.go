@@ -0,0 +1,70 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var forkNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ValidForkName reports whether name can be used as a fork session name.
+func ValidForkName(name string) bool {
+	return forkNamePattern.MatchString(name)
+}
+
+// Fork copies the session into a new session file called name in the same
+// directory and returns a store for the copy. The copy keeps the redactor and
+// session settings and is titled after the original. Forking onto an existing
+// session fails.
+func (s *Store) Fork(ctx context.Context, name string) (*Store, error) {
+	if s == nil || s.path == "" {
+		return nil, errors.New("session path is required")
+	}
+	if !ValidForkName(name) {
+		return nil, fmt.Errorf("invalid fork name %q: use lowercase letters, digits, - and _", name)
+	}
+	path := filepath.Join(filepath.Dir(s.path), name+sessionFileExt)
+	if path == s.path {
+		return nil, fmt.Errorf("session %q is the current session", name)
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("session %q already exists", name)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("check fork session: %w", err)
+	}
+
+	messages, err := s.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fork := &Store{path: path, redact: s.redact}
+	if err := fork.Rewrite(ctx, messages); err != nil {
+		return nil, err
+	}
+	title, err := s.Title()
+	if err != nil {
+		return nil, err
+	}
+	title = strings.TrimSpace(title + " (fork " + name + ")")
+	if err := fork.SetTitle(title); err != nil {
+		return nil, err
+	}
+	meta, err := s.Meta()
+	if err != nil {
+		return nil, err
+	}
+	if err := fork.SetMeta(meta); err != nil {
+		return nil, err
+	}
+	return fork, nil
+}
+
+// Name returns the session file name without its extension.
+func (s *Store) Name() string {
+	return strings.TrimSuffix(filepath.Base(s.path), sessionFileExt)
+}
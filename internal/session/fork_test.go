@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+func TestForkCopiesSessionUnderNewName(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	main := New(filepath.Join(dir, "cli", "default.jsonl"))
+	if err := main.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "plan the migration"},
+		{Role: provider.RoleAssistant, Content: "use postgres"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := main.SetTitle("Migration plan"); err != nil {
+		t.Fatalf("set title: %v", err)
+	}
+	if err := main.SetMeta(Meta{DisabledPromptBlocks: []string{"facts"}}); err != nil {
+		t.Fatalf("set meta: %v", err)
+	}
+
+	fork, err := main.Fork(ctx, "try-sqlite")
+	if err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	if fork.Name() != "try-sqlite" {
+		t.Fatalf("unexpected fork name %q", fork.Name())
+	}
+	if err := fork.Append(ctx, []provider.ChatMessage{{Role: provider.RoleUser, Content: "what about sqlite?"}}); err != nil {
+		t.Fatalf("append fork: %v", err)
+	}
+
+	forked, err := fork.Load(ctx)
+	if err != nil {
+		t.Fatalf("load fork: %v", err)
+	}
+	original, err := main.Load(ctx)
+	if err != nil {
+		t.Fatalf("load main: %v", err)
+	}
+	if len(forked) != 3 || len(original) != 2 {
+		t.Fatalf("expected fork to diverge from main, got fork=%d main=%d", len(forked), len(original))
+	}
+	if title, _ := fork.Title(); title != "Migration plan (fork try-sqlite)" {
+		t.Fatalf("unexpected fork title %q", title)
+	}
+
+	if meta, err := fork.Meta(); err != nil || len(meta.DisabledPromptBlocks) != 1 {
+		t.Fatalf("expected fork to keep session settings, got %#v err=%v", meta, err)
+	}
+
+	if _, err := main.Fork(ctx, "try-sqlite"); err == nil {
+		t.Fatalf("expected forking onto an existing session to fail")
+	}
+	if _, err := main.Fork(ctx, "../escape"); err == nil {
+		t.Fatalf("expected invalid fork name to be rejected")
+	}
+}
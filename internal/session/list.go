@@ -0,0 +1,135 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+const (
+	sessionFileExt = ".jsonl"
+	titleFileExt   = ".title"
+)
+
+// Info describes one persisted session for listing.
+type Info struct {
+	// Name is the session path relative to the sessions directory, without
+	// extension (for example "cli/default").
+	Name      string
+	Path      string
+	Title     string
+	Turns     int
+	UpdatedAt time.Time
+}
+
+// Title returns the stored session title, or "" if none has been generated.
+func (s *Store) Title() (string, error) {
+	if s == nil || s.path == "" {
+		return "", errors.New("session path is required")
+	}
+	return readTitle(titlePath(s.path))
+}
+
+// SetTitle stores a short human-readable title for the session.
+func (s *Store) SetTitle(title string) error {
+	if s == nil || s.path == "" {
+		return errors.New("session path is required")
+	}
+	if s.redact != nil {
+		title = s.redact(title)
+	}
+	title = strings.Join(strings.Fields(title), " ")
+	if title == "" {
+		return errors.New("session title is required")
+	}
+	if err := store.WriteFile(titlePath(s.path), []byte(title+"\n")); err != nil {
+		return fmt.Errorf("write session title: %w", err)
+	}
+	return nil
+}
+
+// List returns every session under dir, most recently updated first.
+func List(ctx context.Context, dir string) ([]Info, error) {
+	var infos []Info
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != sessionFileExt {
+			return nil
+		}
+
+		fileInfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		title, err := readTitle(titlePath(path))
+		if err != nil {
+			return err
+		}
+		messages, err := New(path).Load(ctx)
+		if err != nil {
+			return err
+		}
+		infos = append(infos, Info{
+			Name:      filepath.ToSlash(strings.TrimSuffix(rel, sessionFileExt)),
+			Path:      path,
+			Title:     title,
+			Turns:     countUserTurns(messages),
+			UpdatedAt: fileInfo.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].UpdatedAt.After(infos[j].UpdatedAt)
+	})
+	return infos, nil
+}
+
+func titlePath(sessionPath string) string {
+	return strings.TrimSuffix(sessionPath, sessionFileExt) + titleFileExt
+}
+
+func readTitle(path string) (string, error) {
+	content, err := store.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read session title: %w", err)
+	}
+	return strings.TrimSpace(content), nil
+}
+
+func countUserTurns(messages []provider.ChatMessage) int {
+	turns := 0
+	for _, msg := range messages {
+		if msg.Role == provider.RoleUser && msg.Kind == "" {
+			turns++
+		}
+	}
+	return turns
+}
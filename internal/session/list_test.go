@@ -0,0 +1,78 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+)
+
+func TestListReturnsSessionsWithTitles(t *testing.T) {
+	dir := t.TempDir()
+	cli := New(filepath.Join(dir, "cli", "default.jsonl"))
+	telegram := New(filepath.Join(dir, "telegram", "default.jsonl"))
+
+	if err := cli.Append(context.Background(), []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "one"},
+		{Role: provider.RoleAssistant, Content: "ok"},
+		{Role: provider.RoleUser, Content: "two"},
+	}); err != nil {
+		t.Fatalf("append cli session: %v", err)
+	}
+	if err := telegram.Append(context.Background(), []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "hello"},
+	}); err != nil {
+		t.Fatalf("append telegram session: %v", err)
+	}
+	if err := cli.SetTitle("  Refactoring   the parser \n"); err != nil {
+		t.Fatalf("set title: %v", err)
+	}
+	older := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "telegram", "default.jsonl"), older, older); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	infos, err := List(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("list sessions: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(infos))
+	}
+	if infos[0].Name != "cli/default" || infos[0].Title != "Refactoring the parser" || infos[0].Turns != 2 {
+		t.Fatalf("unexpected first session: %+v", infos[0])
+	}
+	if infos[1].Name != "telegram/default" || infos[1].Title != "" || infos[1].Turns != 1 {
+		t.Fatalf("unexpected second session: %+v", infos[1])
+	}
+}
+
+func TestListMissingDirReturnsEmpty(t *testing.T) {
+	infos, err := List(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("list missing dir: %v", err)
+	}
+	if len(infos) != 0 {
+		t.Fatalf("expected no sessions, got %+v", infos)
+	}
+}
+
+func TestResetClearsTitle(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "cli", "default.jsonl"))
+	if err := store.SetTitle("Old topic"); err != nil {
+		t.Fatalf("set title: %v", err)
+	}
+	if err := store.Reset(context.Background()); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	title, err := store.Title()
+	if err != nil {
+		t.Fatalf("title: %v", err)
+	}
+	if title != "" {
+		t.Fatalf("expected title cleared, got %q", title)
+	}
+}
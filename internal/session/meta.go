@@ -0,0 +1,101 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+const metaFileExt = ".meta.json"
+
+// Meta holds per-session settings stored next to the session file.
+type Meta struct {
+	// DisabledPromptBlocks lists system-prompt blocks left out of this session.
+	DisabledPromptBlocks []string `json:"disabled_prompt_blocks,omitempty"`
+	// Coding adds a workspace snapshot to the system prompt of this session.
+	Coding bool `json:"coding,omitempty"`
+	// Project is the [projects] entry selected with /project.
+	Project string `json:"project,omitempty"`
+	// LastTurn reports how the most recent turn ran, for /last-turn.
+	LastTurn *TurnReport `json:"last_turn,omitempty"`
+}
+
+// TurnReport is a compact record of one agent turn: where its time and
+// money went.
+type TurnReport struct {
+	Started      time.Time `json:"started"`
+	DurationMS   int64     `json:"duration_ms"`
+	ModelCalls   int       `json:"model_calls"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	CostUSD      float64   `json:"cost_usd"`
+	// Tools lists the tool calls in the order they ran.
+	Tools []ToolReport `json:"tools,omitempty"`
+	// Approvals lists the prompts the user answered during the turn.
+	Approvals []ApprovalReport `json:"approvals,omitempty"`
+	// Error is why the turn failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// ToolReport is one tool call in a TurnReport. DurationMS includes any
+// time spent waiting for approval.
+type ToolReport struct {
+	Name       string `json:"name"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ApprovalReport is one approval prompt in a TurnReport. Decision is
+// "approved", "denied", or "failed" when no answer arrived.
+type ApprovalReport struct {
+	Tool     string `json:"tool"`
+	Decision string `json:"decision"`
+	WaitMS   int64  `json:"wait_ms"`
+}
+
+// Meta returns the stored session settings, or zero settings if none exist.
+func (s *Store) Meta() (Meta, error) {
+	if s == nil || s.path == "" {
+		return Meta{}, errors.New("session path is required")
+	}
+	return readMeta(metaPath(s.path))
+}
+
+// SetMeta stores per-session settings.
+func (s *Store) SetMeta(meta Meta) error {
+	if s == nil || s.path == "" {
+		return errors.New("session path is required")
+	}
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("marshal session metadata: %w", err)
+	}
+	if err := store.WriteFile(metaPath(s.path), append(encoded, '\n')); err != nil {
+		return fmt.Errorf("write session metadata: %w", err)
+	}
+	return nil
+}
+
+func metaPath(sessionPath string) string {
+	return strings.TrimSuffix(sessionPath, sessionFileExt) + metaFileExt
+}
+
+func readMeta(path string) (Meta, error) {
+	content, err := store.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Meta{}, nil
+	}
+	if err != nil {
+		return Meta{}, fmt.Errorf("read session metadata: %w", err)
+	}
+	var meta Meta
+	if err := json.Unmarshal([]byte(content), &meta); err != nil {
+		return Meta{}, fmt.Errorf("parse session metadata: %w", err)
+	}
+	return meta, nil
+}
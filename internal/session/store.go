@@ -17,8 +17,13 @@ import (
 
 // Store persists conversation history in a JSONL file.
 type Store struct {
-	path string
-	mu   sync.Mutex
+	path   string
+	mu     sync.Mutex
+	redact func(string) string
+	// stamp is the file version last read or written here; see
+	// ChangedElsewhere.
+	stamp   fileStamp
+	stamped bool
 }
 
 type record struct {
@@ -27,6 +32,7 @@ type record struct {
 	Content    string              `json:"content,omitempty"`
 	ToolCallID string              `json:"tool_call_id,omitempty"`
 	ToolCalls  []provider.ToolCall `json:"tool_calls,omitempty"`
+	Trust      string              `json:"trust,omitempty"`
 }
 
 // New creates a session store for one channel session file.
@@ -34,6 +40,12 @@ func New(path string) *Store {
 	return &Store{path: path}
 }
 
+// SetRedactor filters message content and tool arguments before they are
+// written, so the original text is never stored.
+func (s *Store) SetRedactor(redact func(string) string) {
+	s.redact = redact
+}
+
 // Load reads all valid JSONL records from disk into chat messages.
 // Malformed lines are skipped.
 func (s *Store) Load(ctx context.Context) ([]provider.ChatMessage, error) {
@@ -44,6 +56,10 @@ func (s *Store) Load(ctx context.Context) ([]provider.ChatMessage, error) {
 		return nil, errors.New("session path is required")
 	}
 
+	// Stamp before reading: a write in between then shows up as a change.
+	s.mu.Lock()
+	s.remember()
+	s.mu.Unlock()
 	content, err := store.ReadFile(s.path)
 	if errors.Is(err, os.ErrNotExist) {
 		return []provider.ChatMessage{}, nil
@@ -72,6 +88,7 @@ func (s *Store) Load(ctx context.Context) ([]provider.ChatMessage, error) {
 			Content:    rec.Content,
 			ToolCallID: rec.ToolCallID,
 			ToolCalls:  rec.ToolCalls,
+			Trust:      rec.Trust,
 		})
 	}
 	if err := scanner.Err(); err != nil {
@@ -100,14 +117,7 @@ func (s *Store) Append(ctx context.Context, messages []provider.ChatMessage) err
 		if err := ctx.Err(); err != nil {
 			return err
 		}
-		rec := record{
-			Kind:       msg.Kind,
-			Role:       msg.Role,
-			Content:    msg.Content,
-			ToolCallID: msg.ToolCallID,
-			ToolCalls:  msg.ToolCalls,
-		}
-		encoded, err := json.Marshal(rec)
+		encoded, err := json.Marshal(s.record(msg))
 		if err != nil {
 			return fmt.Errorf("marshal session record: %w", err)
 		}
@@ -118,6 +128,7 @@ func (s *Store) Append(ctx context.Context, messages []provider.ChatMessage) err
 	if err := store.AppendFile(s.path, []byte(b.String())); err != nil {
 		return fmt.Errorf("append session record: %w", err)
 	}
+	s.remember()
 	return nil
 }
 
@@ -138,14 +149,7 @@ func (s *Store) Rewrite(ctx context.Context, messages []provider.ChatMessage) er
 		if err := ctx.Err(); err != nil {
 			return err
 		}
-		rec := record{
-			Kind:       msg.Kind,
-			Role:       msg.Role,
-			Content:    msg.Content,
-			ToolCallID: msg.ToolCallID,
-			ToolCalls:  msg.ToolCalls,
-		}
-		encoded, err := json.Marshal(rec)
+		encoded, err := json.Marshal(s.record(msg))
 		if err != nil {
 			return fmt.Errorf("marshal session record: %w", err)
 		}
@@ -156,10 +160,44 @@ func (s *Store) Rewrite(ctx context.Context, messages []provider.ChatMessage) er
 	if err := store.WriteFile(s.path, []byte(b.String())); err != nil {
 		return fmt.Errorf("rewrite session record: %w", err)
 	}
+	s.remember()
 	return nil
 }
 
-// Reset clears all persisted session history.
+func (s *Store) record(msg provider.ChatMessage) record {
+	rec := record{
+		Kind:       msg.Kind,
+		Role:       msg.Role,
+		Content:    msg.Content,
+		ToolCallID: msg.ToolCallID,
+		ToolCalls:  msg.ToolCalls,
+		Trust:      msg.Trust,
+	}
+	if s.redact == nil {
+		return rec
+	}
+	rec.Content = s.redact(rec.Content)
+	if len(rec.ToolCalls) > 0 {
+		rec.ToolCalls = make([]provider.ToolCall, len(msg.ToolCalls))
+		for i, call := range msg.ToolCalls {
+			call.Arguments = s.redact(call.Arguments)
+			rec.ToolCalls[i] = call
+		}
+	}
+	return rec
+}
+
+// Reset clears all persisted session history, the session title, and
+// per-session settings.
 func (s *Store) Reset(ctx context.Context) error {
-	return s.Rewrite(ctx, nil)
+	if err := s.Rewrite(ctx, nil); err != nil {
+		return err
+	}
+	if err := store.RemoveFile(titlePath(s.path)); err != nil {
+		return fmt.Errorf("remove session title: %w", err)
+	}
+	if err := store.RemoveFile(metaPath(s.path)); err != nil {
+		return fmt.Errorf("remove session metadata: %w", err)
+	}
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/neoclaw-ai/neoclaw/internal/provider"
@@ -31,6 +32,7 @@ func TestStoreAppendLoadRoundTrip(t *testing.T) {
 			Role:       provider.RoleTool,
 			ToolCallID: "1",
 			Content:    "file1\nfile2",
+			Trust:      "workspace",
 		},
 	}
 
@@ -51,6 +53,51 @@ func TestStoreAppendLoadRoundTrip(t *testing.T) {
 	if got[2].ToolCalls[0].Name != "list_dir" {
 		t.Fatalf("expected tool call to round-trip, got %#v", got[2].ToolCalls)
 	}
+	if got[3].Trust != "workspace" {
+		t.Fatalf("expected trust to round-trip, got %q", got[3].Trust)
+	}
+}
+
+func TestStoreRedactsBeforeWriting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions", "cli", "default.jsonl")
+	store := New(path)
+	store.SetRedactor(func(text string) string {
+		return strings.ReplaceAll(text, "555-123-4567", "[redacted phone]")
+	})
+
+	input := []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "call me at 555-123-4567"},
+		{
+			Role: provider.RoleAssistant,
+			ToolCalls: []provider.ToolCall{
+				{ID: "1", Name: "memory_append", Arguments: `{"text":"phone 555-123-4567"}`},
+			},
+		},
+	}
+	if err := store.Append(context.Background(), input); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := store.SetTitle("Calling 555-123-4567"); err != nil {
+		t.Fatalf("set title: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read session: %v", err)
+	}
+	if strings.Contains(string(raw), "555-123-4567") {
+		t.Fatalf("expected phone number to be redacted on disk, got %q", raw)
+	}
+	if input[0].Content != "call me at 555-123-4567" || input[1].ToolCalls[0].Arguments != `{"text":"phone 555-123-4567"}` {
+		t.Fatalf("expected caller messages to be left unchanged, got %#v", input)
+	}
+	title, err := store.Title()
+	if err != nil {
+		t.Fatalf("title: %v", err)
+	}
+	if title != "Calling [redacted phone]" {
+		t.Fatalf("expected redacted title, got %q", title)
+	}
 }
 
 func TestStoreLoadSkipsMalformedLines(t *testing.T) {
@@ -81,6 +128,9 @@ func TestStoreResetClearsFile(t *testing.T) {
 	}); err != nil {
 		t.Fatalf("append: %v", err)
 	}
+	if err := store.SetMeta(Meta{DisabledPromptBlocks: []string{"profile"}}); err != nil {
+		t.Fatalf("set meta: %v", err)
+	}
 
 	if err := store.Reset(context.Background()); err != nil {
 		t.Fatalf("reset: %v", err)
@@ -92,4 +142,7 @@ func TestStoreResetClearsFile(t *testing.T) {
 	if len(got) != 0 {
 		t.Fatalf("expected empty history, got %#v", got)
 	}
+	if meta, err := store.Meta(); err != nil || len(meta.DisabledPromptBlocks) != 0 {
+		t.Fatalf("expected reset to clear session settings, got %#v err=%v", meta, err)
+	}
 }
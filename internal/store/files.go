@@ -4,6 +4,7 @@ package store
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +16,37 @@ var (
 	pathLocks   = map[string]*sync.Mutex{}
 )
 
+// Backend performs the file operations behind ReadFile, WriteFile,
+// AppendFile, and RemoveFile. Paths are cleaned before they reach it.
+type Backend interface {
+	ReadFile(path string) ([]byte, error)
+	WriteFile(path string, data []byte) error
+	AppendFile(path string, data []byte) error
+	RemoveFile(path string) error
+}
+
+var (
+	backendMu sync.RWMutex
+	backend   Backend = Local{}
+)
+
+// SetBackend replaces the backend used by the package-level helpers. A nil
+// backend restores the local filesystem.
+func SetBackend(b Backend) {
+	if b == nil {
+		b = Local{}
+	}
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backend = b
+}
+
+func currentBackend() Backend {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend
+}
+
 // ReadFile reads a file and returns it as a string.
 func ReadFile(path string) (string, error) {
 	cleanPath, err := cleanPath(path)
@@ -22,7 +54,7 @@ func ReadFile(path string) (string, error) {
 		return "", err
 	}
 
-	raw, err := os.ReadFile(cleanPath)
+	raw, err := currentBackend().ReadFile(cleanPath)
 	if err != nil {
 		return "", err
 	}
@@ -35,19 +67,49 @@ func WriteFile(path string, data []byte) error {
 	if err != nil {
 		return err
 	}
+	return currentBackend().WriteFile(cleanPath, data)
+}
+
+// AppendFile appends bytes to a file, creating it if missing.
+func AppendFile(path string, data []byte) error {
+	cleanPath, err := cleanPath(path)
+	if err != nil {
+		return err
+	}
+	return currentBackend().AppendFile(cleanPath, data)
+}
+
+// RemoveFile deletes a file. A missing file is not an error.
+func RemoveFile(path string) error {
+	cleanPath, err := cleanPath(path)
+	if err != nil {
+		return err
+	}
+	return currentBackend().RemoveFile(cleanPath)
+}
+
+// Local is the default backend, reading and writing the local filesystem.
+type Local struct{}
 
-	lock := lockForPath(cleanPath)
+// ReadFile reads a file from disk.
+func (Local) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// WriteFile writes a temp file next to path and renames it into place.
+func (Local) WriteFile(path string, data []byte) error {
+	lock := lockForPath(path)
 	lock.Lock()
 	defer lock.Unlock()
 
-	dir := filepath.Dir(cleanPath)
+	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("create directory %s: %w", dir, err)
 	}
 
-	tempFile, err := os.CreateTemp(dir, filepath.Base(cleanPath)+".tmp-*")
+	tempFile, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
 	if err != nil {
-		return fmt.Errorf("create temp file for %s: %w", cleanPath, err)
+		return fmt.Errorf("create temp file for %s: %w", path, err)
 	}
 	tempPath := tempFile.Name()
 	defer func() {
@@ -56,46 +118,53 @@ func WriteFile(path string, data []byte) error {
 
 	if _, err := tempFile.Write(data); err != nil {
 		tempFile.Close()
-		return fmt.Errorf("write temp file for %s: %w", cleanPath, err)
+		return fmt.Errorf("write temp file for %s: %w", path, err)
 	}
 	if err := tempFile.Chmod(0o644); err != nil {
 		tempFile.Close()
-		return fmt.Errorf("chmod temp file for %s: %w", cleanPath, err)
+		return fmt.Errorf("chmod temp file for %s: %w", path, err)
 	}
 	if err := tempFile.Close(); err != nil {
-		return fmt.Errorf("close temp file for %s: %w", cleanPath, err)
+		return fmt.Errorf("close temp file for %s: %w", path, err)
 	}
-	if err := os.Rename(tempPath, cleanPath); err != nil {
-		return fmt.Errorf("replace file %s: %w", cleanPath, err)
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("replace file %s: %w", path, err)
 	}
 
 	return nil
 }
 
-// AppendFile appends bytes to a file, creating it if missing.
-func AppendFile(path string, data []byte) error {
-	cleanPath, err := cleanPath(path)
-	if err != nil {
-		return err
-	}
-
-	lock := lockForPath(cleanPath)
+// AppendFile appends to a file on disk, creating it if missing.
+func (Local) AppendFile(path string, data []byte) error {
+	lock := lockForPath(path)
 	lock.Lock()
 	defer lock.Unlock()
 
-	dir := filepath.Dir(cleanPath)
+	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("create directory %s: %w", dir, err)
 	}
 
-	f, err := os.OpenFile(cleanPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
-		return fmt.Errorf("open file %s for append: %w", cleanPath, err)
+		return fmt.Errorf("open file %s for append: %w", path, err)
 	}
 	defer f.Close()
 
 	if _, err := f.Write(data); err != nil {
-		return fmt.Errorf("append file %s: %w", cleanPath, err)
+		return fmt.Errorf("append file %s: %w", path, err)
+	}
+	return nil
+}
+
+// RemoveFile deletes a file from disk.
+func (Local) RemoveFile(path string) error {
+	lock := lockForPath(path)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove file %s: %w", path, err)
 	}
 	return nil
 }
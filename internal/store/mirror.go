@@ -0,0 +1,226 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+const (
+	defaultMirrorDelay = 2 * time.Second
+	mirrorRetryDelay   = 30 * time.Second
+	mirrorTimeout      = 2 * time.Minute
+)
+
+// Remote is an off-machine object store addressed by slash-separated keys.
+type Remote interface {
+	// Get returns an error wrapping fs.ErrNotExist when key is missing.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	// List returns every key in the store.
+	List(ctx context.Context) ([]string, error)
+}
+
+// Mirror is a Backend that keeps files on local disk, the working copy, and
+// copies every change under Root to a Remote in the background. Bursts of
+// writes to the same file are uploaded once.
+type Mirror struct {
+	Local  Backend
+	Remote Remote
+	// Root is the directory that is mirrored. Keys are paths relative to it.
+	Root string
+	// Skip lists directories under Root, relative to it, that stay local.
+	Skip []string
+	// Delay batches writes before uploading; zero means two seconds.
+	Delay time.Duration
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+	wake    chan struct{}
+
+	// uploadMu serializes flushes so an older copy never overwrites a newer one.
+	uploadMu sync.Mutex
+}
+
+// ReadFile reads the local copy.
+func (m *Mirror) ReadFile(path string) ([]byte, error) {
+	return m.local().ReadFile(path)
+}
+
+// WriteFile writes the local copy and queues the upload.
+func (m *Mirror) WriteFile(path string, data []byte) error {
+	if err := m.local().WriteFile(path, data); err != nil {
+		return err
+	}
+	m.queue(path)
+	return nil
+}
+
+// AppendFile appends to the local copy and queues the upload of the whole file.
+func (m *Mirror) AppendFile(path string, data []byte) error {
+	if err := m.local().AppendFile(path, data); err != nil {
+		return err
+	}
+	m.queue(path)
+	return nil
+}
+
+// RemoveFile deletes the local copy and queues the remote delete.
+func (m *Mirror) RemoveFile(path string) error {
+	if err := m.local().RemoveFile(path); err != nil {
+		return err
+	}
+	m.queue(path)
+	return nil
+}
+
+// Key returns the remote key for path, or false when path is outside Root
+// or in a skipped directory.
+func (m *Mirror) Key(path string) (string, bool) {
+	rel, err := filepath.Rel(m.Root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	for _, skip := range m.Skip {
+		skip = filepath.Clean(skip)
+		if rel == skip || strings.HasPrefix(rel, skip+string(filepath.Separator)) {
+			return "", false
+		}
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// Flush uploads or deletes every queued file now. Files that fail stay
+// queued for the next attempt.
+func (m *Mirror) Flush(ctx context.Context) error {
+	m.uploadMu.Lock()
+	defer m.uploadMu.Unlock()
+
+	m.mu.Lock()
+	paths := make([]string, 0, len(m.pending))
+	for path := range m.pending {
+		paths = append(paths, path)
+	}
+	m.pending = nil
+	m.mu.Unlock()
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		if err := m.sync(ctx, path); err != nil {
+			errs = append(errs, err)
+			m.mu.Lock()
+			if m.pending == nil {
+				m.pending = map[string]struct{}{}
+			}
+			m.pending[path] = struct{}{}
+			m.mu.Unlock()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sync makes the remote copy of path match the local one.
+func (m *Mirror) sync(ctx context.Context, path string) error {
+	key, ok := m.Key(path)
+	if !ok {
+		return nil
+	}
+	data, err := m.local().ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := m.Remote.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete %s from storage backend: %w", key, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s for storage backend: %w", path, err)
+	}
+	if err := m.Remote.Put(ctx, key, data); err != nil {
+		return fmt.Errorf("upload %s to storage backend: %w", key, err)
+	}
+	return nil
+}
+
+func (m *Mirror) queue(path string) {
+	if _, ok := m.Key(path); !ok {
+		return
+	}
+	m.mu.Lock()
+	if m.pending == nil {
+		m.pending = map[string]struct{}{}
+	}
+	m.pending[path] = struct{}{}
+	if m.wake == nil {
+		m.wake = make(chan struct{}, 1)
+		go m.run()
+	}
+	m.mu.Unlock()
+	m.signal()
+}
+
+func (m *Mirror) signal() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Mirror) run() {
+	delay := m.Delay
+	if delay <= 0 {
+		delay = defaultMirrorDelay
+	}
+	for range m.wake {
+		time.Sleep(delay)
+		ctx, cancel := context.WithTimeout(context.Background(), mirrorTimeout)
+		err := m.Flush(ctx)
+		cancel()
+		if err != nil {
+			logging.Logger().Warn("storage backend upload failed; retrying", "err", err, "retry_in", mirrorRetryDelay)
+			time.AfterFunc(mirrorRetryDelay, m.signal)
+		}
+	}
+}
+
+func (m *Mirror) local() Backend {
+	if m.Local == nil {
+		return Local{}
+	}
+	return m.Local
+}
+
+// QueueAll queues every file under Root that m mirrors, to upload state
+// that existed before the backend was configured. It returns how many files
+// were queued.
+func (m *Mirror) QueueAll() (int, error) {
+	count := 0
+	err := filepath.WalkDir(m.Root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Skip the temp files WriteFile renames into place.
+		if !entry.Type().IsRegular() || strings.Contains(entry.Name(), ".tmp-") {
+			return nil
+		}
+		if _, ok := m.Key(path); ok {
+			m.queue(path)
+			count++
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	return count, err
+}
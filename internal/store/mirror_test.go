@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryRemote struct {
+	mu    sync.Mutex
+	files map[string]string
+	fail  bool
+}
+
+func (r *memoryRemote) Get(_ context.Context, key string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, ok := r.files[key]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", key, fs.ErrNotExist)
+	}
+	return []byte(data), nil
+}
+
+func (r *memoryRemote) Put(_ context.Context, key string, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail {
+		return errors.New("remote unavailable")
+	}
+	if r.files == nil {
+		r.files = map[string]string{}
+	}
+	r.files[key] = string(data)
+	return nil
+}
+
+func (r *memoryRemote) Delete(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.files, key)
+	return nil
+}
+
+func (r *memoryRemote) List(_ context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := make([]string, 0, len(r.files))
+	for key := range r.files {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (r *memoryRemote) snapshot() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := map[string]string{}
+	for key, value := range r.files {
+		copied[key] = value
+	}
+	return copied
+}
+
+func TestMirrorCopiesChangesToRemote(t *testing.T) {
+	root := t.TempDir()
+	remote := &memoryRemote{}
+	mirror := &Mirror{Remote: remote, Root: root, Skip: []string{"agents/default/workspace"}, Delay: time.Hour}
+
+	daily := filepath.Join(root, "agents", "default", "memory", "daily", "2026-03-01.tsv")
+	if err := mirror.AppendFile(daily, []byte("one\n")); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := mirror.AppendFile(daily, []byte("two\n")); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	jobs := filepath.Join(root, "agents", "default", "jobs.json")
+	if err := mirror.WriteFile(jobs, []byte("[]\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := mirror.WriteFile(filepath.Join(root, "agents", "default", "workspace", "notes.md"), []byte("local only")); err != nil {
+		t.Fatalf("write workspace: %v", err)
+	}
+	if err := mirror.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	got := remote.snapshot()
+	if len(got) != 2 || got["agents/default/memory/daily/2026-03-01.tsv"] != "one\ntwo\n" || got["agents/default/jobs.json"] != "[]\n" {
+		t.Fatalf("unexpected remote files %#v", got)
+	}
+
+	if err := mirror.RemoveFile(daily); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := mirror.Flush(context.Background()); err != nil {
+		t.Fatalf("flush remove: %v", err)
+	}
+	if _, ok := remote.snapshot()["agents/default/memory/daily/2026-03-01.tsv"]; ok {
+		t.Fatalf("expected removed file deleted remotely")
+	}
+}
+
+func TestMirrorKeepsFailedUploadsQueued(t *testing.T) {
+	root := t.TempDir()
+	remote := &memoryRemote{fail: true}
+	mirror := &Mirror{Remote: remote, Root: root, Delay: time.Hour}
+	path := filepath.Join(root, "memory.tsv")
+	if err := mirror.WriteFile(path, []byte("fact\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if raw, err := os.ReadFile(path); err != nil || string(raw) != "fact\n" {
+		t.Fatalf("expected local write to succeed, got %q %v", raw, err)
+	}
+	if err := mirror.Flush(context.Background()); err == nil {
+		t.Fatalf("expected flush to report the failed upload")
+	}
+
+	remote.mu.Lock()
+	remote.fail = false
+	remote.mu.Unlock()
+	if err := mirror.Flush(context.Background()); err != nil {
+		t.Fatalf("retry flush: %v", err)
+	}
+	if remote.snapshot()["memory.tsv"] != "fact\n" {
+		t.Fatalf("expected retried upload, got %#v", remote.snapshot())
+	}
+}
+
+func TestMirrorQueueAllUploadsExistingState(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"claw.pid", "logs/costs.tsv", "agents/default/USER.md"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	remote := &memoryRemote{}
+	mirror := &Mirror{Remote: remote, Root: root, Skip: []string{"claw.pid"}, Delay: time.Hour}
+
+	count, err := mirror.QueueAll()
+	if err != nil || count != 2 {
+		t.Fatalf("expected 2 files queued, got %d %v", count, err)
+	}
+	if err := mirror.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := remote.snapshot(); len(got) != 2 || got["logs/costs.tsv"] != "logs/costs.tsv" {
+		t.Fatalf("unexpected remote files %#v", got)
+	}
+}
+
+func TestSetBackendRoutesPackageHelpers(t *testing.T) {
+	root := t.TempDir()
+	remote := &memoryRemote{}
+	SetBackend(&Mirror{Remote: remote, Root: root, Delay: time.Hour})
+	t.Cleanup(func() { SetBackend(nil) })
+
+	path := filepath.Join(root, "tasks.json")
+	if err := WriteFile(path, []byte("[]")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := ReadFile(path)
+	if err != nil || got != "[]" {
+		t.Fatalf("expected local read of written file, got %q %v", got, err)
+	}
+	if err := RemoveFile(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := RemoveFile(path); err != nil {
+		t.Fatalf("expected removing a missing file to succeed: %v", err)
+	}
+}
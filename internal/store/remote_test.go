@@ -0,0 +1,246 @@
+package store
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAWSSigningKey(t *testing.T) {
+	// Example from the AWS Signature Version 4 documentation.
+	key := awsSigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	if got := hex.EncodeToString(key); got != "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d" {
+		t.Fatalf("unexpected signing key %s", got)
+	}
+}
+
+func TestS3PutGetDelete(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20260301/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+			http.Error(w, "bad auth "+auth, http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("x-amz-content-sha256") != sha256Hex(body) {
+				http.Error(w, "bad payload hash", http.StatusBadRequest)
+				return
+			}
+			objects[r.URL.EscapedPath()] = string(body)
+		case http.MethodGet:
+			if r.URL.Query().Get("list-type") == "2" {
+				// One key per page, to exercise continuation tokens.
+				var keys []string
+				for key := range objects {
+					keys = append(keys, strings.TrimPrefix(key, "/backups/"))
+				}
+				sort.Strings(keys)
+				next := 0
+				if token := r.URL.Query().Get("continuation-token"); token != "" {
+					next, _ = strconv.Atoi(token)
+				}
+				io.WriteString(w, "<ListBucketResult>")
+				if next < len(keys) {
+					key, _ := url.PathUnescape(keys[next])
+					fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+				}
+				if next+1 < len(keys) {
+					fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%d</NextContinuationToken>", next+1)
+				}
+				io.WriteString(w, "</ListBucketResult>")
+				return
+			}
+			body, ok := objects[r.URL.EscapedPath()]
+			if !ok {
+				http.Error(w, "NoSuchKey", http.StatusNotFound)
+				return
+			}
+			io.WriteString(w, body)
+		case http.MethodDelete:
+			delete(objects, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	remote := &S3{
+		Endpoint:  server.URL,
+		Region:    "eu-west-1",
+		Bucket:    "backups",
+		Prefix:    "neoclaw/",
+		AccessKey: "AKID",
+		SecretKey: "secret",
+		Client:    server.Client(),
+		now:       func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) },
+	}
+	ctx := context.Background()
+	if err := remote.Put(ctx, "agents/default/sessions/cli/default session.jsonl", []byte("{}\n")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, ok := objects["/backups/neoclaw/agents/default/sessions/cli/default%20session.jsonl"]; !ok {
+		t.Fatalf("expected path-style encoded object key, got %#v", objects)
+	}
+	got, err := remote.Get(ctx, "agents/default/sessions/cli/default session.jsonl")
+	if err != nil || string(got) != "{}\n" {
+		t.Fatalf("get: %q %v", got, err)
+	}
+	if err := remote.Put(ctx, "agents/default/memory/memory.tsv", []byte("fact\n")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	keys, err := remote.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if strings.Join(keys, ",") != "agents/default/memory/memory.tsv,agents/default/sessions/cli/default session.jsonl" {
+		t.Fatalf("unexpected keys %q", keys)
+	}
+	if err := remote.Delete(ctx, "agents/default/sessions/cli/default session.jsonl"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := remote.Get(ctx, "agents/default/sessions/cli/default session.jsonl"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not-exist after delete, got %v", err)
+	}
+}
+
+func TestWebDAVPutCreatesParentCollections(t *testing.T) {
+	var mu sync.Mutex
+	collections := map[string]bool{"/dav/": true}
+	files := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "claw" || pass != "pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		path := r.URL.Path
+		parent := path[:strings.LastIndex(strings.TrimSuffix(path, "/"), "/")+1]
+		switch r.Method {
+		case "MKCOL":
+			if collections[path] {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if !collections[parent] {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			collections[path] = true
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPut:
+			if !collections[parent] {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			files[path] = string(body)
+			w.WriteHeader(http.StatusCreated)
+		case "PROPFIND":
+			if r.Header.Get("Depth") != "1" || !collections[path] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			io.WriteString(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:">`)
+			members := map[string]bool{path: true}
+			for member, isDir := range collections {
+				if strings.HasPrefix(member, path) && member != path && !strings.Contains(strings.TrimSuffix(member[len(path):], "/"), "/") {
+					members[member] = isDir
+				}
+			}
+			for member := range files {
+				if strings.HasPrefix(member, path) && !strings.Contains(member[len(path):], "/") {
+					members[member] = false
+				}
+			}
+			for member, isDir := range members {
+				kind := ""
+				if isDir {
+					kind = "<d:collection/>"
+				}
+				fmt.Fprintf(w, "<d:response><d:href>%s</d:href><d:propstat><d:prop><d:resourcetype>%s</d:resourcetype></d:prop></d:propstat></d:response>", member, kind)
+			}
+			io.WriteString(w, "</d:multistatus>")
+		case http.MethodGet:
+			body, ok := files[path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			io.WriteString(w, body)
+		}
+	}))
+	defer server.Close()
+
+	remote := &WebDAV{URL: server.URL + "/dav/", Username: "claw", Password: "pw", Client: server.Client()}
+	ctx := context.Background()
+	if err := remote.Put(ctx, "agents/default/memory/memory.tsv", []byte("fact\n")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if !collections["/dav/agents/default/memory/"] {
+		t.Fatalf("expected parent collections created, got %#v", collections)
+	}
+	got, err := remote.Get(ctx, "agents/default/memory/memory.tsv")
+	if err != nil || string(got) != "fact\n" {
+		t.Fatalf("get: %q %v", got, err)
+	}
+	if _, err := remote.Get(ctx, "missing.tsv"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not-exist, got %v", err)
+	}
+	if err := remote.Put(ctx, "config.toml", []byte("[llm]\n")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	keys, err := remote.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != "agents/default/memory/memory.tsv,config.toml" {
+		t.Fatalf("unexpected keys %q", keys)
+	}
+}
+
+func TestRestoreWritesKeysUnderRoot(t *testing.T) {
+	replica := t.TempDir()
+	source := Dir{Path: replica}
+	ctx := context.Background()
+	if err := source.Put(ctx, "agents/default/memory/memory.tsv", []byte("fact\n")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	keys, err := source.List(ctx)
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("list: %q %v", keys, err)
+	}
+
+	root := t.TempDir()
+	if err := Restore(ctx, source, root, keys); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	got, err := ReadFile(filepath.Join(root, "agents", "default", "memory", "memory.tsv"))
+	if err != nil || got != "fact\n" {
+		t.Fatalf("restored file: %q %v", got, err)
+	}
+
+	if err := Restore(ctx, source, root, []string{"../outside.tsv"}); err == nil {
+		t.Fatal("expected error for key outside root")
+	}
+}
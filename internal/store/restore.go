@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir is a Remote backed by a local directory, such as a mounted disk or a
+// copy of a replica. Keys are paths relative to Path.
+type Dir struct {
+	Path string
+}
+
+// Get reads a file under Path.
+func (d Dir) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := keyPath(d.Path, key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// Put writes a file under Path.
+func (d Dir) Put(_ context.Context, key string, data []byte) error {
+	path, err := keyPath(d.Path, key)
+	if err != nil {
+		return err
+	}
+	return Local{}.WriteFile(path, data)
+}
+
+// Delete removes a file under Path. Deleting a missing file succeeds.
+func (d Dir) Delete(_ context.Context, key string) error {
+	path, err := keyPath(d.Path, key)
+	if err != nil {
+		return err
+	}
+	return Local{}.RemoveFile(path)
+}
+
+// List returns every regular file under Path.
+func (d Dir) List(_ context.Context) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(d.Path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() || strings.Contains(entry.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(d.Path, path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", d.Path, err)
+	}
+	return keys, nil
+}
+
+// Restore downloads each key from remote into root, replacing local files.
+// It writes the local filesystem directly so a configured Mirror does not
+// upload the files straight back.
+func Restore(ctx context.Context, remote Remote, root string, keys []string) error {
+	for _, key := range keys {
+		path, err := keyPath(root, key)
+		if err != nil {
+			return err
+		}
+		data, err := remote.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("download %s: %w", key, err)
+		}
+		if err := (Local{}).WriteFile(path, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyPath resolves key under root, refusing keys that would escape it.
+func keyPath(root, key string) (string, error) {
+	path := filepath.Join(root, filepath.FromSlash(key))
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return path, nil
+}
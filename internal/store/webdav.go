@@ -0,0 +1,204 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WebDAV is a Remote for a WebDAV collection, such as Nextcloud or a
+// storage box. Missing parent collections are created on upload.
+type WebDAV struct {
+	// URL is the collection files are stored under.
+	URL      string
+	Username string
+	Password string
+	Client   *http.Client
+}
+
+// Get downloads a file.
+func (w *WebDAV) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := w.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("webdav file %s: %w", key, fs.ErrNotExist)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, webdavError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Put uploads a file, creating parent collections when the server reports
+// them missing.
+func (w *WebDAV) Put(ctx context.Context, key string, data []byte) error {
+	status, err := w.put(ctx, key, data)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusConflict {
+		return nil
+	}
+	if err := w.makeParents(ctx, key); err != nil {
+		return err
+	}
+	status, err = w.put(ctx, key, data)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusConflict {
+		return fmt.Errorf("webdav PUT %s: parent collection missing", key)
+	}
+	return nil
+}
+
+// Delete removes a file. Deleting a missing file succeeds.
+func (w *WebDAV) Delete(ctx context.Context, key string) error {
+	resp, err := w.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return webdavError(resp)
+	}
+	return nil
+}
+
+// List returns every file under URL, walking collections one level at a
+// time since Depth: infinity is often disabled.
+func (w *WebDAV) List(ctx context.Context) ([]string, error) {
+	base, err := url.Parse(strings.TrimRight(w.URL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("parse webdav url: %w", err)
+	}
+	var keys []string
+	dirs := []string{""}
+	for len(dirs) > 0 {
+		dir := dirs[0]
+		dirs = dirs[1:]
+		entries, err := w.propfind(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			href, err := url.Parse(entry.Href)
+			if err != nil {
+				return nil, fmt.Errorf("parse webdav href %q: %w", entry.Href, err)
+			}
+			key := strings.TrimPrefix(href.Path, base.Path)
+			if key == href.Path || strings.TrimSuffix(key, "/") == strings.TrimSuffix(dir, "/") {
+				// Outside the collection, or the listed collection itself.
+				continue
+			}
+			if entry.Collection != nil {
+				dirs = append(dirs, strings.TrimSuffix(key, "/")+"/")
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+type webdavEntry struct {
+	Href       string    `xml:"DAV: href"`
+	Collection *struct{} `xml:"DAV: propstat>prop>resourcetype>collection"`
+}
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?><propfind xmlns="DAV:"><prop><resourcetype/></prop></propfind>`
+
+// propfind lists the immediate members of the collection at dir.
+func (w *WebDAV) propfind(ctx context.Context, dir string) ([]webdavEntry, error) {
+	resp, err := w.do(ctx, "PROPFIND", dir, []byte(propfindBody), "Depth", "1")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, webdavError(resp)
+	}
+	var result struct {
+		Responses []webdavEntry `xml:"DAV: response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode webdav listing of %q: %w", dir, err)
+	}
+	return result.Responses, nil
+}
+
+// put returns the status code so Put can retry after a 409 Conflict.
+func (w *WebDAV) put(ctx context.Context, key string, data []byte) (int, error) {
+	resp, err := w.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict || resp.StatusCode/100 == 2 {
+		return resp.StatusCode, nil
+	}
+	return 0, webdavError(resp)
+}
+
+// makeParents creates each collection above key, top down. Collections that
+// already exist answer 405 Method Not Allowed.
+func (w *WebDAV) makeParents(ctx context.Context, key string) error {
+	parts := strings.Split(strings.Trim(key, "/"), "/")
+	for i := 1; i < len(parts); i++ {
+		dir := strings.Join(parts[:i], "/") + "/"
+		resp, err := w.do(ctx, "MKCOL", dir, nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusMethodNotAllowed {
+			return webdavError(resp)
+		}
+	}
+	return nil
+}
+
+// do sends a request for key. headers are name, value pairs.
+func (w *WebDAV) do(ctx context.Context, method, key string, body []byte, headers ...string) (*http.Response, error) {
+	var escaped []string
+	for _, part := range strings.Split(key, "/") {
+		escaped = append(escaped, url.PathEscape(part))
+	}
+	target := strings.TrimRight(w.URL, "/") + "/" + strings.Join(escaped, "/")
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build webdav request: %w", err)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	if w.Username != "" || w.Password != "" {
+		req.SetBasicAuth(w.Username, w.Password)
+	}
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webdav %s %s: %w", method, key, err)
+	}
+	return resp, nil
+}
+
+func webdavError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("webdav %s %s: status %d: %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,205 @@
+package table
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/calc"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Result is the output of a query.
+type Result struct {
+	Columns []string
+	Rows    [][]Value
+}
+
+// Query loads the table into an in-memory SQLite database and runs one
+// read-only SELECT over it.
+func (t *Table) Query(query string) (*Result, error) {
+	query, err := singleStatement(query)
+	if err != nil {
+		return nil, err
+	}
+	words := strings.Fields(strings.ToUpper(query))
+	if len(words) == 0 || (words[0] != "SELECT" && words[0] != "WITH" && words[0] != "VALUES") {
+		return nil, errors.New("only SELECT queries are supported")
+	}
+
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+	// Each connection to :memory: is its own database, so everything runs
+	// on one. The connection cannot attach files or write once loaded.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	defer conn.Close()
+	if err := t.insert(ctx, conn); err != nil {
+		return nil, err
+	}
+	if _, err := sqlite.Limit(conn, sqlite3.SQLITE_LIMIT_ATTACHED, 0); err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, queryError(err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, queryError(err)
+	}
+	result := &Result{Columns: columns}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, queryError(err)
+		}
+		row := make([]Value, len(columns))
+		for i, v := range values {
+			row[i] = fromSQLite(v)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+	return result, nil
+}
+
+// singleStatement trims a trailing semicolon and rejects input holding more
+// than one statement, which the driver would otherwise run in turn.
+func singleStatement(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; c {
+		case '\'', '"', '`':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				return "", errors.New("unterminated quote")
+			}
+			i += end + 1
+		case '[':
+			end := strings.IndexByte(query[i+1:], ']')
+			if end < 0 {
+				return "", errors.New("unterminated quote")
+			}
+			i += end + 1
+		case '-':
+			if strings.HasPrefix(query[i:], "--") {
+				end := strings.IndexByte(query[i:], '\n')
+				if end < 0 {
+					return query[:i], nil
+				}
+				i += end
+			}
+		case '/':
+			if strings.HasPrefix(query[i:], "/*") {
+				end := strings.Index(query[i+2:], "*/")
+				if end < 0 {
+					return query[:i], nil
+				}
+				i += end + 3
+			}
+		case ';':
+			if rest, err := singleStatement(query[i+1:]); err != nil || strings.TrimSpace(rest) != "" {
+				return "", errors.New("only one statement is allowed")
+			}
+			return query[:i], nil
+		}
+	}
+	return query, nil
+}
+
+// insert creates the table and copies the rows into it. Columns have no
+// declared type, so numbers stay numbers and text stays text.
+func (t *Table) insert(ctx context.Context, conn *sql.Conn) error {
+	quoted := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		quoted[i] = `"` + strings.ReplaceAll(column, `"`, `""`) + `"`
+	}
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", Name, strings.Join(quoted, ", "))); err != nil {
+		return fmt.Errorf("create table: %w", err)
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("load rows: %w", err)
+	}
+	defer tx.Rollback()
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(t.Columns)), ", ")
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s VALUES (%s)", Name, placeholders))
+	if err != nil {
+		return fmt.Errorf("load rows: %w", err)
+	}
+	defer stmt.Close()
+	args := make([]any, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, v := range row {
+			args[i] = v
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return fmt.Errorf("load rows: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("load rows: %w", err)
+	}
+	return nil
+}
+
+// fromSQLite converts a scanned SQLite value to a Value.
+func fromSQLite(v any) Value {
+	switch v := v.(type) {
+	case int64:
+		return float64(v)
+	case []byte:
+		return string(v)
+	case bool:
+		if v {
+			return 1.0
+		}
+		return 0.0
+	}
+	return v
+}
+
+// queryError drops the driver's code suffix and points at the table name
+// when a query names another table.
+func queryError(err error) error {
+	message := err.Error()
+	if i := strings.LastIndex(message, " ("); i > 0 && strings.HasSuffix(message, ")") {
+		message = message[:i]
+	}
+	if strings.Contains(message, "no such table") {
+		message += fmt.Sprintf("; the table is named %s", Name)
+	}
+	return errors.New(message)
+}
+
+// Text renders a value as text: numbers without float noise, NULL as "".
+func Text(v Value) string {
+	switch v := v.(type) {
+	case float64:
+		return calc.Format(v)
+	case string:
+		return v
+	}
+	return ""
+}
@@ -0,0 +1,216 @@
+// Package table loads CSV, TSV, and XLSX files into an in-memory SQLite database and answers read-only SELECT queries over them.
+package table
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Name is the table name queries select FROM.
+const Name = "data"
+
+// MaxRows caps how many data rows a file may have.
+const MaxRows = 1_000_000
+
+// Value is a cell: nil for an empty cell (NULL), float64, or string.
+type Value any
+
+// Table is a header row and the data rows below it.
+type Table struct {
+	Columns []string
+	Rows    [][]Value
+}
+
+// Load parses a .csv, .tsv, or .xlsx file's content. For XLSX, sheet picks
+// a worksheet by name; empty means the first one.
+func Load(name string, content []byte, sheet string) (*Table, error) {
+	var records [][]string
+	var err error
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		records, err = readDelimited(content, ',')
+	case ".tsv", ".tab":
+		records, err = readDelimited(content, '\t')
+	case ".xlsx":
+		records, err = readXLSX(content, sheet)
+	default:
+		return nil, fmt.Errorf("unsupported file type %q; use .csv, .tsv, or .xlsx", filepath.Ext(name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("file has no header row")
+	}
+	if len(records)-1 > MaxRows {
+		return nil, fmt.Errorf("file has more than %d rows", MaxRows)
+	}
+	return fromRecords(records), nil
+}
+
+func readDelimited(content []byte, comma rune) ([][]string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(content) {
+		return nil, errors.New("file is not valid UTF-8 text")
+	}
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse: %w", err)
+		}
+		records = append(records, record)
+	}
+}
+
+// fromRecords names columns from the header, filling blanks and making
+// duplicates unique, and converts cells to values.
+func fromRecords(records [][]string) *Table {
+	width := 0
+	for _, record := range records {
+		width = max(width, len(record))
+	}
+	t := &Table{Columns: make([]string, width)}
+	seen := map[string]int{}
+	for i := range t.Columns {
+		name := ""
+		if i < len(records[0]) {
+			name = strings.TrimSpace(records[0][i])
+		}
+		if name == "" {
+			name = fmt.Sprintf("column%d", i+1)
+		}
+		key := strings.ToLower(name)
+		if n := seen[key]; n > 0 {
+			name = fmt.Sprintf("%s_%d", name, n+1)
+		}
+		seen[key]++
+		t.Columns[i] = name
+	}
+	for _, record := range records[1:] {
+		row := make([]Value, width)
+		for i := range row {
+			if i < len(record) {
+				row[i] = parseCell(record[i])
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// thousands matches numbers written with comma thousands separators.
+var thousands = regexp.MustCompile(`^-?\d{1,3}(,\d{3})+(\.\d+)?$`)
+
+// parseCell reads numbers, including exports' "$1,234.50" and "(12.00)"
+// styles, and keeps everything else as text.
+func parseCell(raw string) Value {
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		return nil
+	}
+	if n, ok := parseNumber(text); ok {
+		return n
+	}
+	number := text
+	negative := false
+	if strings.HasPrefix(number, "(") && strings.HasSuffix(number, ")") {
+		negative = true
+		number = number[1 : len(number)-1]
+	}
+	if strings.HasPrefix(number, "-") {
+		negative = !negative
+		number = number[1:]
+	}
+	for _, symbol := range []string{"$", "€", "£"} {
+		number = strings.TrimPrefix(number, symbol)
+	}
+	if thousands.MatchString(number) {
+		number = strings.ReplaceAll(number, ",", "")
+	}
+	n, ok := parseNumber(number)
+	if !ok {
+		return text
+	}
+	if negative {
+		n = -n
+	}
+	return n
+}
+
+func parseNumber(text string) (float64, bool) {
+	// Leading zeros mark identifiers such as ZIP codes, not numbers.
+	if len(text) > 1 && text[0] == '0' && text[1] != '.' {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil || strings.ContainsAny(text, "xXnNiI_") {
+		return 0, false
+	}
+	return n, true
+}
+
+// Describe lists the columns with the kind of values each holds.
+func (t *Table) Describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "table %s: %d rows\n", Name, len(t.Rows))
+	for i, column := range t.Columns {
+		numbers, texts := 0, 0
+		for _, row := range t.Rows {
+			switch row[i].(type) {
+			case float64:
+				numbers++
+			case string:
+				texts++
+			}
+		}
+		kind := "empty"
+		switch {
+		case numbers > 0 && texts == 0:
+			kind = "number"
+		case texts > 0 && numbers == 0:
+			kind = "text"
+		case texts > 0:
+			kind = "mixed"
+		}
+		fmt.Fprintf(&b, "  %s (%s)\n", quoteIdent(column), kind)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// keywords cannot be used as bare column names; double-quote them instead.
+var keywords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "FROM": true, "WHERE": true, "GROUP": true,
+	"BY": true, "HAVING": true, "ORDER": true, "ASC": true, "DESC": true,
+	"LIMIT": true, "OFFSET": true, "AS": true, "AND": true, "OR": true,
+	"NOT": true, "LIKE": true, "GLOB": true, "IS": true, "NULL": true, "IN": true,
+	"BETWEEN": true, "CASE": true, "WHEN": true, "THEN": true, "ELSE": true,
+	"END": true, "CAST": true, "JOIN": true, "ON": true, "USING": true,
+	"UNION": true, "EXCEPT": true, "INTERSECT": true, "WITH": true,
+	"VALUES": true, "EXISTS": true, "TABLE": true, "INDEX": true,
+}
+
+var plainIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// quoteIdent double-quotes a column name that would not parse bare.
+func quoteIdent(name string) string {
+	if plainIdent.MatchString(name) && !keywords[strings.ToUpper(name)] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
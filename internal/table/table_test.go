@@ -0,0 +1,180 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const expensesCSV = `Date,Merchant,Category,Amount
+2024-01-03,Fresh Market,Groceries,"$1,020.50"
+2024-01-15,Corner Shop,Groceries,30
+2024-01-20,City Power,Utilities,80
+2024-02-02,Fresh Market,Groceries,45.25
+2024-02-10,Cafe,Dining,(12.00)
+2024-02-11,Cafe,Dining,
+`
+
+func loadExpenses(t *testing.T) *Table {
+	t.Helper()
+	data, err := Load("expenses.csv", []byte(expensesCSV), "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	return data
+}
+
+func queryRows(t *testing.T, data *Table, sql string) [][]string {
+	t.Helper()
+	result, err := data.Query(sql)
+	if err != nil {
+		t.Fatalf("query %q: %v", sql, err)
+	}
+	rows := make([][]string, len(result.Rows))
+	for i, row := range result.Rows {
+		for _, v := range row {
+			if v == nil {
+				rows[i] = append(rows[i], "NULL")
+				continue
+			}
+			rows[i] = append(rows[i], Text(v))
+		}
+	}
+	return rows
+}
+
+func TestLoadParsesNumbersAndHeaders(t *testing.T) {
+	data, err := Load("x.tsv", []byte("id\t\tid\nA1\t007\t1,234\n"), "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if strings.Join(data.Columns, ",") != "id,column2,id_2" {
+		t.Fatalf("unexpected columns %v", data.Columns)
+	}
+	if data.Rows[0][1] != "007" || data.Rows[0][2] != 1234.0 {
+		t.Fatalf("unexpected row %#v", data.Rows[0])
+	}
+
+	expenses := loadExpenses(t)
+	if expenses.Rows[0][3] != 1020.5 || expenses.Rows[4][3] != -12.0 || expenses.Rows[5][3] != nil {
+		t.Fatalf("unexpected amounts %#v %#v %#v", expenses.Rows[0][3], expenses.Rows[4][3], expenses.Rows[5][3])
+	}
+}
+
+func TestQueryGroupsAndOrders(t *testing.T) {
+	data := loadExpenses(t)
+	got := queryRows(t, data, `SELECT substr(Date, 1, 7) AS month, round(avg(Amount), 2) AS average, count(*)
+		FROM data WHERE Category = 'Groceries' GROUP BY month ORDER BY month DESC`)
+	want := [][]string{{"2024-02", "45.25", "1"}, {"2024-01", "525.25", "2"}}
+	if strings.Join(flatten(got), ",") != strings.Join(flatten(want), ",") {
+		t.Fatalf("unexpected rows %v", got)
+	}
+
+	got = queryRows(t, data, `select Category, sum(Amount) total_spent from data group by 1 having total_spent > 50 order by 2`)
+	if strings.Join(flatten(got), ",") != "Utilities,80,Groceries,1095.75" {
+		t.Fatalf("unexpected rows %v", got)
+	}
+
+	got = queryRows(t, data, `SELECT count(*), count(Amount), count(DISTINCT Merchant), min(Date), max(Amount) FROM data`)
+	if strings.Join(flatten(got), ",") != "6,5,4,2024-01-03,1020.5" {
+		t.Fatalf("unexpected aggregates %v", got)
+	}
+}
+
+func TestQueryFiltersAndExpressions(t *testing.T) {
+	data := loadExpenses(t)
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{`SELECT Merchant FROM data WHERE Merchant LIKE '%market' AND Amount BETWEEN 40 AND 50`, "Fresh Market"},
+		{`SELECT DISTINCT Category FROM data WHERE Category NOT IN ('Groceries') ORDER BY Category`, "Dining,Utilities"},
+		{`SELECT Merchant FROM data WHERE Amount IS NULL`, "Cafe"},
+		{`SELECT CASE WHEN Amount < 0 THEN 'refund' ELSE 'charge' END FROM data WHERE Category = 'Dining' LIMIT 1`, "refund"},
+		{`SELECT strftime('%Y/%m', Date) || ' ' || upper(Merchant) FROM data ORDER BY Amount DESC LIMIT 1 OFFSET 1`, "2024/01 CITY POWER"},
+		{`SELECT coalesce(Amount, 0) * 2 FROM "data" WHERE Date = '2024-02-11'`, "0"},
+		{`SELECT "Amount" / 0 FROM data LIMIT 1`, "NULL"},
+	}
+	for _, tt := range tests {
+		got := strings.Join(flatten(queryRows(t, data, tt.sql)), ",")
+		if got != tt.want {
+			t.Errorf("query %q = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestQueryMatchesSQLite(t *testing.T) {
+	data := loadExpenses(t)
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{`SELECT round(Amount, 400) FROM data WHERE Merchant = 'City Power'`, "80"},
+		{`SELECT substr(Merchant, -100, 2), substr(Merchant, 0, 2) FROM data LIMIT 1`, ",F"},
+		{`SELECT max(Amount, 100) FROM data WHERE Category = 'Utilities'`, "100"},
+		{`SELECT e.Merchant FROM data AS e WHERE e.Amount > 1000`, "Fresh Market"},
+		{`SELECT a.Date, b.Date FROM data a JOIN data b ON a.Merchant = b.Merchant AND a.Date < b.Date WHERE a.Category = 'Dining'`, "2024-02-10,2024-02-11"},
+	}
+	for _, tt := range tests {
+		got := strings.Join(flatten(queryRows(t, data, tt.sql)), ",")
+		if got != tt.want {
+			t.Errorf("query %q = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	data := loadExpenses(t)
+	for sql, want := range map[string]string{
+		"DELETE FROM data":                            "only SELECT",
+		"WITH x AS (SELECT 1) DELETE FROM data":       "readonly",
+		"SELECT * FROM expenses":                      "table is named data",
+		"SELECT Missing FROM data":                    "no such column: Missing",
+		"SELECT Date FROM data WHERE sum(Amount) > 1": "misuse of aggregate",
+		"SELECT nope(Date) FROM data":                 "no such function",
+		"SELECT 'open FROM data":                      "unterminated",
+		"SELECT Date FROM data; DROP TABLE data":      "only one statement",
+		"SELECT 1; ATTACH 'other.db' AS other":        "only one statement",
+	} {
+		if _, err := data.Query(sql); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("query %q: expected error containing %q, got %v", sql, want, err)
+		}
+	}
+}
+
+func TestLoadXLSX(t *testing.T) {
+	book := excelize.NewFile()
+	book.SetSheetName("Sheet1", "Summary")
+	book.SetCellStr("Summary", "A1", "unused")
+	book.NewSheet("Budget")
+	book.SetCellStr("Budget", "A1", "Item")
+	book.SetCellStr("Budget", "C1", "Cost")
+	book.SetCellStr("Budget", "A2", "Rent")
+	book.SetCellInt("Budget", "C2", 1200)
+	book.SetCellFormula("Budget", "C2", "1000+200")
+	var buf bytes.Buffer
+	if err := book.Write(&buf); err != nil {
+		t.Fatalf("write workbook: %v", err)
+	}
+
+	data, err := Load("budget.xlsx", buf.Bytes(), "budget")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if strings.Join(data.Columns, ",") != "Item,column2,Cost" || data.Rows[0][0] != "Rent" || data.Rows[0][2] != 1200.0 {
+		t.Fatalf("unexpected table %#v", data)
+	}
+	if _, err := Load("budget.xlsx", buf.Bytes(), "Missing"); err == nil || !strings.Contains(err.Error(), "sheets: Summary, Budget") {
+		t.Fatalf("expected missing sheet error, got %v", err)
+	}
+}
+
+func flatten(rows [][]string) []string {
+	var out []string
+	for _, row := range rows {
+		out = append(out, row...)
+	}
+	return out
+}
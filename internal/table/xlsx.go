@@ -0,0 +1,46 @@
+package table
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxXLSXUnzipBytes caps how much a workbook may expand to when unzipped,
+// so a small file cannot exhaust memory.
+const maxXLSXUnzipBytes = 256 << 20
+
+// readXLSX returns the cell text of one worksheet. Formulas contribute
+// their cached values; dates stay as Excel serial numbers.
+func readXLSX(content []byte, sheetName string) ([][]string, error) {
+	book, err := excelize.OpenReader(bytes.NewReader(content), excelize.Options{UnzipSizeLimit: maxXLSXUnzipBytes})
+	if err != nil {
+		return nil, fmt.Errorf("open xlsx: %w", err)
+	}
+	defer book.Close()
+
+	sheets := book.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("workbook has no sheets")
+	}
+	sheet := sheets[0]
+	if sheetName != "" {
+		sheet = ""
+		for _, name := range sheets {
+			if strings.EqualFold(name, sheetName) {
+				sheet = name
+			}
+		}
+		if sheet == "" {
+			return nil, fmt.Errorf("no sheet named %q; sheets: %s", sheetName, strings.Join(sheets, ", "))
+		}
+	}
+	records, err := book.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("read sheet %s: %w", sheet, err)
+	}
+	return records, nil
+}
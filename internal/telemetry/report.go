@@ -0,0 +1,136 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// ReportInterval is how often counts are sent.
+const ReportInterval = 24 * time.Hour
+
+// flushInterval is how often counts are saved, so a crash loses little.
+const flushInterval = time.Hour
+
+// Report is exactly what is sent. claw telemetry payload prints it.
+type Report struct {
+	InstallID string         `json:"install_id"`
+	Version   string         `json:"version"`
+	OS        string         `json:"os"`
+	Arch      string         `json:"arch"`
+	From      time.Time      `json:"from"`
+	To        time.Time      `json:"to"`
+	Features  map[string]int `json:"features"`
+	Errors    map[string]int `json:"errors"`
+}
+
+// NewReport builds the report for counts.
+func NewReport(state State, counts Counts, version string, now time.Time) Report {
+	return Report{
+		InstallID: state.InstallID,
+		Version:   version,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		From:      counts.Since,
+		To:        now.UTC(),
+		Features:  counts.Features,
+		Errors:    counts.Errors,
+	}
+}
+
+// Send posts report as JSON to endpoint.
+func Send(ctx context.Context, client *http.Client, endpoint string, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("telemetry request: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail := strings.TrimSpace(string(raw))
+		if len(detail) > 300 {
+			detail = detail[:300]
+		}
+		return fmt.Errorf("telemetry request failed: %s %s", resp.Status, detail)
+	}
+	return nil
+}
+
+// Reporter saves counts periodically and sends them once a day while the
+// user is opted in.
+type Reporter struct {
+	StatePath  string
+	CountsPath string
+	// Endpoint receives reports; empty keeps them local.
+	Endpoint string
+	Version  string
+	Client   *http.Client
+}
+
+// Run counts until ctx is done, saving the counts on the way out.
+func (r Reporter) Run(ctx context.Context) {
+	Start()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if _, _, err := r.save(time.Now()); err != nil {
+				logging.Logger().Warn("failed to save telemetry counts", "err", err)
+			}
+			return
+		case <-ticker.C:
+			if err := r.tick(ctx, time.Now()); err != nil {
+				logging.Logger().Warn("telemetry report failed", "err", err)
+			}
+		}
+	}
+}
+
+// tick saves the counts and sends them if a report is due.
+func (r Reporter) tick(ctx context.Context, now time.Time) error {
+	state, counts, err := r.save(now)
+	if err != nil || !state.Enabled {
+		return err
+	}
+	if r.Endpoint == "" || now.Sub(counts.Since) < ReportInterval {
+		return nil
+	}
+	if err := Send(ctx, r.Client, r.Endpoint, NewReport(state, counts, r.Version, now)); err != nil {
+		return err
+	}
+	return store.RemoveFile(r.CountsPath)
+}
+
+// save adds the in-memory counts to the saved ones. If the user opted out
+// since the server started, it discards them instead.
+func (r Reporter) save(now time.Time) (State, Counts, error) {
+	state, err := LoadState(r.StatePath)
+	if err != nil {
+		return state, Counts{}, err
+	}
+	if !state.Enabled {
+		drain()
+		return state, Counts{}, store.RemoveFile(r.CountsPath)
+	}
+	counts, err := Flush(r.CountsPath, now)
+	return state, counts, err
+}
@@ -0,0 +1,181 @@
+// Package telemetry collects opt-in, anonymous usage counts: which features
+// are used and which classes of error happen, never message text, tool
+// arguments, or anything a user typed. Counting only starts once the user
+// runs claw telemetry on, and reports can be printed before they are sent.
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// State is the user's telemetry choice, saved by claw telemetry on|off.
+type State struct {
+	Enabled bool `json:"enabled"`
+	// InstallID is random, created on opt-in, and discarded on opt-out. It
+	// only lets reports from one install be told apart.
+	InstallID string    `json:"install_id,omitempty"`
+	ChangedAt time.Time `json:"changed_at,omitempty"`
+}
+
+// Counts are the totals collected since Since.
+type Counts struct {
+	Since    time.Time      `json:"since"`
+	Features map[string]int `json:"features"`
+	Errors   map[string]int `json:"errors"`
+}
+
+var (
+	mu       sync.Mutex
+	counting bool
+	features = map[string]int{}
+	errs     = map[string]int{}
+)
+
+// Start turns on counting in this process. Without it Feature and Error do
+// nothing.
+func Start() {
+	mu.Lock()
+	defer mu.Unlock()
+	counting = true
+}
+
+// Feature counts one use of a feature, such as "tool.web_search" or
+// "command./usage". Names must come from code, never from user input.
+func Feature(name string) {
+	add(features, name)
+}
+
+// Error counts one error of a class, such as "provider.timeout".
+func Error(class string) {
+	add(errs, class)
+}
+
+func add(counts map[string]int, name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if counting {
+		counts[name]++
+	}
+}
+
+// drain returns and clears the in-memory counts.
+func drain() (map[string]int, map[string]int) {
+	mu.Lock()
+	defer mu.Unlock()
+	f, e := features, errs
+	features, errs = map[string]int{}, map[string]int{}
+	return f, e
+}
+
+// LoadState reads the saved choice; a missing file means telemetry is off.
+func LoadState(path string) (State, error) {
+	var state State
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("read telemetry state: %w", err)
+	}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return state, fmt.Errorf("decode telemetry state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// SetEnabled saves the user's choice. Opting in creates a new install ID;
+// opting out forgets it.
+func SetEnabled(path string, enabled bool, now time.Time) (State, error) {
+	state, err := LoadState(path)
+	if err != nil {
+		return state, err
+	}
+	if enabled && state.InstallID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return state, fmt.Errorf("create install id: %w", err)
+		}
+		state.InstallID = hex.EncodeToString(id)
+	}
+	if !enabled {
+		state.InstallID = ""
+	}
+	state.Enabled = enabled
+	state.ChangedAt = now.UTC()
+	return state, writeJSON(path, state)
+}
+
+// LoadCounts reads the totals saved so far.
+func LoadCounts(path string) (Counts, error) {
+	counts := Counts{Features: map[string]int{}, Errors: map[string]int{}}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return counts, nil
+	}
+	if err != nil {
+		return counts, fmt.Errorf("read telemetry counts: %w", err)
+	}
+	if err := json.Unmarshal(raw, &counts); err != nil {
+		return counts, fmt.Errorf("decode telemetry counts %s: %w", path, err)
+	}
+	if counts.Features == nil {
+		counts.Features = map[string]int{}
+	}
+	if counts.Errors == nil {
+		counts.Errors = map[string]int{}
+	}
+	return counts, nil
+}
+
+// Flush adds the in-memory counts to those saved in path.
+func Flush(path string, now time.Time) (Counts, error) {
+	counts, err := LoadCounts(path)
+	if err != nil {
+		return counts, err
+	}
+	f, e := drain()
+	if len(f) == 0 && len(e) == 0 && !counts.Since.IsZero() {
+		return counts, nil
+	}
+	if counts.Since.IsZero() {
+		counts.Since = now.UTC()
+	}
+	for name, n := range f {
+		counts.Features[name] += n
+	}
+	for name, n := range e {
+		counts.Errors[name] += n
+	}
+	return counts, writeJSON(path, counts)
+}
+
+// Names lists the keys of counts in order, for display.
+func Names(counts map[string]int) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeJSON(path string, value any) error {
+	raw, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := store.WriteFile(path, append(raw, '\n')); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func resetCounting(t *testing.T) {
+	t.Helper()
+	drain()
+	mu.Lock()
+	counting = false
+	mu.Unlock()
+	t.Cleanup(func() {
+		drain()
+		mu.Lock()
+		counting = false
+		mu.Unlock()
+	})
+}
+
+func TestCountsOnlyAfterStart(t *testing.T) {
+	resetCounting(t)
+	path := filepath.Join(t.TempDir(), "counts.json")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	Feature("tool.web_search")
+	Start()
+	Feature("tool.web_search")
+	Feature("tool.web_search")
+	Error("provider.timeout")
+	counts, err := Flush(path, now)
+	if err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	Feature("command./usage")
+	counts, err = Flush(path, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if counts.Features["tool.web_search"] != 2 || counts.Features["command./usage"] != 1 || counts.Errors["provider.timeout"] != 1 {
+		t.Fatalf("unexpected counts %+v", counts)
+	}
+	if !counts.Since.Equal(now) {
+		t.Fatalf("expected counts since first flush, got %v", counts.Since)
+	}
+}
+
+func TestSetEnabledManagesInstallID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	if state, err := LoadState(path); err != nil || state.Enabled {
+		t.Fatalf("expected off by default, got %+v (%v)", state, err)
+	}
+	state, err := SetEnabled(path, true, time.Now())
+	if err != nil || !state.Enabled || len(state.InstallID) != 32 {
+		t.Fatalf("unexpected state %+v (%v)", state, err)
+	}
+	state, err = SetEnabled(path, false, time.Now())
+	if err != nil || state.Enabled || state.InstallID != "" {
+		t.Fatalf("expected id forgotten on opt-out, got %+v (%v)", state, err)
+	}
+}
+
+func TestReporterSendsDailyAndDiscardsAfterOptOut(t *testing.T) {
+	resetCounting(t)
+	dir := t.TempDir()
+	var received []Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report Report
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			t.Errorf("decode report: %v", err)
+		}
+		received = append(received, report)
+	}))
+	defer server.Close()
+
+	reporter := Reporter{
+		StatePath:  filepath.Join(dir, "telemetry.json"),
+		CountsPath: filepath.Join(dir, "counts.json"),
+		Endpoint:   server.URL,
+		Version:    "1.2.3",
+		Client:     server.Client(),
+	}
+	state, err := SetEnabled(reporter.StatePath, true, time.Now())
+	if err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	Start()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	Feature("tool.todo_add")
+	if err := reporter.tick(context.Background(), now); err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if len(received) != 0 {
+		t.Fatalf("expected no report before a day has passed")
+	}
+	if err := reporter.tick(context.Background(), now.Add(ReportInterval)); err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if len(received) != 1 || received[0].InstallID != state.InstallID || received[0].Features["tool.todo_add"] != 1 || received[0].Version != "1.2.3" {
+		t.Fatalf("unexpected reports %+v", received)
+	}
+	if _, err := os.Stat(reporter.CountsPath); !os.IsNotExist(err) {
+		t.Fatalf("expected counts reset after sending, got %v", err)
+	}
+
+	Feature("tool.todo_add")
+	if _, err := SetEnabled(reporter.StatePath, false, time.Now()); err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	if err := reporter.tick(context.Background(), now.Add(3*ReportInterval)); err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected nothing sent after opt-out, got %d reports", len(received))
+	}
+	if _, err := os.Stat(reporter.CountsPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no counts saved after opt-out, got %v", err)
+	}
+}
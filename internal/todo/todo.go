@@ -0,0 +1,227 @@
+// Package todo stores the user's task list, with optional due dates.
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// DateLayout is the format of task due dates.
+const DateLayout = "2006-01-02"
+
+// doneRetention is how long completed tasks are kept before being dropped.
+const doneRetention = 30 * 24 * time.Hour
+
+// Task is one item on the list.
+type Task struct {
+	ID        int       `json:"id"`
+	Text      string    `json:"text"`
+	Due       string    `json:"due,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	DoneAt    time.Time `json:"done_at,omitzero"`
+}
+
+// Done reports whether the task has been completed.
+func (t Task) Done() bool {
+	return !t.DoneAt.IsZero()
+}
+
+// DueBy reports whether an open task is due on or before now's local date.
+func (t Task) DueBy(now time.Time) bool {
+	return !t.Done() && t.Due != "" && t.Due <= now.In(time.Local).Format(DateLayout)
+}
+
+// Format renders the task on one line, e.g. "3. buy filters (due Sat 2026-03-07)".
+func (t Task) Format(now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d. ", t.ID)
+	if t.Done() {
+		b.WriteString("[done] ")
+	}
+	b.WriteString(t.Text)
+	if t.Due != "" {
+		label := t.Due
+		if due, err := time.ParseInLocation(DateLayout, t.Due, time.Local); err == nil {
+			label = due.Format("Mon 2006-01-02")
+		}
+		today := now.In(time.Local).Format(DateLayout)
+		switch {
+		case t.Done():
+			fmt.Fprintf(&b, " (due %s)", label)
+		case t.Due < today:
+			fmt.Fprintf(&b, " (due %s, overdue)", label)
+		case t.Due == today:
+			b.WriteString(" (due today)")
+		default:
+			fmt.Fprintf(&b, " (due %s)", label)
+		}
+	}
+	return b.String()
+}
+
+// FormatList renders tasks one per line under a heading.
+func FormatList(tasks []Task, now time.Time) string {
+	if len(tasks) == 0 {
+		return "No open tasks."
+	}
+	lines := make([]string, 0, len(tasks)+1)
+	lines = append(lines, "Todo:")
+	for _, task := range tasks {
+		lines = append(lines, task.Format(now))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Store persists tasks as a JSON array.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// New creates a task store backed by path.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Add appends an open task. due is empty or a YYYY-MM-DD date.
+func (s *Store) Add(text, due string, now time.Time) (Task, error) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return Task{}, errors.New("task text is required")
+	}
+	due = strings.TrimSpace(due)
+	if due != "" {
+		if _, err := time.ParseInLocation(DateLayout, due, time.Local); err != nil {
+			return Task{}, fmt.Errorf("due date must be YYYY-MM-DD, got %q", due)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks, err := s.load()
+	if err != nil {
+		return Task{}, err
+	}
+	task := Task{ID: 1, Text: text, Due: due, CreatedAt: now.UTC()}
+	for _, existing := range tasks {
+		if existing.ID >= task.ID {
+			task.ID = existing.ID + 1
+		}
+	}
+	tasks = append(tasks, task)
+	if err := s.save(tasks, now); err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+// Complete marks a task done. Completing a done task again is a no-op.
+func (s *Store) Complete(id int, now time.Time) (Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks, err := s.load()
+	if err != nil {
+		return Task{}, err
+	}
+	for i, task := range tasks {
+		if task.ID != id {
+			continue
+		}
+		if !task.Done() {
+			tasks[i].DoneAt = now.UTC()
+			if err := s.save(tasks, now); err != nil {
+				return Task{}, err
+			}
+		}
+		return tasks[i], nil
+	}
+	return Task{}, fmt.Errorf("no task %d", id)
+}
+
+// List returns open tasks, earliest due date first and undated tasks last.
+// With includeDone, recently completed tasks follow the open ones.
+func (s *Store) List(includeDone bool) ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	kept := tasks[:0]
+	for _, task := range tasks {
+		if includeDone || !task.Done() {
+			kept = append(kept, task)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		a, b := kept[i], kept[j]
+		if a.Done() != b.Done() {
+			return !a.Done()
+		}
+		if (a.Due == "") != (b.Due == "") {
+			return a.Due != ""
+		}
+		if a.Due != b.Due {
+			return a.Due < b.Due
+		}
+		return a.ID < b.ID
+	})
+	return kept, nil
+}
+
+// Due returns open tasks due on or before now's local date.
+func (s *Store) Due(now time.Time) ([]Task, error) {
+	tasks, err := s.List(false)
+	if err != nil {
+		return nil, err
+	}
+	var due []Task
+	for _, task := range tasks {
+		if task.DueBy(now) {
+			due = append(due, task)
+		}
+	}
+	return due, nil
+}
+
+func (s *Store) load() ([]Task, error) {
+	raw, err := store.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read tasks: %w", err)
+	}
+	var tasks []Task
+	if err := json.Unmarshal([]byte(raw), &tasks); err != nil {
+		return nil, fmt.Errorf("decode tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// save writes tasks, dropping ones completed more than doneRetention ago.
+func (s *Store) save(tasks []Task, now time.Time) error {
+	kept := make([]Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Done() && now.Sub(task.DoneAt) > doneRetention {
+			continue
+		}
+		kept = append(kept, task)
+	}
+	raw, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode tasks: %w", err)
+	}
+	if err := store.WriteFile(s.path, append(raw, '\n')); err != nil {
+		return fmt.Errorf("write tasks: %w", err)
+	}
+	return nil
+}
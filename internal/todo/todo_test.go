@@ -0,0 +1,68 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddCompleteList(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "tasks.json"))
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.Local)
+
+	filters, err := s.Add("buy  furnace filters", "2026-03-07", now)
+	if err != nil {
+		t.Fatalf("add filters: %v", err)
+	}
+	if filters.ID != 1 || filters.Text != "buy furnace filters" {
+		t.Fatalf("unexpected task %#v", filters)
+	}
+	if _, err := s.Add("call plumber", "", now); err != nil {
+		t.Fatalf("add plumber: %v", err)
+	}
+	if _, err := s.Add("renew passport", "2026-03-01", now); err != nil {
+		t.Fatalf("add passport: %v", err)
+	}
+	if _, err := s.Add("bad date", "Saturday", now); err == nil {
+		t.Fatalf("expected invalid due date to fail")
+	}
+
+	tasks, err := s.List(false)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	want := "Todo:\n3. renew passport (due Sun 2026-03-01, overdue)\n1. buy furnace filters (due Sat 2026-03-07)\n2. call plumber"
+	if got := FormatList(tasks, now); got != want {
+		t.Fatalf("unexpected list:\n%s\nwant:\n%s", got, want)
+	}
+
+	due, err := s.Due(now)
+	if err != nil {
+		t.Fatalf("due: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != 3 {
+		t.Fatalf("expected only the overdue task, got %#v", due)
+	}
+
+	done, err := s.Complete(3, now)
+	if err != nil || !done.Done() {
+		t.Fatalf("complete: %#v %v", done, err)
+	}
+	if _, err := s.Complete(9, now); err == nil {
+		t.Fatalf("expected missing task to fail")
+	}
+	if tasks, _ := s.List(false); len(tasks) != 2 {
+		t.Fatalf("expected completed task hidden, got %#v", tasks)
+	}
+	if tasks, _ := s.List(true); len(tasks) != 3 || tasks[2].ID != 3 {
+		t.Fatalf("expected completed task listed last, got %#v", tasks)
+	}
+
+	// Completed tasks are dropped a month later, on the next write.
+	if _, err := s.Add("water plants", "", now.AddDate(0, 2, 0)); err != nil {
+		t.Fatalf("add later: %v", err)
+	}
+	if tasks, _ := s.List(true); len(tasks) != 3 {
+		t.Fatalf("expected old completed task pruned, got %#v", tasks)
+	}
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+// RegisterArtifactTool records a produced file so the user can list and download it.
+type RegisterArtifactTool struct {
+	WorkspaceDir string
+	SecurityMode string
+	Store        *artifacts.Store
+	// ResolveChannelID names the chat the file is registered for, so other
+	// chats' /artifacts do not list it.
+	ResolveChannelID func() string
+}
+
+// Name returns the tool name.
+func (t RegisterArtifactTool) Name() string {
+	return "register_artifact"
+}
+
+// Description returns the tool description for the model.
+func (t RegisterArtifactTool) Description() string {
+	return "Register a file you created for the user (report, script, image, export) so they can find and download it with /artifacts. Do not register scratch files."
+}
+
+// Schema returns the JSON schema for register_artifact args.
+func (t RegisterArtifactTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path to the file, absolute or relative to workspace",
+			},
+			"description": map[string]any{
+				"type":        "string",
+				"description": "One short line describing what the file is",
+			},
+		},
+		"required": []string{"path", "description"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t RegisterArtifactTool) Permission() Permission {
+	return AutoApprove
+}
+
+// SummarizeArgs returns a human-readable description of a register_artifact call.
+func (t RegisterArtifactTool) SummarizeArgs(args map[string]any) string {
+	path, _ := args["path"].(string)
+	return "register_artifact: " + strings.TrimSpace(path)
+}
+
+// Execute registers the file and returns its artifact ID.
+func (t RegisterArtifactTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("artifact store is required")
+	}
+	pathArg, err := stringArg(args, "path")
+	if err != nil {
+		return nil, err
+	}
+	description, err := stringArg(args, "description")
+	if err != nil {
+		return nil, err
+	}
+
+	// Artifacts can be sent to the user, so keep them inside the workspace
+	// unless the operator opted out of all boundaries.
+	var path string
+	if strings.EqualFold(strings.TrimSpace(t.SecurityMode), config.SecurityModeDanger) {
+		path, err = resolveInputPath(t.WorkspaceDir, projectPath(ctx, pathArg))
+	} else {
+		path, err = resolveWorkspacePath(t.WorkspaceDir, projectPath(ctx, pathArg))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	artifact, err := artifactStore(t.Store, t.ResolveChannelID).Add(path, description, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: fmt.Sprintf("registered artifact %d (%s); the user can download it with /artifact_%d", artifact.ID, artifact.Name(), artifact.ID)}, nil
+}
+
+// artifactStore returns the view of store for the chat resolve names.
+func artifactStore(store *artifacts.Store, resolve func() string) *artifacts.Store {
+	if resolve == nil {
+		return store
+	}
+	return store.ForChannel(strings.TrimSpace(resolve()))
+}
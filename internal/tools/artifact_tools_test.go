@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
+)
+
+func TestRegisterArtifact_RecordsWorkspaceFile(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "report.md"), []byte("# Report\n"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	store := artifacts.New(filepath.Join(t.TempDir(), "artifacts.json"))
+	tool := RegisterArtifactTool{WorkspaceDir: workspace, Store: store}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"path": "report.md", "description": "Weekly report"})
+	if err != nil {
+		t.Fatalf("register artifact: %v", err)
+	}
+	if !strings.Contains(res.Output, "/artifact_1") {
+		t.Fatalf("expected download command in output, got %q", res.Output)
+	}
+	items, err := store.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 1 || items[0].Description != "Weekly report" {
+		t.Fatalf("unexpected artifacts: %#v", items)
+	}
+}
+
+func TestRegisterArtifact_RejectsPathOutsideWorkspace(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("x"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	tool := RegisterArtifactTool{
+		WorkspaceDir: t.TempDir(),
+		Store:        artifacts.New(filepath.Join(t.TempDir(), "artifacts.json")),
+	}
+	_, err := tool.Execute(context.Background(), map[string]any{"path": outside, "description": "secret"})
+	if err == nil || !strings.Contains(err.Error(), "outside workspace") {
+		t.Fatalf("expected outside workspace error, got %v", err)
+	}
+}
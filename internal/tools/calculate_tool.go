@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/neoclaw-ai/neoclaw/internal/calc"
+)
+
+// CalculateTool evaluates math expressions and converts units and currencies
+// deterministically, so the model never has to do arithmetic itself.
+type CalculateTool struct {
+	// Rates converts currencies; nil disables currency conversion.
+	Rates *calc.FXRates
+}
+
+// Name returns the tool name.
+func (t CalculateTool) Name() string {
+	return "calculate"
+}
+
+// Description returns the tool description for the model.
+func (t CalculateTool) Description() string {
+	return "Evaluate a math expression exactly, optionally converting the result between units (mi to km, F to C, lb to kg) or currencies (USD to EUR, daily reference rates). Always use this instead of doing arithmetic yourself."
+}
+
+// Schema returns the JSON schema for calculate args.
+func (t CalculateTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"expression": map[string]any{
+				"type":        "string",
+				"description": "Math expression using + - * / % ^, parentheses, pi, e and sqrt, abs, round(x, digits), floor, ceil, ln, log, exp, sin, cos, tan, pow, min, max. No thousands separators, e.g. 250000*0.005/(1-(1+0.005)^-360)",
+			},
+			"from": map[string]any{
+				"type":        "string",
+				"description": "Optional unit or upper-case ISO currency code of the result, e.g. mi, kg, F, GiB, USD",
+			},
+			"to": map[string]any{
+				"type":        "string",
+				"description": "Optional unit or upper-case ISO currency code to convert to; required with from",
+			},
+		},
+		"required": []string{"expression"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t CalculateTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute evaluates the expression and applies the optional conversion.
+func (t CalculateTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	expression, err := stringArg(args, "expression")
+	if err != nil {
+		return nil, err
+	}
+	from, err := optionalStringArg(args, "from", "")
+	if err != nil {
+		return nil, err
+	}
+	to, err := optionalStringArg(args, "to", "")
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := calc.Eval(expression)
+	if err != nil {
+		return nil, fmt.Errorf("evaluate %q: %w", expression, err)
+	}
+	if from == "" && to == "" {
+		return &ToolResult{Output: fmt.Sprintf("%s = %s", expression, calc.Format(value))}, nil
+	}
+	if from == "" || to == "" {
+		return nil, errors.New("from and to must be given together")
+	}
+
+	if calc.IsCurrency(from) && calc.IsCurrency(to) {
+		if t.Rates == nil {
+			return nil, errors.New("currency conversion is not configured")
+		}
+		converted, date, err := t.Rates.Convert(ctx, value, from, to)
+		if err != nil {
+			return nil, err
+		}
+		converted = math.Round(converted*100) / 100
+		return &ToolResult{Output: fmt.Sprintf("%s %s = %s %s (reference rates of %s)", calc.Format(value), from, calc.Format(converted), to, date)}, nil
+	}
+	converted, err := calc.ConvertUnit(value, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: fmt.Sprintf("%s %s = %s %s", calc.Format(value), from, calc.Format(converted), to)}, nil
+}
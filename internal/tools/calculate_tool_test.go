@@ -0,0 +1,27 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCalculateToolEvaluatesAndConverts(t *testing.T) {
+	tool := CalculateTool{}
+	ctx := context.Background()
+
+	res, err := tool.Execute(ctx, map[string]any{"expression": "0.1 + 0.2"})
+	if err != nil || res.Output != "0.1 + 0.2 = 0.3" {
+		t.Fatalf("unexpected result %#v err=%v", res, err)
+	}
+	res, err = tool.Execute(ctx, map[string]any{"expression": "26.2", "from": "mi", "to": "km"})
+	if err != nil || res.Output != "26.2 mi = 42.1648128 km" {
+		t.Fatalf("unexpected conversion %#v err=%v", res, err)
+	}
+	if _, err := tool.Execute(ctx, map[string]any{"expression": "1", "from": "USD", "to": "EUR"}); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Fatalf("expected currency conversion to need rates, got %v", err)
+	}
+	if _, err := tool.Execute(ctx, map[string]any{"expression": "1", "from": "mi"}); err == nil {
+		t.Fatalf("expected from without to to fail")
+	}
+}
@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/expenses"
+)
+
+// LogExpenseTool records something the user spent.
+type LogExpenseTool struct {
+	Log *expenses.Log
+	now func() time.Time
+}
+
+// Name returns the tool name.
+func (t LogExpenseTool) Name() string {
+	return "log_expense"
+}
+
+// Description returns the tool description for the model.
+func (t LogExpenseTool) Description() string {
+	return "Record money the user says they spent, so it can be added up later with expense_report. Log refunds with a negative amount."
+}
+
+// Schema returns the JSON schema for log_expense args.
+func (t LogExpenseTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"amount": map[string]any{
+				"type":        "string",
+				"description": "Amount spent as a plain number, e.g. 12.50",
+			},
+			"currency": map[string]any{
+				"type":        "string",
+				"description": "ISO currency code, e.g. USD or EUR",
+			},
+			"category": map[string]any{
+				"type":        "string",
+				"description": "Short category such as groceries, dining, transport, rent, or other. Reuse categories the user already has.",
+			},
+			"merchant": map[string]any{
+				"type":        "string",
+				"description": "Optional shop or payee",
+			},
+			"date": map[string]any{
+				"type":        "string",
+				"description": "Optional date of the purchase as YYYY-MM-DD; defaults to today",
+			},
+			"note": map[string]any{
+				"type":        "string",
+				"description": "Optional short note",
+			},
+		},
+		"required": []string{"amount", "currency", "category"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t LogExpenseTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute appends the expense to the log.
+func (t LogExpenseTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Log == nil {
+		return nil, errors.New("expense log is required")
+	}
+	rawAmount, err := stringArg(args, "amount")
+	if err != nil {
+		return nil, err
+	}
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(rawAmount), ",", ""), 64)
+	if err != nil {
+		return nil, fmt.Errorf("argument amount must be a number, got %q", rawAmount)
+	}
+	currency, err := stringArg(args, "currency")
+	if err != nil {
+		return nil, err
+	}
+	category, err := stringArg(args, "category")
+	if err != nil {
+		return nil, err
+	}
+	merchant, err := optionalStringArg(args, "merchant", "")
+	if err != nil {
+		return nil, err
+	}
+	date, err := optionalStringArg(args, "date", "")
+	if err != nil {
+		return nil, err
+	}
+	note, err := optionalStringArg(args, "note", "")
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+
+	entry, err := t.Log.Append(expenses.Entry{
+		Timestamp: now(),
+		Date:      date,
+		Amount:    amount,
+		Currency:  currency,
+		Category:  category,
+		Merchant:  merchant,
+		Note:      note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: "logged " + entry.Format()}, nil
+}
+
+// ExpenseReportTool totals recorded expenses for a month.
+type ExpenseReportTool struct {
+	Log *expenses.Log
+	now func() time.Time
+}
+
+// Name returns the tool name.
+func (t ExpenseReportTool) Name() string {
+	return "expense_report"
+}
+
+// Description returns the tool description for the model.
+func (t ExpenseReportTool) Description() string {
+	return "Total the user's logged expenses for a month by currency and category. With a category, also lists each expense in it."
+}
+
+// Schema returns the JSON schema for expense_report args.
+func (t ExpenseReportTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"month": map[string]any{
+				"type":        "string",
+				"description": "Month as YYYY-MM; defaults to the current month",
+			},
+			"category": map[string]any{
+				"type":        "string",
+				"description": "Optional category to report on, e.g. groceries",
+			},
+		},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t ExpenseReportTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute summarizes the month's expenses.
+func (t ExpenseReportTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Log == nil {
+		return nil, errors.New("expense log is required")
+	}
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+	month, err := optionalStringArg(args, "month", now().In(time.Local).Format(expenses.MonthLayout))
+	if err != nil {
+		return nil, err
+	}
+	category, err := optionalStringArg(args, "category", "")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := t.Log.Month(month)
+	if err != nil {
+		return nil, err
+	}
+	title := expenses.MonthTitle(strings.TrimSpace(month))
+	if strings.TrimSpace(category) == "" {
+		return &ToolResult{Output: expenses.Summarize(entries).Format(title)}, nil
+	}
+
+	category = expenses.NormalizeCategory(category)
+	var matching []expenses.Entry
+	for _, entry := range entries {
+		if entry.Category == category {
+			matching = append(matching, entry)
+		}
+	}
+	lines := []string{expenses.Summarize(matching).Format(title + " in " + category)}
+	for _, entry := range matching {
+		lines = append(lines, entry.Format())
+	}
+	return TruncateOutput(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/expenses"
+)
+
+func TestExpenseToolsLogAndReport(t *testing.T) {
+	t.Parallel()
+
+	log := expenses.New(filepath.Join(t.TempDir(), "expenses.tsv"))
+	now := time.Date(2026, 3, 7, 12, 0, 0, 0, time.Local)
+	logTool := LogExpenseTool{Log: log, now: func() time.Time { return now }}
+
+	logged, err := logTool.Execute(context.Background(), map[string]any{
+		"amount":   "1,200.00",
+		"currency": "usd",
+		"category": "Rent",
+	})
+	if err != nil {
+		t.Fatalf("log_expense: %v", err)
+	}
+	if logged.Output != "logged 2026-03-07 1200.00 USD rent" {
+		t.Fatalf("unexpected log output %q", logged.Output)
+	}
+	if _, err := logTool.Execute(context.Background(), map[string]any{
+		"amount":   "12.5",
+		"currency": "USD",
+		"category": "dining",
+		"merchant": "Joe's",
+		"date":     "2026-03-02",
+	}); err != nil {
+		t.Fatalf("log_expense dining: %v", err)
+	}
+	if _, err := logTool.Execute(context.Background(), map[string]any{"amount": "twelve", "currency": "USD", "category": "dining"}); err == nil {
+		t.Fatalf("expected non-numeric amount to fail")
+	}
+
+	reportTool := ExpenseReportTool{Log: log, now: func() time.Time { return now }}
+	report, err := reportTool.Execute(context.Background(), map[string]any{})
+	if err != nil {
+		t.Fatalf("expense_report: %v", err)
+	}
+	if !strings.Contains(report.Output, "Expenses for March 2026 (2 entries)\nTotal: 1212.50 USD\n- rent: 1200.00 USD (1)\n- dining: 12.50 USD (1)") {
+		t.Fatalf("unexpected report %q", report.Output)
+	}
+
+	dining, err := reportTool.Execute(context.Background(), map[string]any{"category": "Dining", "month": "2026-03"})
+	if err != nil {
+		t.Fatalf("expense_report dining: %v", err)
+	}
+	if !strings.HasSuffix(dining.Output, "\n2026-03-02 12.50 USD dining at Joe's") || strings.Contains(dining.Output, "rent") {
+		t.Fatalf("unexpected category report %q", dining.Output)
+	}
+
+	if _, err := reportTool.Execute(context.Background(), map[string]any{"month": "March"}); err == nil {
+		t.Fatalf("expected invalid month to fail")
+	}
+}
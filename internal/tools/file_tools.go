@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -42,6 +44,36 @@ func resolveInputPath(workspaceDir, input string) (string, error) {
 	return filepath.Clean(filepath.Join(workspaceDir, input)), nil
 }
 
+// projectPath joins a relative path onto the active project's root, set
+// with WithWorkdir, so file tools find files where run_command runs.
+// Absolute paths, and all paths outside a project, are returned unchanged.
+func projectPath(ctx context.Context, input string) string {
+	dir, ok := ctx.Value(workdirKey{}).(string)
+	if !ok || dir == "" || filepath.IsAbs(input) {
+		return input
+	}
+	return filepath.Join(dir, input)
+}
+
+// resolveWritePath resolves a path a tool will change: anywhere in danger
+// mode, otherwise only under the workspace.
+func resolveWritePath(workspaceDir, securityMode, input string) (string, error) {
+	if strings.EqualFold(strings.TrimSpace(securityMode), config.SecurityModeDanger) {
+		return resolveInputPath(workspaceDir, input)
+	}
+	return resolveWorkspacePath(workspaceDir, input)
+}
+
+// isWorkspaceRoot reports whether a resolved path is the workspace itself.
+func isWorkspaceRoot(workspaceDir, path string) bool {
+	workspaceAbs, err := filepath.Abs(workspaceDir)
+	if err != nil {
+		return false
+	}
+	workspaceReal, err := filepath.EvalSymlinks(workspaceAbs)
+	return err == nil && workspaceReal == path
+}
+
 func resolveWorkspacePath(workspaceDir, input string) (string, error) {
 	if strings.TrimSpace(workspaceDir) == "" {
 		return "", errors.New("workspace directory is required")
@@ -177,13 +209,13 @@ func (t ReadFileTool) Permission() Permission {
 }
 
 // Execute reads text content from a workspace-scoped path.
-func (t ReadFileTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+func (t ReadFileTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
 	pathArg, err := stringArg(args, "path")
 	if err != nil {
 		return nil, err
 	}
 
-	path, err := resolveInputPath(t.WorkspaceDir, pathArg)
+	path, err := resolveInputPath(t.WorkspaceDir, projectPath(ctx, pathArg))
 	if err != nil {
 		return nil, err
 	}
@@ -236,13 +268,13 @@ func (t ListDirTool) Permission() Permission {
 }
 
 // Execute lists entries in the resolved directory path.
-func (t ListDirTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+func (t ListDirTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
 	pathArg, err := stringArg(args, "path")
 	if err != nil {
 		return nil, err
 	}
 
-	path, err := resolveInputPath(t.WorkspaceDir, pathArg)
+	path, err := resolveInputPath(t.WorkspaceDir, projectPath(ctx, pathArg))
 	if err != nil {
 		return nil, err
 	}
@@ -331,7 +363,7 @@ func (t WriteFileTool) SummarizeArgs(args map[string]any) string {
 }
 
 // Execute writes content to a workspace-scoped file path.
-func (t WriteFileTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+func (t WriteFileTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
 	pathArg, err := stringArg(args, "path")
 	if err != nil {
 		return nil, err
@@ -341,12 +373,7 @@ func (t WriteFileTool) Execute(_ context.Context, args map[string]any) (*ToolRes
 		return nil, err
 	}
 
-	var path string
-	if strings.EqualFold(strings.TrimSpace(t.SecurityMode), config.SecurityModeDanger) {
-		path, err = resolveInputPath(t.WorkspaceDir, pathArg)
-	} else {
-		path, err = resolveWorkspacePath(t.WorkspaceDir, pathArg)
-	}
+	path, err := resolveWritePath(t.WorkspaceDir, t.SecurityMode, projectPath(ctx, pathArg))
 	if err != nil {
 		return nil, err
 	}
@@ -358,6 +385,185 @@ func (t WriteFileTool) Execute(_ context.Context, args map[string]any) (*ToolRes
 	return &ToolResult{Output: "ok"}, nil
 }
 
+// MoveFileTool moves or renames a file or directory within the workspace.
+type MoveFileTool struct {
+	WorkspaceDir string
+	SecurityMode string
+}
+
+// Name returns the tool name.
+func (t MoveFileTool) Name() string {
+	return "move_file"
+}
+
+// Description returns the tool description for the model.
+func (t MoveFileTool) Description() string {
+	return "Move or rename a file or directory in the workspace. Never overwrites: fails if the destination exists."
+}
+
+// Schema returns the JSON schema for move_file args.
+func (t MoveFileTool) Schema() map[string]any {
+	return transferSchema()
+}
+
+// Permission declares default permission behavior for this tool.
+func (t MoveFileTool) Permission() Permission {
+	return AutoApprove
+}
+
+// SummarizeArgs returns a concise summary for move_file.
+func (t MoveFileTool) SummarizeArgs(args map[string]any) string {
+	source, _ := args["source"].(string)
+	destination, _ := args["destination"].(string)
+	return fmt.Sprintf("move_file: %q -> %q", strings.TrimSpace(source), strings.TrimSpace(destination))
+}
+
+// Execute renames source to destination after checking both.
+func (t MoveFileTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	source, destination, err := resolveTransfer(ctx, t.WorkspaceDir, t.SecurityMode, args)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkspaceRoot(t.WorkspaceDir, source) {
+		return nil, errors.New("refusing to move the workspace itself")
+	}
+	if err := os.Rename(source, destination); err != nil {
+		return nil, fmt.Errorf("move file: %w", err)
+	}
+	return &ToolResult{Output: fmt.Sprintf("moved %s to %s", source, destination)}, nil
+}
+
+// CopyFileTool copies a file within the workspace.
+type CopyFileTool struct {
+	WorkspaceDir string
+	SecurityMode string
+}
+
+// Name returns the tool name.
+func (t CopyFileTool) Name() string {
+	return "copy_file"
+}
+
+// Description returns the tool description for the model.
+func (t CopyFileTool) Description() string {
+	return "Copy a file in the workspace. Never overwrites: fails if the destination exists."
+}
+
+// Schema returns the JSON schema for copy_file args.
+func (t CopyFileTool) Schema() map[string]any {
+	return transferSchema()
+}
+
+// Permission declares default permission behavior for this tool.
+func (t CopyFileTool) Permission() Permission {
+	return AutoApprove
+}
+
+// SummarizeArgs returns a concise summary for copy_file.
+func (t CopyFileTool) SummarizeArgs(args map[string]any) string {
+	source, _ := args["source"].(string)
+	destination, _ := args["destination"].(string)
+	return fmt.Sprintf("copy_file: %q -> %q", strings.TrimSpace(source), strings.TrimSpace(destination))
+}
+
+// Execute copies source to destination after checking both.
+func (t CopyFileTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	source, destination, err := resolveTransfer(ctx, t.WorkspaceDir, t.SecurityMode, args)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("stat source: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", source)
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("open source: %w", err)
+	}
+	defer in.Close()
+	// O_EXCL closes the gap between the existence check and the write.
+	out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return nil, fmt.Errorf("create destination: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(destination)
+		return nil, fmt.Errorf("copy file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(destination)
+		return nil, fmt.Errorf("close destination: %w", err)
+	}
+	return &ToolResult{Output: fmt.Sprintf("copied %s to %s (%s bytes)", source, destination, formatWithCommas(int(info.Size())))}, nil
+}
+
+func transferSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"source": map[string]any{
+				"type":        "string",
+				"description": "Path relative to workspace or absolute path under workspace",
+			},
+			"destination": map[string]any{
+				"type":        "string",
+				"description": "New path relative to workspace or absolute path under workspace; an existing directory receives the source under its own name",
+			},
+		},
+		"required": []string{"source", "destination"},
+	}
+}
+
+// resolveTransfer checks both ends of a move or copy against the workspace
+// boundary, resolving symlinks, and creates the destination's parent
+// directories. Like mv, an existing destination directory receives the
+// source under its own name.
+func resolveTransfer(ctx context.Context, workspaceDir, securityMode string, args map[string]any) (string, string, error) {
+	sourceArg, err := stringArg(args, "source")
+	if err != nil {
+		return "", "", err
+	}
+	destinationArg, err := stringArg(args, "destination")
+	if err != nil {
+		return "", "", err
+	}
+
+	source, err := resolveWritePath(workspaceDir, securityMode, projectPath(ctx, sourceArg))
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := os.Lstat(source); err != nil {
+		return "", "", fmt.Errorf("stat source: %w", err)
+	}
+	destination, err := resolveWritePath(workspaceDir, securityMode, projectPath(ctx, destinationArg))
+	if err != nil {
+		return "", "", err
+	}
+	if info, err := os.Stat(destination); err == nil && info.IsDir() {
+		destination, err = resolveWritePath(workspaceDir, securityMode, filepath.Join(destination, filepath.Base(source)))
+		if err != nil {
+			return "", "", err
+		}
+	}
+	if destination == source {
+		return "", "", errors.New("source and destination are the same")
+	}
+	if _, err := os.Lstat(destination); err == nil {
+		return "", "", fmt.Errorf("destination %s already exists", destinationArg)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", "", fmt.Errorf("stat destination: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return "", "", fmt.Errorf("create destination directory: %w", err)
+	}
+	return source, destination, nil
+}
+
 func formatWithCommas(n int) string {
 	if n == 0 {
 		return "0"
@@ -248,3 +248,125 @@ func TestWriteFileSummarizeArgs(t *testing.T) {
 		t.Fatalf("expected %q, got %q", want, got)
 	}
 }
+
+func TestFileTools_ResolveRelativePathsInProject(t *testing.T) {
+	workspace := t.TempDir()
+	project := filepath.Join(workspace, "blog")
+	writeFixtures(t, workspace, map[string]string{
+		"notes.txt":      "workspace notes",
+		"blog/notes.txt": "blog notes",
+	})
+	ctx := WithWorkdir(context.Background(), project)
+
+	res, err := ReadFileTool{WorkspaceDir: workspace}.Execute(ctx, map[string]any{"path": "notes.txt"})
+	if err != nil || res.Output != "blog notes" {
+		t.Fatalf("expected the project's file, got %q %v", res.Output, err)
+	}
+	if _, err := (WriteFileTool{WorkspaceDir: workspace}).Execute(ctx, map[string]any{"path": "draft.md", "content": "draft"}); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, "draft.md")); err != nil {
+		t.Fatalf("expected draft in the project: %v", err)
+	}
+	if _, err := (MoveFileTool{WorkspaceDir: workspace}).Execute(ctx, map[string]any{"source": "draft.md", "destination": "posts/draft.md"}); err != nil {
+		t.Fatalf("move file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, "posts", "draft.md")); err != nil {
+		t.Fatalf("expected the move to stay in the project: %v", err)
+	}
+
+	// Absolute paths and ../ still reach the rest of the workspace, but not
+	// beyond it.
+	res, err = ReadFileTool{WorkspaceDir: workspace}.Execute(ctx, map[string]any{"path": "../notes.txt"})
+	if err != nil || res.Output != "workspace notes" {
+		t.Fatalf("expected the workspace file, got %q %v", res.Output, err)
+	}
+	if _, err := (WriteFileTool{WorkspaceDir: workspace}).Execute(ctx, map[string]any{"path": "../../escape.txt", "content": "x"}); err == nil {
+		t.Fatal("expected a write outside the workspace to fail")
+	}
+}
+
+func TestMoveFile_RenamesAndMovesIntoDirectory(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "draft.md"), []byte("v1"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(workspace, "archive"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	tool := MoveFileTool{WorkspaceDir: workspace}
+	if tool.Permission() != AutoApprove {
+		t.Fatal("expected move_file to auto-approve")
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"source": "draft.md", "destination": "notes/final.md"}); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"source": "notes/final.md", "destination": "archive"}); err != nil {
+		t.Fatalf("move into directory: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(workspace, "archive", "final.md")); err != nil || string(data) != "v1" {
+		t.Fatalf("expected moved file, got %q %v", data, err)
+	}
+}
+
+func TestMoveFile_RefusesOverwriteAndEscapes(t *testing.T) {
+	workspace := t.TempDir()
+	outside := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(workspace, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(workspace, "link")); err != nil {
+		t.Fatalf("create symlink: %v", err)
+	}
+
+	tool := MoveFileTool{WorkspaceDir: workspace}
+	tests := []struct {
+		source, destination, want string
+	}{
+		{source: "a.txt", destination: "b.txt", want: "already exists"},
+		{source: "a.txt", destination: "link/a.txt", want: "outside workspace"},
+		{source: "link/secret.txt", destination: "secret.txt", want: "outside workspace"},
+		{source: filepath.Join(outside, "secret.txt"), destination: "secret.txt", want: "outside workspace"},
+		{source: ".", destination: "elsewhere", want: "workspace itself"},
+	}
+	for _, test := range tests {
+		_, err := tool.Execute(context.Background(), map[string]any{"source": test.source, "destination": test.destination})
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("move %s -> %s: expected %q error, got %v", test.source, test.destination, test.want, err)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(workspace, "b.txt")); err != nil || string(data) != "b.txt" {
+		t.Fatalf("expected destination untouched, got %q %v", data, err)
+	}
+}
+
+func TestCopyFile_CopiesAndRefusesDirectories(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "run.sh"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(workspace, "dir"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	tool := CopyFileTool{WorkspaceDir: workspace}
+	if _, err := tool.Execute(context.Background(), map[string]any{"source": "run.sh", "destination": "bin/run.sh"}); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(workspace, "bin", "run.sh"))
+	if err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("expected copy with source mode, got %v %v", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, "run.sh")); err != nil {
+		t.Fatalf("expected source kept: %v", err)
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"source": "dir", "destination": "dir2"}); err == nil || !strings.Contains(err.Error(), "not a regular file") {
+		t.Fatalf("expected directory refusal, got %v", err)
+	}
+}
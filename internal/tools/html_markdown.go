@@ -0,0 +1,20 @@
+//go:build !lite
+
+package tools
+
+import (
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+)
+
+// htmlToMarkdown converts an HTML string to Markdown. On error it logs and
+// returns the original HTML so the caller always gets usable output.
+func htmlToMarkdown(html string) string {
+	md, err := htmltomarkdown.ConvertString(html)
+	if err != nil {
+		logging.Logger().Info("html to markdown conversion failed, returning raw html", "err", err)
+		return html
+	}
+	return md
+}
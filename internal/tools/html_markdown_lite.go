@@ -0,0 +1,44 @@
+//go:build lite
+
+package tools
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlDroppedBlocks = regexp.MustCompile(`(?is)<(script|style|noscript|svg|head)\b.*?</(script|style|noscript|svg|head)>`)
+	htmlComments      = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlHeadings      = regexp.MustCompile(`(?i)<h([1-6])\b[^>]*>`)
+	htmlListItems     = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	htmlBlockEnds     = regexp.MustCompile(`(?i)</(p|div|h[1-6]|table|ul|ol|blockquote|pre)\s*>`)
+	htmlLineBreaks    = regexp.MustCompile(`(?i)<(br|/tr|hr)\b[^>]*>`)
+	htmlTags          = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLineRuns     = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+`)
+)
+
+// htmlToMarkdown reduces HTML to readable text with headings and list items
+// marked. The lite build uses it instead of a full HTML parser to keep the
+// binary small; links and tables lose their structure.
+func htmlToMarkdown(page string) string {
+	page = htmlDroppedBlocks.ReplaceAllString(page, "")
+	page = htmlComments.ReplaceAllString(page, "")
+	page = htmlHeadings.ReplaceAllStringFunc(page, func(tag string) string {
+		level := int(htmlHeadings.FindStringSubmatch(tag)[1][0] - '0')
+		return "\n\n" + strings.Repeat("#", level) + " "
+	})
+	page = htmlListItems.ReplaceAllString(page, "\n- ")
+	page = htmlBlockEnds.ReplaceAllString(page, "\n\n")
+	page = htmlLineBreaks.ReplaceAllString(page, "\n")
+	page = htmlTags.ReplaceAllString(page, "")
+	page = html.UnescapeString(page)
+
+	lines := strings.Split(page, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	page = strings.Join(lines, "\n")
+	return strings.TrimSpace(blankLineRuns.ReplaceAllString(page, "\n\n"))
+}
@@ -0,0 +1,16 @@
+//go:build lite
+
+package tools
+
+import "testing"
+
+func TestLiteHTMLToMarkdown(t *testing.T) {
+	page := `<html><head><title>x</title><style>p{}</style></head><body>
+<h2>Opening  hours</h2><p>Mon&ndash;Fri <b>9&nbsp;to 5</b></p>
+<ul><li>Closed on holidays</li><li>Call first</li></ul><script>track()</script>
+</body></html>`
+	want := "## Opening hours\n\nMon–Fri 9 to 5\n\n- Closed on holidays\n- Call first"
+	if got := htmlToMarkdown(page); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -57,7 +57,7 @@ func (t JobListTool) Execute(ctx context.Context, _ map[string]any) (*ToolResult
 		if job.Enabled {
 			status = "enabled"
 		}
-		fmt.Fprintf(&b, "%d. %s (%s) - %s\n", i+1, job.Description, job.Cron, status)
+		fmt.Fprintf(&b, "%d. %s (%s) - %s\n", i+1, job.Description, job.Schedule(), status)
 		fmt.Fprintf(&b, "   id: %s, action: %s, channel: %s", job.ID, job.Action, job.ChannelID)
 		if i < len(jobs)-1 {
 			b.WriteByte('\n')
@@ -98,7 +98,7 @@ func (t JobCreateTool) Schema() map[string]any {
 			},
 			"action": map[string]any{
 				"type":        "string",
-				"description": "One of: send_message, run_command, http_request",
+				"description": "One of: send_message, run_command, http_request, profile_refresh (args: optional lookback_days; proposes USER.md updates for the user to approve), run_workflow (args: workflow name)",
 			},
 			"args": map[string]any{
 				"type":        "object",
@@ -140,15 +140,7 @@ func (t JobCreateTool) Execute(ctx context.Context, args map[string]any) (*ToolR
 		return nil, err
 	}
 
-	channelID := strings.TrimSpace(t.ChannelID)
-	if t.ResolveChannelID != nil {
-		if resolved := strings.TrimSpace(t.ResolveChannelID()); resolved != "" {
-			channelID = resolved
-		}
-	}
-	if channelID == "" {
-		channelID = "cli"
-	}
+	channelID := jobChannelID(t.ChannelID, t.ResolveChannelID)
 	createInput := scheduler.CreateInput{
 		Description: description,
 		Cron:        cronSpec,
@@ -165,6 +157,20 @@ func (t JobCreateTool) Execute(ctx context.Context, args map[string]any) (*ToolR
 	return &ToolResult{Output: fmt.Sprintf("created job %s", job.ID)}, nil
 }
 
+// jobChannelID picks the channel a job reports to: the active conversation's
+// channel when known, else the configured default, else cli.
+func jobChannelID(channelID string, resolve func() string) string {
+	if resolve != nil {
+		if resolved := strings.TrimSpace(resolve()); resolved != "" {
+			return resolved
+		}
+	}
+	if channelID = strings.TrimSpace(channelID); channelID != "" {
+		return channelID
+	}
+	return "cli"
+}
+
 // JobDeleteTool deletes a scheduled job by ID.
 type JobDeleteTool struct {
 	Service *scheduler.Service
@@ -285,7 +291,7 @@ func objectArg(args map[string]any, key string) (map[string]any, error) {
 
 func validateJobAction(action scheduler.Action) error {
 	switch action {
-	case scheduler.ActionSendMessage, scheduler.ActionRunCommand, scheduler.ActionHTTPRequest:
+	case scheduler.ActionSendMessage, scheduler.ActionRunCommand, scheduler.ActionHTTPRequest, scheduler.ActionProfileRefresh, scheduler.ActionRunWorkflow:
 		return nil
 	default:
 		return fmt.Errorf("unsupported job action %s", action)
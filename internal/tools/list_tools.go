@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/lists"
+)
+
+var listNameProperty = map[string]any{
+	"type":        "string",
+	"description": "List name, e.g. shopping or hardware_store (default: shopping)",
+}
+
+// ListAddTool adds items to a shared list.
+type ListAddTool struct {
+	Store *lists.Store
+}
+
+// Name returns the tool name.
+func (t ListAddTool) Name() string {
+	return "list_add"
+}
+
+// Description returns the tool description for the model.
+func (t ListAddTool) Description() string {
+	return "Add items to a list shared by everyone who talks to you, such as the shopping list"
+}
+
+// Schema returns the JSON schema for list_add args.
+func (t ListAddTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"items": map[string]any{
+				"type":        "string",
+				"description": "Comma-separated items, e.g. milk, 2 lemons, coffee filters",
+			},
+			"list": listNameProperty,
+		},
+		"required": []string{"items"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t ListAddTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute adds the items and returns the updated list.
+func (t ListAddTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("list store is required")
+	}
+	raw, err := stringArg(args, "items")
+	if err != nil {
+		return nil, err
+	}
+	list, err := optionalStringArg(args, "list", lists.DefaultList)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.Store.Add(list, strings.Split(raw, ","), time.Now()); err != nil {
+		return nil, err
+	}
+	return showList(t.Store, list)
+}
+
+// ListRemoveTool removes an item from a shared list.
+type ListRemoveTool struct {
+	Store *lists.Store
+}
+
+// Name returns the tool name.
+func (t ListRemoveTool) Name() string {
+	return "list_remove"
+}
+
+// Description returns the tool description for the model.
+func (t ListRemoveTool) Description() string {
+	return "Remove an item from a shared list, for example once it has been bought"
+}
+
+// Schema returns the JSON schema for list_remove args.
+func (t ListRemoveTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"item": map[string]any{
+				"type":        "string",
+				"description": "Item text as shown by list_show, or its number",
+			},
+			"list": listNameProperty,
+		},
+		"required": []string{"item"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t ListRemoveTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute removes the item and returns the updated list.
+func (t ListRemoveTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("list store is required")
+	}
+	item, err := stringArg(args, "item")
+	if err != nil {
+		return nil, err
+	}
+	list, err := optionalStringArg(args, "list", lists.DefaultList)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.Store.Remove(list, item); err != nil {
+		return nil, err
+	}
+	return showList(t.Store, list)
+}
+
+// ListShowTool shows a shared list.
+type ListShowTool struct {
+	Store *lists.Store
+}
+
+// Name returns the tool name.
+func (t ListShowTool) Name() string {
+	return "list_show"
+}
+
+// Description returns the tool description for the model.
+func (t ListShowTool) Description() string {
+	return "Show a shared list, or the names of all lists"
+}
+
+// Schema returns the JSON schema for list_show args.
+func (t ListShowTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"list": map[string]any{
+				"type":        "string",
+				"description": "List name (default: shopping); use * to list the names of all lists",
+			},
+		},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t ListShowTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute returns the list with checkboxes.
+func (t ListShowTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("list store is required")
+	}
+	list, err := optionalStringArg(args, "list", lists.DefaultList)
+	if err != nil {
+		return nil, err
+	}
+	if list == "*" {
+		names, err := t.Store.Names()
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			return &ToolResult{Output: "No lists yet."}, nil
+		}
+		return &ToolResult{Output: fmt.Sprintf("Lists: %s", strings.Join(names, ", "))}, nil
+	}
+	return showList(t.Store, list)
+}
+
+func showList(store *lists.Store, list string) (*ToolResult, error) {
+	items, err := store.Items(list)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: lists.Format(list, items)}, nil
+}
@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/lists"
+)
+
+func TestListTools(t *testing.T) {
+	t.Parallel()
+
+	store := lists.New(filepath.Join(t.TempDir(), "lists.json"))
+	added, err := ListAddTool{Store: store}.Execute(context.Background(), map[string]any{"items": "milk, coffee filters"})
+	if err != nil {
+		t.Fatalf("list_add: %v", err)
+	}
+	if added.Output != "Shopping list:\n1. ☐ milk\n2. ☐ coffee filters" {
+		t.Fatalf("unexpected add output %q", added.Output)
+	}
+	removed, err := ListRemoveTool{Store: store}.Execute(context.Background(), map[string]any{"item": "Milk"})
+	if err != nil {
+		t.Fatalf("list_remove: %v", err)
+	}
+	if removed.Output != "Shopping list:\n1. ☐ coffee filters" {
+		t.Fatalf("unexpected remove output %q", removed.Output)
+	}
+	names, err := ListShowTool{Store: store}.Execute(context.Background(), map[string]any{"list": "*"})
+	if err != nil {
+		t.Fatalf("list_show: %v", err)
+	}
+	if names.Output != "Lists: shopping" {
+		t.Fatalf("unexpected names output %q", names.Output)
+	}
+}
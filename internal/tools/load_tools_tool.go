@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LoadToolName is the name of the tool that loads specialized tools.
+const LoadToolName = "load_tools"
+
+// maxSummaryLength caps each tool summary in the load_tools description.
+const maxSummaryLength = 100
+
+// LoadToolsTool lets the model add specialized tools to the core set it is
+// offered, so their schemas are only sent once a task needs them.
+type LoadToolsTool struct {
+	// Available returns the tools that are not offered yet.
+	Available func() []Tool
+	// Load offers the named tools from the next request on.
+	Load func(names []string)
+}
+
+// Name returns the tool name.
+func (t LoadToolsTool) Name() string {
+	return LoadToolName
+}
+
+// Description returns the tool description for the model, listing what can
+// still be loaded.
+func (t LoadToolsTool) Description() string {
+	var available []Tool
+	if t.Available != nil {
+		available = t.Available()
+	}
+	if len(available) == 0 {
+		return "Load more tools. Every tool is already loaded."
+	}
+	var b strings.Builder
+	b.WriteString("Load specialized tools before using them; they can be called right after. Load only what the task needs. Not loaded yet:")
+	for _, tool := range available {
+		fmt.Fprintf(&b, "\n- %s: %s", tool.Name(), summarizeDescription(tool.Description()))
+	}
+	return b.String()
+}
+
+// Schema returns the JSON schema for load_tools args.
+func (t LoadToolsTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"names": map[string]any{
+				"type":        "string",
+				"description": "Comma-separated tool names from the list, e.g. todo_add, todo_list",
+			},
+		},
+		"required": []string{"names"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t LoadToolsTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute loads the named tools.
+func (t LoadToolsTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Available == nil || t.Load == nil {
+		return nil, errors.New("tool loading is not configured")
+	}
+	raw, err := stringArg(args, "names")
+	if err != nil {
+		return nil, err
+	}
+	available := map[string]bool{}
+	for _, tool := range t.Available() {
+		available[tool.Name()] = true
+	}
+	var names, unknown []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case available[name]:
+			names = append(names, name)
+			available[name] = false
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("cannot load %s: not in the list of tools that are not loaded yet", strings.Join(unknown, ", "))
+	}
+	if len(names) == 0 {
+		return nil, errors.New("names is required")
+	}
+	t.Load(names)
+	return &ToolResult{Output: fmt.Sprintf("Loaded %s. Call %s now.", strings.Join(names, ", "), plural(len(names), "it", "them"))}, nil
+}
+
+// summarizeDescription keeps the first sentence of a tool description.
+func summarizeDescription(description string) string {
+	description = strings.TrimSpace(description)
+	if line, _, ok := strings.Cut(description, "\n"); ok {
+		description = line
+	}
+	if sentence, _, ok := strings.Cut(description, ". "); ok {
+		description = sentence
+	}
+	description = strings.TrimSuffix(description, ".")
+	if runes := []rune(description); len(runes) > maxSummaryLength {
+		description = string(runes[:maxSummaryLength]) + "…"
+	}
+	return description
+}
@@ -0,0 +1,193 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/embeddings"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+	"github.com/neoclaw-ai/neoclaw/internal/rerank"
+)
+
+const (
+	defaultMemorySearchLimit = 10
+	// defaultRerankCandidates matches the [rerank] candidates default.
+	defaultRerankCandidates = 30
+)
+
+// MemorySearchTool finds memory facts and daily log entries by meaning:
+// entries are ranked by embedding similarity to the query, and the closest
+// Candidates are reordered by Reranker when one is configured.
+type MemorySearchTool struct {
+	Store    *memory.Store
+	Embedder embeddings.Embedder
+	// Reranker is optional.
+	Reranker rerank.Reranker
+	// Candidates caps how many embedding matches are reranked; zero uses
+	// the default.
+	Candidates int
+}
+
+type scoredEntry struct {
+	entry memory.LogEntry
+	text  string
+	score float64
+}
+
+// Name returns the tool name.
+func (t MemorySearchTool) Name() string {
+	return "memory_search"
+}
+
+// Description returns the tool description for the model.
+func (t MemorySearchTool) Description() string {
+	return "Search memory facts and daily logs by meaning rather than exact words, e.g. \"what did we decide about the holiday\". Returns the closest entries, best first. Use search_logs instead for exact names, numbers, or patterns."
+}
+
+// Schema returns the JSON schema for memory_search args.
+func (t MemorySearchTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "What to look for, in plain words",
+			},
+			"limit": map[string]any{
+				"type":        "string",
+				"description": "Maximum entries to return (default: 10)",
+			},
+			"from_time": map[string]any{
+				"type":        "string",
+				"description": "Optional RFC3339 timestamp lower bound (inclusive)",
+			},
+			"to_time": map[string]any{
+				"type":        "string",
+				"description": "Optional RFC3339 timestamp upper bound (inclusive, default: now)",
+			},
+		},
+		"required": []string{"query"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t MemorySearchTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute ranks entries against the query and returns TSV output with a
+// score column, best match first.
+func (t MemorySearchTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("memory store is required")
+	}
+	if t.Embedder == nil {
+		return nil, errors.New("embeddings are not configured")
+	}
+	query, err := stringArg(args, "query")
+	if err != nil {
+		return nil, err
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("query is required")
+	}
+	limitRaw, err := optionalStringArg(args, "limit", strconv.Itoa(defaultMemorySearchLimit))
+	if err != nil {
+		return nil, err
+	}
+	limit, err := strconv.Atoi(limitRaw)
+	if err != nil || limit < 1 {
+		return nil, fmt.Errorf("argument limit must be a positive integer")
+	}
+	fromTime, err := optionalRFC3339Arg(args, "from_time", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	toTime, err := optionalRFC3339Arg(args, "to_time", time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := t.Store.Entries(fromTime, toTime)
+	if len(entries) == 0 {
+		return &ToolResult{Output: "No memory entries to search."}, nil
+	}
+	texts := make([]string, 0, len(entries)+1)
+	texts = append(texts, query)
+	for _, entry := range entries {
+		texts = append(texts, searchText(entry))
+	}
+	vectors, err := t.Embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed memory: %w", err)
+	}
+	scored := make([]scoredEntry, len(entries))
+	for i, entry := range entries {
+		scored[i] = scoredEntry{
+			entry: entry,
+			text:  texts[i+1],
+			score: float64(embeddings.Cosine(vectors[0], vectors[i+1])),
+		}
+	}
+	sortScored(scored)
+
+	candidates := t.Candidates
+	if candidates <= 0 {
+		candidates = defaultRerankCandidates
+	}
+	if t.Reranker != nil {
+		t.rerank(ctx, query, scored[:min(len(scored), candidates)])
+	}
+	scored = scored[:min(len(scored), limit)]
+
+	lines := make([]string, 0, len(scored)+1)
+	lines = append(lines, "score\tts\ttags\ttext\tkv")
+	for _, match := range scored {
+		lines = append(lines, strconv.FormatFloat(match.score, 'f', 3, 64)+"\t"+strings.Join(match.entry.MarshalTSV(), "\t"))
+	}
+	return &ToolResult{Output: strings.Join(lines, "\n")}, nil
+}
+
+// rerank reorders candidates in place by the reranker's scores. If the
+// reranker fails, the embedding order is kept so the search still answers.
+func (t MemorySearchTool) rerank(ctx context.Context, query string, candidates []scoredEntry) {
+	documents := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		documents[i] = candidate.text
+	}
+	scores, err := t.Reranker.Rerank(ctx, query, documents)
+	if err != nil {
+		logging.Logger().Warn("rerank failed; using embedding order", "err", err)
+		return
+	}
+	for i := range candidates {
+		candidates[i].score = scores[i]
+	}
+	sortScored(candidates)
+}
+
+// searchText is what gets embedded for an entry: its tags give short notes
+// some context.
+func searchText(entry memory.LogEntry) string {
+	if len(entry.Tags) == 0 {
+		return entry.Text
+	}
+	return strings.Join(entry.Tags, ", ") + ": " + entry.Text
+}
+
+// sortScored orders best first, newest first among ties.
+func sortScored(scored []scoredEntry) {
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].entry.Timestamp.After(scored[j].entry.Timestamp)
+	})
+}
@@ -4,17 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/neoclaw-ai/neoclaw/internal/config"
 	"github.com/neoclaw-ai/neoclaw/internal/memory"
 )
 
 // DailyLogAppendTool appends structured entries to the daily log.
 type DailyLogAppendTool struct {
 	Store *memory.Store
+	// Writes is a config.MemoryWrites* mode; empty means auto.
+	Writes string
 }
 
 // Name returns the tool name.
@@ -22,6 +26,9 @@ func (t DailyLogAppendTool) Name() string {
 	return "daily_log_append"
 }
 
+// WritesMemory marks the tool as persisting to the daily log.
+func (t DailyLogAppendTool) WritesMemory() {}
+
 // Description returns the tool description for the model.
 func (t DailyLogAppendTool) Description() string {
 	return "Append a structured entry to the daily log"
@@ -51,7 +58,12 @@ func (t DailyLogAppendTool) Schema() map[string]any {
 
 // Permission declares default permission behavior for this tool.
 func (t DailyLogAppendTool) Permission() Permission {
-	return AutoApprove
+	return memoryWritePermission(t.Writes)
+}
+
+// SummarizeArgs returns a human-readable approval prompt.
+func (t DailyLogAppendTool) SummarizeArgs(args map[string]any) string {
+	return summarizeMemoryWrite("daily_log_append", args)
 }
 
 // Execute appends a structured entry into the daily log.
@@ -74,11 +86,15 @@ func (t DailyLogAppendTool) Execute(_ context.Context, args map[string]any) (*To
 	if err != nil {
 		return nil, err
 	}
-	if err := t.Store.AppendDailyLog(memory.LogEntry{
+	entry := memory.LogEntry{
 		Tags: tags,
 		Text: text,
 		KV:   kv,
-	}); err != nil {
+	}
+	if t.Writes == config.MemoryWritesQueue {
+		return queueMemoryWrite(t.Store, memory.PendingDailyLog, entry)
+	}
+	if err := t.Store.AppendDailyLog(entry); err != nil {
 		return nil, err
 	}
 	return &ToolResult{Output: "ok"}, nil
@@ -87,6 +103,8 @@ func (t DailyLogAppendTool) Execute(_ context.Context, args map[string]any) (*To
 // MemoryAppendTool appends structured facts to long-term memory.
 type MemoryAppendTool struct {
 	Store *memory.Store
+	// Writes is a config.MemoryWrites* mode; empty means auto.
+	Writes string
 }
 
 // Name returns the tool name.
@@ -94,6 +112,9 @@ func (t MemoryAppendTool) Name() string {
 	return "memory_append"
 }
 
+// WritesMemory marks the tool as persisting to long-term memory.
+func (t MemoryAppendTool) WritesMemory() {}
+
 // Description returns the tool description for the model.
 func (t MemoryAppendTool) Description() string {
 	return "Add a structured fact to long-term memory"
@@ -106,7 +127,7 @@ func (t MemoryAppendTool) Schema() map[string]any {
 		"properties": map[string]any{
 			"tags": map[string]any{
 				"type":        "string",
-				"description": "Comma-separated tags. First tag is the primary topic.",
+				"description": "Comma-separated tags. First tag is the primary topic. Use contact for facts about a person.",
 			},
 			"text": map[string]any{
 				"type":        "string",
@@ -114,7 +135,7 @@ func (t MemoryAppendTool) Schema() map[string]any {
 			},
 			"kv": map[string]any{
 				"type":        "string",
-				"description": "Optional key=value metadata string",
+				"description": "Optional key=value metadata string. Contact facts need name=First_Last and may add relationship=sister and last_interaction=2026-02-28.",
 			},
 			"expires": map[string]any{
 				"type":        "string",
@@ -127,7 +148,12 @@ func (t MemoryAppendTool) Schema() map[string]any {
 
 // Permission declares default permission behavior for this tool.
 func (t MemoryAppendTool) Permission() Permission {
-	return AutoApprove
+	return memoryWritePermission(t.Writes)
+}
+
+// SummarizeArgs returns a human-readable approval prompt.
+func (t MemoryAppendTool) SummarizeArgs(args map[string]any) string {
+	return summarizeMemoryWrite("memory_append", args)
 }
 
 // Execute appends a structured fact to memory.tsv.
@@ -147,6 +173,9 @@ func (t MemoryAppendTool) Execute(_ context.Context, args map[string]any) (*Tool
 	if err != nil {
 		return nil, err
 	}
+	if slices.Contains(tags, memory.ContactTag) && memory.ContactName(kv) == "" {
+		return nil, errors.New("contact facts need kv name=<name>, with underscores for spaces")
+	}
 	expires, err := optionalStringArg(args, "expires", "")
 	if err != nil {
 		return nil, err
@@ -163,12 +192,37 @@ func (t MemoryAppendTool) Execute(_ context.Context, args map[string]any) (*Tool
 		Text: text,
 		KV:   kv,
 	}
+	if t.Writes == config.MemoryWritesQueue {
+		return queueMemoryWrite(t.Store, memory.PendingMemory, entry)
+	}
 	if err := t.Store.AppendMemory(entry); err != nil {
 		return nil, err
 	}
 	return &ToolResult{Output: fmt.Sprintf("%s\t%s", strings.Join(entry.Tags, ","), entry.Text)}, nil
 }
 
+func memoryWritePermission(writes string) Permission {
+	if writes == config.MemoryWritesApprove {
+		return RequiresApproval
+	}
+	return AutoApprove
+}
+
+func summarizeMemoryWrite(name string, args map[string]any) string {
+	tags, _ := args["tags"].(string)
+	text, _ := args["text"].(string)
+	return fmt.Sprintf("%s [%s]: %s", name, strings.TrimSpace(tags), strings.TrimSpace(text))
+}
+
+// queueMemoryWrite holds a write for review in /memory pending instead of
+// saving it.
+func queueMemoryWrite(store *memory.Store, target string, entry memory.LogEntry) (*ToolResult, error) {
+	if err := store.QueuePending(target, entry); err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: "queued for the user's review; it is not saved until they approve it with /memory pending"}, nil
+}
+
 // MemoryTagsTool lists first-tag counts across memory facts.
 type MemoryTagsTool struct {
 	Store *memory.Store
@@ -230,6 +284,200 @@ func (t MemoryTagsTool) Execute(_ context.Context, _ map[string]any) (*ToolResul
 	return &ToolResult{Output: out.String()}, nil
 }
 
+// MemoryRetagTool renames a tag across memory facts and daily logs.
+type MemoryRetagTool struct {
+	Store *memory.Store
+}
+
+// Name returns the tool name.
+func (t MemoryRetagTool) Name() string {
+	return "memory_retag"
+}
+
+// WritesMemory marks the tool as rewriting long-term memory and the daily log.
+func (t MemoryRetagTool) WritesMemory() {}
+
+// Description returns the tool description for the model.
+func (t MemoryRetagTool) Description() string {
+	return "Rename a tag on every memory fact and daily log entry, e.g. locations to location. The newest fact per first tag stays active, so renaming a topic onto an existing one supersedes the older facts."
+}
+
+// Schema returns the JSON schema for memory_retag args.
+func (t MemoryRetagTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"from": map[string]any{
+				"type":        "string",
+				"description": "Tag to rename",
+			},
+			"to": map[string]any{
+				"type":        "string",
+				"description": "New tag name; may be a tag that already exists",
+			},
+		},
+		"required": []string{"from", "to"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t MemoryRetagTool) Permission() Permission {
+	return RequiresApproval
+}
+
+// SummarizeArgs returns a human-readable approval prompt.
+func (t MemoryRetagTool) SummarizeArgs(args map[string]any) string {
+	from, _ := args["from"].(string)
+	to, _ := args["to"].(string)
+	return fmt.Sprintf("memory_retag: rename tag %s to %s in memory and daily logs", strings.TrimSpace(from), strings.TrimSpace(to))
+}
+
+// Execute renames the tag and reports how many entries changed.
+func (t MemoryRetagTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("memory store is required")
+	}
+	from, err := stringArg(args, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := stringArg(args, "to")
+	if err != nil {
+		return nil, err
+	}
+	result, err := t.Store.Retag([]string{from}, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: formatRetagResult(result)}, nil
+}
+
+// MemoryMergeTool folds several tags into one across memory facts and daily
+// logs.
+type MemoryMergeTool struct {
+	Store *memory.Store
+}
+
+// Name returns the tool name.
+func (t MemoryMergeTool) Name() string {
+	return "memory_merge"
+}
+
+// WritesMemory marks the tool as rewriting long-term memory and the daily log.
+func (t MemoryMergeTool) WritesMemory() {}
+
+// Description returns the tool description for the model.
+func (t MemoryMergeTool) Description() string {
+	return "Merge near-duplicate tags into one on every memory fact and daily log entry, e.g. loc, locations, and city into location. The newest fact per first tag stays active afterwards."
+}
+
+// Schema returns the JSON schema for memory_merge args.
+func (t MemoryMergeTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"tags": map[string]any{
+				"type":        "string",
+				"description": "Comma-separated tags to merge away",
+			},
+			"into": map[string]any{
+				"type":        "string",
+				"description": "Tag to keep",
+			},
+		},
+		"required": []string{"tags", "into"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t MemoryMergeTool) Permission() Permission {
+	return RequiresApproval
+}
+
+// SummarizeArgs returns a human-readable approval prompt.
+func (t MemoryMergeTool) SummarizeArgs(args map[string]any) string {
+	tags, _ := args["tags"].(string)
+	into, _ := args["into"].(string)
+	return fmt.Sprintf("memory_merge: merge tags [%s] into %s in memory and daily logs", strings.TrimSpace(tags), strings.TrimSpace(into))
+}
+
+// Execute merges the tags and reports how many entries changed.
+func (t MemoryMergeTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("memory store is required")
+	}
+	tags, err := parseTagsArg(args, "tags")
+	if err != nil {
+		return nil, err
+	}
+	into, err := stringArg(args, "into")
+	if err != nil {
+		return nil, err
+	}
+	result, err := t.Store.Retag(tags, into)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: formatRetagResult(result)}, nil
+}
+
+func formatRetagResult(result memory.RetagResult) string {
+	return fmt.Sprintf("retagged %d memory facts and %d daily log entries", result.Facts, result.DailyEntries)
+}
+
+// ContactLookupTool looks up people in the contact book.
+type ContactLookupTool struct {
+	Store *memory.Store
+}
+
+// Name returns the tool name.
+func (t ContactLookupTool) Name() string {
+	return "contact_lookup"
+}
+
+// Description returns the tool description for the model.
+func (t ContactLookupTool) Description() string {
+	return "Look up people in the contact book by name, relationship, or detail"
+}
+
+// Schema returns the JSON schema for contact_lookup args.
+func (t ContactLookupTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "Text to match, e.g. Alice, sister, or dentist; omit to list everyone",
+			},
+		},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t ContactLookupTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute returns matching contacts, one per line.
+func (t ContactLookupTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("memory store is required")
+	}
+	query, err := optionalStringArg(args, "query", "")
+	if err != nil {
+		return nil, err
+	}
+	contacts := memory.FindContacts(t.Store.Contacts(time.Now()), query)
+	if len(contacts) == 0 {
+		return &ToolResult{Output: "No matching contacts."}, nil
+	}
+	lines := make([]string, 0, len(contacts))
+	for _, contact := range contacts {
+		lines = append(lines, contact.Format())
+	}
+	return &ToolResult{Output: strings.Join(lines, "\n")}, nil
+}
+
 // SearchLogsTool searches memory entries and daily logs using regex matching.
 type SearchLogsTool struct {
 	Store *memory.Store
@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/embeddings"
 	"github.com/neoclaw-ai/neoclaw/internal/memory"
 )
 
@@ -64,6 +66,32 @@ func TestDailyLogAppendToolRejectsSummaryTag(t *testing.T) {
 	}
 }
 
+func TestMemoryWriteModes(t *testing.T) {
+	store := mustNewMemoryStore(t, t.TempDir())
+
+	if (MemoryAppendTool{Store: store}).Permission() != AutoApprove {
+		t.Fatalf("expected auto mode to write without approval")
+	}
+	if (DailyLogAppendTool{Store: store, Writes: config.MemoryWritesApprove}).Permission() != RequiresApproval {
+		t.Fatalf("expected approve mode to require approval")
+	}
+
+	tool := MemoryAppendTool{Store: store, Writes: config.MemoryWritesQueue}
+	res, err := tool.Execute(context.Background(), map[string]any{"tags": "diet", "text": "Vegetarian"})
+	if err != nil {
+		t.Fatalf("queue memory write: %v", err)
+	}
+	if !strings.Contains(res.Output, "/memory pending") {
+		t.Fatalf("expected queued output, got %q", res.Output)
+	}
+	if len(store.ActiveFacts(time.Now())) != 0 {
+		t.Fatalf("expected queued fact not to be written")
+	}
+	if pending, err := store.Pending(); err != nil || len(pending) != 1 || pending[0].Target != memory.PendingMemory {
+		t.Fatalf("expected one queued fact, got %#v err=%v", pending, err)
+	}
+}
+
 func TestMemoryAppendToolAddsExpiresEpoch(t *testing.T) {
 	memoryDir := t.TempDir()
 	store := mustNewMemoryStore(t, memoryDir)
@@ -125,6 +153,41 @@ func TestMemoryTagsToolFormatsSortedCounts(t *testing.T) {
 	}
 }
 
+func TestMemoryRetagAndMergeTools(t *testing.T) {
+	store := mustNewMemoryStore(t, t.TempDir())
+	for _, tag := range []string{"locations", "loc", "city", "diet"} {
+		if err := store.AppendMemory(memory.LogEntry{Tags: []string{tag}, Text: "fact about " + tag, KV: "-"}); err != nil {
+			t.Fatalf("append memory fact: %v", err)
+		}
+	}
+
+	retag := MemoryRetagTool{Store: store}
+	if retag.Permission() != RequiresApproval {
+		t.Fatal("expected memory_retag to require approval")
+	}
+	res, err := retag.Execute(context.Background(), map[string]any{"from": "locations", "to": "location"})
+	if err != nil {
+		t.Fatalf("memory retag: %v", err)
+	}
+	if res.Output != "retagged 1 memory facts and 0 daily log entries" {
+		t.Fatalf("unexpected retag output %q", res.Output)
+	}
+
+	merge := MemoryMergeTool{Store: store}
+	if merge.Permission() != RequiresApproval {
+		t.Fatal("expected memory_merge to require approval")
+	}
+	if got := merge.SummarizeArgs(map[string]any{"tags": "loc,city", "into": "location"}); got != "memory_merge: merge tags [loc,city] into location in memory and daily logs" {
+		t.Fatalf("unexpected summary %q", got)
+	}
+	if _, err := merge.Execute(context.Background(), map[string]any{"tags": "loc, City", "into": "location"}); err != nil {
+		t.Fatalf("memory merge: %v", err)
+	}
+	if got := store.FactTags(); len(got) != 2 || got["location"] != 3 || got["diet"] != 1 {
+		t.Fatalf("expected location and diet left, got %#v", got)
+	}
+}
+
 func TestSearchLogsToolExecuteFormatsTSVAndAppliesTimeBounds(t *testing.T) {
 	memoryDir := t.TempDir()
 	store := mustNewMemoryStore(t, memoryDir)
@@ -278,3 +341,95 @@ func mustNewMemoryStore(t *testing.T, dir string) *memory.Store {
 	}
 	return store
 }
+
+func TestContactLookupToolAndContactValidation(t *testing.T) {
+	store := mustNewMemoryStore(t, t.TempDir())
+	appendTool := MemoryAppendTool{Store: store}
+
+	if _, err := appendTool.Execute(context.Background(), map[string]any{
+		"tags": "contact",
+		"text": "Sister, lives in Berlin",
+	}); err == nil {
+		t.Fatalf("expected contact fact without name to fail")
+	}
+	if _, err := appendTool.Execute(context.Background(), map[string]any{
+		"tags": "contact",
+		"text": "Lives in Berlin",
+		"kv":   "name=Anna relationship=sister",
+	}); err != nil {
+		t.Fatalf("append contact: %v", err)
+	}
+
+	lookup := ContactLookupTool{Store: store}
+	res, err := lookup.Execute(context.Background(), map[string]any{"query": "sister"})
+	if err != nil {
+		t.Fatalf("contact lookup: %v", err)
+	}
+	if res.Output != "Anna (sister): Lives in Berlin" {
+		t.Fatalf("unexpected lookup output %q", res.Output)
+	}
+	res, err = lookup.Execute(context.Background(), map[string]any{"query": "bob"})
+	if err != nil {
+		t.Fatalf("contact lookup: %v", err)
+	}
+	if res.Output != "No matching contacts." {
+		t.Fatalf("unexpected empty lookup output %q", res.Output)
+	}
+}
+
+type reverseReranker struct {
+	documents []string
+}
+
+func (r *reverseReranker) Rerank(_ context.Context, _ string, documents []string) ([]float64, error) {
+	r.documents = documents
+	scores := make([]float64, len(documents))
+	for i := range documents {
+		scores[i] = float64(i)
+	}
+	return scores, nil
+}
+
+func TestMemorySearchToolRanksByMeaningAndReranksCandidates(t *testing.T) {
+	store := mustNewMemoryStore(t, t.TempDir())
+	for i, text := range []string{
+		"Booked the dentist appointment for Maria",
+		"Paid the electricity bill",
+		"Maria's dentist moved to Tuesday",
+	} {
+		if err := store.AppendDailyLog(memory.LogEntry{
+			Timestamp: time.Date(2026, 2, 16+i, 9, 0, 0, 0, time.UTC),
+			Tags:      []string{"event"},
+			Text:      text,
+		}); err != nil {
+			t.Fatalf("append daily log: %v", err)
+		}
+	}
+
+	tool := MemorySearchTool{Store: store, Embedder: embeddings.NewLexical(0)}
+	res, err := tool.Execute(context.Background(), map[string]any{"query": "when is Maria at the dentist", "limit": "2"})
+	if err != nil {
+		t.Fatalf("memory search: %v", err)
+	}
+	lines := strings.Split(res.Output, "\n")
+	if len(lines) != 3 || lines[0] != "score\tts\ttags\ttext\tkv" {
+		t.Fatalf("unexpected output %q", res.Output)
+	}
+	for _, line := range lines[1:] {
+		if !strings.Contains(line, "dentist") {
+			t.Fatalf("expected dentist entries first, got %q", res.Output)
+		}
+	}
+
+	// Only the closest two candidates are reranked; the reranker's order wins.
+	reranker := &reverseReranker{}
+	tool.Reranker = reranker
+	tool.Candidates = 2
+	res, err = tool.Execute(context.Background(), map[string]any{"query": "when is Maria at the dentist", "limit": "1"})
+	if err != nil {
+		t.Fatalf("memory search with rerank: %v", err)
+	}
+	if len(reranker.documents) != 2 || !strings.Contains(res.Output, reranker.documents[1][len("event: "):]) {
+		t.Fatalf("expected reranker's top pick, got %q from %q", res.Output, reranker.documents)
+	}
+}
@@ -0,0 +1,243 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+	"github.com/neoclaw-ai/neoclaw/internal/memory"
+)
+
+// defaultMetricReportDays is how far back metric_report looks by default.
+const defaultMetricReportDays = 30
+
+// TrackMetricTool records one reading of a numeric series in the daily log.
+type TrackMetricTool struct {
+	Store *memory.Store
+	// Writes is a config.MemoryWrites* mode; empty means auto.
+	Writes string
+	now    func() time.Time
+}
+
+// Name returns the tool name.
+func (t TrackMetricTool) Name() string {
+	return "track_metric"
+}
+
+// WritesMemory marks the tool as persisting to the daily log.
+func (t TrackMetricTool) WritesMemory() {}
+
+// Description returns the tool description for the model.
+func (t TrackMetricTool) Description() string {
+	return "Record a reading of a numeric series the user tracks, such as weight, sleep hours, or workout minutes, so metric_report can show trends"
+}
+
+// Schema returns the JSON schema for track_metric args.
+func (t TrackMetricTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"metric": map[string]any{
+				"type":        "string",
+				"description": "Series name, e.g. weight, sleep, or workout_minutes. Reuse names the user already tracks.",
+			},
+			"value": map[string]any{
+				"type":        "string",
+				"description": "The reading as a plain number, e.g. 72.5",
+			},
+			"unit": map[string]any{
+				"type":        "string",
+				"description": "Optional unit, e.g. kg, hours, or minutes",
+			},
+			"category": map[string]any{
+				"type":        "string",
+				"description": "Optional daily log type for retention and privacy rules, e.g. health or fitness; defaults to metric",
+			},
+			"date": map[string]any{
+				"type":        "string",
+				"description": "Optional day the reading is for as YYYY-MM-DD; defaults to today",
+			},
+			"note": map[string]any{
+				"type":        "string",
+				"description": "Optional short note",
+			},
+		},
+		"required": []string{"metric", "value"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t TrackMetricTool) Permission() Permission {
+	return memoryWritePermission(t.Writes)
+}
+
+// SummarizeArgs returns a human-readable approval prompt.
+func (t TrackMetricTool) SummarizeArgs(args map[string]any) string {
+	metric, _ := args["metric"].(string)
+	value, _ := args["value"].(string)
+	unit, _ := args["unit"].(string)
+	return strings.TrimSpace(fmt.Sprintf("track_metric %s: %s %s", strings.TrimSpace(metric), strings.TrimSpace(value), strings.TrimSpace(unit)))
+}
+
+// Execute appends the reading to the daily log.
+func (t TrackMetricTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("memory store is required")
+	}
+	metric, err := stringArg(args, "metric")
+	if err != nil {
+		return nil, err
+	}
+	rawValue, err := stringArg(args, "value")
+	if err != nil {
+		return nil, err
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(rawValue), 64)
+	if err != nil {
+		return nil, fmt.Errorf("argument value must be a number, got %q", rawValue)
+	}
+	unit, err := optionalStringArg(args, "unit", "")
+	if err != nil {
+		return nil, err
+	}
+	category, err := optionalStringArg(args, "category", memory.MetricTag)
+	if err != nil {
+		return nil, err
+	}
+	if memory.NormalizeTags([]string{category})[0] == "summary" {
+		return nil, errors.New("argument category cannot be summary")
+	}
+	date, err := optionalStringArg(args, "date", "")
+	if err != nil {
+		return nil, err
+	}
+	note, err := optionalStringArg(args, "note", "")
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+	timestamp := now()
+	if date != "" {
+		day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("argument date must be YYYY-MM-DD, got %q", date)
+		}
+		if day.After(timestamp) {
+			return nil, errors.New("argument date must not be in the future")
+		}
+		// Keep the time of day so readings for the same day stay in order.
+		timestamp = time.Date(day.Year(), day.Month(), day.Day(), timestamp.Hour(), timestamp.Minute(), timestamp.Second(), 0, time.Local)
+	}
+
+	entry := memory.MetricEntry(category, memory.Reading{
+		Timestamp: timestamp,
+		Name:      metric,
+		Value:     value,
+		Unit:      unit,
+	}, note)
+	if t.Writes == config.MemoryWritesQueue {
+		return queueMemoryWrite(t.Store, memory.PendingDailyLog, entry)
+	}
+	if err := t.Store.AppendDailyLog(entry); err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: fmt.Sprintf("tracked %s on %s", entry.Text, timestamp.Format("2006-01-02"))}, nil
+}
+
+// MetricReportTool summarizes the trend of a tracked series.
+type MetricReportTool struct {
+	Store *memory.Store
+	now   func() time.Time
+}
+
+// Name returns the tool name.
+func (t MetricReportTool) Name() string {
+	return "metric_report"
+}
+
+// Description returns the tool description for the model.
+func (t MetricReportTool) Description() string {
+	return "Summarize a tracked series over recent days: latest value, average, range, and change. Omit metric to list tracked series."
+}
+
+// Schema returns the JSON schema for metric_report args.
+func (t MetricReportTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"metric": map[string]any{
+				"type":        "string",
+				"description": "Series name as recorded with track_metric; omit to list all series",
+			},
+			"days": map[string]any{
+				"type":        "string",
+				"description": "How many days back to look; defaults to 30",
+			},
+			"daily": map[string]any{
+				"type":        "string",
+				"description": "How to combine several readings on one day: avg (default, for weight or sleep) or sum (for workouts or steps)",
+			},
+		},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t MetricReportTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute summarizes the series or lists tracked series.
+func (t MetricReportTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("memory store is required")
+	}
+	metric, err := optionalStringArg(args, "metric", "")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(metric) == "" {
+		names := t.Store.MetricNames()
+		if len(names) == 0 {
+			return &ToolResult{Output: "No metrics tracked yet."}, nil
+		}
+		return &ToolResult{Output: "Tracked metrics: " + strings.Join(names, ", ")}, nil
+	}
+	rawDays, err := optionalStringArg(args, "days", strconv.Itoa(defaultMetricReportDays))
+	if err != nil {
+		return nil, err
+	}
+	days, err := strconv.Atoi(strings.TrimSpace(rawDays))
+	if err != nil || days < 1 {
+		return nil, fmt.Errorf("argument days must be a positive whole number, got %q", rawDays)
+	}
+	daily, err := optionalStringArg(args, "daily", "avg")
+	if err != nil {
+		return nil, err
+	}
+	daily = strings.ToLower(strings.TrimSpace(daily))
+	if daily != "avg" && daily != "sum" {
+		return nil, fmt.Errorf("argument daily must be avg or sum, got %q", daily)
+	}
+
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+	current := now()
+	year, month, day := current.In(time.Local).Date()
+	from := time.Date(year, month, day, 0, 0, 0, 0, time.Local).AddDate(0, 0, -(days - 1))
+	readings := t.Store.Readings(metric, from, current)
+	name := memory.NormalizeMetricName(metric)
+	if len(readings) == 0 {
+		return &ToolResult{Output: fmt.Sprintf("No readings for %s in the last %d days.", name, days)}, nil
+	}
+	unit := readings[len(readings)-1].Unit
+	return &ToolResult{Output: memory.SummarizeTrend(name, unit, memory.DailyValues(readings, daily == "sum"), current)}, nil
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+func TestMetricToolsTrackAndReport(t *testing.T) {
+	store := mustNewMemoryStore(t, t.TempDir())
+	now := time.Date(2026, 3, 14, 20, 0, 0, 0, time.Local)
+	track := TrackMetricTool{Store: store, now: func() time.Time { return now }}
+
+	tracked, err := track.Execute(context.Background(), map[string]any{
+		"metric":   "Weight",
+		"value":    "72.5",
+		"unit":     "kg",
+		"category": "health",
+		"date":     "2026-03-13",
+	})
+	if err != nil {
+		t.Fatalf("track_metric: %v", err)
+	}
+	if tracked.Output != "tracked weight: 72.5 kg on 2026-03-13" {
+		t.Fatalf("unexpected track output %q", tracked.Output)
+	}
+	if _, err := track.Execute(context.Background(), map[string]any{"metric": "weight", "value": "72", "unit": "kg"}); err != nil {
+		t.Fatalf("track_metric today: %v", err)
+	}
+	entries, err := store.GetDailyLogs(now.AddDate(0, 0, -2), now)
+	if err != nil || len(entries) != 2 || entries[0].Tags[0] != "health" || entries[1].Tags[0] != "metric" {
+		t.Fatalf("unexpected daily log entries %#v %v", entries, err)
+	}
+	for _, args := range []map[string]any{
+		{"metric": "weight", "value": "heavy"},
+		{"metric": "weight", "value": "72", "date": "2026-03-20"},
+	} {
+		if _, err := track.Execute(context.Background(), args); err == nil {
+			t.Fatalf("expected error for %#v", args)
+		}
+	}
+
+	report := MetricReportTool{Store: store, now: func() time.Time { return now }}
+	listed, err := report.Execute(context.Background(), map[string]any{})
+	if err != nil || listed.Output != "Tracked metrics: weight" {
+		t.Fatalf("unexpected metric list %#v %v", listed, err)
+	}
+	summary, err := report.Execute(context.Background(), map[string]any{"metric": "weight", "days": "7"})
+	if err != nil {
+		t.Fatalf("metric_report: %v", err)
+	}
+	if !strings.HasPrefix(summary.Output, "weight: latest 72 kg on 2026-03-14\n2 days from 2026-03-13") || !strings.Contains(summary.Output, "Change since 2026-03-13: -0.5 kg") {
+		t.Fatalf("unexpected report %q", summary.Output)
+	}
+	if _, err := report.Execute(context.Background(), map[string]any{"metric": "weight", "daily": "max"}); err == nil {
+		t.Fatalf("expected invalid daily mode to fail")
+	}
+}
+
+func TestTrackMetricQueuesWhenConfigured(t *testing.T) {
+	store := mustNewMemoryStore(t, t.TempDir())
+	tool := TrackMetricTool{Store: store, Writes: config.MemoryWritesQueue}
+	if tool.Permission() != AutoApprove {
+		t.Fatalf("expected queued writes to auto-approve")
+	}
+	if (TrackMetricTool{Writes: config.MemoryWritesApprove}).Permission() != RequiresApproval {
+		t.Fatalf("expected approve mode to require approval")
+	}
+
+	result, err := tool.Execute(context.Background(), map[string]any{"metric": "sleep", "value": "7", "unit": "hours"})
+	if err != nil {
+		t.Fatalf("track_metric: %v", err)
+	}
+	if !strings.HasPrefix(result.Output, "queued") {
+		t.Fatalf("unexpected output %q", result.Output)
+	}
+	if names := store.MetricNames(); len(names) != 0 {
+		t.Fatalf("expected nothing written before approval, got %#v", names)
+	}
+}
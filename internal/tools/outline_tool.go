@@ -0,0 +1,249 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// OutlineFileTool lists the functions, types, and classes in a source file
+// with their line numbers.
+type OutlineFileTool struct {
+	WorkspaceDir string
+}
+
+// Name returns the tool name.
+func (t OutlineFileTool) Name() string {
+	return "outline_file"
+}
+
+// Description returns the tool description for the model.
+func (t OutlineFileTool) Description() string {
+	return "Outline a Go, Python, or JavaScript/TypeScript source file: its functions, methods, types, and classes with line numbers. Use this to find your way around a large file before reading parts of it."
+}
+
+// Schema returns the JSON schema for outline_file args.
+func (t OutlineFileTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Absolute path or path relative to workspace",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t OutlineFileTool) Permission() Permission {
+	return AutoApprove
+}
+
+// outlineEntry is one outline line.
+type outlineEntry struct {
+	line  int
+	depth int
+	text  string
+}
+
+// Execute outlines the file using the parser for its language.
+func (t OutlineFileTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	pathArg, err := stringArg(args, "path")
+	if err != nil {
+		return nil, err
+	}
+	path, err := resolveInputPath(t.WorkspaceDir, projectPath(ctx, pathArg))
+	if err != nil {
+		return nil, err
+	}
+	content, err := store.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	if isBinary([]byte(content)) {
+		return nil, fmt.Errorf("file %s appears to be binary", path)
+	}
+
+	var entries []outlineEntry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		entries, err = outlineGo(content)
+		if err != nil {
+			return nil, err
+		}
+	case ".py":
+		entries = outlineLines(content, pythonOutline)
+	case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx":
+		entries = outlineLines(content, jsOutline)
+	default:
+		return nil, fmt.Errorf("outline_file supports .go, .py, .js, and .ts files, not %s", filepath.Base(path))
+	}
+	if len(entries) == 0 {
+		return &ToolResult{Output: "No functions, types, or classes found."}, nil
+	}
+
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("%5d  %s%s", entry.line, strings.Repeat("  ", entry.depth), entry.text))
+	}
+	return &ToolResult{Output: strings.Join(lines, "\n")}, nil
+}
+
+// outlineGo lists top-level declarations with their signatures, plus
+// interface methods.
+func outlineGo(content string) ([]outlineEntry, error) {
+	fset := token.NewFileSet()
+	// A file with syntax errors still yields the declarations before them.
+	file, err := parser.ParseFile(fset, "", content, parser.SkipObjectResolution)
+	if file == nil {
+		return nil, fmt.Errorf("parse go: %w", err)
+	}
+
+	var entries []outlineEntry
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			signature := *decl
+			signature.Body = nil
+			signature.Doc = nil
+			entries = append(entries, outlineEntry{line: fset.Position(decl.Pos()).Line, text: printGo(fset, &signature)})
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					entries = append(entries, outlineEntry{line: fset.Position(spec.Pos()).Line, text: "type " + spec.Name.Name + " " + goTypeKind(spec.Type)})
+					if iface, ok := spec.Type.(*ast.InterfaceType); ok {
+						for _, method := range iface.Methods.List {
+							if len(method.Names) == 0 {
+								continue
+							}
+							entries = append(entries, outlineEntry{line: fset.Position(method.Pos()).Line, depth: 1, text: method.Names[0].Name + strings.TrimPrefix(printGo(fset, method.Type), "func")})
+						}
+					}
+				case *ast.ValueSpec:
+					names := make([]string, 0, len(spec.Names))
+					for _, name := range spec.Names {
+						names = append(names, name.Name)
+					}
+					entries = append(entries, outlineEntry{line: fset.Position(spec.Pos()).Line, text: decl.Tok.String() + " " + strings.Join(names, ", ")})
+				}
+			}
+		}
+	}
+	return entries, nil
+}
+
+func goTypeKind(expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	case *ast.FuncType:
+		return "func"
+	case *ast.MapType:
+		return "map"
+	case *ast.ArrayType:
+		return "slice"
+	case *ast.ChanType:
+		return "chan"
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+func printGo(fset *token.FileSet, node any) string {
+	var b bytes.Buffer
+	if err := printer.Fprint(&b, fset, node); err != nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// lineOutliner returns the outline text for a source line and whether the
+// line declares something.
+type lineOutliner func(line string) (string, bool)
+
+var (
+	pythonDecl = regexp.MustCompile(`^\s*((?:async\s+)?def\s+\w+\s*\([^)]*\)?|class\s+\w+)`)
+
+	jsFunction = regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*(\w*)\s*\(`)
+	jsClass    = regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:abstract\s+)?(class|interface|enum)\s+(\w+)`)
+	jsType     = regexp.MustCompile(`^\s*(?:export\s+)?type\s+(\w+)\s*(?:<[^=]*>)?\s*=`)
+	jsArrow    = regexp.MustCompile(`^\s*(?:export\s+)?(?:const|let|var)\s+(\w+)\s*(?::[^=]+)?=\s*(?:async\s+)?(?:function\b|\([^)]*\)\s*(?::[^=]+)?=>|\w+\s*=>)`)
+	jsMethod   = regexp.MustCompile(`^\s+(?:(?:public|private|protected|static|async|readonly|override|get|set)\s+)*\*?(\w+)\s*\([^;]*\)\s*(?::[^{;]+)?\{\s*$`)
+)
+
+// jsKeywords look like method calls to jsMethod but are control flow.
+var jsKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true,
+	"with": true, "return": true, "function": true, "else": true,
+}
+
+func pythonOutline(line string) (string, bool) {
+	match := pythonDecl.FindStringSubmatch(line)
+	if match == nil {
+		return "", false
+	}
+	text := match[1]
+	if strings.Contains(text, "(") && !strings.HasSuffix(text, ")") {
+		text += "...)"
+	}
+	return strings.Join(strings.Fields(text), " "), true
+}
+
+func jsOutline(line string) (string, bool) {
+	if match := jsFunction.FindStringSubmatch(line); match != nil {
+		name := match[1]
+		if name == "" {
+			name = "(anonymous)"
+		}
+		return "function " + name, true
+	}
+	if match := jsClass.FindStringSubmatch(line); match != nil {
+		return match[1] + " " + match[2], true
+	}
+	if match := jsType.FindStringSubmatch(line); match != nil {
+		return "type " + match[1], true
+	}
+	if match := jsArrow.FindStringSubmatch(line); match != nil {
+		return "function " + match[1], true
+	}
+	if match := jsMethod.FindStringSubmatch(line); match != nil && !jsKeywords[match[1]] {
+		return "method " + match[1], true
+	}
+	return "", false
+}
+
+// outlineLines applies outline to each line and nests entries by indentation.
+func outlineLines(content string, outline lineOutliner) []outlineEntry {
+	var entries []outlineEntry
+	// indents holds the indentation of the enclosing entries.
+	var indents []int
+	for i, line := range strings.Split(content, "\n") {
+		text, ok := outline(line)
+		if !ok {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		for len(indents) > 0 && indents[len(indents)-1] >= indent {
+			indents = indents[:len(indents)-1]
+		}
+		entries = append(entries, outlineEntry{line: i + 1, depth: len(indents), text: text})
+		indents = append(indents, indent)
+	}
+	return entries
+}
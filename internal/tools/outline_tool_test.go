@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestOutlineFile_Go(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{"server.go": `package server
+
+const DefaultPort = 8080
+
+// Server serves.
+type Server struct {
+	port int
+}
+
+type Handler interface {
+	Handle(req string) (string, error)
+}
+
+func New(port int) *Server {
+	return &Server{port: port}
+}
+
+func (s *Server) Start() error {
+	return nil
+}
+`})
+
+	res, err := (OutlineFileTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"path": "server.go"})
+	if err != nil {
+		t.Fatalf("outline: %v", err)
+	}
+	want := strings.Join([]string{
+		"    3  const DefaultPort",
+		"    6  type Server struct",
+		"   10  type Handler interface",
+		"   11    Handle(req string) (string, error)",
+		"   14  func New(port int) *Server",
+		"   18  func (s *Server) Start() error",
+	}, "\n")
+	if res.Output != want {
+		t.Fatalf("unexpected outline:\n%s", res.Output)
+	}
+}
+
+func TestOutlineFile_PythonAndJS(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{
+		"app.py": `import os
+
+class Store:
+    def __init__(self, path):
+        self.path = path
+
+    async def load(self,
+                   key):
+        pass
+
+def main():
+    if True:
+        pass
+`,
+		"app.ts": `export class Client {
+  private async fetch(url: string): Promise<string> {
+    if (url) {
+      return url;
+    }
+  }
+}
+
+export const handler = async (event) => {
+  return event;
+};
+
+function helper(a, b) {
+  return a + b;
+}
+`,
+	})
+
+	res, err := (OutlineFileTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"path": "app.py"})
+	if err != nil {
+		t.Fatalf("outline python: %v", err)
+	}
+	want := "    3  class Store\n    4    def __init__(self, path)\n    7    async def load(self,...)\n   11  def main()"
+	if res.Output != want {
+		t.Fatalf("unexpected python outline:\n%s", res.Output)
+	}
+
+	res, err = (OutlineFileTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"path": "app.ts"})
+	if err != nil {
+		t.Fatalf("outline ts: %v", err)
+	}
+	want = "    1  class Client\n    2    method fetch\n    9  function handler\n   13  function helper"
+	if res.Output != want {
+		t.Fatalf("unexpected ts outline:\n%s", res.Output)
+	}
+}
+
+func TestOutlineFile_RejectsUnsupportedFiles(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{"notes.txt": "hello"})
+	if _, err := (OutlineFileTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"path": "notes.txt"}); err == nil || !strings.Contains(err.Error(), "supports") {
+		t.Fatalf("expected unsupported file error, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/patch"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// ApplyPatchTool applies a unified diff to files under the workspace.
+type ApplyPatchTool struct {
+	WorkspaceDir string
+	SecurityMode string
+}
+
+// Name returns the tool name.
+func (t ApplyPatchTool) Name() string {
+	return "apply_patch"
+}
+
+// Description returns the tool description for the model.
+func (t ApplyPatchTool) Description() string {
+	return "Edit files by applying a unified diff (git diff or diff -u format, with --- and +++ headers and @@ hunks). Use /dev/null to create or delete a file. Prefer this over rewriting a whole file with write_file. Nothing is written unless every hunk applies."
+}
+
+// Schema returns the JSON schema for apply_patch args.
+func (t ApplyPatchTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"patch": map[string]any{
+				"type":        "string",
+				"description": "Unified diff; paths are relative to workspace or absolute under workspace",
+			},
+		},
+		"required": []string{"patch"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t ApplyPatchTool) Permission() Permission {
+	return AutoApprove
+}
+
+// SummarizeArgs lists the files a patch touches.
+func (t ApplyPatchTool) SummarizeArgs(args map[string]any) string {
+	raw, _ := args["patch"].(string)
+	files, err := patch.Parse(raw)
+	if err != nil {
+		return "apply_patch: <invalid diff>"
+	}
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, patchTarget(file))
+	}
+	return "apply_patch: " + strings.Join(paths, ", ")
+}
+
+// patchedFile is the outcome of applying one file diff in memory.
+type patchedFile struct {
+	diff    patch.FileDiff
+	oldPath string
+	newPath string
+	content string
+	results []patch.Result
+}
+
+// Execute checks every path, applies every hunk in memory, and writes only
+// when all of them applied.
+func (t ApplyPatchTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	raw, err := stringArg(args, "patch")
+	if err != nil {
+		return nil, err
+	}
+	files, err := patch.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Content already patched earlier in this diff, by resolved path.
+	pending := map[string]string{}
+	var patched []patchedFile
+	rejected := false
+	for _, diff := range files {
+		file := patchedFile{diff: diff}
+		if diff.OldPath != "" {
+			if file.oldPath, err = resolveWritePath(t.WorkspaceDir, t.SecurityMode, projectPath(ctx, diff.OldPath)); err != nil {
+				return nil, err
+			}
+		}
+		if diff.NewPath != "" {
+			if file.newPath, err = resolveWritePath(t.WorkspaceDir, t.SecurityMode, projectPath(ctx, diff.NewPath)); err != nil {
+				return nil, err
+			}
+		}
+
+		current := ""
+		if file.oldPath == "" {
+			if _, err := os.Lstat(file.newPath); err == nil {
+				return nil, fmt.Errorf("cannot create %s: it already exists", diff.NewPath)
+			}
+		} else if content, ok := pending[file.oldPath]; ok {
+			current = content
+		} else {
+			content, err := store.ReadFile(file.oldPath)
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", diff.OldPath, err)
+			}
+			current = content
+		}
+
+		file.content, file.results = patch.Apply(current, diff.Hunks)
+		for _, result := range file.results {
+			if !result.Applied {
+				rejected = true
+			}
+		}
+		if file.newPath != "" {
+			pending[file.newPath] = file.content
+		}
+		patched = append(patched, file)
+	}
+
+	if rejected {
+		return &ToolResult{Output: formatPatchReport(patched, true)}, nil
+	}
+	for _, file := range patched {
+		if file.newPath != "" {
+			if err := store.WriteFile(file.newPath, []byte(file.content)); err != nil {
+				return nil, fmt.Errorf("write %s: %w", file.diff.NewPath, err)
+			}
+		}
+		if file.oldPath != "" && file.oldPath != file.newPath {
+			if err := store.RemoveFile(file.oldPath); err != nil {
+				return nil, fmt.Errorf("remove %s: %w", file.diff.OldPath, err)
+			}
+		}
+	}
+	return &ToolResult{Output: formatPatchReport(patched, false)}, nil
+}
+
+// formatPatchReport lists each file with hunks that needed an offset or fuzz
+// and, when rejected, the hunks that did not apply.
+func formatPatchReport(files []patchedFile, rejected bool) string {
+	var b strings.Builder
+	if rejected {
+		b.WriteString("Patch not applied; no files were changed. Rejected hunks:\n")
+	}
+	for _, file := range files {
+		if rejected {
+			for _, result := range file.results {
+				if !result.Applied {
+					fmt.Fprintf(&b, "%s %s\n%s\n", patchTarget(file.diff), result.Hunk.Header, strings.Join(result.Hunk.Lines, "\n"))
+				}
+			}
+			continue
+		}
+		action := "patched"
+		switch {
+		case file.oldPath == "":
+			action = "created"
+		case file.newPath == "":
+			action = "deleted"
+		case file.oldPath != file.newPath:
+			action = "renamed " + file.diff.OldPath + " to"
+		}
+		fmt.Fprintf(&b, "%s %s (%d %s)", action, patchTarget(file.diff), len(file.results), plural(len(file.results), "hunk", "hunks"))
+		var notes []string
+		for i, result := range file.results {
+			if result.Offset != 0 || result.Fuzz != 0 {
+				notes = append(notes, fmt.Sprintf("hunk %d at offset %+d with fuzz %d", i+1, result.Offset, result.Fuzz))
+			}
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, "; %s; check the result", strings.Join(notes, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func patchTarget(file patch.FileDiff) string {
+	if file.NewPath != "" {
+		return file.NewPath
+	}
+	return file.OldPath
+}
+
+func plural(count int, singular, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+	return pluralForm
+}
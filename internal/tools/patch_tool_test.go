@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyPatch_AppliesAcrossFiles(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "todo.md"), []byte("- milk\n- eggs\n- bread\n"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "old.txt"), []byte("bye\n"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	diff := `--- a/todo.md
++++ b/todo.md
+@@ -1,3 +1,3 @@
+ - milk
+-- eggs
++- butter
+ - bread
+--- /dev/null
++++ b/notes/new.md
+@@ -0,0 +1 @@
++hello
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`
+	res, err := (ApplyPatchTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"patch": diff})
+	if err != nil {
+		t.Fatalf("apply patch: %v", err)
+	}
+	for _, want := range []string{"patched todo.md (1 hunk)", "created notes/new.md", "deleted old.txt"} {
+		if !strings.Contains(res.Output, want) {
+			t.Fatalf("expected %q in report, got %q", want, res.Output)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(workspace, "todo.md")); string(data) != "- milk\n- butter\n- bread\n" {
+		t.Fatalf("unexpected todo.md: %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(workspace, "notes", "new.md")); string(data) != "hello\n" {
+		t.Fatalf("unexpected new.md: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, "old.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected old.txt deleted, got %v", err)
+	}
+}
+
+func TestApplyPatch_RejectedHunkWritesNothing(t *testing.T) {
+	workspace := t.TempDir()
+	for name, content := range map[string]string{"a.txt": "one\n", "b.txt": "two\n"} {
+		if err := os.WriteFile(filepath.Join(workspace, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+	diff := `--- a/a.txt
++++ b/a.txt
+@@ -1 +1 @@
+-one
++uno
+--- a/b.txt
++++ b/b.txt
+@@ -1 +1 @@
+-three
++tres
+`
+	res, err := (ApplyPatchTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"patch": diff})
+	if err != nil {
+		t.Fatalf("apply patch: %v", err)
+	}
+	if !strings.Contains(res.Output, "no files were changed") || !strings.Contains(res.Output, "b.txt @@ -1 +1 @@\n-three\n+tres") {
+		t.Fatalf("expected rejected hunk report, got %q", res.Output)
+	}
+	if data, _ := os.ReadFile(filepath.Join(workspace, "a.txt")); string(data) != "one\n" {
+		t.Fatalf("expected a.txt untouched, got %q", data)
+	}
+}
+
+func TestApplyPatch_RejectsPathsOutsideWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	diff := "--- /dev/null\n+++ b/../escape.txt\n@@ -0,0 +1 @@\n+x\n"
+	_, err := (ApplyPatchTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"patch": diff})
+	if err == nil || !strings.Contains(err.Error(), "outside workspace") {
+		t.Fatalf("expected outside workspace error, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"go.yaml.in/yaml/v3"
+)
+
+// maxDataFileBytes caps the JSON and YAML files query_data loads.
+const maxDataFileBytes = 50 << 20
+
+// QueryDataTool extracts values from JSON, JSON Lines, and YAML files.
+type QueryDataTool struct {
+	WorkspaceDir string
+}
+
+// Name returns the tool name.
+func (t QueryDataTool) Name() string {
+	return "query_data"
+}
+
+// Description returns the tool description for the model.
+func (t QueryDataTool) Description() string {
+	return "Extract values from a JSON, JSON Lines, or YAML file without reading it whole. The query is a jq program, e.g. .users[0].name, .users[].email, [.users[] | select(.age > 40) | .name], .users | length, keys, map(.price) | add. JSON Lines files are an array of their records. Omit query to see the top-level structure."
+}
+
+// Schema returns the JSON schema for query_data args.
+func (t QueryDataTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "JSON (.json), JSON Lines (.jsonl, .ndjson), or YAML (.yaml, .yml) file, absolute or relative to workspace",
+			},
+			"query": map[string]any{
+				"type":        "string",
+				"description": "jq program; omit to describe the top level",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t QueryDataTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute loads the file and runs the jq query against it.
+func (t QueryDataTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	pathArg, err := stringArg(args, "path")
+	if err != nil {
+		return nil, err
+	}
+	query, err := optionalStringArg(args, "query", "")
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := resolveInputPath(t.WorkspaceDir, projectPath(ctx, pathArg))
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxDataFileBytes {
+		return nil, fmt.Errorf("%s is larger than %d MB", pathArg, maxDataFileBytes>>20)
+	}
+	content, err := store.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	document, err := loadData(path, []byte(content))
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", pathArg, err)
+	}
+
+	if query == "" {
+		return &ToolResult{Output: describeJSON(document)}, nil
+	}
+	parsed, err := gojq.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("parse query: %w", err)
+	}
+	var outputs []string
+	iter := parsed.RunWithContext(ctx, document)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := value.(error); ok {
+			var halt *gojq.HaltError
+			if errors.As(err, &halt) && halt.Value() == nil {
+				break
+			}
+			return nil, fmt.Errorf("run query: %w", err)
+		}
+		text, err := formatJQValue(value)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, text)
+	}
+	if len(outputs) == 0 || (len(outputs) == 1 && outputs[0] == "null") {
+		return &ToolResult{Output: fmt.Sprintf("No match for %s.", query)}, nil
+	}
+	return &ToolResult{Output: strings.Join(outputs, "\n")}, nil
+}
+
+// formatJQValue shows strings raw and everything else as indented JSON.
+func formatJQValue(value any) (string, error) {
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode result: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// loadData decodes the file into the values jq works on.
+func loadData(path string, content []byte) (any, error) {
+	document, err := dataAsJSON(path, content)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	return value, nil
+}
+
+// dataAsJSON returns the file as one JSON document; JSON Lines become an
+// array of their records.
+func dataAsJSON(path string, content []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if !json.Valid(content) {
+			return "", errors.New("file is not valid JSON")
+		}
+		return string(content), nil
+	case ".jsonl", ".ndjson":
+		var records []string
+		for i, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			if !json.Valid([]byte(line)) {
+				return "", fmt.Errorf("line %d is not valid JSON", i+1)
+			}
+			records = append(records, line)
+		}
+		return "[" + strings.Join(records, ",") + "]", nil
+	case ".yaml", ".yml":
+		var value any
+		if err := yaml.Unmarshal(content, &value); err != nil {
+			return "", fmt.Errorf("parse yaml: %w", err)
+		}
+		encoded, err := json.Marshal(jsonCompatible(value))
+		if err != nil {
+			return "", fmt.Errorf("convert yaml: %w", err)
+		}
+		return string(encoded), nil
+	}
+	return "", fmt.Errorf("unsupported file type %q; use .json, .jsonl, .ndjson, .yaml, or .yml", filepath.Ext(path))
+}
+
+// jsonCompatible converts YAML maps with non-string keys so they encode.
+func jsonCompatible(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			value[key] = jsonCompatible(item)
+		}
+		return value
+	case map[any]any:
+		converted := make(map[string]any, len(value))
+		for key, item := range value {
+			converted[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return converted
+	case []any:
+		for i, item := range value {
+			value[i] = jsonCompatible(item)
+		}
+		return value
+	}
+	return value
+}
+
+// describeJSON summarizes a document's top level: object keys with their
+// types, or an array's length and first element.
+func describeJSON(document any) string {
+	switch document := document.(type) {
+	case map[string]any:
+		lines := make([]string, 0, len(document))
+		for key, value := range document {
+			lines = append(lines, fmt.Sprintf("  %s: %s", key, describeJSONValue(value)))
+		}
+		sort.Strings(lines)
+		return fmt.Sprintf("object with %d %s:\n%s", len(lines), plural(len(lines), "key", "keys"), strings.Join(lines, "\n"))
+	case []any:
+		summary := fmt.Sprintf("array of %d %s", len(document), plural(len(document), "item", "items"))
+		if len(document) == 0 {
+			return summary
+		}
+		return summary + "; first item:\n" + describeJSON(document[0])
+	}
+	return describeJSONValue(document)
+}
+
+func describeJSONValue(value any) string {
+	switch value := value.(type) {
+	case map[string]any:
+		return fmt.Sprintf("object (%d %s)", len(value), plural(len(value), "key", "keys"))
+	case []any:
+		return fmt.Sprintf("array (%d %s)", len(value), plural(len(value), "item", "items"))
+	case string:
+		text := value
+		if runes := []rune(text); len(runes) > 40 {
+			text = string(runes[:39]) + "…"
+		}
+		return fmt.Sprintf("string %q", text)
+	case float64:
+		encoded, _ := json.Marshal(value)
+		return "number " + string(encoded)
+	case bool:
+		return fmt.Sprintf("bool %t", value)
+	}
+	return "null"
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestQueryData_JSONAndJQ(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{
+		"users.json": `{"users":[{"name":"Ada","age":36,"tags":["admin"]},{"name":"Linus","age":54}],"meta":{"total":2}}`,
+	})
+	tool := QueryDataTool{WorkspaceDir: workspace}
+
+	for query, want := range map[string]string{
+		".users[0].name":                       "Ada",
+		"[.users[].name]":                      "[\n  \"Ada\",\n  \"Linus\"\n]",
+		".users[].age":                         "36\n54",
+		".users[] | select(.age > 40) | .name": "Linus",
+		".users | length":                      "2",
+		`.["meta"].total`:                      "2",
+		"[.users[].tags // [] | length] | add": "1",
+		".users[5]":                            "No match for .users[5].",
+	} {
+		res, err := tool.Execute(context.Background(), map[string]any{"path": "users.json", "query": query})
+		if err != nil {
+			t.Fatalf("query %q: %v", query, err)
+		}
+		if res.Output != want {
+			t.Errorf("query %q = %q, want %q", query, res.Output, want)
+		}
+	}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"path": "users.json"})
+	if err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	if res.Output != "object with 2 keys:\n  meta: object (1 key)\n  users: array (2 items)" {
+		t.Fatalf("unexpected description:\n%s", res.Output)
+	}
+}
+
+func TestQueryData_YAMLAndJSONLines(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{
+		"config.yaml":  "server:\n  port: 8080\n  hosts: [a, b]\n1: numeric key\n",
+		"events.jsonl": "{\"type\":\"click\"}\n\n{\"type\":\"view\"}\n",
+		"broken.json":  "{",
+	})
+	tool := QueryDataTool{WorkspaceDir: workspace}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"path": "config.yaml", "query": ".server.hosts[1]"})
+	if err != nil || res.Output != "b" {
+		t.Fatalf("unexpected yaml result %q %v", res.Output, err)
+	}
+	res, err = tool.Execute(context.Background(), map[string]any{"path": "events.jsonl", "query": "map(.type)"})
+	if err != nil || !strings.Contains(res.Output, `"view"`) {
+		t.Fatalf("unexpected jsonl result %q %v", res.Output, err)
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": "broken.json", "query": "."}); err == nil || !strings.Contains(err.Error(), "not valid JSON") {
+		t.Fatalf("expected invalid JSON error, got %v", err)
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": "config.yaml", "query": ".server |"}); err == nil || !strings.Contains(err.Error(), "parse query") {
+		t.Fatalf("expected query parse error, got %v", err)
+	}
+}
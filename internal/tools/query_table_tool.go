@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"github.com/neoclaw-ai/neoclaw/internal/table"
+)
+
+const (
+	// maxTableFileBytes caps the spreadsheet files query_table loads.
+	maxTableFileBytes = 50 << 20
+	// maxQueryRows caps how many result rows are returned to the model.
+	maxQueryRows = 100
+	// maxCellChars caps how much of one cell is shown.
+	maxCellChars = 80
+	// previewRows is how many rows are shown when no query is given.
+	previewRows = 5
+)
+
+// QueryTableTool runs read-only SQL over a CSV, TSV, or XLSX file.
+type QueryTableTool struct {
+	WorkspaceDir string
+}
+
+// Name returns the tool name.
+func (t QueryTableTool) Name() string {
+	return "query_table"
+}
+
+// Description returns the tool description for the model.
+func (t QueryTableTool) Description() string {
+	return fmt.Sprintf("Answer questions about a CSV, TSV, or XLSX file with one read-only SQLite SELECT over a table named %s, e.g. SELECT substr(Date, 1, 7) AS month, round(avg(Amount), 2) FROM %s WHERE Category = 'Groceries' GROUP BY month. Any SQLite SELECT works, including aliases, joins, subqueries, and window functions. Double-quote column names with spaces. Omit sql to see the columns and first rows.", table.Name, table.Name)
+}
+
+// Schema returns the JSON schema for query_table args.
+func (t QueryTableTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "CSV, TSV, or XLSX file, absolute or relative to workspace",
+			},
+			"sql": map[string]any{
+				"type":        "string",
+				"description": fmt.Sprintf("SELECT query over the table %s; omit to list columns and sample rows", table.Name),
+			},
+			"sheet": map[string]any{
+				"type":        "string",
+				"description": "XLSX worksheet name (default: first sheet)",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t QueryTableTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute loads the file and runs the query, or describes the table.
+func (t QueryTableTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	pathArg, err := stringArg(args, "path")
+	if err != nil {
+		return nil, err
+	}
+	query, err := optionalStringArg(args, "sql", "")
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := optionalStringArg(args, "sheet", "")
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := resolveInputPath(t.WorkspaceDir, projectPath(ctx, pathArg))
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxTableFileBytes {
+		return nil, fmt.Errorf("%s is larger than %d MB", pathArg, maxTableFileBytes>>20)
+	}
+	content, err := store.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	data, err := table.Load(path, []byte(content), sheet)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", pathArg, err)
+	}
+
+	if query == "" {
+		preview := &table.Result{Columns: data.Columns, Rows: data.Rows[:min(previewRows, len(data.Rows))]}
+		return &ToolResult{Output: data.Describe() + "\n\nfirst rows:\n" + formatQueryResult(preview)}, nil
+	}
+	result, err := data.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: formatQueryResult(result)}, nil
+}
+
+// formatQueryResult renders rows as pipe-separated lines under a header.
+func formatQueryResult(result *table.Result) string {
+	if len(result.Rows) == 0 {
+		return "(no rows)"
+	}
+	var b strings.Builder
+	b.WriteString(strings.Join(result.Columns, " | "))
+	shown := result.Rows[:min(len(result.Rows), maxQueryRows)]
+	for _, row := range shown {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = formatCell(v)
+		}
+		b.WriteString("\n" + strings.Join(cells, " | "))
+	}
+	if len(result.Rows) > len(shown) {
+		fmt.Fprintf(&b, "\n[showing %d of %s rows; aggregate or add LIMIT]", len(shown), formatWithCommas(len(result.Rows)))
+	} else {
+		fmt.Fprintf(&b, "\n(%d %s)", len(result.Rows), plural(len(result.Rows), "row", "rows"))
+	}
+	return b.String()
+}
+
+func formatCell(v table.Value) string {
+	text := table.Text(v)
+	if v == nil {
+		text = "NULL"
+	}
+	text = strings.NewReplacer("\n", " ", "\r", " ", "|", "/").Replace(text)
+	if runes := []rune(text); len(runes) > maxCellChars {
+		text = string(runes[:maxCellChars-1]) + "…"
+	}
+	return text
+}
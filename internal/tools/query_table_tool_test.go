@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestQueryTable_DescribesAndQueries(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{
+		"export.csv": "Date,Category,Amount\n2024-01-03,Groceries,20\n2024-01-09,Groceries,30\n2024-02-01,Rent,1000\n",
+	})
+	tool := QueryTableTool{WorkspaceDir: workspace}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"path": "export.csv"})
+	if err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	for _, want := range []string{"table data: 3 rows", "  Date (text)", "  Amount (number)", "Date | Category | Amount\n2024-01-03 | Groceries | 20"} {
+		if !strings.Contains(res.Output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, res.Output)
+		}
+	}
+
+	res, err = tool.Execute(context.Background(), map[string]any{
+		"path": "export.csv",
+		"sql":  "SELECT substr(Date, 1, 7) AS month, avg(Amount) AS average FROM data WHERE Category = 'Groceries' GROUP BY month",
+	})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if res.Output != "month | average\n2024-01 | 25\n(1 row)" {
+		t.Fatalf("unexpected output:\n%s", res.Output)
+	}
+
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": "export.csv", "sql": "UPDATE data SET Amount = 0"}); err == nil {
+		t.Fatal("expected non-SELECT query to fail")
+	}
+}
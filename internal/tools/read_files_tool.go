@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+const (
+	// defaultReadFilesMaxFiles caps how many files one read_files call returns.
+	defaultReadFilesMaxFiles = 50
+	// defaultReadFilesMaxBytes matches the default tool output length, so the
+	// tool stops on a file boundary before the agent loop would truncate.
+	defaultReadFilesMaxBytes = 12000
+	// maxUnreadListed caps how many unread matches are named in the footer,
+	// and readFilesFooterReserve keeps room for that footer under MaxBytes.
+	maxUnreadListed        = 20
+	readFilesFooterReserve = 1024
+)
+
+// ReadFilesTool reads every text file matching a glob in one call.
+type ReadFilesTool struct {
+	WorkspaceDir string
+	// MaxFiles and MaxBytes cap the files returned and their total size.
+	MaxFiles int
+	MaxBytes int
+}
+
+// Name returns the tool name.
+func (t ReadFilesTool) Name() string {
+	return "read_files"
+}
+
+// Description returns the tool description for the model.
+func (t ReadFilesTool) Description() string {
+	return "Read several text files at once by glob, e.g. \"src/**/*.go\" or \"docs/*.md\". ** matches any number of directories. Returns each file under a header with its path; stops at a file count and total size cap and names the files it did not read."
+}
+
+// Schema returns the JSON schema for read_files args.
+func (t ReadFilesTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"pattern": map[string]any{
+				"type":        "string",
+				"description": "Glob relative to workspace or absolute; ** matches any number of directories",
+			},
+		},
+		"required": []string{"pattern"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t ReadFilesTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute reads matching files in path order until a cap is reached.
+func (t ReadFilesTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	pattern, err := stringArg(args, "pattern")
+	if err != nil {
+		return nil, err
+	}
+	matches, err := globFiles(t.WorkspaceDir, projectPath(ctx, pattern))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return &ToolResult{Output: fmt.Sprintf("No files match %s.", pattern)}, nil
+	}
+
+	maxFiles := t.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = defaultReadFilesMaxFiles
+	}
+	maxBytes := t.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultReadFilesMaxBytes
+	}
+	maxBytes = max(maxBytes-readFilesFooterReserve, maxBytes/2)
+
+	var b strings.Builder
+	read := 0
+	next := 0
+	for ; next < len(matches) && read < maxFiles; next++ {
+		match := matches[next]
+		display := workspaceDisplayPath(t.WorkspaceDir, match)
+		content, err := store.ReadFile(match)
+		if err != nil {
+			fmt.Fprintf(&b, "==> %s <==\n[unreadable: %v]\n\n", display, err)
+			continue
+		}
+		if isBinary([]byte(content)) {
+			fmt.Fprintf(&b, "==> %s <==\n[binary, skipped]\n\n", display)
+			continue
+		}
+		header := fmt.Sprintf("==> %s (%s bytes) <==\n", display, formatWithCommas(len(content)))
+		remaining := maxBytes - b.Len() - len(header)
+		if len(content) > remaining {
+			if read > 0 {
+				// Leave the whole file for a narrower call rather than cut it.
+				break
+			}
+			// A single file larger than the cap is returned in part.
+			b.WriteString(header)
+			b.WriteString(truncateUTF8(content, max(remaining, 0)))
+			b.WriteString("\n[truncated; use read_file for the rest]\n\n")
+			read++
+			next++
+			break
+		}
+		b.WriteString(header)
+		b.WriteString(content)
+		if !strings.HasSuffix(content, "\n") {
+			b.WriteByte('\n')
+		}
+		b.WriteByte('\n')
+		read++
+	}
+
+	if unread := matches[next:]; len(unread) > 0 {
+		names := make([]string, 0, min(len(unread), maxUnreadListed))
+		for _, match := range unread[:min(len(unread), maxUnreadListed)] {
+			names = append(names, workspaceDisplayPath(t.WorkspaceDir, match))
+		}
+		fmt.Fprintf(&b, "[stopped after %d of %d matching files; not read: %s", read, len(matches), strings.Join(names, ", "))
+		if len(unread) > maxUnreadListed {
+			fmt.Fprintf(&b, " and %d more", len(unread)-maxUnreadListed)
+		}
+		b.WriteString("]")
+	}
+	return &ToolResult{Output: strings.TrimRight(b.String(), "\n")}, nil
+}
+
+// globFiles returns the regular files matching pattern, sorted by path.
+// Hidden directories are only entered when the pattern names one.
+func globFiles(workspaceDir, pattern string) ([]string, error) {
+	resolved, err := resolveInputPath(workspaceDir, filepath.FromSlash(pattern))
+	if err != nil {
+		return nil, err
+	}
+	segments := strings.Split(filepath.ToSlash(resolved), "/")
+
+	// Walk from the deepest directory that has no wildcard in it.
+	static := 0
+	for static < len(segments)-1 && !strings.ContainsAny(segments[static], `*?[\`) {
+		static++
+	}
+	root := filepath.FromSlash(strings.Join(segments[:static], "/"))
+	if root == "" {
+		root = string(filepath.Separator)
+	}
+	rest := segments[static:]
+	allowHidden := false
+	for _, segment := range rest {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		if strings.HasPrefix(segment, ".") {
+			allowHidden = true
+		}
+	}
+
+	var matches []string
+	err = filepath.WalkDir(root, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if current == root {
+				return err
+			}
+			return nil
+		}
+		if current == root {
+			return nil
+		}
+		if entry.IsDir() {
+			if strings.HasPrefix(entry.Name(), ".") && !allowHidden {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, current)
+		if err != nil {
+			return nil
+		}
+		if matchGlob(rest, strings.Split(filepath.ToSlash(rel), "/")) {
+			matches = append(matches, current)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", root, err)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// matchGlob matches path segments against pattern segments, where a "**"
+// segment matches zero or more path segments.
+func matchGlob(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlob(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// workspaceDisplayPath shows paths under the workspace relative to it.
+func workspaceDisplayPath(workspaceDir, target string) string {
+	if workspaceDir != "" {
+		if rel, err := filepath.Rel(filepath.Clean(workspaceDir), target); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return target
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFixtures(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+}
+
+func TestReadFiles_MatchesDoubleStarGlob(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{
+		"src/main.go":          "package main\n",
+		"src/util/strings.go":  "package util\n",
+		"src/util/notes.md":    "not go\n",
+		"src/.cache/hidden.go": "package hidden\n",
+		"src/blob.go":          "\x00\x01",
+		"README.md":            "readme\n",
+	})
+
+	tool := ReadFilesTool{WorkspaceDir: workspace}
+	if tool.Permission() != AutoApprove {
+		t.Fatal("expected read_files to be auto-approved")
+	}
+	res, err := tool.Execute(context.Background(), map[string]any{"pattern": "src/**/*.go"})
+	if err != nil {
+		t.Fatalf("read files: %v", err)
+	}
+	for _, want := range []string{
+		"==> src/main.go (13 bytes) <==\npackage main\n",
+		"==> src/util/strings.go (13 bytes) <==\npackage util",
+		"==> src/blob.go <==\n[binary, skipped]",
+	} {
+		if !strings.Contains(res.Output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, res.Output)
+		}
+	}
+	for _, unwanted := range []string{"notes.md", "hidden.go", "README.md"} {
+		if strings.Contains(res.Output, unwanted) {
+			t.Fatalf("did not expect %s in output:\n%s", unwanted, res.Output)
+		}
+	}
+	if strings.Index(res.Output, "src/blob.go") > strings.Index(res.Output, "src/main.go") {
+		t.Fatalf("expected files in path order:\n%s", res.Output)
+	}
+}
+
+func TestReadFiles_StopsAtCaps(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{
+		"a.txt": "aaa\n",
+		"b.txt": "bbb\n",
+		"c.txt": "ccc\n",
+	})
+
+	res, err := (ReadFilesTool{WorkspaceDir: workspace, MaxFiles: 2}).Execute(context.Background(), map[string]any{"pattern": "*.txt"})
+	if err != nil {
+		t.Fatalf("read files: %v", err)
+	}
+	if !strings.Contains(res.Output, "b.txt") || !strings.HasSuffix(res.Output, "[stopped after 2 of 3 matching files; not read: c.txt]") {
+		t.Fatalf("expected file cap footer, got:\n%s", res.Output)
+	}
+
+	writeFixtures(t, workspace, map[string]string{"a-big.txt": strings.Repeat("x", 5000)})
+	res, err = (ReadFilesTool{WorkspaceDir: workspace, MaxBytes: 2000}).Execute(context.Background(), map[string]any{"pattern": "*.txt"})
+	if err != nil {
+		t.Fatalf("read files: %v", err)
+	}
+	if !strings.Contains(res.Output, "[truncated; use read_file for the rest]") || !strings.Contains(res.Output, "not read: a.txt, b.txt, c.txt") {
+		t.Fatalf("expected size cap handling, got:\n%s", res.Output)
+	}
+	if len(res.Output) > 2000 {
+		t.Fatalf("expected output within cap, got %d bytes", len(res.Output))
+	}
+}
+
+func TestReadFiles_NoMatchesAndBadPattern(t *testing.T) {
+	workspace := t.TempDir()
+	res, err := (ReadFilesTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"pattern": "missing/**/*.go"})
+	if err != nil || res.Output != "No files match missing/**/*.go." {
+		t.Fatalf("expected no matches, got %q %v", res.Output, err)
+	}
+	if _, err := (ReadFilesTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"pattern": "[a-"}); err == nil {
+		t.Fatal("expected invalid pattern error")
+	}
+}
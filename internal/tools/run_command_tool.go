@@ -19,6 +19,18 @@ type RunCommandTool struct {
 	Timeout      time.Duration
 	SecurityMode string
 	ProxyAddress string
+	// Sandbox, when set, reports whether command must run through
+	// claw sandbox-run, confined to WorkspaceDir.
+	Sandbox func(command string) bool
+}
+
+type workdirKey struct{}
+
+// WithWorkdir makes dir the working directory of run_command calls made with
+// ctx that do not pass their own, such as the root of the active project,
+// and the directory file tools resolve relative paths against.
+func WithWorkdir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, workdirKey{}, dir)
 }
 
 // Name returns the tool name.
@@ -69,7 +81,7 @@ func (t RunCommandTool) SummarizeArgs(args map[string]any) string {
 
 // Execute runs the command and returns combined output, appending exit code on failures.
 func (t RunCommandTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
-	command, workdir, err := t.validateArgs(args)
+	command, workdir, err := t.validateArgs(ctx, args)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +94,13 @@ func (t RunCommandTool) Execute(ctx context.Context, args map[string]any) (*Tool
 	defer cancel()
 
 	cmd := exec.CommandContext(runCtx, "sh", "-c", command)
+	if t.Sandbox != nil && t.Sandbox(command) {
+		executable, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("resolve executable for sandbox: %w", err)
+		}
+		cmd = exec.CommandContext(runCtx, executable, "sandbox-run", "--dir", t.WorkspaceDir, "--", "sh", "-c", command)
+	}
 	cmd.Dir = workdir
 	cmd.Env = t.commandEnv()
 	configureCommandForCancellation(cmd)
@@ -142,7 +161,7 @@ func (t RunCommandTool) commandEnv() []string {
 }
 
 // validateArgs validates command args and resolves working directory.
-func (t RunCommandTool) validateArgs(args map[string]any) (string, string, error) {
+func (t RunCommandTool) validateArgs(ctx context.Context, args map[string]any) (string, string, error) {
 	command, err := stringArg(args, "command")
 	if err != nil {
 		return "", "", err
@@ -152,6 +171,13 @@ func (t RunCommandTool) validateArgs(args map[string]any) (string, string, error
 	}
 
 	workdir := t.WorkspaceDir
+	if dir, ok := ctx.Value(workdirKey{}).(string); ok && dir != "" {
+		wd, err := resolveWorkspacePath(t.WorkspaceDir, dir)
+		if err != nil {
+			return "", "", err
+		}
+		workdir = wd
+	}
 	if raw, ok := args["workdir"]; ok {
 		value, ok := raw.(string)
 		if !ok {
@@ -159,7 +185,7 @@ func (t RunCommandTool) validateArgs(args map[string]any) (string, string, error
 		}
 		value = strings.TrimSpace(value)
 		if value != "" {
-			wd, err := resolveWorkspacePath(t.WorkspaceDir, value)
+			wd, err := resolveWorkspacePath(t.WorkspaceDir, projectPath(ctx, value))
 			if err != nil {
 				return "", "", err
 			}
@@ -3,6 +3,8 @@ package tools
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -24,6 +26,29 @@ func TestRunCommand_ExecutesCommand(t *testing.T) {
 	}
 }
 
+func TestRunCommand_WithWorkdirStaysInWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	project := filepath.Join(workspace, "blog")
+	if err := os.Mkdir(project, 0o755); err != nil {
+		t.Fatalf("mkdir project: %v", err)
+	}
+	tool := RunCommandTool{
+		WorkspaceDir: workspace,
+		Timeout:      5 * time.Minute,
+	}
+
+	res, err := tool.Execute(WithWorkdir(context.Background(), project), map[string]any{"command": "pwd"})
+	if err != nil {
+		t.Fatalf("execute command: %v", err)
+	}
+	if got := strings.TrimSpace(res.Output); filepath.Base(got) != "blog" {
+		t.Fatalf("expected the command to run in the project, got %q", got)
+	}
+	if _, err := tool.Execute(WithWorkdir(context.Background(), t.TempDir()), map[string]any{"command": "pwd"}); err == nil {
+		t.Fatal("expected a working directory outside the workspace to be refused")
+	}
+}
+
 func TestRunCommand_AllowedBinaryOK(t *testing.T) {
 	workspace := t.TempDir()
 
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+// ChannelFileSender sends a file to the active user channel.
+type ChannelFileSender interface {
+	SendFile(ctx context.Context, path, caption string) error
+}
+
+// SendFileTool delivers a file, such as a chart or CSV, to the current
+// channel.
+type SendFileTool struct {
+	// Sender is the channel's message sender; it sends files when it also
+	// implements ChannelFileSender.
+	Sender       ChannelMessageSender
+	Writer       io.Writer
+	WorkspaceDir string
+	// SecurityMode is a config.SecurityMode* value; only danger mode lets
+	// files outside the workspace be sent.
+	SecurityMode string
+}
+
+// Name returns the tool name.
+func (t SendFileTool) Name() string {
+	return "send_file"
+}
+
+// Description returns the tool description for the model.
+func (t SendFileTool) Description() string {
+	return "Send a file from the workspace to the user, such as a generated chart, CSV, or screenshot, instead of pasting its content. Images are shown inline where the channel supports it. Relative paths are resolved from the workspace."
+}
+
+// Schema returns the JSON schema for send_file args.
+func (t SendFileTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path of the file to send",
+			},
+			"caption": map[string]any{
+				"type":        "string",
+				"description": "Optional short caption shown with the file",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t SendFileTool) Permission() Permission {
+	return AutoApprove
+}
+
+// SummarizeArgs returns a concise summary for send_file.
+func (t SendFileTool) SummarizeArgs(args map[string]any) string {
+	path, _ := args["path"].(string)
+	return "send_file: " + path
+}
+
+// Execute sends the file through Sender, or points a terminal user at it.
+func (t SendFileTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	pathArg, err := stringArg(args, "path")
+	if err != nil {
+		return nil, err
+	}
+	caption, err := optionalStringArg(args, "caption", "")
+	if err != nil {
+		return nil, err
+	}
+	// The file leaves the machine, so keep it inside the workspace unless
+	// the operator opted out of all boundaries.
+	var path string
+	if strings.EqualFold(strings.TrimSpace(t.SecurityMode), config.SecurityModeDanger) {
+		path, err = resolveInputPath(t.WorkspaceDir, projectPath(ctx, pathArg))
+	} else {
+		path, err = resolveWorkspacePath(t.WorkspaceDir, projectPath(ctx, pathArg))
+	}
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", pathArg, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a file", pathArg)
+	}
+	if info.Size() == 0 {
+		return nil, fmt.Errorf("%s is empty", pathArg)
+	}
+
+	if t.Sender != nil {
+		files, ok := t.Sender.(ChannelFileSender)
+		if !ok {
+			return nil, errors.New("this channel cannot send files; tell the user the file's path instead")
+		}
+		if err := files.SendFile(ctx, path, caption); err != nil {
+			return nil, err
+		}
+		return &ToolResult{Output: "sent"}, nil
+	}
+
+	writer := t.Writer
+	if writer == nil {
+		writer = os.Stdout
+	}
+	if caption != "" {
+		fmt.Fprintf(writer, "%s\n  %s\n", caption, path)
+	} else {
+		fmt.Fprintln(writer, path)
+	}
+	return &ToolResult{Output: "sent"}, nil
+}
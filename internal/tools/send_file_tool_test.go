@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
+)
+
+type fakeFileSender struct {
+	fakeChannelSender
+	path    string
+	caption string
+}
+
+func (s *fakeFileSender) SendFile(_ context.Context, path, caption string) error {
+	s.path, s.caption = path, caption
+	return nil
+}
+
+func TestSendFileResolvesWorkspacePaths(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "chart.png"), []byte("png"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	sender := &fakeFileSender{}
+	tool := SendFileTool{Sender: sender, WorkspaceDir: workspace}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"path": "chart.png", "caption": "Weight, last 30 days"})
+	if err != nil {
+		t.Fatalf("execute send_file: %v", err)
+	}
+	if res.Output != "sent" || sender.path != filepath.Join(workspace, "chart.png") || sender.caption != "Weight, last 30 days" {
+		t.Fatalf("unexpected send: %q %q %q", res.Output, sender.path, sender.caption)
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": "missing.csv"}); err == nil {
+		t.Fatal("expected a missing file to fail")
+	}
+}
+
+func TestSendFileWithoutFileSupport(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "report.csv"), []byte("a,b\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	tool := SendFileTool{Sender: &fakeChannelSender{}, WorkspaceDir: workspace}
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": "report.csv"}); err == nil || !strings.Contains(err.Error(), "cannot send files") {
+		t.Fatalf("expected channels without files to refuse, got %v", err)
+	}
+
+	var out bytes.Buffer
+	tool = SendFileTool{Writer: &out, WorkspaceDir: workspace}
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": "report.csv"}); err != nil {
+		t.Fatalf("execute send_file: %v", err)
+	}
+	if got := out.String(); got != filepath.Join(workspace, "report.csv")+"\n" {
+		t.Fatalf("expected the path on the terminal, got %q", got)
+	}
+}
+
+func TestSendFileRefusesPathsOutsideWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "id_rsa")
+	if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(workspace, "key")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	sender := &fakeFileSender{}
+	tool := SendFileTool{Sender: sender, WorkspaceDir: workspace}
+
+	for _, path := range []string{"/etc/passwd", outside, "../id_rsa", "key"} {
+		if _, err := tool.Execute(context.Background(), map[string]any{"path": path}); err == nil {
+			t.Fatalf("expected %s to be refused", path)
+		}
+	}
+	if sender.path != "" {
+		t.Fatalf("expected nothing sent, got %q", sender.path)
+	}
+
+	tool.SecurityMode = config.SecurityModeDanger
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": outside}); err != nil {
+		t.Fatalf("expected danger mode to allow %s: %v", outside, err)
+	}
+}
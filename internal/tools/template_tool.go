@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// maxRenderedBytes caps a rendered template, so a runaway range cannot fill
+// the disk.
+const maxRenderedBytes = 5 << 20
+
+// RenderTemplateTool fills a workspace Go template with JSON or YAML data.
+type RenderTemplateTool struct {
+	WorkspaceDir string
+	SecurityMode string
+}
+
+// Name returns the tool name.
+func (t RenderTemplateTool) Name() string {
+	return "render_template"
+}
+
+// Description returns the tool description for the model.
+func (t RenderTemplateTool) Description() string {
+	return "Render a Go text/template file from the workspace with data, for recurring reports and letters. Fields are {{.name}}; loops are {{range .items}}...{{end}}; conditionals are {{if .paid}}...{{else}}...{{end}}. Extra functions: upper, lower, trim, join, default, date (\"2006-01-02\" layout, of a date string or now). Missing fields are an error. Writes to output when given, otherwise returns the text."
+}
+
+// Schema returns the JSON schema for render_template args.
+func (t RenderTemplateTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"template": map[string]any{
+				"type":        "string",
+				"description": "Template file, absolute or relative to workspace",
+			},
+			"data": map[string]any{
+				"type":        "string",
+				"description": "JSON object with the template's data",
+			},
+			"data_path": map[string]any{
+				"type":        "string",
+				"description": "JSON or YAML file with the data, instead of data",
+			},
+			"output": map[string]any{
+				"type":        "string",
+				"description": "File to write, relative to workspace or absolute under workspace; omit to return the text",
+			},
+		},
+		"required": []string{"template"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t RenderTemplateTool) Permission() Permission {
+	return AutoApprove
+}
+
+// SummarizeArgs names the template and where it is written.
+func (t RenderTemplateTool) SummarizeArgs(args map[string]any) string {
+	templatePath, _ := args["template"].(string)
+	output, _ := args["output"].(string)
+	if strings.TrimSpace(output) == "" {
+		return fmt.Sprintf("render_template: template=%q", templatePath)
+	}
+	return fmt.Sprintf("render_template: template=%q output=%q", templatePath, output)
+}
+
+// Execute renders the template and writes or returns the result.
+func (t RenderTemplateTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	templateArg, err := stringArg(args, "template")
+	if err != nil {
+		return nil, err
+	}
+	dataArg, err := optionalStringArg(args, "data", "")
+	if err != nil {
+		return nil, err
+	}
+	dataPathArg, err := optionalStringArg(args, "data_path", "")
+	if err != nil {
+		return nil, err
+	}
+	outputArg, err := optionalStringArg(args, "output", "")
+	if err != nil {
+		return nil, err
+	}
+	if dataArg != "" && dataPathArg != "" {
+		return nil, errors.New("pass data or data_path, not both")
+	}
+
+	templatePath, err := resolveInputPath(t.WorkspaceDir, projectPath(ctx, templateArg))
+	if err != nil {
+		return nil, err
+	}
+	source, err := store.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("read template: %w", err)
+	}
+	tmpl, err := template.New(templateArg).Funcs(templateFuncs).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	document := dataArg
+	if dataPathArg != "" {
+		dataPath, err := resolveInputPath(t.WorkspaceDir, projectPath(ctx, dataPathArg))
+		if err != nil {
+			return nil, err
+		}
+		content, err := store.ReadFile(dataPath)
+		if err != nil {
+			return nil, fmt.Errorf("read data: %w", err)
+		}
+		if document, err = dataAsJSON(dataPath, []byte(content)); err != nil {
+			return nil, fmt.Errorf("load %s: %w", dataPathArg, err)
+		}
+	}
+	var data any
+	if document != "" {
+		decoder := json.NewDecoder(strings.NewReader(document))
+		// Keep numbers as written rather than as float64, so 1500000
+		// does not render as 1.5e+06.
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return nil, fmt.Errorf("parse data: %w", err)
+		}
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&limitedWriter{w: &rendered, remaining: maxRenderedBytes}, data); err != nil {
+		return nil, err
+	}
+	if outputArg == "" {
+		return &ToolResult{Output: rendered.String()}, nil
+	}
+
+	outputPath, err := resolveWritePath(t.WorkspaceDir, t.SecurityMode, projectPath(ctx, outputArg))
+	if err != nil {
+		return nil, err
+	}
+	if err := store.WriteFile(outputPath, rendered.Bytes()); err != nil {
+		return nil, fmt.Errorf("write file: %w", err)
+	}
+	return &ToolResult{Output: fmt.Sprintf("wrote %s (%s bytes)", outputArg, formatWithCommas(rendered.Len()))}, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"join": func(sep string, items []any) string {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	},
+	// default returns fallback when value is empty: {{default "n/a" .note}}.
+	"default": func(fallback, value any) any {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+	// date formats now, or a date string, with a Go layout:
+	// {{date "January 2, 2006"}} or {{date "Jan 2" .due}}.
+	"date": func(layout string, value ...string) (string, error) {
+		if len(value) == 0 {
+			return time.Now().Format(layout), nil
+		}
+		for _, input := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+			if when, err := time.Parse(input, strings.TrimSpace(value[0])); err == nil {
+				return when.Format(layout), nil
+			}
+		}
+		return "", fmt.Errorf("date: cannot read %q; use YYYY-MM-DD or RFC 3339", value[0])
+	},
+}
+
+// limitedWriter fails once more than remaining bytes are written.
+type limitedWriter struct {
+	w         *bytes.Buffer
+	remaining int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > l.remaining {
+		return 0, fmt.Errorf("rendered output is larger than %d MB", maxRenderedBytes>>20)
+	}
+	l.remaining -= len(p)
+	return l.w.Write(p)
+}
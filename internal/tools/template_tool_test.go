@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate_WritesOutput(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{
+		"templates/invoice.tmpl": "Invoice for {{upper .client}} due {{date \"Jan 2, 2006\" .due}}\n{{range .items}}- {{.name}}: {{.amount}}\n{{end}}Note: {{default \"none\" .note}}\n",
+		"data/invoice.yaml":      "client: acme\ndue: 2026-03-01\nitems:\n  - name: Design\n    amount: 1500000\nnote: \"\"\n",
+	})
+	tool := RenderTemplateTool{WorkspaceDir: workspace}
+
+	res, err := tool.Execute(context.Background(), map[string]any{
+		"template":  "templates/invoice.tmpl",
+		"data_path": "data/invoice.yaml",
+		"output":    "out/invoice.txt",
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if res.Output != "wrote out/invoice.txt (62 bytes)" {
+		t.Fatalf("unexpected output %q", res.Output)
+	}
+	got, err := os.ReadFile(filepath.Join(workspace, "out", "invoice.txt"))
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	want := "Invoice for ACME due Mar 1, 2026\n- Design: 1500000\nNote: none\n"
+	if string(got) != want {
+		t.Fatalf("unexpected render:\n%s", got)
+	}
+}
+
+func TestRenderTemplate_InlineDataAndErrors(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{"hello.tmpl": "Hello {{.name}}, tags: {{join \", \" .tags}}"})
+	tool := RenderTemplateTool{WorkspaceDir: workspace}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"template": "hello.tmpl", "data": `{"name":"Ada","tags":["a","b"]}`})
+	if err != nil || res.Output != "Hello Ada, tags: a, b" {
+		t.Fatalf("unexpected render %q %v", res.Output, err)
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"template": "hello.tmpl", "data": `{"tags":[]}`}); err == nil || !strings.Contains(err.Error(), "name") {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"template": "hello.tmpl", "data": `{"name":"x","tags":[]}`, "output": "../escape.txt"}); err == nil || !strings.Contains(err.Error(), "outside workspace") {
+		t.Fatalf("expected outside workspace error, got %v", err)
+	}
+}
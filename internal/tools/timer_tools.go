@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
+)
+
+// maxTimerDuration bounds timers; anything longer belongs in a scheduled job.
+const maxTimerDuration = 7 * 24 * time.Hour
+
+// timerArg marks one-shot jobs created by start_timer and holds their label.
+const timerArg = "timer"
+
+// StartTimerTool starts a countdown that messages the user when it ends.
+type StartTimerTool struct {
+	Service          *scheduler.Service
+	ChannelID        string
+	ResolveChannelID func() string
+	now              func() time.Time
+}
+
+// Name returns the tool name.
+func (t StartTimerTool) Name() string {
+	return "start_timer"
+}
+
+// Description returns the tool description for the model.
+func (t StartTimerTool) Description() string {
+	return "Start a timer that sends the user a message when it ends (pomodoros, cooking, reminders in N minutes). Never claim a timer is running without calling this."
+}
+
+// Schema returns the JSON schema for start_timer args.
+func (t StartTimerTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"duration": map[string]any{
+				"type":        "string",
+				"description": "How long to run, e.g. 25m, 1h30m, 90s (at most 7 days)",
+			},
+			"label": map[string]any{
+				"type":        "string",
+				"description": "Short name shown when the timer ends, e.g. pomodoro or pasta",
+			},
+		},
+		"required": []string{"duration", "label"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t StartTimerTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute schedules a one-shot job that sends the timer message.
+func (t StartTimerTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Service == nil {
+		return nil, errors.New("scheduler service is required")
+	}
+	rawDuration, err := stringArg(args, "duration")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := time.ParseDuration(strings.ReplaceAll(rawDuration, " ", ""))
+	if err != nil {
+		return nil, fmt.Errorf("argument duration must look like 25m or 1h30m: %w", err)
+	}
+	if duration < time.Second || duration > maxTimerDuration {
+		return nil, errors.New("argument duration must be between 1s and 7 days")
+	}
+	label, err := stringArg(args, "label")
+	if err != nil {
+		return nil, err
+	}
+
+	channelID := jobChannelID(t.ChannelID, t.ResolveChannelID)
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+	endsAt := now().Add(duration)
+	job, err := t.Service.Create(ctx, scheduler.CreateInput{
+		Description: "Timer: " + label,
+		RunAt:       endsAt,
+		Action:      scheduler.ActionSendMessage,
+		Args: map[string]any{
+			"message": fmt.Sprintf("Timer done: %s (%s)", label, formatTimerDuration(duration)),
+			timerArg:  label,
+		},
+		ChannelID: channelID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := fmt.Sprintf("started timer %s: %s, ends at %s", job.ID, label, endsAt.In(time.Local).Format("15:04:05"))
+	if !t.Service.Running() {
+		out += ". The scheduler is not running in this process, so the message is sent once claw start is running."
+	}
+	return &ToolResult{Output: out}, nil
+}
+
+// CheckTimerTool reports running timers with elapsed and remaining time.
+type CheckTimerTool struct {
+	Service *scheduler.Service
+	now     func() time.Time
+}
+
+// Name returns the tool name.
+func (t CheckTimerTool) Name() string {
+	return "check_timer"
+}
+
+// Description returns the tool description for the model.
+func (t CheckTimerTool) Description() string {
+	return "List running timers with how long each has run and how long is left. Cancel one with job_delete and its id."
+}
+
+// Schema returns the JSON schema for check_timer args.
+func (t CheckTimerTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id": map[string]any{
+				"type":        "string",
+				"description": "Optional timer id; omit to list all timers",
+			},
+		},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t CheckTimerTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute lists timers created by start_timer.
+func (t CheckTimerTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Service == nil {
+		return nil, errors.New("scheduler service is required")
+	}
+	id, err := optionalStringArg(args, "id", "")
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := t.Service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+	current := now()
+
+	var b strings.Builder
+	count := 0
+	for _, job := range jobs {
+		label, ok := job.Args[timerArg].(string)
+		if !ok || job.RunAt.IsZero() || (id != "" && job.ID != id) {
+			continue
+		}
+		count++
+		elapsed := current.Sub(job.CreatedAt)
+		left := max(job.RunAt.Sub(current), 0)
+		fmt.Fprintf(&b, "%d. %s: %s elapsed, %s left (ends %s), id: %s\n",
+			count, label, formatTimerDuration(elapsed), formatTimerDuration(left),
+			job.RunAt.In(time.Local).Format("15:04:05"), job.ID)
+	}
+	if count == 0 {
+		if id != "" {
+			return nil, fmt.Errorf("timer %s not found", id)
+		}
+		return &ToolResult{Output: "No running timers."}, nil
+	}
+	return &ToolResult{Output: strings.TrimSuffix(b.String(), "\n")}, nil
+}
+
+// formatTimerDuration renders whole seconds, e.g. 25m0s becomes 25m.
+func formatTimerDuration(d time.Duration) string {
+	text := d.Round(time.Second).String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
+)
+
+func TestStartAndCheckTimerTools(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	svc := scheduler.NewService(filepath.Join(t.TempDir(), "jobs.json"), scheduler.NewRunner(scheduler.ActionRunners{}, nil))
+	startTool := StartTimerTool{Service: svc, ChannelID: "cli", now: func() time.Time { return start }}
+
+	started, err := startTool.Execute(context.Background(), map[string]any{"duration": "25m", "label": "pomodoro"})
+	if err != nil {
+		t.Fatalf("start timer: %v", err)
+	}
+	if !strings.Contains(started.Output, "pomodoro, ends at 10:25:00") {
+		t.Fatalf("unexpected start output %q", started.Output)
+	}
+
+	jobs, err := svc.List(context.Background())
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(jobs))
+	}
+	job := jobs[0]
+	if job.Cron != "" || !job.RunAt.Equal(start.Add(25*time.Minute)) {
+		t.Fatalf("expected one-shot job at 10:25, got cron %q run_at %s", job.Cron, job.RunAt)
+	}
+	if job.Args["message"] != "Timer done: pomodoro (25m)" {
+		t.Fatalf("unexpected timer message %#v", job.Args["message"])
+	}
+
+	checkTool := CheckTimerTool{Service: svc, now: func() time.Time { return job.CreatedAt.Add(10 * time.Minute) }}
+	checked, err := checkTool.Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("check timer: %v", err)
+	}
+	if !strings.Contains(checked.Output, "pomodoro: 10m elapsed") {
+		t.Fatalf("unexpected check output %q", checked.Output)
+	}
+
+	if _, err := checkTool.Execute(context.Background(), map[string]any{"id": "missing"}); err == nil {
+		t.Fatalf("expected error for unknown timer id")
+	}
+}
+
+func TestStartTimerToolRejectsBadDurations(t *testing.T) {
+	t.Parallel()
+
+	svc := scheduler.NewService(filepath.Join(t.TempDir(), "jobs.json"), scheduler.NewRunner(scheduler.ActionRunners{}, nil))
+	tool := StartTimerTool{Service: svc}
+	for _, duration := range []string{"soon", "0s", "-5m", "200h"} {
+		if _, err := tool.Execute(context.Background(), map[string]any{"duration": duration, "label": "x"}); err == nil {
+			t.Fatalf("expected error for duration %q", duration)
+		}
+	}
+}
+
+func TestCheckTimerToolIgnoresRecurringJobs(t *testing.T) {
+	t.Parallel()
+
+	svc := scheduler.NewService(filepath.Join(t.TempDir(), "jobs.json"), scheduler.NewRunner(scheduler.ActionRunners{}, nil))
+	if _, err := svc.Create(context.Background(), scheduler.CreateInput{
+		Description: "daily ping",
+		Cron:        "0 9 * * *",
+		Action:      scheduler.ActionSendMessage,
+		Args:        map[string]any{"message": "hello"},
+		ChannelID:   "cli",
+	}); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	checked, err := CheckTimerTool{Service: svc}.Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("check timer: %v", err)
+	}
+	if checked.Output != "No running timers." {
+		t.Fatalf("unexpected output %q", checked.Output)
+	}
+}
@@ -0,0 +1,225 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
+	"github.com/neoclaw-ai/neoclaw/internal/todo"
+)
+
+// TodoAddTool adds a task to the user's list, optionally with a reminder.
+type TodoAddTool struct {
+	Store *todo.Store
+	// Service schedules reminders; nil disables remind_at.
+	Service          *scheduler.Service
+	ChannelID        string
+	ResolveChannelID func() string
+	now              func() time.Time
+}
+
+// Name returns the tool name.
+func (t TodoAddTool) Name() string {
+	return "todo_add"
+}
+
+// Description returns the tool description for the model.
+func (t TodoAddTool) Description() string {
+	return "Add a task to the user's todo list, with an optional due date and an optional reminder message at a set time"
+}
+
+// Schema returns the JSON schema for todo_add args.
+func (t TodoAddTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"text": map[string]any{
+				"type":        "string",
+				"description": "The task, e.g. buy furnace filters",
+			},
+			"due": map[string]any{
+				"type":        "string",
+				"description": "Optional due date as YYYY-MM-DD",
+			},
+			"remind_at": map[string]any{
+				"type":        "string",
+				"description": "Optional time to message the user about the task, as 2026-03-07T09:00 (local time) or a delay like 2h or 3d",
+			},
+		},
+		"required": []string{"text"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t TodoAddTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute adds the task and schedules its reminder.
+func (t TodoAddTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("todo store is required")
+	}
+	text, err := stringArg(args, "text")
+	if err != nil {
+		return nil, err
+	}
+	due, err := optionalStringArg(args, "due", "")
+	if err != nil {
+		return nil, err
+	}
+	remindRaw, err := optionalStringArg(args, "remind_at", "")
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+	current := now()
+
+	// Validate the reminder before saving so a bad time doesn't leave a task
+	// the user thinks has a reminder.
+	var remindAt time.Time
+	if remindRaw != "" {
+		if t.Service == nil {
+			return nil, errors.New("reminders are unavailable")
+		}
+		remindAt, err = parseExpiryTime(remindRaw, current)
+		if err != nil {
+			return nil, fmt.Errorf("argument remind_at: %w", err)
+		}
+		if !remindAt.After(current) {
+			return nil, errors.New("argument remind_at must be in the future")
+		}
+	}
+
+	task, err := t.Store.Add(text, due, current)
+	if err != nil {
+		return nil, err
+	}
+	out := "added " + task.Format(current)
+	if remindAt.IsZero() {
+		return &ToolResult{Output: out}, nil
+	}
+	job, err := t.Service.Create(ctx, scheduler.CreateInput{
+		Description: fmt.Sprintf("Todo %d reminder", task.ID),
+		RunAt:       remindAt,
+		Action:      scheduler.ActionSendMessage,
+		Args:        map[string]any{"message": fmt.Sprintf("Reminder: %s (todo %d)", task.Text, task.ID)},
+		ChannelID:   jobChannelID(t.ChannelID, t.ResolveChannelID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("task %d added, but scheduling its reminder failed: %w", task.ID, err)
+	}
+	out += fmt.Sprintf("; reminder %s at %s", job.ID, remindAt.In(time.Local).Format("Mon 2006-01-02 15:04"))
+	return &ToolResult{Output: out}, nil
+}
+
+// TodoCompleteTool marks a task done.
+type TodoCompleteTool struct {
+	Store *todo.Store
+}
+
+// Name returns the tool name.
+func (t TodoCompleteTool) Name() string {
+	return "todo_complete"
+}
+
+// Description returns the tool description for the model.
+func (t TodoCompleteTool) Description() string {
+	return "Mark a task on the user's todo list as done"
+}
+
+// Schema returns the JSON schema for todo_complete args.
+func (t TodoCompleteTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id": map[string]any{
+				"type":        "string",
+				"description": "Task number from todo_list, e.g. 3",
+			},
+		},
+		"required": []string{"id"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t TodoCompleteTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute marks the task done.
+func (t TodoCompleteTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("todo store is required")
+	}
+	rawID, err := stringArg(args, "id")
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(rawID), "#"))
+	if err != nil {
+		return nil, fmt.Errorf("argument id must be a task number, got %q", rawID)
+	}
+	now := time.Now()
+	task, err := t.Store.Complete(id, now)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: "completed " + task.Format(now)}, nil
+}
+
+// TodoListTool lists the user's tasks.
+type TodoListTool struct {
+	Store *todo.Store
+}
+
+// Name returns the tool name.
+func (t TodoListTool) Name() string {
+	return "todo_list"
+}
+
+// Description returns the tool description for the model.
+func (t TodoListTool) Description() string {
+	return "List open tasks on the user's todo list, earliest due first"
+}
+
+// Schema returns the JSON schema for todo_list args.
+func (t TodoListTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"include_done": map[string]any{
+				"type":        "string",
+				"description": "Set to true to also list tasks completed in the last 30 days",
+			},
+		},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t TodoListTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute lists tasks.
+func (t TodoListTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Store == nil {
+		return nil, errors.New("todo store is required")
+	}
+	includeDone, err := optionalStringArg(args, "include_done", "false")
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := t.Store.List(strings.EqualFold(includeDone, "true"))
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Output: todo.FormatList(tasks, time.Now())}, nil
+}
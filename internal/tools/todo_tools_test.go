@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/scheduler"
+	"github.com/neoclaw-ai/neoclaw/internal/todo"
+)
+
+func TestTodoToolsAddWithReminderCompleteList(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tasks := todo.New(filepath.Join(dir, "tasks.json"))
+	svc := scheduler.NewService(filepath.Join(dir, "jobs.json"), scheduler.NewRunner(scheduler.ActionRunners{}, nil))
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, time.Local)
+	addTool := TodoAddTool{Store: tasks, Service: svc, ChannelID: "telegram", now: func() time.Time { return now }}
+
+	added, err := addTool.Execute(context.Background(), map[string]any{
+		"text":      "buy filters",
+		"due":       "2026-03-07",
+		"remind_at": "2026-03-07T09:00",
+	})
+	if err != nil {
+		t.Fatalf("todo_add: %v", err)
+	}
+	if !strings.HasPrefix(added.Output, "added 1. buy filters (due Sat 2026-03-07); reminder ") {
+		t.Fatalf("unexpected add output %q", added.Output)
+	}
+	jobs, err := svc.List(context.Background())
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ChannelID != "telegram" || jobs[0].Args["message"] != "Reminder: buy filters (todo 1)" {
+		t.Fatalf("unexpected reminder job %#v", jobs)
+	}
+	if !jobs[0].RunAt.Equal(time.Date(2026, 3, 7, 9, 0, 0, 0, time.Local)) {
+		t.Fatalf("unexpected reminder time %s", jobs[0].RunAt)
+	}
+
+	if _, err := addTool.Execute(context.Background(), map[string]any{"text": "late", "remind_at": "2026-03-01"}); err == nil {
+		t.Fatalf("expected past reminder to fail")
+	}
+	if listed, _ := tasks.List(true); len(listed) != 1 {
+		t.Fatalf("expected rejected task not to be saved, got %#v", listed)
+	}
+
+	if _, err := (TodoCompleteTool{Store: tasks}).Execute(context.Background(), map[string]any{"id": "#1"}); err != nil {
+		t.Fatalf("todo_complete: %v", err)
+	}
+	listed, err := TodoListTool{Store: tasks}.Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("todo_list: %v", err)
+	}
+	if listed.Output != "No open tasks." {
+		t.Fatalf("unexpected list output %q", listed.Output)
+	}
+}
@@ -48,9 +48,18 @@ type ApprovalPersister interface {
 	PersistApproval(args map[string]any) error
 }
 
+// MemoryWriter is an optional marker for tools that persist to long-term
+// memory or the daily log. Incognito turns leave these tools out.
+type MemoryWriter interface {
+	WritesMemory()
+}
+
 // ToolResult is the normalized output returned by tools.
 type ToolResult struct {
 	Output string
+	// FullOutputPath is where the untruncated output was saved when Output
+	// was too long to give to the model in full.
+	FullOutputPath string
 }
 
 // TruncateOutput truncates large output to the configured inline limit.
@@ -69,6 +78,10 @@ func TruncateOutput(output string) (*ToolResult, error) {
 // Registry stores tools by unique name.
 type Registry struct {
 	byName map[string]Tool
+	// advertised, when set, limits which tools ToolDefinitions includes.
+	advertised func(Tool) bool
+	// trust overrides defaultTrust for named tools.
+	trust map[string]string
 }
 
 // NewRegistry creates an empty tool registry.
@@ -113,11 +126,35 @@ func (r *Registry) Tools() []Tool {
 	return out
 }
 
+// Filter returns a new registry holding only the tools keep accepts.
+func (r *Registry) Filter(keep func(Tool) bool) *Registry {
+	out := NewRegistry()
+	out.trust = r.trust
+	for name, tool := range r.byName {
+		if keep(tool) {
+			out.byName[name] = tool
+		}
+	}
+	return out
+}
+
+// Advertise returns a registry holding the same tools whose ToolDefinitions
+// only include the tools advertised accepts. Lookup still finds every tool.
+// advertised is asked on every call, so what is offered can grow mid-turn.
+func (r *Registry) Advertise(advertised func(Tool) bool) *Registry {
+	out := r.Filter(func(Tool) bool { return true })
+	out.advertised = advertised
+	return out
+}
+
 // ToolDefinitions converts registered tools into LLM request tool definitions.
 func (r *Registry) ToolDefinitions() []provider.ToolDefinition {
 	tools := r.Tools()
 	defs := make([]provider.ToolDefinition, 0, len(tools))
 	for _, tool := range tools {
+		if r.advertised != nil && !r.advertised(tool) {
+			continue
+		}
 		defs = append(defs, provider.ToolDefinition{
 			Name:        tool.Name(),
 			Description: tool.Description(),
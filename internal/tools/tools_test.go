@@ -2,7 +2,10 @@ package tools
 
 import (
 	"context"
+	"strings"
 	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/config"
 )
 
 func TestRegistryRegisterAndLookup(t *testing.T) {
@@ -33,6 +36,47 @@ func TestRegistryRegisterRejectsDuplicate(t *testing.T) {
 	}
 }
 
+func TestRegistryFilter(t *testing.T) {
+	r := NewRegistry()
+	for _, tool := range []Tool{staticTool{name: "read_file"}, MemoryAppendTool{}, DailyLogAppendTool{}} {
+		if err := r.Register(tool); err != nil {
+			t.Fatalf("register %s: %v", tool.Name(), err)
+		}
+	}
+
+	filtered := r.Filter(func(tool Tool) bool {
+		_, writes := tool.(MemoryWriter)
+		return !writes
+	})
+	if got := len(filtered.Tools()); got != 1 {
+		t.Fatalf("expected 1 tool after filter, got %d", got)
+	}
+	if _, ok := filtered.Lookup("read_file"); !ok {
+		t.Fatalf("expected read_file to remain")
+	}
+	if len(r.Tools()) != 3 {
+		t.Fatalf("expected original registry to be unchanged")
+	}
+}
+
+func TestRegistryTrust(t *testing.T) {
+	r := NewRegistry()
+	r.SetTrust(map[string]string{"read_file": config.TrustInternet})
+	if got := r.Trust("http_request"); got != config.TrustInternet {
+		t.Fatalf("expected http_request to be internet content, got %q", got)
+	}
+	if got := r.Trust("todo_list"); got != config.TrustUser {
+		t.Fatalf("expected todo_list to be user content, got %q", got)
+	}
+	if got := r.Trust("list_dir"); got != config.TrustWorkspace {
+		t.Fatalf("expected other tools to be workspace content, got %q", got)
+	}
+	filtered := r.Filter(func(Tool) bool { return true })
+	if got := filtered.Trust("read_file"); got != config.TrustInternet {
+		t.Fatalf("expected overrides to survive Filter, got %q", got)
+	}
+}
+
 func TestToolDefinitionsSerializesSchema(t *testing.T) {
 	r := NewRegistry()
 	schema := map[string]any{
@@ -112,3 +156,64 @@ func (t staticTool) Execute(_ context.Context, _ map[string]any) (*ToolResult, e
 	}
 	return &ToolResult{Output: "ok"}, nil
 }
+
+func TestLoadToolsTool(t *testing.T) {
+	loaded := map[string]bool{}
+	available := []Tool{
+		LoadToolsTool{},
+		ListShowTool{},
+	}
+	tool := LoadToolsTool{
+		Available: func() []Tool {
+			var out []Tool
+			for _, candidate := range available {
+				if !loaded[candidate.Name()] {
+					out = append(out, candidate)
+				}
+			}
+			return out
+		},
+		Load: func(names []string) {
+			for _, name := range names {
+				loaded[name] = true
+			}
+		},
+	}
+	if desc := tool.Description(); !strings.Contains(desc, "\n- list_show: ") {
+		t.Fatalf("expected list_show in description, got %q", desc)
+	}
+
+	if _, err := tool.Execute(context.Background(), map[string]any{"names": "list_show, nope"}); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected unknown tool error, got %v", err)
+	}
+	result, err := tool.Execute(context.Background(), map[string]any{"names": "list_show"})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if result.Output != "Loaded list_show. Call it now." || !loaded["list_show"] {
+		t.Fatalf("unexpected result %q, loaded %v", result.Output, loaded)
+	}
+	if desc := tool.Description(); strings.Contains(desc, "list_show") {
+		t.Fatalf("loaded tool still listed: %q", desc)
+	}
+}
+
+func TestRegistryAdvertise(t *testing.T) {
+	registry := NewRegistry()
+	for _, tool := range []Tool{ListAddTool{}, ListShowTool{}} {
+		if err := registry.Register(tool); err != nil {
+			t.Fatalf("register: %v", err)
+		}
+	}
+	advertised := registry.Advertise(func(tool Tool) bool { return tool.Name() == "list_show" })
+	defs := advertised.ToolDefinitions()
+	if len(defs) != 1 || defs[0].Name != "list_show" {
+		t.Fatalf("unexpected definitions %+v", defs)
+	}
+	if _, ok := advertised.Lookup("list_add"); !ok {
+		t.Fatalf("expected unadvertised tool to stay callable")
+	}
+	if got := len(registry.ToolDefinitions()); got != 2 {
+		t.Fatalf("original registry changed: %d definitions", got)
+	}
+}
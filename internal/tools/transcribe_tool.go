@@ -0,0 +1,185 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"github.com/neoclaw-ai/neoclaw/internal/transcribe"
+)
+
+const (
+	// transcriptDir holds saved transcripts, relative to the workspace.
+	transcriptDir = "transcripts"
+	// transcriptPreviewChars is how much of a transcript is returned inline.
+	transcriptPreviewChars = 1500
+)
+
+// TranscribeAudioTool transcribes an audio file from the workspace, saves
+// the transcript, and registers it as an artifact.
+type TranscribeAudioTool struct {
+	WorkspaceDir  string
+	Client        *transcribe.Client
+	ChunkDuration time.Duration
+	Artifacts     *artifacts.Store
+	// ResolveChannelID names the chat the transcript artifact belongs to.
+	ResolveChannelID func() string
+
+	// split defaults to transcribe.Split; tests replace it.
+	split func(ctx context.Context, path, dir string, chunk time.Duration) ([]string, error)
+}
+
+// Name returns the tool name.
+func (t TranscribeAudioTool) Name() string {
+	return "transcribe_audio"
+}
+
+// Description returns the tool description for the model.
+func (t TranscribeAudioTool) Description() string {
+	return "Transcribe an audio recording from the workspace (mp3, m4a, wav, ogg, webm, and similar), such as a meeting. Long recordings are split into chunks with timestamps. The full transcript is saved under transcripts/ and registered as an artifact; the result gives its path and the beginning of the text."
+}
+
+// Schema returns the JSON schema for transcribe_audio args.
+func (t TranscribeAudioTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Audio file, absolute or relative to workspace",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t TranscribeAudioTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute transcribes the file chunk by chunk and saves the transcript.
+func (t TranscribeAudioTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Client == nil {
+		return nil, errors.New("transcription is not configured")
+	}
+	pathArg, err := stringArg(args, "path")
+	if err != nil {
+		return nil, err
+	}
+	path, err := resolveInputPath(t.WorkspaceDir, projectPath(ctx, pathArg))
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", pathArg)
+	}
+
+	chunks, cleanup, err := t.chunks(ctx, path, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var b strings.Builder
+	for i, chunk := range chunks {
+		text, err := t.Client.Transcribe(ctx, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("transcribe chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		if len(chunks) > 1 {
+			fmt.Fprintf(&b, "%s ", transcribe.Timestamp(time.Duration(i)*t.ChunkDuration))
+		}
+		b.WriteString(text)
+		b.WriteString("\n\n")
+	}
+	transcript := strings.TrimSpace(b.String()) + "\n"
+
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	outputPath, err := uniquePath(filepath.Join(t.WorkspaceDir, transcriptDir, stem+".txt"))
+	if err != nil {
+		return nil, err
+	}
+	if err := store.WriteFile(outputPath, []byte(transcript)); err != nil {
+		return nil, fmt.Errorf("save transcript: %w", err)
+	}
+	relative := workspaceDisplayPath(t.WorkspaceDir, outputPath)
+	logging.Logger().Info("audio transcribed", "path", path, "chunks", len(chunks), "transcript", outputPath)
+
+	var out strings.Builder
+	words := len(strings.Fields(transcript))
+	fmt.Fprintf(&out, "Transcribed %s (%s words, %d %s). Saved to %s", pathArg, formatWithCommas(words), len(chunks), plural(len(chunks), "chunk", "chunks"), relative)
+	if t.Artifacts != nil {
+		artifact, err := artifactStore(t.Artifacts, t.ResolveChannelID).Add(outputPath, "Transcript of "+filepath.Base(path), time.Now())
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&out, " as artifact %d (/artifact_%d)", artifact.ID, artifact.ID)
+	}
+	out.WriteString(".\n\n")
+	preview := []rune(transcript)
+	if len(preview) > transcriptPreviewChars {
+		fmt.Fprintf(&out, "Beginning of transcript (read_file %s for the rest):\n%s…", relative, string(preview[:transcriptPreviewChars]))
+	} else {
+		out.WriteString(transcript)
+	}
+	return &ToolResult{Output: strings.TrimSpace(out.String())}, nil
+}
+
+// chunks splits the recording when ffmpeg is available, and otherwise
+// sends it whole if it is small enough.
+func (t TranscribeAudioTool) chunks(ctx context.Context, path string, size int64) ([]string, func(), error) {
+	noop := func() {}
+	if t.ChunkDuration <= 0 {
+		if size > transcribe.MaxUploadBytes {
+			return nil, noop, fmt.Errorf("file is larger than %d MB; set transcription.chunk_duration to split it", transcribe.MaxUploadBytes>>20)
+		}
+		return []string{path}, noop, nil
+	}
+
+	dir, err := os.MkdirTemp("", "neoclaw-transcribe-")
+	if err != nil {
+		return nil, noop, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	split := t.split
+	if split == nil {
+		split = transcribe.Split
+	}
+	chunks, err := split(ctx, path, dir, t.ChunkDuration)
+	if errors.Is(err, transcribe.ErrNoFFmpeg) && size <= transcribe.MaxUploadBytes {
+		return []string{path}, cleanup, nil
+	}
+	if err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+	return chunks, cleanup, nil
+}
+
+// uniquePath returns path, or path with -2, -3, ... before the extension if
+// it already exists.
+func uniquePath(path string) (string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	candidate := path
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		} else if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/artifacts"
+	"github.com/neoclaw-ai/neoclaw/internal/transcribe"
+)
+
+func TestTranscribeAudio_ChunksAndSavesArtifact(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{
+		"meetings/standup.m4a":    "audio",
+		"transcripts/standup.txt": "older transcript",
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("missing bearer token")
+		}
+		if r.FormValue("model") != "whisper-1" {
+			t.Errorf("unexpected model %q", r.FormValue("model"))
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("form file: %v", err)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		fmt.Fprintf(w, "text of %s (%s)\n", header.Filename, content)
+	}))
+	defer server.Close()
+
+	store := artifacts.New(filepath.Join(t.TempDir(), "artifacts.json"))
+	tool := TranscribeAudioTool{
+		WorkspaceDir:  workspace,
+		Client:        &transcribe.Client{Endpoint: server.URL, APIKey: "key", Model: "whisper-1", HTTPClient: server.Client()},
+		ChunkDuration: 10 * time.Minute,
+		Artifacts:     store,
+		split: func(_ context.Context, path, dir string, chunk time.Duration) ([]string, error) {
+			var chunks []string
+			for i := range 2 {
+				chunkPath := filepath.Join(dir, fmt.Sprintf("chunk-%04d.mp3", i))
+				if err := os.WriteFile(chunkPath, []byte(fmt.Sprintf("part %d", i)), 0o600); err != nil {
+					return nil, err
+				}
+				chunks = append(chunks, chunkPath)
+			}
+			return chunks, nil
+		},
+	}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"path": "meetings/standup.m4a"})
+	if err != nil {
+		t.Fatalf("transcribe: %v", err)
+	}
+	if !strings.HasPrefix(res.Output, "Transcribed meetings/standup.m4a (12 words, 2 chunks). Saved to transcripts/standup-2.txt as artifact 1 (/artifact_1).") {
+		t.Fatalf("unexpected output %q", res.Output)
+	}
+	got, err := os.ReadFile(filepath.Join(workspace, "transcripts", "standup-2.txt"))
+	if err != nil {
+		t.Fatalf("read transcript: %v", err)
+	}
+	want := "[00:00:00] text of chunk-0000.mp3 (part 0)\n\n[00:10:00] text of chunk-0001.mp3 (part 1)\n"
+	if string(got) != want {
+		t.Fatalf("unexpected transcript %q", got)
+	}
+	items, err := store.List()
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expected one artifact, got %v (%v)", items, err)
+	}
+}
+
+func TestTranscribeAudio_WithoutFFmpegSendsSmallFilesWhole(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{"memo.mp3": "audio"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, header, _ := r.FormFile("file")
+		fmt.Fprintf(w, "hello from %s", header.Filename)
+	}))
+	defer server.Close()
+
+	tool := TranscribeAudioTool{
+		WorkspaceDir:  workspace,
+		Client:        &transcribe.Client{Endpoint: server.URL, HTTPClient: server.Client()},
+		ChunkDuration: 10 * time.Minute,
+		split: func(context.Context, string, string, time.Duration) ([]string, error) {
+			return nil, transcribe.ErrNoFFmpeg
+		},
+	}
+	res, err := tool.Execute(context.Background(), map[string]any{"path": "memo.mp3"})
+	if err != nil {
+		t.Fatalf("transcribe: %v", err)
+	}
+	want := "Transcribed memo.mp3 (3 words, 1 chunk). Saved to transcripts/memo.txt.\n\nhello from memo.mp3"
+	if res.Output != want {
+		t.Fatalf("unexpected output %q", res.Output)
+	}
+}
+
+func TestTranscribeAudio_Errors(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{"memo.mp3": "audio"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad audio", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	tool := TranscribeAudioTool{
+		WorkspaceDir: workspace,
+		Client:       &transcribe.Client{Endpoint: server.URL, HTTPClient: server.Client()},
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": "memo.mp3"}); err == nil || !strings.Contains(err.Error(), "400 Bad Request bad audio") {
+		t.Fatalf("expected API error, got %v", err)
+	}
+
+	tool.Client = &transcribe.Client{Endpoint: "https://api.openai.com/v1/audio/transcriptions", HTTPClient: server.Client()}
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": "memo.mp3"}); err == nil || !strings.Contains(err.Error(), "api_key is required") {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+	if _, err := tool.Execute(context.Background(), map[string]any{"path": "."}); err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Fatalf("expected directory error, got %v", err)
+	}
+}
@@ -0,0 +1,417 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultTranscriptMaxBytes matches the default tool output length.
+	defaultTranscriptMaxBytes = 12000
+	// maxTranscriptResponseBytes caps each page or caption file downloaded.
+	maxTranscriptResponseBytes = 10 << 20
+	// transcriptBlock merges caption cues into one timestamped line per
+	// stretch of about this long, which reads better and costs fewer tokens.
+	transcriptBlock = 30 * time.Second
+)
+
+// GetTranscriptTool fetches the subtitles of a video as timestamped text.
+type GetTranscriptTool struct {
+	Client *http.Client
+	// MaxBytes caps the returned text; the rest is reached with start.
+	MaxBytes int
+}
+
+// cue is one caption line and where it starts in the video.
+type cue struct {
+	Start time.Duration
+	Text  string
+}
+
+// Name returns the tool name.
+func (t GetTranscriptTool) Name() string {
+	return "get_transcript"
+}
+
+// Description returns the tool description for the model.
+func (t GetTranscriptTool) Description() string {
+	return "Fetch the transcript of a video as timestamped text, for summarizing talks and videos without watching them. Works with YouTube links, direct .vtt or .srt subtitle URLs, and pages that embed a <track> subtitle file. Long transcripts are cut off; call again with start set to the last timestamp to continue."
+}
+
+// Schema returns the JSON schema for get_transcript args.
+func (t GetTranscriptTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"url": map[string]any{
+				"type":        "string",
+				"description": "Video page or subtitle file URL",
+			},
+			"language": map[string]any{
+				"type":        "string",
+				"description": "Preferred subtitle language code, e.g. en or de (default: en, then the first available)",
+			},
+			"start": map[string]any{
+				"type":        "string",
+				"description": "Skip to this position, e.g. 12:30 or 1:02:00",
+			},
+		},
+		"required": []string{"url"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t GetTranscriptTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute downloads the subtitles and renders them from start.
+func (t GetTranscriptTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	rawURL, err := stringArg(args, "url")
+	if err != nil {
+		return nil, err
+	}
+	language, err := optionalStringArg(args, "language", "en")
+	if err != nil {
+		return nil, err
+	}
+	startArg, err := optionalStringArg(args, "start", "")
+	if err != nil {
+		return nil, err
+	}
+	var start time.Duration
+	if startArg != "" {
+		if start, err = parseClock(startArg); err != nil {
+			return nil, err
+		}
+	}
+	if t.Client == nil {
+		return nil, errors.New("http client is required")
+	}
+	target, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("url must be an absolute http(s) URL, got %q", rawURL)
+	}
+
+	var title string
+	var cues []cue
+	if id := youtubeVideoID(target); id != "" {
+		title, cues, err = t.youtubeTranscript(ctx, id, language)
+	} else {
+		title, cues, err = t.subtitleTranscript(ctx, target)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(cues) == 0 {
+		return nil, errors.New("the subtitles are empty")
+	}
+	return &ToolResult{Output: t.render(title, cues, start)}, nil
+}
+
+// render writes cues from start as blocks of timestamped text, stopping on
+// a line boundary before MaxBytes.
+func (t GetTranscriptTool) render(title string, cues []cue, start time.Duration) string {
+	maxBytes := t.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultTranscriptMaxBytes
+	}
+	end := cues[len(cues)-1].Start
+
+	var b strings.Builder
+	if title != "" {
+		b.WriteString(title + "\n\n")
+	}
+	var line strings.Builder
+	var lineStart time.Duration
+	written := 0
+	flush := func() bool {
+		if line.Len() == 0 {
+			return true
+		}
+		text := fmt.Sprintf("[%s] %s\n", formatClock(lineStart), line.String())
+		line.Reset()
+		// Keep room for the continuation note, but always make progress.
+		if written > 0 && b.Len()+len(text) > maxBytes-100 {
+			fmt.Fprintf(&b, "[transcript continues to %s; call get_transcript with start=%s for the rest]", formatClock(end), formatClock(lineStart))
+			return false
+		}
+		b.WriteString(text)
+		written++
+		return true
+	}
+	for _, c := range cues {
+		if c.Start < start {
+			continue
+		}
+		if line.Len() > 0 && c.Start-lineStart >= transcriptBlock {
+			if !flush() {
+				return b.String()
+			}
+		}
+		if line.Len() == 0 {
+			lineStart = c.Start
+		} else {
+			line.WriteByte(' ')
+		}
+		line.WriteString(c.Text)
+	}
+	if !flush() {
+		return b.String()
+	}
+	if written == 0 {
+		return fmt.Sprintf("Nothing after %s; the transcript ends at %s.", formatClock(start), formatClock(end))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+var youtubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// youtubeVideoID returns the video ID of a YouTube watch, short, embed, or
+// youtu.be link, or "" for other URLs.
+func youtubeVideoID(u *url.URL) string {
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	var id string
+	switch host {
+	case "youtu.be":
+		id = strings.Trim(u.Path, "/")
+	case "youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		if u.Path == "/watch" {
+			id = u.Query().Get("v")
+		}
+		for _, prefix := range []string{"/shorts/", "/embed/", "/live/"} {
+			if strings.HasPrefix(u.Path, prefix) {
+				id = strings.Trim(strings.TrimPrefix(u.Path, prefix), "/")
+			}
+		}
+	}
+	if !youtubeIDPattern.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// youtubeTranscript reads the caption tracks listed in the watch page and
+// downloads the best match for language.
+func (t GetTranscriptTool) youtubeTranscript(ctx context.Context, id, language string) (string, []cue, error) {
+	page, _, err := t.fetch(ctx, "https://www.youtube.com/watch?v="+id)
+	if err != nil {
+		return "", nil, err
+	}
+	title := pageTitle(page)
+	title = strings.TrimSuffix(title, " - YouTube")
+
+	offset := bytes.Index(page, []byte(`"captionTracks":`))
+	if offset < 0 {
+		return "", nil, errors.New("this video has no subtitles")
+	}
+	var tracks []struct {
+		BaseURL      string `json:"baseUrl"`
+		LanguageCode string `json:"languageCode"`
+		Kind         string `json:"kind"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(page[offset+len(`"captionTracks":`):]))
+	if err := decoder.Decode(&tracks); err != nil || len(tracks) == 0 {
+		return "", nil, errors.New("could not read the video's subtitle list")
+	}
+
+	// Prefer the requested language, then English, then anything; within
+	// each, prefer uploaded subtitles over automatic ones ("asr").
+	best, bestScore := 0, -1
+	for i, track := range tracks {
+		score := 0
+		switch {
+		case strings.EqualFold(track.LanguageCode, language) || strings.HasPrefix(strings.ToLower(track.LanguageCode), strings.ToLower(language)+"-"):
+			score = 4
+		case strings.HasPrefix(track.LanguageCode, "en"):
+			score = 2
+		}
+		if track.Kind != "asr" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	track := tracks[best]
+	body, _, err := t.fetch(ctx, track.BaseURL)
+	if err != nil {
+		return "", nil, err
+	}
+	cues, err := parseTimedText(body)
+	if err != nil {
+		return "", nil, err
+	}
+	label := track.LanguageCode
+	if track.Kind == "asr" {
+		label += ", auto-generated"
+	}
+	return fmt.Sprintf("%s (%s subtitles)", title, label), cues, nil
+}
+
+// subtitleTranscript reads a WebVTT or SRT file, or the first <track>
+// subtitle file of an HTML page.
+func (t GetTranscriptTool) subtitleTranscript(ctx context.Context, target *url.URL) (string, []cue, error) {
+	body, contentType, err := t.fetch(ctx, target.String())
+	if err != nil {
+		return "", nil, err
+	}
+	if !strings.Contains(contentType, "text/html") {
+		return "", parseSubtitles(body), nil
+	}
+
+	title := pageTitle(body)
+	match := trackPattern.FindSubmatch(body)
+	if match == nil {
+		return "", nil, errors.New("no subtitles found on this page; try a direct .vtt or .srt URL")
+	}
+	src, err := target.Parse(html.UnescapeString(string(match[1])))
+	if err != nil {
+		return "", nil, fmt.Errorf("subtitle link: %w", err)
+	}
+	subtitles, _, err := t.fetch(ctx, src.String())
+	if err != nil {
+		return "", nil, err
+	}
+	return title, parseSubtitles(subtitles), nil
+}
+
+var (
+	titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	trackPattern = regexp.MustCompile(`(?is)<track\b[^>]*?\bsrc=["']([^"']+)["']`)
+	tagPattern   = regexp.MustCompile(`<[^>]*>`)
+)
+
+func pageTitle(page []byte) string {
+	match := titlePattern.FindSubmatch(page)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(html.UnescapeString(string(match[1])))
+}
+
+func (t GetTranscriptTool) fetch(ctx context.Context, rawURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+	req.Header.Set("Accept-Language", "en")
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTranscriptResponseBytes))
+	if err != nil {
+		return nil, "", fmt.Errorf("read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("request to %s failed: %s", req.URL.Host, resp.Status)
+	}
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+// parseTimedText reads YouTube caption XML, in either the classic
+// <text start="1.5"> form or the newer <p t="1500"> form.
+func parseTimedText(body []byte) ([]cue, error) {
+	var doc struct {
+		Texts []struct {
+			Start string `xml:"start,attr"`
+			Text  string `xml:",chardata"`
+		} `xml:"text"`
+		Paragraphs []struct {
+			Millis int    `xml:"t,attr"`
+			Inner  string `xml:",innerxml"`
+		} `xml:"body>p"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("parse subtitles: %w", err)
+	}
+	var cues []cue
+	for _, text := range doc.Texts {
+		seconds, err := strconv.ParseFloat(text.Start, 64)
+		if err != nil {
+			continue
+		}
+		// Classic captions escape entities twice (&amp;#39;).
+		cues = appendCue(cues, time.Duration(seconds*float64(time.Second)), html.UnescapeString(text.Text))
+	}
+	for _, p := range doc.Paragraphs {
+		text := html.UnescapeString(tagPattern.ReplaceAllString(p.Inner, ""))
+		cues = appendCue(cues, time.Duration(p.Millis)*time.Millisecond, text)
+	}
+	return cues, nil
+}
+
+// parseSubtitles reads WebVTT and SRT cues. Inline styling and karaoke
+// timestamps are dropped.
+func parseSubtitles(body []byte) []cue {
+	var cues []cue
+	var start time.Duration
+	inCue := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			inCue = false
+		case strings.Contains(line, "-->"):
+			stamp := strings.TrimSpace(strings.SplitN(line, "-->", 2)[0])
+			if parsed, err := parseClock(strings.ReplaceAll(stamp, ",", ".")); err == nil {
+				start, inCue = parsed, true
+			}
+		case inCue:
+			cues = appendCue(cues, start, html.UnescapeString(tagPattern.ReplaceAllString(line, "")))
+		}
+	}
+	return cues
+}
+
+// appendCue adds text unless it is empty or repeats the previous line, as
+// rolling automatic captions do.
+func appendCue(cues []cue, start time.Duration, text string) []cue {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" || (len(cues) > 0 && cues[len(cues)-1].Text == text) {
+		return cues
+	}
+	return append(cues, cue{Start: start, Text: text})
+}
+
+// parseClock reads 75, 1:15, 01:01:15, or 00:01:15.500.
+func parseClock(value string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid position %q; use MM:SS or HH:MM:SS", value)
+	}
+	var total float64
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid position %q; use MM:SS or HH:MM:SS", value)
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total * float64(time.Second)), nil
+}
+
+// formatClock renders an offset as M:SS, or H:MM:SS from an hour on.
+func formatClock(offset time.Duration) string {
+	total := int(offset.Seconds())
+	if total >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total/60%60, total%60)
+	}
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// transcriptClient serves pages by full URL and fails on anything else.
+func transcriptClient(t *testing.T, pages map[string]string, contentTypes map[string]string) *http.Client {
+	t.Helper()
+	return &http.Client{
+		Transport: webRoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, ok := pages[req.URL.String()]
+			if !ok {
+				t.Fatalf("unexpected request %s", req.URL)
+			}
+			header := make(http.Header)
+			header.Set("Content-Type", contentTypes[req.URL.String()])
+			return &http.Response{
+				StatusCode: 200,
+				Status:     "200 OK",
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+}
+
+func TestGetTranscript_YouTubePrefersRequestedLanguage(t *testing.T) {
+	page := `<html><head><title>Go Concurrency Patterns - YouTube</title></head><script>var ytInitialPlayerResponse = {"captions":{"playerCaptionsTracklistRenderer":{"captionTracks":[` +
+		`{"baseUrl":"https://www.youtube.com/api/timedtext?v=f6kdp27TYZs&lang=en&kind=asr","languageCode":"en","kind":"asr"},` +
+		`{"baseUrl":"https://www.youtube.com/api/timedtext?v=f6kdp27TYZs&lang=de","languageCode":"de"}` +
+		`]}}};</script></html>`
+	captions := `<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+		`<text start="0.5" dur="2">Hallo &amp;amp; willkommen</text>` +
+		`<text start="3" dur="2">zu Go</text>` +
+		`<text start="3.5" dur="2">zu Go</text>` +
+		`<text start="41.2" dur="2">Kanäle</text>` +
+		`</transcript>`
+	tool := GetTranscriptTool{Client: transcriptClient(t, map[string]string{
+		"https://www.youtube.com/watch?v=f6kdp27TYZs":                 page,
+		"https://www.youtube.com/api/timedtext?v=f6kdp27TYZs&lang=de": captions,
+	}, nil)}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"url": "https://youtu.be/f6kdp27TYZs?t=10", "language": "de"})
+	if err != nil {
+		t.Fatalf("get transcript: %v", err)
+	}
+	want := "Go Concurrency Patterns (de subtitles)\n\n[0:00] Hallo & willkommen zu Go\n[0:41] Kanäle"
+	if res.Output != want {
+		t.Fatalf("unexpected transcript %q", res.Output)
+	}
+}
+
+func TestGetTranscript_VTTPagingAndTrackLinks(t *testing.T) {
+	vtt := "WEBVTT\n\n00:00:01.000 --> 00:00:04.000\n<c>First</c> line\n\n" +
+		"00:00:40.000 --> 00:00:44.000\nSecond line\n\n" +
+		"01:00:05.000 --> 01:00:09.000\nThird line\n"
+	pages := map[string]string{
+		"https://talks.example.com/talk":          `<title>Talk</title><video><track kind="captions" src="/subs/talk.vtt"></video>`,
+		"https://talks.example.com/subs/talk.vtt": vtt,
+	}
+	tool := GetTranscriptTool{
+		Client:   transcriptClient(t, pages, map[string]string{"https://talks.example.com/talk": "text/html; charset=utf-8"}),
+		MaxBytes: 140,
+	}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"url": "https://talks.example.com/talk"})
+	if err != nil {
+		t.Fatalf("get transcript: %v", err)
+	}
+	want := "Talk\n\n[0:01] First line\n[transcript continues to 1:00:05; call get_transcript with start=0:40 for the rest]"
+	if res.Output != want {
+		t.Fatalf("unexpected first page %q", res.Output)
+	}
+
+	res, err = tool.Execute(context.Background(), map[string]any{"url": "https://talks.example.com/subs/talk.vtt", "start": "0:40"})
+	if err != nil {
+		t.Fatalf("get transcript from 0:40: %v", err)
+	}
+	if res.Output != "[0:40] Second line\n[1:00:05] Third line" {
+		t.Fatalf("unexpected second page %q", res.Output)
+	}
+
+	res, err = tool.Execute(context.Background(), map[string]any{"url": "https://talks.example.com/subs/talk.vtt", "start": "2:00:00"})
+	if err != nil || res.Output != "Nothing after 2:00:00; the transcript ends at 1:00:05." {
+		t.Fatalf("unexpected result past the end: %v %v", res, err)
+	}
+}
+
+func TestGetTranscript_Errors(t *testing.T) {
+	tool := GetTranscriptTool{Client: transcriptClient(t, map[string]string{
+		"https://www.youtube.com/watch?v=f6kdp27TYZs": "<title>No captions - YouTube</title>",
+		"https://example.com/":                        "<title>Plain page</title>",
+	}, map[string]string{"https://example.com/": "text/html"})}
+
+	cases := map[string]map[string]any{
+		"has no subtitles":     {"url": "https://www.youtube.com/watch?v=f6kdp27TYZs"},
+		"no subtitles found":   {"url": "https://example.com/"},
+		"absolute http(s) URL": {"url": "ftp://example.com/talk.vtt"},
+		"invalid position":     {"url": "https://example.com/", "start": "ten"},
+	}
+	for want, args := range cases {
+		if _, err := tool.Execute(context.Background(), args); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	libreTranslateEndpoint = "https://libretranslate.com"
+	deepLEndpoint          = "https://api.deepl.com"
+	deepLFreeEndpoint      = "https://api-free.deepl.com"
+	// maxTranslateChars caps the text sent in one call.
+	maxTranslateChars = 20000
+)
+
+// TranslateTool translates text through LibreTranslate or DeepL, detecting
+// the source language when it is not given.
+type TranslateTool struct {
+	Client   *http.Client
+	Provider string
+	// Endpoint overrides the provider's public API URL, e.g. for a
+	// self-hosted LibreTranslate.
+	Endpoint string
+	APIKey   string
+}
+
+// Name returns the tool name.
+func (t TranslateTool) Name() string {
+	return "translate"
+}
+
+// Description returns the tool description for the model.
+func (t TranslateTool) Description() string {
+	return "Translate text into another language with a translation service, detecting the source language. Returns the detected language and the translation. Use it to read messages in a language you are unsure of, or to send a reply in a household member's preferred language (check memory for it)."
+}
+
+// Schema returns the JSON schema for translate args.
+func (t TranslateTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"text": map[string]any{
+				"type":        "string",
+				"description": "Text to translate",
+			},
+			"target": map[string]any{
+				"type":        "string",
+				"description": "Target language code, e.g. en, de, es, pt-BR",
+			},
+			"source": map[string]any{
+				"type":        "string",
+				"description": "Source language code (default: detect)",
+			},
+		},
+		"required": []string{"text", "target"},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t TranslateTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute translates the text and reports the source language.
+func (t TranslateTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	text, err := stringArg(args, "text")
+	if err != nil {
+		return nil, err
+	}
+	target, err := stringArg(args, "target")
+	if err != nil {
+		return nil, err
+	}
+	source, err := optionalStringArg(args, "source", "")
+	if err != nil {
+		return nil, err
+	}
+	if len([]rune(text)) > maxTranslateChars {
+		return nil, fmt.Errorf("text is longer than %d characters; translate it in parts", maxTranslateChars)
+	}
+	target = strings.TrimSpace(target)
+	source = strings.TrimSpace(source)
+	if strings.EqualFold(source, "auto") {
+		source = ""
+	}
+	if t.Client == nil {
+		return nil, errors.New("http client is required")
+	}
+
+	var translated, detected string
+	switch strings.ToLower(strings.TrimSpace(t.Provider)) {
+	case "":
+		return nil, errors.New("translation.provider is not configured")
+	case "libretranslate":
+		translated, detected, err = t.libreTranslate(ctx, text, source, target)
+	case "deepl":
+		if strings.TrimSpace(t.APIKey) == "" {
+			return nil, errors.New("translation.api_key is required for deepl")
+		}
+		translated, detected, err = t.deepL(ctx, text, source, target)
+	default:
+		return nil, fmt.Errorf("unsupported translation.provider %s", t.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	from := source
+	if from == "" {
+		from = detected + " (detected)"
+	}
+	return &ToolResult{Output: fmt.Sprintf("%s → %s:\n%s", from, strings.ToLower(target), translated)}, nil
+}
+
+func (t TranslateTool) libreTranslate(ctx context.Context, text, source, target string) (string, string, error) {
+	if source == "" {
+		source = "auto"
+	}
+	payload := map[string]string{
+		"q":      text,
+		"source": strings.ToLower(source),
+		"target": strings.ToLower(target),
+		"format": "text",
+	}
+	if t.APIKey != "" {
+		payload["api_key"] = t.APIKey
+	}
+	endpoint := t.Endpoint
+	if strings.TrimSpace(endpoint) == "" {
+		endpoint = libreTranslateEndpoint
+	}
+	var response struct {
+		TranslatedText   string `json:"translatedText"`
+		DetectedLanguage struct {
+			Language string `json:"language"`
+		} `json:"detectedLanguage"`
+	}
+	if err := t.post(ctx, strings.TrimRight(endpoint, "/")+"/translate", payload, nil, &response); err != nil {
+		return "", "", err
+	}
+	return response.TranslatedText, response.DetectedLanguage.Language, nil
+}
+
+func (t TranslateTool) deepL(ctx context.Context, text, source, target string) (string, string, error) {
+	payload := map[string]any{
+		"text":        []string{text},
+		"target_lang": strings.ToUpper(target),
+	}
+	if source != "" {
+		// DeepL source languages have no regional variant.
+		payload["source_lang"] = strings.ToUpper(strings.SplitN(source, "-", 2)[0])
+	}
+	endpoint := t.Endpoint
+	if strings.TrimSpace(endpoint) == "" {
+		// Free-plan keys end in ":fx" and use a separate host.
+		endpoint = deepLEndpoint
+		if strings.HasSuffix(t.APIKey, ":fx") {
+			endpoint = deepLFreeEndpoint
+		}
+	}
+	var response struct {
+		Translations []struct {
+			Text                   string `json:"text"`
+			DetectedSourceLanguage string `json:"detected_source_language"`
+		} `json:"translations"`
+	}
+	headers := map[string]string{"Authorization": "DeepL-Auth-Key " + t.APIKey}
+	if err := t.post(ctx, strings.TrimRight(endpoint, "/")+"/v2/translate", payload, headers, &response); err != nil {
+		return "", "", err
+	}
+	if len(response.Translations) == 0 {
+		return "", "", errors.New("translation response was empty")
+	}
+	first := response.Translations[0]
+	return first.Text, strings.ToLower(first.DetectedSourceLanguage), nil
+}
+
+// post sends payload as JSON and decodes the JSON reply into out.
+func (t TranslateTool) post(ctx context.Context, url string, payload any, headers map[string]string, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create translation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", defaultUserAgent)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute translation request: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("read translation response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &apiErr)
+		detail := strings.TrimSpace(apiErr.Error + " " + apiErr.Message)
+		if detail == "" {
+			return fmt.Errorf("translation request failed: %s", resp.Status)
+		}
+		return fmt.Errorf("translation request failed: %s: %s", resp.Status, detail)
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decode translation response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestTranslateTool_LibreTranslateDetectsSource(t *testing.T) {
+	client := &http.Client{
+		Transport: webRoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.String() != "http://translate.lan:5000/translate" {
+				t.Fatalf("unexpected url %s", req.URL)
+			}
+			var payload map[string]string
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+			if payload["source"] != "auto" || payload["target"] != "en" || payload["q"] != "Wo ist der Schlüssel?" {
+				t.Fatalf("unexpected payload %v", payload)
+			}
+			if _, ok := payload["api_key"]; ok {
+				t.Fatalf("api_key sent without a key configured")
+			}
+			return jsonResponse(200, `{"translatedText":"Where is the key?","detectedLanguage":{"confidence":92,"language":"de"}}`), nil
+		}),
+	}
+	tool := TranslateTool{Client: client, Provider: "libretranslate", Endpoint: "http://translate.lan:5000/"}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"text": "Wo ist der Schlüssel?", "target": "EN"})
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	if res.Output != "de (detected) → en:\nWhere is the key?" {
+		t.Fatalf("unexpected output %q", res.Output)
+	}
+}
+
+func TestTranslateTool_DeepL(t *testing.T) {
+	client := &http.Client{
+		Transport: webRoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.String() != "https://api-free.deepl.com/v2/translate" {
+				t.Fatalf("unexpected url %s", req.URL)
+			}
+			if got := req.Header.Get("Authorization"); got != "DeepL-Auth-Key key:fx" {
+				t.Fatalf("unexpected auth header %q", got)
+			}
+			var payload struct {
+				Text       []string `json:"text"`
+				TargetLang string   `json:"target_lang"`
+				SourceLang string   `json:"source_lang"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+			if payload.TargetLang != "PT-BR" || payload.SourceLang != "EN" || len(payload.Text) != 1 {
+				t.Fatalf("unexpected payload %+v", payload)
+			}
+			return jsonResponse(200, `{"translations":[{"detected_source_language":"EN","text":"Jantar às sete"}]}`), nil
+		}),
+	}
+	tool := TranslateTool{Client: client, Provider: "deepl", APIKey: "key:fx"}
+
+	res, err := tool.Execute(context.Background(), map[string]any{"text": "Dinner at seven", "target": "pt-BR", "source": "en-US"})
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	if res.Output != "en-US → pt-br:\nJantar às sete" {
+		t.Fatalf("unexpected output %q", res.Output)
+	}
+}
+
+func TestTranslateTool_Errors(t *testing.T) {
+	client := &http.Client{
+		Transport: webRoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(400, `{"error":"xx is not supported"}`), nil
+		}),
+	}
+	args := map[string]any{"text": "hello", "target": "xx"}
+	cases := map[string]TranslateTool{
+		"translation.provider is not configured": {Client: client},
+		"translation.api_key is required":        {Client: client, Provider: "deepl"},
+		"unsupported translation.provider":       {Client: client, Provider: "babelfish"},
+		"xx is not supported":                    {Client: client, Provider: "libretranslate"},
+		"http client is required":                {Provider: "libretranslate"},
+	}
+	for want, tool := range cases {
+		if _, err := tool.Execute(context.Background(), args); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
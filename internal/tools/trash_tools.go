@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/trash"
+)
+
+// DeleteFileTool moves a workspace file to the trash.
+type DeleteFileTool struct {
+	WorkspaceDir string
+	SecurityMode string
+	Trash        *trash.Bin
+}
+
+// Name returns the tool name.
+func (t DeleteFileTool) Name() string {
+	return "delete_file"
+}
+
+// Description returns the tool description for the model.
+func (t DeleteFileTool) Description() string {
+	return "Delete a file by moving it to the trash. Use this instead of rm; the user can restore it with restore_deleted."
+}
+
+// Schema returns the JSON schema for delete_file args.
+func (t DeleteFileTool) Schema() map[string]any {
+	return trashPathSchema("File path relative to workspace or absolute path under workspace")
+}
+
+// Permission declares default permission behavior for this tool.
+func (t DeleteFileTool) Permission() Permission {
+	return RequiresApproval
+}
+
+// SummarizeArgs returns a concise approval prompt summary for delete_file.
+func (t DeleteFileTool) SummarizeArgs(args map[string]any) string {
+	path, _ := args["path"].(string)
+	return fmt.Sprintf("delete_file: path=%q (moved to trash)", strings.TrimSpace(path))
+}
+
+// Execute moves the file to the trash.
+func (t DeleteFileTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	return moveToTrash(ctx, t.WorkspaceDir, t.SecurityMode, t.Trash, args, false)
+}
+
+// DeleteDirTool moves a workspace directory and everything in it to the trash.
+type DeleteDirTool struct {
+	WorkspaceDir string
+	SecurityMode string
+	Trash        *trash.Bin
+}
+
+// Name returns the tool name.
+func (t DeleteDirTool) Name() string {
+	return "delete_dir"
+}
+
+// Description returns the tool description for the model.
+func (t DeleteDirTool) Description() string {
+	return "Delete a directory and its contents by moving it to the trash. Use this instead of rm -r; the user can restore it with restore_deleted."
+}
+
+// Schema returns the JSON schema for delete_dir args.
+func (t DeleteDirTool) Schema() map[string]any {
+	return trashPathSchema("Directory path relative to workspace or absolute path under workspace")
+}
+
+// Permission declares default permission behavior for this tool.
+func (t DeleteDirTool) Permission() Permission {
+	return RequiresApproval
+}
+
+// SummarizeArgs returns a concise approval prompt summary for delete_dir.
+func (t DeleteDirTool) SummarizeArgs(args map[string]any) string {
+	path, _ := args["path"].(string)
+	return fmt.Sprintf("delete_dir: path=%q and everything in it (moved to trash)", strings.TrimSpace(path))
+}
+
+// Execute moves the directory to the trash.
+func (t DeleteDirTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	return moveToTrash(ctx, t.WorkspaceDir, t.SecurityMode, t.Trash, args, true)
+}
+
+// RestoreDeletedTool moves an item from the trash back to where it was.
+type RestoreDeletedTool struct {
+	Trash *trash.Bin
+}
+
+// Name returns the tool name.
+func (t RestoreDeletedTool) Name() string {
+	return "restore_deleted"
+}
+
+// Description returns the tool description for the model.
+func (t RestoreDeletedTool) Description() string {
+	return "Restore a file or directory removed with delete_file or delete_dir. Without an id, lists what is in the trash."
+}
+
+// Schema returns the JSON schema for restore_deleted args.
+func (t RestoreDeletedTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id": map[string]any{
+				"type":        "string",
+				"description": "Trash item id returned by delete_file or delete_dir; omit to list the trash",
+			},
+		},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t RestoreDeletedTool) Permission() Permission {
+	return AutoApprove
+}
+
+// Execute restores one item, or lists the trash when no id is given.
+func (t RestoreDeletedTool) Execute(_ context.Context, args map[string]any) (*ToolResult, error) {
+	if t.Trash == nil {
+		return nil, errors.New("trash is not configured")
+	}
+	raw, _ := args["id"].(string)
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "#")
+	if raw == "" {
+		items, err := t.Trash.List()
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			return &ToolResult{Output: "Trash is empty."}, nil
+		}
+		lines := make([]string, 0, len(items))
+		for _, item := range items {
+			lines = append(lines, FormatTrashItem(item))
+		}
+		return &ToolResult{Output: strings.Join(lines, "\n")}, nil
+	}
+
+	id, err := strconv.Atoi(raw)
+	if err != nil || id < 1 {
+		return nil, fmt.Errorf("invalid trash id %q", raw)
+	}
+	item, err := t.Trash.Restore(id)
+	if err != nil {
+		return nil, err
+	}
+	logging.Logger().Info("restored from trash", "id", item.ID, "path", item.Path)
+	return &ToolResult{Output: "restored " + item.Path}, nil
+}
+
+// FormatTrashItem renders one trash item as a single line.
+func FormatTrashItem(item trash.Item) string {
+	kind := "file"
+	if item.Dir {
+		kind = "dir"
+	}
+	return fmt.Sprintf("#%d %s %s (%s bytes) deleted %s", item.ID, kind, item.Path, formatWithCommas(int(item.Size)), item.DeletedAt.Local().Format("2006-01-02 15:04"))
+}
+
+func trashPathSchema(description string) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": description,
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+// moveToTrash resolves path like write_file does and moves it to the trash.
+func moveToTrash(ctx context.Context, workspaceDir, securityMode string, bin *trash.Bin, args map[string]any, wantDir bool) (*ToolResult, error) {
+	if bin == nil {
+		return nil, errors.New("trash is not configured")
+	}
+	pathArg, err := stringArg(args, "path")
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := resolveWritePath(workspaceDir, securityMode, projectPath(ctx, pathArg))
+	if err != nil {
+		return nil, err
+	}
+	if isWorkspaceRoot(workspaceDir, path) {
+		return nil, errors.New("refusing to delete the workspace itself")
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", pathArg, err)
+	}
+	switch {
+	case wantDir && !info.IsDir():
+		return nil, fmt.Errorf("%s is not a directory; use delete_file", pathArg)
+	case !wantDir && info.IsDir():
+		return nil, fmt.Errorf("%s is a directory; use delete_dir", pathArg)
+	}
+
+	item, err := bin.Move(path, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	logging.Logger().Info("moved to trash", "id", item.ID, "path", item.Path, "dir", item.Dir)
+	return &ToolResult{Output: fmt.Sprintf("moved %s to trash as #%d; restore_deleted with id %d puts it back", item.Path, item.ID, item.ID)}, nil
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/trash"
+)
+
+func TestDeleteFile_MovesToTrashAndRestores(t *testing.T) {
+	dir := t.TempDir()
+	workspace := filepath.Join(dir, "workspace")
+	if err := os.MkdirAll(workspace, 0o755); err != nil {
+		t.Fatalf("mkdir workspace: %v", err)
+	}
+	path := filepath.Join(workspace, "notes.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	bin := trash.New(filepath.Join(dir, "trash"))
+
+	deleteTool := DeleteFileTool{WorkspaceDir: workspace, Trash: bin}
+	if deleteTool.Permission() != RequiresApproval {
+		t.Fatal("expected delete_file to require approval")
+	}
+	res, err := deleteTool.Execute(context.Background(), map[string]any{"path": "notes.txt"})
+	if err != nil {
+		t.Fatalf("delete file: %v", err)
+	}
+	if !strings.Contains(res.Output, "as #1") {
+		t.Fatalf("expected trash id in output, got %q", res.Output)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected file moved away, got %v", err)
+	}
+
+	restoreTool := RestoreDeletedTool{Trash: bin}
+	res, err = restoreTool.Execute(context.Background(), map[string]any{})
+	if err != nil || !strings.Contains(res.Output, "#1 file "+path) {
+		t.Fatalf("expected trash listing, got %q %v", res.Output, err)
+	}
+	if _, err := restoreTool.Execute(context.Background(), map[string]any{"id": "1"}); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "hello" {
+		t.Fatalf("expected restored file, got %q %v", data, err)
+	}
+}
+
+func TestDeleteFile_RejectsWrongKindAndOutsideWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	workspace := filepath.Join(dir, "workspace")
+	if err := os.MkdirAll(filepath.Join(workspace, "build"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "outside.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	bin := trash.New(filepath.Join(dir, "trash"))
+
+	if _, err := (DeleteFileTool{WorkspaceDir: workspace, Trash: bin}).Execute(context.Background(), map[string]any{"path": "build"}); err == nil || !strings.Contains(err.Error(), "use delete_dir") {
+		t.Fatalf("expected directory error, got %v", err)
+	}
+	if _, err := (DeleteDirTool{WorkspaceDir: workspace, Trash: bin}).Execute(context.Background(), map[string]any{"path": "."}); err == nil || !strings.Contains(err.Error(), "workspace itself") {
+		t.Fatalf("expected workspace root refusal, got %v", err)
+	}
+	if _, err := (DeleteFileTool{WorkspaceDir: workspace, Trash: bin}).Execute(context.Background(), map[string]any{"path": filepath.Join(dir, "outside.txt")}); err == nil || !strings.Contains(err.Error(), "outside workspace") {
+		t.Fatalf("expected outside workspace error, got %v", err)
+	}
+	if _, err := (DeleteDirTool{WorkspaceDir: workspace, Trash: bin}).Execute(context.Background(), map[string]any{"path": "build"}); err != nil {
+		t.Fatalf("delete dir: %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultTreeDepth = 3
+	maxTreeDepth     = 10
+	// maxTreeLines caps how many entries one tree call renders.
+	maxTreeLines = 400
+)
+
+// TreeTool renders a depth-limited directory tree that honors .gitignore.
+type TreeTool struct {
+	WorkspaceDir string
+}
+
+// Name returns the tool name.
+func (t TreeTool) Name() string {
+	return "tree"
+}
+
+// Description returns the tool description for the model.
+func (t TreeTool) Description() string {
+	return "Show a directory tree with file sizes and per-directory totals, skipping .git and anything matched by .gitignore. Use this to get oriented in a project instead of repeated list_dir calls."
+}
+
+// Schema returns the JSON schema for tree args.
+func (t TreeTool) Schema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Directory to show, absolute or relative to workspace (default: workspace)",
+			},
+			"depth": map[string]any{
+				"type":        "string",
+				"description": fmt.Sprintf("How many levels to expand, 1 to %d (default %d)", maxTreeDepth, defaultTreeDepth),
+			},
+		},
+	}
+}
+
+// Permission declares default permission behavior for this tool.
+func (t TreeTool) Permission() Permission {
+	return AutoApprove
+}
+
+// treeNode is one file or directory; directories carry totals for
+// everything below them, including levels deeper than the rendered depth.
+type treeNode struct {
+	name     string
+	dir      bool
+	size     int64
+	files    int
+	children []*treeNode
+}
+
+// Execute walks the directory and renders it.
+func (t TreeTool) Execute(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	pathArg, err := optionalStringArg(args, "path", ".")
+	if err != nil {
+		return nil, err
+	}
+	depthArg, err := optionalStringArg(args, "depth", strconv.Itoa(defaultTreeDepth))
+	if err != nil {
+		return nil, err
+	}
+	depth, err := strconv.Atoi(depthArg)
+	if err != nil || depth < 1 || depth > maxTreeDepth {
+		return nil, fmt.Errorf("depth must be a number from 1 to %d", maxTreeDepth)
+	}
+
+	root, err := resolveInputPath(t.WorkspaceDir, projectPath(ctx, pathArg))
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", pathArg)
+	}
+
+	node := &treeNode{name: filepath.Base(root), dir: true}
+	buildTree(root, "", node, loadGitignore(root, "", nil), depth)
+
+	var lines []string
+	lines = append(lines, formatTreeNode(node))
+	hidden := renderTree(node.children, "", &lines)
+	if hidden > 0 {
+		lines = append(lines, fmt.Sprintf("[%d more entries not shown; pick a subdirectory or lower depth]", hidden))
+	}
+	return &ToolResult{Output: strings.Join(lines, "\n")}, nil
+}
+
+// buildTree fills node with the entries of dir, keeping children only for
+// the first depth levels and totals for all of them.
+func buildTree(dir, rel string, node *treeNode, rules []ignoreRule, depth int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == ".git" {
+			continue
+		}
+		childRel := path.Join(rel, name)
+		if ignored(rules, childRel, entry.IsDir()) {
+			continue
+		}
+		child := &treeNode{name: name, dir: entry.IsDir()}
+		if child.dir {
+			childDir := filepath.Join(dir, name)
+			buildTree(childDir, childRel, child, loadGitignore(childDir, childRel, rules), depth-1)
+			node.files += child.files
+		} else {
+			if info, err := entry.Info(); err == nil {
+				child.size = info.Size()
+			}
+			node.files++
+		}
+		node.size += child.size
+		if depth > 0 {
+			node.children = append(node.children, child)
+		}
+	}
+	sort.Slice(node.children, func(i, j int) bool {
+		return node.children[i].name < node.children[j].name
+	})
+}
+
+// renderTree appends one line per node until maxTreeLines and returns how
+// many entries did not fit.
+func renderTree(nodes []*treeNode, indent string, lines *[]string) int {
+	hidden := 0
+	for i, node := range nodes {
+		if len(*lines) >= maxTreeLines {
+			hidden += countTreeNodes(nodes[i:])
+			break
+		}
+		branch, next := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, next = "└── ", "    "
+		}
+		*lines = append(*lines, indent+branch+formatTreeNode(node))
+		hidden += renderTree(node.children, indent+next, lines)
+	}
+	return hidden
+}
+
+func countTreeNodes(nodes []*treeNode) int {
+	count := len(nodes)
+	for _, node := range nodes {
+		count += countTreeNodes(node.children)
+	}
+	return count
+}
+
+func formatTreeNode(node *treeNode) string {
+	if !node.dir {
+		return fmt.Sprintf("%s (%s)", node.name, formatSize(node.size))
+	}
+	return fmt.Sprintf("%s/ (%d %s, %s)", node.name, node.files, plural(node.files, "file", "files"), formatSize(node.size))
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// ignoreRule is one .gitignore line, scoped to the directory holding it.
+type ignoreRule struct {
+	// base is the slash path of the .gitignore's directory, "" for the root.
+	base     string
+	segments []string
+	negate   bool
+	dirOnly  bool
+	// anchored rules contain a slash and match from base; others match a
+	// name at any level below it.
+	anchored bool
+}
+
+// loadGitignore returns parent plus the rules from dir/.gitignore, if any.
+func loadGitignore(dir, rel string, parent []ignoreRule) []ignoreRule {
+	file, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return parent
+	}
+	defer file.Close()
+
+	rules := append([]ignoreRule{}, parent...)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{base: rel}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		rule.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+		rule.segments = strings.Split(line, "/")
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ignored applies rules in order; the last matching rule wins.
+func ignored(rules []ignoreRule, rel string, dir bool) bool {
+	result := false
+	for _, rule := range rules {
+		if rule.dirOnly && !dir {
+			continue
+		}
+		name := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			name = strings.TrimPrefix(rel, rule.base+"/")
+		}
+		segments := strings.Split(name, "/")
+		var matched bool
+		if rule.anchored {
+			matched = matchGlob(rule.segments, segments)
+		} else {
+			matched = matchGlob(rule.segments, segments[len(segments)-1:])
+		}
+		if matched {
+			result = !rule.negate
+		}
+	}
+	return result
+}
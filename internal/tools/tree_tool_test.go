@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTree_RendersSizesAndHonorsGitignore(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{
+		".gitignore":              "*.log\nbuild/\n!keep.log\n",
+		".git/HEAD":               "ref: refs/heads/main\n",
+		"go.mod":                  "module example\n",
+		"debug.log":               "noise",
+		"keep.log":                "kept",
+		"build/out.bin":           "binary",
+		"cmd/app/main.go":         "package main\n",
+		"internal/.gitignore":     "/generated\n",
+		"internal/generated/x.go": "package generated\n",
+		"internal/core/core.go":   strings.Repeat("x", 2048),
+	})
+
+	res, err := (TreeTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"depth": "2"})
+	if err != nil {
+		t.Fatalf("tree: %v", err)
+	}
+	for _, want := range []string{
+		"├── cmd/ (1 file, 13 B)",
+		"│   └── app/ (1 file, 13 B)",
+		"├── go.mod (15 B)",
+		"├── internal/ (2 files, 2.0 KB)",
+		"│   ├── .gitignore (11 B)",
+		"│   └── core/ (1 file, 2.0 KB)",
+		"└── keep.log (4 B)",
+	} {
+		if !strings.Contains(res.Output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, res.Output)
+		}
+	}
+	for _, unwanted := range []string{"debug.log", "build", "HEAD", "generated", "main.go"} {
+		if strings.Contains(res.Output, unwanted) {
+			t.Fatalf("did not expect %s in output:\n%s", unwanted, res.Output)
+		}
+	}
+}
+
+func TestTree_RejectsBadDepthAndFiles(t *testing.T) {
+	workspace := t.TempDir()
+	writeFixtures(t, workspace, map[string]string{"a.txt": "a"})
+
+	if _, err := (TreeTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"depth": "0"}); err == nil {
+		t.Fatal("expected depth error")
+	}
+	if _, err := (TreeTool{WorkspaceDir: workspace}).Execute(context.Background(), map[string]any{"path": "a.txt"}); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Fatalf("expected not a directory error, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package tools
+
+import "github.com/neoclaw-ai/neoclaw/internal/config"
+
+// defaultTrust is the trust level of built-in tools whose output is not
+// config.TrustWorkspace, the level of every other tool.
+var defaultTrust = map[string]string{
+	"http_request":   config.TrustInternet,
+	"web_search":     config.TrustInternet,
+	"get_transcript": config.TrustInternet,
+
+	"memory_append":    config.TrustUser,
+	"memory_search":    config.TrustUser,
+	"memory_tags":      config.TrustUser,
+	"memory_retag":     config.TrustUser,
+	"memory_merge":     config.TrustUser,
+	"search_logs":      config.TrustUser,
+	"daily_log_append": config.TrustUser,
+	"contact_lookup":   config.TrustUser,
+	"todo_add":         config.TrustUser,
+	"todo_complete":    config.TrustUser,
+	"todo_list":        config.TrustUser,
+	"list_add":         config.TrustUser,
+	"list_remove":      config.TrustUser,
+	"list_show":        config.TrustUser,
+	"log_expense":      config.TrustUser,
+	"expense_report":   config.TrustUser,
+	"track_metric":     config.TrustUser,
+	"metric_report":    config.TrustUser,
+	"start_timer":      config.TrustUser,
+	"check_timer":      config.TrustUser,
+	"calculate":        config.TrustUser,
+}
+
+// SetTrust overrides the trust level of named tools' output with
+// config.Trust* values.
+func (r *Registry) SetTrust(overrides map[string]string) {
+	r.trust = overrides
+}
+
+// Trust returns the config.Trust* level of the named tool's output.
+func (r *Registry) Trust(name string) string {
+	if level, ok := r.trust[name]; ok {
+		return level
+	}
+	if level, ok := defaultTrust[name]; ok {
+		return level
+	}
+	return config.TrustWorkspace
+}
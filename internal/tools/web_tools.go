@@ -8,9 +8,6 @@ import (
 	"io"
 	"net/http"
 	"strings"
-
-	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
-	"github.com/neoclaw-ai/neoclaw/internal/logging"
 )
 
 const braveSearchEndpoint = "https://api.search.brave.com/res/v1/web/search"
@@ -265,17 +262,6 @@ func (t HTTPRequestTool) Execute(ctx context.Context, args map[string]any) (*Too
 	return TruncateOutput(output)
 }
 
-// htmlToMarkdown converts an HTML string to Markdown. On error it logs and
-// returns the original HTML so the caller always gets usable output.
-func htmlToMarkdown(html string) string {
-	md, err := htmltomarkdown.ConvertString(html)
-	if err != nil {
-		logging.Logger().Info("html to markdown conversion failed, returning raw html", "err", err)
-		return html
-	}
-	return md
-}
-
 func parseHeaderArgs(args map[string]any) (map[string]string, error) {
 	rawHeaders, ok := args["headers"]
 	if !ok {
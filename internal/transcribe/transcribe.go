@@ -0,0 +1,142 @@
+// Package transcribe turns audio files into text through an OpenAI-compatible
+// speech-to-text API, splitting long recordings with ffmpeg.
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxUploadBytes is the largest file the transcription API accepts in one
+// request.
+const MaxUploadBytes = 25 << 20
+
+// ErrNoFFmpeg means long recordings cannot be split.
+var ErrNoFFmpeg = errors.New("ffmpeg is not installed; it is needed to split long recordings")
+
+// maxResponseBytes caps how much transcript text one request may return.
+const maxResponseBytes = 10 << 20
+
+// Client calls an OpenAI-compatible /audio/transcriptions endpoint.
+type Client struct {
+	Endpoint   string
+	APIKey     string
+	Model      string
+	HTTPClient *http.Client
+}
+
+// Transcribe uploads one audio file and returns its text.
+func (c *Client) Transcribe(ctx context.Context, path string) (string, error) {
+	if strings.TrimSpace(c.Endpoint) == "" {
+		return "", errors.New("transcription.endpoint is required")
+	}
+	if c.APIKey == "" && strings.Contains(c.Endpoint, "api.openai.com") {
+		return "", errors.New("transcription.api_key is required for the OpenAI endpoint")
+	}
+	if c.HTTPClient == nil {
+		return "", errors.New("http client is required")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("model", c.Model); err != nil {
+		return "", err
+	}
+	if err := form.WriteField("response_format", "text"); err != nil {
+		return "", err
+	}
+	part, err := form.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return "", fmt.Errorf("read audio: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, &body)
+	if err != nil {
+		return "", fmt.Errorf("create transcription request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("transcription request: %w", err)
+	}
+	defer resp.Body.Close()
+	text, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return "", fmt.Errorf("read transcription response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail := strings.TrimSpace(string(text))
+		if len(detail) > 300 {
+			detail = detail[:300]
+		}
+		return "", fmt.Errorf("transcription request failed: %s %s", resp.Status, detail)
+	}
+	return strings.TrimSpace(string(text)), nil
+}
+
+// Split cuts an audio file into chunk-long mono MP3 segments in dir using
+// ffmpeg and returns their paths in order. Re-encoding at a low bitrate
+// also keeps each segment well under MaxUploadBytes.
+func Split(ctx context.Context, path, dir string, chunk time.Duration) ([]string, error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, ErrNoFFmpeg
+	}
+	seconds := int(chunk.Seconds())
+	if seconds < 1 {
+		return nil, errors.New("chunk duration must be at least one second")
+	}
+	cmd := exec.CommandContext(ctx, ffmpeg,
+		"-nostdin", "-hide_banner", "-loglevel", "error",
+		"-i", path,
+		"-vn", "-ac", "1", "-ar", "16000", "-b:a", "48k",
+		"-f", "segment", "-segment_time", strconv.Itoa(seconds), "-reset_timestamps", "1",
+		filepath.Join(dir, "chunk-%04d.mp3"),
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("split audio with ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	chunks, err := filepath.Glob(filepath.Join(dir, "chunk-*.mp3"))
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, errors.New("ffmpeg produced no audio; is this an audio file?")
+	}
+	sort.Strings(chunks)
+	return chunks, nil
+}
+
+// Timestamp renders an offset as [HH:MM:SS] for marking chunk starts.
+func Timestamp(offset time.Duration) string {
+	total := int(offset.Seconds())
+	return fmt.Sprintf("[%02d:%02d:%02d]", total/3600, total/60%60, total%60)
+}
@@ -0,0 +1,212 @@
+// Package trash moves deleted files aside instead of removing them, so agent deletes can be listed, restored, and expire after a retention period.
+package trash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+const indexFileName = "index.json"
+
+// Item is one deleted file or directory.
+type Item struct {
+	ID int `json:"id"`
+	// Path is where the item was before it was deleted.
+	Path      string    `json:"path"`
+	Dir       bool      `json:"dir"`
+	Size      int64     `json:"size"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Bin keeps deleted items under dir/<id>/ with an index.json describing them.
+type Bin struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// New creates a trash bin rooted at dir.
+func New(dir string) *Bin {
+	return &Bin{dir: dir}
+}
+
+// Move moves path into the trash. path must be on the same filesystem as
+// the bin; symlinks are moved, not followed.
+func (b *Bin) Move(path string, now time.Time) (Item, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return Item{}, fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	items, err := b.load()
+	if err != nil {
+		return Item{}, err
+	}
+
+	item := Item{ID: 1, Path: path, Dir: info.IsDir(), Size: pathSize(path, info), DeletedAt: now.UTC()}
+	for _, existing := range items {
+		if existing.ID >= item.ID {
+			item.ID = existing.ID + 1
+		}
+	}
+	itemDir := b.itemDir(item.ID)
+	if err := os.MkdirAll(itemDir, 0o755); err != nil {
+		return Item{}, fmt.Errorf("create trash directory: %w", err)
+	}
+	if err := os.Rename(path, filepath.Join(itemDir, filepath.Base(path))); err != nil {
+		_ = os.Remove(itemDir)
+		return Item{}, fmt.Errorf("move %s to trash: %w", path, err)
+	}
+	if err := b.save(append(items, item)); err != nil {
+		return Item{}, err
+	}
+	return item, nil
+}
+
+// List returns items newest first.
+func (b *Bin) List() ([]Item, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	items, err := b.load()
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+	return items, nil
+}
+
+// Restore moves an item back to its original path. It refuses to replace
+// anything that has been created there since.
+func (b *Bin) Restore(id int) (Item, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	items, err := b.load()
+	if err != nil {
+		return Item{}, err
+	}
+	index := -1
+	for i, item := range items {
+		if item.ID == id {
+			index = i
+		}
+	}
+	if index < 0 {
+		return Item{}, fmt.Errorf("trash item %d not found", id)
+	}
+	item := items[index]
+
+	if _, err := os.Lstat(item.Path); err == nil {
+		return Item{}, fmt.Errorf("cannot restore trash item %d: %s already exists", id, item.Path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return Item{}, fmt.Errorf("stat %s: %w", item.Path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(item.Path), 0o755); err != nil {
+		return Item{}, fmt.Errorf("create directory for %s: %w", item.Path, err)
+	}
+	if err := os.Rename(filepath.Join(b.itemDir(id), filepath.Base(item.Path)), item.Path); err != nil {
+		return Item{}, fmt.Errorf("restore %s: %w", item.Path, err)
+	}
+	_ = os.RemoveAll(b.itemDir(id))
+	if err := b.save(append(items[:index], items[index+1:]...)); err != nil {
+		return Item{}, err
+	}
+	return item, nil
+}
+
+// Purge permanently removes items deleted more than maxAge before now and
+// returns how many were removed. A zero maxAge keeps everything.
+func (b *Bin) Purge(maxAge time.Duration, now time.Time) (int, error) {
+	if maxAge <= 0 {
+		return 0, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	items, err := b.load()
+	if err != nil {
+		return 0, err
+	}
+	kept := items[:0]
+	removed := 0
+	for _, item := range items {
+		if now.Sub(item.DeletedAt) <= maxAge {
+			kept = append(kept, item)
+			continue
+		}
+		if err := os.RemoveAll(b.itemDir(item.ID)); err != nil {
+			kept = append(kept, item)
+			continue
+		}
+		removed++
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	if err := b.save(kept); err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+func (b *Bin) itemDir(id int) string {
+	return filepath.Join(b.dir, strconv.Itoa(id))
+}
+
+func (b *Bin) load() ([]Item, error) {
+	raw, err := store.ReadFile(filepath.Join(b.dir, indexFileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read trash index: %w", err)
+	}
+	var items []Item
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, fmt.Errorf("decode trash index: %w", err)
+	}
+	return items, nil
+}
+
+func (b *Bin) save(items []Item) error {
+	if items == nil {
+		items = []Item{}
+	}
+	raw, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode trash index: %w", err)
+	}
+	if err := store.WriteFile(filepath.Join(b.dir, indexFileName), append(raw, '\n')); err != nil {
+		return fmt.Errorf("write trash index: %w", err)
+	}
+	return nil
+}
+
+// pathSize returns the size of a file, or the total size of the regular
+// files under a directory.
+func pathSize(path string, info fs.FileInfo) int64 {
+	if !info.IsDir() {
+		return info.Size()
+	}
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
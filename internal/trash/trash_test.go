@@ -0,0 +1,99 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMoveListRestore(t *testing.T) {
+	dir := t.TempDir()
+	workspace := filepath.Join(dir, "workspace")
+	notes := writeFile(t, filepath.Join(workspace, "notes.txt"), "hello")
+	build := filepath.Join(workspace, "build")
+	writeFile(t, filepath.Join(build, "out", "app.js"), "123")
+	bin := New(filepath.Join(dir, "trash"))
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	first, err := bin.Move(notes, now)
+	if err != nil {
+		t.Fatalf("move notes: %v", err)
+	}
+	if first.ID != 1 || first.Dir || first.Size != 5 {
+		t.Fatalf("unexpected item: %#v", first)
+	}
+	second, err := bin.Move(build, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("move build: %v", err)
+	}
+	if second.ID != 2 || !second.Dir || second.Size != 3 {
+		t.Fatalf("unexpected item: %#v", second)
+	}
+	if exists(notes) || exists(build) {
+		t.Fatal("expected originals to be gone")
+	}
+
+	items, err := bin.List()
+	if err != nil || len(items) != 2 || items[0].ID != 2 {
+		t.Fatalf("unexpected list: %#v %v", items, err)
+	}
+
+	writeFile(t, notes, "new notes")
+	if _, err := bin.Restore(1); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected restore to refuse overwriting, got %v", err)
+	}
+	if _, err := bin.Restore(2); err != nil {
+		t.Fatalf("restore build: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(build, "out", "app.js")); err != nil || string(data) != "123" {
+		t.Fatalf("expected restored directory contents, got %q %v", data, err)
+	}
+	if _, err := bin.Restore(2); err == nil {
+		t.Fatal("expected restored item to leave the trash")
+	}
+}
+
+func TestPurgeRemovesExpiredItems(t *testing.T) {
+	dir := t.TempDir()
+	bin := New(filepath.Join(dir, "trash"))
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	if _, err := bin.Move(writeFile(t, filepath.Join(dir, "old.txt"), "x"), now.Add(-48*time.Hour)); err != nil {
+		t.Fatalf("move old: %v", err)
+	}
+	if _, err := bin.Move(writeFile(t, filepath.Join(dir, "new.txt"), "x"), now.Add(-time.Hour)); err != nil {
+		t.Fatalf("move new: %v", err)
+	}
+
+	if removed, err := bin.Purge(0, now); err != nil || removed != 0 {
+		t.Fatalf("expected zero retention to keep everything, got %d %v", removed, err)
+	}
+	removed, err := bin.Purge(24*time.Hour, now)
+	if err != nil || removed != 1 {
+		t.Fatalf("expected one purged item, got %d %v", removed, err)
+	}
+	items, err := bin.List()
+	if err != nil || len(items) != 1 || filepath.Base(items[0].Path) != "new.txt" {
+		t.Fatalf("unexpected list after purge: %#v %v", items, err)
+	}
+	if exists(filepath.Join(dir, "trash", "1")) {
+		t.Fatal("expected purged item directory to be removed")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
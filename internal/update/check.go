@@ -0,0 +1,68 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+)
+
+// CheckInterval is how long a release feed result is reused.
+const CheckInterval = 24 * time.Hour
+
+type checkState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// LatestCached returns the latest release version, asking feedURL only when
+// the result saved in statePath is older than CheckInterval.
+func LatestCached(ctx context.Context, client *http.Client, feedURL, statePath string, now time.Time) (string, error) {
+	var state checkState
+	raw, err := os.ReadFile(statePath)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(raw, &state); err != nil {
+			state = checkState{}
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return "", fmt.Errorf("read update state: %w", err)
+	}
+	if state.Latest != "" && now.Sub(state.CheckedAt) < CheckInterval && !now.Before(state.CheckedAt) {
+		return state.Latest, nil
+	}
+	release, err := Latest(ctx, client, feedURL)
+	if err != nil {
+		return "", err
+	}
+	if err := Record(statePath, release.Version, now); err != nil {
+		return "", err
+	}
+	return release.Version, nil
+}
+
+// Record saves version as the latest known release.
+func Record(statePath, version string, now time.Time) error {
+	raw, err := json.MarshalIndent(checkState{CheckedAt: now.UTC(), Latest: version}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := store.WriteFile(statePath, append(raw, '\n')); err != nil {
+		return fmt.Errorf("write update state: %w", err)
+	}
+	return nil
+}
+
+// Notice tells the user that latest is available, or returns "" when
+// current is up to date.
+func Notice(current, latest string) string {
+	if !Newer(current, latest) {
+		return ""
+	}
+	return fmt.Sprintf("NeoClaw %s is available (you have %s). Run claw update to install it.", latest, current)
+}
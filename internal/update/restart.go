@@ -0,0 +1,41 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// RestartSignal asks a running server to restart itself with the binary
+// now on disk; see the server's shutdown handling in the cli package.
+const RestartSignal = syscall.SIGHUP
+
+// Restart signals the server whose pid is in pidPath to restart. It reports
+// false when no server is running.
+func Restart(pidPath string) (bool, error) {
+	raw, err := os.ReadFile(pidPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read pid file: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil || pid <= 0 {
+		return false, fmt.Errorf("pid file %s is malformed", pidPath)
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false, nil
+	}
+	if err := process.Signal(RestartSignal); err != nil {
+		if errors.Is(err, os.ErrProcessDone) || errors.Is(err, syscall.ESRCH) {
+			return false, nil
+		}
+		return false, fmt.Errorf("signal server process %d: %w", pid, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,248 @@
+// Package update checks the release feed for newer NeoClaw builds and
+// replaces the installed binary with a download whose checksum matches the
+// release's checksums.txt.
+package update
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultFeedURL is the GitHub API endpoint for the latest release.
+	DefaultFeedURL = "https://api.github.com/repos/neoclaw-ai/neoclaw/releases/latest"
+
+	// ChecksumsAsset is the release asset listing SHA-256 sums; see
+	// .goreleaser.yaml.
+	ChecksumsAsset = "checksums.txt"
+
+	binaryName       = "claw"
+	maxFeedBytes     = 1 << 20
+	maxChecksumBytes = 64 << 10
+	maxArchiveBytes  = 100 << 20
+)
+
+// Release is one published version and its downloadable assets.
+type Release struct {
+	// Version has no leading "v", e.g. "1.4.0".
+	Version string
+	// Assets maps asset file names to download URLs.
+	Assets map[string]string
+}
+
+// Latest fetches the newest release from feedURL.
+func Latest(ctx context.Context, client *http.Client, feedURL string) (*Release, error) {
+	raw, err := get(ctx, client, feedURL, maxFeedBytes)
+	if err != nil {
+		return nil, err
+	}
+	var feed struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(raw, &feed); err != nil {
+		return nil, fmt.Errorf("decode release feed: %w", err)
+	}
+	version := strings.TrimPrefix(strings.TrimSpace(feed.TagName), "v")
+	if _, ok := parseVersion(version); !ok {
+		return nil, fmt.Errorf("release feed has unexpected version %q", feed.TagName)
+	}
+	release := &Release{Version: version, Assets: make(map[string]string, len(feed.Assets))}
+	for _, asset := range feed.Assets {
+		release.Assets[asset.Name] = asset.URL
+	}
+	return release, nil
+}
+
+// AssetName returns the release archive for a platform, matching the
+// archive name_template in .goreleaser.yaml. 32-bit ARM is only built for
+// ARMv6, which also runs on ARMv7.
+func AssetName(goos, goarch string) string {
+	if goarch == "arm" {
+		goarch = "armv6"
+	}
+	return fmt.Sprintf("neoclaw-%s-%s.tar.gz", goos, goarch)
+}
+
+// Newer reports whether latest is a later version than current. Versions
+// that do not parse, such as "dev", are never older or newer.
+func Newer(current, latest string) bool {
+	c, ok := parseVersion(strings.TrimPrefix(current, "v"))
+	if !ok {
+		return false
+	}
+	l, ok := parseVersion(strings.TrimPrefix(latest, "v"))
+	if !ok {
+		return false
+	}
+	for i := range c {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// IsRelease reports whether version is a release version rather than a
+// development build.
+func IsRelease(version string) bool {
+	_, ok := parseVersion(strings.TrimPrefix(version, "v"))
+	return ok
+}
+
+// Download fetches asset from release, checks it against the release's
+// checksums, and returns the claw binary inside it.
+func Download(ctx context.Context, client *http.Client, release *Release, asset string) ([]byte, error) {
+	archiveURL, ok := release.Assets[asset]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no build for this platform (%s)", release.Version, asset)
+	}
+	checksumsURL, ok := release.Assets[ChecksumsAsset]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.Version, ChecksumsAsset)
+	}
+	sums, err := get(ctx, client, checksumsURL, maxChecksumBytes)
+	if err != nil {
+		return nil, err
+	}
+	want, err := checksumFor(sums, asset)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := get(ctx, client, archiveURL, maxArchiveBytes)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(archive)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: got %s, want %s", asset, got, want)
+	}
+	return extractBinary(archive)
+}
+
+// Replace swaps the binary at path for binary. The new file is written next
+// to the old one and renamed over it, so path is never half-written.
+func Replace(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".update-*")
+	if err != nil {
+		return fmt.Errorf("%w. If claw was installed by a package manager or runs in a container, update it there instead", err)
+	}
+	tempPath := temp.Name()
+	defer os.Remove(tempPath)
+	if _, err := temp.Write(binary); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Chmod(info.Mode().Perm() | 0o111); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, path)
+}
+
+// checksumFor finds asset's SHA-256 in a sha256sum-style listing.
+func checksumFor(sums []byte, asset string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no entry for %s", ChecksumsAsset, asset)
+}
+
+func extractBinary(archive []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("open release archive: %w", err)
+	}
+	defer gz.Close()
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("release archive has no %s binary", binaryName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read release archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || filepath.Base(header.Name) != binaryName {
+			continue
+		}
+		binary, err := io.ReadAll(io.LimitReader(reader, maxArchiveBytes))
+		if err != nil {
+			return nil, fmt.Errorf("read release archive: %w", err)
+		}
+		return binary, nil
+	}
+}
+
+func get(ctx context.Context, client *http.Client, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create update request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("update request: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", url, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail := strings.TrimSpace(string(raw))
+		if len(detail) > 300 {
+			detail = detail[:300]
+		}
+		return nil, fmt.Errorf("update request failed: %s %s", resp.Status, detail)
+	}
+	if int64(len(raw)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, limit)
+	}
+	return raw, nil
+}
+
+// parseVersion parses "MAJOR.MINOR.PATCH". Pre-releases are not offered as
+// updates, so they do not parse.
+func parseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
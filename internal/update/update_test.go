@@ -0,0 +1,166 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewer(t *testing.T) {
+	cases := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"1.2.3", "1.2.4", true},
+		{"v1.2.3", "1.10.0", true},
+		{"1.2.3", "1.2.3", false},
+		{"2.0.0", "1.9.9", false},
+		{"dev", "1.0.0", false},
+		{"1.0.0", "1.1.0-rc1", false},
+	}
+	for _, tc := range cases {
+		if got := Newer(tc.current, tc.latest); got != tc.want {
+			t.Fatalf("Newer(%q, %q) = %v, want %v", tc.current, tc.latest, got, tc.want)
+		}
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	if got := AssetName("linux", "arm"); got != "neoclaw-linux-armv6.tar.gz" {
+		t.Fatalf("unexpected arm asset %q", got)
+	}
+	if got := AssetName("darwin", "arm64"); got != "neoclaw-darwin-arm64.tar.gz" {
+		t.Fatalf("unexpected darwin asset %q", got)
+	}
+}
+
+// releaseServer serves a feed for version with one archive holding binary.
+// corrupt makes checksums.txt disagree with the archive.
+func releaseServer(t *testing.T, version string, binary []byte, corrupt bool) *httptest.Server {
+	t.Helper()
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string][]byte{"README.md": []byte("readme"), "claw": binary} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("tar header: %v", err)
+		}
+		tw.Write(body)
+	}
+	tw.Close()
+	gz.Close()
+	sum := sha256.Sum256(archive.Bytes())
+	digest := hex.EncodeToString(sum[:])
+	if corrupt {
+		digest = strings.Repeat("0", len(digest))
+	}
+	asset := AssetName("linux", "amd64")
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest":
+			fmt.Fprintf(w, `{"tag_name":"v%s","assets":[{"name":%q,"browser_download_url":"%s/archive"},{"name":"checksums.txt","browser_download_url":"%s/sums"}]}`,
+				version, asset, server.URL, server.URL)
+		case "/archive":
+			w.Write(archive.Bytes())
+		case "/sums":
+			fmt.Fprintf(w, "%s  neoclaw-darwin-arm64.tar.gz\n%s  %s\n", strings.Repeat("1", 64), digest, asset)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadVerifiesChecksum(t *testing.T) {
+	server := releaseServer(t, "1.4.0", []byte("new binary"), false)
+	release, err := Latest(context.Background(), server.Client(), server.URL+"/latest")
+	if err != nil {
+		t.Fatalf("latest: %v", err)
+	}
+	if release.Version != "1.4.0" {
+		t.Fatalf("unexpected version %q", release.Version)
+	}
+	binary, err := Download(context.Background(), server.Client(), release, AssetName("linux", "amd64"))
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if string(binary) != "new binary" {
+		t.Fatalf("unexpected binary %q", binary)
+	}
+	if _, err := Download(context.Background(), server.Client(), release, AssetName("linux", "arm64")); err == nil || !strings.Contains(err.Error(), "no build") {
+		t.Fatalf("expected missing platform error, got %v", err)
+	}
+
+	bad := releaseServer(t, "1.4.0", []byte("tampered"), true)
+	release, err = Latest(context.Background(), bad.Client(), bad.URL+"/latest")
+	if err != nil {
+		t.Fatalf("latest: %v", err)
+	}
+	if _, err := Download(context.Background(), bad.Client(), release, AssetName("linux", "amd64")); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+}
+
+func TestReplaceKeepsModeAndLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "claw")
+	if err := os.WriteFile(path, []byte("old"), 0o750); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := Replace(path, []byte("new")); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil || string(raw) != "new" {
+		t.Fatalf("unexpected content %q (%v)", raw, err)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0o751 {
+		t.Fatalf("unexpected mode %v", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected only the binary, got %v", entries)
+	}
+}
+
+func TestLatestCachedReusesRecentResult(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"tag_name":"v1.5.0","assets":[]}`)
+	}))
+	defer server.Close()
+	statePath := filepath.Join(t.TempDir(), "update_check.json")
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, at := range []time.Time{now, now.Add(time.Hour), now.Add(CheckInterval + time.Minute)} {
+		latest, err := LatestCached(context.Background(), server.Client(), server.URL, statePath, at)
+		if err != nil || latest != "1.5.0" {
+			t.Fatalf("unexpected latest %q (%v)", latest, err)
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 feed requests, got %d", requests)
+	}
+	if got := Notice("1.4.2", "1.5.0"); !strings.Contains(got, "1.5.0 is available (you have 1.4.2)") {
+		t.Fatalf("unexpected notice %q", got)
+	}
+	if got := Notice("1.5.0", "1.5.0"); got != "" {
+		t.Fatalf("expected no notice, got %q", got)
+	}
+}
@@ -0,0 +1,286 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/logging"
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+// Run statuses recorded in the state file.
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusStopped   = "stopped"
+	StatusFailed    = "failed"
+)
+
+var referencePattern = regexp.MustCompile(`\{\{\s*(previous|steps\.[a-z0-9_-]+)\s*\}\}`)
+
+// Runner executes workflows from Dir and records progress under StateDir.
+type Runner struct {
+	Dir      string
+	StateDir string
+	// Prompt runs one standalone agent turn and returns the final reply.
+	Prompt func(ctx context.Context, text string) (string, error)
+	// Registry holds the tools available to tool steps.
+	Registry *tools.Registry
+	// Approver answers approval steps and tool approvals. Without one, those
+	// steps fail and the run can be resumed interactively.
+	Approver approval.Approver
+}
+
+// Result summarizes one run.
+type Result struct {
+	Workflow string
+	Status   string
+	// Step is the step the run ended on (failed or stopped), if any.
+	Step   string
+	Output string
+	Error  string
+}
+
+// state is the persisted progress of the latest run of one workflow.
+type state struct {
+	Workflow  string            `json:"workflow"`
+	Status    string            `json:"status"`
+	NextStep  int               `json:"next_step"`
+	Previous  string            `json:"previous"`
+	Outputs   map[string]string `json:"outputs"`
+	Error     string            `json:"error,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Run executes the named workflow from the first step, or from the failed
+// step of the previous run when resume is set.
+func (r *Runner) Run(ctx context.Context, name string, resume bool) (Result, error) {
+	def, err := Load(r.Dir, name)
+	if err != nil {
+		return Result{}, err
+	}
+
+	st := state{Workflow: def.Name, Outputs: map[string]string{}}
+	if resume {
+		prev, err := r.loadState(def.Name)
+		if err != nil {
+			return Result{}, err
+		}
+		if prev.Status != StatusFailed {
+			return Result{}, fmt.Errorf("workflow %q has no failed run to resume", def.Name)
+		}
+		if prev.NextStep >= len(def.Steps) {
+			return Result{}, fmt.Errorf("workflow %q changed since the failed run; start it again", def.Name)
+		}
+		st = prev
+		if st.Outputs == nil {
+			st.Outputs = map[string]string{}
+		}
+	}
+	st.Status = StatusRunning
+	st.Error = ""
+
+	logging.Logger().Info("workflow start", "workflow", def.Name, "resume", resume, "from_step", st.NextStep+1)
+	for st.NextStep < len(def.Steps) {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+		step := def.Steps[st.NextStep]
+		if err := r.saveState(st); err != nil {
+			return Result{}, err
+		}
+
+		startedAt := time.Now()
+		output, proceed, err := r.runStep(ctx, def.Name, step, st)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return Result{}, err
+			}
+			logging.Logger().Warn(
+				"workflow step failed",
+				"workflow", def.Name,
+				"step", step.Name,
+				"type", step.Type,
+				"duration_ms", time.Since(startedAt).Milliseconds(),
+				"err", err,
+			)
+			st.Status = StatusFailed
+			st.Error = err.Error()
+			if saveErr := r.saveState(st); saveErr != nil {
+				return Result{}, saveErr
+			}
+			return Result{Workflow: def.Name, Status: StatusFailed, Step: step.Name, Output: st.Previous, Error: st.Error}, nil
+		}
+		logging.Logger().Info(
+			"workflow step complete",
+			"workflow", def.Name,
+			"step", step.Name,
+			"type", step.Type,
+			"duration_ms", time.Since(startedAt).Milliseconds(),
+		)
+
+		if !proceed {
+			st.Status = StatusStopped
+			if err := r.saveState(st); err != nil {
+				return Result{}, err
+			}
+			return Result{Workflow: def.Name, Status: StatusStopped, Step: step.Name, Output: st.Previous}, nil
+		}
+		if step.Type != StepCondition {
+			st.Previous = output
+			st.Outputs[step.Name] = output
+		}
+		st.NextStep++
+	}
+
+	st.Status = StatusCompleted
+	if err := r.saveState(st); err != nil {
+		return Result{}, err
+	}
+	logging.Logger().Info("workflow complete", "workflow", def.Name)
+	return Result{Workflow: def.Name, Status: StatusCompleted, Output: st.Previous}, nil
+}
+
+// runStep executes one step. proceed is false when a condition stops the run.
+func (r *Runner) runStep(ctx context.Context, workflowName string, step Step, st state) (output string, proceed bool, err error) {
+	switch step.Type {
+	case StepPrompt:
+		if r.Prompt == nil {
+			return "", false, errors.New("prompt steps are not available here")
+		}
+		out, err := r.Prompt(ctx, expandReferences(step.Prompt, st))
+		return out, true, err
+	case StepTool:
+		if r.Registry == nil {
+			return "", false, errors.New("tool steps are not available here")
+		}
+		tool, ok := r.Registry.Lookup(step.Tool)
+		if !ok {
+			return "", false, fmt.Errorf("unknown tool %s", step.Tool)
+		}
+		args := expandArgs(step.Args, st)
+		description := step.Tool
+		if s, ok := tool.(tools.Summarizer); ok {
+			description = s.SummarizeArgs(args)
+		}
+		result, err := approval.ExecuteTool(ctx, r.Approver, tool, args, description)
+		if err != nil {
+			return "", false, err
+		}
+		if result == nil {
+			return "", true, nil
+		}
+		return result.Output, true, nil
+	case StepCondition:
+		previous := strings.ToLower(st.Previous)
+		if step.Contains != "" {
+			return "", strings.Contains(previous, strings.ToLower(step.Contains)), nil
+		}
+		return "", !strings.Contains(previous, strings.ToLower(step.NotContains)), nil
+	case StepApproval:
+		if r.Approver == nil {
+			return "", false, errors.New("approval required but no approver is available; resume the workflow with /run")
+		}
+		decision, err := r.Approver.RequestApproval(ctx, approval.ApprovalRequest{
+			Tool:        "workflow",
+			Description: fmt.Sprintf("%s: %s", workflowName, expandReferences(step.Message, st)),
+			Args:        map[string]any{"workflow": workflowName, "step": step.Name},
+		})
+		if err != nil {
+			return "", false, err
+		}
+		if decision != approval.Approved {
+			return "", false, errors.New("approval denied")
+		}
+		// Approval passes the previous output through unchanged.
+		return st.Previous, true, nil
+	default:
+		return "", false, fmt.Errorf("unsupported step type %q", step.Type)
+	}
+}
+
+// expandReferences replaces {{previous}} and {{steps.NAME}} with earlier outputs.
+func expandReferences(text string, st state) string {
+	return referencePattern.ReplaceAllStringFunc(text, func(match string) string {
+		ref := referencePattern.FindStringSubmatch(match)[1]
+		if ref == "previous" {
+			return st.Previous
+		}
+		if out, ok := st.Outputs[strings.TrimPrefix(ref, "steps.")]; ok {
+			return out
+		}
+		return match
+	})
+}
+
+func expandArgs(args map[string]any, st state) map[string]any {
+	out := make(map[string]any, len(args))
+	for key, value := range args {
+		if s, ok := value.(string); ok {
+			out[key] = expandReferences(s, st)
+			continue
+		}
+		out[key] = value
+	}
+	return out
+}
+
+func (r *Runner) statePath(name string) string {
+	return filepath.Join(r.StateDir, name+".json")
+}
+
+func (r *Runner) loadState(name string) (state, error) {
+	raw, err := store.ReadFile(r.statePath(name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return state{}, fmt.Errorf("workflow %q has no previous run", name)
+		}
+		return state{}, fmt.Errorf("read workflow state: %w", err)
+	}
+	var st state
+	if err := json.Unmarshal([]byte(raw), &st); err != nil {
+		return state{}, fmt.Errorf("decode workflow state: %w", err)
+	}
+	return st, nil
+}
+
+func (r *Runner) saveState(st state) error {
+	st.UpdatedAt = time.Now().UTC()
+	raw, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode workflow state: %w", err)
+	}
+	if err := store.WriteFile(r.statePath(st.Workflow), append(raw, '\n')); err != nil {
+		return fmt.Errorf("write workflow state: %w", err)
+	}
+	return nil
+}
+
+// FormatResult renders a run result for the user.
+func FormatResult(res Result) string {
+	var b strings.Builder
+	switch res.Status {
+	case StatusCompleted:
+		fmt.Fprintf(&b, "Workflow %s completed.", res.Workflow)
+	case StatusStopped:
+		fmt.Fprintf(&b, "Workflow %s stopped at %s: condition not met.", res.Workflow, res.Step)
+	case StatusFailed:
+		fmt.Fprintf(&b, "Workflow %s failed at %s: %s\nFix the problem and send /run %s resume to continue from that step.", res.Workflow, res.Step, res.Error, res.Workflow)
+	default:
+		fmt.Fprintf(&b, "Workflow %s: %s.", res.Workflow, res.Status)
+	}
+	if out := strings.TrimSpace(res.Output); out != "" && res.Status != StatusFailed {
+		b.WriteString("\n\n")
+		b.WriteString(out)
+	}
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neoclaw-ai/neoclaw/internal/approval"
+	"github.com/neoclaw-ai/neoclaw/internal/tools"
+)
+
+func TestRunCompletesAndChainsOutputs(t *testing.T) {
+	t.Setenv("NEOCLAW_HOME", t.TempDir())
+	r := newTestRunner(t, `
+steps:
+  - name: gather
+    type: tool
+    tool: echo
+    args:
+      text: "3 todo items"
+  - type: condition
+    contains: TODO
+  - name: summarize
+    type: prompt
+    prompt: "Summarize: {{steps.gather}}"
+`)
+	var prompts []string
+	r.Prompt = func(_ context.Context, text string) (string, error) {
+		prompts = append(prompts, text)
+		return "summary", nil
+	}
+
+	res, err := r.Run(context.Background(), "flow", false)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if res.Status != StatusCompleted || res.Output != "summary" {
+		t.Fatalf("unexpected result: %#v", res)
+	}
+	if len(prompts) != 1 || prompts[0] != "Summarize: 3 todo items" {
+		t.Fatalf("unexpected prompts: %#v", prompts)
+	}
+}
+
+func TestRunStopsWhenConditionFails(t *testing.T) {
+	t.Setenv("NEOCLAW_HOME", t.TempDir())
+	r := newTestRunner(t, `
+steps:
+  - type: tool
+    tool: echo
+    args:
+      text: all clear
+  - name: only-on-error
+    type: condition
+    contains: error
+  - type: prompt
+    prompt: should not run
+`)
+	r.Prompt = func(context.Context, string) (string, error) {
+		t.Fatalf("prompt step should not run")
+		return "", nil
+	}
+
+	res, err := r.Run(context.Background(), "flow", false)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if res.Status != StatusStopped || res.Step != "only-on-error" {
+		t.Fatalf("unexpected result: %#v", res)
+	}
+}
+
+func TestRunResumesFromFailedStep(t *testing.T) {
+	t.Setenv("NEOCLAW_HOME", t.TempDir())
+	r := newTestRunner(t, `
+steps:
+  - name: gather
+    type: tool
+    tool: echo
+    args:
+      text: data
+  - name: confirm
+    type: approval
+    message: "Continue with {{previous}}?"
+  - name: report
+    type: prompt
+    prompt: "Report on {{steps.gather}}"
+`)
+	var prompts []string
+	r.Prompt = func(_ context.Context, text string) (string, error) {
+		prompts = append(prompts, text)
+		return "done", nil
+	}
+
+	// Without an approver the approval step fails and the run is saved.
+	res, err := r.Run(context.Background(), "flow", false)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if res.Status != StatusFailed || res.Step != "confirm" {
+		t.Fatalf("expected failure at confirm, got %#v", res)
+	}
+
+	approver := &recordingApprover{}
+	r.Approver = approver
+	echo := r.Registry
+	r.Registry = tools.NewRegistry() // the gather step must not run again
+	res, err = r.Run(context.Background(), "flow", true)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if res.Status != StatusCompleted {
+		t.Fatalf("expected completed after resume, got %#v", res)
+	}
+	if len(approver.requests) != 1 || approver.requests[0].Description != "flow: Continue with data?" {
+		t.Fatalf("unexpected approval requests: %#v", approver.requests)
+	}
+	if len(prompts) != 1 || prompts[0] != "Report on data" {
+		t.Fatalf("unexpected prompts: %#v", prompts)
+	}
+
+	r.Registry = echo
+	if _, err := r.Run(context.Background(), "flow", true); err == nil {
+		t.Fatalf("expected error resuming a completed run")
+	}
+}
+
+func TestRunRecordsPromptFailure(t *testing.T) {
+	t.Setenv("NEOCLAW_HOME", t.TempDir())
+	r := newTestRunner(t, "steps:\n  - type: prompt\n    prompt: hi\n")
+	r.Prompt = func(context.Context, string) (string, error) {
+		return "", errors.New("provider down")
+	}
+	res, err := r.Run(context.Background(), "flow", false)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if res.Status != StatusFailed || res.Error != "provider down" {
+		t.Fatalf("unexpected result: %#v", res)
+	}
+	if _, err := os.Stat(filepath.Join(r.StateDir, "flow.json")); err != nil {
+		t.Fatalf("expected state file: %v", err)
+	}
+}
+
+func newTestRunner(t *testing.T, definition string) *Runner {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "flow.yaml"), []byte(definition), 0o644); err != nil {
+		t.Fatalf("write workflow: %v", err)
+	}
+	registry := tools.NewRegistry()
+	if err := registry.Register(echoTool{}); err != nil {
+		t.Fatalf("register echo: %v", err)
+	}
+	return &Runner{
+		Dir:      dir,
+		StateDir: filepath.Join(dir, "runs"),
+		Registry: registry,
+	}
+}
+
+type echoTool struct{}
+
+func (echoTool) Name() string                 { return "echo" }
+func (echoTool) Description() string          { return "echo text" }
+func (echoTool) Schema() map[string]any       { return map[string]any{"type": "object"} }
+func (echoTool) Permission() tools.Permission { return tools.AutoApprove }
+func (echoTool) Execute(_ context.Context, args map[string]any) (*tools.ToolResult, error) {
+	text, _ := args["text"].(string)
+	return &tools.ToolResult{Output: text}, nil
+}
+
+type recordingApprover struct {
+	requests []approval.ApprovalRequest
+}
+
+func (a *recordingApprover) RequestApproval(_ context.Context, req approval.ApprovalRequest) (approval.ApprovalDecision, error) {
+	a.requests = append(a.requests, req)
+	return approval.Approved, nil
+}
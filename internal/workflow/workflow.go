@@ -0,0 +1,169 @@
+// Package workflow loads YAML-defined multi-step pipelines and runs them with per-step logging and resume after failure.
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/neoclaw-ai/neoclaw/internal/store"
+	"go.yaml.in/yaml/v3"
+)
+
+const definitionExt = ".yaml"
+
+// StepType identifies what one workflow step does.
+type StepType string
+
+const (
+	// StepPrompt runs one agent turn with the step's prompt.
+	StepPrompt StepType = "prompt"
+	// StepTool calls a registered tool with fixed arguments.
+	StepTool StepType = "tool"
+	// StepCondition stops the workflow unless the previous output matches.
+	StepCondition StepType = "condition"
+	// StepApproval asks the user before continuing.
+	StepApproval StepType = "approval"
+)
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidName reports whether name can name a workflow file.
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Definition is one workflow file.
+type Definition struct {
+	Name        string `yaml:"-"`
+	Description string `yaml:"description"`
+	Steps       []Step `yaml:"steps"`
+}
+
+// Step is one ordered workflow step. Which fields apply depends on Type.
+type Step struct {
+	Name string   `yaml:"name"`
+	Type StepType `yaml:"type"`
+
+	// Prompt is the agent instruction for prompt steps.
+	Prompt string `yaml:"prompt"`
+
+	// Tool and Args describe the call for tool steps.
+	Tool string         `yaml:"tool"`
+	Args map[string]any `yaml:"args"`
+
+	// Contains and NotContains test the previous step's output for
+	// condition steps. Matching is case-insensitive.
+	Contains    string `yaml:"contains"`
+	NotContains string `yaml:"not_contains"`
+
+	// Message is shown to the user for approval steps.
+	Message string `yaml:"message"`
+}
+
+// Load reads and validates the workflow called name from dir.
+func Load(dir, name string) (Definition, error) {
+	if !namePattern.MatchString(name) {
+		return Definition{}, fmt.Errorf("invalid workflow name %q", name)
+	}
+	raw, err := store.ReadFile(filepath.Join(dir, name+definitionExt))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Definition{}, fmt.Errorf("workflow %q not found: %w", name, fs.ErrNotExist)
+		}
+		return Definition{}, fmt.Errorf("read workflow %q: %w", name, err)
+	}
+	return Parse(name, []byte(raw))
+}
+
+// Parse decodes and validates one workflow definition.
+func Parse(name string, raw []byte) (Definition, error) {
+	var def Definition
+	if err := yaml.Unmarshal(raw, &def); err != nil {
+		return Definition{}, fmt.Errorf("parse workflow %q: %w", name, err)
+	}
+	def.Name = name
+	if err := def.Validate(); err != nil {
+		return Definition{}, fmt.Errorf("workflow %q: %w", name, err)
+	}
+	return def, nil
+}
+
+// List returns every valid workflow in dir sorted by name. Invalid files are
+// reported through the returned error after all valid ones are collected.
+func List(dir string) ([]Definition, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read workflows directory: %w", err)
+	}
+	var (
+		defs []Definition
+		errs []error
+	)
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), definitionExt)
+		if entry.IsDir() || !ok || !namePattern.MatchString(name) {
+			continue
+		}
+		def, err := Load(dir, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs, errors.Join(errs...)
+}
+
+// Validate checks step types and required fields, and fills in default step names.
+func (d *Definition) Validate() error {
+	if len(d.Steps) == 0 {
+		return errors.New("at least one step is required")
+	}
+	seen := map[string]bool{}
+	for i := range d.Steps {
+		step := &d.Steps[i]
+		step.Name = strings.TrimSpace(step.Name)
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step-%d", i+1)
+		}
+		if seen[step.Name] {
+			return fmt.Errorf("duplicate step name %q", step.Name)
+		}
+		seen[step.Name] = true
+
+		switch step.Type {
+		case StepPrompt:
+			if strings.TrimSpace(step.Prompt) == "" {
+				return fmt.Errorf("step %q: prompt is required", step.Name)
+			}
+		case StepTool:
+			if strings.TrimSpace(step.Tool) == "" {
+				return fmt.Errorf("step %q: tool is required", step.Name)
+			}
+		case StepCondition:
+			if (step.Contains == "") == (step.NotContains == "") {
+				return fmt.Errorf("step %q: exactly one of contains or not_contains is required", step.Name)
+			}
+			if i == 0 {
+				return fmt.Errorf("step %q: condition cannot be the first step", step.Name)
+			}
+		case StepApproval:
+			if strings.TrimSpace(step.Message) == "" {
+				return fmt.Errorf("step %q: message is required", step.Name)
+			}
+		default:
+			return fmt.Errorf("step %q: unsupported type %q (expected prompt, tool, condition, or approval)", step.Name, step.Type)
+		}
+	}
+	return nil
+}
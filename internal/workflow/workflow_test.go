@@ -0,0 +1,65 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFillsStepNames(t *testing.T) {
+	def, err := Parse("weekly-review", []byte(`
+description: Summarize the week
+steps:
+  - type: prompt
+    prompt: Summarize this week's daily logs.
+  - name: check
+    type: condition
+    contains: todo
+  - type: approval
+    message: Send the summary?
+`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if def.Name != "weekly-review" || def.Description != "Summarize the week" {
+		t.Fatalf("unexpected definition: %#v", def)
+	}
+	names := []string{def.Steps[0].Name, def.Steps[1].Name, def.Steps[2].Name}
+	if strings.Join(names, ",") != "step-1,check,step-3" {
+		t.Fatalf("unexpected step names: %v", names)
+	}
+}
+
+func TestParseRejectsInvalidSteps(t *testing.T) {
+	cases := map[string]string{
+		"no steps":        `description: empty`,
+		"unknown type":    "steps:\n  - type: email\n",
+		"missing prompt":  "steps:\n  - type: prompt\n",
+		"first condition": "steps:\n  - type: condition\n    contains: x\n",
+		"both conditions": "steps:\n  - type: prompt\n    prompt: hi\n  - type: condition\n    contains: a\n    not_contains: b\n",
+		"duplicate names": "steps:\n  - name: a\n    type: prompt\n    prompt: hi\n  - name: a\n    type: prompt\n    prompt: hi\n",
+	}
+	for name, raw := range cases {
+		if _, err := Parse("bad", []byte(raw)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestListSkipsInvalidWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "good.yaml"), []byte("steps:\n  - type: prompt\n    prompt: hi\n"), 0o644); err != nil {
+		t.Fatalf("write good: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte("steps: []\n"), 0o644); err != nil {
+		t.Fatalf("write bad: %v", err)
+	}
+	defs, err := List(dir)
+	if err == nil {
+		t.Fatalf("expected error for invalid workflow")
+	}
+	if len(defs) != 1 || defs[0].Name != "good" {
+		t.Fatalf("unexpected workflows: %#v", defs)
+	}
+}
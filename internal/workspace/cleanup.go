@@ -0,0 +1,168 @@
+// Package workspace maintains the agent workspace, pruning scratch files under tmp/ by age and total size.
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+)
+
+// Retention limits what CleanTmp keeps. Zero values disable a limit.
+type Retention struct {
+	MaxAge   time.Duration
+	MaxBytes int64
+}
+
+// Report summarizes one cleanup pass.
+type Report struct {
+	Removed    int
+	FreedBytes int64
+	// Protected counts files that would have been removed but are still referenced.
+	Protected int
+}
+
+// String renders the report as scheduler job output.
+func (r Report) String() string {
+	return fmt.Sprintf("removed %d file(s), freed %d bytes, kept %d referenced file(s)", r.Removed, r.FreedBytes, r.Protected)
+}
+
+type tmpFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// CleanTmp removes files under dir older than MaxAge, then removes the oldest
+// remaining files until the directory fits in MaxBytes. Files for which
+// protected returns true are never removed. Empty subdirectories are pruned.
+// A missing dir is not an error.
+func CleanTmp(dir string, retention Retention, protected func(path string) bool, now time.Time) (Report, error) {
+	var files []tmpFile
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path != dir {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, tmpFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return Report{}, fmt.Errorf("scan %s: %w", dir, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+
+	var report Report
+	skipped := map[string]bool{}
+	remove := func(f tmpFile) (bool, error) {
+		if protected != nil && protected(f.path) {
+			if !skipped[f.path] {
+				skipped[f.path] = true
+				report.Protected++
+			}
+			return false, nil
+		}
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, fmt.Errorf("remove %s: %w", f.path, err)
+		}
+		report.Removed++
+		report.FreedBytes += f.size
+		total -= f.size
+		return true, nil
+	}
+
+	var kept []tmpFile
+	for _, f := range files {
+		if retention.MaxAge > 0 && now.Sub(f.modTime) > retention.MaxAge {
+			removed, err := remove(f)
+			if err != nil {
+				return report, err
+			}
+			if removed {
+				continue
+			}
+		}
+		kept = append(kept, f)
+	}
+	if retention.MaxBytes > 0 {
+		for _, f := range kept {
+			if total <= retention.MaxBytes {
+				break
+			}
+			if _, err := remove(f); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	// Deepest directories first so parents empty out after their children.
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d)
+		if err == nil && len(entries) == 0 {
+			_ = os.Remove(d)
+		}
+	}
+	return report, nil
+}
+
+// SessionReferences returns a predicate reporting whether a file's name appears
+// in the last window messages of any session under sessionsDir, including tool
+// call arguments. Matching on the base name is deliberately loose: keeping an
+// extra file is cheaper than deleting one the conversation still points at.
+func SessionReferences(ctx context.Context, sessionsDir string, window int) (func(path string) bool, error) {
+	infos, err := session.List(ctx, sessionsDir)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	for _, info := range infos {
+		messages, err := session.New(info.Path).Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if window > 0 && len(messages) > window {
+			messages = messages[len(messages)-window:]
+		}
+		for _, msg := range messages {
+			b.WriteString(msg.Content)
+			b.WriteByte('\n')
+			for _, call := range msg.ToolCalls {
+				b.WriteString(call.Arguments)
+				b.WriteByte('\n')
+			}
+		}
+	}
+	text := b.String()
+	return func(path string) bool {
+		return text != "" && strings.Contains(text, filepath.Base(path))
+	}, nil
+}
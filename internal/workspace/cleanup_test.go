@@ -0,0 +1,131 @@
+package workspace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoclaw-ai/neoclaw/internal/provider"
+	"github.com/neoclaw-ai/neoclaw/internal/session"
+)
+
+func writeTmpFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestCleanTmpRemovesExpiredFilesAndEmptyDirs(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tmp")
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := filepath.Join(dir, "job", "old.txt")
+	fresh := filepath.Join(dir, "fresh.txt")
+	writeTmpFile(t, old, 10, now.Add(-48*time.Hour))
+	writeTmpFile(t, fresh, 10, now.Add(-time.Hour))
+
+	report, err := CleanTmp(dir, Retention{MaxAge: 24 * time.Hour}, nil, now)
+	if err != nil {
+		t.Fatalf("clean: %v", err)
+	}
+	if report.Removed != 1 || report.FreedBytes != 10 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if exists(old) || exists(filepath.Dir(old)) {
+		t.Fatalf("expected expired file and its empty directory to be removed")
+	}
+	if !exists(fresh) {
+		t.Fatalf("expected fresh file to be kept")
+	}
+}
+
+func TestCleanTmpEnforcesSizeOldestFirst(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	oldest := filepath.Join(dir, "a.txt")
+	middle := filepath.Join(dir, "b.txt")
+	newest := filepath.Join(dir, "c.txt")
+	writeTmpFile(t, oldest, 100, now.Add(-3*time.Hour))
+	writeTmpFile(t, middle, 100, now.Add(-2*time.Hour))
+	writeTmpFile(t, newest, 100, now.Add(-time.Hour))
+
+	report, err := CleanTmp(dir, Retention{MaxBytes: 150}, nil, now)
+	if err != nil {
+		t.Fatalf("clean: %v", err)
+	}
+	if report.Removed != 2 {
+		t.Fatalf("expected 2 removals, got %+v", report)
+	}
+	if exists(oldest) || exists(middle) || !exists(newest) {
+		t.Fatalf("expected only the newest file to remain")
+	}
+}
+
+func TestCleanTmpKeepsProtectedFiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	referenced := filepath.Join(dir, "output-123.txt")
+	other := filepath.Join(dir, "output-456.txt")
+	writeTmpFile(t, referenced, 100, now.Add(-48*time.Hour))
+	writeTmpFile(t, other, 100, now.Add(-48*time.Hour))
+
+	protected := func(path string) bool { return path == referenced }
+	report, err := CleanTmp(dir, Retention{MaxAge: time.Hour, MaxBytes: 1}, protected, now)
+	if err != nil {
+		t.Fatalf("clean: %v", err)
+	}
+	if report.Removed != 1 || report.Protected != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if !exists(referenced) || exists(other) {
+		t.Fatalf("expected referenced file to survive and other to be removed")
+	}
+}
+
+func TestCleanTmpMissingDir(t *testing.T) {
+	report, err := CleanTmp(filepath.Join(t.TempDir(), "missing"), Retention{MaxAge: time.Hour}, nil, time.Now())
+	if err != nil {
+		t.Fatalf("expected missing dir to be ignored, got %v", err)
+	}
+	if report.Removed != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestSessionReferencesUsesRecentWindow(t *testing.T) {
+	ctx := context.Background()
+	sessionsDir := t.TempDir()
+	store := session.New(filepath.Join(sessionsDir, "cli", "default.jsonl"))
+	if err := store.Append(ctx, []provider.ChatMessage{
+		{Role: provider.RoleUser, Content: "see tmp/early.log"},
+		{Role: provider.RoleAssistant, ToolCalls: []provider.ToolCall{{ID: "1", Name: "read_file", Arguments: `{"path":"tmp/report.csv"}`}}},
+		{Role: provider.RoleTool, ToolCallID: "1", Content: "ok"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	protected, err := SessionReferences(ctx, sessionsDir, 2)
+	if err != nil {
+		t.Fatalf("references: %v", err)
+	}
+	if !protected("/ws/tmp/report.csv") {
+		t.Fatalf("expected file in tool call arguments to be protected")
+	}
+	if protected("/ws/tmp/early.log") {
+		t.Fatalf("expected file outside the recent window to be unprotected")
+	}
+}